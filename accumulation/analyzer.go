@@ -18,10 +18,15 @@
 package accumulation
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
+	"go/ast"
+	"go/token"
 	"reflect"
 	"runtime/debug"
+	"slices"
+	"strings"
 
 	"go.uber.org/nilaway/annotation"
 	"go.uber.org/nilaway/assertion"
@@ -29,6 +34,7 @@ import (
 	"go.uber.org/nilaway/config"
 	"go.uber.org/nilaway/diagnostic"
 	"go.uber.org/nilaway/inference"
+	"go.uber.org/nilaway/suppression"
 	"go.uber.org/nilaway/util/analysishelper"
 	"golang.org/x/tools/go/analysis"
 )
@@ -41,12 +47,32 @@ const _doc = "Read the assertions and annotations from this package as Results f
 // Analyzer here is the accumulator that combines assertions and annotations to generate a list of
 // triggered assertions that will become errors in the next Analyzer
 var Analyzer = &analysis.Analyzer{
-	Name:       "nilaway_accumulation_analyzer",
-	Doc:        _doc,
-	Run:        run,
-	FactTypes:  []analysis.Fact{new(inference.InferredMap)},
-	Requires:   []*analysis.Analyzer{config.Analyzer, assertion.Analyzer, annotation.Analyzer},
-	ResultType: reflect.TypeOf(([]analysis.Diagnostic)(nil)),
+	Name:             "nilaway_accumulation_analyzer",
+	Doc:              _doc,
+	Run:              run,
+	FactTypes:        []analysis.Fact{new(inference.InferredMap), new(diagnostic.ReportedConflicts)},
+	Requires:         []*analysis.Analyzer{config.Analyzer, assertion.Analyzer, annotation.Analyzer},
+	ResultType:       reflect.TypeOf((*Result)(nil)),
+	RunDespiteErrors: true,
+}
+
+// Result is the result of the accumulation analyzer.
+type Result struct {
+	// Diagnostics is the list of potential errors for upper-level analyzers to report.
+	Diagnostics []analysis.Diagnostic
+	// InferredMap is the final inferred map of this package, shared with the complementary
+	// analyzers (e.g., redundantcheck). It is nil if the package is not analyzed.
+	InferredMap *inference.InferredMap
+	// Suppressed is the number of the errors of this package suppressed by each mechanism (e.g.,
+	// in the excluded files), for the drivers to report.
+	Suppressed suppression.Counts
+	// SkippedForTypeErrors is the number of the functions of this package skipped due to its type
+	// errors (i.e., referencing broken objects), for the drivers to report that the package is
+	// only partially analyzed.
+	SkippedForTypeErrors int
+	// AbortedForTypeErrors indicates whether the analysis of this package is aborted due to its
+	// type errors, for the drivers to report.
+	AbortedForTypeErrors bool
 }
 
 // run is the primary driver function for NilAway's analysis.
@@ -74,6 +100,10 @@ var Analyzer = &analysis.Analyzer{
 //
 // Lastly, we export the _incremental_ information we have gathered from the analysis of local
 // package for use by downstream packages.
+//
+// The mode is NoInfer for every package if the single-package mode is requested by the user
+// (config.InferenceModeSinglePackage), where no facts are observed from the upstream packages or
+// exported for the downstream ones either, such that each package is analyzed on its own.
 func run(pass *analysis.Pass) (result interface{}, _ error) {
 	// As a last resort, we recover from a panic when running the analyzer, convert the panic to
 	// a diagnostic and return.
@@ -81,20 +111,25 @@ func run(pass *analysis.Pass) (result interface{}, _ error) {
 		if r := recover(); r != nil {
 			// Deferred functions are executed after a result is generated, so here we modify the
 			// return value `result` in-place.
+			res, ok := result.(*Result)
+			if !ok || res == nil {
+				res = &Result{}
+				result = res
+			}
+			if len(pass.TypeErrors) > 0 {
+				// Ill-typed input is expected to break the analysis, which is not an internal error.
+				res.AbortedForTypeErrors = true
+				return
+			}
 			// Diagnostics with invalid positions (<= 0) will be silently suppressed, so here we use 1.
 			d := analysis.Diagnostic{Pos: 1, Message: fmt.Sprintf("INTERNAL PANIC: %s\n%s", r, string(debug.Stack()))}
-			if diagnostics, ok := result.([]analysis.Diagnostic); ok {
-				result = append(diagnostics, d)
-			} else {
-				result = []analysis.Diagnostic{d}
-			}
+			res.Diagnostics = append(res.Diagnostics, d)
 		}
 	}()
 
 	conf := pass.ResultOf[config.Analyzer].(*config.Config)
 	if !conf.IsPkgInScope(pass.Pkg) {
-		// Must return a typed nil since the driver is using reflection to retrieve the result.
-		return ([]analysis.Diagnostic)(nil), nil
+		return &Result{}, nil
 	}
 
 	assertionsResult := pass.ResultOf[assertion.Analyzer].(*analysishelper.Result[[]annotation.FullTrigger])
@@ -104,40 +139,102 @@ func run(pass *analysis.Pass) (result interface{}, _ error) {
 		// errors. However, in the future we could implement error recovery and make use of the partial
 		// information to continue the analysis.
 		// Diagnostics with invalid positions (<= 0) will be silently suppressed, so here we use 1.
-		return []analysis.Diagnostic{{Pos: 1, Message: fmt.Sprintf("INTERNAL ERROR(s):\n%s", err)}}, nil
+		if len(pass.TypeErrors) > 0 {
+			// Ill-typed input is expected to break the analysis, which is not an internal error.
+			return &Result{AbortedForTypeErrors: true}, nil
+		}
+		d := analysis.Diagnostic{Pos: 1, Message: fmt.Sprintf("INTERNAL ERROR(s):\n%s", err)}
+		return &Result{Diagnostics: []analysis.Diagnostic{d}}, nil
 	}
 
+	singlePackage := conf.InferenceMode == config.InferenceModeSinglePackage
+
 	diagnosticEngine := diagnostic.NewEngine(pass)
+	if conf.FixMode == config.FixModeGuard {
+		diagnosticEngine.EnableGuardFixes(conf.FixPolicy, assertionsResult.Res)
+	}
+	if conf.DebugDeps {
+		diagnosticEngine.EnableDepsDebugging()
+	}
+	diagnosticEngine.SetReportAt(conf.ReportAt)
+	diagnosticEngine.SetReportPositionPolicy(conf.ReportPositionPolicy)
+	diagnosticEngine.SetRecoveredPanics(conf.RecoveredPanics)
+	diagnosticEngine.SetCheckStructInit(conf.CheckStructInit)
+	diagnosticEngine.SetRequireSourceInScope(conf.RequireSourceInScope, conf.IsPkgPathInScope)
+	// There are no errors determined by the upstream facts to deduplicate in single-package mode.
+	if conf.CrossPackageDedup && !singlePackage {
+		diagnosticEngine.EnableCrossPackageDedup()
+	}
+	if err := diagnosticEngine.SetPathFormat(conf.PathFormat); err != nil {
+		return nil, err
+	}
+	if conf.ExplainProvenance {
+		diagnosticEngine.EnableProvenance()
+	}
+	if conf.DisableLineDirectives {
+		diagnosticEngine.DisableLineDirectives()
+	}
+	if conf.MessageTemplate != "" {
+		tmpl, err := diagnostic.ParseMessageTemplate(conf.MessageTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("parse message template: %w", err)
+		}
+		diagnosticEngine.SetMessageTemplate(tmpl)
+	}
 
 	// Create an inference engine and observe (load) information from upstream dependencies (i.e.,
 	// mappings between annotation sites and their inferred values).
 	inferenceEngine := inference.NewEngine(pass, diagnosticEngine)
-	inferenceEngine.ObserveUpstream()
+	if !singlePackage {
+		inferenceEngine.ObserveUpstream()
+	}
 
-	// Determine inference type based on comments in package doc string.
+	// Determine inference type based on comments in package doc string. The sites of a stubbed
+	// package are fully determined by the annotations of its stub package, hence no inference.
 	mode := inference.DetermineMode(pass)
+	if conf.IsPkgStubbed(pass.Pkg) || singlePackage {
+		mode = inference.NoInfer
+	}
 
 	// First observe all annotations from annotationsResult (observes only syntactic annotations
 	// for FullInfer mode, otherwise all annotations for NoInfer)
 	inferenceEngine.ObserveAnnotations(annotationsResult.Res, mode)
 
+	// Then seed the inference with the externally produced nilness facts, if any. The sites already
+	// determined by the annotations above are not overridden.
+	if conf.ImportFactsDir != "" && mode == inference.FullInfer {
+		if err := inferenceEngine.ObserveImportedFacts(annotationsResult.Res, conf.ImportFactsDir); err != nil {
+			return nil, fmt.Errorf("import nilness facts: %w", err)
+		}
+	}
+
 	var (
 		inferredMap *inference.InferredMap
 		diagnostics []analysis.Diagnostic
 	)
 	switch mode {
 	case inference.FullInfer:
+		// Assume the parameters of the exported functions and the unconstrained sites to be
+		// nilable first, if requested.
+		if conf.StrictExports {
+			inferenceEngine.ObserveStrictExports(annotationsResult.Res)
+		}
+		inferenceEngine.ObserveDefaultNilability(annotationsResult.Res, assertionsResult.Res, conf.DefaultNilability)
 		// Incorporate assertions from this package one-by-one into the inferredAnnotationMap, possibly
 		// determining local and upstream sites in the process. This is guaranteed not to determine any
 		// sites unless we really have a reason they have to be determined.
 		inferenceEngine.ObservePackage(assertionsResult.Res)
 		inferredMap = inferenceEngine.InferredMap()
 		diagnostics = diagnosticEngine.Diagnostics(conf.GroupErrorMessages)
+		if conf.FixMode == config.FixModeAnnotate {
+			// Report the inferred annotations instead of the errors in annotate mode.
+			diagnostics = diagnosticEngine.AnnotationDiagnostics(inferredMap)
+		}
 
 	case inference.NoInfer:
 		// In non-inference case - use the classical assertionNode.CheckErrors method to determine error outputs
 		inferredMap = inferenceEngine.InferredMap()
-		checkErrors(assertionsResult.Res, inferredMap, diagnosticEngine)
+		checkErrors(assertionsResult.Res, inferredMap, annotationsResult.Res, diagnosticEngine)
 		// Retrieve the diagnostics from the engine. Note that we should not group the
 		// diagnostics for easier unit testing.
 		diagnostics = diagnosticEngine.Diagnostics(false /* grouping */)
@@ -146,6 +243,53 @@ func run(pass *analysis.Pass) (result interface{}, _ error) {
 		panic("Invalid mode for running NilAway")
 	}
 
+	// Also report the problems found in the annotation comments (e.g., syntax errors).
+	diagnostics = append(diagnostics, annotationsResult.Res.Diagnostics()...)
+
+	// Account for the errors suppressed so far (and below) for the suppression report of the drivers.
+	suppressed := make(suppression.Counts)
+	suppressed.Add(diagnosticEngine.Suppressed())
+
+	// Drop the diagnostics in the files that should not be reported (e.g., generated files). Note
+	// that the files are still analyzed above, so the nilability flowing through them is known.
+	diagnostics = reportedDiagnostics(pass, conf, diagnostics, suppressed)
+
+	// The functions referencing broken objects in packages with type errors are skipped by the
+	// function analyzer, hence we count them for the drivers to tell the users that the package
+	// is only partially analyzed (e.g., in the summary).
+	skippedForTypeErrors := 0
+	if len(pass.TypeErrors) > 0 {
+		for _, file := range pass.Files {
+			if !conf.IsFileInScope(file) {
+				continue
+			}
+			for _, decl := range file.Decls {
+				if f, ok := decl.(*ast.FuncDecl); ok && f.Body != nil && analysishelper.ReferencesBrokenObjects(pass.TypesInfo, f) {
+					skippedForTypeErrors++
+				}
+			}
+		}
+	}
+
+	// The upstream facts produced by an incompatible version of NilAway are ignored, hence we
+	// notify the users once per package instead of failing in the middle of decoding them.
+	if pkgs := inferenceEngine.IncompatibleUpstream(); len(pkgs) > 0 {
+		diagnostics = append(diagnostics, incompatibleFactsNotice(pass, pkgs))
+	}
+
+	// The packages analyzed under memory pressure are analyzed less aggressively, hence we notify
+	// the users that some errors may be missed (or summarized).
+	if conf.Degraded && !conf.IsPkgStubbed(pass.Pkg) {
+		diagnostics = append(diagnostics, analysis.Diagnostic{Pos: packageClausePos(pass), Message: fmt.Sprintf(
+			"package analyzed in degraded mode due to memory pressure (above %g of the memory limit): "+
+				"assertion trees limited to %d tracked expressions and struct initialization checks skipped",
+			conf.MemoryPressureThreshold, conf.EffectiveMaxTreeWidth())})
+	}
+
+	// Finally, sort the diagnostics by their positions (and codes) such that the output order is
+	// deterministic across runs and drivers, which keeps the diffs small for baseline tooling.
+	sortDiagnostics(pass, diagnostics)
+
 	// Export the _incremental_ information from this inferred map for analysis of downstream
 	// packages via the Fact mechanism (which [uses gob encoding under the hood]). The custom
 	// GobEncode / GobDecode methods of InferredAnnotationMap ensure that only incremental
@@ -155,9 +299,46 @@ func run(pass *analysis.Pass) (result interface{}, _ error) {
 	//
 	// [uses gob encoding under the hood]: https://pkg.go.dev/golang.org/x/tools/go/analysis#hdr-Modular_analysis_with_Facts
 	// [gob encoding]: https://pkg.go.dev/encoding/gob#hdr-Basics
-	inferredMap.Export(pass)
+	if !singlePackage {
+		inferredMap.Export(pass)
+		// Also export the conflicts finally reported by this package, such that the downstream
+		// packages observing the same conflicts (via the facts) do not report them again.
+		diagnosticEngine.ExportReportedConflicts(diagnostics)
+	}
+
+	// Write the final nilabilities of the sites of this package in a machine-readable form for
+	// external tools if requested.
+	if conf.ExportFactsDir != "" {
+		if err := inferredMap.WriteFactsFile(conf.ExportFactsDir, pass.Pkg.Path()); err != nil {
+			return nil, fmt.Errorf("export nilness facts: %w", err)
+		}
+	}
+
+	return &Result{Diagnostics: diagnostics, InferredMap: inferredMap, Suppressed: suppressed, SkippedForTypeErrors: skippedForTypeErrors}, nil
+}
+
+// packageClausePos returns the position of the package clause of the first file of the package,
+// where the notices about the whole package are reported.
+func packageClausePos(pass *analysis.Pass) token.Pos {
+	// Diagnostics with invalid positions (<= 0) will be silently suppressed, so we fall back to 1.
+	if len(pass.Files) > 0 && pass.Files[0].Package.IsValid() {
+		return pass.Files[0].Package
+	}
+	return 1
+}
 
-	return diagnostics, nil
+// incompatibleFactsNotice returns the notice for the upstream packages whose facts were produced
+// by an incompatible version of NilAway, reported at the package clause (see packageClausePos).
+func incompatibleFactsNotice(pass *analysis.Pass, pkgs []string) analysis.Diagnostic {
+	const maxListed = 3
+	listed := strings.Join(pkgs[:min(len(pkgs), maxListed)], ", ")
+	if len(pkgs) > maxListed {
+		listed += fmt.Sprintf(" and %d more", len(pkgs)-maxListed)
+	}
+	return analysis.Diagnostic{Pos: packageClausePos(pass), Message: fmt.Sprintf(
+		"facts of upstream package(s) %s were produced by an incompatible version of NilAway "+
+			"(expected encoding %s) and are ignored, rebuild them with the same version",
+		listed, inference.GobFingerprint())}
 }
 
 type conflictHandler interface {
@@ -165,8 +346,16 @@ type conflictHandler interface {
 }
 
 // checkErrors iterates over a set of full triggers, checking each one against a given annotation
-// map to see if it fails and if so appending it to the returned list.
-func checkErrors(triggers []annotation.FullTrigger, annMap annotation.Map, diagnosticEngine conflictHandler) {
+// map to see if it fails and if so appending it to the returned list. The triggers for the fields
+// omitted from composite literals (annotation.FldOmitted) are only checked if the fields are
+// explicitly annotated in the package annotations, since the unannotated fields are nonnil by
+// default in the no-infer mode.
+func checkErrors(
+	triggers []annotation.FullTrigger,
+	annMap annotation.Map,
+	pkgAnnotations *annotation.ObservedMap,
+	diagnosticEngine conflictHandler,
+) {
 	// Filter triggers for error return handling -- inter-procedural and annotations-based (no inference).
 	// (Note that since we are using FilterTriggersForErrorReturn as a preprocessing step here, we can directly use its
 	// first output `filteredTriggers` to check and report errors. The second output of raw `deleted triggers` is not
@@ -195,6 +384,10 @@ func checkErrors(triggers []annotation.FullTrigger, annMap annotation.Map, diagn
 	for _, trigger := range finalTriggers {
 		// Skip checking any full triggers we created by duplicating from contracted functions
 		// to the caller function.
+		if c, ok := trigger.Consumer.Annotation.(*annotation.FldOmitted); ok &&
+			!pkgAnnotations.IsFieldAnnotated(c.Ann.(*annotation.FieldAnnotationKey).FieldDecl) {
+			continue
+		}
 		if !trigger.CreatedFromDuplication && trigger.Check(annMap) {
 			diagnosticEngine.AddSingleAssertionConflict(trigger)
 		}
@@ -206,3 +399,47 @@ func checkErrors(triggers []annotation.FullTrigger, annMap annotation.Map, diagn
 func init() {
 	inference.GobRegister()
 }
+
+// reportedDiagnostics returns the diagnostics that are not in the files excluded from reporting
+// (see config.Config.IsFileReported and config.Config.ErrorFilter). The diagnostics are filtered
+// in place, and the numbers of the ones suppressed are added to suppressed by their mechanisms.
+func reportedDiagnostics(pass *analysis.Pass, conf *config.Config, diagnostics []analysis.Diagnostic, suppressed suppression.Counts) []analysis.Diagnostic {
+	excluded := make(map[*token.File]suppression.Mechanism)
+	for _, file := range pass.Files {
+		if m := conf.FileSuppressedBy(file); m != "" {
+			excluded[pass.Fset.File(file.Pos())] = m
+		}
+	}
+	if len(excluded) > 0 {
+		diagnostics = slices.DeleteFunc(diagnostics, func(d analysis.Diagnostic) bool {
+			m, ok := excluded[pass.Fset.File(d.Pos)]
+			if ok {
+				suppressed[m]++
+			}
+			return ok
+		})
+	}
+	return conf.ErrorFilter.Apply(pass.Fset, diagnostics, suppressed)
+}
+
+// sortDiagnostics sorts the diagnostics in place by file name, line, column, category (i.e., the
+// diagnostic code) and message. The sort is stable, so diagnostics that compare equal keep their
+// relative order.
+func sortDiagnostics(pass *analysis.Pass, diagnostics []analysis.Diagnostic) {
+	slices.SortStableFunc(diagnostics, func(a, b analysis.Diagnostic) int {
+		posA, posB := pass.Fset.Position(a.Pos), pass.Fset.Position(b.Pos)
+		if n := cmp.Compare(posA.Filename, posB.Filename); n != 0 {
+			return n
+		}
+		if n := cmp.Compare(posA.Line, posB.Line); n != 0 {
+			return n
+		}
+		if n := cmp.Compare(posA.Column, posB.Column); n != 0 {
+			return n
+		}
+		if n := cmp.Compare(a.Category, b.Category); n != 0 {
+			return n
+		}
+		return cmp.Compare(a.Message, b.Message)
+	})
+}
@@ -50,6 +50,27 @@ func (a AffiliationPair) implementingMethodAsExpr() ast.Expr {
 	}
 }
 
+// implementingReturnAsExpr returns an expression positioned at the result at position `retNum` of
+// the given return statement of the implementing method (or at the return statement itself if it
+// is a naked return), such that a violation of the covariance of results is reported at the return
+// that weakens the nilability of the interface method, rather than at the interface. If no return
+// statement is given (e.g., the implementing method is declared in another package), the
+// violation is reported at the implementing method.
+func (a AffiliationPair) implementingReturnAsExpr(ret *ast.ReturnStmt, retNum int) ast.Expr {
+	if ret == nil {
+		return a.implementingMethodAsExpr()
+	}
+	pos := ret.Pos()
+	if retNum < len(ret.Results) {
+		pos = ret.Results[retNum].Pos()
+	}
+	return &ast.Ident{
+		NamePos: pos,
+		Name:    a.ImplementingMethod.Name(),
+		Obj:     nil,
+	}
+}
+
 // FullTriggerForInterfaceParamFlow takes the knowledge that `affiliation` represents an affiliation
 // discovered in the analyzed code - for example, an assignment of a variable of interface type `I`
 // to a value of pointer type `*S` - and returns a FullTrigger representing the assertion that
@@ -83,9 +104,11 @@ func FullTriggerForInterfaceParamFlow(affiliation AffiliationPair, paramNum int)
 // discovered in the analyzed code - for example, an assignment of a variable of interface type `I`
 // to a value of pointer type `*S` - and returns a FullTrigger representing the assertion that
 // the implementing method can have a nilable result at position `retNum` only if the interface method
-// has such a nilable result. This encodes "covariance" of annotations for results.
+// has such a nilable result. This encodes "covariance" of annotations for results. The violations
+// are reported at the return statement `ret` of the implementing method, if given.
 // Precondition: retNum < numResults(affiliation.InterfaceMethod)
-func FullTriggerForInterfaceResultFlow(affiliation AffiliationPair, retNum int) FullTrigger {
+// nilable(ret)
+func FullTriggerForInterfaceResultFlow(affiliation AffiliationPair, retNum int, ret *ast.ReturnStmt) FullTrigger {
 	return FullTrigger{
 		Producer: &ProduceTrigger{
 			Annotation: &MethodResultReachesInterface{
@@ -101,7 +124,7 @@ func FullTriggerForInterfaceResultFlow(affiliation AffiliationPair, retNum int)
 					Ann: RetKeyFromRetNum(affiliation.InterfaceMethod, retNum)},
 				AffiliationPair: affiliation,
 			},
-			Expr:         affiliation.interfaceMethodAsExpr(),
+			Expr:         affiliation.implementingReturnAsExpr(ret, retNum),
 			Guards:       util.NoGuards(),
 			GuardMatched: false,
 		},
@@ -29,11 +29,12 @@ const _doc = "Read the annotations for each struct, interface, and function in t
 // be matched against assertions. It returns the map generated from reading the annotations in the
 // source code
 var Analyzer = &analysis.Analyzer{
-	Name:       "nilaway_annotation_analyzer",
-	Doc:        _doc,
-	Run:        analysishelper.WrapRun(run),
-	ResultType: reflect.TypeOf((*analysishelper.Result[*ObservedMap])(nil)),
-	Requires:   []*analysis.Analyzer{config.Analyzer},
+	Name:             "nilaway_annotation_analyzer",
+	Doc:              _doc,
+	Run:              analysishelper.WrapRun(run),
+	ResultType:       reflect.TypeOf((*analysishelper.Result[*ObservedMap])(nil)),
+	Requires:         []*analysis.Analyzer{config.Analyzer},
+	RunDespiteErrors: true,
 }
 
 func run(pass *analysis.Pass) (*ObservedMap, error) {
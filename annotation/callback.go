@@ -0,0 +1,102 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package annotation
+
+import (
+	"go/ast"
+	"go/types"
+
+	"go.uber.org/nilaway/util"
+)
+
+// This file contains the annotation-level support for callbacks, i.e., functions passed as the
+// function-typed parameters of other functions (e.g., `Walk(handle)` for
+// `func Walk(cb func(x *T) error)`), or stored in the function-typed struct fields (e.g.,
+// `s.onEvent = handle` for `onEvent func(e *Event)`).
+
+// A CallbackPair is the atomic object of the callback mechanism: a pair consisting of a function
+// with a function-typed parameter at position `ParamNum` (the callback), and a function passed as
+// that parameter at a call site. If FieldDecl is set, the callback is instead the function-typed
+// struct field, and the function is the one stored in the field.
+type CallbackPair struct {
+	FuncDecl  *types.Func
+	ParamNum  int
+	FieldDecl *types.Var
+	Callback  *types.Func
+}
+
+// paramKey returns the key on the `num`-th parameter of the callback of the pair.
+func (pair CallbackPair) paramKey(num int) *CallbackParamAnnotationKey {
+	if pair.FieldDecl != nil {
+		return NewFieldCallbackParamKey(pair.FieldDecl, num)
+	}
+	return NewCallbackParamKey(pair.FuncDecl, pair.ParamNum, num)
+}
+
+// FullTriggerForCallbackParamFlow takes the knowledge that `pair` represents a function passed as
+// a callback at the call site `arg` and returns a FullTrigger representing the assertion that the
+// callback can be passed a nilable argument at position `paramNum` only if the function passed
+// has such a nilable parameter. This is analogous to the contravariance of the parameters of the
+// methods implementing an interface (see FullTriggerForInterfaceParamFlow).
+// Precondition: paramNum < numParams(pair.Callback)
+func FullTriggerForCallbackParamFlow(pair CallbackPair, paramNum int, arg ast.Expr) FullTrigger {
+	return FullTrigger{
+		Producer: &ProduceTrigger{
+			Annotation: &CallbackParamReachesFunc{
+				TriggerIfNilable: &TriggerIfNilable{
+					Ann: pair.paramKey(paramNum)},
+				CallbackPair: pair,
+			},
+			Expr: arg,
+		},
+		Consumer: &ConsumeTrigger{
+			Annotation: &FuncParamFromCallback{
+				TriggerIfNonNil: &TriggerIfNonNil{
+					Ann: ParamKeyFromArgNum(pair.Callback, paramNum)},
+				CallbackPair: pair,
+			},
+			Expr:   arg,
+			Guards: util.NoGuards(),
+		},
+	}
+}
+
+// FullTriggerForCallbackResultFlow takes the knowledge that `pair` represents a function passed as
+// a callback at the call site `arg` and returns a FullTrigger representing the assertion that the
+// function passed can have a nilable result at position `retNum` only if the callback has such a
+// nilable result. This is analogous to the covariance of the results of the methods implementing
+// an interface (see FullTriggerForInterfaceResultFlow).
+// Precondition: retNum < numResults(pair.Callback)
+func FullTriggerForCallbackResultFlow(pair CallbackPair, retNum int, arg ast.Expr) FullTrigger {
+	return FullTrigger{
+		Producer: &ProduceTrigger{
+			Annotation: &FuncResultReachesCallback{
+				TriggerIfNilable: &TriggerIfNilable{
+					Ann: RetKeyFromRetNum(pair.Callback, retNum)},
+				CallbackPair: pair,
+			},
+			Expr: arg,
+		},
+		Consumer: &ConsumeTrigger{
+			Annotation: &CallbackResultFromFunc{
+				TriggerIfNonNil: &TriggerIfNonNil{
+					Ann: &CallbackRetAnnotationKey{FuncDecl: pair.FuncDecl, ParamNum: pair.ParamNum, FieldDecl: pair.FieldDecl, RetNum: retNum}},
+				CallbackPair: pair,
+			},
+			Expr:   arg,
+			Guards: util.NoGuards(),
+		},
+	}
+}
@@ -67,6 +67,10 @@ type ConsumingAnnotationTrigger interface {
 	// SetNeedsGuard sets the underlying Guard-Neediness of this ConsumerTrigger, if present.
 	// Default setting for ConsumerTriggers is that they need a guard. Override this method to set the need for a guard to false.
 	SetNeedsGuard(bool)
+
+	// elideTrivialAssignments elides the trivial single-use temporaries from the assignment flow of
+	// the trigger when printed in its Prestring (see assignmentFlow.elideTrivialAssignments).
+	elideTrivialAssignments()
 }
 
 // Prestring is an interface used to encode objects that have compact on-the-wire encodings
@@ -112,6 +116,9 @@ type assignmentFlow struct {
 	// We use ordered map for `assignments` to maintain the order of assignments in the flow, and also to avoid
 	// duplicates that can get introduced due to fix point convergence in backpropagation.
 	assignments *orderedmap.OrderedMap[Assignment, bool]
+	// elided indicates whether the trivial single-use temporaries are elided from the printed flow
+	// (see elideTrivialAssignments).
+	elided bool
 }
 
 func (a *assignmentFlow) addEntry(entry Assignment) {
@@ -123,13 +130,25 @@ func (a *assignmentFlow) addEntry(entry Assignment) {
 
 func (a *assignmentFlow) copy() assignmentFlow {
 	if a.assignments == nil {
-		return assignmentFlow{}
+		return assignmentFlow{elided: a.elided}
 	}
 	assignments := orderedmap.New[Assignment, bool]()
 	for _, p := range a.assignments.Pairs {
 		assignments.Store(p.Key, true)
 	}
-	return assignmentFlow{assignments: assignments}
+	return assignmentFlow{assignments: assignments, elided: a.elided}
+}
+
+// elideTrivialAssignments elides the trivial single-use temporaries from the flow when printed,
+// i.e., the variables that are assigned and then copied to another variable only once in the
+// flow. The chains of such copies are merged into one assignment naming the elided temporaries,
+// e.g., the flow
+//
+//	`foo()` to `x` at a.go:1:2, `x` to `y` at a.go:2:2, `y` to `z` at a.go:3:2
+//
+// is printed as "`foo()` to `z` via `x`, `y` at a.go:3:2".
+func (a *assignmentFlow) elideTrivialAssignments() {
+	a.elided = true
 }
 
 func (a *assignmentFlow) String() string {
@@ -138,10 +157,23 @@ func (a *assignmentFlow) String() string {
 	}
 
 	// backprop algorithm populates assignment entries in backward order. Reverse entries to get forward order of
-	// assignments, and store in `strs` slice.
-	strs := make([]string, 0, len(a.assignments.Pairs))
+	// assignments.
+	assignments := make([]Assignment, 0, len(a.assignments.Pairs))
 	for i := len(a.assignments.Pairs) - 1; i >= 0; i-- {
-		strs = append(strs, a.assignments.Pairs[i].Key.String())
+		assignments = append(assignments, a.assignments.Pairs[i].Key)
+	}
+	var merged []mergedAssignment
+	if a.elided {
+		merged = mergeTrivialCopies(assignments)
+	} else {
+		merged = make([]mergedAssignment, len(assignments))
+		for i, assignment := range assignments {
+			merged[i] = mergedAssignment{Assignment: assignment}
+		}
+	}
+	strs := make([]string, len(merged))
+	for i := range merged {
+		strs[i] = merged[i].String()
 	}
 
 	// build the informative print string tracking the assignments
@@ -151,6 +183,44 @@ func (a *assignmentFlow) String() string {
 	return sb.String()
 }
 
+// mergedAssignment is an assignment with the temporaries elided from the chain of copies ending in
+// it (see mergeTrivialCopies).
+type mergedAssignment struct {
+	Assignment
+	temps []string
+}
+
+func (a *mergedAssignment) String() string {
+	if len(a.temps) == 0 {
+		return a.Assignment.String()
+	}
+	return fmt.Sprintf("`%s` to `%s` via `%s` at %s", a.RHSExprStr, a.LHSExprStr, strings.Join(a.temps, "`, `"), a.Position)
+}
+
+// mergeTrivialCopies merges the chains of copies through the trivial single-use temporaries in the
+// assignments (in the forward order), see assignmentFlow.elideTrivialAssignments.
+func mergeTrivialCopies(assignments []Assignment) []mergedAssignment {
+	lhsCount, rhsCount := make(map[string]int), make(map[string]int)
+	for _, a := range assignments {
+		lhsCount[a.LHSExprStr]++
+		rhsCount[a.RHSExprStr]++
+	}
+
+	merged := []mergedAssignment{{Assignment: assignments[0]}}
+	for _, a := range assignments[1:] {
+		last := &merged[len(merged)-1]
+		temp := last.LHSExprStr
+		if a.RHSExprStr != temp || !token.IsIdentifier(temp) || !token.IsIdentifier(a.LHSExprStr) ||
+			lhsCount[temp] != 1 || rhsCount[temp] != 1 {
+			merged = append(merged, mergedAssignment{Assignment: a})
+			continue
+		}
+		last.temps = append(last.temps, temp)
+		last.LHSExprStr, last.Position = a.LHSExprStr, a.Position
+	}
+	return merged
+}
+
 // TriggerIfNonNil is triggered if the contained Annotation is non-nil
 type TriggerIfNonNil struct {
 	Ann              Key
@@ -400,7 +470,8 @@ func (p PtrLoadPrestring) String() string {
 
 // MapAccess is when a map value flows to a point where it is indexed, and thus must be non-nil
 //
-// note: this trigger is produced only if config.ErrorOnNilableMapRead == true
+// note: this trigger is produced only if the reads from maps are checked strictly (see
+// config.ErrorOnNilableMapReadFlag)
 type MapAccess struct {
 	*ConsumeTriggerTautology
 }
@@ -684,6 +755,49 @@ func (f FldAssignPrestring) String() string {
 	return sb.String()
 }
 
+// FldOmitted is when a field is omitted from a struct composite literal (e.g., `T{}` or
+// `T{OnlySomeFields: x}`), leaving the field nil.
+type FldOmitted struct {
+	*TriggerIfNonNil
+}
+
+// equals returns true if the passed ConsumingAnnotationTrigger is equal to this one
+func (f *FldOmitted) equals(other ConsumingAnnotationTrigger) bool {
+	if other, ok := other.(*FldOmitted); ok {
+		return f.TriggerIfNonNil.equals(other.TriggerIfNonNil)
+	}
+	return false
+}
+
+// Copy returns a deep copy of this ConsumingAnnotationTrigger
+func (f *FldOmitted) Copy() ConsumingAnnotationTrigger {
+	copyConsumer := *f
+	copyConsumer.TriggerIfNonNil = f.TriggerIfNonNil.Copy().(*TriggerIfNonNil)
+	return &copyConsumer
+}
+
+// Prestring returns this FldOmitted as a Prestring
+func (f *FldOmitted) Prestring() Prestring {
+	fldAnn := f.Ann.(*FieldAnnotationKey)
+	return FldOmittedPrestring{
+		FieldName:     fldAnn.FieldDecl.Name(),
+		AssignmentStr: f.assignmentFlow.String(),
+	}
+}
+
+// FldOmittedPrestring is a Prestring storing the needed information to compactly encode a FldOmitted
+type FldOmittedPrestring struct {
+	FieldName     string
+	AssignmentStr string
+}
+
+func (f FldOmittedPrestring) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("field `%s` omitted from the composite literal", f.FieldName))
+	sb.WriteString(f.AssignmentStr)
+	return sb.String()
+}
+
 // ArgFldPass is when a struct field value (A.f) flows to a point where it is passed to a function with a param of
 // the same struct type (A)
 type ArgFldPass struct {
@@ -736,7 +850,7 @@ type ArgFldPassPrestring struct {
 
 func (f ArgFldPassPrestring) String() string {
 	var sb strings.Builder
-	prefix := ""
+	prefix := "passed as "
 	if f.IsPassed {
 		prefix = "assigned to "
 	}
@@ -798,7 +912,9 @@ func (g GlobalVarAssignPrestring) String() string {
 // CallSiteParamAnnotationKey. ParamAnnotationKey is the parameter site in the function
 // declaration; CallSiteParamAnnotationKey is the argument site in the call expression.
 // CallSiteParamAnnotationKey is specifically used for functions with contracts since we need to
-// duplicate the sites for context sensitivity.
+// duplicate the sites for context sensitivity. It is also used on top of
+// CallbackParamAnnotationKey for the arguments passed to a callback (i.e., a function-typed
+// parameter) of the function.
 type ArgPass struct {
 	*TriggerIfNonNil
 }
@@ -835,9 +951,17 @@ func (a *ArgPass) Prestring() Prestring {
 			Location:      key.Location.String(),
 			AssignmentStr: a.assignmentFlow.String(),
 		}
+	case *CallbackParamAnnotationKey:
+		// A call to a callback (i.e., a function-typed parameter) of the function.
+		return ArgPassPrestring{
+			ParamName:     key.MinimalString(),
+			FuncName:      key.CallbackName(),
+			Location:      "",
+			AssignmentStr: a.assignmentFlow.String(),
+		}
 	default:
 		panic(fmt.Sprintf(
-			"Expected ParamAnnotationKey or CallSiteParamAnnotationKey but got: %T", key))
+			"Expected ParamAnnotationKey, CallSiteParamAnnotationKey or CallbackParamAnnotationKey but got: %T", key))
 	}
 }
 
@@ -928,12 +1052,34 @@ func (a ArgPassDeepPrestring) String() string {
 // E.g., `s.foo()`, here `s` is a receiver and forms the RecvPass Consumer
 type RecvPass struct {
 	*TriggerIfNonNil
+
+	// InterfaceMethod is the interface method the method is invoked via, if the receiver value
+	// is assigned to an interface before the invocation, e.g., `var i I = s; i.foo()` (see
+	// RecvPassViaInterface).
+	InterfaceMethod *types.Func
+}
+
+// RecvPassViaInterface converts the given consumer of an interface value invoking an interface
+// method (e.g., `i.foo()`) into a RecvPass for the implementing method, when the dynamic type of
+// the value is known to be that of the implementing method's receiver (e.g., after `var i I = s`).
+// This respects the nil-receiver safety of the implementing method, since a nil pointer assigned
+// to an interface is passed to the method as a nil receiver, rather than causing a panic at the
+// invocation. The assignments tracked by the given consumer are kept.
+func RecvPassViaInterface(f *FldAccess, implementingMethod *types.Func) *RecvPass {
+	return &RecvPass{
+		TriggerIfNonNil: &TriggerIfNonNil{
+			Ann:              &RecvAnnotationKey{FuncDecl: implementingMethod},
+			IsGuardNotNeeded: f.IsGuardNotNeeded,
+			assignmentFlow:   f.assignmentFlow.copy(),
+		},
+		InterfaceMethod: f.Sel.(*types.Func),
+	}
 }
 
 // equals returns true if the passed ConsumingAnnotationTrigger is equal to this one
 func (a *RecvPass) equals(other ConsumingAnnotationTrigger) bool {
 	if other, ok := other.(*RecvPass); ok {
-		return a.TriggerIfNonNil.equals(other.TriggerIfNonNil)
+		return a.TriggerIfNonNil.equals(other.TriggerIfNonNil) && a.InterfaceMethod == other.InterfaceMethod
 	}
 	return false
 }
@@ -948,21 +1094,30 @@ func (a *RecvPass) Copy() ConsumingAnnotationTrigger {
 // Prestring returns this RecvPass as a Prestring
 func (a *RecvPass) Prestring() Prestring {
 	recvAnn := a.Ann.(*RecvAnnotationKey)
+	interfaceMethodName := ""
+	if a.InterfaceMethod != nil {
+		interfaceMethodName = util.PartiallyQualifiedFuncName(a.InterfaceMethod)
+	}
 	return RecvPassPrestring{
-		FuncName:      recvAnn.FuncDecl.Name(),
-		AssignmentStr: a.assignmentFlow.String(),
+		FuncName:            recvAnn.FuncDecl.Name(),
+		InterfaceMethodName: interfaceMethodName,
+		AssignmentStr:       a.assignmentFlow.String(),
 	}
 }
 
 // RecvPassPrestring is a Prestring storing the needed information to compactly encode a RecvPass
 type RecvPassPrestring struct {
-	FuncName      string
-	AssignmentStr string
+	FuncName            string
+	InterfaceMethodName string
+	AssignmentStr       string
 }
 
 func (a RecvPassPrestring) String() string {
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("used as receiver to call `%s()`", a.FuncName))
+	if a.InterfaceMethodName != "" {
+		sb.WriteString(fmt.Sprintf(" through interface method `%s()`", a.InterfaceMethodName))
+	}
 	sb.WriteString(a.AssignmentStr)
 	return sb.String()
 }
@@ -1017,6 +1172,108 @@ func (i InterfaceResultFromImplementationPrestring) String() string {
 	return sb.String()
 }
 
+// FuncParamFromCallback is when a param flows from a callback to a function passed as the callback
+type FuncParamFromCallback struct {
+	*TriggerIfNonNil
+	CallbackPair
+}
+
+// equals returns true if the passed ConsumingAnnotationTrigger is equal to this one
+func (f *FuncParamFromCallback) equals(other ConsumingAnnotationTrigger) bool {
+	if other, ok := other.(*FuncParamFromCallback); ok {
+		return f.TriggerIfNonNil.equals(other.TriggerIfNonNil) && f.CallbackPair == other.CallbackPair
+	}
+	return false
+}
+
+// Copy returns a deep copy of this ConsumingAnnotationTrigger
+func (f *FuncParamFromCallback) Copy() ConsumingAnnotationTrigger {
+	copyConsumer := *f
+	copyConsumer.TriggerIfNonNil = f.TriggerIfNonNil.Copy().(*TriggerIfNonNil)
+	return &copyConsumer
+}
+
+// Prestring returns this FuncParamFromCallback as a Prestring
+func (f *FuncParamFromCallback) Prestring() Prestring {
+	paramAnn := f.Ann.(*ParamAnnotationKey)
+	return FuncParamFromCallbackPrestring{
+		paramAnn.ParamNameString(),
+		util.PartiallyQualifiedFuncName(paramAnn.FuncDecl),
+		f.assignmentFlow.String(),
+	}
+}
+
+// FuncParamFromCallbackPrestring is a Prestring storing the needed information to compactly encode a FuncParamFromCallback
+type FuncParamFromCallbackPrestring struct {
+	ParamName     string
+	FuncName      string
+	AssignmentStr string
+}
+
+func (f FuncParamFromCallbackPrestring) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("passed as parameter `%s` to `%s()`", f.ParamName, f.FuncName))
+	sb.WriteString(f.AssignmentStr)
+	return sb.String()
+}
+
+// CallbackResultFromFunc is when a result flows from a function passed as a callback to the callback
+type CallbackResultFromFunc struct {
+	*TriggerIfNonNil
+	CallbackPair
+}
+
+// equals returns true if the passed ConsumingAnnotationTrigger is equal to this one
+func (c *CallbackResultFromFunc) equals(other ConsumingAnnotationTrigger) bool {
+	if other, ok := other.(*CallbackResultFromFunc); ok {
+		return c.TriggerIfNonNil.equals(other.TriggerIfNonNil) && c.CallbackPair == other.CallbackPair
+	}
+	return false
+}
+
+// Copy returns a deep copy of this ConsumingAnnotationTrigger
+func (c *CallbackResultFromFunc) Copy() ConsumingAnnotationTrigger {
+	copyConsumer := *c
+	copyConsumer.TriggerIfNonNil = c.TriggerIfNonNil.Copy().(*TriggerIfNonNil)
+	return &copyConsumer
+}
+
+// Prestring returns this CallbackResultFromFunc as a Prestring
+func (c *CallbackResultFromFunc) Prestring() Prestring {
+	retAnn := c.Ann.(*CallbackRetAnnotationKey)
+	funcName := ""
+	if retAnn.FieldDecl == nil {
+		funcName = util.PartiallyQualifiedFuncName(retAnn.FuncDecl)
+	}
+	return CallbackResultFromFuncPrestring{
+		retAnn.RetNum,
+		retAnn.CallbackName(),
+		funcName,
+		c.assignmentFlow.String(),
+	}
+}
+
+// CallbackResultFromFuncPrestring is a Prestring storing the needed information to compactly encode a CallbackResultFromFunc
+type CallbackResultFromFuncPrestring struct {
+	RetNum       int
+	CallbackName string
+	// FuncName is empty for the callbacks stored in the struct fields.
+	FuncName      string
+	AssignmentStr string
+}
+
+func (c CallbackResultFromFuncPrestring) String() string {
+	var sb strings.Builder
+	if c.FuncName == "" {
+		sb.WriteString(fmt.Sprintf("returned as result %d from callback field `%s`", c.RetNum, c.CallbackName))
+	} else {
+		sb.WriteString(fmt.Sprintf("returned as result %d from callback `%s` of `%s()`",
+			c.RetNum, c.CallbackName, c.FuncName))
+	}
+	sb.WriteString(c.AssignmentStr)
+	return sb.String()
+}
+
 // MethodParamFromInterface is when a param flows from an interface method to a concrete method via implementation
 type MethodParamFromInterface struct {
 	*TriggerIfNonNil
@@ -1067,15 +1324,16 @@ func (m MethodParamFromInterfacePrestring) String() string {
 	return sb.String()
 }
 
-// DuplicateReturnConsumer duplicates a given consume trigger, assuming the given consumer trigger
-// is for a UseAsReturn annotation.
-func DuplicateReturnConsumer(t *ConsumeTrigger, location token.Position) *ConsumeTrigger {
+// DuplicateReturnConsumer duplicates a given consume trigger for the call site of the callee,
+// assuming the given consumer trigger is for a UseAsReturn annotation. The callee is the function
+// of the result, or an interface method devirtualized to it.
+func DuplicateReturnConsumer(t *ConsumeTrigger, callee *types.Func, location token.Position) *ConsumeTrigger {
 	ann := t.Annotation.(*UseAsReturn)
 	key := ann.TriggerIfNonNil.Ann.(*RetAnnotationKey)
 	return &ConsumeTrigger{
 		Annotation: &UseAsReturn{
 			TriggerIfNonNil: &TriggerIfNonNil{
-				Ann: NewCallSiteRetKey(key.FuncDecl, key.RetNum, location)},
+				Ann: NewCallSiteRetKey(callee, key.RetNum, location)},
 			IsNamedReturn: ann.IsNamedReturn,
 			RetStmt:       ann.RetStmt,
 		},
@@ -1874,6 +2132,54 @@ func (f FldEscapePrestring) String() string {
 	return sb.String()
 }
 
+// ReflectEscape is when a value escapes out of our analysis scope via reflection (e.g.,
+// `reflect.ValueOf(x)`), an `unsafe.Pointer` conversion, or a cgo call. The escaped values are
+// typically dereferenced by code we cannot analyze (e.g., reflection-based setters such as
+// `reflect.ValueOf(x).Elem().Set(...)` panic on nil pointers), hence the conservative
+// reflect-escape mode presumes them to be nonnil.
+type ReflectEscape struct {
+	*ConsumeTriggerTautology
+
+	// Via describes how the value escapes, e.g., "`reflect.ValueOf`".
+	Via string
+}
+
+// equals returns true if the passed ConsumingAnnotationTrigger is equal to this one
+func (r *ReflectEscape) equals(other ConsumingAnnotationTrigger) bool {
+	if other, ok := other.(*ReflectEscape); ok {
+		return r.ConsumeTriggerTautology.equals(other.ConsumeTriggerTautology) && r.Via == other.Via
+	}
+	return false
+}
+
+// Copy returns a deep copy of this ConsumingAnnotationTrigger
+func (r *ReflectEscape) Copy() ConsumingAnnotationTrigger {
+	copyConsumer := *r
+	copyConsumer.ConsumeTriggerTautology = r.ConsumeTriggerTautology.Copy().(*ConsumeTriggerTautology)
+	return &copyConsumer
+}
+
+// Prestring returns this ReflectEscape as a Prestring
+func (r *ReflectEscape) Prestring() Prestring {
+	return ReflectEscapePrestring{
+		Via:           r.Via,
+		AssignmentStr: r.assignmentFlow.String(),
+	}
+}
+
+// ReflectEscapePrestring is a Prestring storing the needed information to compactly encode a ReflectEscape
+type ReflectEscapePrestring struct {
+	Via           string
+	AssignmentStr string
+}
+
+func (r ReflectEscapePrestring) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("escaped via %s out of our analysis scope (presumed nonnil)", r.Via))
+	sb.WriteString(r.AssignmentStr)
+	return sb.String()
+}
+
 // UseAsNonErrorRetDependentOnErrorRetNilability is when a value flows to a point where it is returned from an error returning function
 type UseAsNonErrorRetDependentOnErrorRetNilability struct {
 	*TriggerIfNonNil
@@ -2094,9 +2400,14 @@ func (c *ConsumeTrigger) Pos() token.Pos {
 // it intersects guard sets
 func MergeConsumeTriggerSlices(left, right []*ConsumeTrigger) []*ConsumeTrigger {
 	var out []*ConsumeTrigger
+	// outIndex buckets the indices of the triggers in `out` by their keys, so that we only need to
+	// compare a new trigger against the few existing ones that could possibly be equal to it.
+	outIndex := make(map[consumeTriggerKey][]int, len(left)+len(right))
 
 	addToOut := func(trigger *ConsumeTrigger) {
-		for i, outTrigger := range out {
+		key := keyOfConsumer(trigger)
+		for _, i := range outIndex[key] {
+			outTrigger := out[i]
 			if outTrigger.Annotation.equals(trigger.Annotation) &&
 				outTrigger.Expr == trigger.Expr {
 				// intersect guard sets - if a guard isn't present in both branches it can't
@@ -2110,6 +2421,7 @@ func MergeConsumeTriggerSlices(left, right []*ConsumeTrigger) []*ConsumeTrigger
 				return
 			}
 		}
+		outIndex[key] = append(outIndex[key], len(out))
 		out = append(out, trigger)
 	}
 
@@ -2145,9 +2457,16 @@ func ConsumeTriggerSlicesEq(left, right []*ConsumeTrigger) bool {
 	if len(left) != len(right) {
 		return false
 	}
+
+	rightIndex := make(map[consumeTriggerKey][]*ConsumeTrigger, len(right))
+	for _, r := range right {
+		k := keyOfConsumer(r)
+		rightIndex[k] = append(rightIndex[k], r)
+	}
+
 lsearch:
 	for _, l := range left {
-		for _, r := range right {
+		for _, r := range rightIndex[keyOfConsumer(l)] {
 			if l.equals(r) {
 				continue lsearch
 			}
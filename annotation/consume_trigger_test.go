@@ -15,8 +15,11 @@
 package annotation
 
 import (
+	"go/token"
+	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -34,12 +37,15 @@ var initStructsConsumingAnnotationTrigger = []any{
 	&FldAccess{ConsumeTriggerTautology: &ConsumeTriggerTautology{}},
 	&UseAsErrorResult{TriggerIfNonNil: &TriggerIfNonNil{Ann: newMockKey()}},
 	&FldAssign{TriggerIfNonNil: &TriggerIfNonNil{Ann: newMockKey()}},
+	&FldOmitted{TriggerIfNonNil: &TriggerIfNonNil{Ann: newMockKey()}},
 	&ArgFldPass{TriggerIfNonNil: &TriggerIfNonNil{Ann: newMockKey()}},
 	&GlobalVarAssign{TriggerIfNonNil: &TriggerIfNonNil{Ann: newMockKey()}},
 	&ArgPass{TriggerIfNonNil: &TriggerIfNonNil{Ann: newMockKey()}},
 	&RecvPass{TriggerIfNonNil: &TriggerIfNonNil{Ann: newMockKey()}},
 	&InterfaceResultFromImplementation{TriggerIfNonNil: &TriggerIfNonNil{Ann: newMockKey()}},
 	&MethodParamFromInterface{TriggerIfNonNil: &TriggerIfNonNil{Ann: newMockKey()}},
+	&FuncParamFromCallback{TriggerIfNonNil: &TriggerIfNonNil{Ann: newMockKey()}},
+	&CallbackResultFromFunc{TriggerIfNonNil: &TriggerIfNonNil{Ann: newMockKey()}},
 	&UseAsReturn{TriggerIfNonNil: &TriggerIfNonNil{Ann: newMockKey()}},
 	&UseAsFldOfReturn{TriggerIfNonNil: &TriggerIfNonNil{Ann: newMockKey()}},
 	&SliceAssign{TriggerIfDeepNonNil: &TriggerIfDeepNonNil{Ann: newMockKey()}},
@@ -55,10 +61,12 @@ var initStructsConsumingAnnotationTrigger = []any{
 	&LocalVarAssignDeep{TriggerIfDeepNonNil: &TriggerIfDeepNonNil{Ann: newMockKey()}},
 	&ChanSend{TriggerIfDeepNonNil: &TriggerIfDeepNonNil{Ann: newMockKey()}},
 	&FldEscape{TriggerIfNonNil: &TriggerIfNonNil{Ann: newMockKey()}},
+	&ReflectEscape{ConsumeTriggerTautology: &ConsumeTriggerTautology{}},
 	&UseAsNonErrorRetDependentOnErrorRetNilability{TriggerIfNonNil: &TriggerIfNonNil{Ann: newMockKey()}},
 	&UseAsErrorRetWithNilabilityUnknown{TriggerIfNonNil: &TriggerIfNonNil{Ann: newMockKey()}},
 	&ArgPassDeep{TriggerIfDeepNonNil: &TriggerIfDeepNonNil{Ann: newMockKey()}},
 	&UseAsReturnDeep{TriggerIfDeepNonNil: &TriggerIfDeepNonNil{Ann: newMockKey()}},
+	&PluginConsumer{ConsumingAnnotationTrigger: &ConsumeTriggerTautology{}, Plugin: &mockPluginTrigger{}},
 }
 
 // ConsumingAnnotationTriggerEqualsTestSuite tests for the `equals` method of all the structs that implement
@@ -91,3 +99,72 @@ func TestConsumingAnnotationTriggerCopySuite(t *testing.T) {
 	t.Parallel()
 	suite.Run(t, new(ConsumingAnnotationTriggerCopyTestSuite))
 }
+
+func TestElideTrivialAssignments(t *testing.T) {
+	t.Parallel()
+
+	// assign returns the assignment of rhs to lhs at the given line of a.go.
+	assign := func(rhs, lhs string, line int) Assignment {
+		return Assignment{LHSExprStr: lhs, RHSExprStr: rhs, Position: token.Position{Filename: "a.go", Line: line, Column: 2}}
+	}
+	flow := func(assignments ...string) string {
+		return " via the assignment(s):\n\t\t- " + strings.Join(assignments, ",\n\t\t- ")
+	}
+	tests := []struct {
+		name        string
+		assignments []Assignment
+		want        string
+	}{
+		{
+			name: "no assignment flow",
+			want: "",
+		},
+		{
+			name:        "chain of temporaries",
+			assignments: []Assignment{assign("foo()", "x", 1), assign("x", "y", 2), assign("y", "z", 3)},
+			want:        flow("`foo()` to `z` via `x`, `y` at a.go:3:2"),
+		},
+		{
+			name:        "temporary assigned twice",
+			assignments: []Assignment{assign("nil", "x", 1), assign("x", "y", 2), assign("nil", "x", 3)},
+			want:        flow("`nil` to `x` at a.go:1:2", "`x` to `y` at a.go:2:2", "`nil` to `x` at a.go:3:2"),
+		},
+		{
+			name:        "copy to a field",
+			assignments: []Assignment{assign("nil", "x", 1), assign("x", "s.f", 2)},
+			want:        flow("`nil` to `x` at a.go:1:2", "`x` to `s.f` at a.go:2:2"),
+		},
+		{
+			name:        "chains separated by a non-trivial assignment",
+			assignments: []Assignment{assign("nil", "x", 1), assign("x", "y", 2), assign("m[y]", "a", 3), assign("a", "b", 4)},
+			want:        flow("`nil` to `y` via `x` at a.go:2:2", "`m[y]` to `b` via `a` at a.go:4:2"),
+		},
+		{
+			name:        "expression with backticks",
+			assignments: []Assignment{assign("f(`x` to `y`)", "x", 1), assign("x", "y", 2)},
+			want:        flow("`f(`x` to `y`)` to `y` via `x` at a.go:2:2"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var full, elided assignmentFlow
+			// The assignments are added in the backward order by backpropagation.
+			for i := len(tt.assignments) - 1; i >= 0; i-- {
+				full.addEntry(tt.assignments[i])
+				elided.addEntry(tt.assignments[i])
+			}
+			elided.elideTrivialAssignments()
+			require.Equal(t, tt.want, elided.String())
+
+			// The full flow is printed as is.
+			strs := make([]string, len(tt.assignments))
+			for i := range tt.assignments {
+				strs[i] = tt.assignments[i].String()
+			}
+			if len(strs) > 0 {
+				require.Equal(t, flow(strs...), full.String())
+			}
+		})
+	}
+}
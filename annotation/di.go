@@ -0,0 +1,254 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package annotation
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"reflect"
+	"regexp"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// DIFramework describes a dependency injection framework (e.g., fx) that NilAway is aware of. The
+// framework calls the constructors registered with it, populating their parameters (and the fields
+// of their parameter objects) with the values provided by other constructors, which is never
+// visible in the analyzed code. Hence, such parameters and fields are assumed nonnil. Other
+// frameworks can be supported via RegisterDIFramework.
+//
+// The functions are matched by their fully qualified names, i.e., "<pkg path>.<func name>" for
+// functions and "<pkg path>.<type name>.<method name>" for methods. All matchers are optional.
+type DIFramework struct {
+	// Name is the name of the framework, e.g., "fx".
+	Name string
+	// Providers matches the functions registering the constructors (or the functions to invoke)
+	// given as arguments with the framework, e.g., `fx.Provide(NewServer)`.
+	Providers *regexp.Regexp
+	// Wrappers matches the functions wrapping the constructors given as their first arguments,
+	// e.g., `fx.Provide(fx.Annotate(NewServer, ...))`.
+	Wrappers *regexp.Regexp
+	// StructProviders matches the functions registering the structs given as `new(T)` in their
+	// first arguments, whose fields named in the rest of the arguments ("*" for all fields) are
+	// populated by the framework, e.g., `wire.Struct(new(Server), "*")`.
+	StructProviders *regexp.Regexp
+	// ParamObjects matches the fully qualified names ("<pkg path>.<type name>") of the marker types
+	// embedded in the parameter objects, whose fields are populated by the framework, e.g., the
+	// structs embedding `fx.In`.
+	ParamObjects *regexp.Regexp
+	// OptionalTag is the key of the struct tag marking the fields of the parameter objects that
+	// are left unpopulated (i.e., nil) if no value is provided, e.g., `optional:"true"`. Such
+	// fields are assumed nilable instead.
+	OptionalTag string
+}
+
+var (
+	_diFrameworksMu sync.RWMutex
+	_diFrameworks   []DIFramework
+)
+
+// RegisterDIFramework registers the dependency injection framework. It must be called before the
+// analysis starts (e.g., in an init function of a custom driver).
+func RegisterDIFramework(f DIFramework) {
+	_diFrameworksMu.Lock()
+	defer _diFrameworksMu.Unlock()
+	_diFrameworks = append(_diFrameworks, f)
+}
+
+func init() {
+	// fx (https://uber-go.github.io/fx) is built on top of dig, where `fx.In` is an alias of
+	// `dig.In`.
+	RegisterDIFramework(DIFramework{
+		Name:         "fx",
+		Providers:    regexp.MustCompile(`^go\.uber\.org/fx\.(Provide|Invoke|Decorate)$`),
+		Wrappers:     regexp.MustCompile(`^go\.uber\.org/fx\.Annotate$`),
+		ParamObjects: regexp.MustCompile(`^go\.uber\.org/fx\.In$`),
+		OptionalTag:  "optional",
+	})
+	RegisterDIFramework(DIFramework{
+		Name:         "dig",
+		Providers:    regexp.MustCompile(`^go\.uber\.org/dig\.(Container|Scope)\.(Provide|Invoke|Decorate)$`),
+		ParamObjects: regexp.MustCompile(`^go\.uber\.org/dig\.In$`),
+		OptionalTag:  "optional",
+	})
+	// wire (https://github.com/google/wire) generates the injectors calling the providers at build
+	// time, but the providers in the sets are referenced only by the declarations of the sets.
+	RegisterDIFramework(DIFramework{
+		Name:            "wire",
+		Providers:       regexp.MustCompile(`^github\.com/google/wire\.(NewSet|Build)$`),
+		StructProviders: regexp.MustCompile(`^github\.com/google/wire\.Struct$`),
+	})
+}
+
+// observeDIInjections adds the annotations for the sites populated by the registered dependency
+// injection frameworks (see DIFramework) to the map, i.e., the parameters of the constructors
+// registered with the frameworks and the fields of the parameter objects. Only the sites in the
+// current package are considered, since the upstream packages are already analyzed. The
+// annotation comments take precedence over the injections.
+func (m *ObservedMap) observeDIInjections(pass *analysis.Pass, files []*ast.File) {
+	_diFrameworksMu.RLock()
+	defer _diFrameworksMu.RUnlock()
+	if len(_diFrameworks) == 0 {
+		return
+	}
+
+	isLocal := func(obj types.Object) bool { return obj != nil && obj.Pkg() == pass.Pkg }
+	observeFunc := func(expr ast.Expr) {
+		var ident *ast.Ident
+		switch expr := ast.Unparen(expr).(type) {
+		case *ast.Ident:
+			ident = expr
+		case *ast.SelectorExpr:
+			ident = expr.Sel
+		default:
+			return
+		}
+		fn, ok := pass.TypesInfo.Uses[ident].(*types.Func)
+		if !ok || !isLocal(fn) {
+			return
+		}
+		params := fn.Type().(*types.Signature).Params()
+		vals := make([]Val, params.Len())
+		for i := 0; i < params.Len(); i++ {
+			var val Val
+			if existing := m.funcParamAnnMap[fn]; i < len(existing) {
+				val = existing[i]
+			}
+			vals[i] = injectedVal(val, params.At(i).Type(), false /* isNilable */)
+		}
+		m.funcParamAnnMap[fn] = vals
+	}
+	observeFields := func(st *types.Struct, names map[string]bool, optionalTag string) {
+		for i := 0; i < st.NumFields(); i++ {
+			fld := st.Field(i)
+			if fld.Embedded() || !isLocal(fld) || (names != nil && !names["*"] && !names[fld.Name()]) {
+				continue
+			}
+			isOptional := optionalTag != "" && reflect.StructTag(st.Tag(i)).Get(optionalTag) == "true"
+			m.fieldAnnMap[fld] = injectedVal(m.fieldAnnMap[fld], fld.Type(), isOptional)
+		}
+	}
+
+	for _, file := range files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.StructType:
+				st, ok := pass.TypesInfo.TypeOf(node).(*types.Struct)
+				if !ok {
+					return true
+				}
+				for _, f := range _diFrameworks {
+					if f.ParamObjects != nil && embedsParamObject(st, f.ParamObjects) {
+						observeFields(st, nil /* names */, f.OptionalTag)
+					}
+				}
+			case *ast.CallExpr:
+				fn, ok := typeutil.Callee(pass.TypesInfo, node).(*types.Func)
+				if !ok {
+					return true
+				}
+				name := qualifiedFuncName(fn)
+				for _, f := range _diFrameworks {
+					switch {
+					case f.Providers != nil && f.Providers.MatchString(name):
+						for _, arg := range node.Args {
+							if call, ok := ast.Unparen(arg).(*ast.CallExpr); ok && len(call.Args) > 0 && f.Wrappers != nil {
+								if wrapper, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func); ok && f.Wrappers.MatchString(qualifiedFuncName(wrapper)) {
+									arg = call.Args[0]
+								}
+							}
+							observeFunc(arg)
+						}
+					case f.StructProviders != nil && f.StructProviders.MatchString(name) && len(node.Args) > 0:
+						ptr, ok := pass.TypesInfo.TypeOf(node.Args[0]).(*types.Pointer)
+						if !ok {
+							continue
+						}
+						st, ok := ptr.Elem().Underlying().(*types.Struct)
+						if !ok {
+							continue
+						}
+						names := make(map[string]bool)
+						for _, arg := range node.Args[1:] {
+							if tv, ok := pass.TypesInfo.Types[arg]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+								names[constant.StringVal(tv.Value)] = true
+							}
+						}
+						observeFields(st, names, "" /* optionalTag */)
+					}
+				}
+			}
+			return true
+		})
+	}
+}
+
+// injectedVal returns the given value (or the default value for the type if the value is empty)
+// with the nilability set as given, unless it is already set by an annotation comment.
+func injectedVal(val Val, t types.Type, isNilable bool) Val {
+	if val == EmptyVal {
+		val = (&nilabilitySet{}).checkNilability(t)
+	}
+	if val.IsNilableSet {
+		return val
+	}
+	return val.overriddenBy(Val{IsNilable: isNilable, IsNilableSet: true})
+}
+
+// embedsParamObject returns true if the struct embeds a marker type matching the regex.
+func embedsParamObject(st *types.Struct, marker *regexp.Regexp) bool {
+	for i := 0; i < st.NumFields(); i++ {
+		fld := st.Field(i)
+		if !fld.Embedded() {
+			continue
+		}
+		// Check the marker type both before and after resolving the aliases (e.g., `fx.In` is an
+		// alias of `dig.In`).
+		for _, t := range []types.Type{fld.Type(), types.Unalias(fld.Type())} {
+			var obj *types.TypeName
+			switch t := t.(type) {
+			case *types.Alias:
+				obj = t.Obj()
+			case *types.Named:
+				obj = t.Obj()
+			}
+			if obj != nil && obj.Pkg() != nil && marker.MatchString(obj.Pkg().Path()+"."+obj.Name()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// qualifiedFuncName returns the fully qualified name of the function, i.e., "<pkg path>.<func
+// name>" for functions and "<pkg path>.<type name>.<method name>" for methods.
+func qualifiedFuncName(fn *types.Func) string {
+	if fn.Pkg() == nil {
+		return fn.Name()
+	}
+	if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+		t := recv.Type()
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		if named, ok := types.Unalias(t).(*types.Named); ok {
+			return fn.Pkg().Path() + "." + named.Obj().Name() + "." + fn.Name()
+		}
+	}
+	return fn.Pkg().Path() + "." + fn.Name()
+}
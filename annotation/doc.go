@@ -14,4 +14,35 @@
 
 // Package annotation implements annotation-related structs (site, maps, triggers) and methods. It
 // also implements the annotation analyzer that reads the manually-provided annotations.
+//
+// Annotations are written as `nilable(<targets>)` and `nonnil(<targets>)` in the doc comments of
+// declarations, where <targets> is a comma-separated list of:
+//
+//   - the name of a parameter, result, receiver, field or global variable (e.g., `x`);
+//   - `param <i>` or `result <i>` for the i-th (0-based) parameter or result, which also works
+//     for unnamed parameters and results;
+//   - `receiver` for the receiver of a method;
+//   - `<field>.<subfield>` for a field of a struct-typed parameter, result or variable whose
+//     struct type is declared in the current package (e.g., `f.Client`);
+//   - any of the above with a deep marker, referring to the nilability of the elements instead:
+//     `*x` for pointers, `<-x` for channels and `x[]` for slices, arrays and maps.
+//
+// For example:
+//
+//	// nilable(result 0, *opts)
+//	// nonnil(param 1, receiver, cfg.Client)
+//	func (s *Server) Open(cfg *Config, name string, opts **Options) (*File, error)
+//
+// Struct fields can also be annotated by a bare `//nilable` or `//nonnil` comment placed directly
+// on the field, which takes precedence over the docstring of the struct. A field annotated with
+// `//nonnil` is considered "always set after construction": its dereferences are never reported,
+// instead every composite literal of the struct type must initialize it with a nonnil value (see
+// EnforcedNonNilFields).
+//
+// Annotations in a comment on the same line as a call override the annotations of the called
+// function at that call site only; the call site annotations can only be applied to functions
+// declared in the current package. Malformed annotations and annotations
+// referring to unknown targets are reported as "Invalid NilAway annotation" diagnostics. This also
+// applies to the sidecar annotations (see config.SidecarAnnotation) for the current package, which
+// are reported at the annotated objects.
 package annotation
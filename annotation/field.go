@@ -0,0 +1,67 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package annotation
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"go.uber.org/nilaway/config"
+	"golang.org/x/tools/go/analysis"
+)
+
+// EnforcedNonNilFields returns the set of fields of the struct types declared (at the top level)
+// in the current package that are annotated with a field-level `//nonnil` comment, e.g.:
+//
+//	type Server struct {
+//		client *Client //nonnil
+//	}
+//
+// Such fields are "always set after construction": reading them is never reported, instead every
+// composite literal of the struct type must initialize them with a nonnil value.
+func EnforcedNonNilFields(pass *analysis.Pass) map[*types.Var]bool {
+	conf := pass.ResultOf[config.Analyzer].(*config.Config)
+
+	fields := make(map[*types.Var]bool)
+	for _, file := range pass.Files {
+		if !conf.IsFileInScope(file) {
+			continue
+		}
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				st, ok := ast.Unparen(spec.(*ast.TypeSpec).Type).(*ast.StructType)
+				if !ok {
+					continue
+				}
+				for _, field := range st.Fields.List {
+					if nilable, ok := nilabilityOfField(field); !ok || nilable {
+						continue
+					}
+					for _, name := range field.Names {
+						if v, ok := pass.TypesInfo.ObjectOf(name).(*types.Var); ok {
+							fields[v] = true
+						}
+					}
+				}
+			}
+		}
+	}
+	return fields
+}
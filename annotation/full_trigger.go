@@ -17,7 +17,9 @@ package annotation
 import (
 	"fmt"
 	"go/token"
+	"slices"
 
+	"go.uber.org/nilaway/config"
 	"go.uber.org/nilaway/util"
 	"golang.org/x/tools/go/analysis"
 )
@@ -120,8 +122,15 @@ func (t *FullTrigger) Prestrings(pass *analysis.Pass) (Prestring, Prestring) {
 			Location:  t.truncatedProducerPos(pass),
 		}
 	}
+	// The trivial single-use temporaries are elided from the assignment flow of the consumer
+	// unless the full flows are requested.
+	consumer := t.Consumer.Annotation
+	if conf, ok := pass.ResultOf[config.Analyzer].(*config.Config); !ok || conf.AssignmentFlows != config.AssignmentFlowsFull {
+		consumer = consumer.Copy()
+		consumer.elideTrivialAssignments()
+	}
 	consumerPrestring := LocatedPrestring{
-		Contained: t.Consumer.Annotation.Prestring(),
+		Contained: consumer.Prestring(),
 		Location:  t.truncatedConsumerPos(pass),
 	}
 	return producerPrestring, consumerPrestring
@@ -147,10 +156,11 @@ func FullTriggerSlicesEq(left, right []FullTrigger) bool {
 
 	// because we have two sets of the same size, without repetition, to test equality it suffices
 	// to check that one of them contains the other
+	rightIndex := indexFullTriggers(right)
 	matched := make(map[int]bool)
-	for _, l := range left {
-		for j, r := range right {
-			if l.equals(r) {
+	for i := range left {
+		for _, j := range rightIndex[keyOfFullTrigger(&left[i])] {
+			if left[i].equals(right[j]) {
 				matched[j] = true
 				break
 			}
@@ -169,12 +179,15 @@ func FullTriggerSlicesEq(left, right []FullTrigger) bool {
 // checking fixed point in propagation, the function FullTriggersEq
 // that does observe GuardMatched should be used instead of this function.
 func MergeFullTriggers(left []FullTrigger, right ...FullTrigger) []FullTrigger {
-	var out []FullTrigger
+	// Pre-size the output to avoid repeated growth, while keeping the result nil if both inputs are empty.
+	out := slices.Grow([]FullTrigger(nil), len(left)+len(right))
 	updateLeftGuard := make(map[int]bool)
 	skipRight := make(map[int]bool)
 
-	for i, l := range left {
-		for j, r := range right {
+	rightIndex := indexFullTriggers(right)
+	for i := range left {
+		for _, j := range rightIndex[keyOfFullTrigger(&left[i])] {
+			l, r := left[i], right[j]
 			if !l.equalsModuloGuardMatched(r) {
 				continue
 			}
@@ -65,6 +65,19 @@ func newMockKey() *mockKey {
 	return mockedKey
 }
 
+// mockPluginTrigger is a minimal implementation of the PluginTrigger interface, equal to all the
+// other mockPluginTriggers.
+type mockPluginTrigger struct{}
+
+func (*mockPluginTrigger) Equals(other PluginTrigger) bool {
+	_, ok := other.(*mockPluginTrigger)
+	return ok
+}
+
+func (*mockPluginTrigger) Prestring() Prestring {
+	return ConstNilPrestring{}
+}
+
 // mockProducingAnnotationTrigger is a mock implementation of the ProducingAnnotationTrigger interface
 type mockProducingAnnotationTrigger struct {
 	mock.Mock
@@ -385,6 +385,197 @@ func RetKeyFromRetNum(fdecl *types.Func, retNum int) *RetAnnotationKey {
 	}
 }
 
+// CallbackParamAnnotationKey allows the Lookup of the Annotation on a parameter of a callback, i.e.,
+// the `CallbackParamNum`-th parameter of the function type of the `ParamNum`-th parameter of a
+// function (e.g., `x` in `func Walk(cb func(x *T))`). It represents the values the function passes
+// to its callback, which flow into the parameters of the functions passed as the callback by the
+// callers. If FieldDecl is set, the key is instead on a parameter of the function type of the
+// struct field (e.g., `e` in `onEvent func(e *Event)`), representing the values passed to the
+// callbacks stored in the field.
+//
+// TODO: Add support for callback parameters with no inference (Currently, only works with inference)
+type CallbackParamAnnotationKey struct {
+	FuncDecl         *types.Func
+	ParamNum         int
+	FieldDecl        *types.Var
+	CallbackParamNum int
+}
+
+// NewCallbackParamKey returns a new instance of CallbackParamAnnotationKey, where the argument
+// number is "rounded down" to the variadic parameter for variadic callbacks.
+func NewCallbackParamKey(fdecl *types.Func, paramNum int, num int) *CallbackParamAnnotationKey {
+	return roundDownCallbackParamKey(&CallbackParamAnnotationKey{
+		FuncDecl:         fdecl,
+		ParamNum:         paramNum,
+		CallbackParamNum: num,
+	})
+}
+
+// NewFieldCallbackParamKey returns a new instance of CallbackParamAnnotationKey on the parameter
+// of the function-typed struct field, where the argument number is "rounded down" to the variadic
+// parameter for variadic callbacks.
+func NewFieldCallbackParamKey(field *types.Var, num int) *CallbackParamAnnotationKey {
+	return roundDownCallbackParamKey(&CallbackParamAnnotationKey{
+		FieldDecl:        field,
+		CallbackParamNum: num,
+	})
+}
+
+func roundDownCallbackParamKey(ck *CallbackParamAnnotationKey) *CallbackParamAnnotationKey {
+	if sig := ck.Signature(); sig.Variadic() && ck.CallbackParamNum >= sig.Params().Len()-1 {
+		ck.CallbackParamNum = sig.Params().Len() - 1
+	}
+	return ck
+}
+
+// CallbackSignature returns the signature of the function-typed `paramNum`-th parameter of the
+// function, or nil if the parameter is not of function type.
+// nilable(result 0)
+func CallbackSignature(fdecl *types.Func, paramNum int) *types.Signature {
+	params := fdecl.Type().(*types.Signature).Params()
+	if paramNum >= params.Len() {
+		return nil
+	}
+	sig, _ := params.At(paramNum).Type().Underlying().(*types.Signature)
+	return sig
+}
+
+// FieldCallbackSignature returns the signature of the function-typed struct field, or nil if the
+// field is not of function type.
+// nilable(result 0)
+func FieldCallbackSignature(field *types.Var) *types.Signature {
+	sig, _ := field.Type().Underlying().(*types.Signature)
+	return sig
+}
+
+// Signature returns the signature of the callback this key is on.
+func (ck *CallbackParamAnnotationKey) Signature() *types.Signature {
+	if ck.FieldDecl != nil {
+		return FieldCallbackSignature(ck.FieldDecl)
+	}
+	return CallbackSignature(ck.FuncDecl, ck.ParamNum)
+}
+
+// CallbackName returns the name of the function-typed parameter of the function (or the
+// function-typed field) this key is on.
+func (ck *CallbackParamAnnotationKey) CallbackName() string {
+	if ck.FieldDecl != nil {
+		return ck.FieldDecl.Name()
+	}
+	return fdeclParamName(ck.FuncDecl, ck.ParamNum)
+}
+
+// Lookup looks this key up in the passed map, returning a Val
+func (ck *CallbackParamAnnotationKey) Lookup(_ Map) (Val, bool) {
+	return nonAnnotatedDefault, false
+}
+
+// Object returns the types.Object that this annotation can best be interpreted as annotating
+func (ck *CallbackParamAnnotationKey) Object() types.Object {
+	if ck.FieldDecl != nil {
+		return ck.FieldDecl
+	}
+	return ck.FuncDecl
+}
+
+// equals returns true if the passed key is equal to this key
+func (ck *CallbackParamAnnotationKey) equals(other Key) bool {
+	if other, ok := other.(*CallbackParamAnnotationKey); ok {
+		return *ck == *other
+	}
+	return false
+}
+
+func (ck *CallbackParamAnnotationKey) copy() Key {
+	copyKey := *ck
+	return &copyKey
+}
+
+func (ck *CallbackParamAnnotationKey) String() string {
+	if ck.FieldDecl != nil {
+		return fmt.Sprintf("Param %d of Callback Field %s", ck.CallbackParamNum, ck.FieldDecl.Name())
+	}
+	return fmt.Sprintf("Param %d of Callback %d of Function %s",
+		ck.CallbackParamNum, ck.ParamNum, ck.FuncDecl.Name())
+}
+
+// MinimalString returns a string representation for this CallbackParamAnnotationKey consisting
+// only of the word "arg" followed by the name of the callback parameter, if named, or its
+// position otherwise.
+func (ck *CallbackParamAnnotationKey) MinimalString() string {
+	if name := ck.Signature().Params().At(ck.CallbackParamNum).Name(); name != "" {
+		return fmt.Sprintf("arg `%s`", name)
+	}
+	return fmt.Sprintf("arg %d", ck.CallbackParamNum)
+}
+
+// CallbackRetAnnotationKey allows the Lookup of the Annotation on a result of a callback, i.e.,
+// the `RetNum`-th result of the function type of the `ParamNum`-th parameter of a function (e.g.,
+// the `*T` in `func Walk(cb func() *T)`). It represents the values returned to the function by its
+// callback, which flow from the results of the functions passed as the callback by the callers.
+// If FieldDecl is set, the key is instead on a result of the function type of the struct field,
+// representing the values returned by the callbacks stored in the field.
+//
+// TODO: Add support for callback results with no inference (Currently, only works with inference)
+type CallbackRetAnnotationKey struct {
+	FuncDecl  *types.Func
+	ParamNum  int
+	FieldDecl *types.Var
+	RetNum    int
+}
+
+// CallbackName returns the name of the function-typed parameter of the function (or the
+// function-typed field) this key is on.
+func (ck *CallbackRetAnnotationKey) CallbackName() string {
+	if ck.FieldDecl != nil {
+		return ck.FieldDecl.Name()
+	}
+	return fdeclParamName(ck.FuncDecl, ck.ParamNum)
+}
+
+// Lookup looks this key up in the passed map, returning a Val
+func (ck *CallbackRetAnnotationKey) Lookup(_ Map) (Val, bool) {
+	return nonAnnotatedDefault, false
+}
+
+// Object returns the types.Object that this annotation can best be interpreted as annotating
+func (ck *CallbackRetAnnotationKey) Object() types.Object {
+	if ck.FieldDecl != nil {
+		return ck.FieldDecl
+	}
+	return ck.FuncDecl
+}
+
+// equals returns true if the passed key is equal to this key
+func (ck *CallbackRetAnnotationKey) equals(other Key) bool {
+	if other, ok := other.(*CallbackRetAnnotationKey); ok {
+		return *ck == *other
+	}
+	return false
+}
+
+func (ck *CallbackRetAnnotationKey) copy() Key {
+	copyKey := *ck
+	return &copyKey
+}
+
+func (ck *CallbackRetAnnotationKey) String() string {
+	if ck.FieldDecl != nil {
+		return fmt.Sprintf("Result %d of Callback Field %s", ck.RetNum, ck.FieldDecl.Name())
+	}
+	return fmt.Sprintf("Result %d of Callback %d of Function %s",
+		ck.RetNum, ck.ParamNum, ck.FuncDecl.Name())
+}
+
+// fdeclParamName returns the name of the `paramNum`-th parameter of the function, if named, or a
+// placeholder string otherwise.
+func fdeclParamName(fdecl *types.Func, paramNum int) string {
+	if name := fdecl.Type().(*types.Signature).Params().At(paramNum).Name(); name != "" {
+		return name
+	}
+	return fmt.Sprintf("<unnamed param %d>", paramNum)
+}
+
 // TypeNameAnnotationKey allows the Lookup of a named type annotations in the Annotation Map
 type TypeNameAnnotationKey struct {
 	TypeDecl *types.TypeName
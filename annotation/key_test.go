@@ -29,6 +29,8 @@ var initStructsKey = []any{
 	&ParamAnnotationKey{},
 	&CallSiteRetAnnotationKey{},
 	&RetAnnotationKey{},
+	&CallbackParamAnnotationKey{},
+	&CallbackRetAnnotationKey{},
 	&TypeNameAnnotationKey{},
 	&GlobalVarAnnotationKey{},
 	&RecvAnnotationKey{},
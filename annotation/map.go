@@ -19,7 +19,6 @@ import (
 	"go/ast"
 	"go/token"
 	"go/types"
-	"regexp"
 	"strings"
 
 	"go.uber.org/nilaway/config"
@@ -127,6 +126,18 @@ func (a Val) makeDeepNonNil(isFinalVal bool) Val {
 	}
 }
 
+// overriddenBy returns a copy of the Val with the nilabilities that are set in the other Val
+// taking precedence.
+func (a Val) overriddenBy(other Val) Val {
+	if other.IsNilableSet {
+		a.IsNilable, a.IsNilableSet = other.IsNilable, true
+	}
+	if other.IsDeepNilableSet {
+		a.IsDeepNilable, a.IsDeepNilableSet = other.IsDeepNilable, true
+	}
+	return a
+}
+
 // A ObservedMap represents a completed set of annotations read from a file or set of files,
 // it can be checked against an assertionTree using RootAssertionNode.ReportErrors
 //
@@ -163,6 +174,21 @@ type ObservedMap struct {
 	// funcCallSiteRetAnnMap maps a function call site to a slice with the annotations of its
 	// duplicated returns at the call site.
 	funcCallSiteRetAnnMap map[CallSite][]Val
+
+	// diagnostics stores the problems (e.g., syntax errors and unknown targets) found in the
+	// annotation comments.
+	diagnostics []analysis.Diagnostic
+}
+
+// Diagnostics returns the diagnostics for the problems found in the annotation comments.
+func (m *ObservedMap) Diagnostics() []analysis.Diagnostic {
+	return m.diagnostics
+}
+
+// IsFieldAnnotated returns true if the nilability of the field is explicitly set by an annotation
+// (rather than by the defaults).
+func (m *ObservedMap) IsFieldAnnotated(fld *types.Var) bool {
+	return m.fieldAnnMap[fld].IsNilableSet
 }
 
 // CallSite uniquely identifies a function call. It contains the called function object and the
@@ -241,112 +267,6 @@ var (
 	nonAnnotatedDefault = EmptyVal
 )
 
-const nilableKeyword = "nilable"
-const nonNilKeyword = "nonnil"
-
-var annotationKeyword = fmt.Sprintf("(%s|%s)", nilableKeyword, nonNilKeyword)
-
-const sep = ","
-const identRegexStr = "[a-zA-Z][a-zA-Z0-9]*"
-
-const paramTemplateStr = "param %s"
-
-var paramRegexStr = fmt.Sprintf(paramTemplateStr, "[0-9]+")
-
-const resultTemplateStr = "result %s"
-
-var resultRegexStr = fmt.Sprintf(resultTemplateStr, "[0-9]+")
-
-var tokenRegexStr = fmt.Sprintf("((%s)|(%s)|(%s))",
-	identRegexStr, paramRegexStr, resultRegexStr)
-
-func paramStr(i int) string {
-	return fmt.Sprintf(paramTemplateStr, fmt.Sprintf("%d", i))
-}
-
-func resultStr(i int) string {
-	return fmt.Sprintf(resultTemplateStr, fmt.Sprintf("%d", i))
-}
-
-var deepIdentRegexStr = fmt.Sprintf("((\\*%s)|(%s\\[\\])|(<-%s)|%s)",
-	tokenRegexStr, tokenRegexStr, tokenRegexStr, tokenRegexStr)
-var seqRegexStr = fmt.Sprintf("%s\\((\\s*%s\\s*(%s\\s*%s\\s*)*)\\)",
-	annotationKeyword, deepIdentRegexStr, sep, deepIdentRegexStr)
-var seqRegex = regexp.MustCompile(seqRegexStr)
-
-type nilabilitySet map[string]Val
-
-// from a CommentGroup return a nilabilitySet of which identifiers are known annotated nilable
-func nilabilityFromCommentGroup(group *ast.CommentGroup) nilabilitySet {
-	set := make(nilabilitySet)
-	// in each of the following utility functions, isFinalVal=true because literally read annotations
-	// are considered final
-	markNilable := func(s string) {
-		if v, ok := set[s]; ok {
-			set[s] = v.makeNilable(true)
-		} else {
-			set[s] = EmptyVal.makeNilable(true)
-		}
-	}
-	markDeepNilable := func(s string) {
-		if v, ok := set[s]; ok {
-			set[s] = v.makeDeepNilable(true)
-		} else {
-			set[s] = EmptyVal.makeDeepNilable(true)
-		}
-	}
-	markNonNil := func(s string) {
-		if v, ok := set[s]; ok {
-			set[s] = v.makeNonNil(true)
-		} else {
-			set[s] = EmptyVal.makeNonNil(true)
-		}
-	}
-	markDeepNonNil := func(s string) {
-		if v, ok := set[s]; ok {
-			set[s] = v.makeDeepNonNil(true)
-		} else {
-			set[s] = EmptyVal.makeDeepNonNil(true)
-		}
-	}
-
-	if group != nil {
-		for _, comment := range group.List {
-			for _, seqMatch := range seqRegex.FindAllStringSubmatch(comment.Text, -1) {
-
-				deepFunc, shallowFunc := markDeepNonNil, markNonNil
-				if seqMatch[1] == nilableKeyword {
-					deepFunc, shallowFunc = markDeepNilable, markNilable
-				}
-
-				for _, match := range strings.Split(seqMatch[2], sep) {
-					match = strings.TrimSpace(match)
-					n := len(match)
-
-					if n >= 2 && match[0] == '*' {
-						deepFunc(match[1:])
-						continue
-					}
-
-					if n >= 3 && match[n-2:] == "[]" {
-						deepFunc(match[:n-2])
-						continue
-					}
-
-					if n >= 3 && match[:2] == "<-" {
-						deepFunc(match[2:])
-						continue
-					}
-
-					shallowFunc(match)
-				}
-			}
-		}
-	}
-
-	return set
-}
-
 // TypeIsDefaultNilable takes a type and returns true iff we assume default nilability for that
 // type - in contrast to the remaining cases, in which we assume default non-nil.
 func TypeIsDefaultNilable(t types.Type) bool {
@@ -397,13 +317,13 @@ func TypeIsDeepDefaultNilable(t types.Type) bool {
 	return false
 }
 
-// checkNilability for a nilabilitySet checks to see if a string is mapped to an Annotation by that
-// set. If it is, then that Annotation is returned. If not, then `nonNil` is returned.
-// the type of the Annotation site is also passed, and it can possibly serve to mark a site
-// as `nilable` when its Annotation doesn't indicate so.
-func (set nilabilitySet) checkNilability(name string, t types.Type) Val {
+// checkNilability for a nilabilitySet checks to see if any of the names (in order) is mapped to an
+// Annotation by that set. If it is, then that Annotation is returned. If not, then `nonNil` is
+// returned. the type of the Annotation site is also passed, and it can possibly serve to mark a
+// site as `nilable` when its Annotation doesn't indicate so.
+func (set *nilabilitySet) checkNilability(t types.Type, names ...string) Val {
 	val := EmptyVal
-	if v, ok := set[name]; ok {
+	if v, ok := set.lookup(names...); ok {
 		val = v
 	}
 	// in each of the following cases, isFinalVal=false because defaults are not considered final
@@ -438,28 +358,25 @@ func newObservedMap(pass *analysis.Pass, files []*ast.File) *ObservedMap {
 
 	// for a function declaration, accumulate its parameters from an *ast.Fieldlist object
 	// listing them, look them up in the docstring, and return an equally long list of
-	// annotationVals
-	accFromFieldList := func(set nilabilitySet, fieldList *ast.FieldList, isParamList bool,
-		isCallSiteAnnotation bool) []Val {
+	// annotationVals. Named parameters (results) can be referred to by their names or indices
+	// (e.g., `param 1`), whereas unnamed ones can only be referred to by their indices.
+	accFromFieldList := func(set *nilabilitySet, fieldList *ast.FieldList, isParamList bool) []Val {
 		if fieldList == nil {
 			// this is included for nil-safety
 			return nil
 		}
 
+		indexKey := resultStr
+		if isParamList {
+			indexKey = paramStr
+		}
+
 		var annVals []Val
-		var lookupKey string
 		for _, field := range fieldList.List {
 			if len(field.Names) == 0 {
 				// case of anonymous field - on which we do not permit annotations
 				// non-named fields
-
-				if isParamList {
-					lookupKey = paramStr(len(annVals))
-				} else {
-					lookupKey = resultStr(len(annVals))
-				}
-
-				annVals = append(annVals, set.checkNilability(lookupKey, typeOf(field.Type)))
+				annVals = append(annVals, set.checkNilability(typeOf(field.Type), indexKey(len(annVals))))
 			} else {
 				for _, name := range field.Names {
 					declFld := pass.TypesInfo.ObjectOf(name).(*types.Var)
@@ -477,32 +394,81 @@ func newObservedMap(pass *analysis.Pass, files []*ast.File) *ObservedMap {
 						fieldType = typeOf(field.Type)
 					}
 
-					if isCallSiteAnnotation {
-						if isParamList {
-							lookupKey = paramStr(len(annVals))
-						} else {
-							lookupKey = resultStr(len(annVals))
-						}
-					} else {
-						lookupKey = name.Name
-					}
-					annVals = append(annVals, set.checkNilability(lookupKey, fieldType))
+					annVals = append(annVals, set.checkNilability(fieldType, name.Name, indexKey(len(annVals))))
 				}
 			}
 		}
 		return annVals
 	}
 
-	readRecvAnnotations := func(decl *ast.FuncDecl, set nilabilitySet) Val {
+	// the receiver can be referred to by its name or the `receiver` keyword
+	readRecvAnnotations := func(decl *ast.FuncDecl, set *nilabilitySet) Val {
 		if decl.Recv != nil {
 			if len(decl.Recv.List) > 1 {
 				panic(fmt.Sprintf("Multiple receivers found for method %s", decl.Name))
 			}
-			return accFromFieldList(set, decl.Recv, false, false)[0]
+			field := decl.Recv.List[0]
+			name := ""
+			if len(field.Names) > 0 {
+				name = field.Names[0].Name
+			}
+			return set.checkNilability(typeOf(field.Type), name, receiverTarget)
 		}
 		return nonAnnotatedDefault
 	}
 
+	// field-granular targets (e.g., `f.Client` in the docstring of a function with a parameter
+	// `f`) annotate the fields of the struct types declared in this package, they are applied
+	// after all declarations have been read such that they take precedence
+	pendingFieldAnns := make(map[*types.Var]Val)
+	readFieldTargets := func(set *nilabilitySet, vars map[string]types.Type) {
+		for _, target := range set.targets(func(name string) bool { return strings.Contains(name, ".") }) {
+			parts := strings.Split(target, ".")
+			t, ok := vars[parts[0]]
+			if !ok {
+				continue
+			}
+			var fld *types.Var
+			for _, part := range parts[1:] {
+				fld = nil
+				named, ok := types.Unalias(util.UnwrapPtr(t)).(*types.Named)
+				if !ok || named.Obj().Pkg() != pass.Pkg {
+					break
+				}
+				st, ok := named.Underlying().(*types.Struct)
+				if !ok {
+					break
+				}
+				for i := 0; i < st.NumFields(); i++ {
+					if st.Field(i).Name() == part {
+						fld = st.Field(i)
+					}
+				}
+				if fld == nil {
+					break
+				}
+				t = fld.Type()
+			}
+			if fld == nil {
+				// leave the target unused such that it is reported as unknown
+				continue
+			}
+			val, _ := set.lookup(target)
+			pendingFieldAnns[fld] = pendingFieldAnns[fld].overriddenBy(val)
+		}
+	}
+
+	// the problems in the annotation comments are reported as diagnostics
+	var diagnostics []analysis.Diagnostic
+	reportProblems := func(set *nilabilitySet) {
+		for _, problem := range set.problems() {
+			diagnostics = append(diagnostics, analysis.Diagnostic{
+				Pos:     set.pos,
+				Message: "Invalid NilAway annotation: " + problem,
+			})
+		}
+	}
+
 	for _, file := range files {
 		if conf.IsFileInScope(file) {
 			for _, decl := range file.Decls {
@@ -510,9 +476,22 @@ func newObservedMap(pass *analysis.Pass, files []*ast.File) *ObservedMap {
 				case *ast.FuncDecl:
 					funcObj := pass.TypesInfo.ObjectOf(decl.Name).(*types.Func)
 					set := nilabilityFromCommentGroup(decl.Doc)
-					funcParamAnnMap[funcObj] = accFromFieldList(set, decl.Type.Params, true, false)
-					funcRetAnnMap[funcObj] = accFromFieldList(set, decl.Type.Results, false, false)
+					funcParamAnnMap[funcObj] = accFromFieldList(set, decl.Type.Params, true)
+					funcRetAnnMap[funcObj] = accFromFieldList(set, decl.Type.Results, false)
 					funcRecvAnnMap[funcObj] = readRecvAnnotations(decl, set)
+					sig := funcObj.Type().(*types.Signature)
+					vars := make(map[string]types.Type)
+					for _, tuple := range [...]*types.Tuple{sig.Params(), sig.Results()} {
+						for i := 0; i < tuple.Len(); i++ {
+							vars[tuple.At(i).Name()] = tuple.At(i).Type()
+						}
+					}
+					if recv := sig.Recv(); recv != nil {
+						vars[recv.Name()] = recv.Type()
+						vars[receiverTarget] = recv.Type()
+					}
+					readFieldTargets(set, vars)
+					reportProblems(set)
 					// store the mapping from the function object to the ast node.
 					funcObjToFuncDecl[funcObj] = decl
 				case *ast.GenDecl:
@@ -522,7 +501,7 @@ func newObservedMap(pass *analysis.Pass, files []*ast.File) *ObservedMap {
 					// this set will contain the nilability annotations read from the appropriate
 					// docstring (this takes into account the syntax option to group declarations -
 					// in which a single keyword may be used to declare a group)
-					readDocNilabilitySet := func(specDoc *ast.CommentGroup) nilabilitySet {
+					readDocNilabilitySet := func(specDoc *ast.CommentGroup) *nilabilitySet {
 						if len(decl.Specs) == 1 {
 							// this reads declarations like type A struct {}
 							return nilabilityFromCommentGroup(decl.Doc)
@@ -542,8 +521,9 @@ func newObservedMap(pass *analysis.Pass, files []*ast.File) *ObservedMap {
 								for _, name := range spec.Names {
 									varObj := pass.TypesInfo.ObjectOf(name).(*types.Var)
 									globalVarsAnnMap[varObj] =
-										docNilabilitySet.checkNilability(name.Name, typeOf(spec.Type))
+										docNilabilitySet.checkNilability(typeOf(spec.Type), name.Name)
 								}
+								reportProblems(docNilabilitySet)
 							}
 						case *ast.TypeSpec:
 							// we've found a declaration for a `type`
@@ -555,16 +535,24 @@ func newObservedMap(pass *analysis.Pass, files []*ast.File) *ObservedMap {
 							readDeepNilability := func() {
 								typeName := pass.TypesInfo.ObjectOf(spec.Name).(*types.TypeName)
 								deepTypeAnnMap[typeName] =
-									docNilabilitySet.checkNilability(spec.Name.Name, typeOf(spec.Type))
+									docNilabilitySet.checkNilability(typeOf(spec.Type), spec.Name.Name)
 							}
 							var handleTypeVal func(expr ast.Expr)
 							handleTypeVal = func(expr ast.Expr) {
 								switch typeVal := expr.(type) {
 								case *ast.StructType:
 									for _, field := range typeVal.Fields.List {
+										// the annotation comments placed directly on the field take
+										// precedence over the ones in the docstring of the struct
+										fieldVal := EmptyVal
+										if nilable, ok := nilabilityOfField(field); ok && nilable {
+											fieldVal = fieldVal.makeNilable(true)
+										} else if ok {
+											fieldVal = fieldVal.makeNonNil(true)
+										}
 										for _, name := range field.Names {
 											fieldAnnMap[pass.TypesInfo.ObjectOf(name).(*types.Var)] =
-												docNilabilitySet.checkNilability(name.Name, typeOf(field.Type))
+												docNilabilitySet.checkNilability(typeOf(field.Type), name.Name).overriddenBy(fieldVal)
 										}
 									}
 								case *ast.InterfaceType:
@@ -575,8 +563,9 @@ func newObservedMap(pass *analysis.Pass, files []*ast.File) *ObservedMap {
 											// this is the common case - a simply declared method
 											set := nilabilityFromCommentGroup(method.Doc)
 											funcObj := pass.TypesInfo.ObjectOf(method.Names[0]).(*types.Func)
-											funcParamAnnMap[funcObj] = accFromFieldList(set, method.Type.(*ast.FuncType).Params, true, false)
-											funcRetAnnMap[funcObj] = accFromFieldList(set, method.Type.(*ast.FuncType).Results, false, false)
+											funcParamAnnMap[funcObj] = accFromFieldList(set, method.Type.(*ast.FuncType).Params, true)
+											funcRetAnnMap[funcObj] = accFromFieldList(set, method.Type.(*ast.FuncType).Results, false)
+											reportProblems(set)
 										case 0:
 										// this is the case of inheritance - i.e. a method with another
 										// method named within it, in this case the identifiers will
@@ -607,6 +596,7 @@ func newObservedMap(pass *analysis.Pass, files []*ast.File) *ObservedMap {
 								}
 							}
 							handleTypeVal(spec.Type)
+							reportProblems(docNilabilitySet)
 						case *ast.ImportSpec: // do nothing - we don't care about these for annotations' sake
 						default:
 							panic(fmt.Sprintf("error - unrecognized spec: %T", spec))
@@ -617,7 +607,10 @@ func newObservedMap(pass *analysis.Pass, files []*ast.File) *ObservedMap {
 		}
 	}
 
-	// Parse inline annotations at call sites.
+	// Parse inline annotations at call sites. The comments are matched by lines, hence the same
+	// comment could annotate multiple calls on the same line.
+	callSiteSetIndices := make(map[*ast.CommentGroup]int)
+	var callSiteSets []*nilabilitySet
 	for _, file := range files {
 		if !conf.IsFileInScope(file) {
 			continue
@@ -659,29 +652,79 @@ func newObservedMap(pass *analysis.Pass, files []*ast.File) *ObservedMap {
 				return true
 			}
 
-			set := nilabilityFromCommentGroup(commentGroup)
-			if len(set) == 0 {
+			index, ok := callSiteSetIndices[commentGroup]
+			if !ok {
+				index = len(callSiteSets)
+				callSiteSetIndices[commentGroup] = index
+				callSiteSets = append(callSiteSets, nilabilityFromCommentGroup(commentGroup))
+			}
+			set := callSiteSets[index]
+			if len(set.vals) == 0 {
 				// empty set, no annotation, keep searching for nested CallExpr nodes.
 				return true
 			}
 			funcDecl, ok := funcObjToFuncDecl[funcObj]
 			if !ok {
-				panic(funcObj.FullName() + " not found but this should not happen since we " +
-					"have parsed annotations once for every function declaration and the " +
-					"mappings should have been set up.")
+				set.errs = append(set.errs, fmt.Sprintf("call site annotations are only supported "+
+					"for functions declared in the current package, got %q", funcObj.FullName()))
+				// the targets are not reported as unknown on top of the error above
+				set.lookup(set.targets(func(string) bool { return true })...)
+				return true
 			}
 			callSite := CallSite{Fun: funcObj, Location: util.PosToLocation(expr.Pos(), pass)}
-			for i, val := range accFromFieldList(set, funcDecl.Type.Params, true, true) {
+			for i, val := range accFromFieldList(set, funcDecl.Type.Params, true) {
 				argLoc := util.PosToLocation(expr.Args[i].Pos(), pass)
 				funcCallSiteParamAnnMap[callSite] = append(funcCallSiteParamAnnMap[callSite],
 					ArgLocAndVal{Location: argLoc, Val: val})
 			}
-			funcCallSiteRetAnnMap[callSite] = accFromFieldList(set, funcDecl.Type.Results, false, true)
+			funcCallSiteRetAnnMap[callSite] = accFromFieldList(set, funcDecl.Type.Results, false)
 			// keep searching for nested CallExpr nodes.
 			return true
 		})
 	}
-	return &ObservedMap{
+	for _, set := range callSiteSets {
+		reportProblems(set)
+	}
+
+	// Apply the field-granular annotations.
+	for fld, val := range pendingFieldAnns {
+		fieldAnnMap[fld] = fieldAnnMap[fld].overriddenBy(val)
+	}
+
+	// The fields, parameters, and results of named types have their own deep nilability (see
+	// util.TypeIsDeepAtSite), which defaults to the deep nilability annotated at the declaration
+	// of the named type, if any.
+	inheritDeepTypeAnn := func(val Val, t types.Type) Val {
+		named, ok := t.(*types.Named)
+		if !ok || val.IsDeepNilableSet {
+			return val
+		}
+		if typeVal, ok := deepTypeAnnMap[named.Obj()]; ok && typeVal.IsDeepNilableSet {
+			val.IsDeepNilable, val.IsDeepNilableSet = typeVal.IsDeepNilable, true
+		}
+		return val
+	}
+	for fld, val := range fieldAnnMap {
+		fieldAnnMap[fld] = inheritDeepTypeAnn(val, fld.Type())
+	}
+	for funcObj, vals := range funcParamAnnMap {
+		params := funcObj.Type().(*types.Signature).Params()
+		for i := range vals {
+			if i < params.Len() {
+				vals[i] = inheritDeepTypeAnn(vals[i], params.At(i).Type())
+			}
+		}
+	}
+	for funcObj, vals := range funcRetAnnMap {
+		results := funcObj.Type().(*types.Signature).Results()
+		for i := range vals {
+			if i < results.Len() {
+				vals[i] = inheritDeepTypeAnn(vals[i], results.At(i).Type())
+			}
+		}
+	}
+
+	m := &ObservedMap{
 		fieldAnnMap:             fieldAnnMap,
 		funcParamAnnMap:         funcParamAnnMap,
 		funcRetAnnMap:           funcRetAnnMap,
@@ -690,7 +733,11 @@ func newObservedMap(pass *analysis.Pass, files []*ast.File) *ObservedMap {
 		globalVarsAnnMap:        globalVarsAnnMap,
 		funcCallSiteParamAnnMap: funcCallSiteParamAnnMap,
 		funcCallSiteRetAnnMap:   funcCallSiteRetAnnMap,
+		diagnostics:             diagnostics,
 	}
+	m.observeDIInjections(pass, files)
+	m.observeSidecarAnnotations(pass, conf.SidecarAnnotations)
+	return m
 }
 
 func getLineFromPos(pos token.Pos, pass *analysis.Pass) int {
@@ -0,0 +1,125 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package annotation
+
+import (
+	"go/token"
+	"strings"
+)
+
+// PluginTrigger describes a kind of triggers contributed by a plugin (see hook.TriggerPlugin),
+// e.g., the values passed to an audit sink, which must be nonnil. The plugins implement their own
+// kinds without editing this package: the trigger deciding when the plugin trigger fires (e.g.,
+// ConsumeTriggerTautology for always) is embedded by PluginConsumer (or PluginProducer), which
+// delegates the equality and the message to the plugin trigger.
+type PluginTrigger interface {
+	// Equals returns true if the passed PluginTrigger is equal to this one, which is only called
+	// for the plugin triggers embedded by the triggers equal otherwise.
+	Equals(PluginTrigger) bool
+	// Prestring returns the compact encoding of the message of the trigger, e.g., "passed to the
+	// audit sink". The Prestring types must be registered along with the plugin (see
+	// hook.TriggerPlugin) for encoding them in the facts.
+	Prestring() Prestring
+}
+
+// PluginConsumer is a ConsumingAnnotationTrigger of a kind contributed by a plugin.
+type PluginConsumer struct {
+	// ConsumingAnnotationTrigger decides when the trigger fires, e.g., &ConsumeTriggerTautology{}
+	// to always require a nonnil value, or &TriggerIfNonNil{Ann: key} to require it only if the
+	// site is nonnil.
+	ConsumingAnnotationTrigger
+	// Plugin is the plugin trigger describing the kind of the trigger.
+	Plugin PluginTrigger
+	assignmentFlow
+}
+
+// equals returns true if the passed ConsumingAnnotationTrigger is equal to this one
+func (p *PluginConsumer) equals(other ConsumingAnnotationTrigger) bool {
+	if other, ok := other.(*PluginConsumer); ok {
+		return p.ConsumingAnnotationTrigger.equals(other.ConsumingAnnotationTrigger) &&
+			p.Plugin.Equals(other.Plugin)
+	}
+	return false
+}
+
+// customPos returns the custom position of the embedded trigger.
+func (p *PluginConsumer) customPos() (token.Pos, bool) {
+	return p.ConsumingAnnotationTrigger.customPos()
+}
+
+// Copy returns a deep copy of this ConsumingAnnotationTrigger
+func (p *PluginConsumer) Copy() ConsumingAnnotationTrigger {
+	copyConsumer := *p
+	copyConsumer.ConsumingAnnotationTrigger = p.ConsumingAnnotationTrigger.Copy()
+	copyConsumer.assignmentFlow = p.assignmentFlow.copy()
+	return &copyConsumer
+}
+
+// AddAssignment adds an assignment to the trigger.
+func (p *PluginConsumer) AddAssignment(e Assignment) {
+	p.assignmentFlow.addEntry(e)
+}
+
+// elideTrivialAssignments elides the trivial single-use temporaries from the assignment flow of
+// the trigger when printed.
+func (p *PluginConsumer) elideTrivialAssignments() {
+	p.assignmentFlow.elideTrivialAssignments()
+}
+
+// Prestring returns this PluginConsumer as a Prestring
+func (p *PluginConsumer) Prestring() Prestring {
+	return PluginPrestring{
+		Contained:     p.Plugin.Prestring(),
+		AssignmentStr: p.assignmentFlow.String(),
+	}
+}
+
+// PluginProducer is a ProducingAnnotationTrigger of a kind contributed by a plugin.
+type PluginProducer struct {
+	// ProducingAnnotationTrigger decides when the trigger fires, e.g., &ProduceTriggerTautology{}
+	// to always produce a nilable value, or &TriggerIfNilable{Ann: key} to produce it only if the
+	// site is nilable.
+	ProducingAnnotationTrigger
+	// Plugin is the plugin trigger describing the kind of the trigger.
+	Plugin PluginTrigger
+}
+
+// equals returns true if the passed ProducingAnnotationTrigger is equal to this one
+func (p *PluginProducer) equals(other ProducingAnnotationTrigger) bool {
+	if other, ok := other.(*PluginProducer); ok {
+		return p.ProducingAnnotationTrigger.equals(other.ProducingAnnotationTrigger) &&
+			p.Plugin.Equals(other.Plugin)
+	}
+	return false
+}
+
+// Prestring returns this PluginProducer as a Prestring
+func (p *PluginProducer) Prestring() Prestring {
+	return PluginPrestring{Contained: p.Plugin.Prestring()}
+}
+
+// PluginPrestring is a Prestring storing the needed information to compactly encode a
+// PluginConsumer or a PluginProducer, where the Prestring of the plugin trigger is contained.
+type PluginPrestring struct {
+	Contained     Prestring
+	AssignmentStr string
+}
+
+func (p PluginPrestring) String() string {
+	var sb strings.Builder
+	sb.WriteString(p.Contained.String())
+	sb.WriteString(p.AssignmentStr)
+	return sb.String()
+}
@@ -380,26 +380,52 @@ func (ConstNilPrestring) String() string {
 // UnassignedFld is when a field of struct is not assigned at initialization
 type UnassignedFld struct {
 	*ProduceTriggerTautology
+	// StructInit is true if the field is tracked by the struct initialization checking (see
+	// config.CheckStructInitFlag), rather than checked at the composite literal itself.
+	StructInit bool
+	// FieldName is the name of the field shown in the message, empty if the consumer names it
+	// already (e.g., for the fields returned by the constructors).
+	FieldName string
+	// StructName is the name of the struct type of the field, shown along with FieldName.
+	StructName string
 }
 
 // equals returns true if the passed ProducingAnnotationTrigger is equal to this one
 func (u *UnassignedFld) equals(other ProducingAnnotationTrigger) bool {
 	if other, ok := other.(*UnassignedFld); ok {
-		return u.ProduceTriggerTautology.equals(other.ProduceTriggerTautology)
+		return u.ProduceTriggerTautology.equals(other.ProduceTriggerTautology) &&
+			u.StructInit == other.StructInit &&
+			u.FieldName == other.FieldName &&
+			u.StructName == other.StructName
 	}
 	return false
 }
 
 // Prestring returns this Prestring as a Prestring
-func (*UnassignedFld) Prestring() Prestring {
-	return UnassignedFldPrestring{}
+func (u *UnassignedFld) Prestring() Prestring {
+	return UnassignedFldPrestring{
+		StructInit: u.StructInit,
+		FieldName:  u.FieldName,
+		StructName: u.StructName,
+	}
 }
 
 // UnassignedFldPrestring is a Prestring storing the needed information to compactly encode a UnassignedFld
-type UnassignedFldPrestring struct{}
-
-func (UnassignedFldPrestring) String() string {
-	return "uninitialized"
+type UnassignedFldPrestring struct {
+	StructInit bool
+	FieldName  string
+	StructName string
+}
+
+func (u UnassignedFldPrestring) String() string {
+	switch {
+	case u.FieldName == "":
+		return "uninitialized"
+	case u.StructName == "":
+		return fmt.Sprintf("uninitialized field `%s`", u.FieldName)
+	default:
+		return fmt.Sprintf("uninitialized field `%s` of `%s`", u.FieldName, u.StructName)
+	}
 }
 
 // NoVarAssign is when a value is determined to flow from a variable that wasn't assigned to
@@ -432,6 +458,37 @@ func (n NoVarAssignPrestring) String() string {
 	return fmt.Sprintf("unassigned variable `%s`", n.VarName)
 }
 
+// UnassignedArrayElem is when a value is determined to flow from an element of a local array
+// variable that was never assigned to, e.g., `var arr [3]*T; arr[i]`
+type UnassignedArrayElem struct {
+	*ProduceTriggerTautology
+	VarObj *types.Var
+}
+
+// equals returns true if the passed ProducingAnnotationTrigger is equal to this one
+func (u *UnassignedArrayElem) equals(other ProducingAnnotationTrigger) bool {
+	if other, ok := other.(*UnassignedArrayElem); ok {
+		return u.ProduceTriggerTautology.equals(other.ProduceTriggerTautology) && u.VarObj == other.VarObj
+	}
+	return false
+}
+
+// Prestring returns this Prestring as a Prestring
+func (u *UnassignedArrayElem) Prestring() Prestring {
+	return UnassignedArrayElemPrestring{
+		VarName: u.VarObj.Name(),
+	}
+}
+
+// UnassignedArrayElemPrestring is a Prestring storing the needed information to compactly encode a UnassignedArrayElem
+type UnassignedArrayElemPrestring struct {
+	VarName string
+}
+
+func (u UnassignedArrayElemPrestring) String() string {
+	return fmt.Sprintf("element of unassigned array `%s`", u.VarName)
+}
+
 // BlankVarReturn is when a value is determined to flow from a blank variable ('_') to a return of the function
 type BlankVarReturn struct {
 	*ProduceTriggerTautology
@@ -457,14 +514,15 @@ func (BlankVarReturnPrestring) String() string {
 	return "return via a blank variable `_`"
 }
 
-// DuplicateParamProducer duplicates a given produce trigger, assuming the given produce trigger
-// is of FuncParam.
-func DuplicateParamProducer(t *ProduceTrigger, location token.Position) *ProduceTrigger {
+// DuplicateParamProducer duplicates a given produce trigger for the call site of the callee,
+// assuming the given produce trigger is of FuncParam. The callee is the function of the parameter,
+// or an interface method devirtualized to it.
+func DuplicateParamProducer(t *ProduceTrigger, callee *types.Func, location token.Position) *ProduceTrigger {
 	key := t.Annotation.(*FuncParam).TriggerIfNilable.Ann.(*ParamAnnotationKey)
 	return &ProduceTrigger{
 		Annotation: &FuncParam{
 			TriggerIfNilable: &TriggerIfNilable{
-				Ann: NewCallSiteParamKey(key.FuncDecl, key.ParamNum, location)}},
+				Ann: NewCallSiteParamKey(callee, key.ParamNum, location)}},
 		Expr: t.Expr,
 	}
 }
@@ -652,6 +710,32 @@ func (TrustedFuncNonnilPrestring) String() string {
 	return "determined to be nonnil by a trusted function"
 }
 
+// TrustedFuncOkResult is used when a value is a non-ok result of a trusted ok-returning function
+// call (e.g., the value of `sync.Map.Load`), which is nonnil only if guarded by the ok result
+type TrustedFuncOkResult struct {
+	*ProduceTriggerNever
+}
+
+// equals returns true if the passed ProducingAnnotationTrigger is equal to this one
+func (t *TrustedFuncOkResult) equals(other ProducingAnnotationTrigger) bool {
+	if other, ok := other.(*TrustedFuncOkResult); ok {
+		return t.ProduceTriggerNever.equals(other.ProduceTriggerNever)
+	}
+	return false
+}
+
+// Prestring returns this Prestring as a Prestring
+func (*TrustedFuncOkResult) Prestring() Prestring {
+	return TrustedFuncOkResultPrestring{}
+}
+
+// TrustedFuncOkResultPrestring is a Prestring storing the needed information to compactly encode a TrustedFuncOkResult
+type TrustedFuncOkResultPrestring struct{}
+
+func (TrustedFuncOkResultPrestring) String() string {
+	return "result of a trusted ok-returning function"
+}
+
 // FldRead is used when a value is determined to flow from a read to a field
 type FldRead struct {
 	*TriggerIfNilable
@@ -699,18 +783,41 @@ func (f *ParamFldRead) equals(other ProducingAnnotationTrigger) bool {
 // Prestring returns this ParamFldRead as a Prestring
 func (f *ParamFldRead) Prestring() Prestring {
 	ann := f.Ann.(*ParamFieldAnnotationKey)
-	return ParamFldReadPrestring{
-		FieldName: ann.FieldDecl.Name(),
+	p := ParamFldReadPrestring{
+		FieldName:  ann.FieldDecl.Name(),
+		FuncName:   ann.FuncDecl.Name(),
+		IsReceiver: ann.IsReceiver(),
+		SideEffect: ann.IsTrackingSideEffect,
 	}
+	if param := ann.ParamName(); param != nil {
+		p.ParamName = param.Name()
+	}
+	return p
 }
 
 // ParamFldReadPrestring is a Prestring storing the needed information to compactly encode a ParamFldRead
 type ParamFldReadPrestring struct {
-	FieldName string
+	FieldName  string
+	ParamName  string
+	FuncName   string
+	IsReceiver bool
+	// SideEffect is true if the field is read by the caller after the call, i.e., as left by the
+	// function rather than as passed to it.
+	SideEffect bool
 }
 
 func (f ParamFldReadPrestring) String() string {
-	return fmt.Sprintf("field `%s`", f.FieldName)
+	param := "param"
+	if f.IsReceiver {
+		param = "receiver"
+	}
+	if f.ParamName != "" && f.ParamName != "_" {
+		param += fmt.Sprintf(" `%s`", f.ParamName)
+	}
+	if f.SideEffect {
+		return fmt.Sprintf("field `%s` of %s as left by `%s()`", f.FieldName, param, f.FuncName)
+	}
+	return fmt.Sprintf("field `%s` of %s of `%s()`", f.FieldName, param, f.FuncName)
 }
 
 // FldReturn is used when a struct field value is determined to flow from a return value of a function
@@ -901,6 +1008,114 @@ func (i InterfaceParamReachesImplementationPrestring) String() string {
 	return ""
 }
 
+// CallbackParamReachesFunc is used when a param of a callback is determined to flow into the param
+// of a function passed as the callback
+type CallbackParamReachesFunc struct {
+	*TriggerIfNilable
+	CallbackPair
+}
+
+// equals returns true if the passed ProducingAnnotationTrigger is equal to this one
+func (c *CallbackParamReachesFunc) equals(other ProducingAnnotationTrigger) bool {
+	if other, ok := other.(*CallbackParamReachesFunc); ok {
+		return c.TriggerIfNilable.equals(other.TriggerIfNilable) && c.CallbackPair == other.CallbackPair
+	}
+	return false
+}
+
+// Prestring returns this CallbackParamReachesFunc as a Prestring
+func (c *CallbackParamReachesFunc) Prestring() Prestring {
+	key := c.Ann.(*CallbackParamAnnotationKey)
+	funcName := ""
+	if key.FieldDecl == nil {
+		funcName = util.PartiallyQualifiedFuncName(key.FuncDecl)
+	}
+	return CallbackParamReachesFuncPrestring{
+		key.MinimalString(),
+		key.CallbackName(),
+		funcName,
+	}
+}
+
+// CallbackParamReachesFuncPrestring is a Prestring storing the needed information to compactly encode a CallbackParamReachesFunc
+type CallbackParamReachesFuncPrestring struct {
+	ParamName    string
+	CallbackName string
+	// FuncName is empty for the callbacks stored in the struct fields.
+	FuncName string
+}
+
+func (c CallbackParamReachesFuncPrestring) String() string {
+	if c.FuncName == "" {
+		return fmt.Sprintf("%s of callback field `%s`", c.ParamName, c.CallbackName)
+	}
+	return fmt.Sprintf("%s of callback `%s` of `%s()`", c.ParamName, c.CallbackName, c.FuncName)
+}
+
+// FuncResultReachesCallback is used when a result of a function passed as a callback is determined
+// to flow into the result of the callback
+type FuncResultReachesCallback struct {
+	*TriggerIfNilable
+	CallbackPair
+}
+
+// equals returns true if the passed ProducingAnnotationTrigger is equal to this one
+func (f *FuncResultReachesCallback) equals(other ProducingAnnotationTrigger) bool {
+	if other, ok := other.(*FuncResultReachesCallback); ok {
+		return f.TriggerIfNilable.equals(other.TriggerIfNilable) && f.CallbackPair == other.CallbackPair
+	}
+	return false
+}
+
+// Prestring returns this FuncResultReachesCallback as a Prestring
+func (f *FuncResultReachesCallback) Prestring() Prestring {
+	key := f.Ann.(*RetAnnotationKey)
+	return FuncResultReachesCallbackPrestring{
+		key.RetNum,
+		util.PartiallyQualifiedFuncName(key.FuncDecl),
+	}
+}
+
+// FuncResultReachesCallbackPrestring is a Prestring storing the needed information to compactly encode a FuncResultReachesCallback
+type FuncResultReachesCallbackPrestring struct {
+	RetNum   int
+	FuncName string
+}
+
+func (f FuncResultReachesCallbackPrestring) String() string {
+	return fmt.Sprintf("result %d of `%s()`", f.RetNum, f.FuncName)
+}
+
+// CallbackReturn is used when a value is determined to flow from a result of a call to a callback
+// (i.e., a function-typed parameter) of the function
+type CallbackReturn struct {
+	*TriggerIfNilable
+}
+
+// equals returns true if the passed ProducingAnnotationTrigger is equal to this one
+func (c *CallbackReturn) equals(other ProducingAnnotationTrigger) bool {
+	if other, ok := other.(*CallbackReturn); ok {
+		return c.TriggerIfNilable.equals(other.TriggerIfNilable)
+	}
+	return false
+}
+
+// Prestring returns this CallbackReturn as a Prestring
+func (c *CallbackReturn) Prestring() Prestring {
+	key := c.Ann.(*CallbackRetAnnotationKey)
+	return CallbackReturnPrestring{key.RetNum, key.CallbackName()}
+}
+
+// CallbackReturnPrestring is a Prestring storing the needed information to compactly encode a CallbackReturn
+type CallbackReturnPrestring struct {
+	RetNum       int
+	CallbackName string
+}
+
+func (c CallbackReturnPrestring) String() string {
+	return fmt.Sprintf("result %d of callback `%s()`", c.RetNum, c.CallbackName)
+}
+
 // GlobalVarRead is when a value is determined to flow from a read to a global variable
 type GlobalVarRead struct {
 	*TriggerIfNilable
@@ -1281,6 +1496,37 @@ func (g GuardMissingPrestring) String() string {
 	return fmt.Sprintf("%s lacking guarding;", g.OldPrestring.String())
 }
 
+// TrackingSummarized is when a tracked expression is dropped from the assertion tree because the
+// tree grew beyond the configured width limit. Since we no longer know what the expression will
+// be assigned, we conservatively assume that it is nil.
+type TrackingSummarized struct {
+	*ProduceTriggerTautology
+	// Limit is the configured maximum width of the assertion tree.
+	Limit int
+}
+
+// equals returns true if the passed ProducingAnnotationTrigger is equal to this one
+func (t *TrackingSummarized) equals(other ProducingAnnotationTrigger) bool {
+	if other, ok := other.(*TrackingSummarized); ok {
+		return t.ProduceTriggerTautology.equals(other.ProduceTriggerTautology) && t.Limit == other.Limit
+	}
+	return false
+}
+
+// Prestring returns this TrackingSummarized as a Prestring
+func (t *TrackingSummarized) Prestring() Prestring {
+	return TrackingSummarizedPrestring{Limit: t.Limit}
+}
+
+// TrackingSummarizedPrestring is a Prestring storing the needed information to compactly encode a TrackingSummarized
+type TrackingSummarizedPrestring struct {
+	Limit int
+}
+
+func (t TrackingSummarizedPrestring) String() string {
+	return fmt.Sprintf("value no longer tracked (note: function exceeds the limit of %d tracked expressions)", t.Limit)
+}
+
 // don't modify the ConsumeTrigger and ProduceTrigger objects after construction! Pointers
 // to them are duplicated
 
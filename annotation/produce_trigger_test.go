@@ -49,6 +49,7 @@ func (s *ProducingAnnotationTriggerTestSuite) SetupTest() {
 		&ConstNil{ProduceTriggerTautology: &ProduceTriggerTautology{}},
 		&UnassignedFld{ProduceTriggerTautology: &ProduceTriggerTautology{}},
 		&NoVarAssign{ProduceTriggerTautology: &ProduceTriggerTautology{}},
+		&UnassignedArrayElem{ProduceTriggerTautology: &ProduceTriggerTautology{}},
 		&BlankVarReturn{ProduceTriggerTautology: &ProduceTriggerTautology{}},
 		&FuncParam{TriggerIfNilable: &TriggerIfNilable{Ann: mockedKey}},
 		&MethodRecv{TriggerIfNilable: &TriggerIfNilable{Ann: mockedKey}},
@@ -56,6 +57,7 @@ func (s *ProducingAnnotationTriggerTestSuite) SetupTest() {
 		&VariadicFuncParam{ProduceTriggerTautology: &ProduceTriggerTautology{}},
 		&TrustedFuncNilable{ProduceTriggerTautology: &ProduceTriggerTautology{}},
 		&TrustedFuncNonnil{ProduceTriggerNever: &ProduceTriggerNever{}},
+		&TrustedFuncOkResult{ProduceTriggerNever: &ProduceTriggerNever{}},
 		&FldRead{TriggerIfNilable: &TriggerIfNilable{Ann: mockedKey}},
 		&ParamFldRead{TriggerIfNilable: &TriggerIfNilable{Ann: mockedKey}},
 		&FldReturn{TriggerIfNilable: &TriggerIfNilable{Ann: mockedKey}},
@@ -63,6 +65,9 @@ func (s *ProducingAnnotationTriggerTestSuite) SetupTest() {
 		&MethodReturn{TriggerIfNilable: &TriggerIfNilable{Ann: mockedKey}},
 		&MethodResultReachesInterface{TriggerIfNilable: &TriggerIfNilable{Ann: mockedKey}},
 		&InterfaceParamReachesImplementation{TriggerIfNilable: &TriggerIfNilable{Ann: mockedKey}},
+		&CallbackParamReachesFunc{TriggerIfNilable: &TriggerIfNilable{Ann: mockedKey}},
+		&FuncResultReachesCallback{TriggerIfNilable: &TriggerIfNilable{Ann: mockedKey}},
+		&CallbackReturn{TriggerIfNilable: &TriggerIfNilable{Ann: mockedKey}},
 		&GlobalVarRead{TriggerIfNilable: &TriggerIfNilable{Ann: mockedKey}},
 		&MapRead{TriggerIfDeepNilable: &TriggerIfDeepNilable{Ann: mockedKey}},
 		&ArrayRead{TriggerIfDeepNilable: &TriggerIfDeepNilable{Ann: mockedKey}},
@@ -76,6 +81,8 @@ func (s *ProducingAnnotationTriggerTestSuite) SetupTest() {
 		&LocalVarReadDeep{TriggerIfDeepNilable: &TriggerIfDeepNilable{Ann: mockedKey}},
 		&GlobalVarReadDeep{TriggerIfDeepNilable: &TriggerIfDeepNilable{Ann: mockedKey}},
 		&GuardMissing{ProduceTriggerTautology: &ProduceTriggerTautology{}, OldAnnotation: mockedProducingAnnotationTrigger},
+		&TrackingSummarized{ProduceTriggerTautology: &ProduceTriggerTautology{}, Limit: 1},
+		&PluginProducer{ProducingAnnotationTrigger: &ProduceTriggerTautology{}, Plugin: &mockPluginTrigger{}},
 	}
 }
 
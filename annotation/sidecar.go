@@ -0,0 +1,157 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package annotation
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"go.uber.org/nilaway/config"
+	"golang.org/x/tools/go/analysis"
+)
+
+// observeSidecarAnnotations adds the annotations read from the sidecar annotation files (see
+// config.SidecarAnnotation) to the map. Only the annotations for objects visible from the current
+// package (i.e., objects in the current package or its transitive imports) are added, and they
+// take precedence over the annotation comments for the same sites. Similar to the annotation
+// comments, the problems in the annotations for the current package (e.g., syntax errors, unknown
+// targets or unknown objects) are reported as diagnostics at the annotated objects.
+func (m *ObservedMap) observeSidecarAnnotations(pass *analysis.Pass, annotations []config.SidecarAnnotation) {
+	if len(annotations) == 0 {
+		return
+	}
+
+	// Collect all packages visible from the current package.
+	pkgs := make(map[string]*types.Package)
+	var collect func(pkg *types.Package)
+	collect = func(pkg *types.Package) {
+		if _, ok := pkgs[pkg.Path()]; ok {
+			return
+		}
+		pkgs[pkg.Path()] = pkg
+		for _, imported := range pkg.Imports() {
+			collect(imported)
+		}
+	}
+	collect(pass.Pkg)
+
+	for _, ann := range annotations {
+		pkg, ok := pkgs[ann.PkgPath]
+		if !ok {
+			continue
+		}
+		set := nilabilityFromCommentGroup(&ast.CommentGroup{List: []*ast.Comment{{Text: "// " + ann.Annotations}}})
+		if len(set.vals) == 0 && len(set.errs) == 0 {
+			continue
+		}
+		// Only the annotations for the current package are reported, such that each problem is
+		// reported once, in the package declaring the annotated object.
+		report := func(pos token.Pos, problems ...string) {
+			if pkg != pass.Pkg {
+				return
+			}
+			for _, problem := range problems {
+				m.diagnostics = append(m.diagnostics, analysis.Diagnostic{
+					Pos:     pos,
+					Message: fmt.Sprintf("Invalid NilAway annotation: %s (in sidecar annotation %s)", problem, ann.Source),
+				})
+			}
+		}
+
+		typeName, member, isMember := strings.Cut(ann.ObjectPath, ".")
+		obj := pkg.Scope().Lookup(typeName)
+		if obj == nil {
+			if len(pass.Files) > 0 {
+				report(pass.Files[0].Package, fmt.Sprintf("unknown object %q", ann.ObjectPath))
+			}
+			continue
+		}
+		if isMember {
+			method, _, _ := types.LookupFieldOrMethod(obj.Type(), true /* addressable */, pkg, member)
+			fn, ok := method.(*types.Func)
+			if !ok {
+				report(obj.Pos(), fmt.Sprintf("unknown method %q", ann.ObjectPath))
+				continue
+			}
+			m.observeSidecarFunc(fn, set)
+			report(fn.Pos(), set.problems()...)
+			continue
+		}
+
+		switch obj := obj.(type) {
+		case *types.Func:
+			m.observeSidecarFunc(obj, set)
+		case *types.Var:
+			m.globalVarsAnnMap[obj] = set.override(m.globalVarsAnnMap[obj], obj.Type(), obj.Name())
+		case *types.TypeName:
+			switch u := obj.Type().Underlying().(type) {
+			case *types.Struct:
+				for i := 0; i < u.NumFields(); i++ {
+					fld := u.Field(i)
+					m.fieldAnnMap[fld] = set.override(m.fieldAnnMap[fld], fld.Type(), fld.Name())
+				}
+			case *types.Pointer, *types.Map, *types.Slice, *types.Array:
+				m.deepTypeAnnMap[obj] = set.override(m.deepTypeAnnMap[obj], obj.Type(), obj.Name())
+			}
+		}
+		report(obj.Pos(), set.problems()...)
+	}
+}
+
+// observeSidecarFunc adds the sidecar annotations for the parameters, results and the receiver of
+// the function to the map. Parameters and results can be referred to by their names or indices
+// (e.g., "param 0" and "result 1").
+func (m *ObservedMap) observeSidecarFunc(fn *types.Func, set *nilabilitySet) {
+	sig := fn.Type().(*types.Signature)
+
+	observeTuple := func(tuple *types.Tuple, existing []Val, indexName func(int) string, isVariadic bool) []Val {
+		vals := make([]Val, tuple.Len())
+		for i := 0; i < tuple.Len(); i++ {
+			v := tuple.At(i)
+			t := v.Type()
+			if isVariadic && i == tuple.Len()-1 {
+				// Variadic parameters are annotated by their element types.
+				t = t.(*types.Slice).Elem()
+			}
+			var val Val
+			if i < len(existing) {
+				val = existing[i]
+			}
+			vals[i] = set.override(val, t, v.Name(), indexName(i))
+		}
+		return vals
+	}
+
+	m.funcParamAnnMap[fn] = observeTuple(sig.Params(), m.funcParamAnnMap[fn], paramStr, sig.Variadic())
+	m.funcRetAnnMap[fn] = observeTuple(sig.Results(), m.funcRetAnnMap[fn], resultStr, false)
+	if recv := sig.Recv(); recv != nil {
+		m.funcRecvAnnMap[fn] = set.override(m.funcRecvAnnMap[fn], recv.Type(), recv.Name(), receiverTarget)
+	}
+}
+
+// override returns the given value (or the default value for the type if the value is empty),
+// with the nilabilities explicitly set in this set for the first present name taking precedence.
+func (set *nilabilitySet) override(val Val, t types.Type, names ...string) Val {
+	if val == EmptyVal {
+		val = set.checkNilability(t)
+	}
+	if v, ok := set.lookup(names...); ok {
+		val = val.overriddenBy(v)
+	}
+	return val
+}
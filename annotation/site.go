@@ -0,0 +1,118 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package annotation
+
+import (
+	"go/types"
+
+	"go.uber.org/nilaway/site"
+)
+
+// SiteOf returns the stable identifier (see site.Site) of the site the key is on, or false if the
+// site cannot be referred to symbolically, e.g., for the local variables, the fields of the
+// anonymous structs or the keys internal to the inference (such as ParamFieldAnnotationKey). The
+// call-site keys map to the sites of the declarations.
+func SiteOf(key Key) (site.Site, bool) {
+	switch key := key.(type) {
+	case *ParamAnnotationKey:
+		return funcSite(key.FuncDecl, func(pkgPath, path string) site.Site {
+			return site.Param(pkgPath, path, key.ParamNum)
+		})
+	case *CallSiteParamAnnotationKey:
+		return funcSite(key.FuncDecl, func(pkgPath, path string) site.Site {
+			return site.Param(pkgPath, path, key.ParamNum)
+		})
+	case *RetAnnotationKey:
+		return funcSite(key.FuncDecl, func(pkgPath, path string) site.Site {
+			return site.Result(pkgPath, path, key.RetNum)
+		})
+	case *CallSiteRetAnnotationKey:
+		return funcSite(key.FuncDecl, func(pkgPath, path string) site.Site {
+			return site.Result(pkgPath, path, key.RetNum)
+		})
+	case *RecvAnnotationKey:
+		return funcSite(key.FuncDecl, func(pkgPath, path string) site.Site {
+			return site.Receiver(pkgPath, path)
+		})
+	case *FieldAnnotationKey:
+		fld := key.FieldDecl.Origin()
+		if fld.Pkg() == nil {
+			return site.Site{}, false
+		}
+		typeName, ok := structTypeName(fld)
+		if !ok {
+			return site.Site{}, false
+		}
+		return site.Field(fld.Pkg().Path(), typeName, fld.Name()), true
+	case *GlobalVarAnnotationKey:
+		v := key.VarDecl
+		if v.Pkg() == nil || v.Pkg().Scope().Lookup(v.Name()) != v {
+			return site.Site{}, false
+		}
+		return site.Global(v.Pkg().Path(), v.Name()), true
+	default:
+		return site.Site{}, false
+	}
+}
+
+// funcSite returns the site made by the given function from the package path and the object path
+// of the function (or the method), or false if it is not declared at the package level (e.g., the
+// methods of the local types).
+func funcSite(fn *types.Func, makeSite func(pkgPath, path string) site.Site) (site.Site, bool) {
+	fn = fn.Origin()
+	pkg := fn.Pkg()
+	if pkg == nil {
+		return site.Site{}, false
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		if pkg.Scope().Lookup(fn.Name()) != fn {
+			return site.Site{}, false
+		}
+		return makeSite(pkg.Path(), fn.Name()), true
+	}
+
+	t := types.Unalias(recv.Type())
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = types.Unalias(ptr.Elem())
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Parent() != pkg.Scope() {
+		return site.Site{}, false
+	}
+	return makeSite(pkg.Path(), named.Obj().Name()+"."+fn.Name()), true
+}
+
+// structTypeName returns the name of the package-level struct type declaring the field, or false
+// if there is none (e.g., for the fields of the anonymous structs).
+func structTypeName(fld *types.Var) (string, bool) {
+	scope := fld.Pkg().Scope()
+	for _, name := range scope.Names() {
+		obj, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || obj.IsAlias() {
+			continue
+		}
+		st, ok := obj.Type().Underlying().(*types.Struct)
+		if !ok {
+			continue
+		}
+		for i := 0; i < st.NumFields(); i++ {
+			if st.Field(i) == fld {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,100 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package annotation
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/nilaway/site"
+)
+
+func TestSiteOf(t *testing.T) {
+	t.Parallel()
+
+	src := `package p
+
+type Config struct {
+	Timeout *int
+	Nested  struct{ Inner *int }
+}
+
+type Box[T any] struct{ Val *T }
+
+type Client struct{}
+
+func (c *Client) Do(req *int) (*int, error) { return req, nil }
+
+func NewClient(cfg *Config) *Client { return nil }
+
+var DefaultClient *Client
+
+func local() {
+	type localType struct{}
+	var x *int
+	_ = x
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, 0)
+	require.NoError(t, err)
+	pkg, err := (&types.Config{}).Check("example.com/p", fset, []*ast.File{file}, nil)
+	require.NoError(t, err)
+	scope := pkg.Scope()
+
+	newClient := scope.Lookup("NewClient").(*types.Func)
+	client := scope.Lookup("Client").(*types.TypeName).Type().(*types.Named)
+	do := client.Method(0)
+	config := scope.Lookup("Config").Type().Underlying().(*types.Struct)
+	box := scope.Lookup("Box").Type().(*types.Named)
+	boxInt := types.NewPointer(types.Typ[types.Int])
+	instantiated, err := types.Instantiate(nil, box, []types.Type{boxInt}, true)
+	require.NoError(t, err)
+	instantiatedField := instantiated.Underlying().(*types.Struct).Field(0)
+
+	tests := []struct {
+		key  Key
+		want site.Site
+	}{
+		{&ParamAnnotationKey{FuncDecl: newClient, ParamNum: 0}, site.Param("example.com/p", "NewClient", 0)},
+		{&CallSiteParamAnnotationKey{FuncDecl: do, ParamNum: 0}, site.Param("example.com/p", "Client.Do", 0)},
+		{&RetAnnotationKey{FuncDecl: do, RetNum: 1}, site.Result("example.com/p", "Client.Do", 1)},
+		{&CallSiteRetAnnotationKey{FuncDecl: newClient, RetNum: 0}, site.Result("example.com/p", "NewClient", 0)},
+		{&RecvAnnotationKey{FuncDecl: do}, site.Receiver("example.com/p", "Client.Do")},
+		{&FieldAnnotationKey{FieldDecl: config.Field(0)}, site.Field("example.com/p", "Config", "Timeout")},
+		{&FieldAnnotationKey{FieldDecl: instantiatedField}, site.Field("example.com/p", "Box", "Val")},
+		{&GlobalVarAnnotationKey{VarDecl: scope.Lookup("DefaultClient").(*types.Var)}, site.Global("example.com/p", "DefaultClient")},
+	}
+	for _, tt := range tests {
+		got, ok := SiteOf(tt.key)
+		require.True(t, ok, tt.want.String())
+		require.Equal(t, tt.want, got)
+	}
+
+	// The sites without symbolic identifiers.
+	nested := config.Field(1).Type().(*types.Struct).Field(0)
+	for _, key := range []Key{
+		&FieldAnnotationKey{FieldDecl: nested},
+		&LocalVarAnnotationKey{VarDecl: types.NewVar(token.NoPos, pkg, "x", boxInt)},
+		&ParamFieldAnnotationKey{FuncDecl: newClient, ParamNum: 0, FieldDecl: config.Field(0)},
+	} {
+		_, ok := SiteOf(key)
+		require.False(t, ok)
+	}
+}
@@ -0,0 +1,211 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package annotation
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// This file implements the parser for the annotation comments, see the package documentation for
+// the grammar.
+
+const nilableKeyword = "nilable"
+const nonNilKeyword = "nonnil"
+
+// receiverTarget is the target referring to the receiver of a method.
+const receiverTarget = "receiver"
+
+// annotationStartRegex matches the start of an annotation, i.e., the keyword followed by "(".
+var annotationStartRegex = regexp.MustCompile(`\b(` + nilableKeyword + `|` + nonNilKeyword + `)\(`)
+
+// fieldAnnotationRegex matches the annotation comments placed directly on struct fields, which
+// consist of a bare keyword (e.g., `//nonnil`) and apply to all names of the field.
+var fieldAnnotationRegex = regexp.MustCompile(`^//\s*(` + nilableKeyword + `|` + nonNilKeyword + `)\s*$`)
+
+// indexedTargetRegex matches the targets referring to parameters and results by their indices.
+var indexedTargetRegex = regexp.MustCompile(`^(param|result)\s+([0-9]+)$`)
+
+func paramStr(i int) string {
+	return "param " + strconv.Itoa(i)
+}
+
+func resultStr(i int) string {
+	return "result " + strconv.Itoa(i)
+}
+
+// nilabilitySet is the set of nilabilities read from a group of annotation comments, keyed by the
+// normalized targets (e.g., "x", "param 0", "result 1", "receiver" or "x.F"). It also keeps track
+// of the targets that have been looked up, such that the unknown targets can be reported.
+type nilabilitySet struct {
+	vals map[string]Val
+	used map[string]bool
+	// pos is the position of the annotation comments.
+	pos token.Pos
+	// errs is the list of syntax errors in the annotation comments.
+	errs []string
+}
+
+// lookup returns the value of the first target in names that is present in the set, and marks
+// all names as used.
+func (set *nilabilitySet) lookup(names ...string) (Val, bool) {
+	var (
+		val   Val
+		found bool
+	)
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		if set.used == nil {
+			set.used = make(map[string]bool)
+		}
+		set.used[name] = true
+		if v, ok := set.vals[name]; ok && !found {
+			val, found = v, true
+		}
+	}
+	return val, found
+}
+
+// targets returns the sorted list of targets matching the predicate.
+func (set *nilabilitySet) targets(pred func(string) bool) []string {
+	var targets []string
+	for name := range set.vals {
+		if pred(name) {
+			targets = append(targets, name)
+		}
+	}
+	slices.Sort(targets)
+	return targets
+}
+
+// problems returns the syntax errors and the unknown (i.e., never looked up) targets of the set,
+// formatted as error messages.
+func (set *nilabilitySet) problems() []string {
+	problems := slices.Clone(set.errs)
+	for _, name := range set.targets(func(name string) bool { return !set.used[name] }) {
+		problems = append(problems, fmt.Sprintf("unknown annotation target %q", name))
+	}
+	return problems
+}
+
+// nilabilityFromCommentGroup parses the annotations in the comment group and returns the set of
+// nilabilities, which is empty if the group is nil or does not contain any annotations.
+func nilabilityFromCommentGroup(group *ast.CommentGroup) *nilabilitySet {
+	set := &nilabilitySet{vals: make(map[string]Val)}
+	if group == nil {
+		return set
+	}
+	set.pos = group.Pos()
+
+	// Read annotations are considered final, hence isFinalVal=true.
+	mark := func(name string, nilable, deep bool) {
+		v := set.vals[name]
+		switch {
+		case nilable && deep:
+			v = v.makeDeepNilable(true)
+		case nilable:
+			v = v.makeNilable(true)
+		case deep:
+			v = v.makeDeepNonNil(true)
+		default:
+			v = v.makeNonNil(true)
+		}
+		set.vals[name] = v
+	}
+
+	for _, comment := range group.List {
+		text := comment.Text
+		for _, loc := range annotationStartRegex.FindAllStringSubmatchIndex(text, -1) {
+			nilable := text[loc[2]:loc[3]] == nilableKeyword
+			rest := text[loc[1]:]
+			end := strings.IndexByte(rest, ')')
+			if end == -1 {
+				set.errs = append(set.errs, fmt.Sprintf("missing \")\" in annotation %q", text[loc[0]:]))
+				continue
+			}
+			for _, target := range strings.Split(rest[:end], ",") {
+				name, deep, err := parseTarget(target)
+				if err != nil {
+					set.errs = append(set.errs, fmt.Sprintf("invalid annotation %q: %s", text[loc[0]:loc[1]+end+1], err))
+					continue
+				}
+				mark(name, nilable, deep)
+			}
+		}
+	}
+	return set
+}
+
+// parseTarget parses a single target in an annotation and returns its normalized name, and
+// whether it refers to the deep nilability of the target.
+func parseTarget(target string) (string, bool, error) {
+	target = strings.TrimSpace(target)
+	deep := false
+	for _, marker := range [...]string{"*", "<-"} {
+		if rest, ok := strings.CutPrefix(target, marker); ok {
+			target, deep = strings.TrimSpace(rest), true
+			break
+		}
+	}
+	if rest, ok := strings.CutSuffix(target, "[]"); ok {
+		if deep {
+			return "", false, fmt.Errorf("multiple deep markers in %q", target)
+		}
+		target, deep = strings.TrimSpace(rest), true
+	}
+
+	if target == "" {
+		return "", false, fmt.Errorf("empty target")
+	}
+	if m := indexedTargetRegex.FindStringSubmatch(target); m != nil {
+		index, err := strconv.Atoi(m[2])
+		if err != nil {
+			return "", false, fmt.Errorf("invalid index in %q: %w", target, err)
+		}
+		if m[1] == "param" {
+			return paramStr(index), deep, nil
+		}
+		return resultStr(index), deep, nil
+	}
+	for _, part := range strings.Split(target, ".") {
+		if !token.IsIdentifier(part) || part == "_" {
+			return "", false, fmt.Errorf("%q is not a parameter, result, receiver or field", target)
+		}
+	}
+	return target, deep, nil
+}
+
+// nilabilityOfField returns the nilability read from the annotation comment placed directly on
+// the struct field (i.e., in its doc comment or line comment), and whether such a comment exists.
+func nilabilityOfField(field *ast.Field) (nilable bool, ok bool) {
+	for _, group := range [...]*ast.CommentGroup{field.Doc, field.Comment} {
+		if group == nil {
+			continue
+		}
+		for _, comment := range group.List {
+			if m := fieldAnnotationRegex.FindStringSubmatch(comment.Text); m != nil {
+				return m[1] == nilableKeyword, true
+			}
+		}
+	}
+	return false, false
+}
@@ -0,0 +1,60 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package annotation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTarget(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		target   string
+		wantName string
+		wantDeep bool
+		wantErr  bool
+	}{
+		{target: "x", wantName: "x"},
+		{target: " x ", wantName: "x"},
+		{target: "param 1", wantName: "param 1"},
+		{target: "result  0", wantName: "result 0"},
+		{target: "receiver", wantName: "receiver"},
+		{target: "f.Client", wantName: "f.Client"},
+		{target: "*x", wantName: "x", wantDeep: true},
+		{target: "<-c", wantName: "c", wantDeep: true},
+		{target: "s[]", wantName: "s", wantDeep: true},
+		{target: "*result 0", wantName: "result 0", wantDeep: true},
+		{target: "", wantErr: true},
+		{target: "*", wantErr: true},
+		{target: "*s[]", wantErr: true},
+		{target: "a b", wantErr: true},
+		{target: "_", wantErr: true},
+		{target: "f.", wantErr: true},
+		{target: "param x", wantErr: true},
+	}
+	for _, tt := range tests {
+		name, deep, err := parseTarget(tt.target)
+		if tt.wantErr {
+			require.Error(t, err, "target %q", tt.target)
+			continue
+		}
+		require.NoError(t, err, "target %q", tt.target)
+		require.Equal(t, tt.wantName, name, "target %q", tt.target)
+		require.Equal(t, tt.wantDeep, deep, "target %q", tt.target)
+	}
+}
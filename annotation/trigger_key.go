@@ -0,0 +1,89 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package annotation
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+)
+
+// consumeTriggerKey is a cheap, comparable summary of a ConsumeTrigger that is used to bucket
+// triggers in the merge and equality helpers. It is consistent with the (expensive) `equals`
+// methods of the triggers: two triggers that are equal modulo guards always share the same key,
+// since every `equals` implementation first requires the same concrete trigger type, and then
+// compares the underlying sites and the consumer expressions. The converse is not true, so
+// triggers sharing a key must still be compared via `equals`. This turns the quadratic scans over
+// all pairs of triggers into (mostly) linear scans over small buckets, which matters for large
+// functions where the fixed-point loop merges and compares thousands of triggers per round (see
+// BenchmarkMergeFullTriggers and BenchmarkMergeConsumeTriggerSlices).
+//
+// Note that the triggers are bucketed rather than hash-consed (i.e., deduplicated into shared
+// canonical instances): the backpropagation updates the triggers in place (e.g., the guard
+// matching in RootAssertionNode.AddGuardMatch, or SetNeedsGuard on the producers), which would leak
+// across all the users of a shared instance. The keys are therefore computed on demand, and the
+// triggers keep being copied where they are updated. Only their immutable Prestrings are interned,
+// once the triggers are reduced to the primitive forms kept by the inference.
+type consumeTriggerKey struct {
+	typ  reflect.Type
+	site types.Object
+	expr ast.Expr
+}
+
+// fullTriggerKey is the FullTrigger counterpart of consumeTriggerKey, additionally covering the
+// producer annotation.
+type fullTriggerKey struct {
+	consumer     consumeTriggerKey
+	producerTyp  reflect.Type
+	producerSite types.Object
+}
+
+// siteObject returns the object of the given site, or nil if the site itself is nil.
+func siteObject(site Key) types.Object {
+	if site == nil {
+		return nil
+	}
+	return site.Object()
+}
+
+// keyOfConsumer returns the bucketing key of the consume trigger.
+func keyOfConsumer(c *ConsumeTrigger) consumeTriggerKey {
+	return consumeTriggerKey{
+		typ:  reflect.TypeOf(c.Annotation),
+		site: siteObject(c.Annotation.UnderlyingSite()),
+		expr: c.Expr,
+	}
+}
+
+// keyOfFullTrigger returns the bucketing key of the full trigger.
+func keyOfFullTrigger(t *FullTrigger) fullTriggerKey {
+	return fullTriggerKey{
+		consumer:     keyOfConsumer(t.Consumer),
+		producerTyp:  reflect.TypeOf(t.Producer.Annotation),
+		producerSite: siteObject(t.Producer.Annotation.UnderlyingSite()),
+	}
+}
+
+// indexFullTriggers buckets the indices of the given full triggers by their keys. The indices in
+// each bucket are in increasing order, so iterating a bucket visits the triggers in the same
+// relative order as iterating the original slice.
+func indexFullTriggers(triggers []FullTrigger) map[fullTriggerKey][]int {
+	index := make(map[fullTriggerKey][]int, len(triggers))
+	for i := range triggers {
+		k := keyOfFullTrigger(&triggers[i])
+		index[k] = append(index[k], i)
+	}
+	return index
+}
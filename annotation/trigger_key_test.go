@@ -0,0 +1,88 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package annotation
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/nilaway/util"
+)
+
+// newBenchTriggers returns n full triggers on distinct global variables and expressions, such that
+// they are all pairwise distinct. Real keys are used instead of the mocks, since the mocks are
+// expensive enough to dominate the benchmarks.
+func newBenchTriggers(n int) []FullTrigger {
+	triggers := make([]FullTrigger, n)
+	for i := range triggers {
+		name := fmt.Sprintf("v%d", i)
+		key := &GlobalVarAnnotationKey{VarDecl: types.NewVar(token.NoPos, nil, name, types.Typ[types.Int])}
+		triggers[i] = FullTrigger{
+			Producer: &ProduceTrigger{
+				Annotation: &GlobalVarRead{TriggerIfNilable: &TriggerIfNilable{Ann: key}},
+				Expr:       ast.NewIdent(name),
+			},
+			Consumer: &ConsumeTrigger{
+				Annotation: &GlobalVarAssign{TriggerIfNonNil: &TriggerIfNonNil{Ann: key}},
+				Expr:       ast.NewIdent(name),
+				Guards:     util.NoGuards(),
+			},
+		}
+	}
+	return triggers
+}
+
+// BenchmarkMergeFullTriggers benchmarks merging two overlapping slices of full triggers, which is
+// done for every block in the fixed-point loop of the backpropagation.
+func BenchmarkMergeFullTriggers(b *testing.B) {
+	for _, n := range []int{100, 1000, 5000} {
+		triggers := newBenchTriggers(n)
+		// The two halves overlap in the middle third.
+		left, right := triggers[:2*n/3], triggers[n/3:]
+		require.Len(b, MergeFullTriggers(left, right...), n)
+
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				MergeFullTriggers(left, right...)
+			}
+		})
+	}
+}
+
+// BenchmarkMergeConsumeTriggerSlices benchmarks merging two overlapping slices of consume
+// triggers, which is done at every join point of the backpropagation.
+func BenchmarkMergeConsumeTriggerSlices(b *testing.B) {
+	for _, n := range []int{100, 1000, 5000} {
+		consumers := make([]*ConsumeTrigger, n)
+		for i, t := range newBenchTriggers(n) {
+			consumers[i] = t.Consumer
+		}
+		// The two halves overlap in the middle third.
+		left, right := consumers[:2*n/3], consumers[n/3:]
+		require.Len(b, MergeConsumeTriggerSlices(left, right), n)
+
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				MergeConsumeTriggerSlices(left, right)
+			}
+		})
+	}
+}
@@ -61,7 +61,7 @@ func DeepNilabilityAsNamedType(typ types.Type) ProducingAnnotationTrigger {
 func DeepNilabilityOfFuncRet(fn *types.Func, retNum int) ProducingAnnotationTrigger {
 	fsig := fn.Type().(*types.Signature)
 	retType := fsig.Results().At(retNum).Type()
-	if util.TypeIsDeep(retType) {
+	if util.TypeIsDeepAtSite(retType) {
 		return &FuncReturnDeep{
 			TriggerIfDeepNilable: &TriggerIfDeepNilable{
 				Ann:        RetKeyFromRetNum(fn, retNum),
@@ -73,8 +73,10 @@ func DeepNilabilityOfFuncRet(fn *types.Func, retNum int) ProducingAnnotationTrig
 
 // DeepNilabilityOfFld inspects a struct field for deep nilability annotation
 func DeepNilabilityOfFld(fld *types.Var) ProducingAnnotationTrigger {
-	if util.TypeIsDeep(fld.Type()) {
-		// in this case, the deep nilability of the field comes from its declaring annotations
+	if util.TypeIsDeepAtSite(fld.Type()) {
+		// in this case, the deep nilability of the field comes from its declaring annotations, even
+		// if the field is of a named type, such that the fields of the same named type do not
+		// share their deep nilability
 		return &FldReadDeep{
 			TriggerIfDeepNilable: &TriggerIfDeepNilable{
 				Ann: &FieldAnnotationKey{
@@ -87,6 +89,10 @@ func DeepNilabilityOfFld(fld *types.Var) ProducingAnnotationTrigger {
 
 // DeepNilabilityOfVar inspects a variable for deep nilability annotation
 func DeepNilabilityOfVar(fdecl *types.Func, v *types.Var) ProducingAnnotationTrigger {
+	if VarIsParam(fdecl, v) && util.TypeIsDeepAtSite(v.Type()) {
+		// like fields, parameters of named types have their own deep nilability annotations
+		return paramAsDeepProducer(fdecl, v)
+	}
 	if util.TypeIsDeep(v.Type()) {
 		// in each of the following cases, the deep nilability of the variable comes from its
 		// declaring annotations
@@ -99,9 +105,6 @@ func DeepNilabilityOfVar(fdecl *types.Func, v *types.Var) ProducingAnnotationTri
 					NeedsGuard: util.TypeIsDeeplyMap(v.Type())},
 			}
 		}
-		if VarIsParam(fdecl, v) {
-			return paramAsDeepProducer(fdecl, v)
-		}
 		if VarIsRecv(fdecl, v) {
 			return &MethodRecvDeep{
 				TriggerIfDeepNilable: &TriggerIfDeepNilable{
@@ -17,8 +17,13 @@
 package affiliation
 
 import (
+	"bytes"
+	"cmp"
+	"encoding/gob"
 	"go/ast"
+	"go/token"
 	"go/types"
+	"slices"
 	"strings"
 
 	"go.uber.org/nilaway/annotation"
@@ -33,6 +38,9 @@ import (
 type Affiliation struct {
 	conf     *config.Config
 	triggers []annotation.FullTrigger
+	// returns maps the methods declared in the current package to their return statements, at which
+	// the violations of the covariance of their results are reported
+	returns map[*types.Func][]*ast.ReturnStmt
 }
 
 // Pair is a struct to store struct-interface affiliation pairs
@@ -54,6 +62,47 @@ type AffliliationCache struct {
 // AFact enables use of the facts passing mechanism in Go's analysis framework
 func (*AffliliationCache) AFact() {}
 
+// cacheEntry is a single entry of the AffliliationCache in its on-the-wire form.
+type cacheEntry struct {
+	Pair  Pair
+	Value bool
+}
+
+// GobEncode encodes the cache as a slice of entries sorted by their pairs. This is needed since the
+// analysis framework requires the encoding of facts to be deterministic, and gob encodes maps in
+// their (random) iteration order.
+func (c *AffliliationCache) GobEncode() ([]byte, error) {
+	entries := make([]cacheEntry, 0, len(c.Cache))
+	for p, v := range c.Cache {
+		entries = append(entries, cacheEntry{Pair: p, Value: v})
+	}
+	slices.SortFunc(entries, func(a, b cacheEntry) int {
+		if n := cmp.Compare(a.Pair.ImplementedID, b.Pair.ImplementedID); n != 0 {
+			return n
+		}
+		return cmp.Compare(a.Pair.DeclaredID, b.Pair.DeclaredID)
+	})
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(entries); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode decodes the cache from the slice of entries encoded by GobEncode.
+func (c *AffliliationCache) GobDecode(data []byte) error {
+	var entries []cacheEntry
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&entries); err != nil {
+		return err
+	}
+	c.Cache = make(ImplementedDeclaredTypesCache, len(entries))
+	for _, e := range entries {
+		c.Cache[e.Pair] = e.Value
+	}
+	return nil
+}
+
 // extractAffiliations processes all affiliations (e.g., interface and its implementing struct) and returns map documenting
 // the affiliations
 func (a *Affiliation) extractAffiliations(pass *analysis.Pass) {
@@ -74,6 +123,7 @@ func (a *Affiliation) extractAffiliations(pass *analysis.Pass) {
 		}
 	}
 
+	a.returns = collectMethodReturns(pass, a.conf)
 	a.computeTriggersForCastingSites(pass, upstreamCache, currentCache)
 
 	// export upstreamCache from this package by adding new entries (if any)
@@ -88,7 +138,14 @@ func (a *Affiliation) extractAffiliations(pass *analysis.Pass) {
 // variable assignments, variable declaration and initialization, method returns, and method parameters.
 func (a *Affiliation) computeTriggersForCastingSites(pass *analysis.Pass, upstreamCache ImplementedDeclaredTypesCache, currentCache ImplementedDeclaredTypesCache) {
 	appendTypeToTypeTriggers := func(lhsType, rhsType types.Type) {
-		a.triggers = append(a.triggers, a.computeTriggersForTypes(lhsType, rhsType, upstreamCache, currentCache)...)
+		a.triggers = append(a.triggers, a.computeTriggersForTypes(pass, lhsType, rhsType, upstreamCache, currentCache)...)
+	}
+	appendValueSpecTriggers := func(spec *ast.ValueSpec) {
+		for i := 0; i < len(spec.Values); i++ {
+			lhsType := pass.TypesInfo.TypeOf(spec.Type)
+			rhsType := pass.TypesInfo.TypeOf(spec.Values[i])
+			appendTypeToTypeTriggers(lhsType, rhsType)
+		}
 	}
 
 	for _, file := range pass.Files {
@@ -98,6 +155,14 @@ func (a *Affiliation) computeTriggersForCastingSites(pass *analysis.Pass, upstre
 
 		// identify sites of explicit or implicit casts
 		for _, decl := range file.Decls {
+			if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.VAR {
+				// global variables, including the idiomatic assertions of implementations such as
+				// `var _ I = (*S)(nil)`
+				for _, spec := range genDecl.Specs {
+					appendValueSpecTriggers(spec.(*ast.ValueSpec))
+				}
+				continue
+			}
 			f, ok := decl.(*ast.FuncDecl)
 			if !ok {
 				continue
@@ -127,11 +192,7 @@ func (a *Affiliation) computeTriggersForCastingSites(pass *analysis.Pass, upstre
 					}
 				case *ast.ValueSpec:
 					// e.g., var i I = &S{}
-					for i := 0; i < len(node.Values); i++ {
-						lhsType := pass.TypesInfo.TypeOf(node.Type)
-						rhsType := pass.TypesInfo.TypeOf(node.Values[i])
-						appendTypeToTypeTriggers(lhsType, rhsType)
-					}
+					appendValueSpecTriggers(node)
 				case *ast.CallExpr:
 					// e.g., func foo(i I), foo(&S{})
 					if ident := util.FuncIdentFromCallExpr(node); ident != nil {
@@ -235,7 +296,7 @@ func (a *Affiliation) computeTriggersForCastingSites(pass *analysis.Pass, upstre
 }
 
 // computeTriggersForTypes finds corresponding concrete implementation and their declared methods and populates them in a map
-func (a *Affiliation) computeTriggersForTypes(lhsType types.Type, rhsType types.Type, upstreamCache ImplementedDeclaredTypesCache, currentCache ImplementedDeclaredTypesCache) []annotation.FullTrigger {
+func (a *Affiliation) computeTriggersForTypes(pass *analysis.Pass, lhsType types.Type, rhsType types.Type, upstreamCache ImplementedDeclaredTypesCache, currentCache ImplementedDeclaredTypesCache) []annotation.FullTrigger {
 	if lhsType == nil || rhsType == nil {
 		return nil
 	}
@@ -270,7 +331,10 @@ func (a *Affiliation) computeTriggersForTypes(lhsType types.Type, rhsType types.
 			continue
 		}
 		if implementedMethod, ok := implementedMethodObj.(*types.Func); ok {
-			triggers = append(triggers, createFunctionTriggers(implementedMethod, interfaceMethod)...)
+			// The methods of the instantiated generic interfaces and implementations (e.g., `I[int]`
+			// and `S[int]`) are distinct objects for each instantiation, while the annotations are
+			// attached to the generic declarations. Hence, we match their origins instead.
+			triggers = append(triggers, a.createFunctionTriggers(pass, implementedMethod.Origin(), interfaceMethod.Origin())...)
 		}
 	}
 	return triggers
@@ -280,13 +344,15 @@ func getFullyQualifiedName(t types.Type) string {
 	s := ""
 	switch n := t.(type) {
 	case *types.Named:
-		s = n.String()
+		// All instantiations of a generic type share the same key, since the affiliations are
+		// computed against the generic declarations of the methods (see computeTriggersForTypes).
+		s = n.Origin().String()
 	case *types.Interface:
 		// interface has no exported field/method that can be used to get its fully qualified path directly. However,
 		// its declared methods (*types.Func) have such exported methods. Therefore, the below logic extracts the
 		// interface's fully qualified path from its method's FullName()
 		if n.NumMethods() > 0 {
-			s = n.Method(0).FullName()
+			s = n.Method(0).Origin().FullName()
 			// funcName.FullName() returns a string of the form "(/path/to/interface).funcName". The below code strips
 			// off the method name and parentheses to get only "/path/to/interface"
 			i := strings.LastIndex(s, ".")
@@ -311,7 +377,7 @@ func computeAfflitiationCacheKey(interfaceObj *types.Interface, concreteObj *typ
 
 // createFunctionTriggers verifies the nilability annotations of the concrete implementation of a method
 // against its interface declaration for covariant return types and contravariant parameter types
-func createFunctionTriggers(implementingMethod *types.Func, interfaceMethod *types.Func) []annotation.FullTrigger {
+func (a *Affiliation) createFunctionTriggers(pass *analysis.Pass, implementingMethod *types.Func, interfaceMethod *types.Func) []annotation.FullTrigger {
 	triggers := make([]annotation.FullTrigger, 0)
 
 	methodSig := implementingMethod.Type().(*types.Signature)
@@ -322,7 +388,9 @@ func createFunctionTriggers(implementingMethod *types.Func, interfaceMethod *typ
 
 	// check for covariance in return types
 	for i := 0; i < methodSig.Results().Len(); i++ {
-		triggers = append(triggers, annotation.FullTriggerForInterfaceResultFlow(affiliation, i))
+		for _, ret := range a.offendingReturns(pass, implementingMethod, i) {
+			triggers = append(triggers, annotation.FullTriggerForInterfaceResultFlow(affiliation, i, ret))
+		}
 	}
 
 	// check for contravariance in parameter types
@@ -332,3 +400,84 @@ func createFunctionTriggers(implementingMethod *types.Func, interfaceMethod *typ
 	}
 	return triggers
 }
+
+// collectMethodReturns collects the return statements of the methods declared in the in-scope
+// files of the current package, excluding the ones of the anonymous functions in their bodies.
+func collectMethodReturns(pass *analysis.Pass, conf *config.Config) map[*types.Func][]*ast.ReturnStmt {
+	returns := make(map[*types.Func][]*ast.ReturnStmt)
+	for _, file := range pass.Files {
+		if !conf.IsFileInScope(file) {
+			continue
+		}
+		for _, decl := range file.Decls {
+			f, ok := decl.(*ast.FuncDecl)
+			if !ok || f.Recv == nil || f.Body == nil {
+				continue
+			}
+			method, ok := pass.TypesInfo.Defs[f.Name].(*types.Func)
+			if !ok {
+				continue
+			}
+			ast.Inspect(f.Body, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.FuncLit:
+					return false
+				case *ast.ReturnStmt:
+					returns[method] = append(returns[method], n)
+				}
+				return true
+			})
+		}
+	}
+	return returns
+}
+
+// offendingReturns returns the return statements of the implementing method at which the
+// violations of the covariance of its result at position `retNum` are reported. These are the
+// ones returning a literal nil at that position if any, otherwise the ones that may return nil
+// (see mayReturnNil), and otherwise (e.g., only the annotation of the result is nilable) all of
+// them. If the method is not declared in the current package, a single nil statement is returned,
+// such that the violations are reported at the method itself.
+func (a *Affiliation) offendingReturns(pass *analysis.Pass, method *types.Func, retNum int) []*ast.ReturnStmt {
+	returns, ok := a.returns[method]
+	if !ok {
+		return []*ast.ReturnStmt{nil}
+	}
+	var nils, mayBeNils []*ast.ReturnStmt
+	for _, ret := range returns {
+		if retNum < len(ret.Results) && util.IsLiteral(ret.Results[retNum], "nil") {
+			nils = append(nils, ret)
+		}
+		if mayReturnNil(pass, ret, retNum) {
+			mayBeNils = append(mayBeNils, ret)
+		}
+	}
+	switch {
+	case len(nils) > 0:
+		return nils
+	case len(mayBeNils) > 0:
+		return mayBeNils
+	default:
+		return returns
+	}
+}
+
+// mayReturnNil returns false if the result at position `retNum` of the return statement is
+// trivially nonnil, e.g., an address of a composite literal or a call to the builtin `new`.
+func mayReturnNil(pass *analysis.Pass, ret *ast.ReturnStmt, retNum int) bool {
+	if retNum >= len(ret.Results) || len(ret.Results) == 1 && retNum > 0 {
+		// naked returns of named results, or returns of calls with multiple results
+		return true
+	}
+	switch expr := ast.Unparen(ret.Results[retNum]).(type) {
+	case *ast.CompositeLit, *ast.FuncLit:
+		return false
+	case *ast.UnaryExpr:
+		return expr.Op != token.AND
+	case *ast.CallExpr:
+		if ident, ok := ast.Unparen(expr.Fun).(*ast.Ident); ok && pass.TypesInfo.Uses[ident] == util.BuiltinNew {
+			return false
+		}
+	}
+	return !util.ExprBarsNilness(pass, ret.Results[retNum])
+}
@@ -31,12 +31,13 @@ const _doc = "Track interface declarations and their implementations by identify
 // variance, and passes them onto the accumulator to be added to existing assertions to be matched
 // against annotations.
 var Analyzer = &analysis.Analyzer{
-	Name:       "nilaway_affiliation_analyzer",
-	Doc:        _doc,
-	Run:        analysishelper.WrapRun(run),
-	FactTypes:  []analysis.Fact{new(AffliliationCache)},
-	ResultType: reflect.TypeOf((*analysishelper.Result[[]annotation.FullTrigger])(nil)),
-	Requires:   []*analysis.Analyzer{config.Analyzer},
+	Name:             "nilaway_affiliation_analyzer",
+	Doc:              _doc,
+	Run:              analysishelper.WrapRun(run),
+	FactTypes:        []analysis.Fact{new(AffliliationCache)},
+	ResultType:       reflect.TypeOf((*analysishelper.Result[[]annotation.FullTrigger])(nil)),
+	Requires:         []*analysis.Analyzer{config.Analyzer},
+	RunDespiteErrors: true,
 }
 
 func run(pass *analysis.Pass) ([]annotation.FullTrigger, error) {
@@ -33,6 +33,29 @@ func TestAnalyzer(t *testing.T) {
 	require.ErrorContains(t, r.(*analysishelper.Result[[]annotation.FullTrigger]).Err, "INTERNAL PANIC")
 }
 
+func TestAffiliationCacheEncoding_Deterministic(t *testing.T) {
+	t.Parallel()
+
+	cache := &AffliliationCache{Cache: make(ImplementedDeclaredTypesCache)}
+	for _, impl := range []string{"a.S", "b.T", "c.U", "d.V"} {
+		for _, decl := range []string{"x.I", "y.J"} {
+			cache.Cache[Pair{ImplementedID: impl, DeclaredID: decl}] = true
+		}
+	}
+
+	encoded, err := cache.GobEncode()
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		again, err := cache.GobEncode()
+		require.NoError(t, err)
+		require.Equal(t, encoded, again)
+	}
+
+	decoded := &AffliliationCache{}
+	require.NoError(t, decoded.GobDecode(encoded))
+	require.Equal(t, cache.Cache, decoded.Cache)
+}
+
 func TestMain(m *testing.M) {
 	goleak.VerifyTestMain(m)
 }
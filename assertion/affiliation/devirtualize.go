@@ -0,0 +1,241 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package affiliation
+
+import (
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/ssa"
+)
+
+// Devirtualize finds the methods of the interfaces of the current package that have a single known
+// implementation, and returns a map from such interface methods to their implementing methods. The
+// calls to the interface methods can then be analyzed as calls to their implementations, e.g., the
+// contracts of the implementations apply at them, rather than falling back to the (absence of)
+// contracts of the interface methods.
+//
+// An interface method is devirtualized only if every interface value carrying it is created in the
+// current package from the same concrete type, i.e., all the `MakeInterface` instructions in the
+// SSA of the package converting into interfaces with the method have the same source type, and no
+// value of another interface merely declaring a method of the same name is converted into them. To
+// make sure that no values are created elsewhere, the interface declaring the method must be
+// unexported, not generic, not mentioned by the exported API of the package, and never the target
+// of a type assertion (which could yield a value of any type created anywhere). Dependency
+// injection of a single implementation, e.g., `newServer(&store{})` for a `newServer(s storer)`,
+// is the typical case.
+//
+// The SSA of the package is not built if it has type errors, in which case nil is returned.
+func Devirtualize(pass *analysis.Pass) map[*types.Func]*types.Func {
+	ssaInput, ok := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
+	if !ok || ssaInput.Pkg == nil {
+		return nil
+	}
+
+	// The initializations of the global variables are in the synthetic package initializer, which
+	// is not one of the source functions.
+	funcs := ssaInput.SrcFuncs
+	if init := ssaInput.Pkg.Func("init"); init != nil {
+		var addAnons func(f *ssa.Function)
+		addAnons = func(f *ssa.Function) {
+			funcs = append(funcs, f)
+			for _, anon := range f.AnonFuncs {
+				addAnons(anon)
+			}
+		}
+		addAnons(init)
+	}
+
+	declaring := declaringInterfaces(pass.Pkg)
+
+	// sources maps the candidate interface methods to the concrete type they are implemented by.
+	// A nil value means the method has more than one (or an unknown) implementation.
+	sources := make(map[*types.Func]types.Type)
+	// addSource records the concrete type as a source of the values of the interface. If from is
+	// not nil, the values are converted from the interface from, which already carries the methods
+	// it shares with the interface.
+	addSource := func(iface types.Type, concrete types.Type, from *types.Interface) {
+		it, ok := iface.Underlying().(*types.Interface)
+		if !ok {
+			return
+		}
+		for i := 0; i < it.NumMethods(); i++ {
+			m := it.Method(i)
+			if _, ok := declaring[m]; !ok {
+				continue
+			}
+			if from != nil {
+				if obj, _, _ := types.LookupFieldOrMethod(from, false /* addressable */, m.Pkg(), m.Name()); obj == m {
+					continue
+				}
+			}
+			if prev, ok := sources[m]; ok && (prev == nil || concrete == nil || !types.Identical(prev, concrete)) {
+				sources[m] = nil
+				continue
+			}
+			sources[m] = concrete
+		}
+	}
+	for _, fn := range funcs {
+		if fn == nil {
+			continue
+		}
+		for _, block := range fn.Blocks {
+			for _, instr := range block.Instrs {
+				switch instr := instr.(type) {
+				case *ssa.MakeInterface:
+					concrete := instr.X.Type()
+					if _, ok := concrete.(*types.TypeParam); ok {
+						// The value of a type parameter could be of any type.
+						concrete = nil
+					}
+					addSource(instr.Type(), concrete, nil /* from */)
+				case *ssa.ChangeInterface:
+					from, _ := instr.X.Type().Underlying().(*types.Interface)
+					addSource(instr.Type(), nil /* concrete */, from)
+				case *ssa.TypeAssert:
+					// The asserted value could come from anywhere.
+					addSource(instr.AssertedType, nil /* concrete */, nil /* from */)
+				}
+			}
+		}
+	}
+
+	devirtualized := make(map[*types.Func]*types.Func)
+	for m, concrete := range sources {
+		if concrete == nil || mentionedByExportedAPI(pass.Pkg, declaring[m]) {
+			continue
+		}
+		obj, index, _ := types.LookupFieldOrMethod(concrete, false /* addressable */, pass.Pkg, m.Name())
+		impl, ok := obj.(*types.Func)
+		// Only the methods declared directly on the concrete type are considered, since a method
+		// promoted from an embedded field is called on a different receiver.
+		if !ok || len(index) != 1 || impl.Pkg() != pass.Pkg || impl.Origin() != impl {
+			continue
+		}
+		devirtualized[m] = impl
+	}
+	return devirtualized
+}
+
+// declaringInterfaces returns a map from the methods of the non-generic unexported interfaces
+// declared at the package level of the given package to their declaring interfaces. Only these
+// methods are candidates for devirtualization.
+func declaringInterfaces(pkg *types.Package) map[*types.Func]*types.Named {
+	declaring := make(map[*types.Func]*types.Named)
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		tn, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || tn.IsAlias() || tn.Exported() {
+			continue
+		}
+		named, ok := tn.Type().(*types.Named)
+		if !ok || named.TypeParams().Len() > 0 {
+			continue
+		}
+		it, ok := named.Underlying().(*types.Interface)
+		if !ok {
+			continue
+		}
+		for i := 0; i < it.NumExplicitMethods(); i++ {
+			declaring[it.ExplicitMethod(i)] = named
+		}
+	}
+	return declaring
+}
+
+// mentionedByExportedAPI returns if the given type is mentioned by the types of the exported
+// objects of the given package (including the exported methods of its types), in which case the
+// other packages are able to pass values of their own types as its instances.
+func mentionedByExportedAPI(pkg *types.Package, target *types.Named) bool {
+	visited := make(map[types.Type]bool)
+	var mentions func(t types.Type) bool
+	mentions = func(t types.Type) bool {
+		if t == nil || visited[t] {
+			return false
+		}
+		visited[t] = true
+		switch t := t.(type) {
+		case *types.Named:
+			if t.Obj() == target.Obj() {
+				return true
+			}
+			// The exported types of the package are checked on their own.
+			return t.Obj().Pkg() == pkg && !t.Obj().Exported() && mentions(t.Underlying())
+		case *types.Pointer:
+			return mentions(t.Elem())
+		case *types.Slice:
+			return mentions(t.Elem())
+		case *types.Array:
+			return mentions(t.Elem())
+		case *types.Chan:
+			return mentions(t.Elem())
+		case *types.Map:
+			return mentions(t.Key()) || mentions(t.Elem())
+		case *types.Tuple:
+			for i := 0; i < t.Len(); i++ {
+				if mentions(t.At(i).Type()) {
+					return true
+				}
+			}
+		case *types.Signature:
+			return mentions(t.Params()) || mentions(t.Results())
+		case *types.Struct:
+			for i := 0; i < t.NumFields(); i++ {
+				if mentions(t.Field(i).Type()) {
+					return true
+				}
+			}
+		case *types.Interface:
+			for i := 0; i < t.NumEmbeddeds(); i++ {
+				if mentions(t.EmbeddedType(i)) {
+					return true
+				}
+			}
+			for i := 0; i < t.NumMethods(); i++ {
+				if mentions(t.Method(i).Type()) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		obj := scope.Lookup(name)
+		if tn, ok := obj.(*types.TypeName); ok {
+			if named, ok := tn.Type().(*types.Named); ok {
+				for i := 0; i < named.NumMethods(); i++ {
+					if m := named.Method(i); m.Exported() && mentions(m.Type()) {
+						return true
+					}
+				}
+			}
+			if !obj.Exported() {
+				continue
+			}
+			if mentions(tn.Type().Underlying()) {
+				return true
+			}
+			continue
+		}
+		if obj.Exported() && mentions(obj.Type()) {
+			return true
+		}
+	}
+	return false
+}
@@ -36,17 +36,20 @@ const _doc = "Build the trees of assertions for each function in this package, p
 // Analyzer here is the analyzer than generates assertions and passes them onto the accumulator to
 // be matched against annotations
 var Analyzer = &analysis.Analyzer{
-	Name:       "nilaway_assertion_analyzer",
-	Doc:        _doc,
-	Run:        analysishelper.WrapRun(run),
-	ResultType: reflect.TypeOf((*analysishelper.Result[[]annotation.FullTrigger])(nil)),
-	Requires:   []*analysis.Analyzer{config.Analyzer, function.Analyzer, affiliation.Analyzer, global.Analyzer},
+	Name:             "nilaway_assertion_analyzer",
+	Doc:              _doc,
+	Run:              analysishelper.WrapRun(run),
+	ResultType:       reflect.TypeOf((*analysishelper.Result[[]annotation.FullTrigger])(nil)),
+	Requires:         []*analysis.Analyzer{config.Analyzer, function.Analyzer, affiliation.Analyzer, global.Analyzer},
+	RunDespiteErrors: true,
 }
 
 func run(pass *analysis.Pass) ([]annotation.FullTrigger, error) {
 	conf := pass.ResultOf[config.Analyzer].(*config.Config)
 
-	if !conf.IsPkgInScope(pass.Pkg) {
+	// The real source of a stubbed package is not analyzed, its sites are determined by the
+	// annotations of the stub package instead (see config.Config.IsPkgStubbed).
+	if !conf.IsPkgInScope(pass.Pkg) || conf.IsPkgStubbed(pass.Pkg) {
 		return nil, nil
 	}
 
@@ -32,11 +32,12 @@ const _doc = "Collect variables from closure that are being assigned and/or acce
 
 // Analyzer collects a set of variables from closure for each function literal
 var Analyzer = &analysis.Analyzer{
-	Name:       "nilaway_anonymous_func_analyzer",
-	Doc:        _doc,
-	Run:        analysishelper.WrapRun(run),
-	ResultType: reflect.TypeOf((*analysishelper.Result[map[*ast.FuncLit]*FuncLitInfo])(nil)),
-	Requires:   []*analysis.Analyzer{config.Analyzer},
+	Name:             "nilaway_anonymous_func_analyzer",
+	Doc:              _doc,
+	Run:              analysishelper.WrapRun(run),
+	ResultType:       reflect.TypeOf((*analysishelper.Result[map[*ast.FuncLit]*FuncLitInfo])(nil)),
+	Requires:         []*analysis.Analyzer{config.Analyzer},
+	RunDespiteErrors: true,
 }
 
 // FuncLitInfo is the struct that stores auxiliary information (e.g., the closure variables it uses,
@@ -78,10 +79,20 @@ func run(pass *analysis.Pass) (map[*ast.FuncLit]*FuncLitInfo, error) {
 	funcLitMap := make(map[*ast.FuncLit]*FuncLitInfo)
 
 	for _, file := range pass.Files {
-		if !conf.IsFileInScope(file) || !conf.ExperimentalAnonymousFuncEnable {
+		if !conf.IsFileInScope(file) {
 			continue
 		}
 
+		// Without the experimental anonymous function support, we only collect the function
+		// literals that can be inlined at their call sites (see inlinableFuncLits).
+		var inlinable map[*ast.FuncLit]bool
+		if !conf.Experiment(config.ExperimentAnonymousFunction) {
+			inlinable = inlinableFuncLits(pass, file)
+			if len(inlinable) == 0 {
+				continue
+			}
+		}
+
 		// Search for top-level function literal declarations across all declarations in a file and call
 		// collectClosure on that, any further recursions will happen in collectClosure
 		closureMap := make(map[*ast.FuncLit][]*VarInfo)
@@ -94,6 +105,9 @@ func run(pass *analysis.Pass) (map[*ast.FuncLit]*FuncLitInfo, error) {
 		})
 
 		for funcLit, vars := range closureMap {
+			if inlinable != nil && !inlinable[funcLit] {
+				continue
+			}
 			fakeDecl, fakeType := createFakeFuncDecl(pass, funcLit, vars)
 
 			funcLitMap[funcLit] = &FuncLitInfo{
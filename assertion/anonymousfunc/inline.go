@@ -0,0 +1,172 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anonymousfunc
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"go.uber.org/nilaway/hook"
+	"golang.org/x/tools/go/analysis"
+)
+
+// inlinableFuncLits returns the set of function literals in the file that can be analyzed as if
+// they were inlined at their only call sites, even if the experimental anonymous function support
+// is disabled. These are the function literals that are either immediately invoked (e.g.,
+// `func() { ... }()`), or bound to a local variable that is used exactly once to call it (e.g.,
+// `f := func() { ... }; f()`). Since the closure variables are passed as arguments at the call
+// site, the nilability of the closure variables (e.g., a nil check before the call) is then
+// propagated into the body of the function literal. Similarly, the function literals passed as
+// callbacks to the trusted higher-order functions that invoke them before returning (e.g., the
+// less function in `sort.Slice`, see hook.AssumeCallback) are inlined at the calls, and the ones
+// invoked when the calling function returns (e.g., the closures registered via `t.Cleanup`) are
+// analyzed with their closure variables passed at the returns instead. Lastly, the
+// function literals stored in the function-typed struct fields of the package (e.g.,
+// `s.onEvent = func(e *Event) { ... }`) are analyzed as well, whose parameters and results are
+// connected to the calls of the fields (e.g., `s.onEvent(e)`) instead.
+//
+// Calls in `go` and `defer` statements are excluded since the function literals are executed at
+// a different time than the call sites. Function literals nested in other function literals are
+// included only if the enclosing ones are also inlinable, since otherwise their call sites are
+// not analyzed.
+func inlinableFuncLits(pass *analysis.Pass, file *ast.File) map[*ast.FuncLit]bool {
+	// First, count the uses of the local variables, and the uses that are direct calls.
+	uses, calls := make(map[*types.Var]int), make(map[*types.Var]int)
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.GoStmt, *ast.DeferStmt:
+			// The calls here are not counted, hence making the called variables not inlinable.
+			ast.Inspect(callOf(node), countUses(pass, uses))
+			return false
+		case *ast.CallExpr:
+			if ident, ok := node.Fun.(*ast.Ident); ok {
+				if v, ok := pass.TypesInfo.Uses[ident].(*types.Var); ok {
+					calls[v]++
+				}
+			}
+		case *ast.Ident:
+			countUses(pass, uses)(node)
+		}
+		return true
+	})
+
+	// Then, collect the inlinable function literals from the function declarations, descending
+	// only into the inlinable ones.
+	candidates := make(map[*ast.FuncLit]bool)
+	inlinable := make(map[*ast.FuncLit]bool)
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Body == nil {
+			continue
+		}
+		var visit func(node ast.Node) bool
+		visit = func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.GoStmt, *ast.DeferStmt:
+				// The function literals called here are not inlinable, but the arguments of the
+				// calls are still evaluated at the statements.
+				for _, arg := range callOf(node).Args {
+					ast.Inspect(arg, visit)
+				}
+				return false
+			case *ast.CallExpr:
+				if funcLit, ok := node.Fun.(*ast.FuncLit); ok {
+					candidates[funcLit] = true
+				}
+				if assumption, ok := hook.AssumeCallback(pass, node); ok && (assumption.Sync || assumption.Deferred) {
+					if funcLit, ok := ast.Unparen(node.Args[assumption.ArgNum]).(*ast.FuncLit); ok {
+						candidates[funcLit] = true
+					}
+				}
+			case *ast.KeyValueExpr:
+				if funcLit, ok := node.Value.(*ast.FuncLit); ok {
+					if key, ok := node.Key.(*ast.Ident); ok && isCallbackField(pass, pass.TypesInfo.Uses[key]) {
+						candidates[funcLit] = true
+					}
+				}
+			case *ast.AssignStmt:
+				if node.Tok == token.ASSIGN && len(node.Lhs) == len(node.Rhs) {
+					for i, rhs := range node.Rhs {
+						funcLit, ok := rhs.(*ast.FuncLit)
+						if !ok {
+							continue
+						}
+						if sel, ok := node.Lhs[i].(*ast.SelectorExpr); ok && isCallbackField(pass, pass.TypesInfo.ObjectOf(sel.Sel)) {
+							candidates[funcLit] = true
+						}
+					}
+				}
+				if node.Tok != token.DEFINE || len(node.Lhs) != len(node.Rhs) {
+					return true
+				}
+				for i, rhs := range node.Rhs {
+					funcLit, ok := rhs.(*ast.FuncLit)
+					if !ok {
+						continue
+					}
+					ident, ok := node.Lhs[i].(*ast.Ident)
+					if !ok {
+						continue
+					}
+					if v, ok := pass.TypesInfo.Defs[ident].(*types.Var); ok && uses[v] == 1 && calls[v] == 1 {
+						candidates[funcLit] = true
+					}
+				}
+			case *ast.FuncLit:
+				if !candidates[node] {
+					return false
+				}
+				inlinable[node] = true
+			}
+			return true
+		}
+		ast.Inspect(funcDecl.Body, visit)
+	}
+	return inlinable
+}
+
+// isCallbackField returns true if the object is a function-typed struct field of the package.
+func isCallbackField(pass *analysis.Pass, obj types.Object) bool {
+	field, ok := obj.(*types.Var)
+	if !ok || !field.IsField() || field.Pkg() != pass.Pkg {
+		return false
+	}
+	_, ok = field.Type().Underlying().(*types.Signature)
+	return ok
+}
+
+// countUses returns a visitor that counts the uses of local variables in the given map.
+func countUses(pass *analysis.Pass, uses map[*types.Var]int) func(ast.Node) bool {
+	return func(node ast.Node) bool {
+		if ident, ok := node.(*ast.Ident); ok {
+			if v, ok := pass.TypesInfo.Uses[ident].(*types.Var); ok {
+				uses[v]++
+			}
+		}
+		return true
+	}
+}
+
+// callOf returns the call expression of a `go` or `defer` statement.
+func callOf(stmt ast.Node) *ast.CallExpr {
+	switch stmt := stmt.(type) {
+	case *ast.GoStmt:
+		return stmt.Call
+	case *ast.DeferStmt:
+		return stmt.Call
+	}
+	return nil
+}
@@ -21,12 +21,15 @@ import (
 	"fmt"
 	"go/ast"
 	"go/types"
+	"maps"
 	"reflect"
 	"runtime/debug"
+	"slices"
 	"strings"
 	"sync"
 
 	"go.uber.org/nilaway/annotation"
+	"go.uber.org/nilaway/assertion/affiliation"
 	"go.uber.org/nilaway/assertion/anonymousfunc"
 	"go.uber.org/nilaway/assertion/function/assertiontree"
 	"go.uber.org/nilaway/assertion/function/functioncontracts"
@@ -35,6 +38,7 @@ import (
 	"go.uber.org/nilaway/util"
 	"go.uber.org/nilaway/util/analysishelper"
 	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
 	"golang.org/x/tools/go/analysis/passes/ctrlflow"
 	"golang.org/x/tools/go/cfg"
 )
@@ -50,12 +54,16 @@ var Analyzer = &analysis.Analyzer{
 	Doc:        _doc,
 	Run:        analysishelper.WrapRun(run),
 	ResultType: reflect.TypeOf((*analysishelper.Result[[]annotation.FullTrigger])(nil)),
+	// Packages with type errors are analyzed in a degraded mode, where the functions referencing
+	// broken objects are skipped.
+	RunDespiteErrors: true,
 	Requires: []*analysis.Analyzer{
 		config.Analyzer,
 		ctrlflow.Analyzer,
 		structfield.Analyzer,
 		anonymousfunc.Analyzer,
 		functioncontracts.Analyzer,
+		buildssa.Analyzer,
 	},
 }
 
@@ -65,6 +73,19 @@ var Analyzer = &analysis.Analyzer{
 // TODO: test how often (if ever) this is hit
 const _maxFuncSizeInBytes = 10000
 
+// IsTooLarge returns true if the function body exceeds the size limit, in which case the function
+// is skipped by the analyzer.
+func IsTooLarge(body *ast.BlockStmt) bool {
+	return int(body.Rbrace-body.Lbrace) > _maxFuncSizeInBytes
+}
+
+// funcGraph builds the CFG of the function body where every call is assumed to possibly return.
+// It is only used when the ctrlflow analyzer (which knows the no-return functions) has not run on
+// the package due to type errors.
+func funcGraph(body *ast.BlockStmt) *cfg.CFG {
+	return cfg.New(body, func(*ast.CallExpr) bool { return true })
+}
+
 // functionResult is the struct that stores the results for analyzing a function declaration.
 type functionResult struct {
 	// triggers is the slice of triggers generated from analyzing a particular function.
@@ -84,7 +105,7 @@ type functionResult struct {
 
 func run(pass *analysis.Pass) ([]annotation.FullTrigger, error) {
 	conf := pass.ResultOf[config.Analyzer].(*config.Config)
-	if !conf.IsPkgInScope(pass.Pkg) {
+	if !conf.IsPkgInScope(pass.Pkg) || conf.IsPkgStubbed(pass.Pkg) {
 		return nil, nil
 	}
 
@@ -94,11 +115,27 @@ func run(pass *analysis.Pass) ([]annotation.FullTrigger, error) {
 		// TODO: enable struct initialization flag (tracked in Issue #23).
 		// TODO: enable anonymous function flag.
 	} else {
-		functionConfig.EnableStructInitCheck = conf.ExperimentalStructInitEnable
-		functionConfig.EnableAnonymousFunc = conf.ExperimentalAnonymousFuncEnable
+		functionConfig.EnableStructInitCheck = conf.CheckStructInit != config.CheckStructInitOff
+		functionConfig.EnableGroupResults = conf.Experiment(config.ExperimentGroupResults)
 	}
-
-	ctrlflowResult := pass.ResultOf[ctrlflow.Analyzer].(*ctrlflow.CFGs)
+	functionConfig.DisableParseCache = conf.DisableParseCache
+	// Reduce the aggressiveness of the analysis under memory pressure instead of running out of
+	// memory: the assertion trees are bounded more tightly and the struct initializations are not
+	// tracked (the package is reported as degraded by the accumulation analyzer).
+	functionConfig.MaxTreeWidth = conf.EffectiveMaxTreeWidth()
+	if conf.Degraded {
+		functionConfig.EnableStructInitCheck = false
+	}
+	functionConfig.EnforcedNonNilFields = annotation.EnforcedNonNilFields(pass)
+	functionConfig.OkReceiverReads = assertiontree.OkReceiverReads(pass)
+	functionConfig.DebugDumpDir = conf.DebugDumpDir
+	functionConfig.DebugDumpFuncs = conf.DebugDumpFuncs
+	functionConfig.EnableReflectEscape = conf.ReflectEscape
+	functionConfig.ErrorOnNilableMapRead = conf.ErrorOnNilableMapRead
+
+	// The ctrlflow analyzer does not run on packages with type errors, in which case we build
+	// conservative CFGs (see funcGraph).
+	ctrlflowResult, _ := pass.ResultOf[ctrlflow.Analyzer].(*ctrlflow.CFGs)
 	anonymousFuncResult := pass.ResultOf[anonymousfunc.Analyzer].(*analysishelper.Result[map[*ast.FuncLit]*anonymousfunc.FuncLitInfo])
 	contractsResult := pass.ResultOf[functioncontracts.Analyzer].(*analysishelper.Result[functioncontracts.Map])
 	if err := errors.Join(anonymousFuncResult.Err, contractsResult.Err); err != nil {
@@ -107,6 +144,11 @@ func run(pass *analysis.Pass) ([]annotation.FullTrigger, error) {
 
 	funcLitMap, funcContracts := anonymousFuncResult.Res, contractsResult.Res
 
+	// The calls to the interface methods with a single implementation in the package are
+	// devirtualized, such that the contracts of the implementations apply at them.
+	devirtualized := affiliation.Devirtualize(pass)
+	funcContracts = devirtualizeContracts(funcContracts, devirtualized)
+
 	// Create a fake ident map for the fake func decl nodes to be shared for all function contexts.
 	pkgFakeIdentMap := make(map[*ast.Ident]types.Object)
 	for _, info := range funcLitMap {
@@ -127,26 +169,26 @@ func run(pass *analysis.Pass) ([]annotation.FullTrigger, error) {
 			continue
 		}
 
-		// Collect all function declarations and function literals if anonymous function support
-		// is enabled.
+		// Collect all function declarations, and the function literals collected by the anonymous
+		// function analyzer: all of them if anonymous function support is enabled, otherwise only
+		// the ones that can be inlined at their call sites.
 		var funcs []ast.Node
 		for _, decl := range file.Decls {
 			if f, ok := decl.(*ast.FuncDecl); ok {
 				funcs = append(funcs, f)
 			}
 		}
-		if functionConfig.EnableAnonymousFunc {
-			// We need a stable order of triggers for inference. However, the
-			// fake func decl nodes generated from the anonymous function analyzer are stored in
-			// a map. Hence, here we traverse the file and append the fake func decl nodes in
-			// depth-first order.
-			ast.Inspect(file, func(node ast.Node) bool {
-				if f, ok := node.(*ast.FuncLit); ok {
+		// We need a stable order of triggers for inference. However, the fake func decl nodes
+		// generated from the anonymous function analyzer are stored in a map. Hence, here we
+		// traverse the file and append the fake func decl nodes in depth-first order.
+		ast.Inspect(file, func(node ast.Node) bool {
+			if f, ok := node.(*ast.FuncLit); ok {
+				if _, ok := funcLitMap[f]; ok {
 					funcs = append(funcs, f)
 				}
-				return true
-			})
-		}
+			}
+			return true
+		})
 
 		for _, fun := range funcs {
 			// Retrieve the auxiliary information about a function to be analyzed, since it is
@@ -158,14 +200,20 @@ func run(pass *analysis.Pass) ([]annotation.FullTrigger, error) {
 			)
 			switch f := fun.(type) {
 			case *ast.FuncDecl:
-				funcDecl, funcLit, graph = f, nil, ctrlflowResult.FuncDecl(f)
+				funcDecl, funcLit = f, nil
+				if ctrlflowResult != nil {
+					graph = ctrlflowResult.FuncDecl(f)
+				}
 			case *ast.FuncLit:
 				info, ok := funcLitMap[f]
 				if !ok {
 					panic(fmt.Sprintf("no func lit info found for anonymous function %v", pass.Fset.Position(f.Pos())))
 				}
 
-				funcDecl, funcLit, graph = info.FakeFuncDecl, f, ctrlflowResult.FuncLit(f)
+				funcDecl, funcLit = info.FakeFuncDecl, f
+				if ctrlflowResult != nil {
+					graph = ctrlflowResult.FuncLit(f)
+				}
 			default:
 				panic(fmt.Sprintf("unrecognized function type %T", f))
 			}
@@ -175,10 +223,16 @@ func run(pass *analysis.Pass) ([]annotation.FullTrigger, error) {
 				continue
 			}
 			// Skip if the function is too large.
-			funcSizeInBytes := int(funcDecl.Body.Rbrace - funcDecl.Body.Lbrace)
-			if funcSizeInBytes > _maxFuncSizeInBytes {
+			if IsTooLarge(funcDecl.Body) {
 				continue
 			}
+			// Skip if the package has type errors and the function references broken objects.
+			if len(pass.TypeErrors) > 0 && analysishelper.ReferencesBrokenObjects(pass.TypesInfo, fun) {
+				continue
+			}
+			if graph == nil {
+				graph = funcGraph(funcDecl.Body)
+			}
 
 			// Now, analyze the function declarations concurrently.
 			wg.Add(1)
@@ -223,8 +277,8 @@ func run(pass *analysis.Pass) ([]annotation.FullTrigger, error) {
 
 	// Duplicate triggers in contracted functions in the callers of the function
 	if len(funcContracts) != 0 {
-		duplicateFullTriggersFromContractedFunctionsToCallers(pass, funcContracts, funcTriggers,
-			funcResults)
+		duplicateFullTriggersFromContractedFunctionsToCallers(pass, funcContracts, devirtualized,
+			funcTriggers, funcResults)
 	}
 
 	// Flatten the triggers
@@ -246,6 +300,7 @@ func run(pass *analysis.Pass) ([]annotation.FullTrigger, error) {
 func duplicateFullTriggersFromContractedFunctionsToCallers(
 	pass *analysis.Pass,
 	funcContracts functioncontracts.Map,
+	devirtualized map[*types.Func]*types.Func,
 	funcTriggers [][]annotation.FullTrigger,
 	funcResults map[*types.Func]*functionResult,
 ) {
@@ -279,7 +334,11 @@ func duplicateFullTriggersFromContractedFunctionsToCallers(
 	// return) into all the callers
 	dupTriggers := map[*types.Func][]annotation.FullTrigger{}
 	for ctrtFunc, calls := range callsByCtrtFunc {
+		// The triggers of a devirtualized interface method are the ones of its implementation.
 		r := funcResults[ctrtFunc]
+		if impl, ok := devirtualized[ctrtFunc]; ok {
+			r = funcResults[impl]
+		}
 		if r == nil {
 			// The contracted function is imported from upstream, and the local package analysis
 			// does not involve it.
@@ -352,11 +411,11 @@ func duplicateFullTrigger(
 		CreatedFromDuplication: true,
 	}
 	if isParamProducer {
-		dupTrigger.Producer = annotation.DuplicateParamProducer(trigger.Producer, argLoc)
+		dupTrigger.Producer = annotation.DuplicateParamProducer(trigger.Producer, callee, argLoc)
 	}
 	if isReturnConsumer {
 		retLoc := util.PosToLocation(callExpr.Pos(), pass)
-		dupTrigger.Consumer = annotation.DuplicateReturnConsumer(trigger.Consumer, retLoc)
+		dupTrigger.Consumer = annotation.DuplicateReturnConsumer(trigger.Consumer, callee, retLoc)
 		// Set up the site that controls the controlled full trigger to be created
 		c := annotation.NewCallSiteParamKey(callee, 0, argLoc)
 		dupTrigger.Controller = c
@@ -403,16 +462,36 @@ func findCallsToContractedFunctions(
 	return calls
 }
 
+// devirtualizeContracts returns the given contracts extended with the contracts of the
+// implementations of the devirtualized interface methods (see affiliation.Devirtualize) for the
+// interface methods. The given contracts are not modified, since they are shared with the other
+// analyzers.
+func devirtualizeContracts(funcContracts functioncontracts.Map, devirtualized map[*types.Func]*types.Func) functioncontracts.Map {
+	var extended functioncontracts.Map
+	for iface, impl := range devirtualized {
+		ctrts, ok := funcContracts[impl]
+		if !ok {
+			continue
+		}
+		if extended == nil {
+			extended = maps.Clone(funcContracts)
+		}
+		extended[iface] = ctrts
+	}
+	if extended == nil {
+		return funcContracts
+	}
+	return extended
+}
+
 // hasOnlyNonNilToNonNilContract returns whether the given function has only one contract that is
-// nonnil->nonnil.
+// nonnil->nonnil, besides contract(pure) which does not affect the nilability of the results.
 func hasOnlyNonNilToNonNilContract(funcContracts functioncontracts.Map, funcObj *types.Func) bool {
-	contracts, ok := funcContracts[funcObj]
-	if !ok || len(contracts) != 1 {
+	contracts := slices.DeleteFunc(slices.Clone(funcContracts[funcObj]), functioncontracts.Contract.IsPure)
+	if len(contracts) != 1 {
 		return false
 	}
-	ctr := contracts[0]
-	return len(ctr.Ins) == 1 && ctr.Ins[0] == functioncontracts.NonNil &&
-		len(ctr.Outs) == 1 && ctr.Outs[0] == functioncontracts.NonNil
+	return contracts[0].IsNonNilToNonNil()
 }
 
 // analyzeFunc analyzes a given function declaration and emit generated triggers, or an error if
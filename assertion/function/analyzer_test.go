@@ -77,7 +77,6 @@ func TestCancelledContext(t *testing.T) {
 	// not really require such features).
 	funcConfig := assertiontree.FunctionConfig{
 		EnableStructInitCheck: true,
-		EnableAnonymousFunc:   true,
 	}
 	// (2) Construct an empty function context. In normal NilAway execution the func lit map and
 	// pkg fake ident map will be created from the separate anonymous function analyzer. However,
@@ -168,7 +167,6 @@ func TestBackpropFixpointConvergence(t *testing.T) {
 		// Prepare the input variables for passing to BackpropAcrossFunc():
 		funcConfig := assertiontree.FunctionConfig{
 			EnableStructInitCheck: true,
-			EnableAnonymousFunc:   true,
 		}
 		emptyFuncLitMap := make(map[*ast.FuncLit]*anonymousfunc.FuncLitInfo)
 		emptyPkgFakeIdentMap := make(map[*ast.Ident]types.Object)
@@ -28,6 +28,7 @@ import (
 	"go.uber.org/nilaway/annotation"
 	"go.uber.org/nilaway/assertion/function/preprocess"
 	"go.uber.org/nilaway/config"
+	"go.uber.org/nilaway/hook"
 	"go.uber.org/nilaway/util"
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/ast/astutil"
@@ -35,12 +36,16 @@ import (
 )
 
 // backpropAcrossBlock iterates over all nodes in the CFG block in _reverse_ order, writes logs,
-// and delegates the handling of each node to backpropAcrossNode.
-func backpropAcrossBlock(rootNode *RootAssertionNode, block *cfg.Block) error {
+// and delegates the handling of each node to backpropAcrossNode. The effects are applied around
+// the back-propagation of the nodes they are registered for (see nodeEffects).
+func backpropAcrossBlock(rootNode *RootAssertionNode, block *cfg.Block, effects *nodeEffects) error {
 	// Iterate over all blocks in _reverse_ order
 	for i := len(block.Nodes) - 1; i >= 0; i-- {
 		node := block.Nodes[i]
 
+		if f, ok := effects.before[node]; ok {
+			f(rootNode)
+		}
 		err := backpropAcrossNode(rootNode, node)
 		if err != nil {
 			pos := rootNode.Pass().Fset.Position(node.Pos())
@@ -51,6 +56,12 @@ func backpropAcrossBlock(rootNode *RootAssertionNode, block *cfg.Block) error {
 				pos.Filename, pos.Line, pos.Column, node, err,
 			)
 		}
+		if f, ok := effects.after[node]; ok {
+			f(rootNode)
+		}
+
+		// Bound the width of the tree, if configured, to prevent explosion in large functions.
+		rootNode.summarizeExcessWidth()
 	}
 
 	return nil
@@ -60,13 +71,45 @@ func backpropAcrossBlock(rootNode *RootAssertionNode, block *cfg.Block) error {
 // different types. For some complicated cases, it further delegates the handling to other
 // finer-grained backpropX functions for better code clarity.
 func backpropAcrossNode(rootNode *RootAssertionNode, node ast.Node) error {
+	// the results populated by the goroutines of a group are available once the group is waited
+	// for (if enabled, see groupResults)
+	populateGroupResults(rootNode, node)
+
 	switch n := node.(type) {
 	case *ast.ParenExpr:
 		return backpropAcrossNode(rootNode, n.X)
 	case *ast.ReturnStmt:
 		return backpropAcrossReturn(rootNode, n)
 	case *ast.AssignStmt:
-		return backpropAcrossAssignment(rootNode, n.Lhs, n.Rhs)
+		// A store with its result assigned (e.g., `old := p.Swap(v)`) happens after the result is
+		// computed, hence it is handled first in backpropagation.
+		if len(n.Rhs) == 1 {
+			if call, ok := ast.Unparen(n.Rhs[0]).(*ast.CallExpr); ok {
+				if err := backpropAcrossStore(rootNode, call); err != nil {
+					return err
+				}
+			}
+		}
+		if err := backpropAcrossAssignment(rootNode, n.Lhs, n.Rhs); err != nil {
+			return err
+		}
+		// The functions stored in the function-typed struct fields are connected to the field
+		// callback sites (e.g., `s.onEvent = handle`).
+		if len(n.Lhs) == len(n.Rhs) {
+			for i, lhs := range n.Lhs {
+				if sel, ok := ast.Unparen(lhs).(*ast.SelectorExpr); ok {
+					if field := rootNode.callbackFieldOf(sel); field != nil {
+						rootNode.addFieldCallbackFlows(field, n.Rhs[i])
+					}
+				}
+			}
+		}
+		// The destinations are scanned into during the calls, i.e., before the assignment.
+		for _, rhs := range n.Rhs {
+			if err := backpropAcrossScans(rootNode, rhs); err != nil {
+				return err
+			}
+		}
 	case *ast.ValueSpec:
 		// These nodes represent declarations such as `var x, y : int = 4, 3`
 		if len(n.Names) > 0 && len(n.Values) > 0 {
@@ -75,11 +118,33 @@ func backpropAcrossNode(rootNode *RootAssertionNode, node ast.Node) error {
 				return err
 			}
 		}
+		// The declarations without values (e.g., `var s T`) construct zero values.
+		if len(n.Values) == 0 {
+			for _, name := range n.Names {
+				rootNode.consumeZeroValueEnforcedFields(rootNode.Pass().TypesInfo.TypeOf(name), name)
+			}
+		}
 	case *ast.SendStmt:
 		return backpropAcrossSend(rootNode, n)
 	case *ast.ExprStmt:
+		if call, ok := ast.Unparen(n.X).(*ast.CallExpr); ok {
+			if err := backpropAcrossStore(rootNode, call); err != nil {
+				return err
+			}
+		}
+		if call := rootNode.asBuiltinCall(n.X, util.BuiltinCopy); call != nil {
+			if err := backpropAcrossCopy(rootNode, call); err != nil {
+				return err
+			}
+		}
+		if err := backpropAcrossScans(rootNode, n.X); err != nil {
+			return err
+		}
 		rootNode.AddComputation(n.X)
+	case *ast.DeferStmt:
+		backpropAcrossDefer(rootNode, n)
 	case *ast.GoStmt:
+		consumeLoopVarCaptures(rootNode, n)
 		rootNode.AddComputation(n.Call)
 	case *ast.IncDecStmt:
 		rootNode.AddComputation(n.X)
@@ -106,9 +171,8 @@ func backpropAcrossNode(rootNode *RootAssertionNode, node ast.Node) error {
 		}
 	// The following cases are not interesting to our nilness analysis, or are currently
 	// unsupported, so we do nothing for them.
-	case *ast.BasicLit, *ast.Ident, *ast.EmptyStmt, *ast.DeferStmt:
+	case *ast.BasicLit, *ast.Ident, *ast.EmptyStmt:
 		// TODO: figure out what source code generates these cases - it's not obvious
-		// TODO: handle defers
 	default:
 		return fmt.Errorf("unrecognized AST node %T in CFG - add a case for it", n)
 	}
@@ -116,6 +180,22 @@ func backpropAcrossNode(rootNode *RootAssertionNode, node ast.Node) error {
 	return nil
 }
 
+// backpropAcrossDefer handles backpropagation for defer statements. It is designed to be called
+// from backpropAcrossNode as a special handler. The deferred call itself is executed when the
+// function returns, but its arguments (e.g., `p.ID` in `defer delete(m, p.ID)`) and the receiver
+// of a deferred method (e.g., `s.mus[p.ID]` in `defer s.mus[p.ID].Unlock()`) are evaluated at the
+// defer statement, hence the dereferences and calls in them are computed here.
+func backpropAcrossDefer(rootNode *RootAssertionNode, node *ast.DeferStmt) {
+	if sel, ok := ast.Unparen(node.Call.Fun).(*ast.SelectorExpr); ok {
+		if id, ok := sel.X.(*ast.Ident); !ok || !rootNode.isPkgName(id) {
+			rootNode.AddComputation(sel.X)
+		}
+	}
+	for _, arg := range node.Call.Args {
+		rootNode.AddComputation(arg)
+	}
+}
+
 // backpropAcrossSend handles backpropagation for send statements. It is designed to be called from
 // backpropAcrossNode as a special handler.
 func backpropAcrossSend(rootNode *RootAssertionNode, node *ast.SendStmt) error {
@@ -126,7 +206,7 @@ func backpropAcrossSend(rootNode *RootAssertionNode, node *ast.SendStmt) error {
 	// channel variable. For (2), since we do not track the state of the channels, we currently
 	// cannot support it.
 	// TODO: rethink our strategy of handling channels (#192).
-	consumer, err := exprAsAssignmentConsumer(rootNode, node, nil)
+	consumer, err := exprAsAssignmentConsumer(rootNode, node)
 	if err != nil {
 		return err
 	}
@@ -144,6 +224,58 @@ func backpropAcrossSend(rootNode *RootAssertionNode, node *ast.SendStmt) error {
 	return nil
 }
 
+// backpropAcrossStore handles backpropagation for calls that store a value to be loaded back later
+// (see hook.AssumeStore). Such a call (e.g., `p.Store(v)` of an `atomic.Pointer`) is handled as
+// the assignment `p.Load() = v`, such that the loads after storing a nonnil value are known to be
+// nonnil. It is designed to be called from backpropAcrossNode as a special handler.
+func backpropAcrossStore(rootNode *RootAssertionNode, call *ast.CallExpr) error {
+	load, value := hook.AssumeStore(rootNode.Pass(), call)
+	if load == nil {
+		return nil
+	}
+	loadCall := &ast.CallExpr{
+		Fun: &ast.SelectorExpr{
+			X:   call.Fun.(*ast.SelectorExpr).X,
+			Sel: rootNode.GetDeclaringIdent(load),
+		},
+		Lparen: call.Lparen,
+		Rparen: call.Rparen,
+	}
+	return backpropAcrossOneToOneAssignment(rootNode, []ast.Expr{loadCall}, []ast.Expr{value})
+}
+
+// backpropAcrossScans handles backpropagation for the calls in the expression that scan values into
+// the destinations whose addresses are passed (see hook.AssumeScan). Such a call (e.g.,
+// `db.First(&u)` of GORM, possibly chained as in `db.First(&u).Error`) is handled as the
+// assignment of the scanned values to the destinations, such that they are not considered
+// unassigned after the call. It is designed to be called from backpropAcrossNode as a special
+// handler.
+func backpropAcrossScans(rootNode *RootAssertionNode, expr ast.Expr) error {
+	var calls []*ast.CallExpr
+	ast.Inspect(expr, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncLit:
+			// The calls in the function literals are not executed here.
+			return false
+		case *ast.CallExpr:
+			calls = append(calls, node)
+		}
+		return true
+	})
+	// The inner calls (e.g., the receivers of the chained calls) are executed first, hence they are
+	// handled last in backpropagation.
+	for _, call := range calls {
+		dests, values := hook.AssumeScan(rootNode.Pass(), call)
+		if len(dests) == 0 {
+			continue
+		}
+		if err := backpropAcrossOneToOneAssignment(rootNode, dests, values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // backpropAcrossReturn handles backpropagation for return statements. It is designed to be called
 // from backpropAcrossNode as a special handler.
 func backpropAcrossReturn(rootNode *RootAssertionNode, node *ast.ReturnStmt) error {
@@ -154,6 +286,9 @@ func backpropAcrossReturn(rootNode *RootAssertionNode, node *ast.ReturnStmt) err
 		rootNode.addConsumptionsForFieldsOfParams()
 	}
 
+	consumeDeferredResultAssigns(rootNode, node)
+	consumeDeferredCallbacks(rootNode, node)
+
 	if len(node.Results) == 1 {
 		if call, ok := node.Results[0].(*ast.CallExpr); ok {
 			var fident *ast.Ident
@@ -287,6 +422,13 @@ func backpropAcrossAssignment(rootNode *RootAssertionNode, lhs, rhs []ast.Expr)
 			rootNode.AddComputation(rhsVal)
 		}
 		for _, lhsVal := range lhs {
+			// a map index written to is consumed as such (see exprAsConsumedByAssignment) instead
+			// of as a read from the map
+			if index, ok := astutil.Unparen(lhsVal).(*ast.IndexExpr); ok && exprAsConsumedByAssignment(rootNode, index) != nil {
+				rootNode.AddComputation(index.X)
+				rootNode.AddComputation(index.Index)
+				continue
+			}
 			rootNode.AddComputation(lhsVal)
 		}
 	}()
@@ -317,6 +459,13 @@ func backpropAcrossAssignment(rootNode *RootAssertionNode, lhs, rhs []ast.Expr)
 			return backpropAcrossRange(rootNode, lhs, r.X)
 		}
 
+		// Ok-returning methods wrapping reads of their receiver maps, e.g., `v, ok := w.Get(k)`
+		// (see OkReceiverReads), where the receiver `w` is nonnil if `ok` is true just like the
+		// map in a map read. The assignment itself is handled as a normal assignment below.
+		if recv := okReceiverReadRecv(rootNode, rhsNode); recv != nil {
+			rootNode.AddGuardMatch(recv, ProduceAsNonnil)
+		}
+
 		// Now we handle special cases for "ok" contracts, the lhs must have length of 2, the first
 		// being the processed variable and second being the `ok` boolean. Specifically, we
 		// currently handle the following cases in NilAway:
@@ -649,6 +798,7 @@ buildShadowMask:
 					if ok && lhsNode != nil {
 						// Add assignment entries to the consumers of lhsNode for informative printing of errors
 						for _, c := range lhsNode.ConsumeTriggers() {
+							convertInterfaceRecvConsumer(rootNode, rootNode.Pass().TypesInfo.TypeOf(rhsVal), c)
 							err := addAssignmentToConsumer(lhsVal, rhsVal, rootNode.Pass(), c.Annotation)
 							if err != nil {
 								return err
@@ -678,6 +828,36 @@ buildShadowMask:
 							rootNode.addProductionsForAssignmentFields(fieldProducers, lhsVal)
 						}
 
+						// The elements of a slice assigned from an `append` call may be any of the
+						// trackable appended values, which are left out of its merged deep producer
+						// (see parseAppendAsProducer), so the assertions on the indices of lhsVal
+						// also land on (copies of) each of them.
+						if call := rootNode.asBuiltinCall(rhsVal, util.BuiltinAppend); call != nil {
+							if lhsNode, _ := rootNode.lookupPath(lpath); lhsNode != nil {
+								for _, value := range appendedValues(call) {
+									for _, child := range lhsNode.Children() {
+										if _, ok := child.(*indexAssertionNode); !ok {
+											continue
+										}
+										vpath, _ := rootNode.ParseExprAsProducer(value, false)
+										if vpath == nil {
+											break
+										}
+										landed := CopyNode(child)
+										for _, c := range landed.ConsumeTriggers() {
+											if err := addAssignmentToConsumer(lhsVal, rhsVal, rootNode.Pass(), c.Annotation); err != nil {
+												return err
+											}
+										}
+										landings = append(landings, deferredLanding{
+											lhsNode: landed,
+											rhs:     vpath,
+										})
+									}
+								}
+							}
+						}
+
 						// beforeTriggersLastIndex is used to find the newly added triggers on the next line
 						beforeTriggersLastIndex := len(rootNode.triggers)
 
@@ -693,6 +873,7 @@ buildShadowMask:
 							continue
 						}
 						for _, t := range rootNode.triggers[beforeTriggersLastIndex:len(rootNode.triggers)] {
+							convertInterfaceRecvConsumer(rootNode, rootNode.Pass().TypesInfo.TypeOf(rhsVal), t.Consumer)
 							err := addAssignmentToConsumer(lhsVal, rhsVal, rootNode.Pass(), t.Consumer.Annotation)
 							if err != nil {
 								return err
@@ -716,7 +897,7 @@ buildShadowMask:
 	for i := range rhs {
 		lhsVal, rhsVal := lhs[i], rhs[i]
 		// Check whether a consumption trigger needs to be added for a field assignment here
-		consumeTrigger, err := exprAsAssignmentConsumer(rootNode, lhsVal, rhsVal)
+		consumeTrigger, err := exprAsAssignmentConsumer(rootNode, lhsVal)
 		if err != nil {
 			return err
 		}
@@ -730,11 +911,104 @@ buildShadowMask:
 		if consumer := exprAsConsumedByAssignment(rootNode, lhsVal); consumer != nil {
 			rootNode.AddConsumption(consumer)
 		}
+		if call := rootNode.asBuiltinCall(rhsVal, util.BuiltinAppend); call != nil {
+			if err := consumeAppendedElems(rootNode, lhsVal, call); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
 }
 
+// consumeAppendedElems handles the elements appended into the slice `lhs` by the assignment
+// `lhs = append(s, x, y...)`, which are assigned into it deeply as in `lhs[i] = x`: the value `x` is
+// consumed as such, and the elements of `s` and `y` are consumed deeply. It is designed to be
+// called from backpropAcrossOneToOneAssignment as a finer-grained handler for append calls.
+func consumeAppendedElems(rootNode *RootAssertionNode, lhs ast.Expr, call *ast.CallExpr) error {
+	if len(call.Args) == 0 {
+		// not type-correct, and hence not analyzed
+		return nil
+	}
+	consumer, err := sliceAsElemsAssignmentConsumer(rootNode, lhs)
+	if err != nil || consumer == nil {
+		return err
+	}
+
+	for _, value := range appendedValues(call) {
+		rootNode.AddConsumption(&annotation.ConsumeTrigger{
+			Annotation: consumer.Copy(),
+			Expr:       value,
+			Guards:     util.NoGuards(),
+		})
+	}
+	srcs := []ast.Expr{call.Args[0]}
+	if call.Ellipsis != token.NoPos {
+		srcs = append(srcs, call.Args[len(call.Args)-1])
+	}
+	for _, src := range srcs {
+		addDeepAssignmentTriggers(rootNode, consumer, src)
+	}
+	return nil
+}
+
+// addDeepAssignmentTriggers adds the triggers for the elements of the slice `src` deeply assigned
+// by the given consumer, e.g., into the elements of `s` by `s = append(s, src...)`.
+func addDeepAssignmentTriggers(rootNode *RootAssertionNode, consumer annotation.ConsumingAnnotationTrigger, src ast.Expr) {
+	for _, producer := range exprAsDeepProducers(rootNode, src) {
+		rootNode.AddNewTriggers(annotation.FullTrigger{
+			Producer: producer,
+			Consumer: &annotation.ConsumeTrigger{
+				Annotation: consumer.Copy(),
+				Expr:       src,
+				Guards:     util.NoGuards(),
+			},
+		})
+	}
+}
+
+// backpropAcrossCopy handles backpropagation for calls to the builtin `copy` (e.g., `copy(dst,
+// src)`), which assign the elements of `src` into those of `dst`. The elements of `src` are
+// consumed deeply as in `dst[i] = src[j]`, and the assertions on the indices of `dst` (e.g., from
+// a later `*dst[0]`) are matched with the deep producers of `src` as well, while still flowing to
+// the elements `dst` had before the call, since only some of them may be overwritten. It is
+// designed to be called from backpropAcrossNode as a special handler.
+func backpropAcrossCopy(rootNode *RootAssertionNode, call *ast.CallExpr) error {
+	if len(call.Args) != 2 {
+		return nil
+	}
+	dst, src := call.Args[0], call.Args[1]
+
+	consumer, err := sliceAsElemsAssignmentConsumer(rootNode, dst)
+	if err != nil {
+		return err
+	}
+	if consumer != nil {
+		addDeepAssignmentTriggers(rootNode, consumer, src)
+	}
+
+	dstPath, _ := rootNode.ParseExprAsProducer(dst, false)
+	dstNode, _ := rootNode.lookupPath(dstPath)
+	if dstNode == nil {
+		return nil
+	}
+	producers := exprAsDeepProducers(rootNode, src)
+	for _, child := range dstNode.Children() {
+		if _, ok := child.(*indexAssertionNode); !ok {
+			continue
+		}
+		for _, c := range child.ConsumeTriggers() {
+			for _, producer := range producers {
+				rootNode.AddNewTriggers(annotation.FullTrigger{
+					Producer: producer,
+					Consumer: c.Copy(),
+				})
+			}
+		}
+	}
+	return nil
+}
+
 // backpropAcrossManyToOneAssignment handles normal many-to-one assignment (e.g, "a, b := foo()"),
 // it is designed to be called from backpropAcrossAssignment as a finer-grained handler for
 // many-to-one normal assignments.
@@ -783,7 +1057,12 @@ func backpropAcrossManyToOneAssignment(rootNode *RootAssertionNode, lhs, rhs []a
 
 		// Update consumers of newly added triggers with assignment entries for informative printing of errors
 		if len(rootNode.triggers) > 0 {
+			var rhsType types.Type
+			if tuple, ok := rootNode.Pass().TypesInfo.TypeOf(rhsVal).(*types.Tuple); ok && i < tuple.Len() {
+				rhsType = tuple.At(i).Type()
+			}
 			for _, t := range rootNode.triggers[beforeTriggersLastIndex:len(rootNode.triggers)] {
+				convertInterfaceRecvConsumer(rootNode, rhsType, t.Consumer)
 				err := addAssignmentToConsumer(lhsVal, rhsVal, rootNode.Pass(), t.Consumer.Annotation)
 				if err != nil {
 					return err
@@ -792,7 +1071,7 @@ func backpropAcrossManyToOneAssignment(rootNode *RootAssertionNode, lhs, rhs []a
 		}
 
 		// Phase 2
-		consumeTrigger, err := exprAsAssignmentConsumer(rootNode, lhsVal, rhsVal)
+		consumeTrigger, err := exprAsAssignmentConsumer(rootNode, lhsVal)
 		if err != nil {
 			return err
 		}
@@ -872,13 +1151,20 @@ func BackpropAcrossFunc(
 ) ([]annotation.FullTrigger, int, int, error) {
 	// We transform the CFG to have it reflect the implicit control flow that happens
 	// inside short-circuiting boolean expressions.
-	preprocessor := preprocess.New(pass)
+	preprocessor := preprocess.New(pass, functionContext.funcContracts)
 	graph = preprocessor.CFG(graph, functionContext.funcDecl)
 
+	dumper := newDebugDumper(pass, decl, functionContext.functionConfig)
+	if err := dumper.dumpCFG(graph); err != nil {
+		return nil, 0, 0, err
+	}
+
 	// Generate rick check effects.
 	richCheckBlocks, exprNonceMap := genInitialRichCheckEffects(graph, functionContext)
 	richCheckBlocks = propagateRichChecks(graph, richCheckBlocks)
 	blocks, preprocessing := blocksAndPreprocessingFromCFG(pass, graph, richCheckBlocks)
+	funcObj, _ := pass.TypesInfo.ObjectOf(decl.Name).(*types.Func)
+	effects := nodeEffectsFromRichChecks(funcObj, graph, richCheckBlocks)
 
 	// The assertion nodes for each block and an array of bools to indicate whether each block is
 	// updated in this round or not.
@@ -978,7 +1264,7 @@ func BackpropAcrossFunc(
 
 			// Now, the final processed node is in succs[0], we can back-propagate across it.
 			nextAssertions[i] = succs[0]
-			err := backpropAcrossBlock(nextAssertions[i], blocks[i])
+			err := backpropAcrossBlock(nextAssertions[i], blocks[i], effects)
 			if err != nil {
 				return nil, roundCount, stableRoundCount, err
 			}
@@ -1011,6 +1297,9 @@ func BackpropAcrossFunc(
 			nextRootAssertionNode = CopyNode(nextAssertions[0]).(*RootAssertionNode)
 			nextRootAssertionNode.ProcessEntry()
 		}
+		if err := dumper.dumpRound(roundCount, nextAssertions); err != nil {
+			return nil, roundCount, stableRoundCount, err
+		}
 
 		if nextRootAssertionNode == nil && currRootAssertionNode == nil ||
 			(nextRootAssertionNode != nil && currRootAssertionNode != nil &&
@@ -1040,8 +1329,12 @@ func BackpropAcrossFunc(
 	}
 
 	// Return the generated full triggers at the entry block; we're done!
-	if currRootAssertionNode == nil {
-		return nil, roundCount, stableRoundCount, nil
+	var triggers []annotation.FullTrigger
+	if currRootAssertionNode != nil {
+		triggers = currRootAssertionNode.triggers
+	}
+	if err := dumper.dumpTriggers(triggers); err != nil {
+		return nil, roundCount, stableRoundCount, err
 	}
-	return currRootAssertionNode.triggers, roundCount, stableRoundCount, nil
+	return triggers, roundCount, stableRoundCount, nil
 }
@@ -23,6 +23,7 @@ import (
 	"go/types"
 
 	"go.uber.org/nilaway/annotation"
+	"go.uber.org/nilaway/config"
 	"go.uber.org/nilaway/hook"
 	"go.uber.org/nilaway/util"
 	"go.uber.org/nilaway/util/asthelper"
@@ -84,6 +85,37 @@ type preprocessPair struct {
 	falseBranchFunc RootFunc
 }
 
+// nodeEffects bundles the functions that modify a *RootAssertionNode when specific nodes are
+// back-propagated, in addition to the regular back-propagation of the nodes. The functions in
+// `before` are applied right before the node is back-propagated (i.e., to the assertions that hold
+// right after the node executes), and the functions in `after` right after.
+type nodeEffects struct {
+	before map[ast.Node]RootFunc
+	after  map[ast.Node]RootFunc
+}
+
+// addBefore adds a function to apply right before the node is back-propagated.
+func (e *nodeEffects) addBefore(node ast.Node, f RootFunc) {
+	if e.before == nil {
+		e.before = make(map[ast.Node]RootFunc)
+	}
+	if existing, ok := e.before[node]; ok {
+		f = composeRootFuncs(existing, f)
+	}
+	e.before[node] = f
+}
+
+// addAfter adds a function to apply right after the node is back-propagated.
+func (e *nodeEffects) addAfter(node ast.Node, f RootFunc) {
+	if e.after == nil {
+		e.after = make(map[ast.Node]RootFunc)
+	}
+	if existing, ok := e.after[node]; ok {
+		f = composeRootFuncs(existing, f)
+	}
+	e.after[node] = f
+}
+
 const knownNilableErrFunc = "sometimesErrs"
 
 // exprCallsKnownNilableErrFunc checks if expression calls a function that we know to be nilable without
@@ -463,6 +495,157 @@ func exprAsConsumedByAssignment(rootNode *RootAssertionNode, expr ast.Node) *ann
 	return nil
 }
 
+// exprAsDeepAssignmentConsumer returns the consumer for a value assigned into an index of the
+// passed expression, e.g., `x` assigned in `a[i] = x`, `*a = x`, or `a <- x` for the expression `a`.
+// nilable(result 0)
+func exprAsDeepAssignmentConsumer(rootNode *RootAssertionNode, expr ast.Expr) (annotation.ConsumingAnnotationTrigger, error) {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		if consumer := deepAssignmentToIdentAsConsumer(rootNode, expr); consumer != nil {
+			return consumer, nil
+		}
+	case *ast.SelectorExpr:
+		if rootNode.isPkgName(expr.X) {
+			if consumer := deepAssignmentToIdentAsConsumer(rootNode, expr.Sel); consumer != nil {
+				return consumer, nil
+			}
+		}
+
+		// this is an assignment to an index of a field
+		fldObj := rootNode.ObjectOf(expr.Sel).(*types.Var)
+		if fldObj.IsField() && util.TypeIsDeepAtSite(fldObj.Type()) {
+			return &annotation.FieldAssignDeep{
+				TriggerIfDeepNonNil: &annotation.TriggerIfDeepNonNil{
+					Ann: &annotation.FieldAnnotationKey{FieldDecl: fldObj},
+				},
+			}, nil
+		}
+	case *ast.CallExpr:
+		// check if this is a call to a function by name
+		if ident := util.FuncIdentFromCallExpr(expr); ident != nil {
+			obj := rootNode.ObjectOf(ident).(*types.Func)
+			if obj.Type().(*types.Signature).Results().Len() != 1 {
+				return nil, errors.New("multiply returning function treated as assignment consumer")
+			}
+			return &annotation.FuncRetAssignDeep{
+				TriggerIfDeepNonNil: &annotation.TriggerIfDeepNonNil{
+					Ann: annotation.RetKeyFromRetNum(obj, 0),
+				},
+			}, nil
+		}
+	case *ast.IndexExpr:
+		return exprAsAssignmentConsumer(rootNode, expr.X)
+	}
+
+	nameAsDeepTrigger := func(name *types.TypeName) *annotation.TriggerIfDeepNonNil {
+		return &annotation.TriggerIfDeepNonNil{Ann: &annotation.TypeNameAnnotationKey{TypeDecl: name}}
+	}
+
+	exprType := rootNode.Pass().TypesInfo.Types[expr].Type
+
+	if named, ok := exprType.(*types.Named); ok {
+		// Calling Underlying on [types.Named] will always return the unnamed type, so we
+		// do not have to recursively "unwrap" the [types.Named].
+		// See [https://github.com/golang/example/tree/master/gotypes#named-types].
+		switch named.Underlying().(type) {
+		case *types.Slice:
+			return &annotation.SliceAssign{TriggerIfDeepNonNil: nameAsDeepTrigger(named.Obj())}, nil
+		case *types.Array:
+			return &annotation.ArrayAssign{TriggerIfDeepNonNil: nameAsDeepTrigger(named.Obj())}, nil
+		case *types.Map:
+			return &annotation.MapAssign{TriggerIfDeepNonNil: nameAsDeepTrigger(named.Obj())}, nil
+		case *types.Pointer:
+			return &annotation.PtrAssign{TriggerIfDeepNonNil: nameAsDeepTrigger(named.Obj())}, nil
+		case *types.Chan:
+			return &annotation.ChanSend{TriggerIfDeepNonNil: nameAsDeepTrigger(named.Obj())}, nil
+		}
+	}
+
+	// at this point - the value being deeply assigned to is of deep type but is not linked
+	// to an annotation site, for example, local variables.
+	// so we introspect on its type alone
+
+	// The elements of local containers of interfaces (e.g., `[]error`) are deeply nilable by
+	// default, but we still track the (possibly nil) concrete values stored into them
+	// (e.g., `errs[i] = ptr`) via their local variable sites, such that their nilability
+	// flows to where the containers are passed or returned and the elements are used.
+	if ident, ok := expr.(*ast.Ident); ok && util.TypeHasInterfaceElem(exprType) {
+		return &annotation.LocalVarAssignDeep{
+			TriggerIfDeepNonNil: &annotation.TriggerIfDeepNonNil{
+				Ann: &annotation.LocalVarAnnotationKey{
+					VarDecl: rootNode.ObjectOf(ident).(*types.Var),
+				},
+			},
+		}, nil
+	}
+
+	if !annotation.TypeIsDeepDefaultNilable(exprType) {
+		if ident, ok := expr.(*ast.Ident); ok {
+			varObj := rootNode.ObjectOf(ident).(*types.Var)
+			return &annotation.LocalVarAssignDeep{
+				TriggerIfDeepNonNil: &annotation.TriggerIfDeepNonNil{
+					Ann: &annotation.LocalVarAnnotationKey{
+						VarDecl: varObj,
+					},
+				},
+			}, nil
+		}
+		return &annotation.DeepAssignPrimitive{ConsumeTriggerTautology: &annotation.ConsumeTriggerTautology{}}, nil
+	}
+	return nil, nil
+}
+
+// sliceAsElemsAssignmentConsumer returns the consumer for the elements assigned into the passed
+// slice expression by the builtins, e.g., `x` and the elements of `y` in `a = append(a, x, y...)`
+// and the elements of `y` in `copy(a, y)`. Only the slices with annotation sites (i.e., parameters,
+// global variables and fields) are considered: the reads of the elements of local variables are
+// tracked in the assertion tree, and produced from the builtin calls directly (see
+// parseAppendAsProducer and backpropAcrossCopy).
+// nilable(result 0)
+func sliceAsElemsAssignmentConsumer(rootNode *RootAssertionNode, expr ast.Expr) (annotation.ConsumingAnnotationTrigger, error) {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return deepAssignmentToIdentAsConsumer(rootNode, expr), nil
+	case *ast.SelectorExpr:
+		return exprAsDeepAssignmentConsumer(rootNode, expr)
+	}
+	return nil, nil
+}
+
+// deepAssignmentToIdentAsConsumer returns the consumer for a value assigned into an index of the
+// variable of the passed identifier, if it is a parameter or a global variable of deep type.
+// nilable(result 0)
+func deepAssignmentToIdentAsConsumer(rootNode *RootAssertionNode, ident *ast.Ident) annotation.ConsumingAnnotationTrigger {
+	funcObj := rootNode.FuncObj()
+	varObj := rootNode.ObjectOf(ident).(*types.Var)
+	if annotation.VarIsParam(funcObj, varObj) && util.TypeIsDeepAtSite(varObj.Type()) {
+		// we've found an assignment to a parameter with deep type - have to check its deep annotation!
+		paramKey := annotation.ParamKeyFromName(funcObj, varObj)
+
+		// but first - if it's a variadic parameter then its "deep" annotation is really just
+		// its shallow annotation:
+		if annotation.VarIsVariadicParam(funcObj, varObj) {
+			return &annotation.VariadicParamAssignDeep{
+				TriggerIfNonNil: &annotation.TriggerIfNonNil{
+					Ann: paramKey}}
+		}
+
+		// we've concluded it's not a variadic parameter
+		return &annotation.ParamAssignDeep{
+			TriggerIfDeepNonNil: &annotation.TriggerIfDeepNonNil{
+				Ann: paramKey}}
+	}
+	if annotation.VarIsGlobal(varObj) && util.TypeIsDeep(varObj.Type()) {
+		// we've found an assignment to a global var with deep type - have to check its deep annotation!
+		return &annotation.GlobalVarAssignDeep{
+			TriggerIfDeepNonNil: &annotation.TriggerIfDeepNonNil{
+				Ann: &annotation.GlobalVarAnnotationKey{
+					VarDecl: varObj,
+				}}}
+	}
+	return nil
+}
+
 // exprAsAssignmentConsumer is similar to parseExprAsProducer, but tries to parse the passed
 // expression as a _consumer_ instead of as a _producer_. The simplest illustrative example of
 // this is when a field read expression is passed as `expr` - meaning a field is being assigned
@@ -472,7 +655,7 @@ func exprAsConsumedByAssignment(rootNode *RootAssertionNode, expr ast.Node) *ann
 // other notable cases include passing a send expression here (which is why we take an `ast.Node`
 // not `ast.Expr`, and various "deep" assignments such as to an index of an object
 // nilable(result 0)
-func exprAsAssignmentConsumer(rootNode *RootAssertionNode, expr ast.Node, exprRHS ast.Node) (annotation.ConsumingAnnotationTrigger, error) {
+func exprAsAssignmentConsumer(rootNode *RootAssertionNode, expr ast.Node) (annotation.ConsumingAnnotationTrigger, error) {
 	if expr, ok := expr.(ast.Expr); ok && util.IsEmptyExpr(expr) {
 		return nil, nil
 	}
@@ -490,137 +673,8 @@ func exprAsAssignmentConsumer(rootNode *RootAssertionNode, expr ast.Node, exprRH
 		return nil
 	}
 
-	handleDeepAssignmentToIdent :=
-		func(ident *ast.Ident) annotation.ConsumingAnnotationTrigger {
-			funcObj := rootNode.FuncObj()
-			varObj := rootNode.ObjectOf(ident).(*types.Var)
-			if util.TypeIsDeep(varObj.Type()) {
-				if annotation.VarIsParam(funcObj, varObj) {
-					// we've found an assignment to a parameter with deep type - have to check its deep annotation!
-					paramKey := annotation.ParamKeyFromName(funcObj, varObj)
-
-					// but first - if it's a variadic parameter then its "deep" annotation is really just
-					// its shallow annotation:
-					if annotation.VarIsVariadicParam(funcObj, varObj) {
-						return &annotation.VariadicParamAssignDeep{
-							TriggerIfNonNil: &annotation.TriggerIfNonNil{
-								Ann: paramKey}}
-					}
-
-					// we've concluded it's not a variadic parameter
-					return &annotation.ParamAssignDeep{
-						TriggerIfDeepNonNil: &annotation.TriggerIfDeepNonNil{
-							Ann: paramKey}}
-				}
-				if annotation.VarIsGlobal(varObj) {
-					// we've found an assignment to a global var with deep type - have to check its deep annotation!
-					return &annotation.GlobalVarAssignDeep{
-						TriggerIfDeepNonNil: &annotation.TriggerIfDeepNonNil{
-							Ann: &annotation.GlobalVarAnnotationKey{
-								VarDecl: varObj,
-							}}}
-				}
-			}
-			return nil
-		}
-
-	handleDeepAssignmentToExpr :=
-		func(expr ast.Expr) (annotation.ConsumingAnnotationTrigger, error) {
-
-			switch expr := expr.(type) {
-			case *ast.Ident:
-				if consumer := handleDeepAssignmentToIdent(expr); consumer != nil {
-					return consumer, nil
-				}
-			case *ast.SelectorExpr:
-				if rootNode.isPkgName(expr.X) {
-					if consumer := handleDeepAssignmentToIdent(expr.Sel); consumer != nil {
-						return consumer, nil
-					}
-				}
-
-				// this is an assignment to an index of a field
-				fldObj := rootNode.ObjectOf(expr.Sel).(*types.Var)
-				if fldObj.IsField() && util.TypeIsDeep(fldObj.Type()) {
-					return &annotation.FieldAssignDeep{
-						TriggerIfDeepNonNil: &annotation.TriggerIfDeepNonNil{
-							Ann: &annotation.FieldAnnotationKey{FieldDecl: fldObj},
-						},
-					}, nil
-				}
-			case *ast.CallExpr:
-				// check if this is a call to a function by name
-				if ident := util.FuncIdentFromCallExpr(expr); ident != nil {
-					obj := rootNode.ObjectOf(ident).(*types.Func)
-					if obj.Type().(*types.Signature).Results().Len() != 1 {
-						return nil, errors.New("multiply returning function treated as assignment consumer")
-					}
-					return &annotation.FuncRetAssignDeep{
-						TriggerIfDeepNonNil: &annotation.TriggerIfDeepNonNil{
-							Ann: annotation.RetKeyFromRetNum(obj, 0),
-						},
-					}, nil
-				}
-			case *ast.IndexExpr:
-				return exprAsAssignmentConsumer(rootNode, expr.X, exprRHS)
-			}
-
-			nameAsDeepTrigger := func(name *types.TypeName) *annotation.TriggerIfDeepNonNil {
-				return &annotation.TriggerIfDeepNonNil{Ann: &annotation.TypeNameAnnotationKey{TypeDecl: name}}
-			}
-
-			exprType := rootNode.Pass().TypesInfo.Types[expr].Type
-
-			if named, ok := exprType.(*types.Named); ok {
-				// Calling Underlying on [types.Named] will always return the unnamed type, so we
-				// do not have to recursively "unwrap" the [types.Named].
-				// See [https://github.com/golang/example/tree/master/gotypes#named-types].
-				switch named.Underlying().(type) {
-				case *types.Slice:
-					return &annotation.SliceAssign{TriggerIfDeepNonNil: nameAsDeepTrigger(named.Obj())}, nil
-				case *types.Array:
-					return &annotation.ArrayAssign{TriggerIfDeepNonNil: nameAsDeepTrigger(named.Obj())}, nil
-				case *types.Map:
-					return &annotation.MapAssign{TriggerIfDeepNonNil: nameAsDeepTrigger(named.Obj())}, nil
-				case *types.Pointer:
-					return &annotation.PtrAssign{TriggerIfDeepNonNil: nameAsDeepTrigger(named.Obj())}, nil
-				case *types.Chan:
-					return &annotation.ChanSend{TriggerIfDeepNonNil: nameAsDeepTrigger(named.Obj())}, nil
-				}
-			}
-
-			// at this point - the value being deeply assigned to is of deep type but is not linked
-			// to an annotation site, for example, local variables.
-			// so we introspect on its type alone
-
-			if !annotation.TypeIsDeepDefaultNilable(exprType) {
-				if ident, ok := expr.(*ast.Ident); ok {
-					varObj := rootNode.ObjectOf(ident).(*types.Var)
-					return &annotation.LocalVarAssignDeep{
-						TriggerIfDeepNonNil: &annotation.TriggerIfDeepNonNil{
-							Ann: &annotation.LocalVarAnnotationKey{
-								VarDecl: varObj,
-							},
-						},
-					}, nil
-				}
-				return &annotation.DeepAssignPrimitive{ConsumeTriggerTautology: &annotation.ConsumeTriggerTautology{}}, nil
-			}
-			return nil, nil
-		}
-
 	switch expr := expr.(type) {
 	case *ast.Ident:
-		// This block checks if the rhs of the assignment is the builtin append function for slices.
-		varObj := rootNode.ObjectOf(expr).(*types.Var)
-		if call, ok := exprRHS.(*ast.CallExpr); ok && util.TypeIsSlice(varObj.Type()) {
-			if fun, ok := call.Fun.(*ast.Ident); ok && rootNode.ObjectOf(fun) == util.BuiltinAppend {
-				if annotation.VarIsParam(rootNode.FuncObj(), varObj) {
-					// If there is a deep assignment to a slice using append method
-					return handleDeepAssignmentToExpr(expr)
-				}
-			}
-		}
 		if consumer := handleAssignmentToIdent(expr); consumer != nil {
 			return consumer, nil
 		}
@@ -656,11 +710,11 @@ func exprAsAssignmentConsumer(rootNode *RootAssertionNode, expr ast.Node, exprRH
 			},
 		}, nil
 	case *ast.StarExpr:
-		return handleDeepAssignmentToExpr(expr.X)
+		return exprAsDeepAssignmentConsumer(rootNode, expr.X)
 	case *ast.IndexExpr:
-		return handleDeepAssignmentToExpr(expr.X)
+		return exprAsDeepAssignmentConsumer(rootNode, expr.X)
 	case *ast.SendStmt:
-		return handleDeepAssignmentToExpr(expr.Chan)
+		return exprAsDeepAssignmentConsumer(rootNode, expr.Chan)
 	}
 
 	// no recognized source of deep nilability consumption
@@ -803,6 +857,37 @@ func exprAsDeepProducer(rootNode *RootAssertionNode, expr ast.Expr) annotation.P
 	return parsedExpr[0].GetDeep().Annotation
 }
 
+// exprAsDeepProducers returns the producers of the indices of the given expression, which are
+// several for the merged elements of an `append` call (see parseAppendAsProducer), and none if the
+// expression is not deeply nilable.
+func exprAsDeepProducers(rootNode *RootAssertionNode, expr ast.Expr) []*annotation.ProduceTrigger {
+	_, parsedExpr := rootNode.ParseExprAsProducer(expr, true)
+	if len(parsedExpr) != 1 {
+		return nil
+	}
+	var producers []*annotation.ProduceTrigger
+	for _, p := range parsedExpr[0].GetDeepSlice() {
+		if p != nil {
+			producers = append(producers, p)
+		}
+	}
+	return producers
+}
+
+// appendedValues returns the values appended one by one by the given call to the builtin `append`,
+// i.e., its arguments other than the first one (the slice appended to) and a spread one (e.g., `y`
+// in `append(s, x, y...)`).
+func appendedValues(call *ast.CallExpr) []ast.Expr {
+	if len(call.Args) == 0 {
+		return nil
+	}
+	values := call.Args[1:]
+	if call.Ellipsis != token.NoPos && len(values) > 0 {
+		values = values[:len(values)-1]
+	}
+	return values
+}
+
 // CheckGuardOnFullTrigger gives guarding its intended semantics:
 // if a full trigger would be created with a guarded producer but
 // not a guarded consumer, then the production as written in the
@@ -845,6 +930,36 @@ func addAssignmentToConsumer(lhs, rhs ast.Expr, pass *analysis.Pass, consumer an
 	return nil
 }
 
+// convertInterfaceRecvConsumer converts the consumer of an interface value invoking an interface
+// method (e.g., `i.foo()`) into a RecvPass for the implementing method, if the value is assigned
+// from an expression of type rhsType that is a pointer to the receiver of the implementing method
+// (e.g., `var i I = s`, where `s` is of type `*S`), or of a map, slice or channel type that is the
+// receiver (e.g., `var i I = m`, where `m` is of type `M` with `type M map[string]int`). Similar to
+// the direct invocations of methods (see RootAssertionNode.consumeRecv), only the receivers of the
+// methods in scope that can be nil are considered, while the other consumers are kept as is, i.e.,
+// the interface value must be nonnil.
+func convertInterfaceRecvConsumer(rootNode *RootAssertionNode, rhsType types.Type, c *annotation.ConsumeTrigger) {
+	fldAccess, ok := c.Annotation.(*annotation.FldAccess)
+	if !ok || rhsType == nil || types.IsInterface(rhsType) || !util.TypeIsDeeplyNilableRecv(rhsType) {
+		return
+	}
+	interfaceMethod, ok := fldAccess.Sel.(*types.Func)
+	if !ok || !types.IsInterface(interfaceMethod.Type().(*types.Signature).Recv().Type()) {
+		return
+	}
+	obj, index, _ := types.LookupFieldOrMethod(rhsType, false /* addressable */, interfaceMethod.Pkg(), interfaceMethod.Name())
+	method, ok := obj.(*types.Func)
+	// A method promoted from an embedded field is called on the field rather than the value.
+	if !ok || len(index) != 1 || !recvIsNilable(method, rhsType) {
+		return
+	}
+	conf := rootNode.Pass().ResultOf[config.Analyzer].(*config.Config)
+	if !conf.IsPkgInScope(method.Pkg()) {
+		return
+	}
+	c.Annotation = annotation.RecvPassViaInterface(fldAccess, method)
+}
+
 func addReturnConsumers(rootNode *RootAssertionNode, node *ast.ReturnStmt, expr ast.Expr, retKey *annotation.RetAnnotationKey, isNamedReturn bool) {
 	// add shallow consumer
 	rootNode.AddConsumption(&annotation.ConsumeTrigger{
@@ -864,7 +979,7 @@ func addReturnConsumers(rootNode *RootAssertionNode, node *ast.ReturnStmt, expr
 	//   return s  // <-- track shallow and deep nilability of `s` here
 	// }
 	// ```
-	if util.TypeIsDeep(rootNode.Pass().TypesInfo.TypeOf(expr)) {
+	if util.TypeIsDeepAtSite(rootNode.Pass().TypesInfo.TypeOf(expr)) {
 		producer := &annotation.ProduceTrigger{
 			Annotation: exprAsDeepProducer(rootNode, expr),
 			Expr:       expr,
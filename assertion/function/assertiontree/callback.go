@@ -0,0 +1,340 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assertiontree
+
+import (
+	"go/ast"
+	"go/types"
+
+	"go.uber.org/nilaway/annotation"
+	"go.uber.org/nilaway/assertion/function/producer"
+	"go.uber.org/nilaway/hook"
+	"go.uber.org/nilaway/util"
+)
+
+// This file implements the support for callbacks, i.e., the function-typed parameters of the
+// functions (e.g., `cb` in `func Walk(cb func(x *T) *U)`). Within such a function, the arguments
+// passed to a call of the callback are consumed by the callback parameter sites (see
+// annotation.CallbackParamAnnotationKey), and the results are produced by the callback result
+// sites (see annotation.CallbackRetAnnotationKey). At the call sites of such a function (e.g.,
+// `Walk(handle)`), the sites are then connected to the parameters and results of the function
+// passed as the callback (see addCallbackFlows), such that the nilability flows into and out of
+// the bodies of the callbacks.
+//
+// Similarly, the callbacks stored in the function-typed struct fields of the package (e.g.,
+// `s.onEvent(e)` for `onEvent func(e *Event)`) have their parameter and result sites on the fields
+// (see calledCallbackField), which are connected to the functions assigned to the fields (e.g.,
+// `s.onEvent = handle` or `&S{onEvent: handle}`, see addFieldCallbackFlows).
+
+// calledCallbackParam returns the index of the function-typed parameter of the function being
+// analyzed that is called by the call expression (e.g., `cb(x)`), and false if the called
+// function is not such a parameter.
+func (r *RootAssertionNode) calledCallbackParam(expr *ast.CallExpr) (int, bool) {
+	ident, ok := ast.Unparen(expr.Fun).(*ast.Ident)
+	if !ok {
+		return 0, false
+	}
+	v, ok := r.ObjectOf(ident).(*types.Var)
+	if !ok {
+		return 0, false
+	}
+	funcObj := r.FuncObj()
+	params := funcObj.Type().(*types.Signature).Params()
+	for i := 0; i < params.Len(); i++ {
+		if params.At(i) == v && annotation.CallbackSignature(funcObj, i) != nil {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// calledCallbackField returns the function-typed struct field of the package that is called by
+// the call expression (e.g., `s.onEvent(e)`), and nil if the called function is not such a field.
+// nilable(result 0)
+func (r *RootAssertionNode) calledCallbackField(expr *ast.CallExpr) *types.Var {
+	sel, ok := ast.Unparen(expr.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	return r.callbackFieldOf(sel)
+}
+
+// callbackFieldOf returns the function-typed struct field of the package selected by the
+// selector expression, and nil otherwise.
+// nilable(result 0)
+func (r *RootAssertionNode) callbackFieldOf(sel *ast.SelectorExpr) *types.Var {
+	selection, ok := r.Pass().TypesInfo.Selections[sel]
+	if !ok || selection.Kind() != types.FieldVal {
+		return nil
+	}
+	field := selection.Obj().(*types.Var).Origin()
+	if field.Pkg() != r.Pass().Pkg || annotation.FieldCallbackSignature(field) == nil {
+		return nil
+	}
+	return field
+}
+
+// consumeCallbackArgs adds consumers for the arguments of the call to the `paramNum`-th
+// parameter (a callback) of the function being analyzed.
+func (r *RootAssertionNode) consumeCallbackArgs(expr *ast.CallExpr, paramNum int) {
+	r.consumeCallbackArgsAt(expr, func(num int) *annotation.CallbackParamAnnotationKey {
+		return annotation.NewCallbackParamKey(r.FuncObj(), paramNum, num)
+	})
+}
+
+// consumeFieldCallbackArgs adds consumers for the arguments of the call to the callback stored in
+// the function-typed struct field.
+func (r *RootAssertionNode) consumeFieldCallbackArgs(expr *ast.CallExpr, field *types.Var) {
+	r.consumeCallbackArgsAt(expr, func(num int) *annotation.CallbackParamAnnotationKey {
+		return annotation.NewFieldCallbackParamKey(field, num)
+	})
+}
+
+// consumeCallbackArgsAt adds consumers for the arguments of the call to a callback at the callback
+// parameter sites given by paramKey.
+func (r *RootAssertionNode) consumeCallbackArgsAt(expr *ast.CallExpr, paramKey func(num int) *annotation.CallbackParamAnnotationKey) {
+	for i, arg := range expr.Args {
+		// Multiply-returning calls directly passed as the arguments (e.g., `cb(foo())`) and the
+		// unpacking of variadic arguments (e.g., `cb(xs...)`) are not tracked here.
+		if _, ok := r.Pass().TypesInfo.TypeOf(arg).(*types.Tuple); ok {
+			continue
+		}
+		if expr.Ellipsis.IsValid() && i == len(expr.Args)-1 {
+			continue
+		}
+		r.AddConsumption(&annotation.ConsumeTrigger{
+			Annotation: &annotation.ArgPass{
+				TriggerIfNonNil: &annotation.TriggerIfNonNil{
+					Ann: paramKey(i),
+				}},
+			Expr:   arg,
+			Guards: util.NoGuards(),
+		})
+	}
+}
+
+// getCallbackReturnProducers returns the producers for the results of the call to the
+// `paramNum`-th parameter (a callback) of the function being analyzed. Only the callbacks with a
+// single non-error result are tracked (see isTrackedCallbackResult), otherwise it returns nil,
+// i.e., the results are assumed nonnil as before.
+func (r *RootAssertionNode) getCallbackReturnProducers(expr *ast.CallExpr, paramNum int) []producer.ParsedProducer {
+	if !isTrackedCallbackResult(annotation.CallbackSignature(r.FuncObj(), paramNum)) {
+		return nil
+	}
+	return callbackReturnProducers(expr, &annotation.CallbackRetAnnotationKey{FuncDecl: r.FuncObj(), ParamNum: paramNum, RetNum: 0})
+}
+
+// getFieldCallbackReturnProducers returns the producers for the results of the call to the
+// callback stored in the function-typed struct field, similar to getCallbackReturnProducers.
+func (r *RootAssertionNode) getFieldCallbackReturnProducers(expr *ast.CallExpr, field *types.Var) []producer.ParsedProducer {
+	if !isTrackedCallbackResult(annotation.FieldCallbackSignature(field)) {
+		return nil
+	}
+	return callbackReturnProducers(expr, &annotation.CallbackRetAnnotationKey{FieldDecl: field, RetNum: 0})
+}
+
+func callbackReturnProducers(expr *ast.CallExpr, key *annotation.CallbackRetAnnotationKey) []producer.ParsedProducer {
+	return []producer.ParsedProducer{producer.ShallowParsedProducer{
+		Producer: &annotation.ProduceTrigger{
+			Annotation: &annotation.CallbackReturn{
+				TriggerIfNilable: &annotation.TriggerIfNilable{Ann: key}},
+			Expr: expr,
+		},
+	}}
+}
+
+// isTrackedCallbackResult returns true if the result of the callback with the signature is
+// tracked. The results of multiply-returning callbacks are not tracked, since they would require
+// the error and ok guarding of the results (see RichCheckEffect), and neither are the single
+// error results, which are expected to be nilable.
+func isTrackedCallbackResult(sig *types.Signature) bool {
+	return sig.Results().Len() == 1 && !types.Identical(sig.Results().At(0).Type(), util.ErrorType)
+}
+
+// addCallbackFlows adds the full triggers connecting the callback sites of the called function
+// `fdecl` to the parameters and results of the functions passed as its callbacks by the call
+// expression (see annotation.FullTriggerForCallbackParamFlow and
+// annotation.FullTriggerForCallbackResultFlow). For the trusted higher-order functions (see
+// hook.AssumeCallback), the assumed behavior on the callback overrides the callback sites.
+func (r *RootAssertionNode) addCallbackFlows(fdecl *types.Func, expr *ast.CallExpr, args []ast.Expr) {
+	fdecl = fdecl.Origin()
+	assumption, trusted := hook.AssumeCallback(r.Pass(), expr)
+	for i, arg := range args {
+		if trusted && i == assumption.ArgNum && assumption.Invoked {
+			r.consumeInvokedClosureVars(arg)
+		}
+		sig := annotation.CallbackSignature(fdecl, i)
+		if sig == nil || (fdecl.Type().(*types.Signature).Variadic() && i >= fdecl.Type().(*types.Signature).Params().Len()-1) {
+			continue
+		}
+		callback := r.callbackFuncOf(arg)
+		if callback == nil || callback.Type().(*types.Signature).Params().Len() < sig.Params().Len() {
+			continue
+		}
+		pair := annotation.CallbackPair{FuncDecl: fdecl, ParamNum: i, Callback: callback}
+		// The parameters of the callback are assumed nonnil, regardless of the values passed by
+		// the implementation of the trusted function.
+		if !(trusted && i == assumption.ArgNum && assumption.NonnilParams) {
+			for j := 0; j < sig.Params().Len(); j++ {
+				r.AddNewTriggers(annotation.FullTriggerForCallbackParamFlow(pair, j, arg))
+			}
+		}
+		if isTrackedCallbackResult(sig) {
+			r.AddNewTriggers(annotation.FullTriggerForCallbackResultFlow(pair, 0, arg))
+		}
+	}
+}
+
+// addFieldCallbackFlows adds the full triggers connecting the callback sites of the
+// function-typed struct field selected by the lhs (e.g., `s.onEvent`) or keyed in a composite
+// literal (e.g., `onEvent` in `&S{onEvent: handle}`) to the parameters and results of the function
+// stored in it, similar to addCallbackFlows.
+func (r *RootAssertionNode) addFieldCallbackFlows(field *types.Var, rhs ast.Expr) {
+	sig := annotation.FieldCallbackSignature(field)
+	callback := r.callbackFuncOf(rhs)
+	if callback == nil || callback.Type().(*types.Signature).Params().Len() < sig.Params().Len() {
+		return
+	}
+	pair := annotation.CallbackPair{FieldDecl: field, Callback: callback}
+	for j := 0; j < sig.Params().Len(); j++ {
+		r.AddNewTriggers(annotation.FullTriggerForCallbackParamFlow(pair, j, rhs))
+	}
+	if isTrackedCallbackResult(sig) {
+		r.AddNewTriggers(annotation.FullTriggerForCallbackResultFlow(pair, 0, rhs))
+	}
+}
+
+// addCompositeLitCallbackFlows adds the full triggers for the functions stored in the
+// function-typed struct fields of the package keyed in the composite literal (see
+// addFieldCallbackFlows).
+func (r *RootAssertionNode) addCompositeLitCallbackFlows(lit *ast.CompositeLit) {
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		field, ok := r.ObjectOf(key).(*types.Var)
+		if !ok || !field.IsField() {
+			continue
+		}
+		field = field.Origin()
+		if field.Pkg() != r.Pass().Pkg || annotation.FieldCallbackSignature(field) == nil {
+			continue
+		}
+		r.addFieldCallbackFlows(field, kv.Value)
+	}
+}
+
+// consumeInvokedClosureVars adds the argument consumers for the variables captured by the
+// analyzed function literal passed as the argument to a trusted higher-order function that invokes
+// it (e.g., `x` in `sort.Slice(xs, func(i, j int) bool { return *x < 0 })`). Similar to the calls
+// of the function literals (see funcArgsFromCallExpr), the closure variables are passed as the
+// extra parameters of the function literal at the call.
+func (r *RootAssertionNode) consumeInvokedClosureVars(arg ast.Expr) {
+	funcLit, ok := ast.Unparen(arg).(*ast.FuncLit)
+	if !ok {
+		return
+	}
+	info, ok := r.functionContext.funcLitMap[funcLit]
+	if !ok {
+		return
+	}
+	numParams := info.FakeFuncObj.Type().(*types.Signature).Params().Len() - len(info.ClosureVars)
+	for i, closure := range info.ClosureVars {
+		if util.TypeBarsNilness(closure.Obj.Type()) {
+			continue
+		}
+		r.AddConsumption(&annotation.ConsumeTrigger{
+			Annotation: &annotation.ArgPass{
+				TriggerIfNonNil: &annotation.TriggerIfNonNil{
+					Ann: annotation.ParamKeyFromArgNum(info.FakeFuncObj, numParams+i),
+				}},
+			Expr:   closure.Ident,
+			Guards: util.NoGuards(),
+		})
+	}
+}
+
+// callbackFuncOf returns the function passed as the argument, which is either a declared function
+// or method (e.g., `handle` or `s.handle`), or an analyzed function literal, either directly or
+// through a variable it is assigned to (see anonymousfunc.Analyzer). It returns nil otherwise.
+// nilable(result 0)
+func (r *RootAssertionNode) callbackFuncOf(arg ast.Expr) *types.Func {
+	arg = ast.Unparen(arg)
+	var ident *ast.Ident
+	switch arg := arg.(type) {
+	case *ast.FuncLit:
+		if info, ok := r.functionContext.funcLitMap[arg]; ok {
+			return info.FakeFuncObj
+		}
+		return nil
+	case *ast.SelectorExpr:
+		// Method expressions (e.g., `T.handle`) take the receiver as their first parameter.
+		if r.isType(arg.X) {
+			return nil
+		}
+		ident = arg.Sel
+	case *ast.Ident:
+		if funcLit := getFuncLitFromAssignment(arg); funcLit != nil {
+			if info, ok := r.functionContext.funcLitMap[funcLit]; ok {
+				return info.FakeFuncObj
+			}
+			return nil
+		}
+		ident = arg
+	default:
+		return nil
+	}
+	if funcObj, ok := r.ObjectOf(ident).(*types.Func); ok {
+		return funcObj.Origin()
+	}
+	return nil
+}
+
+// errGuardedParam returns the identifiers declaring the parameter of the function being analyzed
+// that is only nonnil if its error parameter is nil, and the error parameter (see
+// hook.ErrGuardedParam), or nils if the function has no such (named) parameters.
+// nilable(result 0, result 1)
+func (r *RootAssertionNode) errGuardedParam() (*ast.Ident, *ast.Ident) {
+	var funcType *ast.FuncType
+	var sig *types.Signature
+	switch {
+	case r.functionContext.funcLit != nil:
+		funcType = r.functionContext.funcLit.Type
+		sig, _ = r.Pass().TypesInfo.TypeOf(r.functionContext.funcLit).(*types.Signature)
+	case r.FuncDecl() != nil:
+		funcType = r.FuncDecl().Type
+		sig, _ = r.FuncObj().Type().(*types.Signature)
+	}
+	if sig == nil {
+		return nil, nil
+	}
+	paramNum, errNum, ok := hook.ErrGuardedParam(sig)
+	if !ok {
+		return nil, nil
+	}
+	var names []*ast.Ident
+	for _, field := range funcType.Params.List {
+		names = append(names, field.Names...)
+	}
+	if len(names) != sig.Params().Len() || names[paramNum].Name == "_" || names[errNum].Name == "_" {
+		return nil, nil
+	}
+	return names[paramNum], names[errNum]
+}
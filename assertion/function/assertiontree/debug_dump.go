@@ -0,0 +1,217 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assertiontree
+
+import (
+	"fmt"
+	"go/ast"
+	"go/printer"
+	"go/types"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"go.uber.org/nilaway/annotation"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/cfg"
+)
+
+// _debugDumpMaxLineLen is the maximum length of a line in the labels of the dumped graphs, longer
+// lines are truncated.
+const _debugDumpMaxLineLen = 80
+
+// unsafeFileNameChars matches the characters that are replaced in the names of the dumped files.
+var unsafeFileNameChars = regexp.MustCompile(`[^a-zA-Z0-9_.\-]+`)
+
+// debugDumper writes the DOT graphs of the preprocessed CFG, the assertion trees of the blocks in
+// each round, and the final full triggers of a function during backpropagation, for diagnosing
+// the analysis (see FunctionConfig.DebugDumpDir). All methods are no-ops on a nil debugDumper.
+type debugDumper struct {
+	pass *analysis.Pass
+	// prefix is the common path prefix of the files dumped for the function.
+	prefix string
+}
+
+// newDebugDumper returns a debugDumper for the function if dumping is enabled and the function is
+// selected, otherwise nil. Functions are selected by matching their full names (e.g.,
+// "example.com/foo.Bar" or "(*example.com/foo.T).Baz") against FunctionConfig.DebugDumpFuncs.
+func newDebugDumper(pass *analysis.Pass, decl *ast.FuncDecl, functionConfig FunctionConfig) *debugDumper {
+	if functionConfig.DebugDumpDir == "" {
+		return nil
+	}
+	name := pass.Pkg.Path() + "." + decl.Name.Name
+	if funcObj, ok := pass.TypesInfo.ObjectOf(decl.Name).(*types.Func); ok {
+		name = funcObj.FullName()
+	}
+	if functionConfig.DebugDumpFuncs != nil && !functionConfig.DebugDumpFuncs.MatchString(name) {
+		return nil
+	}
+	return &debugDumper{
+		pass:   pass,
+		prefix: filepath.Join(functionConfig.DebugDumpDir, unsafeFileNameChars.ReplaceAllString(name, "_")),
+	}
+}
+
+// dumpCFG writes the preprocessed CFG to "<prefix>.cfg.dot".
+func (d *debugDumper) dumpCFG(graph *cfg.CFG) error {
+	if d == nil {
+		return nil
+	}
+	var b strings.Builder
+	b.WriteString("digraph cfg {\n\tnode [shape=box, fontname=monospace];\n")
+	for _, block := range graph.Blocks {
+		header := fmt.Sprintf("Block %d", block.Index)
+		// The blocks created by the preprocessor do not have kinds.
+		if block.Kind != cfg.KindInvalid {
+			header += fmt.Sprintf(" (%s)", block.Kind)
+		}
+		lines := []string{header}
+		for _, node := range block.Nodes {
+			lines = append(lines, d.nodeString(node))
+		}
+		style := ""
+		if !block.Live {
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(&b, "\tb%d [label=\"%s\"%s];\n", block.Index, dotLabel(lines...), style)
+		for i, succ := range block.Succs {
+			attrs := ""
+			if len(block.Succs) == 2 {
+				// The first successor of a branch block is the true branch.
+				attrs = fmt.Sprintf(" [label=\"%t\"]", i == 0)
+			}
+			fmt.Fprintf(&b, "\tb%d -> b%d%s;\n", block.Index, succ.Index, attrs)
+		}
+	}
+	b.WriteString("}\n")
+	return d.write("cfg", b.String())
+}
+
+// dumpRound writes the assertion trees of the blocks after the given round of backpropagation to
+// "<prefix>.round<N>.dot", one cluster per block.
+func (d *debugDumper) dumpRound(round int, assertions []*RootAssertionNode) error {
+	if d == nil {
+		return nil
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph round%d {\n\tnode [shape=box, fontname=monospace];\n", round)
+	for i, root := range assertions {
+		if root == nil {
+			continue
+		}
+		fmt.Fprintf(&b, "\tsubgraph cluster_b%d {\n\t\tlabel=\"Block %d (%d full triggers)\";\n", i, i, len(root.triggers))
+		count := 0
+		var visit func(node AssertionNode) string
+		visit = func(node AssertionNode) string {
+			id := fmt.Sprintf("b%d_n%d", i, count)
+			count++
+			lines := []string{node.MinimalString()}
+			for _, consumer := range node.ConsumeTriggers() {
+				lines = append(lines, "consume: "+consumer.Annotation.Prestring().String())
+			}
+			fmt.Fprintf(&b, "\t\t%s [label=\"%s\"];\n", id, dotLabel(lines...))
+			for _, child := range node.Children() {
+				fmt.Fprintf(&b, "\t\t%s -> %s;\n", id, visit(child))
+			}
+			return id
+		}
+		visit(root)
+		b.WriteString("\t}\n")
+	}
+	b.WriteString("}\n")
+	return d.write(fmt.Sprintf("round%d", round), b.String())
+}
+
+// dumpTriggers writes the final full triggers of the function to "<prefix>.triggers.dot", where
+// each trigger is an edge from its producer to its consumer.
+func (d *debugDumper) dumpTriggers(triggers []annotation.FullTrigger) error {
+	if d == nil {
+		return nil
+	}
+	var b strings.Builder
+	b.WriteString("digraph triggers {\n\trankdir=LR;\n\tnode [shape=box, fontname=monospace];\n")
+	ids := make(map[string]string)
+	nodeID := func(kind string, label string) string {
+		key := kind + "\x00" + label
+		if id, ok := ids[key]; ok {
+			return id
+		}
+		id := fmt.Sprintf("%s%d", kind, len(ids))
+		ids[key] = id
+		fmt.Fprintf(&b, "\t%s [label=\"%s\"];\n", id, dotLabel(label))
+		return id
+	}
+	for _, trigger := range triggers {
+		producer := nodeID("p", fmt.Sprintf("%s\n%s", trigger.Producer.Annotation.Prestring(), d.exprString(trigger.Producer.Expr)))
+		consumer := nodeID("c", fmt.Sprintf("%s\n%s", trigger.Consumer.Annotation.Prestring(), d.pass.Fset.Position(trigger.Consumer.Pos())))
+		attrs := ""
+		if trigger.Controlled() {
+			attrs = fmt.Sprintf(" [label=\"%s\"]", dotLabel("controlled by "+trigger.Controller.String()))
+		}
+		fmt.Fprintf(&b, "\t%s -> %s%s;\n", producer, consumer, attrs)
+	}
+	b.WriteString("}\n")
+	return d.write("triggers", b.String())
+}
+
+// nodeString returns the source representation of a CFG node.
+func (d *debugDumper) nodeString(node ast.Node) string {
+	var b strings.Builder
+	if err := printer.Fprint(&b, d.pass.Fset, node); err != nil {
+		return fmt.Sprintf("<%T>", node)
+	}
+	return b.String()
+}
+
+// exprString returns the source representation and the position of an expression, if any.
+func (d *debugDumper) exprString(expr ast.Expr) string {
+	if expr == nil {
+		return ""
+	}
+	s := d.nodeString(expr)
+	if expr.Pos().IsValid() {
+		s += " at " + d.pass.Fset.Position(expr.Pos()).String()
+	}
+	return s
+}
+
+// write writes the content to "<prefix>.<suffix>.dot".
+func (d *debugDumper) write(suffix string, content string) error {
+	if err := os.MkdirAll(filepath.Dir(d.prefix), 0o755); err != nil {
+		return fmt.Errorf("create debug dump directory: %w", err)
+	}
+	if err := os.WriteFile(d.prefix+"."+suffix+".dot", []byte(content), 0o644); err != nil {
+		return fmt.Errorf("write debug dump: %w", err)
+	}
+	return nil
+}
+
+// dotLabel returns the escaped DOT label consisting of the given (left-justified) lines, where
+// long lines are truncated.
+func dotLabel(lines ...string) string {
+	var b strings.Builder
+	for _, line := range lines {
+		for _, l := range strings.Split(line, "\n") {
+			if len(l) > _debugDumpMaxLineLen {
+				l = l[:_debugDumpMaxLineLen-3] + "..."
+			}
+			l = strings.ReplaceAll(l, `\`, `\\`)
+			l = strings.ReplaceAll(l, `"`, `\"`)
+			b.WriteString(l + `\l`)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,199 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assertiontree
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"go.uber.org/nilaway/annotation"
+	"go.uber.org/nilaway/hook"
+	"go.uber.org/nilaway/util"
+	"golang.org/x/tools/go/analysis"
+)
+
+// deferredResultAssign is an assignment to a named result variable in a deferred function
+// literal, e.g., `defer func() { p = nil }()`, which overrides the returned value when the
+// function returns.
+type deferredResultAssign struct {
+	// deferPos is the position of the defer statement: only the returns after it are affected.
+	deferPos token.Pos
+	// resultIndex is the index of the assigned named result variable.
+	resultIndex int
+	// value is the assigned expression.
+	value ast.Expr
+}
+
+// collectDeferredResultAssigns finds the assignments to the named result variables of the
+// function in the function literals directly deferred in its body. The assigned values are only
+// collected if they do not refer to variables declared in the function literals, since they are
+// evaluated in the context of the returns of the enclosing function. Error-returning and
+// ok-returning functions are skipped, since the deferred assignments there are typically
+// guarded by the error (or ok) result, e.g., `defer func() { if err != nil { p = nil } }()`, which
+// the error contract already accounts for.
+func collectDeferredResultAssigns(pass *analysis.Pass, decl *ast.FuncDecl) []deferredResultAssign {
+	if decl == nil || decl.Body == nil || decl.Type.Results == nil {
+		return nil
+	}
+	funcObj, ok := pass.TypesInfo.ObjectOf(decl.Name).(*types.Func)
+	if !ok || util.FuncIsErrReturning(funcObj) || util.FuncIsOkReturning(funcObj) {
+		return nil
+	}
+
+	// map the named result variables to their indices
+	results := make(map[types.Object]int)
+	i := 0
+	for _, field := range decl.Type.Results.List {
+		for _, name := range field.Names {
+			if obj := pass.TypesInfo.Defs[name]; obj != nil && name.Name != "_" {
+				results[obj] = i
+			}
+			i++
+		}
+	}
+	if len(results) == 0 {
+		return nil
+	}
+
+	var assigns []deferredResultAssign
+	ast.Inspect(decl.Body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncLit:
+			// function literals that are not directly deferred are not executed at the returns
+			return false
+		case *ast.DeferStmt:
+			funcLit, ok := node.Call.Fun.(*ast.FuncLit)
+			if !ok {
+				return true
+			}
+			ast.Inspect(funcLit.Body, func(inner ast.Node) bool {
+				switch inner := inner.(type) {
+				case *ast.FuncLit:
+					return false
+				case *ast.AssignStmt:
+					if inner.Tok != token.ASSIGN || len(inner.Lhs) != len(inner.Rhs) {
+						return true
+					}
+					for j, lhs := range inner.Lhs {
+						ident, ok := lhs.(*ast.Ident)
+						if !ok {
+							continue
+						}
+						index, ok := results[pass.TypesInfo.Uses[ident]]
+						if !ok || refersToLocalOf(pass, inner.Rhs[j], funcLit) {
+							continue
+						}
+						assigns = append(assigns, deferredResultAssign{
+							deferPos:    node.Pos(),
+							resultIndex: index,
+							value:       inner.Rhs[j],
+						})
+					}
+				}
+				return true
+			})
+			return false
+		}
+		return true
+	})
+	return assigns
+}
+
+// refersToLocalOf returns true if the expression refers to a variable declared in the function
+// literal (including its parameters).
+func refersToLocalOf(pass *analysis.Pass, expr ast.Expr, funcLit *ast.FuncLit) bool {
+	found := false
+	ast.Inspect(expr, func(node ast.Node) bool {
+		if ident, ok := node.(*ast.Ident); ok {
+			if v, ok := pass.TypesInfo.Uses[ident].(*types.Var); ok &&
+				v.Pos() >= funcLit.Pos() && v.Pos() < funcLit.End() {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// consumeDeferredResultAssigns adds the return consumers for the values assigned to the named
+// result variables by the deferred function literals preceding the return statement, since
+// they may override the returned values.
+func consumeDeferredResultAssigns(rootNode *RootAssertionNode, node *ast.ReturnStmt) {
+	for _, assign := range rootNode.functionContext.deferredResultAssigns {
+		if assign.deferPos > node.Pos() {
+			continue
+		}
+		retKey := annotation.RetKeyFromRetNum(rootNode.FuncObj(), assign.resultIndex)
+		addReturnConsumers(rootNode, node, assign.value, retKey, true /* isNamedReturn */)
+	}
+}
+
+// deferredCallback is a function literal passed to a trusted function that invokes it when the
+// calling function returns (see hook.CallbackAssumption), e.g., `t.Cleanup(func() { srv.Close() })`.
+type deferredCallback struct {
+	// callPos is the position of the registering call: only the returns after it are affected.
+	callPos token.Pos
+	// funcLit is the registered function literal.
+	funcLit *ast.FuncLit
+}
+
+// collectDeferredCallbacks finds the function literals registered to be invoked when the function
+// returns. Only the registrations that are statements directly in the function body are
+// collected, such that every return after them is known to be preceded by the registration.
+func collectDeferredCallbacks(pass *analysis.Pass, decl *ast.FuncDecl, funcLit *ast.FuncLit) []deferredCallback {
+	var body *ast.BlockStmt
+	switch {
+	case funcLit != nil:
+		body = funcLit.Body
+	case decl != nil:
+		body = decl.Body
+	}
+	if body == nil {
+		return nil
+	}
+
+	var callbacks []deferredCallback
+	for _, stmt := range body.List {
+		exprStmt, ok := stmt.(*ast.ExprStmt)
+		if !ok {
+			continue
+		}
+		call, ok := ast.Unparen(exprStmt.X).(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		assumption, ok := hook.AssumeCallback(pass, call)
+		if !ok || !assumption.Deferred {
+			continue
+		}
+		if lit, ok := ast.Unparen(call.Args[assumption.ArgNum]).(*ast.FuncLit); ok {
+			callbacks = append(callbacks, deferredCallback{callPos: call.Pos(), funcLit: lit})
+		}
+	}
+	return callbacks
+}
+
+// consumeDeferredCallbacks adds the argument consumers for the variables captured by the function
+// literals registered before the return statement to be invoked when the function returns, since
+// they are evaluated at the return (see consumeInvokedClosureVars).
+func consumeDeferredCallbacks(rootNode *RootAssertionNode, node *ast.ReturnStmt) {
+	for _, callback := range rootNode.functionContext.deferredCallbacks {
+		if callback.callPos > node.Pos() {
+			continue
+		}
+		rootNode.consumeInvokedClosureVars(callback.funcLit)
+	}
+}
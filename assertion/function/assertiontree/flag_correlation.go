@@ -0,0 +1,303 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assertiontree
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"slices"
+
+	"go.uber.org/nilaway/annotation"
+	"go.uber.org/nilaway/util"
+	"go.uber.org/nilaway/util/asthelper"
+	"golang.org/x/tools/go/cfg"
+)
+
+// A FlagCorrelation is a RichCheckEffect for a local boolean flag that is set alongside a value,
+// e.g., `found` and `p` in:
+//
+//	var p *T
+//	found := false
+//	for _, x := range xs {
+//		if x.Match() {
+//			p = x
+//			found = true
+//		}
+//	}
+//	if found {
+//		p.Use()
+//	}
+//
+// The flag is correlated with the value if every assignment of true to the flag is preceded by an
+// assignment to the value in the same block, and all other assignments to the flag assign false.
+// Then, in the true branch of a check on the flag, the consumptions of the value are guarded. The
+// guarded consumptions reaching a reset of the flag (e.g., its definition) during backpropagation,
+// without being consumed by an assignment to the value first, are dropped: such paths cannot reach
+// the check with the flag being true.
+//
+// Unlike the other RichCheckEffects, a FlagCorrelation is never invalidated and holds in every
+// block: all assignments to the flag are accounted for when establishing the correlation, and its
+// soundness relies on the resets rather than on the flow of the effect.
+type FlagCorrelation struct {
+	root  *RootAssertionNode // an associated root node
+	flag  TrackableExpr      // the boolean flag
+	value TrackableExpr      // the value set alongside the flag
+	guard util.GuardNonce    // the guard to be applied on a matching check
+	// resets are the nodes right after which the flag is known to be false unless the value is
+	// assigned later, i.e., the assignments of false to the flag (including its definition), and
+	// the definition of the value if it follows the definition of the flag in the same block.
+	resets []ast.Node
+}
+
+func (f *FlagCorrelation) isTriggeredBy(expr ast.Expr) bool {
+	return exprMatchesTrackableExpr(f.root, expr, f.flag)
+}
+
+func (f *FlagCorrelation) isInvalidatedBy(ast.Node) bool { return false }
+
+func (f *FlagCorrelation) effectIfTrue(node *RootAssertionNode) {
+	guardExpr(node, f.value, f.guard)
+}
+
+func (f *FlagCorrelation) effectIfFalse(*RootAssertionNode) {
+	// no-op
+}
+
+// effectOnReset drops the consumptions of the value guarded by a check on the flag, since they
+// cannot reach the check with the flag being true.
+func (f *FlagCorrelation) effectOnReset(node *RootAssertionNode) {
+	dropGuardedExpr(node, f.value, f.guard)
+}
+
+func (*FlagCorrelation) isNoop() bool { return false }
+
+func (f *FlagCorrelation) equals(effect RichCheckEffect) bool {
+	other, ok := effect.(*FlagCorrelation)
+	if !ok {
+		return false
+	}
+	return f.root.Equal(f.flag, other.flag) && f.root.Equal(f.value, other.value) && f.guard == other.guard
+}
+
+// dropGuardedExpr removes the consume triggers guarded by the passed GuardNonce from the assertion
+// node corresponding to the passed expression (if such a node exists).
+func dropGuardedExpr(rootNode *RootAssertionNode, expr TrackableExpr, guard util.GuardNonce) {
+	lookedUpNode, _ := rootNode.lookupPath(expr)
+	if lookedUpNode == nil {
+		return
+	}
+	rootNode.invalidateParseCache()
+	consumers := slices.Clone(lookedUpNode.ConsumeTriggers())
+	lookedUpNode.SetConsumeTriggers(slices.DeleteFunc(consumers, func(c *annotation.ConsumeTrigger) bool {
+		return c.Guards.Contains(guard)
+	}))
+}
+
+// flagCorrelationsFromCFG finds the local boolean flags correlated with local values in the CFG
+// (see FlagCorrelation), and returns the FlagCorrelation effects.
+func flagCorrelationsFromCFG(rootNode *RootAssertionNode, nonceGenerator *util.GuardNonceGenerator, graph *cfg.CFG) []RichCheckEffect {
+	funcDecl := rootNode.FuncDecl()
+	if funcDecl == nil || funcDecl.Body == nil {
+		return nil
+	}
+
+	type location struct {
+		block *cfg.Block
+		index int
+	}
+	locations := make(map[ast.Node]location)
+	for _, block := range graph.Blocks {
+		for i, node := range block.Nodes {
+			locations[node] = location{block: block, index: i}
+		}
+	}
+
+	// Collect the definitions of the local variables in the CFG, which are the candidates for the
+	// flags (if they are booleans defined as false) and the values.
+	type definition struct {
+		node  ast.Node
+		ident *ast.Ident
+	}
+	defs := make(map[*types.Var]definition)
+	var flags []*types.Var
+	for _, block := range graph.Blocks {
+		for _, node := range block.Nodes {
+			if assign, ok := node.(*ast.AssignStmt); ok && assign.Tok != token.DEFINE {
+				continue
+			}
+			lhs, rhs := asthelper.ExtractLHSRHS(node)
+			for i, l := range lhs {
+				ident, ok := l.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				v, ok := rootNode.Pass().TypesInfo.Defs[ident].(*types.Var)
+				if !ok {
+					continue
+				}
+				defs[v] = definition{node: node, ident: ident}
+				if !isBoolType(v.Type()) {
+					continue
+				}
+				if len(rhs) == 0 {
+					// `var flag bool`
+					flags = append(flags, v)
+				} else if len(rhs) == len(lhs) {
+					if val, ok := boolConstant(rootNode, rhs[i]); ok && !val {
+						flags = append(flags, v)
+					}
+				}
+			}
+		}
+	}
+	if len(flags) == 0 {
+		return nil
+	}
+
+	// Collect the sets and resets of the flags, disqualifying the flags that are assigned
+	// non-constant values, assigned outside the CFG (e.g., in closures) or have their addresses
+	// taken.
+	sets := make(map[*types.Var][]ast.Node)
+	resets := make(map[*types.Var][]ast.Node)
+	disqualified := make(map[*types.Var]bool)
+	isFlag := func(expr ast.Expr) (*types.Var, bool) {
+		ident, ok := ast.Unparen(expr).(*ast.Ident)
+		if !ok {
+			return nil, false
+		}
+		v, ok := rootNode.Pass().TypesInfo.ObjectOf(ident).(*types.Var)
+		if !ok || !slices.Contains(flags, v) {
+			return nil, false
+		}
+		return v, true
+	}
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for i, l := range n.Lhs {
+				v, ok := isFlag(l)
+				if !ok || defs[v].node == n {
+					continue
+				}
+				if _, ok := locations[n]; !ok || len(n.Lhs) != len(n.Rhs) {
+					disqualified[v] = true
+					continue
+				}
+				val, ok := boolConstant(rootNode, n.Rhs[i])
+				switch {
+				case !ok:
+					disqualified[v] = true
+				case val:
+					sets[v] = append(sets[v], n)
+				default:
+					resets[v] = append(resets[v], n)
+				}
+			}
+		case *ast.UnaryExpr:
+			if v, ok := isFlag(n.X); ok && n.Op == token.AND {
+				disqualified[v] = true
+			}
+		case *ast.RangeStmt:
+			for _, expr := range [...]ast.Expr{n.Key, n.Value} {
+				if v, ok := isFlag(expr); ok {
+					disqualified[v] = true
+				}
+			}
+		}
+		return true
+	})
+
+	var effects []RichCheckEffect
+	for _, flag := range flags {
+		if disqualified[flag] || len(sets[flag]) == 0 {
+			continue
+		}
+
+		// The correlated values are the local variables with nilable types assigned before every
+		// set of the flag in the same block.
+		var values []*types.Var
+		for i, set := range sets[flag] {
+			loc := locations[set]
+			var assigned []*types.Var
+			for _, node := range loc.block.Nodes[:loc.index] {
+				lhs, _ := asthelper.ExtractLHSRHS(node)
+				for _, l := range lhs {
+					ident, ok := l.(*ast.Ident)
+					if !ok {
+						continue
+					}
+					v, ok := rootNode.Pass().TypesInfo.ObjectOf(ident).(*types.Var)
+					if !ok || v == flag || util.TypeBarsNilness(v.Type()) {
+						continue
+					}
+					if _, ok := defs[v]; ok && !slices.Contains(assigned, v) {
+						assigned = append(assigned, v)
+					}
+				}
+			}
+			if i == 0 {
+				values = assigned
+			} else {
+				values = slices.DeleteFunc(values, func(v *types.Var) bool { return !slices.Contains(assigned, v) })
+			}
+		}
+
+		flagDef := defs[flag]
+		flagParsed := parseExpr(rootNode, flagDef.ident)
+		if flagParsed == nil {
+			continue
+		}
+		for _, value := range values {
+			valueDef := defs[value]
+			valueParsed := parseExpr(rootNode, valueDef.ident)
+			if valueParsed == nil {
+				continue
+			}
+			flagResets := append([]ast.Node{flagDef.node}, resets[flag]...)
+			// The flag is also known to be false right after the definition of the value, if the
+			// value is defined after the flag in the same block (since the flag can only be set
+			// after the value is assigned).
+			flagLoc, valueLoc := locations[flagDef.node], locations[valueDef.node]
+			if flagLoc.block == valueLoc.block && flagLoc.index < valueLoc.index {
+				flagResets = append(flagResets, valueDef.node)
+			}
+			effects = append(effects, &FlagCorrelation{
+				root:   rootNode,
+				flag:   flagParsed,
+				value:  valueParsed,
+				guard:  nonceGenerator.Next(flagDef.ident),
+				resets: flagResets,
+			})
+		}
+	}
+	return effects
+}
+
+// isBoolType returns true if the underlying type of the passed type is a boolean type.
+func isBoolType(t types.Type) bool {
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsBoolean != 0
+}
+
+// boolConstant returns the value of the passed expression if it is a boolean constant.
+func boolConstant(rootNode *RootAssertionNode, expr ast.Expr) (bool, bool) {
+	tv, ok := rootNode.Pass().TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.Bool {
+		return false, false
+	}
+	return constant.BoolVal(tv.Value), true
+}
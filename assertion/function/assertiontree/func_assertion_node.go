@@ -29,6 +29,9 @@ type funcAssertionNode struct {
 	// declaring identifier for this function
 	decl *types.Func
 	args []ast.Expr
+	// trusted is the assumed nilability of the result of a trusted method (see hook.AssumeReturn),
+	// which overrides its return annotation if set.
+	trusted annotation.ProducingAnnotationTrigger
 }
 
 func (f *funcAssertionNode) MinimalString() string {
@@ -41,6 +44,10 @@ func (f *funcAssertionNode) DefaultTrigger() annotation.ProducingAnnotationTrigg
 		panic("only functions with singular result should be entered into the assertion tree")
 	}
 
+	if f.trusted != nil {
+		return f.trusted
+	}
+
 	if f.decl.Type().(*types.Signature).Recv() != nil {
 		return &annotation.MethodReturn{
 			TriggerIfNilable: &annotation.TriggerIfNilable{
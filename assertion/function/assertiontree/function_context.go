@@ -17,6 +17,7 @@ package assertiontree
 import (
 	"go/ast"
 	"go/types"
+	"regexp"
 
 	"go.uber.org/nilaway/assertion/anonymousfunc"
 	"go.uber.org/nilaway/assertion/function/functioncontracts"
@@ -44,6 +45,11 @@ type FunctionContext struct {
 	// analysis will not reach a fixpoint.
 	selectorExpressionCache SelectorExprMap
 
+	// promotionCache caches the desugared forms of selector expressions that access promoted
+	// fields or methods through embedded fields (see RootAssertionNode.desugarPromotion), for the
+	// same reason as selectorExpressionCache.
+	promotionCache map[*ast.SelectorExpr]*ast.SelectorExpr
+
 	// fakeIdentMap is used to undo the creation of fake identifiers as sometimes needed
 	// (see annotation.GetObjByIdent) - This is not really a hack - it exists exactly to
 	// make up for the fact that some types.Objects just aren't matched with an AST node
@@ -63,14 +69,59 @@ type FunctionContext struct {
 
 	// funcContracts stores the function contracts of all the functions.
 	funcContracts functioncontracts.Map
+
+	// deferredResultAssigns stores the assignments to the named result variables in the deferred
+	// function literals of the function (see collectDeferredResultAssigns).
+	deferredResultAssigns []deferredResultAssign
+
+	// deferredCallbacks stores the function literals registered in the function to be invoked when
+	// it returns (see collectDeferredCallbacks).
+	deferredCallbacks []deferredCallback
+
+	// assignedFields stores the struct fields assigned anywhere in the function (see
+	// collectAssignedFields).
+	assignedFields map[*types.Var]bool
+
+	// initializedFields stores the struct fields assigned right after the constructions of the
+	// structs in the function, keyed by the constructions (see collectInitializedFields).
+	initializedFields map[ast.Expr]map[*types.Var]bool
+
+	// loopVars stores the variables declared by the loops of the function (see collectLoopVars).
+	loopVars loopVars
+
+	// groupResults stores the results populated by the goroutines of the groups of the function
+	// (see collectGroupResults).
+	groupResults groupResults
 }
 
 // FunctionConfig is meant to hold all the user set configuration for analyzing a function
 type FunctionConfig struct {
 	// EnableStructInitCheck is a flag to enable tracking struct initializations.
 	EnableStructInitCheck bool
-	// EnableAnonymousFunc is a flag to enable checking anonymous functions.
-	EnableAnonymousFunc bool
+	// EnableGroupResults is a flag to enable the heuristic for the results populated by the
+	// goroutines of a group (see groupResults).
+	EnableGroupResults bool
+	// DisableParseCache is a flag to disable the memoization of ParseExprAsProducer results.
+	DisableParseCache bool
+	// MaxTreeWidth is the maximum number of children of a root assertion node, 0 means no limit.
+	MaxTreeWidth int
+	// EnforcedNonNilFields is the set of fields annotated with a field-level `//nonnil`, which
+	// must be initialized with nonnil values in composite literals.
+	EnforcedNonNilFields map[*types.Var]bool
+	// OkReceiverReads is the set of ok-returning methods wrapping comma-ok reads of their receiver
+	// maps, whose receivers are nonnil if the returned `ok` is true (see OkReceiverReads).
+	OkReceiverReads map[*types.Func]bool
+	// DebugDumpDir is the directory to write the DOT graphs of the intermediate states of the
+	// backpropagation to (see debugDumper), empty means disabled.
+	DebugDumpDir string
+	// DebugDumpFuncs selects the functions to dump by their full names, nil means all functions.
+	DebugDumpFuncs *regexp.Regexp
+	// EnableReflectEscape is a flag to conservatively consume the values escaping via reflection,
+	// `unsafe.Pointer` conversions, or cgo calls (see reflectEscapeVia).
+	EnableReflectEscape bool
+	// ErrorOnNilableMapRead is a flag to consume the maps read from (e.g., `m[k]`), such that the
+	// reads from possibly-nil maps are reported.
+	ErrorOnNilableMapRead bool
 }
 
 // NewFunctionContext returns a new FunctionContext and initializes all the maps
@@ -89,10 +140,17 @@ func NewFunctionContext(
 		funcLit:                 funcLit,
 		fakeIdentMap:            make(map[*ast.Ident]types.Object),
 		selectorExpressionCache: make(SelectorExprMap),
+		promotionCache:          make(map[*ast.SelectorExpr]*ast.SelectorExpr),
 		functionConfig:          functionConfig,
 		funcLitMap:              funcLitMap,
 		pkgFakeIdentMap:         pkgFakeIdentMap,
 		funcContracts:           funcContracts,
+		deferredResultAssigns:   collectDeferredResultAssigns(pass, decl),
+		deferredCallbacks:       collectDeferredCallbacks(pass, decl, funcLit),
+		assignedFields:          collectAssignedFields(pass, decl, funcLit),
+		initializedFields:       collectInitializedFields(pass, decl, funcLit),
+		loopVars:                collectLoopVars(pass, decl, funcLit),
+		groupResults:            collectGroupResults(pass, decl, funcLit, functionConfig),
 	}
 }
 
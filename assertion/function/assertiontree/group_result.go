@@ -0,0 +1,273 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assertiontree
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"go.uber.org/nilaway/annotation"
+	"go.uber.org/nilaway/hook"
+	"go.uber.org/nilaway/util"
+	"golang.org/x/tools/go/analysis"
+)
+
+// groupResults maps the groups of goroutines of a function (i.e., the variables of the trusted
+// group types such as `errgroup.Group` and `sync.WaitGroup`, see hook.AssumeGroupCall) to the
+// results populated by their goroutines. For example, in
+//
+//	results := make([]*T, n)
+//	var res *R
+//	g.Go(func() error { results[i], err = fetch(i); return err })
+//	g.Go(func() (err error) { res, err = get(); return })
+//	if err := g.Wait(); err != nil { return err }
+//	results[0].Use(); res.Use()
+//
+// the goroutines of `g` populate `res` and the indices of `results`, which are read once `g.Wait()`
+// returns a nil error. Since the goroutines are not analyzed as part of the function, the reads
+// would otherwise be reported, e.g., as the reads of the unassigned `res`. Instead, with the
+// heuristic enabled (see FunctionConfig.EnableGroupResults), the populated results are assumed to
+// be nonnil after the wait (see populateGroupResults), which holds if the goroutines only populate
+// them on success (i.e., a nil error implies all goroutines succeeded).
+type groupResults map[*types.Var]*populatedResults
+
+// populatedResults are the results populated by the goroutines of a group.
+type populatedResults struct {
+	// vars are the variables assigned by the goroutines (e.g., `res` in `res, err = get()`).
+	vars []*ast.Ident
+	// indexed are the variables whose indices are assigned by the goroutines (e.g., `results` in
+	// `results[i] = r`), which are tracked per index.
+	indexed []*ast.Ident
+}
+
+// collectGroupResults collects the results populated by the goroutines of the groups of the
+// function body (see groupResults). Only the goroutines spawned with function literals are
+// considered, i.e., `g.Go(func() error { ... })` and `go func() { defer wg.Done(); ... }()`, and
+// only the assignments to the local variables (and their indices) of the function are collected.
+// The error variables are skipped since they are populated on failure instead.
+func collectGroupResults(pass *analysis.Pass, decl *ast.FuncDecl, funcLit *ast.FuncLit, functionConfig FunctionConfig) groupResults {
+	if !functionConfig.EnableGroupResults {
+		return nil
+	}
+	var body *ast.BlockStmt
+	switch {
+	case funcLit != nil:
+		body = funcLit.Body
+	case decl != nil:
+		body = decl.Body
+	}
+	if body == nil {
+		return nil
+	}
+
+	// groupOf returns the group variable of the given call to a method of a group with the given
+	// kind, or nil if the call is not such a call or its receiver is not a variable.
+	groupOf := func(call *ast.CallExpr, kind hook.GroupCall) *types.Var {
+		sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+		if !ok || hook.AssumeGroupCall(pass, call) != kind {
+			return nil
+		}
+		ident, ok := ast.Unparen(sel.X).(*ast.Ident)
+		if !ok {
+			return nil
+		}
+		v, _ := pass.TypesInfo.Uses[ident].(*types.Var)
+		return v
+	}
+
+	// isResult returns true if the given identifier refers to a local variable of the function
+	// that is declared outside the goroutine and can hold a nilable (non-error) result.
+	isResult := func(ident *ast.Ident, goroutine *ast.FuncLit, elem bool) bool {
+		v, ok := pass.TypesInfo.Uses[ident].(*types.Var)
+		if !ok || v.Pos() < body.Pos() || v.Pos() >= body.End() ||
+			(v.Pos() >= goroutine.Pos() && v.Pos() < goroutine.End()) {
+			return false
+		}
+		t := v.Type()
+		if elem {
+			switch u := t.Underlying().(type) {
+			case *types.Slice:
+				t = u.Elem()
+			case *types.Array:
+				t = u.Elem()
+			case *types.Map:
+				t = u.Elem()
+			default:
+				return false
+			}
+		}
+		return !types.Identical(t, util.ErrorType)
+	}
+
+	results := make(groupResults)
+	type groupResult struct {
+		group  *types.Var
+		result types.Object
+	}
+	seen := make(map[groupResult]bool)
+	populate := func(group *types.Var, goroutine *ast.FuncLit) {
+		if results[group] == nil {
+			results[group] = &populatedResults{}
+		}
+		populated := results[group]
+		// firstSeen returns true if the result is seen for the first time for the group
+		firstSeen := func(ident *ast.Ident) bool {
+			key := groupResult{group: group, result: pass.TypesInfo.Uses[ident]}
+			if seen[key] {
+				return false
+			}
+			seen[key] = true
+			return true
+		}
+		ast.Inspect(goroutine.Body, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.AssignStmt:
+				if node.Tok != token.ASSIGN {
+					return true
+				}
+				for _, lhs := range node.Lhs {
+					switch lhs := ast.Unparen(lhs).(type) {
+					case *ast.Ident:
+						if isResult(lhs, goroutine, false) && firstSeen(lhs) {
+							populated.vars = append(populated.vars, lhs)
+						}
+					case *ast.IndexExpr:
+						x, ok := ast.Unparen(lhs.X).(*ast.Ident)
+						if ok && isResult(x, goroutine, true) && firstSeen(x) {
+							populated.indexed = append(populated.indexed, x)
+						}
+					}
+				}
+			}
+			return true
+		})
+	}
+
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncLit:
+			// the goroutines spawned in the nested function literals are collected in their own
+			// contexts
+			return false
+		case *ast.CallExpr:
+			// `g.Go(func() error { ... })`
+			if len(node.Args) != 1 {
+				return true
+			}
+			if goroutine, ok := ast.Unparen(node.Args[0]).(*ast.FuncLit); ok {
+				if group := groupOf(node, hook.GroupSpawn); group != nil {
+					populate(group, goroutine)
+				}
+			}
+		case *ast.GoStmt:
+			// `go func() { defer wg.Done(); ... }()`
+			goroutine, ok := ast.Unparen(node.Call.Fun).(*ast.FuncLit)
+			if !ok {
+				return true
+			}
+			var group *types.Var
+			ast.Inspect(goroutine.Body, func(inner ast.Node) bool {
+				if call, ok := inner.(*ast.CallExpr); ok && group == nil {
+					group = groupOf(call, hook.GroupDone)
+				}
+				return group == nil
+			})
+			if group != nil {
+				populate(group, goroutine)
+			}
+			return false
+		}
+		return true
+	})
+	return results
+}
+
+// populateGroupResults assumes the results populated by the goroutines of a group (see
+// groupResults) to be nonnil if the given node waits for the group, i.e., `wg.Wait()`, or
+// `g.Wait()` whose error is checked (e.g., `if err := g.Wait(); err != nil { ... }`), since the
+// results are only read once the goroutines succeeded. The variables are assumed to be assigned
+// nonnil values at the wait, and so are the tracked indices of the indexed variables.
+func populateGroupResults(rootNode *RootAssertionNode, node ast.Node) {
+	if len(rootNode.functionContext.groupResults) == 0 {
+		return
+	}
+
+	var wait *ast.CallExpr
+	switch node := node.(type) {
+	case *ast.ExprStmt:
+		// the error of `g.Wait()` is discarded here, so only `wg.Wait()` counts
+		if call, ok := ast.Unparen(node.X).(*ast.CallExpr); ok && hook.AssumeGroupCall(rootNode.Pass(), call) == hook.GroupWait {
+			wait = call
+		}
+	case *ast.AssignStmt:
+		// `err := g.Wait()`
+		if len(node.Lhs) == 1 && len(node.Rhs) == 1 && !util.IsEmptyExpr(node.Lhs[0]) {
+			if call, ok := ast.Unparen(node.Rhs[0]).(*ast.CallExpr); ok && hook.AssumeGroupCall(rootNode.Pass(), call) == hook.GroupWaitErr {
+				wait = call
+			}
+		}
+	case *ast.BinaryExpr:
+		// `g.Wait() != nil`
+		for _, operand := range [...]ast.Expr{node.X, node.Y} {
+			if call, ok := ast.Unparen(operand).(*ast.CallExpr); ok && hook.AssumeGroupCall(rootNode.Pass(), call) == hook.GroupWaitErr {
+				wait = call
+			}
+		}
+	}
+	if wait == nil {
+		return
+	}
+	sel, ok := ast.Unparen(wait.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	ident, ok := ast.Unparen(sel.X).(*ast.Ident)
+	if !ok {
+		return
+	}
+	group, ok := rootNode.ObjectOf(ident).(*types.Var)
+	if !ok || rootNode.functionContext.groupResults[group] == nil {
+		return
+	}
+	populated := rootNode.functionContext.groupResults[group]
+
+	for _, v := range populated.vars {
+		rootNode.AddProduction(&annotation.ProduceTrigger{
+			Annotation: &annotation.ProduceTriggerNever{},
+			Expr:       v,
+		})
+	}
+	for _, v := range populated.indexed {
+		path, _ := rootNode.ParseExprAsProducer(v, false)
+		node, _ := rootNode.lookupPath(path)
+		if node == nil {
+			continue
+		}
+		children := node.Children()
+		for i := len(children) - 1; i >= 0; i-- {
+			if child, ok := children[i].(*indexAssertionNode); ok {
+				rootNode.invalidateParseCache()
+				rootNode.triggerProductions(child, &annotation.ProduceTrigger{
+					Annotation: &annotation.ProduceTriggerNever{},
+					Expr:       v,
+				})
+				detachFromParent(child, i)
+			}
+		}
+	}
+}
@@ -0,0 +1,330 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assertiontree
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"slices"
+
+	"go.uber.org/nilaway/annotation"
+	"go.uber.org/nilaway/util"
+	"golang.org/x/tools/go/cfg"
+)
+
+// An IterationCorrelation is a RichCheckEffect for the index of a loop that is checked to not be
+// at the first iteration, correlated with a value assigned in every iteration of the loop, e.g.,
+// `i` and `prev` in:
+//
+//	var prev *T
+//	for i, cur := range xs {
+//		if i > 0 {
+//			prev.Use()
+//		}
+//		prev = cur
+//	}
+//
+// The index starts at zero and only increases, so a check like `i > 0` holds exactly when an
+// earlier iteration has completed. The value is correlated with the index if it is assigned by a
+// top-level statement of the loop body that cannot be skipped by a `continue` or `goto`, so an
+// earlier iteration must have assigned it. Then, in the true branch of the check, the consumptions
+// of the value are guarded, and the guarded consumptions reaching the entry of the loop (i.e., the
+// first iteration) during backpropagation are dropped, just like for a FlagCorrelation. Only the
+// values assigned in the loop body (e.g., `prev = cur`) can therefore flow to such consumptions,
+// rather than the values from before the loop (e.g., the unassigned `prev`).
+//
+// Like a FlagCorrelation, an IterationCorrelation is never invalidated and holds in every block.
+type IterationCorrelation struct {
+	root  *RootAssertionNode // an associated root node
+	index *types.Var         // the index of the loop
+	value TrackableExpr      // the value assigned in every iteration of the loop
+	guard util.GuardNonce    // the guard to be applied on a matching check
+	entry ast.Node           // the node right after which the loop is entered for the first time
+}
+
+func (c *IterationCorrelation) isTriggeredBy(expr ast.Expr) bool {
+	return exprChecksNotFirstIteration(c.root, expr, c.index)
+}
+
+func (c *IterationCorrelation) isInvalidatedBy(ast.Node) bool { return false }
+
+func (c *IterationCorrelation) effectIfTrue(node *RootAssertionNode) {
+	guardExpr(node, c.value, c.guard)
+}
+
+func (c *IterationCorrelation) effectIfFalse(*RootAssertionNode) {
+	// no-op
+}
+
+// effectOnEntry drops the consumptions of the value guarded by a check on the index, since they
+// cannot be reached in the first iteration of the loop.
+func (c *IterationCorrelation) effectOnEntry(node *RootAssertionNode) {
+	dropGuardedExpr(node, c.value, c.guard)
+}
+
+func (*IterationCorrelation) isNoop() bool { return false }
+
+func (c *IterationCorrelation) equals(effect RichCheckEffect) bool {
+	other, ok := effect.(*IterationCorrelation)
+	if !ok {
+		return false
+	}
+	return c.index == other.index && c.root.Equal(c.value, other.value) && c.guard == other.guard
+}
+
+// exprChecksNotFirstIteration returns true if the passed expression checks that the passed loop
+// index is positive, i.e., one of `i > 0`, `i != 0` and `i >= 1` (or their mirrored forms).
+func exprChecksNotFirstIteration(rootNode *RootAssertionNode, expr ast.Expr, index *types.Var) bool {
+	binExpr, ok := ast.Unparen(expr).(*ast.BinaryExpr)
+	if !ok {
+		return false
+	}
+	isIndex := func(expr ast.Expr) bool {
+		ident, ok := ast.Unparen(expr).(*ast.Ident)
+		return ok && rootNode.Pass().TypesInfo.Uses[ident] == index
+	}
+
+	op, lhs, rhs := binExpr.Op, binExpr.X, binExpr.Y
+	if !isIndex(lhs) {
+		// normalize the mirrored forms, e.g., `0 < i` to `i > 0`
+		lhs, rhs = rhs, lhs
+		switch op {
+		case token.LSS:
+			op = token.GTR
+		case token.LEQ:
+			op = token.GEQ
+		}
+	}
+	if !isIndex(lhs) {
+		return false
+	}
+	val, ok := intConstant(rootNode, rhs)
+	if !ok {
+		return false
+	}
+	switch op {
+	case token.GTR, token.NEQ:
+		return val == 0
+	case token.GEQ:
+		return val == 1
+	}
+	return false
+}
+
+// iterationCorrelationsFromCFG finds the loop indices correlated with the values assigned in
+// every iteration of their loops in the CFG (see IterationCorrelation), and returns the
+// IterationCorrelation effects.
+func iterationCorrelationsFromCFG(rootNode *RootAssertionNode, nonceGenerator *util.GuardNonceGenerator, graph *cfg.CFG) []RichCheckEffect {
+	funcDecl := rootNode.FuncDecl()
+	if funcDecl == nil || funcDecl.Body == nil {
+		return nil
+	}
+
+	inCFG := make(map[ast.Node]bool)
+	rangeEntries := make(map[ast.Expr]ast.Node)
+	for _, block := range graph.Blocks {
+		for _, node := range block.Nodes {
+			inCFG[node] = true
+		}
+		// The ranging assignment is the last node of the block preceding a range loop.
+		if rangeExpr := getRangeExpr(block); rangeExpr != nil {
+			rangeEntries[rangeExpr] = block.Nodes[len(block.Nodes)-1]
+		}
+	}
+
+	var effects []RichCheckEffect
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		var (
+			index *ast.Ident
+			entry ast.Node
+			body  *ast.BlockStmt
+		)
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			// closures are not part of the CFG
+			return false
+		case *ast.RangeStmt:
+			index, entry, body = rangeIndex(rootNode, n), rangeEntries[n.X], n.Body
+		case *ast.ForStmt:
+			if inCFG[n.Init] {
+				index, entry, body = forIndex(rootNode, n), n.Init, n.Body
+			}
+		default:
+			return true
+		}
+		if index == nil || entry == nil {
+			return true
+		}
+		indexVar, ok := rootNode.Pass().TypesInfo.Defs[index].(*types.Var)
+		if !ok || varIsModifiedIn(rootNode, indexVar, body) {
+			return true
+		}
+		for _, value := range valuesAssignedEveryIteration(rootNode, n, body) {
+			parsed := parseExpr(rootNode, value)
+			if parsed == nil {
+				continue
+			}
+			effects = append(effects, &IterationCorrelation{
+				root:  rootNode,
+				index: indexVar,
+				value: parsed,
+				guard: nonceGenerator.Next(index),
+				entry: entry,
+			})
+		}
+		return true
+	})
+	return effects
+}
+
+// rangeIndex returns the index defined by the passed range statement if it starts at zero and
+// increases by one in every iteration, i.e., if the ranged value is a slice, an array (or a
+// pointer to an array), a string or an integer. Otherwise, it returns nil.
+func rangeIndex(rootNode *RootAssertionNode, rangeStmt *ast.RangeStmt) *ast.Ident {
+	ident, ok := rangeStmt.Key.(*ast.Ident)
+	if !ok || rangeStmt.Tok != token.DEFINE || ident.Name == "_" {
+		return nil
+	}
+	rangeType := rootNode.Pass().TypesInfo.TypeOf(rangeStmt.X)
+	if rangeType == nil {
+		return nil
+	}
+	if ptr, ok := rangeType.Underlying().(*types.Pointer); ok {
+		rangeType = ptr.Elem()
+	}
+	switch t := rangeType.Underlying().(type) {
+	case *types.Slice, *types.Array:
+		return ident
+	case *types.Basic:
+		if t.Info()&(types.IsString|types.IsInteger) != 0 {
+			return ident
+		}
+	}
+	return nil
+}
+
+// forIndex returns the index defined by the passed for statement if it starts at zero and only
+// increases, i.e., for loops of the form `for i := 0; ...; i++` (or `i += c` with a positive
+// constant `c`). Otherwise, it returns nil.
+func forIndex(rootNode *RootAssertionNode, forStmt *ast.ForStmt) *ast.Ident {
+	init, ok := forStmt.Init.(*ast.AssignStmt)
+	if !ok || init.Tok != token.DEFINE || len(init.Lhs) != 1 || len(init.Rhs) != 1 {
+		return nil
+	}
+	ident, ok := init.Lhs[0].(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	if val, ok := intConstant(rootNode, init.Rhs[0]); !ok || val != 0 {
+		return nil
+	}
+	isIndex := func(expr ast.Expr) bool {
+		postIdent, ok := ast.Unparen(expr).(*ast.Ident)
+		return ok && rootNode.Pass().TypesInfo.Uses[postIdent] == rootNode.Pass().TypesInfo.Defs[ident]
+	}
+	switch post := forStmt.Post.(type) {
+	case *ast.IncDecStmt:
+		if post.Tok == token.INC && isIndex(post.X) {
+			return ident
+		}
+	case *ast.AssignStmt:
+		if post.Tok == token.ADD_ASSIGN && len(post.Lhs) == 1 && isIndex(post.Lhs[0]) {
+			if val, ok := intConstant(rootNode, post.Rhs[0]); ok && val > 0 {
+				return ident
+			}
+		}
+	}
+	return nil
+}
+
+// varIsModifiedIn returns true if the passed variable is assigned, incremented or decremented, or
+// has its address taken anywhere in the passed node.
+func varIsModifiedIn(rootNode *RootAssertionNode, v *types.Var, node ast.Node) bool {
+	isVar := func(expr ast.Expr) bool {
+		ident, ok := ast.Unparen(expr).(*ast.Ident)
+		return ok && rootNode.Pass().TypesInfo.ObjectOf(ident) == v
+	}
+	modified := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			modified = modified || slices.ContainsFunc(n.Lhs, isVar)
+		case *ast.IncDecStmt:
+			modified = modified || isVar(n.X)
+		case *ast.UnaryExpr:
+			modified = modified || (n.Op == token.AND && isVar(n.X))
+		case *ast.RangeStmt:
+			modified = modified || isVar(n.Key) || isVar(n.Value)
+		}
+		return !modified
+	})
+	return modified
+}
+
+// valuesAssignedEveryIteration returns the local variables with nilable types, defined outside the
+// passed loop, that are assigned by top-level statements of the loop body that are not preceded by
+// any `continue` or `goto` (which may skip the assignments).
+func valuesAssignedEveryIteration(rootNode *RootAssertionNode, loop ast.Node, body *ast.BlockStmt) []*ast.Ident {
+	var values []*ast.Ident
+	seen := make(map[*types.Var]bool)
+	for _, stmt := range body.List {
+		if assign, ok := stmt.(*ast.AssignStmt); ok && assign.Tok == token.ASSIGN {
+			for _, lhs := range assign.Lhs {
+				ident, ok := lhs.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				v, ok := rootNode.Pass().TypesInfo.Uses[ident].(*types.Var)
+				if !ok || annotation.VarIsGlobal(v) || util.TypeBarsNilness(v.Type()) || v.Pos() >= loop.Pos() || seen[v] {
+					continue
+				}
+				seen[v] = true
+				values = append(values, ident)
+			}
+		}
+		if stmtMayBranch(stmt) {
+			break
+		}
+	}
+	return values
+}
+
+// stmtMayBranch returns true if the passed statement contains a `continue` or `goto` (outside of
+// closures).
+func stmtMayBranch(stmt ast.Stmt) bool {
+	branches := false
+	ast.Inspect(stmt, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.BranchStmt:
+			branches = branches || n.Tok == token.CONTINUE || n.Tok == token.GOTO
+		}
+		return !branches
+	})
+	return branches
+}
+
+// intConstant returns the value of the passed expression if it is an integer constant that fits in
+// an int64.
+func intConstant(rootNode *RootAssertionNode, expr ast.Expr) (int64, bool) {
+	tv, ok := rootNode.Pass().TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.Int {
+		return 0, false
+	}
+	return constant.Int64Val(tv.Value)
+}
@@ -0,0 +1,268 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assertiontree
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"go/version"
+
+	"go.uber.org/nilaway/annotation"
+	"go.uber.org/nilaway/util"
+	"golang.org/x/tools/go/analysis"
+)
+
+// _perIterationLoopVarVersion is the Go version since which the variables declared by loops are
+// created afresh for each iteration (see https://go.dev/blog/loopvar-preview).
+const _perIterationLoopVarVersion = "go1.22"
+
+// loopVar is a variable declared by a loop statement, e.g., `p` in `for _, p := range ps` or in
+// `for p := head; p != nil; p = p.next`.
+type loopVar struct {
+	// body is the body of the declaring loop statement.
+	body *ast.BlockStmt
+	// rangeX is the ranged expression if the variable is produced from its deep nilability (e.g.,
+	// the value variable in a range over a slice), nil otherwise.
+	rangeX ast.Expr
+	// rangeIdent is the identifier declaring the variable in the range statement.
+	rangeIdent *ast.Ident
+	// postValues are the values assigned to the variable by the post statement of a for loop.
+	postValues []ast.Expr
+	// bodyAssigns are the assignments to the variable in the loop body.
+	bodyAssigns []loopVarAssign
+}
+
+// loopVarAssign is an assignment to a loop variable in the loop body.
+type loopVarAssign struct {
+	// pos is the position of the assignment.
+	pos token.Pos
+	// value is the assigned expression.
+	value ast.Expr
+}
+
+// loopVars collects the variables declared by the loop statements of the function (excluding the
+// nested function literals) along with the values they may take after their declarations. The
+// assigned values are only collected if they do not refer to the variables declared in the loop
+// bodies, since they are evaluated at the `go` statements capturing the variables (see
+// consumeLoopVarCaptures).
+type loopVars struct {
+	// vars maps the loop variables to their information.
+	vars map[*types.Var]*loopVar
+	// shared indicates whether the loop variables are shared across the iterations, i.e., the
+	// file is written in a Go version older than _perIterationLoopVarVersion.
+	shared bool
+}
+
+// collectLoopVars collects the loop variables of the function body (see loopVars).
+func collectLoopVars(pass *analysis.Pass, decl *ast.FuncDecl, funcLit *ast.FuncLit) loopVars {
+	var body *ast.BlockStmt
+	switch {
+	case funcLit != nil:
+		body = funcLit.Body
+	case decl != nil:
+		body = decl.Body
+	}
+	if body == nil {
+		return loopVars{}
+	}
+
+	vars := make(map[*types.Var]*loopVar)
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncLit:
+			// the loops in the nested function literals are collected in their own contexts
+			return false
+		case *ast.RangeStmt:
+			if node.Tok != token.DEFINE {
+				return true
+			}
+			for _, lhs := range [...]ast.Expr{node.Key, node.Value} {
+				ident, ok := lhs.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				if v, ok := pass.TypesInfo.Defs[ident].(*types.Var); ok {
+					lv := &loopVar{body: node.Body, rangeIdent: ident}
+					if rangeProducesDeeply(pass, node, ident) {
+						lv.rangeX = node.X
+					}
+					vars[v] = lv
+				}
+			}
+		case *ast.ForStmt:
+			init, ok := node.Init.(*ast.AssignStmt)
+			if !ok || init.Tok != token.DEFINE {
+				return true
+			}
+			for _, lhs := range init.Lhs {
+				ident, ok := lhs.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				if v, ok := pass.TypesInfo.Defs[ident].(*types.Var); ok {
+					lv := &loopVar{body: node.Body}
+					if post, ok := node.Post.(*ast.AssignStmt); ok {
+						for _, a := range assignedValues(pass, post, v) {
+							lv.postValues = append(lv.postValues, a.value)
+						}
+					}
+					vars[v] = lv
+				}
+			}
+		case *ast.AssignStmt:
+			// The assignments are visited after the declaring loops, since they are nested in the
+			// loop bodies.
+			for v, lv := range vars {
+				if node.Pos() < lv.body.Pos() || node.End() > lv.body.End() {
+					continue
+				}
+				for _, a := range assignedValues(pass, node, v) {
+					if !refersToDeclaredIn(pass, a.value, lv.body) {
+						lv.bodyAssigns = append(lv.bodyAssigns, a)
+					}
+				}
+			}
+		}
+		return true
+	})
+	if len(vars) == 0 {
+		return loopVars{}
+	}
+	return loopVars{vars: vars, shared: isLoopVarShared(pass, body)}
+}
+
+// rangeProducesDeeply returns true if the variable declared by the identifier in the range
+// statement is produced from the deep nilability of the ranged expression, mirroring
+// backpropAcrossRange.
+func rangeProducesDeeply(pass *analysis.Pass, rangeStmt *ast.RangeStmt, ident *ast.Ident) bool {
+	rangeType := types.Unalias(pass.TypesInfo.TypeOf(rangeStmt.X))
+	if named, ok := rangeType.(*types.Named); ok && named.Obj() != nil && named.Obj().Pkg() != nil &&
+		named.Obj().Pkg().Path() == "iter" {
+		return false
+	}
+	if rangeStmt.Value == ident {
+		return !typeIsString(rangeType)
+	}
+	return rangeStmt.Value == nil && util.TypeIsDeeplyChan(rangeType)
+}
+
+// assignedValues returns the values assigned to the variable in the assignment statement.
+func assignedValues(pass *analysis.Pass, assign *ast.AssignStmt, v *types.Var) []loopVarAssign {
+	if assign.Tok != token.ASSIGN || len(assign.Lhs) != len(assign.Rhs) {
+		return nil
+	}
+	var values []loopVarAssign
+	for i, lhs := range assign.Lhs {
+		if ident, ok := ast.Unparen(lhs).(*ast.Ident); ok && pass.TypesInfo.Uses[ident] == v {
+			values = append(values, loopVarAssign{pos: assign.Pos(), value: assign.Rhs[i]})
+		}
+	}
+	return values
+}
+
+// refersToDeclaredIn returns true if the expression refers to a variable declared in the block.
+func refersToDeclaredIn(pass *analysis.Pass, expr ast.Expr, block *ast.BlockStmt) bool {
+	found := false
+	ast.Inspect(expr, func(node ast.Node) bool {
+		if ident, ok := node.(*ast.Ident); ok {
+			if v, ok := pass.TypesInfo.Uses[ident].(*types.Var); ok &&
+				v.Pos() >= block.Pos() && v.Pos() < block.End() {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// isLoopVarShared returns true if the loop variables in the file containing the node are shared
+// across the iterations, i.e., the Go version of the file (or of the package if the file does not
+// specify one) is older than _perIterationLoopVarVersion. The variables are assumed to be
+// per-iteration if the version is unknown, following the current toolchains.
+func isLoopVarShared(pass *analysis.Pass, node ast.Node) bool {
+	v := pass.Pkg.GoVersion()
+	for _, file := range pass.Files {
+		if file.Pos() <= node.Pos() && node.End() <= file.End() {
+			if fileVersion := pass.TypesInfo.FileVersions[file]; fileVersion != "" {
+				v = fileVersion
+			}
+			break
+		}
+	}
+	return version.IsValid(v) && version.Compare(v, _perIterationLoopVarVersion) < 0
+}
+
+// consumeLoopVarCaptures adds the argument consumers for the values that the loop variables
+// captured by the function literal spawned in the `go` statement may take while the goroutine
+// runs. The closure variables are passed as arguments at the `go` statement (see
+// funcArgsFromCallExpr), so the guards at the statement would otherwise apply to the goroutine as
+// well. However, the goroutine observes the later assignments to the loop variables in the same
+// iteration, and, if the loop variables are shared across the iterations (before Go 1.22), the
+// values of all later iterations, including the ranged values and the values assigned by the post
+// statements, which are not guarded at the `go` statement.
+func consumeLoopVarCaptures(rootNode *RootAssertionNode, node *ast.GoStmt) {
+	lvs := rootNode.functionContext.loopVars
+	if len(lvs.vars) == 0 {
+		return
+	}
+	info := getFuncLitInfo(node.Call, &rootNode.functionContext)
+	if info == nil {
+		return
+	}
+
+	for i, closure := range info.ClosureVars {
+		lv, ok := lvs.vars[closure.Obj]
+		if !ok || util.TypeBarsNilness(closure.Obj.Type()) || node.Pos() < lv.body.Pos() || node.End() > lv.body.End() {
+			continue
+		}
+		paramNum := len(node.Call.Args) + i
+		newConsumer := func(expr ast.Expr) *annotation.ConsumeTrigger {
+			return &annotation.ConsumeTrigger{
+				Annotation: &annotation.ArgPass{
+					TriggerIfNonNil: &annotation.TriggerIfNonNil{
+						Ann: annotation.ParamKeyFromArgNum(info.FakeFuncObj, paramNum),
+					}},
+				Expr:   expr,
+				Guards: util.NoGuards(),
+			}
+		}
+
+		for _, assign := range lv.bodyAssigns {
+			// With per-iteration loop variables, only the assignments after the `go` statement
+			// in the same iteration are observed by the goroutine.
+			if lvs.shared || assign.pos > node.End() {
+				rootNode.AddConsumption(newConsumer(assign.value))
+			}
+		}
+		if !lvs.shared {
+			continue
+		}
+		for _, value := range lv.postValues {
+			rootNode.AddConsumption(newConsumer(value))
+		}
+		if lv.rangeX != nil {
+			producer := exprAsDeepProducer(rootNode, lv.rangeX)
+			producer.SetNeedsGuard(false)
+			rootNode.AddNewTriggers(annotation.FullTrigger{
+				Producer: &annotation.ProduceTrigger{
+					Annotation: producer,
+					Expr:       lv.rangeIdent,
+				},
+				Consumer: newConsumer(closure.Ident),
+			})
+		}
+	}
+}
@@ -0,0 +1,205 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assertiontree
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+
+	"go.uber.org/nilaway/util"
+	"go.uber.org/nilaway/util/asthelper"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// OkReceiverReads returns the set of ok-returning methods (see util.FuncIsOkReturning) declared in
+// the current package that wrap a comma-ok read of a map rooted at their receivers, e.g.,
+//
+//	type M map[string]*T
+//
+//	func (m M) Get(k string) (*T, bool) {
+//		v, ok := m[k]
+//		return v, ok
+//	}
+//
+// For such methods, the returned `ok` being true implies that the receiver is nonnil, just like
+// the `ok` of a direct map read `v, ok := m[k]` implies that the map `m` is nonnil. To keep the
+// check simple (and sound), a method is included only if its receiver is never reassigned, and
+// every return statement returns as `ok` either the literal `false` or a variable that is assigned
+// only by comma-ok reads of a map rooted at the receiver (i.e., the receiver itself, `*m`, or
+// fields of the receiver such as `m.cache`).
+func OkReceiverReads(pass *analysis.Pass) map[*types.Func]bool {
+	methods := make(map[*types.Func]bool)
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Recv == nil || funcDecl.Body == nil {
+				continue
+			}
+			funcObj, ok := pass.TypesInfo.ObjectOf(funcDecl.Name).(*types.Func)
+			if !ok || !util.FuncIsOkReturning(funcObj) {
+				continue
+			}
+			if isOkReceiverRead(pass, funcDecl, funcObj) {
+				methods[funcObj] = true
+			}
+		}
+	}
+	return methods
+}
+
+// isOkReceiverRead returns true if the method passes on the `ok` of comma-ok reads of a map
+// rooted at its receiver, see OkReceiverReads for details.
+func isOkReceiverRead(pass *analysis.Pass, funcDecl *ast.FuncDecl, funcObj *types.Func) bool {
+	recv := funcObj.Type().(*types.Signature).Recv()
+	if recv == nil || recv.Name() == "" || recv.Name() == "_" {
+		return false
+	}
+
+	// okVars maps the variables assigned by comma-ok reads of the receiver map to whether they
+	// are assigned only by such reads.
+	okVars := make(map[types.Object]bool)
+	recvAssigned := false
+	var returns []*ast.ReturnStmt
+	ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncLit:
+			// The return statements of the function literals are not the returns of the method,
+			// and we do not track the assignments in them either.
+			recvAssigned = recvAssigned || funcLitAssigns(pass, node, recv)
+			return false
+		case *ast.ReturnStmt:
+			returns = append(returns, node)
+			return true
+		case *ast.UnaryExpr:
+			// Taking the address of the receiver allows it to be modified indirectly.
+			if ident, ok := astutil.Unparen(node.X).(*ast.Ident); ok && node.Op == token.AND &&
+				pass.TypesInfo.Uses[ident] == recv {
+				recvAssigned = true
+			}
+			return true
+		}
+
+		lhs, rhs := asthelper.ExtractLHSRHS(node)
+		if len(lhs) == 0 {
+			return true
+		}
+		isRead := len(lhs) == 2 && len(rhs) == 1 && isReceiverMapRead(pass, rhs[0], recv)
+		for i, l := range lhs {
+			ident, ok := astutil.Unparen(l).(*ast.Ident)
+			if !ok {
+				continue
+			}
+			obj := pass.TypesInfo.ObjectOf(ident)
+			if obj == nil {
+				continue
+			}
+			if obj == recv {
+				recvAssigned = true
+			}
+			qualified, seen := okVars[obj]
+			okVars[obj] = (qualified || !seen) && isRead && i == 1
+		}
+		return true
+	})
+	if recvAssigned {
+		return false
+	}
+
+	numResults := funcObj.Type().(*types.Signature).Results().Len()
+	passesOnRead := false
+	for _, ret := range returns {
+		if len(ret.Results) != numResults {
+			// Bare returns of named results, and returns of other multiply-returning functions.
+			return false
+		}
+		okExpr := astutil.Unparen(ret.Results[numResults-1])
+		if tv, ok := pass.TypesInfo.Types[okExpr]; ok && tv.Value != nil &&
+			tv.Value.Kind() == constant.Bool && !constant.BoolVal(tv.Value) {
+			continue
+		}
+		ident, ok := okExpr.(*ast.Ident)
+		if !ok || !okVars[pass.TypesInfo.ObjectOf(ident)] {
+			return false
+		}
+		passesOnRead = true
+	}
+	return passesOnRead
+}
+
+// isReceiverMapRead returns true if the expression is a read `x[k]` of a map `x` rooted at the
+// receiver, i.e., `x` is the receiver itself, its dereference, or a chain of fields of it.
+func isReceiverMapRead(pass *analysis.Pass, expr ast.Expr, recv *types.Var) bool {
+	index, ok := astutil.Unparen(expr).(*ast.IndexExpr)
+	if !ok || !util.TypeIsDeeplyMap(pass.TypesInfo.TypeOf(index.X)) {
+		return false
+	}
+	x := astutil.Unparen(index.X)
+	for {
+		switch e := x.(type) {
+		case *ast.Ident:
+			return pass.TypesInfo.Uses[e] == recv
+		case *ast.StarExpr:
+			x = astutil.Unparen(e.X)
+		case *ast.SelectorExpr:
+			if sel, ok := pass.TypesInfo.Selections[e]; !ok || sel.Kind() != types.FieldVal {
+				return false
+			}
+			x = astutil.Unparen(e.X)
+		default:
+			return false
+		}
+	}
+}
+
+// funcLitAssigns returns true if the function literal assigns to the given variable.
+func funcLitAssigns(pass *analysis.Pass, funcLit *ast.FuncLit, v *types.Var) bool {
+	assigned := false
+	ast.Inspect(funcLit.Body, func(node ast.Node) bool {
+		lhs, _ := asthelper.ExtractLHSRHS(node)
+		for _, l := range lhs {
+			if ident, ok := astutil.Unparen(l).(*ast.Ident); ok && pass.TypesInfo.ObjectOf(ident) == v {
+				assigned = true
+			}
+		}
+		return !assigned
+	})
+	return assigned
+}
+
+// okReceiverReadRecv returns the receiver expression `w` of a call `w.Get(k)` to a method in
+// OkReceiverReads, or nil if the expression is not such a call. The receiver must be selected
+// directly (i.e., not through embedded fields), such that `w` itself is the receiver of the method.
+func okReceiverReadRecv(rootNode *RootAssertionNode, expr ast.Expr) ast.Expr {
+	call, ok := astutil.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	sel, ok := astutil.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	funcObj, ok := rootNode.ObjectOf(sel.Sel).(*types.Func)
+	if !ok || !rootNode.functionContext.functionConfig.OkReceiverReads[funcObj] {
+		return nil
+	}
+	selection, ok := rootNode.Pass().TypesInfo.Selections[sel]
+	if !ok || selection.Kind() != types.MethodVal || len(selection.Index()) != 1 {
+		return nil
+	}
+	return sel.X
+}
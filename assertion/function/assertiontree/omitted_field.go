@@ -0,0 +1,285 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assertiontree
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"go.uber.org/nilaway/annotation"
+	"go.uber.org/nilaway/util"
+	"golang.org/x/tools/go/analysis"
+)
+
+// consumeOmittedFields adds full triggers for the pointer-like fields (see panicsIfNil) omitted
+// from a struct composite literal (e.g., `T{}` or `T{OnlySomeFields: x}`), which are left nil.
+// Such a trigger is reported at the literal only if the field is declared or inferred to be
+// nonnil, rather than at a far-away dereference of the field.
+//
+// The following fields are not considered: (1) the fields enforced by field-level `//nonnil`
+// annotations, which are handled by consumeEnforcedFields instead, (2) the unexported fields of
+// the structs from other packages, which cannot be initialized at the literal, and (3) the fields
+// assigned anywhere in the function (e.g., `s := &T{}; s.f = x`), which are commonly initialized
+// after the literal. The omissions are not checked in the struct-init mode, where the fields of
+// the literals are already tracked to their dereferences.
+func (r *RootAssertionNode) consumeOmittedFields(expr *ast.CompositeLit) {
+	if r.functionContext.functionConfig.EnableStructInitCheck {
+		return
+	}
+	t := r.Pass().TypesInfo.TypeOf(expr)
+	if t == nil {
+		return
+	}
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return
+	}
+
+	initialized := make(map[*types.Var]bool)
+	for _, elt := range expr.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			// Positional composite literals must initialize all fields.
+			return
+		}
+		if key, ok := kv.Key.(*ast.Ident); ok {
+			if fld, ok := r.ObjectOf(key).(*types.Var); ok {
+				initialized[fld] = true
+			}
+		}
+	}
+
+	enforced := r.functionContext.functionConfig.EnforcedNonNilFields
+	assigned := r.functionContext.assignedFields
+	for i := 0; i < st.NumFields(); i++ {
+		fld := st.Field(i)
+		if initialized[fld] || enforced[fld] || assigned[fld] || !panicsIfNil(fld.Type()) ||
+			(!fld.Exported() && fld.Pkg() != r.Pass().Pkg) {
+			continue
+		}
+		r.AddNewTriggers(annotation.FullTrigger{
+			Producer: &annotation.ProduceTrigger{
+				Annotation: &annotation.UnassignedFld{ProduceTriggerTautology: &annotation.ProduceTriggerTautology{}},
+				Expr:       expr,
+			},
+			Consumer: &annotation.ConsumeTrigger{
+				Annotation: &annotation.FldOmitted{
+					TriggerIfNonNil: &annotation.TriggerIfNonNil{
+						Ann: &annotation.FieldAnnotationKey{FieldDecl: fld},
+					},
+				},
+				Expr:   expr,
+				Guards: util.NoGuards(),
+			},
+		})
+	}
+}
+
+// panicsIfNil returns true if the nil values of the type are commonly used in ways that panic,
+// i.e., pointers, interfaces, functions, and maps. The nil slices and channels are idiomatic zero
+// values (e.g., they can be appended to or ranged over), hence omitting them is not checked.
+func panicsIfNil(t types.Type) bool {
+	if _, ok := t.(*types.TypeParam); ok {
+		return false
+	}
+	switch t.Underlying().(type) {
+	case *types.Pointer, *types.Interface, *types.Signature, *types.Map:
+		return true
+	}
+	return false
+}
+
+// collectAssignedFields returns the set of struct fields assigned (e.g., `x.f = v`) anywhere in
+// the body of the function declaration or the function literal, including the nested function
+// literals.
+func collectAssignedFields(pass *analysis.Pass, decl *ast.FuncDecl, funcLit *ast.FuncLit) map[*types.Var]bool {
+	var body *ast.BlockStmt
+	switch {
+	case funcLit != nil:
+		body = funcLit.Body
+	case decl != nil:
+		body = decl.Body
+	}
+	if body == nil {
+		return nil
+	}
+
+	assigned := make(map[*types.Var]bool)
+	ast.Inspect(body, func(node ast.Node) bool {
+		assign, ok := node.(*ast.AssignStmt)
+		if !ok {
+			return true
+		}
+		for _, lhs := range assign.Lhs {
+			sel, ok := ast.Unparen(lhs).(*ast.SelectorExpr)
+			if !ok {
+				continue
+			}
+			if fld, ok := pass.TypesInfo.ObjectOf(sel.Sel).(*types.Var); ok && fld.IsField() {
+				assigned[fld] = true
+			}
+		}
+		return true
+	})
+	return assigned
+}
+
+// collectInitializedFields returns the struct fields assigned right after the constructions of the
+// structs stored in local variables in the body of the function declaration or the function
+// literal (e.g., `s := &T{}; s.f = x; return s`), keyed by the constructions: the composite
+// literals, the `new(T)` calls, and the identifiers of the `var s T` declarations. Only the
+// assignments to the fields of the variable before any other use of it (i.e., before the value
+// may escape) are collected, which complete the initializations of the constructions.
+func collectInitializedFields(pass *analysis.Pass, decl *ast.FuncDecl, funcLit *ast.FuncLit) map[ast.Expr]map[*types.Var]bool {
+	var body *ast.BlockStmt
+	switch {
+	case funcLit != nil:
+		body = funcLit.Body
+	case decl != nil:
+		body = decl.Body
+	}
+	if body == nil {
+		return nil
+	}
+
+	// construction returns the construction of a struct stored in a local variable by the
+	// statement, if any.
+	construction := func(stmt ast.Stmt) (types.Object, ast.Expr) {
+		var name *ast.Ident
+		var value ast.Expr
+		switch stmt := stmt.(type) {
+		case *ast.AssignStmt:
+			if len(stmt.Lhs) != 1 || len(stmt.Rhs) != 1 {
+				return nil, nil
+			}
+			name, _ = ast.Unparen(stmt.Lhs[0]).(*ast.Ident)
+			value = ast.Unparen(stmt.Rhs[0])
+		case *ast.DeclStmt:
+			gen, ok := stmt.Decl.(*ast.GenDecl)
+			if !ok || len(gen.Specs) != 1 {
+				return nil, nil
+			}
+			spec, ok := gen.Specs[0].(*ast.ValueSpec)
+			if !ok || len(spec.Names) != 1 || len(spec.Values) > 1 {
+				return nil, nil
+			}
+			name = spec.Names[0]
+			if len(spec.Values) == 0 {
+				// `var s T` constructs the zero value at the identifier.
+				return pass.TypesInfo.ObjectOf(name), name
+			}
+			value = ast.Unparen(spec.Values[0])
+		}
+		if name == nil || value == nil {
+			return nil, nil
+		}
+		if unary, ok := value.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+			value = ast.Unparen(unary.X)
+		}
+		switch value := value.(type) {
+		case *ast.CompositeLit:
+			return pass.TypesInfo.ObjectOf(name), value
+		case *ast.CallExpr:
+			if fun, ok := ast.Unparen(value.Fun).(*ast.Ident); ok && len(value.Args) == 1 {
+				if b, ok := pass.TypesInfo.ObjectOf(fun).(*types.Builtin); ok && b.Name() == "new" {
+					return pass.TypesInfo.ObjectOf(name), value
+				}
+			}
+		}
+		return nil, nil
+	}
+
+	// uses returns true if the node refers to the object.
+	uses := func(node ast.Node, obj types.Object) bool {
+		found := false
+		ast.Inspect(node, func(n ast.Node) bool {
+			if ident, ok := n.(*ast.Ident); ok && pass.TypesInfo.ObjectOf(ident) == obj {
+				found = true
+			}
+			return !found
+		})
+		return found
+	}
+
+	// fieldsAssigned returns the fields of the object assigned by the statement, or false if the
+	// statement uses the object in any other way.
+	fieldsAssigned := func(stmt ast.Stmt, obj types.Object) ([]*types.Var, bool) {
+		assign, ok := stmt.(*ast.AssignStmt)
+		if !ok || assign.Tok != token.ASSIGN {
+			return nil, !uses(stmt, obj)
+		}
+		for _, rhs := range assign.Rhs {
+			if uses(rhs, obj) {
+				return nil, false
+			}
+		}
+		var fields []*types.Var
+		for _, lhs := range assign.Lhs {
+			sel, ok := ast.Unparen(lhs).(*ast.SelectorExpr)
+			if !ok {
+				if uses(lhs, obj) {
+					return nil, false
+				}
+				continue
+			}
+			if x, ok := ast.Unparen(sel.X).(*ast.Ident); !ok || pass.TypesInfo.ObjectOf(x) != obj {
+				if uses(lhs, obj) {
+					return nil, false
+				}
+				continue
+			}
+			if fld, ok := pass.TypesInfo.ObjectOf(sel.Sel).(*types.Var); ok && fld.IsField() {
+				fields = append(fields, fld)
+			}
+		}
+		return fields, true
+	}
+
+	initialized := make(map[ast.Expr]map[*types.Var]bool)
+	ast.Inspect(body, func(node ast.Node) bool {
+		var stmts []ast.Stmt
+		switch node := node.(type) {
+		case *ast.BlockStmt:
+			stmts = node.List
+		case *ast.CaseClause:
+			stmts = node.Body
+		case *ast.CommClause:
+			stmts = node.Body
+		default:
+			return true
+		}
+		for i, stmt := range stmts {
+			obj, expr := construction(stmt)
+			if obj == nil {
+				continue
+			}
+			for _, next := range stmts[i+1:] {
+				fields, ok := fieldsAssigned(next, obj)
+				if !ok {
+					break
+				}
+				for _, fld := range fields {
+					if initialized[expr] == nil {
+						initialized[expr] = make(map[*types.Var]bool)
+					}
+					initialized[expr][fld] = true
+				}
+			}
+		}
+		return true
+	})
+	return initialized
+}
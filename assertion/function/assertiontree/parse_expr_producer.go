@@ -26,9 +26,10 @@ import (
 )
 
 // ParseExprAsProducer takes an expression, and determines whether it is `trackable` - i.e. if it is a
-// linear sequence of variable reads, field reads, indexes by `stable` expressions, and function calls
-// with `stable` arguments. An expression is `stable` if our static analysis assume that multiple
-// syntactic occurrences of it will always yield the same value - i.e. they are assumed to be constant.
+// linear sequence of variable reads, field reads, indexes by `stable` expressions, and calls to
+// pure functions (see RootAssertionNode.IsPure) with `stable` arguments. An expression is `stable`
+// if our static analysis assume that multiple syntactic occurrences of it will always yield the
+// same value - i.e. they are assumed to be constant.
 //
 // This function and the cases in which it returns a sequence of nodes serve as our internal
 // definition of `trackable`, and similarly the function isStable below serves as our internal
@@ -53,9 +54,73 @@ import (
 // nilable(shallowSeq)
 // nilable(producers)
 //
+// Results are memoized per root (see parseCacheKey), unless disabled via FunctionConfig.
+//
 // TODO: split this up into smaller functions with more granular documentation
 func (r *RootAssertionNode) ParseExprAsProducer(expr ast.Expr, doNotTrack bool) (
 	shallowSeq TrackableExpr, producers []producer.ParsedProducer) {
+	if r.functionContext.functionConfig.DisableParseCache {
+		return r.parseExprAsProducer(expr, doNotTrack)
+	}
+
+	key := parseCacheKey{expr: expr, doNotTrack: doNotTrack}
+	if cached, ok := r.parseCache[key]; ok {
+		return copyTrackableExpr(cached), nil
+	}
+
+	shallowSeq, producers = r.parseExprAsProducer(expr, doNotTrack)
+	// Producers carry annotation triggers that callers freely modify (e.g., setting guards), and
+	// they are not copyable in general, so we only memoize the results without producers. This
+	// covers the trackable expressions, which are the bulk of the repeated parses.
+	if producers == nil {
+		if r.parseCache == nil {
+			r.parseCache = make(map[parseCacheKey]TrackableExpr)
+		}
+		r.parseCache[key] = copyTrackableExpr(shallowSeq)
+	}
+	return shallowSeq, producers
+}
+
+// parseCacheKey is the key for memoizing the results of ParseExprAsProducer, i.e., the (identity
+// of the) expression and the doNotTrack flag. The function context of the root is fixed for the
+// lifetime of the root, while its mutable state (i.e., its children, consumers, and triggers) is
+// covered by invalidating the cache whenever it changes (see invalidateParseCache). The cached
+// results are also copied both in and out of the cache (see copyTrackableExpr), so linking the
+// returned nodes into the tree and mutating them there never changes the cached values.
+type parseCacheKey struct {
+	expr       ast.Expr
+	doNotTrack bool
+}
+
+// invalidateParseCache drops the memoized results of ParseExprAsProducer. It must be called by
+// every operation that mutates the tree of the root (e.g., adding consumptions or productions,
+// matching guards, or merging trees), such that the results never outlive the state they were
+// computed from.
+func (r *RootAssertionNode) invalidateParseCache() {
+	clear(r.parseCache)
+}
+
+// copyTrackableExpr returns a fresh copy of the nodes in the trackable expression, since the nodes
+// returned from ParseExprAsProducer are linked into (and hence modified by) the assertion trees.
+//
+// nilable(expr, result 0)
+func copyTrackableExpr(expr TrackableExpr) TrackableExpr {
+	if expr == nil {
+		return nil
+	}
+	fresh := make(TrackableExpr, len(expr))
+	for i, node := range expr {
+		fresh[i] = CopyNode(node)
+	}
+	return fresh
+}
+
+// parseExprAsProducer implements ParseExprAsProducer without memoization.
+//
+// nilable(shallowSeq)
+// nilable(producers)
+func (r *RootAssertionNode) parseExprAsProducer(expr ast.Expr, doNotTrack bool) (
+	shallowSeq TrackableExpr, producers []producer.ParsedProducer) {
 
 	parseIdent := func(expr *ast.Ident) (TrackableExpr, []producer.ParsedProducer) {
 		if util.IsEmptyExpr(expr) {
@@ -199,6 +264,9 @@ func (r *RootAssertionNode) ParseExprAsProducer(expr ast.Expr, doNotTrack bool)
 			return nil, nil
 		}
 
+		// a promoted field is tracked under the embedded fields it is accessed through
+		expr = r.desugarPromotion(expr)
+
 		fldReadProduce := func() []producer.ParsedProducer {
 			fldObj := r.ObjectOf(expr.Sel).(*types.Var)
 			return []producer.ParsedProducer{producer.DeepParsedProducer{
@@ -230,6 +298,14 @@ func (r *RootAssertionNode) ParseExprAsProducer(expr ast.Expr, doNotTrack bool)
 		return nil, fldReadProduce()
 
 	case *ast.CallExpr:
+		if r.isType(expr.Fun) {
+			// A conversion (e.g., `(*T)(p)` or `MyFunc(f)`) is trusted to produce a nonnil value,
+			// like the trusted helpers converting values to pointers (e.g., `lo.ToPtr(v)`, see
+			// hook.AssumeReturn). This does not hold for the conversions of nil values, which are
+			// rarely written on purpose.
+			return nil, nil
+		}
+
 		// we delay this check until we're sure we have to make it, as it could be expensive
 		litArgs := func() bool {
 			for _, expr := range expr.Args {
@@ -241,23 +317,47 @@ func (r *RootAssertionNode) ParseExprAsProducer(expr ast.Expr, doNotTrack bool)
 		}
 
 		if prod := hook.AssumeReturn(r.Pass(), expr); prod != nil {
-			return nil, []producer.ParsedProducer{producer.ShallowParsedProducer{Producer: prod}}
+			// A nilable result of a trusted call with stable arguments (e.g., `p.Load()` of an
+			// `atomic.Pointer` or `errors.Unwrap(err)`) is still tracked with the assumed
+			// nilability as its default, such that it can be nil-checked and assigned to by stores
+			// (see hook.AssumeStore).
+			if _, ok := prod.Annotation.(*annotation.TrustedFuncNilable); ok && !doNotTrack && litArgs() {
+				if tracked := r.trackTrustedCall(expr, prod.Annotation); tracked != nil {
+					return tracked, nil
+				}
+			}
+			// The assumed nilability applies to every result of a multiply-returning trusted call
+			// (e.g., `lo.Must2(f())`).
+			numResults := 1
+			if tuple, ok := r.Pass().TypesInfo.TypeOf(expr).(*types.Tuple); ok {
+				numResults = tuple.Len()
+			}
+			producers := []producer.ParsedProducer{producer.ShallowParsedProducer{Producer: prod}}
+			for len(producers) < numResults {
+				producers = append(producers, producer.ShallowParsedProducer{Producer: hook.AssumeReturn(r.Pass(), expr)})
+			}
+			return nil, producers
+		}
+
+		if info := getFuncLitInfo(expr, &r.functionContext); info != nil {
+			// a call to an analyzed function literal (e.g., an immediately invoked one) produces
+			// the results of its fake function declaration, whose return statements are consumed
+			// when analyzing the function literal
+			return nil, r.getFuncReturnProducers(info.FakeFuncDecl.Name, expr)
 		}
 
 		// the cases of a function and method call are different enough here that it would be useless
 		// to try to subsume this switch with funcIdentFromCallExpr
 		switch fun := expr.Fun.(type) {
 		case *ast.Ident: // direct function call
+			if paramNum, ok := r.calledCallbackParam(expr); ok {
+				// a call to a callback (i.e., a function-typed parameter) of this function
+				// produces the results of the callback sites
+				return nil, r.getCallbackReturnProducers(expr, paramNum)
+			}
 			if !r.isFunc(fun) {
-				// The following block implements the basic support for append function where it has
-				// only two arguments and the first argument is the same as the lhs of assignment.
-				// Since in Go it is allowed to have only one argument in the append method, we need
-				// to have a check to make sure that len(expr.Args) > 1
-				if r.ObjectOf(fun) == util.BuiltinAppend && len(expr.Args) > 1 {
-					// TODO: handle the correlation of return type of append with its first argument .
-					// TODO: iterate over the arguments of the append call if it has more than two args
-					rec, producers := r.ParseExprAsProducer(expr.Args[1], false)
-					return rec, producers
+				if r.ObjectOf(fun) == util.BuiltinAppend {
+					return r.parseAppendAsProducer(expr, doNotTrack)
 				}
 
 				// We are in the case of built-in functions. The below block particularly checks for the case of the
@@ -279,24 +379,36 @@ func (r *RootAssertionNode) ParseExprAsProducer(expr ast.Expr, doNotTrack bool)
 				// anonymous functions will also fall into this case
 				return nil, nil
 			}
-			// non-builtin funcs
-			if !doNotTrack && litArgs() {
+			// non-builtin funcs: only the calls to pure functions are tracked, since the calls to
+			// other functions may return different values each time
+			if !doNotTrack && litArgs() && r.IsPure(r.ObjectOf(fun).(*types.Func)) {
 				return TrackableExpr{&funcAssertionNode{
 					decl: r.ObjectOf(fun).(*types.Func), args: expr.Args}}, nil
 			}
-			// function call has non-literal args, so is not literal, use its return annotation
+			// function call has non-literal args or calls an impure function, so is not
+			// trackable, use its return annotation
 			// alternatively, doNotTrack was set
 			return nil, r.getFuncReturnProducers(fun, expr)
 
 		case *ast.SelectorExpr: // method call
+			if field := r.calledCallbackField(expr); field != nil {
+				// a call to a callback stored in a function-typed struct field produces the
+				// results of the field callback sites
+				return nil, r.getFieldCallbackReturnProducers(expr, field)
+			}
 			if !r.isFunc(fun.Sel) {
 				// we assume builtins and type casts don't return nil
 				return nil, nil
 			}
+			if r.returnsDirectReceiver(fun) {
+				// a method returning its receiver (e.g., `b.WithX(x)` of a builder) produces its
+				// receiver, such that the chained calls after a nonnil receiver are nonnil
+				return r.ParseExprAsProducer(fun.X, doNotTrack)
+			}
 			if doNotTrack {
 				return nil, r.getFuncReturnProducers(fun.Sel, expr)
 			}
-			if litArgs() {
+			if funcObj := r.ObjectOf(fun.Sel).(*types.Func); litArgs() && (r.IsPure(funcObj) || hook.IsAssumedLoad(funcObj)) {
 				if r.isPkgName(fun.X) {
 					return TrackableExpr{&funcAssertionNode{
 						decl: r.ObjectOf(fun.Sel).(*types.Func), args: expr.Args}}, nil
@@ -309,7 +421,8 @@ func (r *RootAssertionNode) ParseExprAsProducer(expr ast.Expr, doNotTrack bool)
 				return nil, r.getFuncReturnProducers(fun.Sel, expr)
 
 			}
-			// function call has non-literal args, so is not literal, use its return annotation
+			// function call has non-literal args or calls an impure function, so is not
+			// trackable, use its return annotation
 			return nil, r.getFuncReturnProducers(fun.Sel, expr)
 
 		default:
@@ -328,7 +441,7 @@ func (r *RootAssertionNode) ParseExprAsProducer(expr ast.Expr, doNotTrack bool)
 			// X part of the expression is trackable. Now we need to check if the index is stable or trackable.
 			// If the index is not stable, it is still considered trackable if it falls into any of these categories:
 			// - Index is a variable (e.g., `m[i]`)
-			// - Index is a built-in function (e.g., `m[len(m)-1]`)
+			// - Index is a call to a built-in or pure function (e.g., `m[len(m)-1]`)
 			// - Index is a field selector chain (e.g., `m[g.h.i]`)
 			// TODO: above non-literal indices should only be considered trackable if no reassignment is found between
 			//  accesses. For example, `i := 0; if m[i] != nil { i = 10; return *m[i] }` should not be considered trackable
@@ -346,7 +459,7 @@ func (r *RootAssertionNode) ParseExprAsProducer(expr ast.Expr, doNotTrack bool)
 					return false
 				case *ast.CallExpr:
 					if fun, ok := index.Fun.(*ast.Ident); ok {
-						if r.isBuiltIn(fun) {
+						if r.isBuiltIn(fun) || r.isStable(fun) {
 							// iterate over the arguments of the call expression
 							for _, arg := range index.Args {
 								if !isIndexTrackable(arg) {
@@ -428,6 +541,13 @@ func (r *RootAssertionNode) ParseExprAsProducer(expr ast.Expr, doNotTrack bool)
 		// simply parse the underlying expression
 		return r.ParseExprAsProducer(expr.X, doNotTrack)
 
+	case *ast.TypeAssertExpr:
+		// Similar to the conversions, a single-valued type assertion (e.g., `any(x).(*T)`) is
+		// trusted to produce a nonnil value: it panics if the asserted value is a nil interface,
+		// and the values asserted to pointer types are rarely typed nils. (The comma-ok form is
+		// handled in backpropAcrossAssignment.)
+		return nil, nil
+
 	case *ast.CompositeLit:
 		if r.functionContext.functionConfig.EnableStructInitCheck {
 			rproducer := r.parseStructCreateExprAsProducer(expr, expr.Elts)
@@ -441,6 +561,81 @@ func (r *RootAssertionNode) ParseExprAsProducer(expr ast.Expr, doNotTrack bool)
 	return nil, nil
 }
 
+// parseAppendAsProducer parses a call to the builtin `append` (e.g., `append(s, x, y...)`). The
+// result is never nil once something is appended, and its elements are those of the first argument
+// `s` as well as the appended values `x` and the elements of the spread argument `y`, so a merged
+// producer of all of them is returned. The appended values that are trackable are left out, since
+// the consumers of the elements of a slice assigned from the call are moved to them instead (see
+// landAppendedElems), such that their nil checks are honored.
+func (r *RootAssertionNode) parseAppendAsProducer(expr *ast.CallExpr, doNotTrack bool) (TrackableExpr, []producer.ParsedProducer) {
+	switch len(expr.Args) {
+	case 0:
+		// not type-correct, and hence not analyzed
+		return nil, nil
+	case 1:
+		// `append(s)` is simply `s`
+		return r.ParseExprAsProducer(expr.Args[0], doNotTrack)
+	}
+
+	deepProducers := exprAsDeepProducers(r, expr.Args[0])
+	for _, arg := range appendedValues(expr) {
+		if path, producers := r.ParseExprAsProducer(arg, false); path == nil && len(producers) == 1 {
+			deepProducers = append(deepProducers, producers[0].GetShallow())
+		}
+	}
+	if expr.Ellipsis != token.NoPos {
+		deepProducers = append(deepProducers, exprAsDeepProducers(r, expr.Args[len(expr.Args)-1])...)
+	}
+	return nil, []producer.ParsedProducer{producer.MergedParsedProducer{
+		ShallowProducer: &annotation.ProduceTrigger{
+			Annotation: &annotation.ProduceTriggerNever{},
+			Expr:       expr,
+		},
+		DeepProducers: deepProducers,
+	}}
+}
+
+// trackTrustedCall returns the trackable expression for a call to a trusted function or method,
+// with the given assumed nilability of its result as the default, or nil if the call (e.g., on an
+// untrackable receiver) cannot be tracked.
+func (r *RootAssertionNode) trackTrustedCall(expr *ast.CallExpr, trusted annotation.ProducingAnnotationTrigger) TrackableExpr {
+	var ident *ast.Ident
+	var recv TrackableExpr
+	switch fun := expr.Fun.(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+		if !r.isPkgName(fun.X) {
+			if recv, _ = r.ParseExprAsProducer(fun.X, false); recv == nil {
+				return nil
+			}
+		}
+	default:
+		return nil
+	}
+	decl, ok := r.ObjectOf(ident).(*types.Func)
+	if !ok {
+		return nil
+	}
+	return append(recv, &funcAssertionNode{decl: decl, args: expr.Args, trusted: trusted})
+}
+
+// returnsDirectReceiver returns if the method call `X.Sel` returns X itself, i.e., the method
+// returns its receiver (see RootAssertionNode.ReturnsReceiver) and is called directly on X (rather
+// than on an embedded field of X or on the address of X).
+func (r *RootAssertionNode) returnsDirectReceiver(fun *ast.SelectorExpr) bool {
+	funcObj, ok := r.ObjectOf(fun.Sel).(*types.Func)
+	if !ok || !r.ReturnsReceiver(funcObj) {
+		return false
+	}
+	sel := r.Pass().TypesInfo.Selections[fun]
+	if sel == nil || sel.Kind() != types.MethodVal || len(sel.Index()) != 1 {
+		return false
+	}
+	return types.Identical(r.Pass().TypesInfo.TypeOf(fun.X), funcObj.Type().(*types.Signature).Recv().Type())
+}
+
 // getFuncReturnProducers returns a list of producers that are triggered at the call expression
 func (r *RootAssertionNode) getFuncReturnProducers(ident *ast.Ident, expr *ast.CallExpr) []producer.ParsedProducer {
 	funcObj := r.ObjectOf(ident).(*types.Func)
@@ -448,10 +643,18 @@ func (r *RootAssertionNode) getFuncReturnProducers(ident *ast.Ident, expr *ast.C
 	numResults := util.FuncNumResults(funcObj)
 	isErrReturning := util.FuncIsErrReturning(funcObj)
 	isOkReturning := util.FuncIsOkReturning(funcObj)
+	assumeOkReturn := isOkReturning && hook.AssumeOkReturn(r.Pass(), expr) != nil
 
 	producers := make([]producer.ParsedProducer, numResults)
 
 	for i := 0; i < numResults; i++ {
+		if assumeOkReturn && i != numResults-1 {
+			// the non-ok results of trusted ok-returning functions (e.g., `sync.Map.Load`) are
+			// nilable unless the ok result is checked
+			producers[i] = producer.ShallowParsedProducer{Producer: hook.AssumeOkReturn(r.Pass(), expr)}
+			continue
+		}
+
 		var retKey annotation.Key
 		if r.HasContract(funcObj) {
 			// Creates a new return site with location information at every call site for a
@@ -515,7 +718,10 @@ func (r *RootAssertionNode) parseStructCreateExprAsProducer(expr ast.Expr, field
 
 			if fieldVal == nil {
 				// this means the field is not assigned any value, thus unassigned field should be produced
-				fieldProducerArray[i] = &annotation.ProduceTrigger{Annotation: &annotation.UnassignedFld{ProduceTriggerTautology: &annotation.ProduceTriggerTautology{}}}
+				fieldProducerArray[i] = &annotation.ProduceTrigger{Annotation: &annotation.UnassignedFld{
+					ProduceTriggerTautology: &annotation.ProduceTriggerTautology{},
+					StructInit:              true,
+				}}
 			} else {
 				// do not track. Get producer for expression `fieldVal` assigned to the field
 				_, fieldProducer := r.ParseExprAsProducer(fieldVal, true)
@@ -0,0 +1,90 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assertiontree
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"go.uber.org/nilaway/annotation"
+	"go.uber.org/nilaway/util"
+)
+
+// _cgoFuncPrefix is the prefix of the names that cgo rewrites the calls to C functions to, i.e.,
+// `C.foo(x)` becomes `(_Cfunc_foo)(x)` in the compiled Go files.
+const _cgoFuncPrefix = "_Cfunc_"
+
+// consumeReflectEscape adds consumers for the arguments of the call if they escape out of our
+// analysis scope via reflection, an `unsafe.Pointer` conversion, or a cgo call (see
+// reflectEscapeVia). This is only enabled in the conservative reflect-escape mode
+// (FunctionConfig.EnableReflectEscape), since such escapes are otherwise silently ignored.
+func (r *RootAssertionNode) consumeReflectEscape(expr *ast.CallExpr) {
+	if !r.functionContext.functionConfig.EnableReflectEscape {
+		return
+	}
+	via, ok := r.reflectEscapeVia(expr)
+	if !ok {
+		return
+	}
+	for _, arg := range expr.Args {
+		// Multiply-returning calls directly passed as the arguments (e.g., `C.foo(bar())`) are
+		// not tracked here.
+		if _, ok := r.Pass().TypesInfo.TypeOf(arg).(*types.Tuple); ok {
+			continue
+		}
+		r.AddConsumption(&annotation.ConsumeTrigger{
+			Annotation: &annotation.ReflectEscape{ConsumeTriggerTautology: &annotation.ConsumeTriggerTautology{}, Via: via},
+			Expr:       arg,
+			Guards:     util.NoGuards(),
+		})
+	}
+}
+
+// reflectEscapeVia returns the description of how the arguments of the call escape out of our
+// analysis scope, and whether they escape at all. The arguments escape if the call is
+// (1) `reflect.ValueOf(x)`, whose result is typically used by reflection-based setters that panic
+// on nil (e.g., `reflect.ValueOf(x).Elem().Set(...)`), (2) a conversion `unsafe.Pointer(x)`, or
+// (3) a call to a C function via cgo, i.e., `C.foo(x)`.
+func (r *RootAssertionNode) reflectEscapeVia(expr *ast.CallExpr) (string, bool) {
+	fun := ast.Unparen(expr.Fun)
+	if tv, ok := r.Pass().TypesInfo.Types[fun]; ok && tv.IsType() {
+		if basic, ok := types.Unalias(tv.Type).(*types.Basic); ok && basic.Kind() == types.UnsafePointer {
+			return "conversion to `unsafe.Pointer`", true
+		}
+		return "", false
+	}
+
+	switch fun := fun.(type) {
+	case *ast.SelectorExpr:
+		// Before cgo processing (e.g., with fake "C" imports), the calls to C functions are
+		// selector expressions on the pseudo-package "C".
+		if id, ok := fun.X.(*ast.Ident); ok {
+			if pkgName, ok := r.ObjectOf(id).(*types.PkgName); ok && pkgName.Imported().Path() == "C" {
+				return fmt.Sprintf("cgo call `C.%s`", fun.Sel.Name), true
+			}
+		}
+		if funcObj, ok := r.ObjectOf(fun.Sel).(*types.Func); ok && funcObj.Pkg() != nil &&
+			funcObj.Pkg().Path() == "reflect" && funcObj.Name() == "ValueOf" {
+			return "`reflect.ValueOf`", true
+		}
+	case *ast.Ident:
+		if name, ok := strings.CutPrefix(fun.Name, _cgoFuncPrefix); ok {
+			return fmt.Sprintf("cgo call `C.%s`", name), true
+		}
+	}
+	return "", false
+}
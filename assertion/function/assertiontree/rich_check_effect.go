@@ -138,6 +138,20 @@ func (r *okRead) equals(effect RichCheckEffect) bool {
 	return r.root.Equal(r.value, other.value) && r.root.Equal(r.ok, other.ok) && r.guard == other.guard
 }
 
+// isPassedOnBy returns true if the return statement returns both the value and the `ok` of this
+// effect, with the `ok` being the final result (e.g., `v, ok := m[k]; return v, ok`).
+func (r *okRead) isPassedOnBy(ret *ast.ReturnStmt) bool {
+	if len(ret.Results) < 2 || !r.isTriggeredBy(ret.Results[len(ret.Results)-1]) {
+		return false
+	}
+	for _, result := range ret.Results[:len(ret.Results)-1] {
+		if exprMatchesTrackableExpr(r.root, result, r.value) {
+			return true
+		}
+	}
+	return false
+}
+
 // A MapOkRead is a RichCheckEffect for the `ok` in `v, ok := m[k]` assignment. To match such an assignment,
 // both the `v` and the `ok` must be identifiers, and to have the intended effect, an `if ok { }` must
 // be encountered before an assignment to either `v` or `ok`.
@@ -366,6 +380,22 @@ func NodeTriggersOkRead(rootNode *RootAssertionNode, nonceGenerator *util.GuardN
 					guard: nonceGenerator.Next(lhs[i]),
 				}})
 		}
+
+		// Create a rich check effect for the receiver `w` of an ok-returning method wrapping a read
+		// of its receiver map, e.g., `v, ok := w.Get(k)` (see OkReceiverReads). Just like the map
+		// itself in `v, ok := mp[k]`, the receiver cannot be nil if `ok` is true.
+		if recv := okReceiverReadRecv(rootNode, rhs); recv != nil {
+			if recvParsed := parseExpr(rootNode, recv); recvParsed != nil {
+				// Here, the receiver is trackable
+				effects = append(effects, &MapOkReadRefl{
+					okRead{
+						root:  rootNode,
+						value: recvParsed,
+						ok:    lhsOkParsed,
+						guard: nonceGenerator.Next(recv),
+					}})
+			}
+		}
 	}
 	if len(effects) > 0 {
 		return effects, true
@@ -446,6 +476,28 @@ func NodeTriggersFuncErrRet(rootNode *RootAssertionNode, nonceGenerator *util.Gu
 	return effects, someEffect
 }
 
+// errGuardedParamEffect returns the FuncErrRet effect for the parameter of the function that is
+// only nonnil if its error parameter is nil (see hook.ErrGuardedParam), as if the parameters were
+// assigned the results of an error-returning function at the entry, and nil if there is no such
+// parameter. The consumptions of the parameter then need the guard (see ProcessEntry).
+// nilable(result 0)
+func errGuardedParamEffect(rootNode *RootAssertionNode, nonceGenerator *util.GuardNonceGenerator) RichCheckEffect {
+	param, errParam := rootNode.errGuardedParam()
+	if param == nil || errParam == nil {
+		return nil
+	}
+	paramParsed, errParsed := parseExpr(rootNode, param), parseExpr(rootNode, errParam)
+	if paramParsed == nil || errParsed == nil {
+		return nil
+	}
+	return &FuncErrRet{
+		root:  rootNode,
+		err:   errParsed,
+		ret:   paramParsed,
+		guard: nonceGenerator.Next(param),
+	}
+}
+
 // nodeIsAssignmentTo(pass, node, one, other) returns true if `node` is an assignment to the variable
 // `one` but not an assignment to the variable `other`
 func nodeAssignsOneWithoutOther(rootNode *RootAssertionNode, node ast.Node, one, other TrackableExpr) bool {
@@ -488,6 +540,7 @@ func guardExpr(rootNode *RootAssertionNode, expr TrackableExpr, guard util.Guard
 	lookedUpNode, _ := rootNode.lookupPath(expr)
 	if lookedUpNode != nil {
 		// The passed expression is tracked, so mark its corresponding node as guarded
+		rootNode.invalidateParseCache()
 		lookedUpNode.SetConsumeTriggers(
 			annotation.ConsumeTriggerSliceAsGuarded(
 				lookedUpNode.ConsumeTriggers(), guard))
@@ -503,6 +556,7 @@ func guardExpr(rootNode *RootAssertionNode, expr TrackableExpr, guard util.Guard
 // the _end_ of each block.
 //
 // Important: do not duplicate any pointers: each returned RichCheckEffect should be a unique object
+// (except for the FlagCorrelations and IterationCorrelations, see below)
 func genInitialRichCheckEffects(graph *cfg.CFG, functionContext FunctionContext) (
 	[][]RichCheckEffect, util.ExprNonceMap) {
 	richCheckBlocks := make([][]RichCheckEffect, len(graph.Blocks))
@@ -512,8 +566,16 @@ func genInitialRichCheckEffects(graph *cfg.CFG, functionContext FunctionContext)
 	// We use a temporary root here as a means to pass contextual information like the function
 	// declaration and analysis pass.
 	rootNode := newRootAssertionNode(nonceGenerator.GetExprNonceMap(), functionContext)
+	flagCorrelations := flagCorrelationsFromCFG(rootNode, nonceGenerator, graph)
+	iterationCorrelations := iterationCorrelationsFromCFG(rootNode, nonceGenerator, graph)
 	for i, block := range graph.Blocks {
 		var richCheckEffects []RichCheckEffect
+		if i == 0 {
+			// The parameters of the function are assigned at its entry, see errGuardedParamEffect.
+			if effect := errGuardedParamEffect(rootNode, nonceGenerator); effect != nil {
+				richCheckEffects = append(richCheckEffects, effect)
+			}
+		}
 		for _, node := range block.Nodes {
 
 			// invalidate any richCheckEffects that this node invalidates
@@ -528,6 +590,12 @@ func genInitialRichCheckEffects(graph *cfg.CFG, functionContext FunctionContext)
 				richCheckEffects = append(richCheckEffects, effects...)
 			}
 		}
+		// A FlagCorrelation is never invalidated, and it is sound regardless of whether the
+		// definition of the flag reaches the block (see FlagCorrelation), so it is present in every
+		// block. Note that this is the only case where the same pointer appears in multiple blocks.
+		richCheckEffects = append(richCheckEffects, flagCorrelations...)
+		// The same holds for an IterationCorrelation (see IterationCorrelation).
+		richCheckEffects = append(richCheckEffects, iterationCorrelations...)
 		// richCheckEffects is now fully populated
 
 		// strip out noops and write into richCheckBlocks
@@ -536,6 +604,55 @@ func genInitialRichCheckEffects(graph *cfg.CFG, functionContext FunctionContext)
 	return richCheckBlocks, nonceGenerator.GetExprNonceMap()
 }
 
+// nodeEffectsFromRichChecks computes the effects of the rich checks that are applied when specific
+// nodes are back-propagated, rather than at the branches of conditionals:
+//
+//   - For a return statement of an ok-returning function (see util.FuncIsOkReturning) that passes
+//     on the value and the `ok` of a rich check effect to the callers, e.g.,
+//     `v, ok := m[k]; return v, ok`, the returned value is guarded as if the `ok` were checked,
+//     since the callers must check the returned `ok` before using the returned value. This allows
+//     the return sites to be inferred nonnil, i.e., the contract of ok-returning functions: the
+//     results are nonnil if `ok` is true.
+//   - For a FlagCorrelation, the guarded consumptions of the value are dropped at the resets of
+//     the flag.
+//   - For an IterationCorrelation, the guarded consumptions of the value are dropped at the entry
+//     of the loop.
+func nodeEffectsFromRichChecks(funcObj *types.Func, graph *cfg.CFG, richCheckBlocks [][]RichCheckEffect) *nodeEffects {
+	effects := &nodeEffects{}
+	isOkReturning := funcObj != nil && util.FuncIsOkReturning(funcObj)
+	seen := make(map[RichCheckEffect]bool)
+	for i, block := range graph.Blocks {
+		ret := block.Return()
+		for _, effect := range richCheckBlocks[i] {
+			var passedOn bool
+			switch e := effect.(type) {
+			case *MapOkRead:
+				passedOn = ret != nil && e.isPassedOnBy(ret)
+			case *ChannelOkRecv:
+				passedOn = ret != nil && e.isPassedOnBy(ret)
+			case *FuncOkReturn:
+				passedOn = ret != nil && e.isPassedOnBy(ret)
+			case *FlagCorrelation:
+				if !seen[e] {
+					seen[e] = true
+					for _, reset := range e.resets {
+						effects.addBefore(reset, e.effectOnReset)
+					}
+				}
+			case *IterationCorrelation:
+				if !seen[e] {
+					seen[e] = true
+					effects.addBefore(e.entry, e.effectOnEntry)
+				}
+			}
+			if passedOn && isOkReturning {
+				effects.addAfter(ret, effect.effectIfTrue)
+			}
+		}
+	}
+	return effects
+}
+
 // stripNoops returns a copy of the passed slice `effects`, minus any no-ops
 func stripNoops(effects []RichCheckEffect) []RichCheckEffect {
 	var strippedEffects []RichCheckEffect
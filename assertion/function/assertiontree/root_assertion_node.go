@@ -22,7 +22,9 @@ import (
 	"go/types"
 
 	"go.uber.org/nilaway/annotation"
+	"go.uber.org/nilaway/assertion/anonymousfunc"
 	"go.uber.org/nilaway/config"
+	"go.uber.org/nilaway/hook"
 	"go.uber.org/nilaway/util"
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/ast/astutil"
@@ -48,6 +50,11 @@ type RootAssertionNode struct {
 	// functionContext holds the context of the function during backpropagation. The state includes
 	// map objects that are created at initialization, and configurations that are passed through function analyzer.
 	functionContext FunctionContext
+
+	// parseCache memoizes the results of ParseExprAsProducer for this root. It is never copied by
+	// CopyNode, so it lives for a single block iteration of the backpropagation, and it is
+	// invalidated whenever the root is mutated (see invalidateParseCache).
+	parseCache map[parseCacheKey]TrackableExpr
 }
 
 // LocationOf returns the location of the given expression.
@@ -55,10 +62,40 @@ func (r *RootAssertionNode) LocationOf(expr ast.Expr) token.Position {
 	return util.PosToLocation(expr.Pos(), r.Pass())
 }
 
-// HasContract returns if the given function has any contracts.
+// HasContract returns if the given function has any contracts that require unique param and
+// return sites at every call site. The contracts of nil-check predicates (e.g., `nil -> true`)
+// are not counted since they are honored by the preprocessor at the branches instead, and neither
+// are the contracts of receiver-returning methods (see ReturnsReceiver) and pure functions (see
+// IsPure).
 func (r *RootAssertionNode) HasContract(funcObj *types.Func) bool {
-	_, ok := r.functionContext.funcContracts[funcObj]
-	return ok
+	for _, ctr := range r.functionContext.funcContracts[funcObj] {
+		if _, _, ok := ctr.NilCheck(); !ok && !ctr.IsReceiverToResult() && !ctr.IsPure() {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPure returns if the given function is pure (i.e., it has the contract `contract(pure)`), such
+// that repeated calls to it with the same arguments can be assumed to return the same value.
+func (r *RootAssertionNode) IsPure(funcObj *types.Func) bool {
+	for _, ctr := range r.functionContext.funcContracts[funcObj] {
+		if ctr.IsPure() {
+			return true
+		}
+	}
+	return false
+}
+
+// ReturnsReceiver returns if the given method always returns its receiver (i.e., it has the
+// contract `recv -> recv`), such as the `WithX` methods of builders.
+func (r *RootAssertionNode) ReturnsReceiver(funcObj *types.Func) bool {
+	for _, ctr := range r.functionContext.funcContracts[funcObj] {
+		if ctr.IsReceiverToResult() {
+			return true
+		}
+	}
+	return false
 }
 
 // MinimalString for a RootAssertionNode returns a minimal string representation of that root node
@@ -68,6 +105,7 @@ func (r *RootAssertionNode) MinimalString() string {
 
 // AddNewTriggers adds the given new triggers to the existing set of triggers of this node
 func (r *RootAssertionNode) AddNewTriggers(newTrigger ...annotation.FullTrigger) {
+	r.invalidateParseCache()
 	r.triggers = annotation.MergeFullTriggers(r.triggers, newTrigger...)
 }
 
@@ -134,7 +172,10 @@ func (r *RootAssertionNode) GetTriggers() []annotation.FullTrigger {
 func (r *RootAssertionNode) GetDeclaringIdent(obj types.Object) *ast.Ident {
 
 	if path, ok := GetDeclaringPath(r.Pass(), obj.Pos(), obj.Pos()); ok && len(path) > 0 {
-		if ident, ok := path[0].(*ast.Ident); ok && ident.Name == obj.Name() {
+		// Note that the symbolic variable of a type switch (e.g., `v` in `switch v := x.(type)`) is
+		// declared by an identifier without any associated object (see backpropAcrossTypeSwitch),
+		// so we have to create a fake identifier for it below instead.
+		if ident, ok := path[0].(*ast.Ident); ok && ident.Name == obj.Name() && r.Pass().TypesInfo.ObjectOf(ident) != nil {
 			return ident
 		}
 		// In case the declaration is package.ident
@@ -167,6 +208,39 @@ func (r *RootAssertionNode) ObjectOf(ident *ast.Ident) types.Object {
 	return r.functionContext.findFakeIdent(ident)
 }
 
+// desugarPromotion rewrites a selector expression that accesses a promoted field or method
+// through embedded fields (e.g., `x.F` where `F` is declared in an embedded field `E` of `x`) to
+// its explicit form (e.g., `x.E.F`), such that the embedded fields become part of the access path
+// and are themselves required to be nonnil. Other selector expressions are returned as is. The
+// desugared expressions are cached, so that repeated calls with the same expression return the
+// same artificial nodes.
+func (r *RootAssertionNode) desugarPromotion(expr *ast.SelectorExpr) *ast.SelectorExpr {
+	if desugared, ok := r.functionContext.promotionCache[expr]; ok {
+		return desugared
+	}
+
+	selection, ok := r.Pass().TypesInfo.Selections[expr]
+	if !ok || len(selection.Index()) <= 1 {
+		return expr
+	}
+
+	fieldOf, t := expr.X, selection.Recv()
+	for _, index := range selection.Index()[:len(selection.Index())-1] {
+		structType := util.TypeAsDeeplyStruct(types.Unalias(t))
+		if structType == nil || index >= structType.NumFields() {
+			// This should not happen for well-typed code, but we do not want to crash the
+			// analysis if it does, so we simply leave the expression as is.
+			return expr
+		}
+		embedded := structType.Field(index)
+		fieldOf, t = r.getSelectorExpr(embedded, fieldOf), embedded.Type()
+	}
+
+	desugared := &ast.SelectorExpr{X: fieldOf, Sel: expr.Sel}
+	r.functionContext.promotionCache[expr] = desugared
+	return desugared
+}
+
 // funcArgsFromCallExpr returns the set of arguments that are passed to the method at the call site. If the method
 // is an anonymous function, it expands the argument set with the closure variables collected for that function
 func (r *RootAssertionNode) funcArgsFromCallExpr(expr *ast.CallExpr) []ast.Expr {
@@ -289,6 +363,7 @@ func (r *RootAssertionNode) AddConsumption(consumer *annotation.ConsumeTrigger)
 		// TODO - possibly avoid merging in a whole new path
 		// ^^^^ But I suspect gains would be marginal or non-existant - same logic either way
 		r.mergeInto(r, newRoot)
+		r.touchTopLevel(path[0])
 	}
 }
 
@@ -339,6 +414,7 @@ func (r *RootAssertionNode) AddProduction(producer *annotation.ProduceTrigger, d
 	// e.g. in `x.f = nonNilVal(); x = foo(); x.f.g()` the production at `x = foo()` also has the effect of
 	// invalidating the previous assignment to `x.f`.
 
+	r.invalidateParseCache()
 	r.triggerProductions(currNode, producer, deeperProducer...)
 
 	detachFromParent(currNode, whichChild)
@@ -347,20 +423,27 @@ func (r *RootAssertionNode) AddProduction(producer *annotation.ProduceTrigger, d
 // triggerProductions takes a node (assumed to be attached to its parent) and matches any of its
 // consumeTriggers with the given produceTrigger, as well as matching any more deeply found consumeTriggers
 // with the default non-tracked produceTriggers of their consuming expressions. Direct children of the
-// node being produced also have the option to be matches with a single optionally passed `deeperProducer`,
-// used for assignments by values with known deep nilness properties.
+// node being produced also have the option to be matches with the optionally passed `deeperProducer`s,
+// used for assignments by values with known deep nilness properties. Passing more than one deeper
+// producer means that the indices are produced by any of them (e.g., the elements of a slice
+// built by `append`), not that they produce deeper levels of indices.
 func (r *RootAssertionNode) triggerProductions(node AssertionNode, producer *annotation.ProduceTrigger, deeperProducer ...*annotation.ProduceTrigger) {
 
-	// first we check if we were passed a deeper producer. If so, we use it to produce any \
+	// first we check if we were passed deeper producers. If so, we use them to produce any
 	// indexAssertionNode children of the currNode
 	if len(deeperProducer) != 0 {
-		if len(deeperProducer) != 1 {
-			// TODO: consider allowing multiple levels of deeper producers to be passed -
-			//   but very incompatible with current annotations approach so not yet
-			panic("for now - only one level of deeper producer is supported, don't pass more")
-		}
 		for _, child := range node.Children() {
 			if child, ok := child.(*indexAssertionNode); ok {
+				// the consumers of the index are matched with each of the other deeper producers
+				// here, and the first one is left to consume (and clear) them as usual below
+				for _, other := range deeperProducer[1:] {
+					for _, consumer := range child.ConsumeTriggers() {
+						r.AddNewTriggers(annotation.FullTrigger{
+							Producer: other,
+							Consumer: consumer.Copy(),
+						})
+					}
+				}
 				r.triggerProductions(child, deeperProducer[0])
 			}
 		}
@@ -493,6 +576,7 @@ func (r *RootAssertionNode) AddGuardMatch(expr ast.Expr, behavior GuardMatchBeha
 	if currNode == nil {
 		return // we don't care if this expression could become guarded because it's not tracked
 	}
+	r.invalidateParseCache()
 	consumers := currNode.ConsumeTriggers()
 	switch behavior {
 	case ContinueTracking:
@@ -532,6 +616,108 @@ func (r *RootAssertionNode) consumeIndexExpr(expr ast.Expr) {
 			Guards:     util.NoGuards(),
 		})
 	}
+	// reading from a nil map is safe in Go, so the maps are only consumed in the strict mode
+	if util.TypeIsDeeplyMap(t) && r.functionContext.functionConfig.ErrorOnNilableMapRead {
+		r.AddConsumption(&annotation.ConsumeTrigger{
+			Annotation: &annotation.MapAccess{ConsumeTriggerTautology: &annotation.ConsumeTriggerTautology{}},
+			Expr:       expr,
+			Guards:     util.NoGuards(),
+		})
+	}
+}
+
+// consumeEnforcedFields adds consumptions for the fields of a struct composite literal that are
+// annotated with a field-level `//nonnil` (see annotation.EnforcedNonNilFields): the values
+// initializing such fields must be nonnil, and omitting such fields (i.e., leaving them nil) is
+// reported at the composite literal.
+func (r *RootAssertionNode) consumeEnforcedFields(expr *ast.CompositeLit) {
+	enforced := r.functionContext.functionConfig.EnforcedNonNilFields
+	if len(enforced) == 0 {
+		return
+	}
+	t := r.Pass().TypesInfo.TypeOf(expr)
+	if t == nil {
+		return
+	}
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return
+	}
+
+	fldAssign := func(fld *types.Var) *annotation.FldAssign {
+		return &annotation.FldAssign{
+			TriggerIfNonNil: &annotation.TriggerIfNonNil{
+				Ann: &annotation.FieldAnnotationKey{FieldDecl: fld},
+			},
+		}
+	}
+
+	initialized := make(map[*types.Var]bool)
+	for i, elt := range expr.Elts {
+		fld, value := (*types.Var)(nil), elt
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			if key, ok := kv.Key.(*ast.Ident); ok {
+				fld, _ = r.ObjectOf(key).(*types.Var)
+			}
+			value = kv.Value
+		} else if i < st.NumFields() {
+			fld = st.Field(i)
+		}
+		if fld == nil {
+			continue
+		}
+		initialized[fld] = true
+		if enforced[fld] {
+			r.AddConsumption(&annotation.ConsumeTrigger{
+				Annotation: fldAssign(fld),
+				Expr:       value,
+				Guards:     util.NoGuards(),
+			})
+		}
+	}
+
+	r.consumeOmittedEnforcedFields(st, initialized, expr)
+}
+
+// consumeZeroValueEnforcedFields reports the fields annotated with a field-level `//nonnil` (see
+// consumeEnforcedFields) that are left nil by the zero value of type t constructed at expr, i.e.,
+// `new(T)` or `var x T`, which omit all the fields like `T{}`.
+func (r *RootAssertionNode) consumeZeroValueEnforcedFields(t types.Type, expr ast.Expr) {
+	if len(r.functionContext.functionConfig.EnforcedNonNilFields) == 0 || t == nil {
+		return
+	}
+	if st, ok := t.Underlying().(*types.Struct); ok {
+		r.consumeOmittedEnforcedFields(st, nil, expr)
+	}
+}
+
+// consumeOmittedEnforcedFields reports the fields of the struct annotated with a field-level
+// `//nonnil` that are not initialized by the construction of the struct at expr, nor assigned
+// right after it (e.g., `s := &T{}; s.f = x`, see collectInitializedFields).
+func (r *RootAssertionNode) consumeOmittedEnforcedFields(st *types.Struct, initialized map[*types.Var]bool, expr ast.Expr) {
+	enforced := r.functionContext.functionConfig.EnforcedNonNilFields
+	assigned := r.functionContext.initializedFields[expr]
+	for i := 0; i < st.NumFields(); i++ {
+		fld := st.Field(i)
+		if !enforced[fld] || initialized[fld] || assigned[fld] {
+			continue
+		}
+		r.AddNewTriggers(annotation.FullTrigger{
+			Producer: &annotation.ProduceTrigger{
+				Annotation: &annotation.UnassignedFld{ProduceTriggerTautology: &annotation.ProduceTriggerTautology{}},
+				Expr:       expr,
+			},
+			Consumer: &annotation.ConsumeTrigger{
+				Annotation: &annotation.FldAssign{
+					TriggerIfNonNil: &annotation.TriggerIfNonNil{
+						Ann: &annotation.FieldAnnotationKey{FieldDecl: fld},
+					},
+				},
+				Expr:   expr,
+				Guards: util.NoGuards(),
+			},
+		})
+	}
 }
 
 // AddComputation takes the knowledge that the expression expr has to be computed to generate any necessary assertions to
@@ -586,6 +772,25 @@ func (r *RootAssertionNode) AddComputation(expr ast.Expr) {
 		r.AddComputation(expr.X)
 	case *ast.CallExpr:
 		r.AddComputation(expr.Fun)
+		if call := r.asBuiltinCall(expr, util.BuiltinNew); call != nil && len(call.Args) == 1 {
+			r.consumeZeroValueEnforcedFields(r.Pass().TypesInfo.TypeOf(call.Args[0]), expr)
+		}
+		if fun, ok := expr.Fun.(*ast.SelectorExpr); ok && r.isType(fun.X) && len(expr.Args) > 0 {
+			// A call to a method expression (e.g., `T.foo(x, ...)` or `(*T).foo(x, ...)`) passes
+			// its first argument as the receiver, which is excluded from the arguments below.
+			if selection, ok := r.Pass().TypesInfo.Selections[fun]; ok && len(selection.Index()) > 1 {
+				// The method is promoted through embedded fields of the receiver, which must
+				// then be nonnil to reach the method.
+				r.AddConsumption(&annotation.ConsumeTrigger{
+					Annotation: &annotation.FldAccess{ConsumeTriggerTautology: &annotation.ConsumeTriggerTautology{}, Sel: r.ObjectOf(fun.Sel)},
+					Expr:       expr.Args[0],
+					Guards:     util.NoGuards(),
+				})
+			} else {
+				r.consumeRecv(expr.Args[0], fun.Sel)
+			}
+			r.AddComputation(expr.Args[0])
+		}
 		exprArgs := r.funcArgsFromCallExpr(expr)
 		var consumeArg func(int, ast.Expr)
 		consumeArgNoop := func(int, ast.Expr) {}
@@ -697,7 +902,7 @@ func (r *RootAssertionNode) AddComputation(expr ast.Expr) {
 					//   foo(s) // <-- track shallow and deep nilability of `s` here
 					// }
 					// ```
-					if util.TypeIsDeep(r.Pass().TypesInfo.TypeOf(arg)) {
+					if util.TypeIsDeepAtSite(r.Pass().TypesInfo.TypeOf(arg)) {
 						deepProducer := &annotation.ProduceTrigger{
 							Annotation: exprAsDeepProducer(r, arg),
 							Expr:       arg,
@@ -726,7 +931,17 @@ func (r *RootAssertionNode) AddComputation(expr ast.Expr) {
 		if fun := getFuncIdent(expr, &r.functionContext); fun != nil && r.isFunc(fun) {
 			// here we have found a call to a function whose declaration we have access to,
 			// so we can mark its arguments as consumed
-			consumeArg = consumeArgTrigger(r.ObjectOf(fun).(*types.Func))
+			if hook.AcceptNilArgs(r.Pass(), expr) {
+				// the arguments are stored as opaque values (e.g., the results set up on the
+				// expected calls of mocks), so they are not consumed
+				consumeArg = consumeArgNoop
+			} else {
+				consumeArg = consumeArgTrigger(r.ObjectOf(fun).(*types.Func))
+			}
+
+			// Connect the callback sites of the function to the functions passed as its
+			// callbacks (e.g., `handle` in `Walk(handle)`).
+			r.addCallbackFlows(r.ObjectOf(fun).(*types.Func), expr, exprArgs)
 
 			if r.functionContext.functionConfig.EnableStructInitCheck {
 				// Add Productions for struct field params
@@ -740,6 +955,27 @@ func (r *RootAssertionNode) AddComputation(expr ast.Expr) {
 			// or a typecast like int(x) - in either case (at least for now), do nothing to try
 			// to consume the arguments
 			consumeArg = consumeArgNoop
+
+			// a call to a callback (i.e., a function-typed parameter) of this function passes
+			// the arguments to the callback sites
+			if paramNum, ok := r.calledCallbackParam(expr); ok {
+				r.consumeCallbackArgs(expr, paramNum)
+			}
+			// similarly, a call to a callback stored in a function-typed struct field passes the
+			// arguments to the field callback sites
+			if field := r.calledCallbackField(expr); field != nil {
+				r.consumeFieldCallbackArgs(expr, field)
+			}
+		}
+
+		// In the conservative reflect-escape mode, the arguments escaping via reflection, unsafe
+		// conversions or cgo calls must be nonnil as well.
+		r.consumeReflectEscape(expr)
+
+		// The registered trigger plugins may consume the values at the call as well (e.g., the
+		// arguments passed to an audit sink).
+		for _, consumer := range hook.PluginConsumers(r.Pass(), expr) {
+			r.AddConsumption(consumer)
 		}
 
 		// when we reach this point, consumeArg will be set to a no-op exactly if we don't know
@@ -751,6 +987,9 @@ func (r *RootAssertionNode) AddComputation(expr ast.Expr) {
 			r.AddComputation(arg)
 		}
 	case *ast.CompositeLit:
+		r.addCompositeLitCallbackFlows(expr)
+		r.consumeEnforcedFields(expr)
+		r.consumeOmittedFields(expr)
 		for _, elt := range expr.Elts {
 			r.AddComputation(elt)
 		}
@@ -771,63 +1010,17 @@ func (r *RootAssertionNode) AddComputation(expr ast.Expr) {
 			}
 		}
 
-		// A selector expression (`X.Sel`, where X is an expression and Sel is a selector) can be handled in the following two ways:
-		// - (1) Allow the expression X to be nilable by creating a TriggerIfNonNil consumer for it. This is a special case,
-		//       with so far the only known case being of method invocations for supporting nilable receivers. Our support
-		//       is currently limited to enabling this analysis only if the below criteria is satisfied.
-		//       - Check 1: selector expression is a method invocation (e.g., `s.foo()`)
-		//       - Check 2: receiver is a pointer receiver (e.g., `func (s *S) foo()` or `func (*S) foo()`). Go automatically
-		//			dereferences a value (non-pointer) receiver when a method is called on a pointer to the type. This means that
-		//			this is not a candidate for analyzing nilable receiver, instead we should check for nilablilty of the
-		//			receiver at the call site itself.
-		//       - In-scope flow:
-		//       	- Check 3: the invoked method is in scope
-		//       	- Check 4: the invoking expression (caller) is of a non-interface type (e.g., struct or named). (We are
-		//       		restricting support only for non-interfaces due to the challenges of secret nil for interfaces.)
-		//       - Out-of-scope flow:
-		//          - Check 5: consider the criteria satisfied to support optimistic default
-		//
-		// - (2) Don't allow the expression X to be nilable by creating a FldAccess (ConsumeTriggerTautology) consumer for it.
-		//       This is default behavior which gets triggered if the above special case is not satisfied.
-
-		allowNilable := false
-		if funcObj, ok := r.ObjectOf(expr.Sel).(*types.Func); ok { // Check 1:  selector expression is a method invocation
-			recv := funcObj.Type().(*types.Signature).Recv()
-			if util.TypeIsDeeplyPtr(recv.Type()) { // Check 2: receiver is a pointer receiver
-				conf := r.Pass().ResultOf[config.Analyzer].(*config.Config)
-				if conf.IsPkgInScope(funcObj.Pkg()) { // Check 3: invoked method is in scope
-					// Here, `t` can only be of type interface, struct, or named, of which we only support for struct and named types.
-					if !util.TypeIsDeeplyInterface(r.Pass().TypesInfo.TypeOf(expr.X)) { // Check 4: invoking expression (caller) is of a non-interface type (e.g., struct or named)
-						allowNilable = true
-						// We are in the special case of supporting nilable receivers! Can be nilable depending on declaration annotation/inferred nilability.
-						r.AddConsumption(&annotation.ConsumeTrigger{
-							Annotation: &annotation.RecvPass{
-								TriggerIfNonNil: &annotation.TriggerIfNonNil{
-									Ann: &annotation.RecvAnnotationKey{
-										FuncDecl: funcObj,
-									},
-								}},
-							Expr:   expr.X,
-							Guards: util.NoGuards(),
-						})
-					}
-				} else { // Check 5: invoked method is out of scope
-					// We are setting an optimistic default here for methods out of scope, specifically to avoid
-					// false positives being reported for methods in generated code. It means that such external
-					// methods are assumed to be safely handling nil receivers
-					allowNilable = true
-				}
-			}
-		}
-		if !allowNilable {
-			// We are in the default case -- it's a field/method access! Must be non-nil.
-			r.AddConsumption(&annotation.ConsumeTrigger{
-				Annotation: &annotation.FldAccess{ConsumeTriggerTautology: &annotation.ConsumeTriggerTautology{}, Sel: r.ObjectOf(expr.Sel)},
-				Expr:       expr.X,
-				Guards:     util.NoGuards(),
-			})
+		if r.isType(expr.X) {
+			// This is a method expression (e.g., `T.foo` or `(*T).foo`), whose receiver is passed
+			// as the first argument instead, so it is consumed at the call site (see the
+			// `*ast.CallExpr` case). A method expression not immediately called is not tracked.
+			return
 		}
 
+		// Accesses to promoted fields or methods also access the embedded fields on the way.
+		expr = r.desugarPromotion(expr)
+		r.consumeRecv(expr.X, expr.Sel)
+
 		r.AddComputation(expr.X)
 	case *ast.SliceExpr:
 		// similar to index case
@@ -881,6 +1074,17 @@ func (r *RootAssertionNode) AddComputation(expr ast.Expr) {
 // is an anonymous function, it will return the fake function declaration created in the
 // function analyzer
 func getFuncIdent(expr *ast.CallExpr, fc *FunctionContext) *ast.Ident {
+	if info := getFuncLitInfo(expr, fc); info != nil {
+		return info.FakeFuncDecl.Name
+	}
+	return util.FuncIdentFromCallExpr(expr)
+}
+
+// getFuncLitInfo returns the auxiliary information of the function literal called by the call
+// expression, either directly (e.g., `func() {...}()`) or through a variable it is assigned to
+// (e.g., `f()` for `f := func() {...}`). It returns nil if the called function is not such a
+// function literal, or if the function literal is not analyzed (see anonymousfunc.Analyzer).
+func getFuncLitInfo(expr *ast.CallExpr, fc *FunctionContext) *anonymousfunc.FuncLitInfo {
 	ident := util.FuncIdentFromCallExpr(expr)
 
 	var funcLit *ast.FuncLit
@@ -892,13 +1096,10 @@ func getFuncIdent(expr *ast.CallExpr, fc *FunctionContext) *ast.Ident {
 		funcLit = getFuncLitFromAssignment(ident)
 	}
 
-	if funcLit != nil {
-		if info, ok := fc.funcLitMap[funcLit]; ok {
-			return info.FakeFuncDecl.Name
-		}
+	if funcLit == nil {
+		return nil
 	}
-
-	return ident
+	return fc.funcLitMap[funcLit]
 }
 
 // getFuncLitFromAssignment if the declaration of the ident is an assignment
@@ -952,6 +1153,7 @@ func (r *RootAssertionNode) LiftFromPath(path TrackableExpr) (AssertionNode, boo
 	if path != nil {
 		node, whichChild := r.lookupPath(path)
 		if node != nil {
+			r.invalidateParseCache()
 			detachFromParent(node, whichChild)
 			return node, true
 		}
@@ -983,6 +1185,7 @@ func (r *RootAssertionNode) LandAtPath(path TrackableExpr, node AssertionNode) {
 		lastNode.SetChildren(childrenToAdd)
 
 		r.mergeInto(r, newRoot)
+		r.touchTopLevel(path[0])
 	}
 }
 
@@ -996,6 +1199,14 @@ type RootFunc = func(*RootAssertionNode)
 // - producing all non-parameter variables as definitely nil (noVarAssign)
 // - producing all remaining function assertions according to their annotation (retAnnotationKey)
 func (r *RootAssertionNode) ProcessEntry() {
+	// The consumptions of the parameter guarded by a nil check of its error parameter are matched
+	// here, where the parameter is produced (see errGuardedParamEffect).
+	var guardedParam types.Object
+	if param, _ := r.errGuardedParam(); param != nil {
+		r.AddGuardMatch(param, ContinueTracking)
+		guardedParam = r.ObjectOf(param)
+	}
+
 	for len(r.Children()) > 0 {
 		child := r.Children()[0]
 		builtExpr := child.BuildExpr(nil)
@@ -1005,10 +1216,27 @@ func (r *RootAssertionNode) ProcessEntry() {
 			r.addProductionsForParamFields(child, builtExpr)
 		}
 
+		var deeperProducers []*annotation.ProduceTrigger
+		if v, ok := child.(*varAssertionNode); ok && r.isUnassignedLocalArray(v.decl) {
+			// the elements of a local array that was never assigned to are all nil, so we produce
+			// them as such rather than reading the (nonnil) deep nilability of the local variable
+			deeperProducers = append(deeperProducers, &annotation.ProduceTrigger{
+				Annotation: &annotation.UnassignedArrayElem{
+					ProduceTriggerTautology: &annotation.ProduceTriggerTautology{},
+					VarObj:                  v.decl,
+				},
+				Expr: builtExpr,
+			})
+		}
+
+		trigger := child.DefaultTrigger()
+		if v, ok := child.(*varAssertionNode); ok && guardedParam != nil && v.decl == guardedParam {
+			trigger.SetNeedsGuard(true)
+		}
 		r.AddProduction(&annotation.ProduceTrigger{
-			Annotation: child.DefaultTrigger(),
+			Annotation: trigger,
 			Expr:       builtExpr,
-		})
+		}, deeperProducers...)
 	}
 
 	// filter triggers for error return handling -- intra-procedural
@@ -1034,6 +1262,99 @@ func (r *RootAssertionNode) ProcessEntry() {
 	}
 }
 
+// isUnassignedLocalArray returns true if `v` is a local array variable with nilable elements that is
+// declared without an initializer (e.g., `var arr [3]*T`) and is never written to or aliased anywhere
+// in the function body. The elements of such an array are therefore all nil. We are conservative here:
+// any assignment to the array or its elements, as well as taking its address or slicing it, disqualifies
+// the variable.
+func (r *RootAssertionNode) isUnassignedLocalArray(v *types.Var) bool {
+	fdecl := r.FuncObj()
+	if annotation.VarIsParam(fdecl, v) || annotation.VarIsRecv(fdecl, v) || annotation.VarIsGlobal(v) {
+		return false
+	}
+	arrType, ok := v.Type().Underlying().(*types.Array)
+	if !ok || util.TypeBarsNilness(arrType.Elem()) {
+		return false
+	}
+	funcDecl := r.FuncDecl()
+	if funcDecl == nil || funcDecl.Body == nil {
+		return false
+	}
+
+	info := r.Pass().TypesInfo
+	declared, unassigned := false, true
+	var stack []ast.Node
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		if !unassigned {
+			return false
+		}
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		stack = append(stack, n)
+
+		switch n := n.(type) {
+		case *ast.ValueSpec:
+			for _, name := range n.Names {
+				if info.Defs[name] == v {
+					declared = true
+					unassigned = len(n.Values) == 0
+				}
+			}
+		case *ast.Ident:
+			if info.Uses[n] != v {
+				return true
+			}
+			var parent, grandparent ast.Node
+			if len(stack) >= 2 {
+				parent = stack[len(stack)-2]
+			}
+			if len(stack) >= 3 {
+				grandparent = stack[len(stack)-3]
+			}
+			switch parent := parent.(type) {
+			case *ast.IndexExpr:
+				// reading an element is allowed, writing to it or taking its address is not
+				if parent.X == n && !isWriteTarget(grandparent, parent) {
+					return true
+				}
+			case *ast.CallExpr:
+				// arrays are passed by value, so the callee cannot write to our copy
+				if parent.Fun != n {
+					return true
+				}
+			case *ast.RangeStmt:
+				if parent.X == n {
+					return true
+				}
+			}
+			unassigned = false
+		}
+		return true
+	})
+	return declared && unassigned
+}
+
+// isWriteTarget returns true if `expr` is written to or has its address taken in its parent node `parent`.
+func isWriteTarget(parent ast.Node, expr ast.Expr) bool {
+	switch parent := parent.(type) {
+	case *ast.AssignStmt:
+		for _, lhs := range parent.Lhs {
+			if lhs == expr {
+				return true
+			}
+		}
+	case *ast.RangeStmt:
+		return parent.Key == expr || parent.Value == expr
+	case *ast.IncDecStmt:
+		return parent.X == expr
+	case *ast.UnaryExpr:
+		return parent.Op == token.AND && parent.X == expr
+	}
+	return false
+}
+
 // performs a shallow comparison of two nodes - doesn't recur into their subtrees and doesn't look at triggers
 // invariant on AssertionNodes is that this can never hold between any two of their distinct children
 func (r *RootAssertionNode) shallowEqNodes(left, right AssertionNode) bool {
@@ -1131,6 +1452,20 @@ func (r *RootAssertionNode) isBuiltIn(ident *ast.Ident) bool {
 	return ok
 }
 
+// asBuiltinCall returns the given expression as a call to the given builtin function (e.g.,
+// util.BuiltinAppend), or nil if it is not one (e.g., if the builtin is shadowed)
+// nilable(result 0)
+func (r *RootAssertionNode) asBuiltinCall(expr ast.Expr, builtin types.Object) *ast.CallExpr {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	if fun, ok := ast.Unparen(call.Fun).(*ast.Ident); ok && r.ObjectOf(fun) == builtin {
+		return call
+	}
+	return nil
+}
+
 // checks if a constant - e.g. "true"
 func (r *RootAssertionNode) isConst(ident *ast.Ident) bool {
 	_, ok := r.ObjectOf(ident).(*types.Const)
@@ -1173,6 +1508,83 @@ func (r *RootAssertionNode) isPkgName(expr ast.Expr) bool {
 	return false
 }
 
+// consumeRecv adds a consumer for the receiver expression `recv` of the method or field `sel`
+// being accessed, i.e., the `X` of a selector expression `X.sel`, or the first argument of a call
+// to a method expression `T.sel(X, ...)`.
+func (r *RootAssertionNode) consumeRecv(recv ast.Expr, sel *ast.Ident) {
+	// A selector expression (`X.Sel`, where X is an expression and Sel is a selector) can be handled in the following two ways:
+	// - (1) Allow the expression X to be nilable by creating a TriggerIfNonNil consumer for it. This is a special case,
+	//       with so far the only known case being of method invocations for supporting nilable receivers. Our support
+	//       is currently limited to enabling this analysis only if the below criteria is satisfied.
+	//       - Check 1: selector expression is a method invocation (e.g., `s.foo()`)
+	//       - Check 2: receiver is a pointer receiver (e.g., `func (s *S) foo()` or `func (*S) foo()`), or a value
+	//			receiver of a named map, slice or channel type (e.g., `func (m M) foo()` for `type M map[string]int`),
+	//			which is passed as is and hence can be nil. Go automatically dereferences a value (non-pointer)
+	//			receiver when a method is called on a pointer to the type (e.g., `m.foo()` where `m` is of type `*M`).
+	//			This means that this is not a candidate for analyzing nilable receiver, instead we should check for
+	//			nilablilty of the receiver at the call site itself. (Value receivers of named function types need no
+	//			special handling since function values are not tracked for nilness.)
+	//       - In-scope flow:
+	//       	- Check 3: the invoked method is in scope
+	//       	- Check 4: the invoking expression (caller) is of a non-interface type (e.g., struct or named). (We are
+	//       		restricting support only for non-interfaces due to the challenges of secret nil for interfaces. The
+	//       		interface values assigned from the nilable receivers are handled separately, see
+	//       		convertInterfaceRecvConsumer.)
+	//       - Out-of-scope flow:
+	//          - Check 5: consider the criteria satisfied to support optimistic default
+	//
+	// - (2) Don't allow the expression X to be nilable by creating a FldAccess (ConsumeTriggerTautology) consumer for it.
+	//       This is default behavior which gets triggered if the above special case is not satisfied.
+
+	allowNilable := false
+	if funcObj, ok := r.ObjectOf(sel).(*types.Func); ok { // Check 1:  selector expression is a method invocation
+		if recvIsNilable(funcObj, r.Pass().TypesInfo.TypeOf(recv)) { // Check 2: receiver is a pointer receiver or a value receiver that can be nil
+			conf := r.Pass().ResultOf[config.Analyzer].(*config.Config)
+			if conf.IsPkgInScope(funcObj.Pkg()) { // Check 3: invoked method is in scope
+				// Here, `t` can only be of type interface, struct, or named (e.g., named map), of which we only support for struct and named types.
+				if !util.TypeIsDeeplyInterface(r.Pass().TypesInfo.TypeOf(recv)) { // Check 4: invoking expression (caller) is of a non-interface type (e.g., struct or named)
+					allowNilable = true
+					// We are in the special case of supporting nilable receivers! Can be nilable depending on declaration annotation/inferred nilability.
+					r.AddConsumption(&annotation.ConsumeTrigger{
+						Annotation: &annotation.RecvPass{
+							TriggerIfNonNil: &annotation.TriggerIfNonNil{
+								Ann: &annotation.RecvAnnotationKey{
+									FuncDecl: funcObj,
+								},
+							}},
+						Expr:   recv,
+						Guards: util.NoGuards(),
+					})
+				}
+			} else { // Check 5: invoked method is out of scope
+				// We are setting an optimistic default here for methods out of scope, specifically to avoid
+				// false positives being reported for methods in generated code. It means that such external
+				// methods are assumed to be safely handling nil receivers
+				allowNilable = true
+			}
+		}
+	}
+	if !allowNilable {
+		// We are in the default case -- it's a field/method access! Must be non-nil.
+		r.AddConsumption(&annotation.ConsumeTrigger{
+			Annotation: &annotation.FldAccess{ConsumeTriggerTautology: &annotation.ConsumeTriggerTautology{}, Sel: r.ObjectOf(sel)},
+			Expr:       recv,
+			Guards:     util.NoGuards(),
+		})
+	}
+}
+
+// recvIsNilable returns true if the receiver of the method can be nil when the method is invoked on a
+// value of type recvType, i.e., the method has a pointer receiver, or a value receiver of a map, slice
+// or channel type invoked on a value of that type (not a pointer to it, which is dereferenced).
+func recvIsNilable(method *types.Func, recvType types.Type) bool {
+	t := method.Type().(*types.Signature).Recv().Type()
+	if util.TypeIsDeeplyPtr(t) {
+		return true
+	}
+	return util.TypeIsDeeplyNilableRecv(t) && recvType != nil && !util.TypeIsDeeplyPtr(recvType)
+}
+
 // checks if this is a type name
 func (r *RootAssertionNode) isTypeName(expr ast.Expr) bool {
 	if ident, ok := expr.(*ast.Ident); ok {
@@ -1210,8 +1622,8 @@ func (r *RootAssertionNode) isIntZero(expr ast.Expr) bool {
 
 // This function defines whether an expression is `stable` - i.e. whether we assume it constant
 // across multiple syntactic accesses. This obviously includes literal expressions closed under
-// builtin logical and arithmetic expressions, but, by assumption, includes function calls and
-// indexes by other `stable` expressions
+// builtin logical and arithmetic expressions, as well as calls to builtins and pure functions (see
+// IsPure) with other `stable` expressions as arguments
 func (r *RootAssertionNode) isStable(expr ast.Expr) bool {
 	switch expr := expr.(type) {
 	case *ast.BasicLit:
@@ -1231,15 +1643,16 @@ func (r *RootAssertionNode) isStable(expr ast.Expr) bool {
 		return r.isStable(expr.Fun)
 	case *ast.Ident:
 		// There are three cases in which we admit an identifier is a stable:
-		// if it is a builtin name, if it is a function name, or if it is const.
+		// if it is a builtin name, if it is a pure function name, or if it is const.
 		// Package is considered a special case of ident to suppport selector expressions used to access stable
 		// expressions, such as constants declared in another package (e.g., pkg.Const)
 		if r.isBuiltIn(expr) || r.isConst(expr) || r.isNil(expr) || r.isPkgName(expr) {
 			return true
 		}
-
-		// TODO: check for function names
-		return false
+		// The name of a pure function is stable as well, such that the calls to it with stable
+		// arguments are stable.
+		funcObj, ok := r.ObjectOf(expr).(*types.Func)
+		return ok && r.IsPure(funcObj)
 	case *ast.SelectorExpr:
 		return r.isStable(expr.Sel) && r.isStable(expr.X)
 	default:
@@ -1292,6 +1705,11 @@ func (r *RootAssertionNode) eqStable(left, right ast.Expr) bool {
 				(r.isPkgName(left) && r.isPkgName(right)) {
 				return left.Name == right.Name
 			}
+			// pure functions (see isStable) are equal if they are the same function
+			if rightFuncObj, ok := r.ObjectOf(right).(*types.Func); ok {
+				leftFuncObj, ok := r.ObjectOf(left).(*types.Func)
+				return ok && leftFuncObj == rightFuncObj && r.IsPure(leftFuncObj)
+			}
 			rightVarObj, rightOk := r.ObjectOf(right).(*types.Var)
 			leftVarObj, leftOk := r.ObjectOf(left).(*types.Var)
 
@@ -1332,6 +1750,7 @@ func (r *RootAssertionNode) mergeInto(left, right AssertionNode) {
 
 	if left, lok := left.(*RootAssertionNode); lok {
 		right := right.(*RootAssertionNode)
+		left.invalidateParseCache()
 		left.triggers = annotation.MergeFullTriggers(left.triggers, right.triggers...)
 	}
 
@@ -40,8 +40,17 @@ func (r *RootAssertionNode) addProductionsForAssignmentFields(fieldProducers []*
 
 			selExpr := r.getSelectorExpr(structType.Field(i), lhsVal)
 
+			// The field is read from the variable directly (e.g., `b.aptr.ptr`), so the message
+			// names the field left uninitialized.
+			ann := fieldProducer.Annotation
+			if unassigned, ok := ann.(*annotation.UnassignedFld); ok && unassigned.FieldName == "" {
+				named := *unassigned
+				named.FieldName = structType.Field(i).Name()
+				named.StructName = structName(r.Pass().TypesInfo.TypeOf(lhsVal))
+				ann = &named
+			}
 			r.AddProduction(&annotation.ProduceTrigger{
-				Annotation: fieldProducer.Annotation,
+				Annotation: ann,
 				Expr:       selExpr,
 			})
 
@@ -189,8 +198,13 @@ func (r *RootAssertionNode) addProductionForVarFieldNode(varNode *varAssertionNo
 			if varAstExpr == selExpr.X {
 				r.AddProduction(
 					&annotation.ProduceTrigger{
-						Annotation: &annotation.UnassignedFld{ProduceTriggerTautology: &annotation.ProduceTriggerTautology{}},
-						Expr:       selExpr,
+						Annotation: &annotation.UnassignedFld{
+							ProduceTriggerTautology: &annotation.ProduceTriggerTautology{},
+							StructInit:              true,
+							FieldName:               fldNode.decl.Name(),
+							StructName:              structName(r.Pass().TypesInfo.TypeOf(varAstExpr)),
+						},
+						Expr: selExpr,
 					})
 			}
 		}
@@ -516,3 +530,15 @@ func (r *RootAssertionNode) getSelectorExpr(fieldDecl *types.Var, fieldOf ast.Ex
 	fieldIdent := r.GetDeclaringIdent(fieldDecl)
 	return r.functionContext.getCachedSelectorExpr(fieldDecl, fieldOf, fieldIdent)
 }
+
+// structName returns the name of the (possibly pointed to) named struct type for the messages,
+// or an empty string if the type is not named (e.g., an anonymous struct).
+func structName(t types.Type) string {
+	if t == nil {
+		return ""
+	}
+	if named, ok := types.Unalias(util.UnwrapPtr(t)).(*types.Named); ok {
+		return named.Obj().Name()
+	}
+	return ""
+}
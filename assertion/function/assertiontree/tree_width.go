@@ -0,0 +1,68 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assertiontree
+
+import (
+	"go.uber.org/nilaway/annotation"
+)
+
+// The width of an assertion tree is the number of children of its root, i.e., the number of
+// distinct tracked expressions (such as variables) that have pending consumers. For functions
+// with a huge number of tracked expressions the trees (and hence the fixed point computation)
+// can explode, so we allow users to bound the width via FunctionConfig.MaxTreeWidth.
+//
+// When the limit is set, the children of the root are kept in least-recently-touched order: a
+// child is moved to the end of the children slice whenever a consumption is added for it, and new
+// children are always appended at the end. When the width exceeds the limit, the children at the
+// front are summarized: all consumers in their subtrees are matched with an annotation.TrackingSummarized
+// producer, which always produces nil. Hence, summarizing can only introduce (explained) false
+// positives, but never hide errors.
+
+// touchTopLevel marks the child of the root that is shallow-equal to the given node as the most
+// recently touched one. It is a no-op if the tree width is not limited.
+func (r *RootAssertionNode) touchTopLevel(node AssertionNode) {
+	if r.functionContext.functionConfig.MaxTreeWidth <= 0 {
+		return
+	}
+	children := r.Children()
+	for i, child := range children {
+		if r.shallowEqNodes(child, node) {
+			copy(children[i:], children[i+1:])
+			children[len(children)-1] = child
+			return
+		}
+	}
+}
+
+// summarizeExcessWidth summarizes the least recently touched children of the root until the
+// width of the tree is within the configured limit. It is a no-op if the tree width is not limited.
+func (r *RootAssertionNode) summarizeExcessWidth() {
+	limit := r.functionContext.functionConfig.MaxTreeWidth
+	if limit <= 0 {
+		return
+	}
+	for len(r.Children()) > limit {
+		r.invalidateParseCache()
+		child := r.Children()[0]
+		r.triggerProductions(child, &annotation.ProduceTrigger{
+			Annotation: &annotation.TrackingSummarized{
+				ProduceTriggerTautology: &annotation.ProduceTriggerTautology{},
+				Limit:                   limit,
+			},
+			Expr: child.BuildExpr(nil),
+		})
+		detachFromParent(child, 0)
+	}
+}
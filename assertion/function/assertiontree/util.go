@@ -21,6 +21,7 @@ import (
 	"go/types"
 
 	"go.uber.org/nilaway/annotation"
+	"go.uber.org/nilaway/hook"
 	"go.uber.org/nilaway/util"
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/ast/astutil"
@@ -192,6 +193,17 @@ func AddNilCheck(pass *analysis.Pass, expr ast.Expr) (trueCheck, falseCheck Root
 		trueNilCheck, falseNilCheck, isNoop := AddNilCheck(pass, e.X)
 		return falseNilCheck, trueNilCheck, isNoop
 	}
+	if call, ok := expr.(*ast.CallExpr); ok {
+		// A call to a trusted function (e.g., `errors.As(err, &target)`) that is replaced with
+		// `call && check` by the hook framework implies the nil check `check` (e.g.,
+		// `target != nil`) if it returns true.
+		if replaced, ok := hook.ReplaceConditional(pass, call).(*ast.BinaryExpr); ok &&
+			replaced.Op == token.LAND && replaced.X == ast.Expr(call) {
+			trueCheck, _, isNoop := AddNilCheck(pass, replaced.Y)
+			return trueCheck, noop, isNoop
+		}
+	}
+
 	binExpr, ok := expr.(*ast.BinaryExpr)
 	if !ok {
 		// `expr` is not a direct or indirect binary expression - do no work
@@ -396,7 +408,7 @@ func CopyNode(node AssertionNode) AssertionNode {
 	case *fldAssertionNode:
 		fresh = &fldAssertionNode{decl: node.decl, functionContext: node.functionContext}
 	case *funcAssertionNode:
-		fresh = &funcAssertionNode{decl: node.decl, args: node.args}
+		fresh = &funcAssertionNode{decl: node.decl, args: node.args, trusted: node.trusted}
 	case *indexAssertionNode:
 		fresh = &indexAssertionNode{
 			index:    node.index,
@@ -39,12 +39,13 @@ const _doc = "Read the contracts of each function in this package, returning the
 // Analyzer here is the analyzer than reads function contracts. It returns the map generated from
 // reading the function contracts in the source code.
 var Analyzer = &analysis.Analyzer{
-	Name:       "nilaway_function_contracts_analyzer",
-	Doc:        _doc,
-	Run:        analysishelper.WrapRun(run),
-	ResultType: reflect.TypeOf((*analysishelper.Result[Map])(nil)),
-	FactTypes:  []analysis.Fact{new(Contracts)},
-	Requires:   []*analysis.Analyzer{config.Analyzer, buildssa.Analyzer},
+	Name:             "nilaway_function_contracts_analyzer",
+	Doc:              _doc,
+	Run:              analysishelper.WrapRun(run),
+	ResultType:       reflect.TypeOf((*analysishelper.Result[Map])(nil)),
+	FactTypes:        []analysis.Fact{new(Contracts)},
+	Requires:         []*analysis.Analyzer{config.Analyzer, buildssa.Analyzer},
+	RunDespiteErrors: true,
 }
 
 // Contracts represents the list of contracts for a function.
@@ -126,7 +127,12 @@ func collectFunctionContracts(pass *analysis.Pass) (Map, error) {
 	conf := pass.ResultOf[config.Analyzer].(*config.Config)
 
 	// Collect ssa for every function.
-	ssaInput := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
+	ssaInput, ok := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
+	if !ok {
+		// The buildssa analyzer does not run on packages with type errors, in which case no
+		// contracts are collected.
+		return make(Map), nil
+	}
 	ssaOfFunc := make(map[*types.Func]*ssa.Function, len(ssaInput.SrcFuncs))
 	for _, fnssa := range ssaInput.SrcFuncs {
 		if fnssa == nil {
@@ -167,16 +173,32 @@ func collectFunctionContracts(pass *analysis.Pass) (Map, error) {
 
 			// If we reach here, it means that there are no handwritten contracts for this
 			// function. We need to infer contracts for this function.
-			if funcDecl.Type.Params.NumFields() != 1 ||
+
+			// Trivial getters are pure, which is cheap to check. The other contracts may still be
+			// inferred for them below, which are merged with contract(pure).
+			if fnssa, ok := ssaOfFunc[funcObj]; ok && isTrivialGetter(fnssa) {
+				m[funcObj] = Contracts{{Pure: true}}
+			}
+
+			// Methods returning their receivers (e.g., the `WithX` methods of builders) are
+			// checked first, which is cheap and does not need the dataflow analysis below.
+			if fnssa, ok := ssaOfFunc[funcObj]; ok && returnsReceiver(fnssa) {
+				m[funcObj] = append(m[funcObj], Contract{Ins: []ContractVal{Receiver}, Outs: []ContractVal{Receiver}})
+				continue
+			}
+
+			if (funcDecl.Type.Params.NumFields() != 1 ||
 				funcDecl.Type.Results.NumFields() != 1 ||
 				util.TypeBarsNilness(funcObj.Type().(*types.Signature).Params().At(0).Type()) ||
 				util.TypeBarsNilness(funcObj.Type().(*types.Signature).Results().At(0).Type()) ||
-				funcObj.Type().(*types.Signature).Variadic() {
+				funcObj.Type().(*types.Signature).Variadic()) &&
+				!isNilCheckPredicate(funcObj.Type().(*types.Signature)) {
 				// We definitely want to ignore any function without any parameters or return
 				// values since they cannot have any contracts.
 
 				// TODO: However, we want to analyze for multiple param/return in the future; for
-				//  now we consider contract(nonnil->nonnil) only.
+				//  now we consider contract(nonnil->nonnil) and the contracts of nil-check
+				//  predicates (e.g., contract(nil->true)) only.
 
 				// TODO: If the function has only one parameter and the parameter is variadic, then
 				//  it may happen that no argument is passed when calling the function. Such cases
@@ -231,7 +253,7 @@ func collectFunctionContracts(pass *analysis.Pass) (Map, error) {
 	// Collect inferred contracts from the channel.
 	var err error
 	for r := range funcChan {
-		m[r.funcObj] = r.contracts
+		m[r.funcObj] = append(m[r.funcObj], r.contracts...)
 		err = errors.Join(err, r.err)
 	}
 
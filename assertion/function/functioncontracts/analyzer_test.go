@@ -69,6 +69,10 @@ func TestContractCollection(t *testing.T) {
 		getFuncObj(pass, "f3"): {
 			Contract{Ins: []ContractVal{NonNil}, Outs: []ContractVal{False}},
 		},
+		getFuncObj(pass, "f4"): {
+			Contract{Ins: []ContractVal{Nil}, Outs: []ContractVal{True}},
+			Contract{Ins: []ContractVal{NonNil}, Outs: []ContractVal{False}},
+		},
 		getFuncObj(pass, "multipleValues"): {
 			Contract{Ins: []ContractVal{Any, NonNil}, Outs: []ContractVal{NonNil, True}},
 		},
@@ -76,6 +80,12 @@ func TestContractCollection(t *testing.T) {
 			Contract{Ins: []ContractVal{Any, NonNil}, Outs: []ContractVal{NonNil, True}},
 			Contract{Ins: []ContractVal{NonNil, Any}, Outs: []ContractVal{NonNil, True}},
 		},
+		getMethodObj(pass, "builder", "withReceiver"): {
+			Contract{Ins: []ContractVal{Receiver}, Outs: []ContractVal{Receiver}},
+		},
+		getMethodObj(pass, "builder", "get"): {
+			Contract{Pure: true},
+		},
 		// function contractCommentInOtherLine should not exist in the map as it has no contract.
 	}
 	if diff := cmp.Diff(expected, actual); diff != "" {
@@ -100,6 +110,10 @@ func TestInfer(t *testing.T) {
 
 	actual := make(Map)
 	for funcObj, contracts := range funcContractsMap {
+		// Contracts imported from the upstream (e.g., stdlib) packages are not checked here.
+		if funcObj.Pkg() != pass.Pkg {
+			continue
+		}
 		actual[funcObj] = contracts
 	}
 
@@ -120,10 +134,53 @@ func TestInfer(t *testing.T) {
 			Contract{Ins: []ContractVal{NonNil}, Outs: []ContractVal{NonNil}},
 		},
 		getFuncObj(pass, "unknownToUnknownButSameValue"): {
+			Contract{Pure: true},
+			Contract{Ins: []ContractVal{NonNil}, Outs: []ContractVal{NonNil}},
+		},
+		getFuncObj(pass, "unknownToNil"): {
+			Contract{Pure: true},
+		},
+		getFuncObj(pass, "isNil"): {
+			Contract{Pure: true},
+			Contract{Ins: []ContractVal{Nil}, Outs: []ContractVal{True}},
+			Contract{Ins: []ContractVal{NonNil}, Outs: []ContractVal{False}},
+		},
+		getFuncObj(pass, "isNonNil"): {
+			Contract{Pure: true},
+			Contract{Ins: []ContractVal{Nil}, Outs: []ContractVal{False}},
+			Contract{Ins: []ContractVal{NonNil}, Outs: []ContractVal{True}},
+		},
+		getFuncObj(pass, "isNilBranches"): {
+			Contract{Ins: []ContractVal{Nil}, Outs: []ContractVal{True}},
+			Contract{Ins: []ContractVal{NonNil}, Outs: []ContractVal{False}},
+		},
+		getFuncObj(pass, "isNonNilAndPositive"): {
+			Contract{Ins: []ContractVal{Nil}, Outs: []ContractVal{False}},
+		},
+		getFuncObj(pass, "alwaysTrue"): {
+			Contract{Pure: true},
+		},
+		getFuncObj(pass, "commaOkInterface"): {
 			Contract{Ins: []ContractVal{NonNil}, Outs: []ContractVal{NonNil}},
 		},
-		// other functions should not exist in the map as the contract nonnil->nonnil does not hold
-		// for them.
+		getFuncObj(pass, "commaOkIsJ"): {
+			Contract{Ins: []ContractVal{Nil}, Outs: []ContractVal{False}},
+		},
+		getMethodObj(pass, "builder", "withX"): {
+			Contract{Ins: []ContractVal{Receiver}, Outs: []ContractVal{Receiver}},
+		},
+		getMethodObj(pass, "builder", "withCheckedX"): {
+			Contract{Ins: []ContractVal{Receiver}, Outs: []ContractVal{Receiver}},
+		},
+		// trivial getters are pure
+		getMethodObj(pass, "builder", "build"): {
+			Contract{Pure: true},
+		},
+		getMethodObj(pass, "valueBuilder", "withNothing"): {
+			Contract{Pure: true},
+		},
+		// other functions should not exist in the map as the contract nonnil->nonnil (or the
+		// contracts of nil-check predicates, recv->recv or pure) does not hold for them.
 
 		// TODO: uncomment this when we support field access when inferring contracts.
 		// getFuncObj(pass, "field"): {
@@ -172,6 +229,9 @@ func TestFactImport(t *testing.T) {
 		getFuncObj(pass, "upstream.ExportedInferred"): {
 			Contract{Ins: []ContractVal{NonNil}, Outs: []ContractVal{NonNil}},
 		},
+		getFuncObj(pass, "upstream.ExportedPure"): {
+			Contract{Pure: true},
+		},
 	}
 	if diff := cmp.Diff(expected, actual); diff != "" {
 		require.Fail(t, fmt.Sprintf("inferred contracts mismatch (-want +got):\n%s", diff))
@@ -195,6 +255,11 @@ func getFuncObj(pass *analysis.Pass, name string) *types.Func {
 	panic(fmt.Sprintf("cannot find function %q", name))
 }
 
+func getMethodObj(pass *analysis.Pass, typeName string, name string) *types.Func {
+	obj, _, _ := types.LookupFieldOrMethod(pass.Pkg.Scope().Lookup(typeName).Type(), true, pass.Pkg, name)
+	return obj.(*types.Func)
+}
+
 func TestMain(m *testing.M) {
 	goleak.VerifyTestMain(m)
 }
@@ -20,12 +20,16 @@ type ContractVal string
 const (
 	// NonNil has keyword "nonnil".
 	NonNil ContractVal = "nonnil"
+	// Nil has keyword "nil".
+	Nil ContractVal = "nil"
 	// False has keyword "false".
 	False ContractVal = "false"
 	// True has keyword "true".
 	True ContractVal = "true"
 	// Any has keyword "_".
 	Any ContractVal = "_"
+	// Receiver has keyword "recv", which stands for the receiver of a method.
+	Receiver ContractVal = "recv"
 )
 
 // newContractVal converts a keyword string into the corresponding function ContractVal.
@@ -33,12 +37,16 @@ func newContractVal(keyword string) ContractVal {
 	switch keyword {
 	case "nonnil":
 		return NonNil
+	case "nil":
+		return Nil
 	case "false":
 		return False
 	case "true":
 		return True
 	case "_":
 		return Any
+	case "recv":
+		return Receiver
 	default:
 		// TODO: The ideal way to handle this is to keep track of this contract parsing error and
 		//  move on to the other contracts. But this may also require some refactoring of other
@@ -53,4 +61,44 @@ type Contract struct {
 	Ins []ContractVal
 	// Outs is the list of output contract values, where the index is the index of the return.
 	Outs []ContractVal
+	// Pure is true for the contract `contract(pure)`, which states that the function is
+	// deterministic and free of side effects, such that repeated calls with the same arguments
+	// return the same values. Ins and Outs are empty for such a contract.
+	Pure bool
+}
+
+// IsNonNilToNonNil returns whether the contract is nonnil->nonnil.
+func (c Contract) IsNonNilToNonNil() bool {
+	return len(c.Ins) == 1 && c.Ins[0] == NonNil && len(c.Outs) == 1 && c.Outs[0] == NonNil
+}
+
+// IsPure returns whether the contract is contract(pure), i.e., the function is deterministic and
+// free of side effects (e.g., a trivial getter), such that repeated calls with the same arguments
+// can be assumed to return the same value.
+func (c Contract) IsPure() bool {
+	return c.Pure
+}
+
+// IsReceiverToResult returns whether the contract is recv->recv, i.e., the method always returns
+// its receiver (e.g., the `WithX` methods of builders), such that the result of a call has the
+// same nilability as the receiver at the call site.
+func (c Contract) IsReceiverToResult() bool {
+	return len(c.Ins) == 1 && c.Ins[0] == Receiver && len(c.Outs) == 1 && c.Outs[0] == Receiver
+}
+
+// NilCheck returns the parameter nilness and the boolean result stated by the contract if it is
+// the contract of a nil-check predicate, i.e., a function taking a single nilable parameter and
+// returning a single bool (e.g., `contract(nil -> true)` for `func isNil(x *T) bool`). The last
+// returned value is false if the contract is not of this form.
+func (c Contract) NilCheck() (paramIsNil bool, result bool, ok bool) {
+	if len(c.Ins) != 1 || (c.Ins[0] != Nil && c.Ins[0] != NonNil) || len(c.Outs) != 1 {
+		return false, false, false
+	}
+	switch c.Outs[0] {
+	case True:
+		return c.Ins[0] == Nil, true, true
+	case False:
+		return c.Ins[0] == Nil, false, true
+	}
+	return false, false, false
 }
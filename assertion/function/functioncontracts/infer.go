@@ -15,6 +15,7 @@
 package functioncontracts
 
 import (
+	"go/constant"
 	"go/token"
 	"go/types"
 
@@ -31,14 +32,29 @@ const _maxNumTablesPerBlock = 1024
 // returns a list of inferred contracts, which may be empty if no contract is inferred but is never
 // nil.
 func inferContracts(fn *ssa.Function) Contracts {
+	derive := deriveContracts
+	if isNilCheckPredicate(fn.Signature) {
+		derive = deriveNilCheckContracts
+	}
+
 	nilnessTableSetByBB := make(map[*ssa.BasicBlock]nilnessTableSet)
 	retInstrs := getReturnInstrs(fn) // TODO: Consider *ssa.Panic
 	// No need of an expensive dataflow analysis if we can derive contracts from the return
 	// instructions directly.
-	if ctrs := deriveContracts(retInstrs, fn, nilnessTableSetByBB); len(ctrs) != 0 {
+	if ctrs := derive(retInstrs, fn, nilnessTableSetByBB); len(ctrs) != 0 {
 		return ctrs
 	}
 
+	if !propagateNilness(fn, nilnessTableSetByBB) {
+		return nil
+	}
+	return derive(retInstrs, fn, nilnessTableSetByBB)
+}
+
+// propagateNilness runs the dataflow analysis over the blocks of the function and stores the
+// learned nilnessTables of each block in nilnessTableSetByBB. It returns false if the analysis is
+// given up due to too many nilnessTables.
+func propagateNilness(fn *ssa.Function, nilnessTableSetByBB map[*ssa.BasicBlock]nilnessTableSet) bool {
 	// Add the entry block to the queue.
 	// TODO: visit fn.Recover.
 	var queue []*ssa.BasicBlock
@@ -140,14 +156,14 @@ func inferContracts(fn *ssa.Function) Contracts {
 		// TODO: nicely handle exponential explosion of tables.
 		if len(nilnessTableSetByBB[b]) >= _maxNumTablesPerBlock {
 			// Too many tables, we should give up inferring contracts for this function.
-			return nil
+			return false
 		}
 
 		// Add successors to queue since the nilness table set of this block has been updated.
 		queue = append(queue, b.Succs...)
 	}
 
-	return deriveContracts(retInstrs, fn, nilnessTableSetByBB)
+	return true
 }
 
 // learnNilness learns nilness for the block succ, extended from one nilnessTable table of its
@@ -158,6 +174,10 @@ func inferContracts(fn *ssa.Function) Contracts {
 // not learn nilness for al kinds of conditions. For now we support only what is supported in
 // the function branch.
 func learnNilness(succ *ssa.BasicBlock, pred *ssa.BasicBlock, table nilnessTable) (nilnessTable, bool) {
+	if typeAssert, okSucc := commaOkBranch(pred); typeAssert != nil {
+		return learnCommaOkNilness(typeAssert, succ == okSucc, table)
+	}
+
 	lTable := nilnessTable{} // learned nilnessTable
 	eqSucc, neSucc, binOp := branch(pred)
 	// TODO: for now we learn nilness from only these two condition: ? == ? and ? != ?
@@ -199,6 +219,34 @@ func learnNilness(succ *ssa.BasicBlock, pred *ssa.BasicBlock, table nilnessTable
 	return lTable, true
 }
 
+// learnCommaOkNilness learns nilness for a successor of a block branching on the `ok` of a
+// comma-ok type assertion `y, ok := x.(T)`, extended from one nilnessTable table of the block.
+// Similar to learnNilness, it returns **only newly learned** nilness and a bool flag that is
+// false if the successor is not reachable under the table.
+//
+// If the assertion succeeds, x must be nonnil, and so is y if T is an interface type (y may still
+// be nil if T is, e.g., a pointer type, since x may hold a nil pointer of type T). Otherwise, y is
+// the zero value of T, i.e., nil if T can have nil as a valid value.
+func learnCommaOkNilness(typeAssert *ssa.TypeAssert, ok bool, table nilnessTable) (nilnessTable, bool) {
+	lTable := nilnessTable{}
+	y := commaOkValue(typeAssert)
+	if ok {
+		if table.nilnessOf(typeAssert.X) == isnil {
+			// A nil interface never passes a type assertion.
+			return lTable, false
+		}
+		lTable.expandNilness(typeAssert.X, isnonnil)
+		if y != nil && types.IsInterface(typeAssert.AssertedType) {
+			lTable.expandNilness(y, isnonnil)
+		}
+		return lTable, true
+	}
+	if y != nil && !util.TypeBarsNilness(typeAssert.AssertedType) {
+		lTable.expandNilness(y, isnil)
+	}
+	return lTable, true
+}
+
 // deriveContracts checks nilness of parameter and return values at every exit block to infer
 // contracts.
 func deriveContracts(
@@ -287,6 +335,168 @@ func deriveContracts(
 	}
 }
 
+// isNilCheckPredicate returns if the function signature is of a nil-check predicate, i.e., it
+// takes a single nilable parameter and returns a single bool, e.g., `func isNil(x *T) bool`.
+func isNilCheckPredicate(sig *types.Signature) bool {
+	if sig.Params().Len() != 1 || sig.Results().Len() != 1 || sig.Variadic() ||
+		util.TypeBarsNilness(sig.Params().At(0).Type()) {
+		return false
+	}
+	basic, ok := sig.Results().At(0).Type().Underlying().(*types.Basic)
+	return ok && basic.Kind() == types.Bool
+}
+
+// deriveNilCheckContracts checks the boolean return values of a nil-check predicate at every exit
+// block under the nilness of the parameter to infer contracts `nil -> true|false` and
+// `nonnil -> true|false`. For example, contracts `nil -> true` and `nonnil -> false` are inferred
+// for `func isNil(x *T) bool { return x == nil }`.
+func deriveNilCheckContracts(
+	retInstrs []*ssa.Return,
+	fn *ssa.Function,
+	nilnessTableSetByBB map[*ssa.BasicBlock]nilnessTableSet,
+) Contracts {
+	// For methods the receiver is the first parameter in ssa, so we take the last one here.
+	param := fn.Params[len(fn.Params)-1]
+
+	// results maps the nilness of the parameter (isnil or isnonnil) to the set of boolean values
+	// that may be returned under it. unknownResult records if the returned value is not known.
+	results := map[nilness]map[bool]bool{isnil: {}, isnonnil: {}}
+	unknownResult := map[nilness]bool{}
+	for _, retInstr := range retInstrs {
+		for _, choice := range returnChoices(retInstr, nilnessTableSetByBB) {
+			// Evaluate the returned value under both choices of the parameter nilness, unless the
+			// nilness of the parameter is already known on this path.
+			for _, pNil := range []nilness{isnil, isnonnil} {
+				if n := choice.table.nilnessOf(param); n != unknown && n != pNil {
+					continue
+				}
+				t := choice.table.copy()
+				t.expandNilness(param, pNil)
+				v, known := t.boolOf(choice.value)
+				if !known {
+					unknownResult[pNil] = true
+					continue
+				}
+				results[pNil][v] = true
+			}
+		}
+	}
+
+	var contracts Contracts
+	for _, pNil := range []nilness{isnil, isnonnil} {
+		if unknownResult[pNil] || len(results[pNil]) != 1 {
+			continue
+		}
+		v := results[pNil][true]
+		// We do not infer contracts for the functions that return the same value regardless of the
+		// nilness of the parameter, since they are not nil checks.
+		if len(results[pNil.negate()]) == 1 && !unknownResult[pNil.negate()] &&
+			results[pNil.negate()][v] {
+			return nil
+		}
+		in, out := NonNil, False
+		if pNil == isnil {
+			in = Nil
+		}
+		if v {
+			out = True
+		}
+		contracts = append(contracts, Contract{Ins: []ContractVal{in}, Outs: []ContractVal{out}})
+	}
+	return contracts
+}
+
+// returnChoice is a nilnessTable that holds at a return instruction together with the value
+// returned under it.
+type returnChoice struct {
+	table nilnessTable
+	value ssa.Value
+}
+
+// returnChoices returns the choices of nilnessTables and returned values at the given return
+// instruction. If the returned value is a phi in the same block, the choices are instead collected
+// from the predecessors (with the nilness learned from the branches), since the returned value
+// depends on the incoming edge.
+func returnChoices(retInstr *ssa.Return, nilnessTableSetByBB map[*ssa.BasicBlock]nilnessTableSet) []returnChoice {
+	tablesOf := func(b *ssa.BasicBlock) nilnessTableSet {
+		if r, ok := nilnessTableSetByBB[b]; ok && len(r) != 0 {
+			return r
+		}
+		return nilnessTableSet{nilnessTable{}}
+	}
+
+	b, ret := retInstr.Block(), retInstr.Results[0]
+	var choices []returnChoice
+	phi, ok := ret.(*ssa.Phi)
+	if !ok || phi.Block() != b {
+		for _, table := range tablesOf(b) {
+			choices = append(choices, returnChoice{table: table, value: ret})
+		}
+		return choices
+	}
+	for i, pred := range b.Preds {
+		for _, table := range tablesOf(pred) {
+			lTable, ok := learnNilness(b, pred, table)
+			if !ok {
+				// The edge is not reachable under this table.
+				continue
+			}
+			nTable := table.copy()
+			nTable.addAll(lTable)
+			choices = append(choices, returnChoice{table: nTable, value: phi.Edges[i]})
+		}
+	}
+	return choices
+}
+
+// returnsReceiver returns whether the given function is a method with a single nilable result of
+// its receiver type, and returns its receiver (i.e., contract(recv -> recv)) at every return.
+func returnsReceiver(fn *ssa.Function) bool {
+	recv := fn.Signature.Recv()
+	if recv == nil || fn.Signature.Results().Len() != 1 || len(fn.Params) == 0 || len(fn.Blocks) == 0 ||
+		util.TypeBarsNilness(recv.Type()) ||
+		!types.Identical(recv.Type(), fn.Signature.Results().At(0).Type()) {
+		return false
+	}
+	retInstrs := getReturnInstrs(fn)
+	if len(retInstrs) == 0 {
+		return false
+	}
+	for _, ret := range retInstrs {
+		// For methods, the receiver is the first parameter in SSA.
+		if ret.Results[0] != fn.Params[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// isTrivialGetter returns whether the given function is a trivial getter, i.e., it has results and
+// consists of a single block of side-effect-free reads (e.g., field reads, map lookups and
+// arithmetic on the parameters and globals) followed by a return, such as
+// `func (s *S) Get(k string) *T { return s.m[k] }`. Such a function is pure (i.e., contract(pure)):
+// repeated calls with the same arguments return the same values, as long as the values read are
+// not modified in between.
+func isTrivialGetter(fn *ssa.Function) bool {
+	if fn.Signature.Results().Len() == 0 || len(fn.Blocks) != 1 {
+		return false
+	}
+	for _, instr := range fn.Blocks[0].Instrs {
+		switch instr := instr.(type) {
+		case *ssa.Field, *ssa.FieldAddr, *ssa.Index, *ssa.IndexAddr, *ssa.Lookup, *ssa.BinOp,
+			*ssa.ChangeType, *ssa.Extract, *ssa.Return, *ssa.DebugRef:
+		case *ssa.UnOp:
+			// receiving from a channel is not side-effect-free
+			if instr.Op == token.ARROW {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 func getReturnInstrs(fn *ssa.Function) []*ssa.Return {
 	returnInstrs := make([]*ssa.Return, 0)
 	for _, b := range fn.Blocks {
@@ -329,6 +539,40 @@ func branch(b *ssa.BasicBlock) (*ssa.BasicBlock, *ssa.BasicBlock, *ssa.BinOp) {
 	return nil, nil, nil
 }
 
+// commaOkBranch reports whether the block b ends with a branch on the `ok` of a comma-ok type
+// assertion `y, ok := x.(T)`. If so, it returns the type assertion and the successor where the
+// assertion succeeds, otherwise it returns all nil.
+func commaOkBranch(b *ssa.BasicBlock) (*ssa.TypeAssert, *ssa.BasicBlock) {
+	ifInstr, ok := b.Instrs[len(b.Instrs)-1].(*ssa.If)
+	if !ok {
+		return nil, nil
+	}
+	extract, ok := ifInstr.Cond.(*ssa.Extract)
+	if !ok || extract.Index != 1 {
+		return nil, nil
+	}
+	typeAssert, ok := extract.Tuple.(*ssa.TypeAssert)
+	if !ok || !typeAssert.CommaOk {
+		return nil, nil
+	}
+	return typeAssert, b.Succs[0]
+}
+
+// commaOkValue returns the asserted value y of a comma-ok type assertion `y, ok := x.(T)`, or
+// nil if the value is never extracted (e.g., `_, ok := x.(T)`).
+func commaOkValue(typeAssert *ssa.TypeAssert) ssa.Value {
+	refs := typeAssert.Referrers()
+	if refs == nil {
+		return nil
+	}
+	for _, ref := range *refs {
+		if extract, ok := ref.(*ssa.Extract); ok && extract.Index == 0 {
+			return extract
+		}
+	}
+	return nil
+}
+
 // isBuiltinAppendCall reports if the call is a call to builtin append.
 func isBuiltinAppendCall(v *ssa.Call) bool {
 	// TODO: consider merge this and assertion.BuiltinAppend
@@ -410,6 +654,14 @@ func (t nilnessTable) nilnessOf(v ssa.Value) nilness {
 		}
 		// append(s) depends on the nilability of s.
 		return t.nilnessOf(v.Call.Args[0])
+	case *ssa.Extract:
+		// The asserted value y of a comma-ok type assertion `y, ok := x.(T)` is the zero value of T
+		// if x is nil, since the assertion always fails then.
+		typeAssert, ok := v.Tuple.(*ssa.TypeAssert)
+		if ok && typeAssert.CommaOk && v.Index == 0 && !util.TypeBarsNilness(typeAssert.AssertedType) &&
+			t.nilnessOf(typeAssert.X) == isnil {
+			return isnil
+		}
 	}
 
 	// Is value intrinsically nil or non-nil?
@@ -442,6 +694,40 @@ func (t nilnessTable) nilnessOf(v ssa.Value) nilness {
 	return unknown
 }
 
+// boolOf reports the boolean value of v given the nilness table, and whether the value can be
+// determined at all. For now we determine only constants, nil comparisons (and their negations),
+// and the `ok` of comma-ok type assertions on nil values.
+func (t nilnessTable) boolOf(v ssa.Value) (bool, bool) {
+	switch v := v.(type) {
+	case *ssa.Const:
+		if v.Value != nil && v.Value.Kind() == constant.Bool {
+			return constant.BoolVal(v.Value), true
+		}
+	case *ssa.UnOp:
+		if v.Op == token.NOT {
+			b, ok := t.boolOf(v.X)
+			return !b, ok
+		}
+	case *ssa.BinOp:
+		if (v.Op != token.EQL && v.Op != token.NEQ) || util.TypeBarsNilness(v.X.Type()) {
+			break
+		}
+		xnil, ynil := t.nilnessOf(v.X), t.nilnessOf(v.Y)
+		// Two nonnil values may or may not be equal.
+		if xnil == unknown || ynil == unknown || (xnil == isnonnil && ynil == isnonnil) {
+			break
+		}
+		return (xnil == ynil) == (v.Op == token.EQL), true
+	case *ssa.Extract:
+		// The `ok` of a comma-ok type assertion `y, ok := x.(T)` is false if x is nil.
+		typeAssert, ok := v.Tuple.(*ssa.TypeAssert)
+		if ok && typeAssert.CommaOk && v.Index == 1 && t.nilnessOf(typeAssert.X) == isnil {
+			return false, true
+		}
+	}
+	return false, false
+}
+
 // expandNilness takes a single known nilness and learn the set of nilness that can be known
 // about it or any of its related values. Some operations, like ChangeInterface, have transitive
 // nilness, such that if you know the underlying value is nil, you also know the value itself is
@@ -23,7 +23,7 @@ import (
 
 const _sep = ","
 const _contractKeyword = "contract"
-const _contractValKeyword = NonNil + "|" + False + "|" + True + "|" + Any
+const _contractValKeyword = NonNil + "|" + Nil + "|" + False + "|" + True + "|" + Any + "|" + Receiver
 
 // _contractRE matches multiple function contracts in the same line. Each contract looks like
 // `contract(VALUE(,VALUE)+ -> VALUE(,VALUE)+)`. The RE also captures two lists of VALUEs,
@@ -35,6 +35,10 @@ var _contractRE = regexp.MustCompile(
 	fmt.Sprintf("^\\s*//\\s*(?:\\s*%s\\s*\\(\\s*((?:%s)(?:\\s*,\\s*(?:%s))*)\\s*->\\s*((?:%s)(?:\\s*,\\s*(?:%s))*)\\s*\\)\\s*)+$",
 		_contractKeyword, _contractValKeyword, _contractValKeyword, _contractValKeyword, _contractValKeyword))
 
+// _pureContractRE matches the contract `contract(pure)` written in its own line, which states that
+// the function is deterministic and free of side effects.
+var _pureContractRE = regexp.MustCompile(fmt.Sprintf("^\\s*//\\s*%s\\s*\\(\\s*pure\\s*\\)\\s*$", _contractKeyword))
+
 // parseContracts parses a slice of function contracts from a singe comment group. If no contract
 // is found from the comment group, an empty slice is returned.
 func parseContracts(doc *ast.CommentGroup) Contracts {
@@ -44,6 +48,10 @@ func parseContracts(doc *ast.CommentGroup) Contracts {
 
 	var contracts Contracts
 	for _, lineComment := range doc.List {
+		if _pureContractRE.MatchString(lineComment.Text) {
+			contracts = append(contracts, Contract{Pure: true})
+			continue
+		}
 		for _, matching := range _contractRE.FindAllStringSubmatch(lineComment.Text, -1) {
 			// matching is a slice of three elements; the first is the whole matched string and the
 			// next two are the captured groups of contract values before and after `->`.
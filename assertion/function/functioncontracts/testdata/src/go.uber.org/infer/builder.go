@@ -0,0 +1,67 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infer
+
+type builder struct {
+	x *int
+}
+
+// withX always returns its receiver, hence contract(recv -> recv) is inferred.
+func (b *builder) withX(x *int) *builder {
+	b.x = x
+	return b
+}
+
+// withCheckedX returns its receiver at every return, hence contract(recv -> recv) is inferred.
+func (b *builder) withCheckedX(x *int) *builder {
+	if x == nil {
+		return b
+	}
+	b.x = x
+	return b
+}
+
+// withNilX may return nil instead of its receiver.
+func (b *builder) withNilX(x *int) *builder {
+	if x == nil {
+		return nil
+	}
+	b.x = x
+	return b
+}
+
+// withCopy returns a copy of its receiver.
+func (b *builder) withCopy() *builder {
+	c := *b
+	return &c
+}
+
+// withReassigned returns a different builder than its receiver.
+func (b *builder) withReassigned() *builder {
+	b = &builder{}
+	return b
+}
+
+// build does not return its receiver type.
+func (b *builder) build() *int {
+	return b.x
+}
+
+type valueBuilder struct{}
+
+// withNothing returns its receiver, but the receiver is a struct value that can never be nil.
+func (v valueBuilder) withNothing() valueBuilder {
+	return v
+}
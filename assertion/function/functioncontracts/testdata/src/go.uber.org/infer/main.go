@@ -325,3 +325,71 @@ func twoCondsMerge(x *STR) *STR {
 func unknownToUnknownButSameValue(x *int) *int {
 	return x
 }
+
+// contract(nil -> true) and contract(nonnil -> false) hold for nil-check predicates.
+func isNil(x *int) bool {
+	return x == nil
+}
+
+// contract(nil -> false) and contract(nonnil -> true) hold.
+func isNonNil(x *int) bool {
+	return !(x == nil)
+}
+
+// contract(nil -> true) and contract(nonnil -> false) hold even if the results are constants
+// returned from different branches.
+func isNilBranches(x *STR) bool {
+	if x != nil {
+		return false
+	}
+	return true
+}
+
+// Only contract(nil -> false) holds here since the result is unknown when x is nonnil.
+func isNonNilAndPositive(x *int) bool {
+	return x != nil && *x > 0
+}
+
+// No contract holds since the result does not depend on the nilness of x.
+func alwaysTrue(x *int) bool {
+	_ = x
+	return true
+}
+
+type J interface {
+	I
+	n()
+}
+
+// contract(nonnil -> nonnil) holds since the asserted value of a successful comma-ok type
+// assertion to an interface type is nonnil.
+func commaOkInterface(x I) I {
+	if j, ok := x.(J); ok {
+		return j
+	}
+	return x
+}
+
+// contract(nonnil -> nonnil) does not hold since x may hold a nil *SI.
+func commaOkPointer(x I) *SI {
+	if si, ok := x.(*SI); ok {
+		return si
+	}
+	return new(SI)
+}
+
+// contract(nonnil -> nonnil) does not hold since the asserted value is nil if the assertion
+// fails.
+func commaOkFailed(x I) I {
+	j, ok := x.(J)
+	if !ok {
+		return j
+	}
+	return x
+}
+
+// Only contract(nil -> false) holds here since the comma-ok type assertion always fails for nil.
+func commaOkIsJ(x I) bool {
+	_, ok := x.(J)
+	return ok
+}
@@ -38,6 +38,12 @@ func f3(x *int) bool {
 	return false
 }
 
+// contract(nil -> true)
+// contract(nonnil -> false)
+func f4(x *int) bool {
+	return x == nil
+}
+
 // contract(_, nonnil -> nonnil, true)
 func multipleValues(key string, deft *int) (*int, bool) {
 	m := map[string]*int{}
@@ -64,3 +70,18 @@ func multipleContracts(x *int, y *int) (*int, bool) {
 // function has no param or return. Only a contract in its own line should be parsed, not even `//
 // contract(nonnil -> nonnil)`.
 func contractCommentInOtherLine() {}
+
+type builder struct{}
+
+// contract(recv -> recv)
+func (b *builder) withReceiver(x *int) *builder {
+	return b
+}
+
+// contract(pure)
+func (b *builder) get(m map[string]*int, k string) *int {
+	if b == nil {
+		return nil
+	}
+	return m[k]
+}
@@ -17,10 +17,14 @@ package preprocess
 import (
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/token"
+	"go/types"
+	"slices"
 
 	"go.uber.org/nilaway/hook"
 	"go.uber.org/nilaway/util"
+	"golang.org/x/tools/go/ast/astutil"
 	"golang.org/x/tools/go/cfg"
 )
 
@@ -42,6 +46,20 @@ import (
 // Canonicalize explicit boolean comparisons:
 // - replace `if x == true {T} {F}` with `if x {T} {F}`
 // - replace `if x == false {T} {F}` with `if !x {T} {F}`
+//
+// Canonicalize switch statements:
+// - replace the case `y` of `switch x { case y: }` with `x == y`, such that nil comparisons in
+// switch cases (e.g., `switch x { case nil: }` or `switch true { case x != nil: }`) are
+// canonicalized like the conditionals above
+//
+// Canonicalize type switch statements:
+// - replace the test of the case `nil` of `switch v := x.(type) { case nil: }` with `x == nil`,
+// such that the other cases (including the default case) know that `x` (and hence `v`) is nonnil
+//
+// Prune constant conditionals:
+// - replace `if cond {T} {F}` with `{T}` (or `{F}`) if `cond` is a compile-time constant (e.g., a
+// boolean constant `debug`, or `runtime.GOOS == "windows"`), such that the dead branches do not
+// create spurious nil flows
 func (p *Preprocessor) CFG(graph *cfg.CFG, funcDecl *ast.FuncDecl) *cfg.CFG {
 	// The ASTs and CFGs are shared across all analyzers in the nogo framework, so we should never
 	// modify them directly. Here, we make a copy of the graph (and all blocks in it) and modify
@@ -55,6 +73,19 @@ func (p *Preprocessor) CFG(graph *cfg.CFG, funcDecl *ast.FuncDecl) *cfg.CFG {
 	failureBlock := &cfg.Block{Index: int32(len(graph.Blocks))}
 	graph.Blocks = append(graph.Blocks, failureBlock)
 
+	// Next, we need to re-insert information that is lost during CFG build for *ast.RangeStmt
+	// and *ast.SwitchStmt by iterating through all blocks. This requires knowing the links between
+	// the nodes contained within a block to their parents (*ast.RangeStmt or *ast.SwitchStmt nodes).
+	// So, here establish the link first.
+	rangeChildren, switchChildren := collectChildren(funcDecl)
+
+	// The case comparisons of switch statements (e.g., `x == nil` for `switch x { case nil: }`)
+	// must be re-inserted before any other transformations, since markSwitchStatements expects the
+	// original CFG structure of switch statements, and the inserted comparisons should be subject
+	// to the canonicalization and hooks just like the conditionals of if statements.
+	markSwitchStatements(graph, switchChildren)
+	p.markTypeSwitchStatements(graph, funcDecl)
+
 	// Perform a series of CFG transformations here (for hooks and canonicalization). The order of
 	// these transformations matters due to canonicalization. Some transformations may expect the
 	// CFG to be in canonical form, and some transformations may change the CFG structure in a way
@@ -68,6 +99,11 @@ func (p *Preprocessor) CFG(graph *cfg.CFG, funcDecl *ast.FuncDecl) *cfg.CFG {
 			p.splitBlockOnTrustedFuncs(graph, block, failureBlock)
 		}
 	}
+	for _, block := range graph.Blocks {
+		if block.Live {
+			p.terminateBlockOnNoReturnFuncs(block)
+		}
+	}
 	for _, block := range graph.Blocks {
 		if block.Live {
 			p.canonicalizeConditional(graph, block)
@@ -82,13 +118,19 @@ func (p *Preprocessor) CFG(graph *cfg.CFG, funcDecl *ast.FuncDecl) *cfg.CFG {
 		}
 	}
 
-	// Next, we need to re-insert information that is lost during CFG build for *ast.RangeStmt
-	// and *ast.SwitchStmt by iterating through all blocks. This requires knowing the links between
-	// the nodes contained within a block to their parents (*ast.RangeStmt or *ast.SwitchStmt nodes).
-	// So, here establish the link and then do the work.
-	rangeChildren, switchChildren := collectChildren(funcDecl)
+	// Pruning constant conditionals requires the CFG to be in canonical form, such that the
+	// constant operands of `&&` and `||` are checked in their own blocks.
+	pruned := false
+	for _, block := range graph.Blocks {
+		if block.Live && p.pruneConstantConditional(block) {
+			pruned = true
+		}
+	}
+	if pruned {
+		markUnreachableBlocks(graph)
+	}
+
 	markRangeStatements(graph, rangeChildren)
-	markSwitchStatements(graph, switchChildren)
 
 	return graph
 }
@@ -172,6 +214,25 @@ func (p *Preprocessor) splitBlockOnTrustedFuncs(graph *cfg.CFG, thisBlock, failu
 	}
 }
 
+// terminateBlockOnNoReturnFuncs truncates the CFG block right after a call that never returns
+// according to the hook framework (e.g., "tb.Fatal()" on a testing.TB interface), and removes its
+// successors, just like the CFG of a call to `panic`.
+func (p *Preprocessor) terminateBlockOnNoReturnFuncs(block *cfg.Block) {
+	for i, node := range block.Nodes {
+		expr, ok := node.(*ast.ExprStmt)
+		if !ok {
+			continue
+		}
+		call, ok := expr.X.(*ast.CallExpr)
+		if !ok || !hook.NoReturn(p.pass, call) {
+			continue
+		}
+		block.Nodes = block.Nodes[:i+1]
+		block.Succs = nil
+		return
+	}
+}
+
 // replaceConditional calls the hook functions and replaces the conditional expressions in the CFG
 // with the returned equivalent expression for analysis.
 //
@@ -185,11 +246,13 @@ func (p *Preprocessor) replaceConditional(graph *cfg.CFG, block *cfg.Block) {
 	if len(block.Nodes) == 0 || len(block.Succs) != 2 {
 		return
 	}
-	call, ok := block.Nodes[len(block.Nodes)-1].(*ast.CallExpr)
-	if !ok {
-		return
+	var replaced ast.Expr
+	switch cond := block.Nodes[len(block.Nodes)-1].(type) {
+	case *ast.CallExpr:
+		replaced = p.replaceConditionalCall(cond)
+	case *ast.Ident:
+		replaced = p.replaceStoredConditional(block, cond)
 	}
-	replaced := hook.ReplaceConditional(p.pass, call)
 	if replaced == nil {
 		return
 	}
@@ -200,6 +263,168 @@ func (p *Preprocessor) replaceConditional(graph *cfg.CFG, block *cfg.Block) {
 	p.canonicalizeConditional(graph, block)
 }
 
+// pruneConstantConditional removes the dead successor of the branching block if its conditional
+// is a compile-time constant, and returns true if it does so. The conditional itself is removed
+// from the block as well, since the constant expressions have no side effects. The conditionals
+// synthesized by the other transformations (which do not have type information) are kept as is.
+func (p *Preprocessor) pruneConstantConditional(block *cfg.Block) bool {
+	if len(block.Nodes) == 0 || len(block.Succs) != 2 {
+		return false
+	}
+	cond, ok := block.Nodes[len(block.Nodes)-1].(ast.Expr)
+	if !ok {
+		return false
+	}
+	tv, ok := p.pass.TypesInfo.Types[cond]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.Bool {
+		return false
+	}
+
+	block.Nodes = block.Nodes[:len(block.Nodes)-1]
+	if constant.BoolVal(tv.Value) {
+		block.Succs = []*cfg.Block{block.Succs[0]}
+	} else {
+		block.Succs = []*cfg.Block{block.Succs[1]}
+	}
+	return true
+}
+
+// markUnreachableBlocks marks the blocks that are no longer reachable from the entry block (e.g.,
+// the dead branches removed by pruneConstantConditional) as not live, such that they are skipped
+// by the analysis just like the unreachable code in the original CFG.
+func markUnreachableBlocks(graph *cfg.CFG) {
+	reachable := make([]bool, len(graph.Blocks))
+	stack := []*cfg.Block{graph.Blocks[0]}
+	reachable[graph.Blocks[0].Index] = true
+	for len(stack) > 0 {
+		block := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, succ := range block.Succs {
+			if !reachable[succ.Index] {
+				reachable[succ.Index] = true
+				stack = append(stack, succ)
+			}
+		}
+	}
+	for _, block := range graph.Blocks {
+		if !reachable[block.Index] {
+			block.Live = false
+		}
+	}
+}
+
+// replaceConditionalCall returns the equivalent expression of a conditional call to a trusted
+// function from the hook framework or to a nil-check predicate (see replaceNilCheckCall). It
+// returns nil if the call is neither.
+func (p *Preprocessor) replaceConditionalCall(call *ast.CallExpr) ast.Expr {
+	if replaced := hook.ReplaceConditional(p.pass, call); replaced != nil {
+		return replaced
+	}
+	return p.replaceNilCheckCall(call)
+}
+
+// replaceStoredConditional returns the equivalent expression of a conditional on a boolean
+// variable that stores the result of a conditional call (see replaceConditionalCall) right before
+// the check, e.g., `ok := errors.As(err, &target); if ok { ... }` is handled as if the call were
+// checked directly, i.e., `ok && target != nil`. It returns nil if the variable is not assigned
+// such a call by the preceding node in the block.
+func (p *Preprocessor) replaceStoredConditional(block *cfg.Block, cond *ast.Ident) ast.Expr {
+	if len(block.Nodes) < 2 {
+		return nil
+	}
+	assign, ok := block.Nodes[len(block.Nodes)-2].(*ast.AssignStmt)
+	if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return nil
+	}
+	lhs, ok := assign.Lhs[0].(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	if obj := p.pass.TypesInfo.ObjectOf(cond); obj == nil || obj != p.pass.TypesInfo.ObjectOf(lhs) {
+		return nil
+	}
+	call, ok := ast.Unparen(assign.Rhs[0]).(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	replaced := p.replaceConditionalCall(call)
+	if replaced == nil {
+		return nil
+	}
+	// The replaced expression is freshly created except for the call and its arguments, so the
+	// occurrences of the call can be replaced with the variable without modifying the original AST.
+	return astutil.Apply(replaced, func(c *astutil.Cursor) bool {
+		if c.Node() == ast.Node(call) {
+			c.Replace(cond)
+			return false
+		}
+		return true
+	}, nil).(ast.Expr)
+}
+
+// replaceNilCheckCall replaces a call to a nil-check predicate, i.e., a function with contracts
+// such as `contract(nil -> true)` (see functioncontracts.Contract.NilCheck), with an equivalent
+// expression that also performs the nil check implied by each of its contracts. For example, a
+// call `isNil(x)` with contracts `nil -> true` and `nonnil -> false` is replaced with
+// `(isNil(x) || x == nil) && x == nil`, such that `x` is known to be nonnil if the call returns
+// false and nil otherwise. Similar to the hooks, the call itself is kept since its argument may
+// have nilness issues. It returns nil if the call is not to a nil-check predicate.
+func (p *Preprocessor) replaceNilCheckCall(call *ast.CallExpr) ast.Expr {
+	// We only replace calls whose argument can be evaluated again without side effects.
+	if len(call.Args) != 1 || !util.IsFieldSelectorChain(call.Args[0]) {
+		return nil
+	}
+	ident := util.FuncIdentFromCallExpr(call)
+	if ident == nil {
+		return nil
+	}
+	funcObj, ok := p.pass.TypesInfo.ObjectOf(ident).(*types.Func)
+	if !ok {
+		return nil
+	}
+	sig := funcObj.Type().(*types.Signature)
+	if sig.Params().Len() != 1 {
+		return nil
+	}
+	// A nil concrete value is not nil anymore once converted to an interface parameter, so the
+	// contracts tell nothing about such an argument.
+	if types.IsInterface(sig.Params().At(0).Type()) && !types.IsInterface(p.pass.TypesInfo.TypeOf(call.Args[0])) {
+		return nil
+	}
+
+	var replaced ast.Expr = call
+	for _, ctr := range p.funcContracts[funcObj] {
+		paramIsNil, result, ok := ctr.NilCheck()
+		if !ok {
+			continue
+		}
+		// Contract `nil -> true` means `x` must be nonnil if the call returns false, i.e.,
+		// `call` is equivalent to `call || x == nil`. The other contracts are handled similarly.
+		op, cmp := token.LAND, token.NEQ
+		if result {
+			op = token.LOR
+		}
+		if paramIsNil == result {
+			cmp = token.EQL
+		}
+		replaced = &ast.BinaryExpr{
+			X:     replaced,
+			OpPos: call.Pos(),
+			Op:    op,
+			Y: &ast.BinaryExpr{
+				X:     call.Args[0],
+				OpPos: call.Args[0].Pos(),
+				Op:    cmp,
+				Y:     &ast.Ident{NamePos: call.Args[0].Pos(), Name: "nil"},
+			},
+		}
+	}
+	if replaced == ast.Expr(call) {
+		return nil
+	}
+	return replaced
+}
+
 // canonicalizeConditional canonicalizes the conditional CFG structures to make it easier to reason
 // about control flows later. For example, it rewrites
 // `if !cond {T} {F}` to `if cond {F} {T}` (swap successors), and rewrites
@@ -311,7 +536,8 @@ func (p *Preprocessor) canonicalizeConditional(graph *cfg.CFG, thisBlock *cfg.Bl
 			// For explicit boolean NEQ checks, we replace the AST nodes for `ok != true` and `ok != false`
 			// (also, `true != ok` and `false != ok`) with `ok` and `!ok` form for the true and false cases, respectively.
 			if util.IsLiteral(y, "false") {
-				replaceCond(x) // replaces `ok != false` with `ok`
+				replaceCond(x)                              // replaces `ok != false` with `ok`
+				p.canonicalizeConditional(graph, thisBlock) // recur since `ok` can be a conditional itself, e.g., `x != nil`
 			} else if util.IsLiteral(y, "true") {
 				newCond := &ast.UnaryExpr{
 					OpPos: y.Pos(),
@@ -326,7 +552,8 @@ func (p *Preprocessor) canonicalizeConditional(graph *cfg.CFG, thisBlock *cfg.Bl
 			// For explicit boolean EQL checks, we replace the AST nodes for `ok == true` and `ok == false`
 			// (also, `true == ok` and `false == ok`) with `ok` and `!ok` form for the true and false cases, respectively.
 			if util.IsLiteral(y, "true") {
-				replaceCond(x) // replaces `ok == true` with `ok`
+				replaceCond(x)                              // replaces `ok == true` with `ok`
+				p.canonicalizeConditional(graph, thisBlock) // recur since `ok` can be a conditional itself, e.g., `x != nil`
 			} else if util.IsLiteral(y, "false") {
 				newCond := &ast.UnaryExpr{
 					OpPos: y.Pos(),
@@ -523,3 +750,133 @@ func markSwitchStatements(graph *cfg.CFG, switchChildren map[ast.Node]*ast.Switc
 		}
 	}
 }
+
+// markTypeSwitchStatements restructures a cfg to reflect the `nil` cases of type switch
+// statements.
+//
+// In particular, `switch v := x.(type) { case T0: e0 case nil: e1 ... }` will be parsed by the
+// CFG into:
+//
+// Block0: Nodes: v := x.(type), Succs: Block1, Block2
+// Block1: e0
+// Block2: Nodes: (none), Succs: Block3, Block4
+// Block3: e1
+// Block4: ...
+//
+// Where each test block (i.e., Block0, Block2, ...) tests one of the case types in order, but the
+// tests themselves are not represented. We insert the test `x == nil` into the test blocks of the
+// `nil` case types, which we transform into:
+//
+// Block0: Nodes: (none), Succs: Block1, Block2
+// Block1: v := x.(type), e0
+// Block2: Nodes: x == nil, Succs: Block3, Block4
+// Block3: v := x.(type), e1
+// Block4: ...
+//
+// Such that the existing logic for reading nil checks from the CFG learns that `x` is nil in the
+// `nil` case, and nonnil in the following cases and the default case. The binding `v := x.(type)`
+// is moved to the beginning of e0, e1, ... as well, such that `v` follows the nilness of `x` in
+// each case. The tests of the other case types are left as is, since `x` (and `v`) can still be
+// nil there, e.g., a nil `*T` stored in an interface matches the case `*T`.
+func (p *Preprocessor) markTypeSwitchStatements(graph *cfg.CFG, funcDecl *ast.FuncDecl) {
+	typeSwitches := make(map[ast.Node]*ast.TypeSwitchStmt)
+	ast.Inspect(funcDecl, func(node ast.Node) bool {
+		if n, ok := node.(*ast.TypeSwitchStmt); ok {
+			typeSwitches[n.Assign] = n
+		}
+		return true
+	})
+	if len(typeSwitches) == 0 {
+		return
+	}
+
+	// The bindings `v := x.(type)` may be moved to the case bodies below, so we keep track of the
+	// handled type switches to avoid handling them again at the case bodies.
+	handled := make(map[*ast.TypeSwitchStmt]bool)
+	for _, block := range graph.Blocks {
+		n := len(block.Nodes)
+		if n < 1 {
+			continue
+		}
+		typeSwitch := typeSwitches[block.Nodes[n-1]]
+		if typeSwitch == nil || handled[typeSwitch] {
+			continue
+		}
+		handled[typeSwitch] = true
+		switchExpr := typeSwitchExpr(typeSwitch)
+		if switchExpr == nil {
+			continue
+		}
+
+		// Walk the test blocks in the same order as the case types are tested, where the next test
+		// block is always the false branch of the current one.
+		var nilTests, bodies []*cfg.Block
+		testBlock := block
+		for _, clause := range typeSwitch.Body.List {
+			cc, ok := clause.(*ast.CaseClause)
+			if !ok || cc.List == nil {
+				// The default case is not tested.
+				continue
+			}
+			for _, caseType := range cc.List {
+				if testBlock == nil || len(testBlock.Succs) != 2 {
+					// This should not happen for CFGs built by the cfg package, but we bail out
+					// gracefully if the structure is not as expected.
+					return
+				}
+				if tv, ok := p.pass.TypesInfo.Types[caseType]; ok && tv.IsNil() {
+					nilTests = append(nilTests, testBlock)
+					testBlock.Nodes = append(testBlock.Nodes, &ast.BinaryExpr{
+						X:     switchExpr,
+						OpPos: caseType.Pos(), // use the position of the case type
+						Op:    token.EQL,
+						Y:     caseType,
+					})
+				}
+				if !slices.Contains(bodies, testBlock.Succs[0]) {
+					bodies = append(bodies, testBlock.Succs[0])
+				}
+				testBlock = testBlock.Succs[1]
+			}
+		}
+		if len(nilTests) == 0 {
+			continue
+		}
+
+		// The symbolic variable `v` is bound to `x` before any test, hence it would not learn
+		// about the nilness of `x` from the `nil` tests. So we move the binding `v := x.(type)`
+		// to the beginning of each case body (and the default case body, which starts at the
+		// false branch of the last test), where `v` is logically bound.
+		if _, ok := typeSwitch.Assign.(*ast.AssignStmt); !ok {
+			continue
+		}
+		if testBlock != nil {
+			bodies = append(bodies, testBlock)
+		}
+		bindingIdx := n - 1
+		block.Nodes = slices.Delete(block.Nodes, bindingIdx, bindingIdx+1)
+		for _, body := range bodies {
+			body.Nodes = slices.Insert(body.Nodes, 0, ast.Node(typeSwitch.Assign))
+		}
+	}
+}
+
+// typeSwitchExpr returns the expression `x` being switched on in the type switch statement
+// `switch x.(type)` or `switch v := x.(type)`, or nil if the statement is malformed.
+func typeSwitchExpr(typeSwitch *ast.TypeSwitchStmt) ast.Expr {
+	var expr ast.Expr
+	switch assign := typeSwitch.Assign.(type) {
+	case *ast.ExprStmt:
+		expr = assign.X
+	case *ast.AssignStmt:
+		if len(assign.Rhs) != 1 {
+			return nil
+		}
+		expr = assign.Rhs[0]
+	}
+	typeAssert, ok := ast.Unparen(expr).(*ast.TypeAssertExpr)
+	if !ok {
+		return nil
+	}
+	return typeAssert.X
+}
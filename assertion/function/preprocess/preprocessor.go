@@ -16,14 +16,18 @@
 // amenable to analysis.
 package preprocess
 
-import "golang.org/x/tools/go/analysis"
+import (
+	"go.uber.org/nilaway/assertion/function/functioncontracts"
+	"golang.org/x/tools/go/analysis"
+)
 
 // Preprocessor handles different preprocessing logic for different types of input.
 type Preprocessor struct {
-	pass *analysis.Pass
+	pass          *analysis.Pass
+	funcContracts functioncontracts.Map
 }
 
 // New returns a new Preprocessor.
-func New(pass *analysis.Pass) *Preprocessor {
-	return &Preprocessor{pass: pass}
+func New(pass *analysis.Pass, funcContracts functioncontracts.Map) *Preprocessor {
+	return &Preprocessor{pass: pass, funcContracts: funcContracts}
 }
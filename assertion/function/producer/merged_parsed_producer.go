@@ -0,0 +1,54 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package producer
+
+import "go.uber.org/nilaway/annotation"
+
+// MergedParsedProducer is a ParsedProducer for values whose indices are produced by any of several
+// values, such as the result of `append(s, x, y...)`, whose elements are those of `s`, `x`, and the
+// elements of `y`. A consumer of an index of such a value is matched with each of DeepProducers.
+type MergedParsedProducer struct {
+	ShallowProducer *annotation.ProduceTrigger
+	DeepProducers   []*annotation.ProduceTrigger
+}
+
+// GetShallow for a MergedParsedProducer returns the ProduceTrigger producing the value itself
+func (mp MergedParsedProducer) GetShallow() *annotation.ProduceTrigger {
+	return mp.ShallowProducer
+}
+
+// GetDeep for a MergedParsedProducer returns the first of the ProduceTriggers producing indices of
+// the value, use GetDeepSlice to get all of them
+// nilable(result 0)
+func (mp MergedParsedProducer) GetDeep() *annotation.ProduceTrigger {
+	if len(mp.DeepProducers) == 0 {
+		return nil
+	}
+	return mp.DeepProducers[0]
+}
+
+// GetFieldProducers returns nil as field producers
+func (mp MergedParsedProducer) GetFieldProducers() []*annotation.ProduceTrigger {
+	return nil
+}
+
+// IsDeep for a MergedParsedProducer returns true
+func (mp MergedParsedProducer) IsDeep() bool { return true }
+
+// GetDeepSlice for a MergedParsedProducer returns all the ProduceTriggers producing indices of the
+// value
+func (mp MergedParsedProducer) GetDeepSlice() []*annotation.ProduceTrigger {
+	return mp.DeepProducers
+}
@@ -26,6 +26,8 @@ type ParsedProducer interface {
 	GetFieldProducers() []*annotation.ProduceTrigger
 	IsDeep() bool
 
-	// GetDeepSlice returns a 0 or 1 length slice; sometimes this is a more convenient representation
+	// GetDeepSlice returns the ProduceTriggers producing indices of the value: none for shallow
+	// producers, one for deep producers, and possibly more for merged producers (see
+	// MergedParsedProducer); sometimes this is a more convenient representation
 	GetDeepSlice() []*annotation.ProduceTrigger
 }
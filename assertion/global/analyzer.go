@@ -30,11 +30,12 @@ const _doc = "Nonnil global variables should be forced to provide a nonnil insta
 
 // Analyzer checks if the nonnill global variables are initialized.
 var Analyzer = &analysis.Analyzer{
-	Name:       "nilaway_global_var_analyzer",
-	Doc:        _doc,
-	Run:        analysishelper.WrapRun(run),
-	ResultType: reflect.TypeOf((*analysishelper.Result[[]annotation.FullTrigger])(nil)),
-	Requires:   []*analysis.Analyzer{config.Analyzer},
+	Name:             "nilaway_global_var_analyzer",
+	Doc:              _doc,
+	Run:              analysishelper.WrapRun(run),
+	ResultType:       reflect.TypeOf((*analysishelper.Result[[]annotation.FullTrigger])(nil)),
+	Requires:         []*analysis.Analyzer{config.Analyzer},
+	RunDespiteErrors: true,
 }
 
 func run(pass *analysis.Pass) ([]annotation.FullTrigger, error) {
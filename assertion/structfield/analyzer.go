@@ -29,11 +29,12 @@ const _doc = "Collect relevant struct fields being assigned and/or accessed from
 
 // Analyzer collects struct fields accessed (e.g., assignments) from within a function.
 var Analyzer = &analysis.Analyzer{
-	Name:       "nilaway_struct_field_analyzer",
-	Doc:        _doc,
-	Run:        analysishelper.WrapRun(run),
-	ResultType: reflect.TypeOf((*analysishelper.Result[*FieldContext])(nil)),
-	Requires:   []*analysis.Analyzer{config.Analyzer},
+	Name:             "nilaway_struct_field_analyzer",
+	Doc:              _doc,
+	Run:              analysishelper.WrapRun(run),
+	ResultType:       reflect.TypeOf((*analysishelper.Result[*FieldContext])(nil)),
+	Requires:         []*analysis.Analyzer{config.Analyzer},
+	RunDespiteErrors: true,
 }
 
 func run(pass *analysis.Pass) (*FieldContext, error) {
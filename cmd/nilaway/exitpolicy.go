@@ -0,0 +1,336 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.uber.org/nilaway/config"
+	"go.uber.org/nilaway/suppression"
+)
+
+// The driver flags for controlling the exit code of the driver (see exitPolicy). They are only
+// registered for the usage, since the driver parses and strips them before running the analysis
+// (see parseExitPolicy).
+var (
+	_exitZero       bool
+	_failThreshold  int
+	_baseline       string
+	_updateBaseline bool
+)
+
+// _exitCodeDiagnostics is the exit code of singlechecker when diagnostics are reported, which the
+// driver keeps for the failing diagnostics.
+const _exitCodeDiagnostics = 3
+
+// exitPolicy is the policy that decides the exit code of the driver from the reported diagnostics,
+// for the incremental rollouts where not all of the diagnostics should fail the CI jobs. The zero
+// value is the default policy of singlechecker, i.e., failing on any diagnostic.
+type exitPolicy struct {
+	// exitZero indicates that the driver always exits with zero (i.e., report-only).
+	exitZero bool
+	// threshold is the number of the (new) diagnostics tolerated, i.e., the driver fails only if
+	// more diagnostics are reported.
+	threshold int
+	// baseline is the path of the baseline file, empty if none. If set, only the diagnostics that
+	// are not in the baseline (i.e., the new ones) count towards the threshold.
+	baseline string
+	// updateBaseline indicates that the reported diagnostics are written to the baseline file
+	// instead, and the driver exits with zero.
+	updateBaseline bool
+	// suppressed is the numbers of the errors suppressed in the run by each mechanism, to which the
+	// errors accepted by the baseline are added before writing the suppression report. It is nil
+	// if the report is not requested (see mainExitPolicy).
+	suppressed suppression.Counts
+}
+
+// active returns true if the policy is not the default one, in which case the driver decides the
+// exit code itself rather than leaving it to singlechecker.
+func (p exitPolicy) active() bool {
+	return p.exitZero || p.threshold > 0 || p.baseline != ""
+}
+
+// parseExitPolicy parses the exit policy from the command line arguments, and returns the
+// arguments without the flags of the policy such that they are not passed to the analysis.
+func parseExitPolicy(args []string) (exitPolicy, []string, error) {
+	var p exitPolicy
+	if value, ok := lookupFlag(args, "exit-zero"); ok && value != "false" {
+		p.exitZero = true
+	}
+	if value, ok := lookupFlag(args, "fail-threshold"); ok {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return exitPolicy{}, nil, fmt.Errorf("invalid -fail-threshold %q, expected a non-negative number of errors", value)
+		}
+		p.threshold = n
+	}
+	if value, ok := lookupFlag(args, "baseline"); ok {
+		p.baseline = value
+	}
+	if value, ok := lookupFlag(args, "update-baseline"); ok && value != "false" {
+		if p.baseline == "" {
+			return exitPolicy{}, nil, fmt.Errorf("-update-baseline requires -baseline")
+		}
+		p.updateBaseline = true
+	}
+	args = removeFlag(removeFlag(args, "fail-threshold"), "baseline")
+	args = removeBoolFlag(removeBoolFlag(args, "exit-zero"), "update-baseline")
+	return p, args, nil
+}
+
+// removeBoolFlag returns the command line arguments without the boolean flag with the given name,
+// whose value (if any) is always given in the same argument unlike the flags removed by
+// removeFlag.
+func removeBoolFlag(args []string, name string) []string {
+	var result []string
+	for i, arg := range args {
+		if arg == "--" {
+			return append(result, args[i:]...)
+		}
+		flagName, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || flagName != name {
+			result = append(result, arg)
+		}
+	}
+	return result
+}
+
+// exitCode returns the exit code of the driver for the reported diagnostics, writing the notes on
+// the policy (e.g., the number of new diagnostics and the stale entries of the baseline) and the
+// suppression report (if requested) to out. The paths of the files are relative to root in the
+// baseline file (see baselineKey).
+func (p exitPolicy) exitCode(diagnostics []jsonDiagnostic, root string, out io.Writer) (int, error) {
+	if p.updateBaseline {
+		if err := writeBaseline(p.baseline, diagnostics, root); err != nil {
+			return 0, err
+		}
+		fmt.Fprintf(out, "nilaway: wrote %d error(s) to the baseline %s\n", len(diagnostics), p.baseline)
+		if p.suppressed != nil {
+			writeSuppressionReport(out, p.suppressed)
+		}
+		return 0, nil
+	}
+
+	failing := diagnostics
+	if p.baseline != "" {
+		baseline, err := readBaseline(p.baseline)
+		if err != nil {
+			return 0, err
+		}
+		var stale []baselineError
+		failing, stale = newDiagnostics(diagnostics, baseline, root)
+		fmt.Fprintf(out, "nilaway: %d of %d error(s) are not in the baseline %s\n", len(failing), len(diagnostics), p.baseline)
+		if len(stale) > 0 {
+			fmt.Fprintf(out, "nilaway: %d stale error(s) in the baseline %s are no longer reported (run with -update-baseline to remove them):\n", len(stale), p.baseline)
+			for _, e := range stale {
+				fmt.Fprintf(out, "  %s: %s\n", e.File, e.Message)
+			}
+		}
+		if accepted := len(diagnostics) - len(failing); accepted > 0 && p.suppressed != nil {
+			p.suppressed[suppression.Baseline] += accepted
+		}
+	}
+	if p.suppressed != nil {
+		writeSuppressionReport(out, p.suppressed)
+	}
+	if p.exitZero || len(failing) <= p.threshold {
+		return 0, nil
+	}
+	return _exitCodeDiagnostics, nil
+}
+
+// baselineFile is the content of a baseline file, i.e., the diagnostics reported when the baseline
+// is created (or updated) that are accepted by the later runs.
+type baselineFile struct {
+	Errors []baselineError `json:"errors"`
+}
+
+// baselineError is a diagnostic in the baseline file.
+type baselineError struct {
+	// File is the path of the file that the diagnostic is reported in, relative to the working
+	// directory of the driver if it is under it.
+	File string `json:"file"`
+	// Message is the message of the diagnostic without the line and column numbers (see
+	// baselineKey).
+	Message string `json:"message"`
+}
+
+var (
+	// _lineColumn matches the line and column numbers of the positions in the Go files.
+	_lineColumn = regexp.MustCompile(`(\.go):\d+(:\d+)?`)
+	// _colorCode matches the ANSI color codes of the pretty-printed messages.
+	_colorCode = regexp.MustCompile("\x1b\\[[0-9;]*m")
+)
+
+// baselineKey returns the entry of the diagnostic in the baseline. The line and column numbers
+// (of both the diagnostic and the positions in the message) are dropped such that the unrelated
+// edits shifting the code do not turn the accepted diagnostics into new ones. The decorations of
+// the pretty-printed messages (see util.PrettyPrintErrorMessage) are dropped as well, such that
+// the baseline applies to all the output formats.
+func baselineKey(d jsonDiagnostic, root string) baselineError {
+	file, _, _ := splitPosn(d.Posn)
+	message := _colorCode.ReplaceAllString(d.Message, "")
+	message = strings.TrimPrefix(message, "error: ")
+	message = _lineColumn.ReplaceAllString(message, "$1")
+	return baselineError{File: relativePath(file, root), Message: message}
+}
+
+// newDiagnostics returns the diagnostics that are not in the baseline, and the stale entries of
+// the baseline that no diagnostic matches (i.e., the errors that have been fixed since). Each entry
+// of the baseline accepts one diagnostic, such that the diagnostics that are the same modulo the
+// positions are new if they outnumber their entries.
+func newDiagnostics(diagnostics []jsonDiagnostic, baseline *baselineFile, root string) ([]jsonDiagnostic, []baselineError) {
+	accepted := make(map[baselineError]int)
+	for _, e := range baseline.Errors {
+		accepted[e]++
+	}
+	var result []jsonDiagnostic
+	for _, d := range diagnostics {
+		key := baselineKey(d, root)
+		if accepted[key] > 0 {
+			accepted[key]--
+			continue
+		}
+		result = append(result, d)
+	}
+	var stale []baselineError
+	for _, e := range baseline.Errors {
+		if accepted[e] > 0 {
+			accepted[e]--
+			stale = append(stale, e)
+		}
+	}
+	return result, stale
+}
+
+// readBaseline reads the baseline file.
+func readBaseline(path string) (*baselineFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read baseline: %w", err)
+	}
+	var baseline baselineFile
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("parse baseline %s: %w", path, err)
+	}
+	return &baseline, nil
+}
+
+// writeBaseline writes the diagnostics to the baseline file, sorted such that the diffs of the
+// updates are small.
+func writeBaseline(path string, diagnostics []jsonDiagnostic, root string) error {
+	baseline := baselineFile{Errors: make([]baselineError, 0, len(diagnostics))}
+	for _, d := range diagnostics {
+		baseline.Errors = append(baseline.Errors, baselineKey(d, root))
+	}
+	slices.SortStableFunc(baseline.Errors, func(a, b baselineError) int {
+		if n := strings.Compare(a.File, b.File); n != 0 {
+			return n
+		}
+		return strings.Compare(a.Message, b.Message)
+	})
+	data, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return fmt.Errorf("write baseline: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write baseline: %w", err)
+	}
+	return nil
+}
+
+// mainExitPolicy runs the analysis with the rest of the command line arguments in place of
+// singlechecker, whose exit code cannot be controlled, and returns the diagnostics. The driver is
+// run again in the JSON mode, and the diagnostics are written to stderr like singlechecker (or the
+// JSON output to stdout as is if `-json` is given). If the suppression report is requested, the
+// numbers of the suppressed errors are returned as well (see _suppressionCountsEnv), and nil
+// otherwise.
+func mainExitPolicy(args []string) ([]jsonDiagnostic, suppression.Counts, error) {
+	// The fixes are applied by singlechecker itself, which cannot be combined with the JSON mode.
+	for _, name := range []string{"fix", config.FixModeFlag} {
+		if _, ok := lookupFlag(args, name); ok {
+			return nil, nil, fmt.Errorf("the exit code flags cannot be combined with -%s", name)
+		}
+	}
+	value, ok := lookupFlag(args, "json")
+	isJSON := ok && value != "false"
+	args = removeBoolFlag(args, "json")
+	// The run is in the JSON mode, whereas the results are printed for humans unless the JSON
+	// output is requested (see the default of -assignment-flows in the JSON mode).
+	if _, ok := lookupFlag(args, config.AssignmentFlowsFlag); !ok && !isJSON {
+		args = append([]string{"-" + config.AssignmentFlowsFlag + "=" + config.AssignmentFlowsSummary}, args...)
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, nil, fmt.Errorf("find the executable: %w", err)
+	}
+	cmd := exec.Command(executable, append([]string{"-json"}, args...)...)
+	cmd.Stderr = os.Stderr
+	var countsPath string
+	if value, ok := lookupFlag(args, "suppression-report"); ok && value != "false" {
+		f, err := os.CreateTemp("", "nilaway-suppressions-*.json")
+		if err != nil {
+			return nil, nil, fmt.Errorf("create suppression counts file: %w", err)
+		}
+		countsPath = f.Name()
+		defer os.Remove(countsPath)
+		if err := f.Close(); err != nil {
+			return nil, nil, fmt.Errorf("create suppression counts file: %w", err)
+		}
+		cmd.Env = append(os.Environ(), _suppressionCountsEnv+"="+countsPath)
+	}
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, nil, fmt.Errorf("run analysis: %w", err)
+	}
+	diagnostics, err := writeDiagnostics(output, isJSON, os.Stdout, os.Stderr)
+	if err != nil || countsPath == "" {
+		return diagnostics, nil, err
+	}
+	suppressed, err := readSuppressionCounts(countsPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	return diagnostics, suppressed, nil
+}
+
+// writeDiagnostics parses the JSON output of the driver and writes it as is to stdout if isJSON
+// is true, or otherwise the (deduplicated and sorted) diagnostics to stderr in the text format of
+// singlechecker. It returns the diagnostics.
+func writeDiagnostics(output []byte, isJSON bool, stdout, stderr io.Writer) ([]jsonDiagnostic, error) {
+	diagnostics, err := parseJSONDiagnostics(output)
+	if err != nil {
+		return nil, err
+	}
+	merged := mergeDiagnostics(diagnostics)
+	if isJSON {
+		if _, err := stdout.Write(output); err != nil {
+			return nil, fmt.Errorf("write JSON output: %w", err)
+		}
+		return merged, nil
+	}
+	for _, d := range merged {
+		fmt.Fprintf(stderr, "%s: %s\n", d.Posn, d.Message)
+	}
+	return merged, nil
+}
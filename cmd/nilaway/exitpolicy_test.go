@@ -0,0 +1,163 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/nilaway/suppression"
+)
+
+func TestParseExitPolicy(t *testing.T) {
+	t.Parallel()
+
+	policy, args, err := parseExitPolicy([]string{"-exit-zero", "-fail-threshold", "2", "--baseline=b.json", "-update-baseline=true", "-pretty-print=false", "./..."})
+	require.NoError(t, err)
+	require.Equal(t, exitPolicy{exitZero: true, threshold: 2, baseline: "b.json", updateBaseline: true}, policy)
+	require.Equal(t, []string{"-pretty-print=false", "./..."}, args)
+	require.True(t, policy.active())
+
+	policy, args, err = parseExitPolicy([]string{"-exit-zero=false", "./...", "--", "-exit-zero"})
+	require.NoError(t, err)
+	require.False(t, policy.active())
+	require.Equal(t, []string{"./...", "--", "-exit-zero"}, args)
+
+	_, _, err = parseExitPolicy([]string{"-fail-threshold=-1", "./..."})
+	require.ErrorContains(t, err, "invalid -fail-threshold")
+	_, _, err = parseExitPolicy([]string{"-update-baseline", "./..."})
+	require.ErrorContains(t, err, "-update-baseline requires -baseline")
+}
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	diagnostics := []jsonDiagnostic{
+		{Posn: "/src/ex/ex.go:9:9", Message: "nil flow at ex/ex.go:9:9"},
+		{Posn: "/src/ex/ex.go:12:2", Message: "other"},
+	}
+	for _, tc := range []struct {
+		policy exitPolicy
+		want   int
+	}{
+		{policy: exitPolicy{}, want: 3},
+		{policy: exitPolicy{exitZero: true}, want: 0},
+		{policy: exitPolicy{threshold: 1}, want: 3},
+		{policy: exitPolicy{threshold: 2}, want: 0},
+	} {
+		code, err := tc.policy.exitCode(diagnostics, "/src", io.Discard)
+		require.NoError(t, err)
+		require.Equal(t, tc.want, code, "%+v", tc.policy)
+	}
+	code, err := exitPolicy{}.exitCode(nil, "/src", io.Discard)
+	require.NoError(t, err)
+	require.Zero(t, code)
+}
+
+func TestBaseline(t *testing.T) {
+	t.Parallel()
+
+	baseline := filepath.Join(t.TempDir(), "baseline.json")
+	var out strings.Builder
+	code, err := exitPolicy{baseline: baseline, updateBaseline: true}.exitCode([]jsonDiagnostic{
+		{Posn: "/src/ex/ex.go:9:9", Message: "\x1b[31merror: \x1b[0mnil flow at ex/ex.go:9:9"},
+		{Posn: "/src/ex/ex.go:12:2", Message: "other"},
+	}, "/src", &out)
+	require.NoError(t, err)
+	require.Zero(t, code)
+	require.Equal(t, "nilaway: wrote 2 error(s) to the baseline "+baseline+"\n", out.String())
+	data, err := os.ReadFile(baseline)
+	require.NoError(t, err)
+	require.Equal(t, `{
+  "errors": [
+    {
+      "file": "ex/ex.go",
+      "message": "nil flow at ex/ex.go"
+    },
+    {
+      "file": "ex/ex.go",
+      "message": "other"
+    }
+  ]
+}
+`, string(data))
+
+	// The errors in the baseline are accepted even if the code is shifted (or the messages are not
+	// pretty-printed), while the new ones (including the extra occurrences of the accepted ones)
+	// fail.
+	out.Reset()
+	shifted := []jsonDiagnostic{
+		{Posn: "/src/ex/ex.go:19:9", Message: "nil flow at ex/ex.go:19:9"},
+		{Posn: "/src/ex/ex.go:22:2", Message: "other"},
+	}
+	code, err = exitPolicy{baseline: baseline}.exitCode(shifted, "/src", &out)
+	require.NoError(t, err)
+	require.Zero(t, code)
+	require.Equal(t, "nilaway: 0 of 2 error(s) are not in the baseline "+baseline+"\n", out.String())
+
+	added := append(shifted, jsonDiagnostic{Posn: "/src/ex/ex.go:30:2", Message: "other"})
+	code, err = exitPolicy{baseline: baseline}.exitCode(added, "/src", io.Discard)
+	require.NoError(t, err)
+	require.Equal(t, 3, code)
+	code, err = exitPolicy{baseline: baseline, threshold: 1}.exitCode(added, "/src", io.Discard)
+	require.NoError(t, err)
+	require.Zero(t, code)
+
+	// The accepted errors are counted in the suppression report, and the entries that are no longer
+	// reported are listed as stale.
+	out.Reset()
+	code, err = exitPolicy{baseline: baseline, suppressed: suppression.Counts{suppression.Generated: 1}}.exitCode(shifted[1:], "/src", &out)
+	require.NoError(t, err)
+	require.Zero(t, code)
+	require.Equal(t, "nilaway: 0 of 1 error(s) are not in the baseline "+baseline+"\n"+
+		"nilaway: 1 stale error(s) in the baseline "+baseline+" are no longer reported (run with -update-baseline to remove them):\n"+
+		"  ex/ex.go: nil flow at ex/ex.go\n"+
+		"nilaway: 2 error(s) suppressed\n"+
+		"  baseline: 1\n"+
+		"  generated: 1\n", out.String())
+
+	_, err = exitPolicy{baseline: filepath.Join(t.TempDir(), "missing.json")}.exitCode(shifted, "/src", io.Discard)
+	require.ErrorContains(t, err, "read baseline")
+}
+
+func TestWriteDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	output := `{"ex": {"nilaway": [
+	{"posn": "/src/ex/ex.go:9:9", "message": "b"},
+	{"posn": "/src/ex/ex.go:4:2", "message": "a"}
+]}}
+{"ex [ex.test]": {"nilaway": [
+	{"posn": "/src/ex/ex.go:9:9", "message": "b"}
+]}}
+`
+	var stdout, stderr strings.Builder
+	diagnostics, err := writeDiagnostics([]byte(output), false, &stdout, &stderr)
+	require.NoError(t, err)
+	require.Len(t, diagnostics, 2)
+	require.Empty(t, stdout.String())
+	require.Equal(t, "/src/ex/ex.go:4:2: a\n/src/ex/ex.go:9:9: b\n", stderr.String())
+
+	stderr.Reset()
+	diagnostics, err = writeDiagnostics([]byte(output), true, &stdout, &stderr)
+	require.NoError(t, err)
+	require.Len(t, diagnostics, 2)
+	require.Equal(t, output, stdout.String())
+	require.Empty(t, stderr.String())
+}
@@ -0,0 +1,387 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.uber.org/nilaway/config"
+	"golang.org/x/tools/go/packages"
+)
+
+// _format is a driver flag for specifying the output format of the diagnostics (see runFormat).
+var _format string
+
+const (
+	// _formatText is the default output format, i.e., the plain text written by singlechecker.
+	_formatText = "text"
+	// _formatGitHubActions is the output format of GitHub Actions workflow commands, which
+	// annotate the files of the pull requests with the diagnostics.
+	_formatGitHubActions = "github-actions"
+	// _formatGitLabCodeQuality is the output format of GitLab Code Quality reports, which are
+	// uploaded as artifacts to show the diagnostics in the merge requests.
+	_formatGitLabCodeQuality = "gitlab-codequality"
+	// _formatCheckstyle is the output format of Checkstyle XML reports, which are read by the
+	// report viewers of Jenkins and other CI systems.
+	_formatCheckstyle = "checkstyle"
+	// _formatJUnit is the output format of JUnit XML reports, where each package is a test that
+	// fails with its diagnostics, for the CI systems only reading the test results.
+	_formatJUnit = "junit"
+)
+
+// _formats are the supported values of the -format flag.
+var _formats = []string{_formatText, _formatGitHubActions, _formatGitLabCodeQuality, _formatCheckstyle, _formatJUnit}
+
+// _recoveredCategory is the category of the diagnostics downgraded for being in the regions
+// recovering from panics, which are reported with a lower severity.
+const _recoveredCategory = "recovered"
+
+// formatRunner runs the analysis and returns the JSON output of the driver.
+type formatRunner func() ([]byte, error)
+
+// runFormat runs the analysis and writes the diagnostics to out in the given format, with the
+// file paths relative to root (i.e., the root of the repository that the CI systems resolve the
+// paths against) if they are under it. The paths of the analyzed root packages (pkgs) are only
+// needed for the JUnit format, where the packages without diagnostics are the passing tests. It
+// returns the diagnostics written.
+func runFormat(format string, run formatRunner, root string, pkgs []string, out io.Writer) ([]jsonDiagnostic, error) {
+	output, err := run()
+	if err != nil {
+		return nil, fmt.Errorf("run analysis: %w", err)
+	}
+	diagnostics, err := parseJSONDiagnostics(output)
+	if err != nil {
+		return nil, err
+	}
+	merged := mergeDiagnostics(diagnostics)
+
+	switch format {
+	case _formatGitHubActions:
+		writeGitHubActions(merged, root, out)
+	case _formatGitLabCodeQuality:
+		if err := writeGitLabCodeQuality(merged, root, out); err != nil {
+			return nil, err
+		}
+	case _formatCheckstyle:
+		if err := writeCheckstyle(merged, root, out); err != nil {
+			return nil, err
+		}
+	case _formatJUnit:
+		if err := writeJUnit(merged, root, pkgs, out); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+	return merged, nil
+}
+
+// writeGitHubActions writes the diagnostics as the `::error` (or `::warning` for the downgraded
+// ones) workflow commands of GitHub Actions, one per line.
+func writeGitHubActions(diagnostics []jsonDiagnostic, root string, out io.Writer) {
+	// See https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions
+	// for the escaping of the properties and the messages.
+	escapeData := strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	escapeProperty := strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+
+	for _, d := range diagnostics {
+		command := "error"
+		if d.Category == _recoveredCategory {
+			command = "warning"
+		}
+		file, line, col := splitPosn(d.Posn)
+		properties := []string{"file=" + escapeProperty.Replace(relativePath(file, root))}
+		if line > 0 {
+			properties = append(properties, "line="+strconv.Itoa(line), "col="+strconv.Itoa(col))
+		}
+		properties = append(properties, "title=NilAway")
+		fmt.Fprintf(out, "::%s %s::%s\n", command, strings.Join(properties, ","), escapeData.Replace(d.Message))
+	}
+}
+
+// codeQualityIssue is an issue in the GitLab Code Quality report, see
+// https://docs.gitlab.com/ee/ci/testing/code_quality.html#code-quality-report-format.
+type codeQualityIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    codeQualityLocation `json:"location"`
+}
+
+// codeQualityLocation is the location of an issue in the GitLab Code Quality report.
+type codeQualityLocation struct {
+	Path  string `json:"path"`
+	Lines struct {
+		Begin int `json:"begin"`
+	} `json:"lines"`
+}
+
+// writeGitLabCodeQuality writes the diagnostics as a GitLab Code Quality report, i.e., a JSON
+// array of the issues.
+func writeGitLabCodeQuality(diagnostics []jsonDiagnostic, root string, out io.Writer) error {
+	issues := make([]codeQualityIssue, 0, len(diagnostics))
+	for _, d := range diagnostics {
+		file, line, col := splitPosn(d.Posn)
+		issue := codeQualityIssue{
+			Description: d.Message,
+			CheckName:   "nilaway",
+			Severity:    "major",
+		}
+		if d.Category != "" {
+			issue.CheckName += "/" + d.Category
+		}
+		if d.Category == _recoveredCategory {
+			issue.Severity = "minor"
+		}
+		issue.Location.Path = relativePath(file, root)
+		issue.Location.Lines.Begin = max(line, 1)
+		// GitLab identifies the issues across the pipelines (e.g., to tell the new ones in a merge
+		// request) by the fingerprints, hence they must be deterministic.
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%s:%d:%d: %s", issue.Location.Path, line, col, d.Message)))
+		issue.Fingerprint = hex.EncodeToString(sum[:])
+		issues = append(issues, issue)
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(issues); err != nil {
+		return fmt.Errorf("write GitLab Code Quality report: %w", err)
+	}
+	return nil
+}
+
+// checkstyleReport is the root element of the Checkstyle XML report.
+type checkstyleReport struct {
+	XMLName xml.Name          `xml:"checkstyle"`
+	Version string            `xml:"version,attr"`
+	Files   []*checkstyleFile `xml:"file"`
+}
+
+// checkstyleFile is the element of a file with the errors in the Checkstyle XML report.
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+// checkstyleError is the element of an error in the Checkstyle XML report.
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// writeCheckstyle writes the diagnostics as a Checkstyle XML report, grouping them by files.
+func writeCheckstyle(diagnostics []jsonDiagnostic, root string, out io.Writer) error {
+	report := checkstyleReport{Version: "5.0"}
+	for _, d := range diagnostics {
+		file, line, col := splitPosn(d.Posn)
+		name := relativePath(file, root)
+		// The diagnostics are sorted by the positions, hence the ones of a file are adjacent.
+		if len(report.Files) == 0 || report.Files[len(report.Files)-1].Name != name {
+			report.Files = append(report.Files, &checkstyleFile{Name: name})
+		}
+		severity, source := "error", "nilaway"
+		if d.Category == _recoveredCategory {
+			severity = "warning"
+		}
+		if d.Category != "" {
+			source += "." + d.Category
+		}
+		f := report.Files[len(report.Files)-1]
+		f.Errors = append(f.Errors, checkstyleError{Line: line, Column: col, Severity: severity, Message: d.Message, Source: source})
+	}
+	return writeXML(report, out)
+}
+
+// junitReport is the root element of the JUnit XML report, i.e., a single test suite.
+type junitReport struct {
+	XMLName  xml.Name    `xml:"testsuites"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Suite    *junitSuite `xml:"testsuite"`
+}
+
+// junitSuite is the test suite of the packages in the JUnit XML report.
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+// junitCase is the test case of a package in the JUnit XML report, which fails if the package has
+// diagnostics.
+type junitCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+// junitFailure is the failure of a test case with the diagnostics of the package.
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit writes the diagnostics as a JUnit XML report, where each package (of the given root
+// packages and the ones with the diagnostics) is a test case that fails with the diagnostics
+// reported for it.
+func writeJUnit(diagnostics []jsonDiagnostic, root string, pkgs []string, out io.Writer) error {
+	byPkg := make(map[string][]jsonDiagnostic)
+	for _, d := range diagnostics {
+		if _, ok := byPkg[d.Package]; !ok {
+			pkgs = append(pkgs, d.Package)
+		}
+		byPkg[d.Package] = append(byPkg[d.Package], d)
+	}
+	pkgs = slices.Clone(pkgs)
+	slices.Sort(pkgs)
+	pkgs = slices.Compact(pkgs)
+
+	suite := &junitSuite{Name: "nilaway", Tests: len(pkgs)}
+	for _, pkg := range pkgs {
+		c := junitCase{ClassName: "nilaway", Name: pkg}
+		if ds := byPkg[pkg]; len(ds) > 0 {
+			var text strings.Builder
+			for _, d := range ds {
+				file, line, col := splitPosn(d.Posn)
+				posn := relativePath(file, root)
+				if line > 0 {
+					posn = fmt.Sprintf("%s:%d:%d", posn, line, col)
+				}
+				fmt.Fprintf(&text, "%s: %s\n", posn, strings.TrimSuffix(d.Message, "\n"))
+			}
+			c.Failure = &junitFailure{
+				Message: fmt.Sprintf("%d error(s) reported by NilAway", len(ds)),
+				Type:    "nilaway",
+				Text:    text.String(),
+			}
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, c)
+	}
+	return writeXML(junitReport{Tests: suite.Tests, Failures: suite.Failures, Suite: suite}, out)
+}
+
+// writeXML writes the report as an indented XML document.
+func writeXML(report any, out io.Writer) error {
+	if _, err := io.WriteString(out, xml.Header); err != nil {
+		return fmt.Errorf("write XML report: %w", err)
+	}
+	enc := xml.NewEncoder(out)
+	enc.Indent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return fmt.Errorf("write XML report: %w", err)
+	}
+	if _, err := io.WriteString(out, "\n"); err != nil {
+		return fmt.Errorf("write XML report: %w", err)
+	}
+	return nil
+}
+
+// rootPackages returns the paths of the root packages (i.e., the ones matching the patterns) to
+// analyze for the command line arguments, including the test packages if they are analyzed. It
+// is best-effort like countPackages: nil is returned if the arguments or the packages cannot be
+// parsed.
+func rootPackages(args []string) []string {
+	patterns, tests, ok := parsePatterns(args)
+	if !ok {
+		return nil
+	}
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedName, Tests: tests}, patterns...)
+	if err != nil {
+		return nil
+	}
+	var paths []string
+	for _, pkg := range pkgs {
+		// The generated main packages of the tests are not analyzed.
+		if strings.HasSuffix(pkg.ID, ".test") {
+			continue
+		}
+		paths = append(paths, packagePath(pkg.ID))
+	}
+	return paths
+}
+
+// relativePath returns the path of the file relative to root in the slash-separated form, or the
+// path itself if it is not under root.
+func relativePath(file, root string) string {
+	if root == "" || !filepath.IsAbs(file) {
+		return file
+	}
+	rel, err := filepath.Rel(root, file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return file
+	}
+	return filepath.ToSlash(rel)
+}
+
+// mainFormat runs the analysis with the rest of the command line arguments and writes the
+// diagnostics to stdout in the given format (see runFormat), and returns the diagnostics written.
+func mainFormat(format string, args []string) ([]jsonDiagnostic, error) {
+	if !slices.Contains(_formats, format) {
+		return nil, fmt.Errorf("unsupported value %q for flag %q, expected one of %s", format, "format", strings.Join(_formats, ", "))
+	}
+	// The diagnostics are converted from the JSON output, and the fixes are not part of the
+	// formats, hence these flags are not supported.
+	for _, name := range []string{"json", "fix", config.FixModeFlag} {
+		if _, ok := lookupFlag(args, name); ok {
+			return nil, fmt.Errorf("-format cannot be combined with -%s", name)
+		}
+	}
+	args = removeFlag(args, "format")
+	// The run is in the JSON mode, whereas the annotations are read by humans (see the default
+	// of -assignment-flows in the JSON mode).
+	if _, ok := lookupFlag(args, config.AssignmentFlowsFlag); !ok {
+		args = append([]string{"-" + config.AssignmentFlowsFlag + "=" + config.AssignmentFlowsSummary}, args...)
+	}
+	// The CI systems render the messages as plain text, where the color codes would be garbage.
+	if _, ok := lookupFlag(args, config.PrettyPrintFlag); !ok {
+		args = append([]string{"-" + config.PrettyPrintFlag + "=false"}, args...)
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("find the executable: %w", err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("get working directory: %w", err)
+	}
+	run := func() ([]byte, error) {
+		cmd := exec.Command(executable, append([]string{"-json"}, args...)...)
+		cmd.Stderr = os.Stderr
+		return cmd.Output()
+	}
+	var pkgs []string
+	if format == _formatJUnit {
+		pkgs = rootPackages(args)
+	}
+	return runFormat(format, run, wd, pkgs, os.Stdout)
+}
@@ -0,0 +1,145 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const _formatOutput = `{"ex": {"nilaway": [
+	{"posn": "/src/ex/ex.go:9:9", "message": "error: Potential nil panic detected.\n\t- ex.go:9:9: unassigned variable ` + "`x`" + ` dereferenced"},
+	{"posn": "/src/ex/ex.go:4:2", "category": "recovered", "message": "recovered, 50%"}
+]}}
+{"ex [ex.test]": {"nilaway": [
+	{"posn": "/src/ex/ex.go:9:9", "message": "error: Potential nil panic detected.\n\t- ex.go:9:9: unassigned variable ` + "`x`" + ` dereferenced"},
+	{"posn": "/other/a,b.go:1:1", "message": "outside"}
+]}}`
+
+func TestRunFormatGitHubActions(t *testing.T) {
+	t.Parallel()
+
+	var out strings.Builder
+	diagnostics, err := runFormat(_formatGitHubActions, func() ([]byte, error) { return []byte(_formatOutput), nil }, "/src", nil, &out)
+	require.NoError(t, err)
+	require.Len(t, diagnostics, 3)
+	require.Equal(t, `::error file=/other/a%2Cb.go,line=1,col=1,title=NilAway::outside
+::warning file=ex/ex.go,line=4,col=2,title=NilAway::recovered, 50%25
+::error file=ex/ex.go,line=9,col=9,title=NilAway::error: Potential nil panic detected.%0A	- ex.go:9:9: unassigned variable `+"`x`"+` dereferenced
+`, out.String())
+}
+
+func TestRunFormatGitLabCodeQuality(t *testing.T) {
+	t.Parallel()
+
+	var out strings.Builder
+	diagnostics, err := runFormat(_formatGitLabCodeQuality, func() ([]byte, error) { return []byte(_formatOutput), nil }, "/src", nil, &out)
+	require.NoError(t, err)
+	require.Len(t, diagnostics, 3)
+
+	var issues []codeQualityIssue
+	require.NoError(t, json.Unmarshal([]byte(out.String()), &issues))
+	require.Len(t, issues, 3)
+	require.Equal(t, "/other/a,b.go", issues[0].Location.Path)
+	require.Equal(t, "ex/ex.go", issues[1].Location.Path)
+	require.Equal(t, 4, issues[1].Location.Lines.Begin)
+	require.Equal(t, "minor", issues[1].Severity)
+	require.Equal(t, "nilaway/recovered", issues[1].CheckName)
+	require.Equal(t, "major", issues[2].Severity)
+	require.Equal(t, "nilaway", issues[2].CheckName)
+	require.Contains(t, issues[2].Description, "Potential nil panic detected.")
+
+	// The fingerprints are unique and deterministic.
+	require.NotEqual(t, issues[0].Fingerprint, issues[1].Fingerprint)
+	require.NotEqual(t, issues[1].Fingerprint, issues[2].Fingerprint)
+	var again strings.Builder
+	_, err = runFormat(_formatGitLabCodeQuality, func() ([]byte, error) { return []byte(_formatOutput), nil }, "/src", nil, &again)
+	require.NoError(t, err)
+	require.Equal(t, out.String(), again.String())
+
+	// An empty report is still a valid JSON array.
+	out.Reset()
+	diagnostics, err = runFormat(_formatGitLabCodeQuality, func() ([]byte, error) { return nil, nil }, "/src", nil, &out)
+	require.NoError(t, err)
+	require.Empty(t, diagnostics)
+	require.Equal(t, "[]\n", out.String())
+}
+
+func TestRunFormatCheckstyle(t *testing.T) {
+	t.Parallel()
+
+	var out strings.Builder
+	diagnostics, err := runFormat(_formatCheckstyle, func() ([]byte, error) { return []byte(_formatOutput), nil }, "/src", nil, &out)
+	require.NoError(t, err)
+	require.Len(t, diagnostics, 3)
+	require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="5.0">
+  <file name="/other/a,b.go">
+    <error line="1" column="1" severity="error" message="outside" source="nilaway"></error>
+  </file>
+  <file name="ex/ex.go">
+    <error line="4" column="2" severity="warning" message="recovered, 50%" source="nilaway.recovered"></error>
+    <error line="9" column="9" severity="error" message="error: Potential nil panic detected.&#xA;&#x9;- ex.go:9:9: unassigned variable `+"`x`"+` dereferenced" source="nilaway"></error>
+  </file>
+</checkstyle>
+`, out.String())
+}
+
+func TestRunFormatJUnit(t *testing.T) {
+	t.Parallel()
+
+	output := `{"ex": {"nilaway": [
+	{"posn": "/src/ex/ex.go:9:9", "message": "nil <panic>"}
+]}}
+{"ex [ex.test]": {"nilaway": [
+	{"posn": "/src/ex/ex.go:9:9", "message": "nil <panic>"},
+	{"posn": "/src/ex/ex_test.go:4:2", "message": "test"}
+]}}`
+	var out strings.Builder
+	diagnostics, err := runFormat(_formatJUnit, func() ([]byte, error) { return []byte(output), nil }, "/src", []string{"ex/clean", "ex"}, &out)
+	require.NoError(t, err)
+	require.Len(t, diagnostics, 2)
+	require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="2" failures="1">
+  <testsuite name="nilaway" tests="2" failures="1">
+    <testcase classname="nilaway" name="ex">
+      <failure message="2 error(s) reported by NilAway" type="nilaway">ex/ex.go:9:9: nil &lt;panic&gt;&#xA;ex/ex_test.go:4:2: test&#xA;</failure>
+    </testcase>
+    <testcase classname="nilaway" name="ex/clean"></testcase>
+  </testsuite>
+</testsuites>
+`, out.String())
+}
+
+func TestRunFormatErrors(t *testing.T) {
+	t.Parallel()
+
+	var out strings.Builder
+	_, err := runFormat(_formatGitHubActions, func() ([]byte, error) { return nil, errors.New("exit status 1") }, "/src", nil, &out)
+	require.ErrorContains(t, err, "exit status 1")
+	_, err = runFormat(_formatGitHubActions, func() ([]byte, error) {
+		return []byte(`{"ex": {"nilaway": {"error": "panic"}}}`), nil
+	}, "/src", nil, &out)
+	require.ErrorContains(t, err, "analyzer nilaway failed on package ex: panic")
+
+	_, err = mainFormat("sarif", nil)
+	require.ErrorContains(t, err, `unsupported value "sarif" for flag "format"`)
+	_, err = mainFormat(_formatGitHubActions, []string{"-fix", "./..."})
+	require.ErrorContains(t, err, "-format cannot be combined with -fix")
+}
@@ -21,117 +21,217 @@ import (
 	"flag"
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 
 	"go.uber.org/nilaway"
 	"go.uber.org/nilaway/config"
-	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/singlechecker"
 )
 
-// Analyzer is identical to the one in nilaway.go, except that it overrides the run function for
-// extra filtering of errors, since the singlechecker does not support error suppression like other
-// popular linter drivers.
-var Analyzer = &analysis.Analyzer{
-	Name:       nilaway.Analyzer.Name,
-	Doc:        nilaway.Analyzer.Doc,
-	Run:        run,
-	FactTypes:  nilaway.Analyzer.FactTypes,
-	ResultType: nilaway.Analyzer.ResultType,
-	Requires:   nilaway.Analyzer.Requires,
-}
-
-var (
-	// _includeErrorsInFiles is a driver flag for specifying the list of file prefixes to only report errors.
-	_includeErrorsInFiles string
-	// _excludeErrorsInFiles is a driver flag for specifying the list of file prefixes to not report errors.
-	_excludeErrorsInFiles string
-)
-
-func run(pass *analysis.Pass) (interface{}, error) {
-	// NilAway by default analyzes all packages, including dependencies. Even if specified to
-	// exclude packages from analysis via configurations, NilAway can still report errors on
-	// packages that are not analyzed if the nilness flow happens within the analyzed package, but
-	// the flow concerns a struct that is in an excluded package. The usual way to handle them is
-	// to suppress them at the driver level, but singlechecker does not support that yet. Therefore,
-	// here we add extra logic to filter the errors.
+func main() {
+	// For better UX, we lift the flags from config.Analyzer to the top level so that users can
+	// specify them without having to specify the analyzer name ("nilaway_config").
+	// For example, without lifting the flags, we will have to use `multichecker` to run the
+	// top-level NilAway analyzer _and_ the config analyzer. Users will have to specify flags as
+	// the following (directed to the "nilaway_config" analyzer):
+	//
+	// `nilaway -nilaway_config.flag1 <VALUE1> -nilaway_config.flag2 <VALUE> ./...`
+	//
+	// With this, the flags will be exposed at the top level, making "nilaway_config" analyzer
+	// transparent to the users:
+	//
+	// `nilaway -flag1 <VALUE1> -flag2 <VALUE> ./...`
+	//
+	config.Analyzer.Flags.VisitAll(func(f *flag.Flag) { flag.Var(f.Value, f.Name, f.Usage) })
 
-	// Properly parse the error suppression flags.
-	includes, err := parseFilePrefixes(_includeErrorsInFiles)
+	// Add the flags for controlling the exit code (e.g., for the incremental rollouts where the
+	// existing errors should not fail the CI jobs). They are parsed and stripped here, such that
+	// the driver modes below (and the processes they run) do not see them, and the exit code is
+	// decided by exitWithPolicy instead of singlechecker.
+	flag.BoolVar(&_exitZero, "exit-zero", false, "Always exit with zero even if errors are reported (i.e., report-only).")
+	flag.IntVar(&_failThreshold, "fail-threshold", 0, "Exit with nonzero only if more errors than this number are reported (only counting the errors not in -baseline, if given).")
+	flag.StringVar(&_baseline, "baseline", "", "The path of a baseline file of the accepted errors (see -update-baseline); exit with nonzero only if errors not in the baseline are reported.")
+	flag.BoolVar(&_updateBaseline, "update-baseline", false, "Write the reported errors to the baseline file given by -baseline and exit with zero.")
+	policy, args, err := parseExitPolicy(os.Args[1:])
 	if err != nil {
-		return nil, fmt.Errorf("parse file prefixes for error inclusion: %w", err)
-	}
-	excludes, err := parseFilePrefixes(_excludeErrorsInFiles)
-	if err != nil {
-		return nil, fmt.Errorf("parse file prefixes for error exclusion: %w", err)
+		fmt.Fprintf(os.Stderr, "nilaway: %v\n", err)
+		os.Exit(1)
 	}
 
-	// Override the report function to add error filtering logic.
-	report := pass.Report
-	pass.Report = func(d analysis.Diagnostic) {
-		p := pass.Fset.File(d.Pos).Name()
-		for _, e := range excludes {
-			if strings.HasPrefix(p, e) {
-				return
+	// Add the flags for the single-file analysis mode, where the contents of one file are read
+	// from stdin (e.g., unsaved buffers in editors), since singlechecker does not support overlays.
+	flag.BoolVar(&_stdin, "stdin", false, "Read the contents of one file from stdin, overlay it onto the package specified by -pkg-path, and report errors only for that file.")
+	flag.StringVar(&_pkgPath, "pkg-path", "", "The import path of the package that the file read from stdin belongs to (for -stdin).")
+	flag.StringVar(&_stdinFileName, "stdin-filename", "", "The path of the file whose contents are read from stdin (for -stdin), default is a new file \""+_defaultStdinFileName+"\" in the directory of the package.")
+	if value, ok := lookupFlag(args, "stdin"); ok && value != "false" {
+		if err := flag.CommandLine.Parse(args); err != nil {
+			fmt.Fprintf(os.Stderr, "nilaway: %v\n", err)
+			os.Exit(1)
+		}
+		if _stdin {
+			if policy.active() {
+				fmt.Fprintf(os.Stderr, "nilaway: -stdin cannot be combined with the exit code flags\n")
+				os.Exit(1)
+			}
+			n, err := runStdin(nilaway.Analyzer, _pkgPath, _stdinFileName, os.Stdin, os.Stdout, os.Stderr)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "nilaway: %v\n", err)
+				os.Exit(1)
 			}
+			if n > 0 {
+				// Exit with the same code as singlechecker when diagnostics are reported.
+				os.Exit(3)
+			}
+			os.Exit(0)
 		}
+	}
 
-		for _, i := range includes {
-			if strings.HasPrefix(p, i) {
-				report(d)
-				return
-			}
+	// Add the flag for analyzing the packages for multiple platforms (i.e., build configurations),
+	// where this driver is run again for each platform and the results are merged.
+	flag.StringVar(&_platforms, "platforms", "", "A comma-separated list of GOOS/GOARCH pairs (e.g., linux/amd64,darwin/arm64) to analyze the packages for separately, merging the errors; the errors not reported for all the platforms are tagged with the platforms they are reported for.")
+	if value, ok := lookupFlag(args, "platforms"); ok && value != "" {
+		diagnostics, err := mainPlatforms(value, args)
+		exitWithPolicy(policy, diagnostics, err)
+	}
+
+	// Add the flag for sharding the analysis of the packages across worker processes, where the
+	// packages are analyzed in dependency order by the go command and the results are merged.
+	flag.StringVar(&_shards, "shards", "", "The number of worker processes to shard the analysis of the packages across (in dependency order, passing the facts between them via files), merging the errors; this cuts the wall-clock time of full-repo runs.")
+	if value, ok := lookupFlag(args, "shards"); ok && value != "" {
+		diagnostics, err := mainShards(value, args)
+		exitWithPolicy(policy, diagnostics, err)
+	}
+
+	// Add the flag for writing the diagnostics in the formats of the CI systems, such that the
+	// CI jobs annotate the pull requests natively without converting the output themselves.
+	flag.StringVar(&_format, "format", _formatText, "The output format of the errors: \"text\" (default), \"github-actions\" (workflow commands annotating the files), \"gitlab-codequality\" (a Code Quality report), \"checkstyle\" (a Checkstyle XML report) or \"junit\" (a JUnit XML report with a test per package), written to stdout; the file paths are relative to the current working directory.")
+	if value, ok := lookupFlag(args, "format"); ok && value != _formatText {
+		diagnostics, err := mainFormat(value, args)
+		exitWithPolicy(policy, diagnostics, err)
+	}
+
+	// singlechecker always exits with nonzero when diagnostics are reported, hence the analysis is
+	// run in place of it under a non-default exit policy.
+	if policy.active() {
+		diagnostics, suppressed, err := mainExitPolicy(args)
+		policy.suppressed = suppressed
+		exitWithPolicy(policy, diagnostics, err)
+	}
+
+	// The fix mode only attaches suggested fixes to the diagnostics, and singlechecker applies
+	// them only if `-fix` is given. For better UX, we turn on `-fix` automatically (unless it is
+	// explicitly set) such that `nilaway -fix-mode=guard ./...` directly rewrites the source files.
+	if mode, ok := lookupFlag(args, config.FixModeFlag); ok && mode != "" {
+		if _, ok := lookupFlag(args, "fix"); !ok {
+			args = append([]string{"-fix"}, args...)
 		}
 	}
 
-	// Delegate the real analysis run to the original nilaway analyzer.
-	return nilaway.Analyzer.Run(pass)
-}
+	// Add the flag for reporting the progress of long runs (e.g., over std or a monorepo), which
+	// otherwise give no feedback for minutes.
+	flag.BoolVar(&_progress, "progress", false, "Print the progress (packages completed / total, current package, elapsed time) to stderr, followed by a summary of the packages analyzed, skipped and errored.")
+	if value, ok := lookupFlag(args, "progress"); ok && value != "false" {
+		enableProgress(args, os.Stderr)
+	}
 
-// parseFilePrefixes parses the comma-separated list of file prefixes, converts them to absolute
-// file paths, and returns them as a slice.
-func parseFilePrefixes(s string) ([]string, error) {
-	if s == "" {
-		return nil, nil
+	// Add the flag for printing a summary once the analysis completes (e.g., the top
+	// offending packages and the time spent in each analyzer) for triaging full-repo runs.
+	flag.BoolVar(&_summary, "summary", false, "Print a summary to stderr once the analysis completes: the diagnostics by code, the packages with the most diagnostics, the number of inferred nilable sites, the functions skipped due to the size limit or type errors, the packages whose analysis is aborted due to type errors, the enabled experiments, and the total time spent in each analyzer (config, functioncontracts, affiliation, function and accumulation).")
+	if value, ok := lookupFlag(args, "summary"); ok && value != "false" {
+		enableSummary(args, os.Stderr)
 	}
 
-	// Convert the file paths to absolute paths.
-	list := strings.Split(s, ",")
-	for i := range list {
-		p, err := filepath.Abs(list[i])
+	// Add the flag for reporting the errors hidden by the suppression mechanisms (e.g., excluded
+	// files), such that teams can track the debt that is not visible in the diagnostics.
+	flag.BoolVar(&_suppressionReport, "suppression-report", false, "Print the numbers of the errors suppressed by each mechanism (e.g., generated files, -exclude-errors-in-files, -recovered-panics=suppress or -baseline) in the analyzed packages to stderr once the analysis completes.")
+	if value, ok := lookupFlag(args, "suppression-report"); ok && value != "false" {
+		enableSuppressionReport(args, os.Stderr)
+	}
+
+	// NilAway by default analyzes all packages, including dependencies, and it can report errors on
+	// packages outside the current working directory if the nil flows cross them. For better UX,
+	// this driver only reports the errors in the current working directory by default (unless the
+	// error suppression flag is explicitly set), while the other drivers report all errors.
+	if _, ok := lookupFlag(args, config.IncludeErrorsInFilesFlag); !ok {
+		wd, err := os.Getwd()
 		if err != nil {
-			return nil, fmt.Errorf("convert %q to absolute path: %w", list[i], err)
+			fmt.Fprintf(os.Stderr, "failed to get working directory: %v\n", err)
+			os.Exit(1)
+		}
+		if err := flag.Set(config.IncludeErrorsInFilesFlag, wd); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to set the default of -%s: %v\n", config.IncludeErrorsInFilesFlag, err)
+			os.Exit(1)
 		}
-		list[i] = p
 	}
-	return list, nil
+
+	// singlechecker parses the flags from os.Args, hence the arguments are only handed over here.
+	os.Args = append([]string{os.Args[0]}, args...)
+	singlechecker.Main(nilaway.Analyzer)
 }
 
-func main() {
-	// For better UX, we lift the flags from config.Analyzer to the top level so that users can
-	// specify them without having to specify the analyzer name ("nilaway_config").
-	// For example, without lifting the flags, we will have to use `multichecker` to run the
-	// top-level NilAway analyzer _and_ the config analyzer. Users will have to specify flags as
-	// the following (directed to the "nilaway_config" analyzer):
-	//
-	// `nilaway -nilaway_config.flag1 <VALUE1> -nilaway_config.flag2 <VALUE> ./...`
-	//
-	// With this, the flags will be exposed at the top level, making "nilaway_config" analyzer
-	// transparent to the users:
-	//
-	// `nilaway -flag1 <VALUE1> -flag2 <VALUE> ./...`
-	//
-	config.Analyzer.Flags.VisitAll(func(f *flag.Flag) { flag.Var(f.Value, f.Name, f.Usage) })
+// mainPlatforms runs the analysis for each of the platforms in the comma-separated list with the
+// rest of the command line arguments (see runPlatforms), and returns the merged diagnostics
+// written to stderr like singlechecker.
+func mainPlatforms(value string, args []string) ([]jsonDiagnostic, error) {
+	platforms, err := parsePlatforms(value)
+	if err != nil {
+		return nil, fmt.Errorf("parse -platforms: %w", err)
+	}
+	// The results are merged from the JSON outputs, and the fixes would be applied for each
+	// platform separately, hence these flags are not supported.
+	for _, name := range []string{"json", "fix", config.FixModeFlag, "format"} {
+		if _, ok := lookupFlag(args, name); ok {
+			return nil, fmt.Errorf("-platforms cannot be combined with -%s", name)
+		}
+	}
+	// The runs for the platforms are in the JSON mode, whereas the merged results are printed
+	// for humans (see the default of -assignment-flows in the JSON mode).
+	if _, ok := lookupFlag(args, config.AssignmentFlowsFlag); !ok {
+		args = append([]string{"-" + config.AssignmentFlowsFlag + "=" + config.AssignmentFlowsSummary}, args...)
+	}
+	run, err := execPlatformRunner(removeFlag(args, "platforms"))
+	if err != nil {
+		return nil, err
+	}
+	return runPlatforms(platforms, run, os.Stderr)
+}
 
-	// Add two more flags to the driver for error suppression since singlechecker does not support it.
+// exitWithPolicy exits with the code decided by the exit policy for the diagnostics reported by a
+// driver mode, or with 1 if the driver mode failed.
+func exitWithPolicy(policy exitPolicy, diagnostics []jsonDiagnostic, err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "nilaway: %v\n", err)
+		os.Exit(1)
+	}
 	wd, err := os.Getwd()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to get working directory: %v\n", err)
+		fmt.Fprintf(os.Stderr, "nilaway: get working directory: %v\n", err)
 		os.Exit(1)
 	}
-	flag.StringVar(&_includeErrorsInFiles, "include-errors-in-files", wd, "A comma-separated list of file prefixes to report errors, default is current working directory.")
-	flag.StringVar(&_excludeErrorsInFiles, "exclude-errors-in-files", "", "A comma-separated list of file prefixes to exclude from error reporting. This takes precedence over include-errors-in-files.")
+	code, err := policy.exitCode(diagnostics, wd, os.Stderr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "nilaway: %v\n", err)
+		os.Exit(1)
+	}
+	os.Exit(code)
+}
 
-	singlechecker.Main(Analyzer)
+// lookupFlag returns the value of the flag with the given name in the command line arguments, and
+// whether the flag is given at all. Note that the returned value is meaningless for boolean flags
+// given without a value.
+func lookupFlag(args []string, name string) (string, bool) {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		flagName, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || flagName != name {
+			continue
+		}
+		if !hasValue && i+1 < len(args) {
+			value = args[i+1]
+		}
+		return value, true
+	}
+	return "", false
 }
@@ -0,0 +1,255 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// _platforms is a driver flag for specifying the GOOS/GOARCH pairs to analyze the packages for
+// (see runPlatforms).
+var _platforms string
+
+// platform is a GOOS/GOARCH pair.
+type platform struct {
+	goos, goarch string
+}
+
+// String returns the platform in the form of "<GOOS>/<GOARCH>".
+func (p platform) String() string {
+	return p.goos + "/" + p.goarch
+}
+
+// parsePlatforms parses the comma-separated list of GOOS/GOARCH pairs (e.g.,
+// "linux/amd64,darwin/arm64"), removing the duplicates.
+func parsePlatforms(s string) ([]platform, error) {
+	var platforms []platform
+	for _, item := range strings.Split(s, ",") {
+		goos, goarch, ok := strings.Cut(strings.TrimSpace(item), "/")
+		if !ok || goos == "" || goarch == "" || strings.Contains(goarch, "/") {
+			return nil, fmt.Errorf("invalid platform %q, expected the form of <GOOS>/<GOARCH>", item)
+		}
+		if p := (platform{goos: goos, goarch: goarch}); !slices.Contains(platforms, p) {
+			platforms = append(platforms, p)
+		}
+	}
+	return platforms, nil
+}
+
+// platformDiagnostic is a diagnostic reported for a subset of the platforms.
+type platformDiagnostic struct {
+	// posn is the position of the diagnostic in the form of "<file>:<line>:<column>".
+	posn string
+	// message is the message of the diagnostic.
+	message string
+	// platforms are the platforms that the diagnostic is reported for.
+	platforms []platform
+}
+
+// platformRunner runs the analysis for a platform and returns the JSON output of the driver.
+type platformRunner func(p platform) ([]byte, error)
+
+// runPlatforms runs the analysis separately for each platform, since the files (and hence the
+// nil flows) differ across the build configurations due to the build constraints, and writes the
+// merged diagnostics to out. Each run loads the packages (and analyzes the dependencies) for its
+// own build configuration only, such that the files excluded for a platform never influence the
+// results of it. The diagnostics reported for all the platforms are written as usual, and the
+// others are tagged with the platforms they are reported for. It returns the diagnostics written.
+func runPlatforms(platforms []platform, run platformRunner, out io.Writer) ([]jsonDiagnostic, error) {
+	var merged []*platformDiagnostic
+	index := make(map[[2]string]*platformDiagnostic)
+	for _, p := range platforms {
+		output, err := run(p)
+		if err != nil {
+			return nil, fmt.Errorf("run analysis for platform %s: %w", p, err)
+		}
+		diagnostics, err := parseJSONDiagnostics(output)
+		if err != nil {
+			return nil, fmt.Errorf("run analysis for platform %s: %w", p, err)
+		}
+		for _, d := range diagnostics {
+			key := [2]string{d.Posn, d.Message}
+			if existing, ok := index[key]; ok {
+				if !slices.Contains(existing.platforms, p) {
+					existing.platforms = append(existing.platforms, p)
+				}
+				continue
+			}
+			index[key] = &platformDiagnostic{posn: d.Posn, message: d.Message, platforms: []platform{p}}
+			merged = append(merged, index[key])
+		}
+	}
+
+	slices.SortStableFunc(merged, func(a, b *platformDiagnostic) int { return comparePosn(a.posn, b.posn) })
+	written := make([]jsonDiagnostic, 0, len(merged))
+	for _, d := range merged {
+		message := d.message
+		if len(d.platforms) < len(platforms) {
+			names := make([]string, len(d.platforms))
+			for i, p := range d.platforms {
+				names[i] = p.String()
+			}
+			message = fmt.Sprintf("[%s] %s", strings.Join(names, ", "), message)
+		}
+		fmt.Fprintf(out, "%s: %s\n", d.posn, message)
+		written = append(written, jsonDiagnostic{Posn: d.posn, Message: message})
+	}
+	return written, nil
+}
+
+// jsonDiagnostic is a diagnostic in the JSON output of the driver (`-json`).
+type jsonDiagnostic struct {
+	Category string `json:"category,omitempty"`
+	Posn     string `json:"posn"`
+	Message  string `json:"message"`
+	// Package is the path of the package that the diagnostic is reported for, which is not part
+	// of the JSON diagnostics but the key of the package in the output (see packagePath).
+	Package string `json:"-"`
+}
+
+// parseJSONDiagnostics parses the JSON output of the driver, which maps the package IDs to the
+// analyzer names to either the lists of diagnostics or the errors of the analyzers.
+func parseJSONDiagnostics(output []byte) ([]jsonDiagnostic, error) {
+	var diagnostics []jsonDiagnostic
+	dec := json.NewDecoder(bytes.NewReader(output))
+	for {
+		var tree map[string]map[string]json.RawMessage
+		if err := dec.Decode(&tree); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("parse JSON output: %w", err)
+		}
+		for pkg, analyzers := range tree {
+			for name, raw := range analyzers {
+				var analyzerErr struct {
+					Err string `json:"error"`
+				}
+				if err := json.Unmarshal(raw, &analyzerErr); err == nil && analyzerErr.Err != "" {
+					return nil, fmt.Errorf("analyzer %s failed on package %s: %s", name, pkg, analyzerErr.Err)
+				}
+				var ds []jsonDiagnostic
+				if err := json.Unmarshal(raw, &ds); err != nil {
+					return nil, fmt.Errorf("parse diagnostics of analyzer %s on package %s: %w", name, pkg, err)
+				}
+				for i := range ds {
+					ds[i].Package = packagePath(pkg)
+				}
+				diagnostics = append(diagnostics, ds...)
+			}
+		}
+	}
+	return diagnostics, nil
+}
+
+// mergeDiagnostics returns the diagnostics sorted by the positions, without the duplicates
+// reported for multiple variants of a package (e.g., with its tests).
+func mergeDiagnostics(diagnostics []jsonDiagnostic) []jsonDiagnostic {
+	seen := make(map[jsonDiagnostic]bool)
+	merged := make([]jsonDiagnostic, 0, len(diagnostics))
+	for _, d := range diagnostics {
+		if !seen[d] {
+			seen[d] = true
+			merged = append(merged, d)
+		}
+	}
+	slices.SortStableFunc(merged, func(a, b jsonDiagnostic) int { return comparePosn(a.Posn, b.Posn) })
+	return merged
+}
+
+// packagePath returns the path of the package with the given ID in the JSON output of the driver,
+// i.e., the ID without the suffix of the test variants (e.g., "ex [ex.test]").
+func packagePath(id string) string {
+	path, _, _ := strings.Cut(id, " ")
+	return path
+}
+
+// comparePosn compares the positions in the form of "<file>:<line>:<column>" by file names, lines
+// and columns.
+func comparePosn(a, b string) int {
+	fileA, lineA, colA := splitPosn(a)
+	fileB, lineB, colB := splitPosn(b)
+	if n := strings.Compare(fileA, fileB); n != 0 {
+		return n
+	}
+	if lineA != lineB {
+		return lineA - lineB
+	}
+	return colA - colB
+}
+
+// splitPosn splits the position in the form of "<file>:<line>:<column>" into its parts, where the
+// line and column are zero if missing.
+func splitPosn(posn string) (string, int, int) {
+	i := strings.LastIndex(posn, ":")
+	if i < 0 {
+		return posn, 0, 0
+	}
+	j := strings.LastIndex(posn[:i], ":")
+	if j < 0 {
+		return posn, 0, 0
+	}
+	line, errLine := strconv.Atoi(posn[j+1 : i])
+	col, errCol := strconv.Atoi(posn[i+1:])
+	if errLine != nil || errCol != nil {
+		return posn, 0, 0
+	}
+	return posn[:j], line, col
+}
+
+// execPlatformRunner returns the platformRunner that executes this driver again with the given
+// arguments (and `-json`) for each platform, setting the GOOS and GOARCH environment variables.
+func execPlatformRunner(args []string) (platformRunner, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("find the executable: %w", err)
+	}
+	return func(p platform) ([]byte, error) {
+		cmd := exec.Command(executable, append([]string{"-json"}, args...)...)
+		cmd.Env = append(os.Environ(), "GOOS="+p.goos, "GOARCH="+p.goarch)
+		cmd.Stderr = os.Stderr
+		return cmd.Output()
+	}, nil
+}
+
+// removeFlag returns the command line arguments without the flag with the given name (and its
+// value), the counterpart of lookupFlag.
+func removeFlag(args []string, name string) []string {
+	var result []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return append(result, args[i:]...)
+		}
+		flagName, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || flagName != name {
+			result = append(result, arg)
+			continue
+		}
+		if !hasValue {
+			// skip the value as well
+			i++
+		}
+	}
+	return result
+}
@@ -0,0 +1,79 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePlatforms(t *testing.T) {
+	t.Parallel()
+
+	platforms, err := parsePlatforms("linux/amd64, darwin/arm64,linux/amd64")
+	require.NoError(t, err)
+	require.Equal(t, []platform{{goos: "linux", goarch: "amd64"}, {goos: "darwin", goarch: "arm64"}}, platforms)
+
+	for _, s := range []string{"linux", "linux/", "/amd64", "linux/amd64/v2", "linux/amd64,"} {
+		_, err := parsePlatforms(s)
+		require.ErrorContains(t, err, "invalid platform", s)
+	}
+}
+
+func TestRunPlatforms(t *testing.T) {
+	t.Parallel()
+
+	outputs := map[string]string{
+		"linux/amd64": `{"ex": {"nilaway": [
+			{"posn": "/src/ex/ex.go:9:9", "message": "common"},
+			{"posn": "/src/ex/ex_linux.go:4:2", "message": "linux only"}
+		]}}`,
+		"darwin/arm64": `{"ex": {"nilaway": [
+			{"posn": "/src/ex/ex_darwin.go:4:2", "message": "darwin only"},
+			{"posn": "/src/ex/ex.go:9:9", "message": "common"},
+			{"posn": "/src/ex/ex.go:10:2", "message": "common"}
+		]}}`,
+	}
+	run := func(p platform) ([]byte, error) { return []byte(outputs[p.String()]), nil }
+	platforms := []platform{{goos: "linux", goarch: "amd64"}, {goos: "darwin", goarch: "arm64"}}
+
+	var out strings.Builder
+	diagnostics, err := runPlatforms(platforms, run, &out)
+	require.NoError(t, err)
+	require.Len(t, diagnostics, 4)
+	require.Equal(t, `/src/ex/ex.go:9:9: common
+/src/ex/ex.go:10:2: [darwin/arm64] common
+/src/ex/ex_darwin.go:4:2: [darwin/arm64] darwin only
+/src/ex/ex_linux.go:4:2: [linux/amd64] linux only
+`, out.String())
+
+	// The errors of the runs and the analyzers are propagated.
+	_, err = runPlatforms(platforms, func(platform) ([]byte, error) { return nil, errors.New("exit status 1") }, &out)
+	require.ErrorContains(t, err, "linux/amd64")
+	_, err = runPlatforms(platforms, func(platform) ([]byte, error) {
+		return []byte(`{"ex": {"nilaway": {"error": "panic"}}}`), nil
+	}, &out)
+	require.ErrorContains(t, err, "analyzer nilaway failed on package ex: panic")
+}
+
+func TestRemoveFlag(t *testing.T) {
+	t.Parallel()
+
+	args := []string{"-platforms", "linux/amd64", "-pretty-print=false", "--platforms=darwin/arm64", "./...", "--", "-platforms"}
+	require.Equal(t, []string{"-pretty-print=false", "./...", "--", "-platforms"}, removeFlag(args, "platforms"))
+}
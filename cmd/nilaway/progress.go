@@ -0,0 +1,204 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/nilaway"
+	"go.uber.org/nilaway/accumulation"
+	"go.uber.org/nilaway/config"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/packages"
+)
+
+// _progress is a driver flag for reporting the progress of the analysis to stderr (see progress).
+var _progress bool
+
+// packageStatus is the status of the analysis of a package.
+type packageStatus uint8
+
+const (
+	// _analyzed indicates that the package is analyzed.
+	_analyzed packageStatus = iota
+	// _skipped indicates that the package is out of the scope (e.g., not in -include-pkgs), i.e.,
+	// it is only visited for its facts.
+	_skipped
+	// _errored indicates that the analysis of the package failed (or panicked).
+	_errored
+)
+
+// String returns the verb describing the status.
+func (s packageStatus) String() string {
+	switch s {
+	case _analyzed:
+		return "analyzed"
+	case _skipped:
+		return "skipped"
+	default:
+		return "errored"
+	}
+}
+
+// progress reports the progress of the analysis, i.e., a line for each completed package with
+// the number of completed packages (out of the total packages to analyze, if known), and a final
+// summary of the packages analyzed, skipped and errored once all the root packages (i.e., the
+// ones matching the patterns) are completed. It is safe for concurrent use, since the packages
+// are analyzed in parallel.
+type progress struct {
+	mu  sync.Mutex
+	out io.Writer
+	// now returns the current time, which is replaceable for testing.
+	now   func() time.Time
+	start time.Time
+	// total is the number of all packages to analyze (including the dependencies), 0 if unknown.
+	total int
+	// roots is the number of root packages, 0 if unknown (and the summary is not written).
+	roots     int
+	rootsDone int
+	counts    [_errored + 1]int
+}
+
+// newProgress returns a new progress reporter writing to out.
+func newProgress(out io.Writer, total, roots int, now func() time.Time) *progress {
+	return &progress{out: out, now: now, start: now(), total: total, roots: roots}
+}
+
+// packageDone reports the completion of the analysis of a package.
+func (p *progress) packageDone(pkgPath string, status packageStatus) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.counts[status]++
+	done := p.counts[_analyzed] + p.counts[_skipped] + p.counts[_errored]
+	count := fmt.Sprint(done)
+	if p.total > 0 {
+		count = fmt.Sprintf("%d/%d", done, p.total)
+	}
+	fmt.Fprintf(p.out, "nilaway: [%s] %s %s (%s elapsed)\n", count, status, pkgPath, p.elapsed())
+}
+
+// rootDone reports the completion of a root package, and writes the summary once all the root
+// packages are completed.
+func (p *progress) rootDone() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rootsDone++
+	if p.rootsDone != p.roots {
+		return
+	}
+	fmt.Fprintf(p.out, "nilaway: %d package(s) analyzed, %d skipped, %d errored in %s\n",
+		p.counts[_analyzed], p.counts[_skipped], p.counts[_errored], p.elapsed())
+}
+
+// elapsed returns the time elapsed since the start, rounded for display.
+func (p *progress) elapsed() time.Duration {
+	return p.now().Sub(p.start).Round(100 * time.Millisecond)
+}
+
+// wrap wraps the Run functions of the accumulation analyzer (which runs on all packages, including
+// the dependencies, for their facts) and the top-level analyzer (which only runs on the root
+// packages) to report the progress.
+func (p *progress) wrap(acc, root *analysis.Analyzer) {
+	accRun := acc.Run
+	acc.Run = func(pass *analysis.Pass) (interface{}, error) {
+		result, err := accRun(pass)
+		status := _analyzed
+		if conf, ok := pass.ResultOf[config.Analyzer].(*config.Config); ok && !conf.IsPkgInScope(pass.Pkg) {
+			status = _skipped
+		}
+		if err != nil || hasInternalErrors(result) {
+			status = _errored
+		}
+		p.packageDone(pass.Pkg.Path(), status)
+		return result, err
+	}
+	rootRun := root.Run
+	root.Run = func(pass *analysis.Pass) (interface{}, error) {
+		defer p.rootDone()
+		return rootRun(pass)
+	}
+}
+
+// hasInternalErrors returns true if the result of the accumulation analyzer contains the
+// diagnostics for the internal errors (or panics) of NilAway.
+func hasInternalErrors(result interface{}) bool {
+	r, ok := result.(*accumulation.Result)
+	if !ok || r == nil {
+		return false
+	}
+	for _, d := range r.Diagnostics {
+		if strings.HasPrefix(d.Message, "INTERNAL ") {
+			return true
+		}
+	}
+	return false
+}
+
+// enableProgress enables the progress reporting to out for the analysis of the packages given in
+// the command line arguments (see countPackages).
+func enableProgress(args []string, out io.Writer) {
+	total, roots := countPackages(args)
+	newProgress(out, total, roots, time.Now).wrap(accumulation.Analyzer, nilaway.Analyzer)
+}
+
+// countPackages returns the number of all packages (including the dependencies) and the number of
+// root packages to analyze for the command line arguments. The package graph is loaded (without
+// type checking) up front, which is best-effort: if the arguments or the packages cannot be
+// parsed, zeros are returned.
+func countPackages(args []string) (total int, roots int) {
+	patterns, tests, ok := parsePatterns(args)
+	if !ok {
+		return 0, 0
+	}
+	pkgs, err := packages.Load(&packages.Config{
+		Mode:  packages.NeedName | packages.NeedImports | packages.NeedDeps,
+		Tests: tests,
+	}, patterns...)
+	if err != nil {
+		return 0, 0
+	}
+	packages.Visit(pkgs, nil, func(*packages.Package) { total++ })
+	return total, len(pkgs)
+}
+
+// parsePatterns returns the package patterns and the value of the -test flag in the command line
+// arguments, parsing them with the flags of this driver along with the flags of singlechecker.
+func parsePatterns(args []string) ([]string, bool, bool) {
+	fs := flag.NewFlagSet("nilaway", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	flag.VisitAll(func(f *flag.Flag) { fs.Var(f.Value, f.Name, f.Usage) })
+	// The flags registered by singlechecker itself (and the flags of this driver registered after
+	// the ones of the driver modes calling this), which are not known yet.
+	tests := fs.Bool("test", true, "")
+	for _, name := range []string{"fix", "diff", "json", "flags", "V", "progress", "summary", "suppression-report"} {
+		if fs.Lookup(name) == nil {
+			fs.Bool(name, false, "")
+		}
+	}
+	for _, name := range []string{"debug", "cpuprofile", "memprofile", "trace", "c"} {
+		if fs.Lookup(name) == nil {
+			fs.String(name, "", "")
+		}
+	}
+	if err := fs.Parse(args); err != nil || fs.NArg() == 0 {
+		return nil, false, false
+	}
+	return fs.Args(), *tests, true
+}
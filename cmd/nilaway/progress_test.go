@@ -0,0 +1,72 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgress(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(0, 0)
+	now := start
+	var out strings.Builder
+	p := newProgress(&out, 3, 2, func() time.Time { return now })
+
+	now = start.Add(1200 * time.Millisecond)
+	p.packageDone("fmt", _skipped)
+	p.packageDone("example.com/a", _analyzed)
+	p.rootDone()
+	now = start.Add(2 * time.Minute)
+	p.packageDone("example.com/b", _errored)
+	p.rootDone()
+
+	require.Equal(t, `nilaway: [1/3] skipped fmt (1.2s elapsed)
+nilaway: [2/3] analyzed example.com/a (1.2s elapsed)
+nilaway: [3/3] errored example.com/b (2m0s elapsed)
+nilaway: 1 package(s) analyzed, 1 skipped, 1 errored in 2m0s
+`, out.String())
+
+	// Without the total and the roots, neither the total nor the summary is written.
+	out.Reset()
+	p = newProgress(&out, 0, 0, func() time.Time { return now })
+	p.packageDone("example.com/a", _analyzed)
+	p.rootDone()
+	require.Equal(t, "nilaway: [1] analyzed example.com/a (0s elapsed)\n", out.String())
+}
+
+func TestParsePatterns(t *testing.T) {
+	t.Parallel()
+
+	patterns, tests, ok := parsePatterns([]string{"-json", "-test=false", "-c", "1", "./a/...", "./b"})
+	require.True(t, ok)
+	require.False(t, tests)
+	require.Equal(t, []string{"./a/...", "./b"}, patterns)
+
+	patterns, tests, ok = parsePatterns([]string{"./..."})
+	require.True(t, ok)
+	require.True(t, tests)
+	require.Equal(t, []string{"./..."}, patterns)
+
+	_, _, ok = parsePatterns([]string{"-unknown-flag", "./..."})
+	require.False(t, ok)
+	_, _, ok = parsePatterns([]string{"-json"})
+	require.False(t, ok)
+}
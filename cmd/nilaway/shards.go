@@ -0,0 +1,111 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"go.uber.org/nilaway/config"
+)
+
+// _shards is a driver flag for specifying the number of worker processes to shard the packages
+// across (see runShards).
+var _shards string
+
+// shardRunner runs the go command with the given arguments and returns its output (i.e., the
+// stderr that the vet tools write their JSON outputs to).
+type shardRunner func(args []string) ([]byte, error)
+
+// runShards runs the analysis as a coordinator that shards the package graph across the given
+// number of worker processes, and writes the merged diagnostics to out. The sharding is delegated
+// to `go vet -vettool`, which schedules one worker process (i.e., this driver in the unitchecker
+// mode) per package in dependency order with at most `shards` of them running at a time, and
+// passes the facts of each package to its dependents via files in the versioned encoding (see
+// inference.GobFingerprint). The JSON outputs of the workers are then merged, deduplicating the
+// diagnostics reported for multiple variants of a package (e.g., with its tests). It returns the
+// diagnostics written.
+func runShards(shards int, executable string, args []string, run shardRunner, out io.Writer) ([]jsonDiagnostic, error) {
+	vetArgs := []string{"vet", "-vettool=" + executable, "-json", "-p=" + strconv.Itoa(shards)}
+	output, err := run(append(vetArgs, args...))
+	if err != nil {
+		return nil, fmt.Errorf("run go vet: %w\n%s", err, output)
+	}
+	// The output of each package is preceded by a "# <package>" line written by the go command.
+	var buf bytes.Buffer
+	for _, line := range bytes.SplitAfter(output, []byte("\n")) {
+		if !bytes.HasPrefix(line, []byte("# ")) {
+			buf.Write(line)
+		}
+	}
+	diagnostics, err := parseJSONDiagnostics(buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	merged := mergeDiagnostics(diagnostics)
+	for _, d := range merged {
+		fmt.Fprintf(out, "%s: %s\n", d.Posn, d.Message)
+	}
+	return merged, nil
+}
+
+// mainShards runs the analysis sharded across the given number of worker processes with the rest
+// of the command line arguments (see runShards), and returns the merged diagnostics written to
+// stderr like singlechecker.
+func mainShards(value string, args []string) ([]jsonDiagnostic, error) {
+	shards, err := strconv.Atoi(value)
+	if err != nil || shards <= 0 {
+		return nil, fmt.Errorf("invalid -shards %q, expected a positive number of worker processes", value)
+	}
+	// The results are merged from the JSON outputs of the workers, and the other driver modes
+	// cannot be combined with the workers run by the go command.
+	for _, name := range []string{"json", "fix", config.FixModeFlag, "format", "platforms", "progress", "stdin", "summary", "suppression-report"} {
+		if _, ok := lookupFlag(args, name); ok {
+			return nil, fmt.Errorf("-shards cannot be combined with -%s", name)
+		}
+	}
+	args = removeFlag(args, "shards")
+	// The workers run in the directories of the packages, hence the default of reporting the
+	// errors in the current working directory only must be resolved here.
+	if _, ok := lookupFlag(args, config.IncludeErrorsInFilesFlag); !ok {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("get working directory: %w", err)
+		}
+		args = append([]string{"-" + config.IncludeErrorsInFilesFlag + "=" + wd}, args...)
+	}
+	// Likewise, the workers are run in the JSON mode, whereas the merged results are printed for
+	// humans (see the default of -assignment-flows in the JSON mode).
+	if _, ok := lookupFlag(args, config.AssignmentFlowsFlag); !ok {
+		args = append([]string{"-" + config.AssignmentFlowsFlag + "=" + config.AssignmentFlowsSummary}, args...)
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("find the executable: %w", err)
+	}
+	run := func(args []string) ([]byte, error) {
+		var stderr bytes.Buffer
+		cmd := exec.Command("go", args...)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = &stderr
+		err := cmd.Run()
+		return stderr.Bytes(), err
+	}
+	return runShards(shards, executable, args, run, os.Stderr)
+}
@@ -0,0 +1,65 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunShards(t *testing.T) {
+	t.Parallel()
+
+	var gotArgs []string
+	run := func(args []string) ([]byte, error) {
+		gotArgs = args
+		return []byte(`# ex/a
+{}
+# ex/b
+{"ex/b": {"nilaway": [
+	{"posn": "/src/ex/b/b.go:9:9", "message": "b"},
+	{"posn": "/src/ex/b/b.go:4:2", "message": "b"}
+]}}
+# ex/b [ex/b.test]
+{"ex/b [ex/b.test]": {"nilaway": [
+	{"posn": "/src/ex/b/b.go:9:9", "message": "b"},
+	{"posn": "/src/ex/b/b_test.go:3:1", "message": "b test"}
+]}}
+`), nil
+	}
+
+	var out strings.Builder
+	diagnostics, err := runShards(4, "/bin/nilaway", []string{"-pretty-print=false", "./..."}, run, &out)
+	require.NoError(t, err)
+	require.Equal(t, []string{"vet", "-vettool=/bin/nilaway", "-json", "-p=4", "-pretty-print=false", "./..."}, gotArgs)
+	require.Len(t, diagnostics, 3)
+	require.Equal(t, `/src/ex/b/b.go:4:2: b
+/src/ex/b/b.go:9:9: b
+/src/ex/b/b_test.go:3:1: b test
+`, out.String())
+
+	// The errors of the go command and the analyzers are propagated.
+	_, err = runShards(4, "/bin/nilaway", nil, func([]string) ([]byte, error) {
+		return []byte("ex/b/b.go:3:1: syntax error"), errors.New("exit status 1")
+	}, &out)
+	require.ErrorContains(t, err, "syntax error")
+	_, err = runShards(4, "/bin/nilaway", nil, func([]string) ([]byte, error) {
+		return []byte("# ex/b\n" + `{"ex/b": {"nilaway": {"error": "panic"}}}`), nil
+	}, &out)
+	require.ErrorContains(t, err, "analyzer nilaway failed on package ex/b: panic")
+}
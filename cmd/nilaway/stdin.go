@@ -0,0 +1,309 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/types"
+	"io"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+
+	"go.uber.org/nilaway/accumulation"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/packages"
+)
+
+// _defaultStdinFileName is the name of the file (in the directory of the package) that the
+// contents read from stdin are overlaid onto if no file name is given.
+const _defaultStdinFileName = "nilaway_stdin.go"
+
+var (
+	// _stdin is a driver flag for enabling the single-file analysis mode, where the contents of one
+	// file are read from stdin (see runStdin).
+	_stdin bool
+	// _pkgPath is a driver flag for specifying the import path of the package that the file read
+	// from stdin belongs to.
+	_pkgPath string
+	// _stdinFileName is a driver flag for specifying the path of the file whose contents are read
+	// from stdin.
+	_stdinFileName string
+)
+
+// runStdin runs the single-file analysis mode for editor integrations that analyze unsaved
+// buffers: it reads the contents of one file from stdin, overlays it onto the package with the
+// given import path, analyzes the package (and its dependencies) with the analyzer, and writes
+// the diagnostics in that file only to out. The file is the one at fileName, which may not exist
+// on disk yet; if fileName is empty, the contents are added as a new file to the package. It
+// returns the number of diagnostics written. The notices about the package being only partially
+// analyzed due to type errors (common in the buffers being edited) are written to errOut.
+//
+// The singlechecker does not support overlays, hence this mode has its own minimal driver that
+// loads the packages via go/packages and runs the analyzers in memory.
+func runStdin(a *analysis.Analyzer, pkgPath string, fileName string, stdin io.Reader, out, errOut io.Writer) (int, error) {
+	if pkgPath == "" {
+		return 0, errors.New("-pkg-path must be given in the stdin mode")
+	}
+	content, err := io.ReadAll(stdin)
+	if err != nil {
+		return 0, fmt.Errorf("read stdin: %w", err)
+	}
+
+	if fileName == "" {
+		dir, err := packageDir(pkgPath)
+		if err != nil {
+			return 0, err
+		}
+		fileName = filepath.Join(dir, _defaultStdinFileName)
+	}
+	fileName, err = filepath.Abs(fileName)
+	if err != nil {
+		return 0, fmt.Errorf("convert %q to absolute path: %w", fileName, err)
+	}
+
+	cfg := &packages.Config{
+		Mode:    packages.LoadAllSyntax | packages.NeedModule,
+		Overlay: map[string][]byte{fileName: content},
+	}
+	pkgs, err := packages.Load(cfg, pkgPath)
+	if err != nil {
+		return 0, fmt.Errorf("load package %q: %w", pkgPath, err)
+	}
+	if len(pkgs) != 1 {
+		return 0, fmt.Errorf("load package %q: expected 1 package, got %d", pkgPath, len(pkgs))
+	}
+	pkg := pkgs[0]
+	// Unsaved buffers often have syntax or type errors, with which the package is still analyzed
+	// (see analysis.Analyzer.RunDespiteErrors).
+	for _, e := range pkg.Errors {
+		if e.Kind != packages.ParseError && e.Kind != packages.TypeError {
+			return 0, fmt.Errorf("load package %q: %w", pkgPath, e)
+		}
+	}
+	if !slices.Contains(pkg.CompiledGoFiles, fileName) {
+		return 0, fmt.Errorf("file %q does not belong to package %q", fileName, pkgPath)
+	}
+
+	driver := newStdinDriver()
+	root := driver.action(a, pkg)
+	if err := root.exec(); err != nil {
+		return 0, err
+	}
+	if act, ok := driver.actions[actionKey{analyzer: accumulation.Analyzer, pkg: pkg}]; ok {
+		if r, ok := act.result.(*accumulation.Result); ok && r != nil {
+			writeTypeErrorNotice(errOut, pkgPath, r)
+		}
+	}
+
+	var diagnostics []analysis.Diagnostic
+	for _, d := range root.diagnostics {
+		if pkg.Fset.File(d.Pos).Name() == fileName {
+			diagnostics = append(diagnostics, d)
+		}
+	}
+	sort.SliceStable(diagnostics, func(i, j int) bool { return diagnostics[i].Pos < diagnostics[j].Pos })
+	for _, d := range diagnostics {
+		fmt.Fprintf(out, "%s: %s\n", pkg.Fset.Position(d.Pos), d.Message)
+	}
+	return len(diagnostics), nil
+}
+
+// writeTypeErrorNotice writes the notice about the package being only partially analyzed due to
+// its type errors to out, if any functions are skipped or the analysis is aborted.
+func writeTypeErrorNotice(out io.Writer, pkgPath string, r *accumulation.Result) {
+	switch {
+	case r.AbortedForTypeErrors:
+		fmt.Fprintf(out, "nilaway: package %s partially analyzed: analysis aborted due to type errors\n", pkgPath)
+	case r.SkippedForTypeErrors > 0:
+		fmt.Fprintf(out, "nilaway: package %s partially analyzed: %d function(s) skipped due to type errors\n", pkgPath, r.SkippedForTypeErrors)
+	}
+}
+
+// packageDir returns the directory of the package with the given import path.
+func packageDir(pkgPath string) (string, error) {
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedName | packages.NeedFiles}, pkgPath)
+	if err != nil {
+		return "", fmt.Errorf("load package %q: %w", pkgPath, err)
+	}
+	if len(pkgs) != 1 || len(pkgs[0].GoFiles) == 0 {
+		return "", fmt.Errorf("cannot find the directory of package %q, specify the file via -stdin-filename", pkgPath)
+	}
+	return filepath.Dir(pkgs[0].GoFiles[0]), nil
+}
+
+// stdinDriver is a minimal driver that runs analyzers on loaded packages in memory, where the
+// facts are passed from the dependencies to the dependents directly.
+type stdinDriver struct {
+	// actions memoizes the action of each analyzer on each package.
+	actions map[actionKey]*action
+}
+
+// actionKey identifies an action.
+type actionKey struct {
+	analyzer *analysis.Analyzer
+	pkg      *packages.Package
+}
+
+// action is the run of an analyzer on a package.
+type action struct {
+	analyzer *analysis.Analyzer
+	pkg      *packages.Package
+	// deps are the actions of the required analyzers on the same package, and the actions of the
+	// same analyzer on the imported packages if the analyzer uses facts.
+	deps []*action
+
+	done         bool
+	err          error
+	result       any
+	diagnostics  []analysis.Diagnostic
+	objectFacts  map[objectFactKey]analysis.Fact
+	packageFacts map[packageFactKey]analysis.Fact
+}
+
+// objectFactKey identifies an object fact.
+type objectFactKey struct {
+	obj types.Object
+	typ reflect.Type
+}
+
+// packageFactKey identifies a package fact.
+type packageFactKey struct {
+	pkg *types.Package
+	typ reflect.Type
+}
+
+// newStdinDriver returns a new stdinDriver.
+func newStdinDriver() *stdinDriver {
+	return &stdinDriver{actions: make(map[actionKey]*action)}
+}
+
+// action returns the (memoized) action of the analyzer on the package, creating the actions of
+// its dependencies as well.
+func (d *stdinDriver) action(a *analysis.Analyzer, pkg *packages.Package) *action {
+	key := actionKey{analyzer: a, pkg: pkg}
+	if act, ok := d.actions[key]; ok {
+		return act
+	}
+	act := &action{analyzer: a, pkg: pkg}
+	d.actions[key] = act
+	for _, req := range a.Requires {
+		act.deps = append(act.deps, d.action(req, pkg))
+	}
+	if len(a.FactTypes) > 0 {
+		paths := make([]string, 0, len(pkg.Imports))
+		for path := range pkg.Imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			act.deps = append(act.deps, d.action(a, pkg.Imports[path]))
+		}
+	}
+	return act
+}
+
+// exec runs the action after its dependencies (once), and returns its error.
+func (act *action) exec() error {
+	if act.done {
+		return act.err
+	}
+	act.done = true
+
+	inputs := make(map[*analysis.Analyzer]any)
+	act.objectFacts = make(map[objectFactKey]analysis.Fact)
+	act.packageFacts = make(map[packageFactKey]analysis.Fact)
+	for _, dep := range act.deps {
+		if err := dep.exec(); err != nil {
+			act.err = fmt.Errorf("%s on %s: %w", dep.analyzer.Name, dep.pkg.PkgPath, err)
+			return act.err
+		}
+		if dep.pkg == act.pkg {
+			inputs[dep.analyzer] = dep.result
+			continue
+		}
+		// The facts of the dependencies are visible to the dependents.
+		for k, v := range dep.objectFacts {
+			act.objectFacts[k] = v
+		}
+		for k, v := range dep.packageFacts {
+			act.packageFacts[k] = v
+		}
+	}
+
+	if act.pkg.IllTyped && !act.analyzer.RunDespiteErrors {
+		// Dependencies with type errors are skipped without facts, similar to other drivers.
+		return nil
+	}
+
+	module := &analysis.Module{}
+	if m := act.pkg.Module; m != nil {
+		module.Path, module.Version, module.GoVersion = m.Path, m.Version, m.GoVersion
+	}
+	pass := &analysis.Pass{
+		Analyzer:     act.analyzer,
+		Fset:         act.pkg.Fset,
+		Files:        act.pkg.Syntax,
+		OtherFiles:   act.pkg.OtherFiles,
+		IgnoredFiles: act.pkg.IgnoredFiles,
+		Pkg:          act.pkg.Types,
+		TypesInfo:    act.pkg.TypesInfo,
+		TypesSizes:   act.pkg.TypesSizes,
+		TypeErrors:   act.pkg.TypeErrors,
+		Module:       module,
+		ResultOf:     inputs,
+		Report:       func(d analysis.Diagnostic) { act.diagnostics = append(act.diagnostics, d) },
+		ImportObjectFact: func(obj types.Object, fact analysis.Fact) bool {
+			return importFact(act.objectFacts[objectFactKey{obj: obj, typ: reflect.TypeOf(fact)}], fact)
+		},
+		ExportObjectFact: func(obj types.Object, fact analysis.Fact) {
+			act.objectFacts[objectFactKey{obj: obj, typ: reflect.TypeOf(fact)}] = fact
+		},
+		ImportPackageFact: func(pkg *types.Package, fact analysis.Fact) bool {
+			return importFact(act.packageFacts[packageFactKey{pkg: pkg, typ: reflect.TypeOf(fact)}], fact)
+		},
+		ExportPackageFact: func(fact analysis.Fact) {
+			act.packageFacts[packageFactKey{pkg: act.pkg.Types, typ: reflect.TypeOf(fact)}] = fact
+		},
+		AllObjectFacts: func() []analysis.ObjectFact {
+			facts := make([]analysis.ObjectFact, 0, len(act.objectFacts))
+			for k, v := range act.objectFacts {
+				facts = append(facts, analysis.ObjectFact{Object: k.obj, Fact: v})
+			}
+			return facts
+		},
+		AllPackageFacts: func() []analysis.PackageFact {
+			facts := make([]analysis.PackageFact, 0, len(act.packageFacts))
+			for k, v := range act.packageFacts {
+				facts = append(facts, analysis.PackageFact{Package: k.pkg, Fact: v})
+			}
+			return facts
+		},
+	}
+
+	act.result, act.err = act.analyzer.Run(pass)
+	return act.err
+}
+
+// importFact copies the stored fact (if any) to the fact pointer, and returns whether it exists.
+func importFact(stored analysis.Fact, fact analysis.Fact) bool {
+	if stored == nil {
+		return false
+	}
+	reflect.ValueOf(fact).Elem().Set(reflect.ValueOf(stored).Elem())
+	return true
+}
@@ -0,0 +1,88 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/nilaway"
+)
+
+//nolint:paralleltest
+func TestRunStdin(t *testing.T) {
+	// This test sets the environment variables for loading the packages in GOPATH mode from the
+	// testdata directory, hence it cannot be run in parallel.
+	testdata, err := filepath.Abs(filepath.Join("..", "..", "testdata"))
+	require.NoError(t, err)
+	t.Setenv("GOPATH", testdata)
+	t.Setenv("GO111MODULE", "off")
+
+	fileName := filepath.Join(testdata, "src", "go.uber.org", "helloworld", "helloworld.go")
+	// The unsaved buffer (which differs from the file on disk) dereferences a nil pointer.
+	buffer := `package helloworld
+
+import "fmt"
+
+func main() {
+	var p *int
+	fmt.Println(*p)
+}
+`
+	var out, errOut strings.Builder
+	n, err := runStdin(nilaway.Analyzer, "go.uber.org/helloworld", fileName, strings.NewReader(buffer), &out, &errOut)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Contains(t, out.String(), fileName+":7:15: ")
+	require.Contains(t, out.String(), "dereferenced")
+	require.Empty(t, errOut.String())
+
+	// Only the contents of the buffer are analyzed, which no longer have the dereference.
+	n, err = runStdin(nilaway.Analyzer, "go.uber.org/helloworld", fileName, strings.NewReader("package helloworld\n"), &out, &errOut)
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	_, err = runStdin(nilaway.Analyzer, "", fileName, strings.NewReader(buffer), &out, &errOut)
+	require.ErrorContains(t, err, "-pkg-path")
+
+	// Buffers with type errors are partially analyzed: the functions referencing broken objects
+	// are skipped, while the others are still analyzed.
+	buffer = `package helloworld
+
+import "fmt"
+
+func main() {
+	var p *int
+	fmt.Println(*p)
+}
+
+func broken() {
+	var p *int
+	fmt.Println(*p, undefined)
+}
+`
+	out.Reset()
+	n, err = runStdin(nilaway.Analyzer, "go.uber.org/helloworld", fileName, strings.NewReader(buffer), &out, &errOut)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Contains(t, out.String(), fileName+":7:15: ")
+	require.NotContains(t, out.String(), fileName+":12:15: ")
+	require.NotContains(t, out.String(), "INTERNAL")
+	// The notice about the partial analysis is not mixed with the diagnostics.
+	require.NotContains(t, out.String(), "partially analyzed")
+	require.Equal(t, "nilaway: package go.uber.org/helloworld partially analyzed: 1 function(s) skipped due to type errors\n", errOut.String())
+}
@@ -0,0 +1,288 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/nilaway"
+	"go.uber.org/nilaway/accumulation"
+	"go.uber.org/nilaway/assertion/affiliation"
+	"go.uber.org/nilaway/assertion/function"
+	"go.uber.org/nilaway/assertion/function/functioncontracts"
+	"go.uber.org/nilaway/config"
+	"go.uber.org/nilaway/inference"
+	"golang.org/x/tools/go/analysis"
+)
+
+// _summary is a driver flag for printing a summary of the analysis at the end of the run (see
+// summary).
+var _summary bool
+
+// _summaryTopPackages is the number of packages with the most diagnostics listed in the summary.
+const _summaryTopPackages = 5
+
+// _uncategorized is the code of the diagnostics without a category in the summary.
+const _uncategorized = "uncategorized"
+
+// summary collects the statistics of the analysis, i.e., the diagnostics by their codes (i.e.,
+// categories) and by packages, the inferred nilable sites, the functions skipped due to the size
+// limit or type errors, the packages whose analysis is aborted due to type errors, the enabled experiments, and the time spent in each of the main analyzers, and writes them
+// once all the root packages are completed. It is safe for concurrent use, since the packages are analyzed in
+// parallel.
+type summary struct {
+	mu  sync.Mutex
+	out io.Writer
+	// now returns the current time, which is replaceable for testing.
+	now func() time.Time
+	// roots is the number of root packages, 0 if unknown (and the summary is not written).
+	roots     int
+	rootsDone int
+
+	codes        map[string]int
+	packages     map[string]int
+	nilableSites int
+	skippedFuncs int
+	// typeErrorFuncs and typeErrorPackages are the numbers of the functions skipped and the
+	// packages whose analysis is aborted due to type errors, respectively.
+	typeErrorFuncs    int
+	typeErrorPackages int
+	// experiments are the enabled experiments (see config.Experiment), which are the same for all
+	// packages.
+	experiments []config.Experiment
+	// timed are the names of the timed analyzers in the order of display.
+	timed     []string
+	durations map[string]time.Duration
+}
+
+// newSummary returns a new summary writing to out.
+func newSummary(out io.Writer, roots int, now func() time.Time) *summary {
+	return &summary{
+		out:       out,
+		now:       now,
+		roots:     roots,
+		codes:     make(map[string]int),
+		packages:  make(map[string]int),
+		durations: make(map[string]time.Duration),
+	}
+}
+
+// diagnosticReported records a diagnostic reported for the package.
+func (s *summary) diagnosticReported(pkgPath string, d analysis.Diagnostic) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	code := d.Category
+	if code == "" {
+		code = _uncategorized
+	}
+	s.codes[code]++
+	s.packages[pkgPath]++
+}
+
+// packageAnalyzed records the numbers of inferred nilable sites and skipped functions of a package.
+func (s *summary) packageAnalyzed(nilableSites, skippedFuncs int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nilableSites += nilableSites
+	s.skippedFuncs += skippedFuncs
+}
+
+// typeErrors records the number of the functions of a package skipped due to its type errors,
+// and whether its analysis is aborted due to them.
+func (s *summary) typeErrors(skippedFuncs int, aborted bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.typeErrorFuncs += skippedFuncs
+	if aborted {
+		s.typeErrorPackages++
+	}
+}
+
+// configured records the experiments enabled by the configuration.
+func (s *summary) configured(experiments []config.Experiment) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.experiments = experiments
+}
+
+// analyzerDone records the time spent in a run of the timed analyzer with the given name.
+func (s *summary) analyzerDone(name string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.durations[name] += d
+}
+
+// rootDone records the completion of a root package, and writes the summary once all the root
+// packages are completed.
+func (s *summary) rootDone() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rootsDone++
+	if s.rootsDone != s.roots {
+		return
+	}
+
+	total := 0
+	codes := make([]string, 0, len(s.codes))
+	for code, n := range s.codes {
+		total += n
+		codes = append(codes, fmt.Sprintf("%s: %d", code, n))
+	}
+	slices.Sort(codes)
+	fmt.Fprintf(s.out, "nilaway: summary\n")
+	fmt.Fprintf(s.out, "  diagnostics: %d", total)
+	if total > 0 {
+		fmt.Fprintf(s.out, " (%s)", strings.Join(codes, ", "))
+	}
+	fmt.Fprintln(s.out)
+
+	if len(s.packages) > 0 {
+		pkgs := make([]string, 0, len(s.packages))
+		for pkg := range s.packages {
+			pkgs = append(pkgs, pkg)
+		}
+		slices.SortFunc(pkgs, func(a, b string) int {
+			return cmp.Or(cmp.Compare(s.packages[b], s.packages[a]), cmp.Compare(a, b))
+		})
+		top := make([]string, 0, _summaryTopPackages)
+		for _, pkg := range pkgs[:min(len(pkgs), _summaryTopPackages)] {
+			top = append(top, fmt.Sprintf("%s (%d)", pkg, s.packages[pkg]))
+		}
+		fmt.Fprintf(s.out, "  top packages: %s\n", strings.Join(top, ", "))
+	}
+	fmt.Fprintf(s.out, "  inferred nilable sites: %d\n", s.nilableSites)
+	fmt.Fprintf(s.out, "  functions skipped due to size limit: %d\n", s.skippedFuncs)
+	fmt.Fprintf(s.out, "  functions skipped due to type errors: %d\n", s.typeErrorFuncs)
+	fmt.Fprintf(s.out, "  packages aborted due to type errors: %d\n", s.typeErrorPackages)
+	experiments := "none"
+	if len(s.experiments) > 0 {
+		names := make([]string, len(s.experiments))
+		for i, e := range s.experiments {
+			names[i] = string(e)
+		}
+		experiments = strings.Join(names, ", ")
+	}
+	fmt.Fprintf(s.out, "  experiments: %s\n", experiments)
+	times := make([]string, 0, len(s.timed))
+	for _, name := range s.timed {
+		times = append(times, fmt.Sprintf("%s %s", name, s.durations[name].Round(time.Millisecond)))
+	}
+	fmt.Fprintf(s.out, "  time per analyzer: %s\n", strings.Join(times, ", "))
+}
+
+// time wraps the Run function of the analyzer to record the time spent in it under the name.
+func (s *summary) time(name string, a *analysis.Analyzer) {
+	s.timed = append(s.timed, name)
+	run := a.Run
+	a.Run = func(pass *analysis.Pass) (interface{}, error) {
+		start := s.now()
+		defer func() { s.analyzerDone(name, s.now().Sub(start)) }()
+		return run(pass)
+	}
+}
+
+// wrap wraps the Run functions of the main analyzers to time them, the config analyzer to record
+// the enabled experiments, the function analyzer to count the skipped functions, the accumulation analyzer to count the inferred nilable sites
+// and the functions skipped due to type errors, and the
+// top-level analyzer (which only runs on the root packages) to count the reported diagnostics.
+func (s *summary) wrap() {
+	s.time("config", config.Analyzer)
+	s.time("functioncontracts", functioncontracts.Analyzer)
+	s.time("affiliation", affiliation.Analyzer)
+	s.time("function", function.Analyzer)
+	s.time("accumulation", accumulation.Analyzer)
+
+	confRun := config.Analyzer.Run
+	config.Analyzer.Run = func(pass *analysis.Pass) (interface{}, error) {
+		result, err := confRun(pass)
+		if conf, ok := result.(*config.Config); ok && conf != nil {
+			s.configured(conf.EnabledExperiments())
+		}
+		return result, err
+	}
+	funcRun := function.Analyzer.Run
+	function.Analyzer.Run = func(pass *analysis.Pass) (interface{}, error) {
+		if conf, ok := pass.ResultOf[config.Analyzer].(*config.Config); ok && conf.IsPkgInScope(pass.Pkg) && !conf.IsPkgStubbed(pass.Pkg) {
+			s.packageAnalyzed(0, skippedFuncs(pass, conf))
+		}
+		return funcRun(pass)
+	}
+	accRun := accumulation.Analyzer.Run
+	accumulation.Analyzer.Run = func(pass *analysis.Pass) (interface{}, error) {
+		result, err := accRun(pass)
+		if r, ok := result.(*accumulation.Result); ok && r != nil {
+			if r.InferredMap != nil {
+				s.packageAnalyzed(nilableSites(r.InferredMap.Facts(pass.Pkg.Path())), 0)
+			}
+			s.typeErrors(r.SkippedForTypeErrors, r.AbortedForTypeErrors)
+		}
+		return result, err
+	}
+	rootRun := nilaway.Analyzer.Run
+	nilaway.Analyzer.Run = func(pass *analysis.Pass) (interface{}, error) {
+		defer s.rootDone()
+		report := pass.Report
+		pass.Report = func(d analysis.Diagnostic) {
+			s.diagnosticReported(pass.Pkg.Path(), d)
+			report(d)
+		}
+		return rootRun(pass)
+	}
+}
+
+// skippedFuncs returns the number of the functions in the files in scope of the package that the
+// function analyzer skips due to the size limit (see function.IsTooLarge).
+func skippedFuncs(pass *analysis.Pass, conf *config.Config) int {
+	n := 0
+	for _, file := range pass.Files {
+		if !conf.IsFileInScope(file) {
+			continue
+		}
+		for _, decl := range file.Decls {
+			if f, ok := decl.(*ast.FuncDecl); ok && f.Body != nil && function.IsTooLarge(f.Body) {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// nilableSites returns the number of the nilable sites (shallow or deep) in the facts.
+func nilableSites(facts *inference.FactsFile) int {
+	n := 0
+	for _, site := range facts.Sites {
+		if site.Shallow == inference.FactsNilable {
+			n++
+		}
+		if site.Deep == inference.FactsNilable {
+			n++
+		}
+	}
+	return n
+}
+
+// enableSummary enables writing the summary to out at the end of the analysis of the packages given
+// in the command line arguments (see countPackages).
+func enableSummary(args []string, out io.Writer) {
+	_, roots := countPackages(args)
+	newSummary(out, roots, time.Now).wrap()
+}
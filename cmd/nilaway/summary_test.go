@@ -0,0 +1,72 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/nilaway/config"
+	"go.uber.org/nilaway/inference"
+	"golang.org/x/tools/go/analysis"
+)
+
+func TestSummary(t *testing.T) {
+	t.Parallel()
+
+	var out strings.Builder
+	s := newSummary(&out, 2, time.Now)
+	s.timed = []string{"config", "function"}
+
+	s.analyzerDone("config", 1200*time.Microsecond)
+	s.analyzerDone("function", 2*time.Second)
+	s.analyzerDone("function", 1*time.Second)
+	s.packageAnalyzed(3, 0)
+	s.packageAnalyzed(2, 1)
+	s.typeErrors(2, false)
+	s.typeErrors(0, true)
+	s.configured([]config.Experiment{config.ExperimentAnonymousFunction, config.ExperimentGroupResults})
+	for _, pkg := range []string{"example.com/a", "example.com/b", "example.com/b", "example.com/c", "example.com/d", "example.com/e", "example.com/f"} {
+		s.diagnosticReported(pkg, analysis.Diagnostic{})
+	}
+	s.diagnosticReported("example.com/a", analysis.Diagnostic{Category: "recovered"})
+	s.rootDone()
+	require.Empty(t, out.String(), "the summary must only be written once all the root packages are completed")
+	s.rootDone()
+
+	require.Equal(t, `nilaway: summary
+  diagnostics: 8 (recovered: 1, uncategorized: 7)
+  top packages: example.com/a (2), example.com/b (2), example.com/c (1), example.com/d (1), example.com/e (1)
+  inferred nilable sites: 5
+  functions skipped due to size limit: 1
+  functions skipped due to type errors: 2
+  packages aborted due to type errors: 1
+  experiments: anonymous-function, group-results
+  time per analyzer: config 1ms, function 3s
+`, out.String())
+}
+
+func TestNilableSites(t *testing.T) {
+	t.Parallel()
+
+	facts := &inference.FactsFile{Sites: []inference.FactsSite{
+		{Shallow: inference.FactsNilable, Deep: inference.FactsNilable},
+		{Shallow: inference.FactsNonnil, Deep: inference.FactsNilable},
+		{Shallow: inference.FactsNonnil},
+	}}
+	require.Equal(t, 3, nilableSites(facts))
+}
@@ -0,0 +1,153 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
+
+	"go.uber.org/nilaway"
+	"go.uber.org/nilaway/accumulation"
+	"go.uber.org/nilaway/suppression"
+	"golang.org/x/tools/go/analysis"
+)
+
+// _suppressionReport is a driver flag for printing the numbers of the errors suppressed by each
+// mechanism at the end of the run (see suppressionReport).
+var _suppressionReport bool
+
+// _suppressionCountsEnv is the environment variable naming the file that the analysis run by the
+// driver under an exit policy (see mainExitPolicy) writes the suppression counts to in JSON, in
+// place of the report. The driver then adds the errors accepted by the baseline, which are only
+// known once the run completes, and writes the report itself (see exitPolicy.exitCode).
+const _suppressionCountsEnv = "NILAWAY_SUPPRESSION_COUNTS"
+
+// suppressionReport collects the numbers of the errors suppressed by each mechanism (see
+// suppression.Mechanism) in the root packages, and writes them once all the root packages are
+// completed. It is safe for concurrent use, since the packages are analyzed in parallel.
+type suppressionReport struct {
+	mu sync.Mutex
+	// write writes the counts once all the root packages are completed.
+	write func(counts suppression.Counts)
+	// roots is the number of root packages, 0 if unknown (and the report is not written).
+	roots     int
+	rootsDone int
+	counts    suppression.Counts
+}
+
+// newSuppressionReport returns a new suppression report writing to out.
+func newSuppressionReport(out io.Writer, roots int) *suppressionReport {
+	return &suppressionReport{
+		write:  func(counts suppression.Counts) { writeSuppressionReport(out, counts) },
+		roots:  roots,
+		counts: make(suppression.Counts),
+	}
+}
+
+// rootDone records the errors suppressed in a root package, and writes the report once all the
+// root packages are completed.
+func (r *suppressionReport) rootDone(suppressed suppression.Counts) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.counts.Add(suppressed)
+	r.rootsDone++
+	if r.rootsDone == r.roots {
+		r.write(r.counts)
+	}
+}
+
+// writeSuppressionReport writes the total number of the suppressed errors to out, followed by
+// the numbers for each mechanism.
+func writeSuppressionReport(out io.Writer, counts suppression.Counts) {
+	total := 0
+	mechanisms := make([]suppression.Mechanism, 0, len(counts))
+	for m, n := range counts {
+		total += n
+		mechanisms = append(mechanisms, m)
+	}
+	// List the mechanisms suppressing the most errors first.
+	slices.SortFunc(mechanisms, func(a, b suppression.Mechanism) int {
+		return cmp.Or(cmp.Compare(counts[b], counts[a]), cmp.Compare(a, b))
+	})
+	fmt.Fprintf(out, "nilaway: %d error(s) suppressed\n", total)
+	for _, m := range mechanisms {
+		fmt.Fprintf(out, "  %s: %d\n", m, counts[m])
+	}
+}
+
+// wrap wraps the Run function of the top-level analyzer (which only runs on the root packages) to
+// collect the errors suppressed in them by the accumulation analyzer. The dependencies are not
+// accounted, since their errors are never reported anyway.
+func (r *suppressionReport) wrap(root *analysis.Analyzer) {
+	rootRun := root.Run
+	root.Run = func(pass *analysis.Pass) (interface{}, error) {
+		var suppressed suppression.Counts
+		if res, ok := pass.ResultOf[accumulation.Analyzer].(*accumulation.Result); ok && res != nil {
+			suppressed = res.Suppressed
+		}
+		defer r.rootDone(suppressed)
+		return rootRun(pass)
+	}
+}
+
+// enableSuppressionReport enables writing the suppression report to out at the end of the analysis
+// of the packages given in the command line arguments (see countPackages).
+func enableSuppressionReport(args []string, out io.Writer) {
+	_, roots := countPackages(args)
+	r := newSuppressionReport(out, roots)
+	if path := os.Getenv(_suppressionCountsEnv); path != "" {
+		r.write = func(counts suppression.Counts) {
+			if err := writeSuppressionCounts(path, counts); err != nil {
+				fmt.Fprintf(out, "nilaway: %v\n", err)
+			}
+		}
+	}
+	r.wrap(nilaway.Analyzer)
+}
+
+// writeSuppressionCounts writes the suppression counts to the file in JSON (see
+// _suppressionCountsEnv).
+func writeSuppressionCounts(path string, counts suppression.Counts) error {
+	data, err := json.Marshal(counts)
+	if err != nil {
+		return fmt.Errorf("write suppression counts: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write suppression counts: %w", err)
+	}
+	return nil
+}
+
+// readSuppressionCounts reads the suppression counts written by writeSuppressionCounts, which are
+// empty if the file is empty (i.e., no root packages were analyzed).
+func readSuppressionCounts(path string) (suppression.Counts, error) {
+	counts := make(suppression.Counts)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read suppression counts: %w", err)
+	}
+	if len(data) == 0 {
+		return counts, nil
+	}
+	if err := json.Unmarshal(data, &counts); err != nil {
+		return nil, fmt.Errorf("parse suppression counts: %w", err)
+	}
+	return counts, nil
+}
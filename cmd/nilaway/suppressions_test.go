@@ -0,0 +1,57 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/nilaway/suppression"
+)
+
+func TestSuppressionReport(t *testing.T) {
+	t.Parallel()
+
+	var out strings.Builder
+	r := newSuppressionReport(&out, 3)
+	r.rootDone(suppression.Counts{suppression.Generated: 2, suppression.RecoveredPanics: 1})
+	r.rootDone(nil)
+	require.Empty(t, out.String(), "the report must only be written once all the root packages are completed")
+	r.rootDone(suppression.Counts{suppression.ExcludeErrorsInFiles: 3, suppression.RecoveredPanics: 1})
+
+	require.Equal(t, `nilaway: 7 error(s) suppressed
+  exclude-errors-in-files: 3
+  generated: 2
+  recovered-panics: 2
+`, out.String())
+}
+
+func TestSuppressionCounts(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "counts.json")
+	require.NoError(t, os.WriteFile(path, nil, 0o644))
+	counts, err := readSuppressionCounts(path)
+	require.NoError(t, err)
+	require.Empty(t, counts, "no root packages were analyzed")
+
+	require.NoError(t, writeSuppressionCounts(path, suppression.Counts{suppression.Generated: 2}))
+	counts, err = readSuppressionCounts(path)
+	require.NoError(t, err)
+	require.Equal(t, suppression.Counts{suppression.Generated: 2}, counts)
+}
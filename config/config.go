@@ -17,12 +17,20 @@ package config
 
 import (
 	"flag"
+	"fmt"
 	"go/ast"
 	"go/types"
+	"maps"
+	"path"
+	"path/filepath"
 	"reflect"
+	"regexp"
+	"slices"
 	"strings"
 
+	"go.uber.org/nilaway/suppression"
 	"go.uber.org/nilaway/util/asthelper"
+	"go.uber.org/nilaway/util/sourcemap"
 	"golang.org/x/tools/go/analysis"
 )
 
@@ -32,10 +40,138 @@ type Config struct {
 	PrettyPrint bool
 	// GroupErrorMessages indicates whether similar error messages should be grouped.
 	GroupErrorMessages bool
-	// ExperimentalStructInitEnable indicates whether experimental struct initialization is enabled.
-	ExperimentalStructInitEnable bool
-	// ExperimentalAnonymousFuncEnable indicates whether experimental anonymous function support is enabled.
-	ExperimentalAnonymousFuncEnable bool
+	// experiments is the set of the enabled experiments (see [Config.Experiment]).
+	experiments map[Experiment]bool
+	// CheckStructInit is how the struct initializations are checked: not at all
+	// (CheckStructInitOff, the default), with the fields left uninitialized tracked to their
+	// dereferences (CheckStructInitReport), or tracked but only reported as a count per package
+	// (CheckStructInitCount), e.g., for estimating the errors before turning the check on.
+	CheckStructInit string
+	// DisableParseCache indicates whether the memoization of expression parsing during
+	// backpropagation should be disabled. This is only meant for debugging NilAway itself.
+	DisableParseCache bool
+	// MaxTreeWidth is the maximum number of tracked expressions at the root of an assertion tree,
+	// 0 means no limit.
+	MaxTreeWidth int
+	// FixMode is the mode for generating suggested fixes for the diagnostics, empty means no fixes.
+	FixMode string
+	// FixPolicy is the policy for choosing the shape of the nil guards in FixModeGuard.
+	FixPolicy string
+	// PackageOverrides is the list of the package overrides (see PackageOverridesFlag) applied to
+	// the current package, in the order they are applied.
+	PackageOverrides []PackageOverride
+	// SidecarAnnotations is the list of annotations read from the sidecar annotation files, after
+	// the models of the standard library (see stdlib.annotations).
+	SidecarAnnotations []SidecarAnnotation
+	// stubPkgs is the set of the paths of the packages stubbed by the stub directories (see
+	// [Config.IsPkgStubbed]).
+	stubPkgs map[string]bool
+	// IncludeGenerated indicates whether the errors in generated files (i.e., files with the
+	// standard "// Code generated ... DO NOT EDIT." header) should be reported.
+	IncludeGenerated bool
+	// ExcludeTests indicates whether the errors in test files should not be reported.
+	ExcludeTests bool
+	// TestsOnly indicates whether only the errors in test files should be reported.
+	TestsOnly bool
+	// ExcludeMocks indicates whether the errors in the mocks (i.e., the packages and files named
+	// after the conventions of gomock and mockery, see IsMockPath) should not be reported.
+	ExcludeMocks bool
+	// ExportFactsDir is the directory to write the final nilabilities of the annotation sites of
+	// each analyzed package to (see inference.FactsFile), empty means no export.
+	ExportFactsDir string
+	// ImportFactsDir is the directory to read the externally produced nilabilities of the
+	// annotation sites of each analyzed package from (see inference.FactsFile), which seed the
+	// inference. Empty means no import.
+	ImportFactsDir string
+	// DebugDeps indicates whether the diagnostics should list the upstream packages (and the
+	// objects in them) that contributed facts to the errors.
+	DebugDeps bool
+	// MessageTemplate is either the name of a built-in message template ("verbose" or "short") or
+	// the text of a custom Go text/template for rendering the diagnostics, empty means the default
+	// (verbose) layout. See diagnostic.MessageData for the data available to the templates.
+	MessageTemplate string
+	// PathFormat is the format of the file paths in the error messages: "short" (the default,
+	// only the enclosing directory is kept), "absolute", "module" (relative to the module root), or
+	// a Go text/template for links (see diagnostic.PathData for the available fields).
+	PathFormat string
+	// ExplainProvenance indicates whether the diagnostics of the inferred conflicts should explain
+	// the root causes (e.g., a nil literal passed at some position) that forced the conflicting
+	// site to be nilable and nonnil.
+	ExplainProvenance bool
+	// DebugDumpDir is the directory to write the DOT graphs of the preprocessed CFGs, the
+	// assertion trees in each round of backpropagation, and the final full triggers of the selected
+	// functions to, empty means disabled.
+	DebugDumpDir string
+	// DebugDumpFuncs selects the functions to dump by matching their full names, nil means all.
+	DebugDumpFuncs *regexp.Regexp
+	// ReflectEscape indicates whether the values escaping via reflection (`reflect.ValueOf`),
+	// `unsafe.Pointer` conversions, or cgo calls should be conservatively presumed nonnil.
+	ReflectEscape bool
+	// ErrorOnNilableMapRead indicates whether the reads from the possibly-nil maps (e.g., `m[k]`)
+	// should be reported, although they are safe (and give the zero values) in Go.
+	ErrorOnNilableMapRead bool
+	// ReportAt is where the errors on the results of the functions inferred to return nonnil
+	// results (since the callers dereference them) are reported: at the dereferences (ReportAtSink,
+	// the default), at the offending return statements (ReportAtSource), or both (ReportAtBoth).
+	ReportAt string
+	// ReportPositionPolicy is the policy for choosing the positions of the errors among the
+	// production sites and the consumption sites of the nil flows (see ReportPositionConsumption,
+	// ReportPositionProduction and ReportPositionModule).
+	ReportPositionPolicy string
+	// DefaultNilability is the nilability that inference assigns to the unconstrained parameters
+	// (i.e., the parameters of the functions not called in the package): none, i.e., they stay
+	// optimistically unconstrained (DefaultNilabilityOptimistic, the default), nilable
+	// (DefaultNilabilityPessimistic), or nilable for the exported functions only
+	// (DefaultNilabilityPessimisticExports).
+	DefaultNilability string
+	// StrictExports indicates whether the parameters of the exported functions should be treated
+	// as nilable regardless of inference (since the callers outside the analysis may pass nil),
+	// unless they are annotated as nonnil.
+	StrictExports bool
+	// ReportRedundantChecks indicates whether the nil checks on the values that are always nonnil
+	// (e.g., `x != nil` right after `x := &T{}`) should be reported as redundant.
+	ReportRedundantChecks bool
+	// RecoveredPanics is how the errors at the dereferences in the regions recovering from panics
+	// (i.e., after a `defer` of a function calling `recover()` without re-panicking) are reported:
+	// as usual (RecoveredPanicsReport, the default), downgraded (RecoveredPanicsDowngrade), or not
+	// at all (RecoveredPanicsSuppress).
+	RecoveredPanics string
+	// RequireSourceInScope is how the errors whose nil sources are in the packages outside the
+	// analysis scope (e.g., excluded by ExcludePkgsFlag) are reported: as usual
+	// (SourceScopeReport, the default), with a "source outside scope" note in the messages
+	// (SourceScopeAnnotate), or not at all (SourceScopeSuppress).
+	RequireSourceInScope string
+	// AssignmentFlows is how the assignment flows in the error messages are printed: with the
+	// trivial single-use temporaries elided (AssignmentFlowsSummary, the default), or in full
+	// (AssignmentFlowsFull).
+	AssignmentFlows string
+	// CrossPackageDedup indicates whether the errors fully determined by the upstream facts should
+	// only be reported by the first package reporting them (in the dependency order), which is
+	// only sound if all analyzed packages are reported (see CrossPackageDedupFlag).
+	CrossPackageDedup bool
+	// DisableLineDirectives indicates whether the positions in the error messages should be the
+	// ones in the (generated) files analyzed, instead of the ones in the authored source files
+	// (e.g., yacc grammars or templ templates) that the "//line" directives point back to.
+	DisableLineDirectives bool
+	// InferenceMode is how the packages are analyzed: with the full inference across packages
+	// (InferenceModeFull, the default), or with the fast analysis of every package on its own
+	// (InferenceModeSinglePackage), e.g., for the editors.
+	InferenceMode string
+	// ModelPackRules is the list of the rules read from the built-in model packs enabled by
+	// ModelPacksFlag and the model pack files given by ModelPackFilesFlag, which model the functions
+	// of the libraries that NilAway does not analyze.
+	ModelPackRules []ModelPackRule
+	// MemoryPressureThreshold is the fraction of the soft memory limit of the Go runtime (i.e.,
+	// GOMEMLIMIT) above which the packages are analyzed in a degraded mode, 0 means never.
+	MemoryPressureThreshold float64
+	// Degraded indicates whether the current package is analyzed in a degraded mode (i.e., with
+	// DegradedMaxTreeWidth and without the struct initialization checks) since the memory in use
+	// exceeded MemoryPressureThreshold when the analysis of the package started.
+	Degraded bool
+	// ErrorFilter suppresses the errors in the files not matching the file prefixes given by
+	// IncludeErrorsInFilesFlag, or matching the ones given by ExcludeErrorsInFilesFlag. It is
+	// consulted by the sub-analyzers, so all drivers share the same filtering semantics.
+	ErrorFilter *suppression.Filter
 
 	// includePkgs is the list of packages to analyze.
 	includePkgs []string
@@ -46,6 +182,18 @@ type Config struct {
 	// string, will cause the file to be excluded from analysis. Examples include "@generated" and
 	// "Code generated by".
 	excludeFileDocStrings []string
+	// testFiles is the set of test files (i.e., "_test.go" files) of the current package.
+	testFiles map[*ast.File]bool
+	// mappedFiles is the set of generated files of the current package whose positions are
+	// mapped back to the authored sources for reporting, i.e., the files rewritten by cgo from the
+	// original source files (i.e., the ones with `import "C"`), and (unless DisableLineDirectives
+	// is set) the files with "//line" directives or registered source mappers (see
+	// sourcemap.Register). See [Config.IsFileReported].
+	mappedFiles map[*ast.File]bool
+	// mockPkg indicates whether the current package is a package of mocks (see IsMockPath).
+	mockPkg bool
+	// mockFiles is the set of the files of mocks (see IsMockPath) of the current package.
+	mockFiles map[*ast.File]bool
 }
 
 // IsPkgInScope returns true iff the passed package is in scope for analysis, i.e., it is in the
@@ -54,14 +202,19 @@ func (c *Config) IsPkgInScope(pkg *types.Package) bool {
 	if pkg == nil {
 		return false
 	}
+	return c.IsPkgPathInScope(pkg.Path())
+}
 
+// IsPkgPathInScope returns true iff the package with the given import path is in scope for
+// analysis (see IsPkgInScope).
+func (c *Config) IsPkgPathInScope(pkgPath string) bool {
 	for _, include := range c.includePkgs {
-		if !strings.HasPrefix(pkg.Path(), include) {
+		if !strings.HasPrefix(pkgPath, include) {
 			continue
 		}
 
 		for _, exclude := range c.excludePkgs {
-			if strings.HasPrefix(pkg.Path(), exclude) {
+			if strings.HasPrefix(pkgPath, exclude) {
 				return false
 			}
 		}
@@ -71,6 +224,29 @@ func (c *Config) IsPkgInScope(pkg *types.Package) bool {
 	return false
 }
 
+// Experiment returns true iff the given experiment is enabled.
+func (c *Config) Experiment(e Experiment) bool {
+	return c.experiments[e]
+}
+
+// EnabledExperiments returns the enabled experiments, in the order of Experiments.
+func (c *Config) EnabledExperiments() []Experiment {
+	var enabled []Experiment
+	for _, e := range Experiments {
+		if c.experiments[e] {
+			enabled = append(enabled, e)
+		}
+	}
+	return enabled
+}
+
+// IsPkgStubbed returns true iff the passed package is stubbed by a stub package in the stub
+// directories, in which case its real source is not analyzed and the annotations of the stub
+// package (which are added to SidecarAnnotations) are used instead.
+func (c *Config) IsPkgStubbed(pkg *types.Package) bool {
+	return pkg != nil && c.stubPkgs[pkg.Path()]
+}
+
 // IsFileInScope returns true iff we should analyze the file. It checks the docstring of the file
 // and returns false if any of the strings in ExcludeFileDocStrings appear in the file docstring.
 func (c *Config) IsFileInScope(file *ast.File) bool {
@@ -95,6 +271,52 @@ func (c *Config) IsFileInScope(file *ast.File) bool {
 	return true
 }
 
+// IsFileReported returns true iff the errors in the file should be reported. The files not
+// reported are still analyzed (such that the nilability flowing through them is known), this
+// includes generated files (see [ast.IsGenerated]) unless IncludeGenerated is set since they
+// cannot be fixed in place (except for the ones whose errors are reported on the authored sources,
+// e.g., the files rewritten by cgo or the templates of templ, see mappedFiles), test files if
+// ExcludeTests is set (or non-test files if TestsOnly is set), and mocks if ExcludeMocks is set.
+func (c *Config) IsFileReported(file *ast.File) bool {
+	return c.FileSuppressedBy(file) == ""
+}
+
+// FileSuppressedBy returns the mechanism suppressing the errors in the file (see IsFileReported),
+// or an empty string if they are reported.
+func (c *Config) FileSuppressedBy(file *ast.File) suppression.Mechanism {
+	if !c.IncludeGenerated && ast.IsGenerated(file) && !c.mappedFiles[file] {
+		return suppression.Generated
+	}
+	if c.ExcludeTests && c.testFiles[file] {
+		return suppression.ExcludeTests
+	}
+	if c.TestsOnly && !c.testFiles[file] {
+		return suppression.TestsOnly
+	}
+	if c.ExcludeMocks && (c.mockPkg || c.mockFiles[file]) {
+		return suppression.ExcludeMocks
+	}
+	return ""
+}
+
+var (
+	// _mockPkgRegex matches the last elements of the paths of the packages of mocks, e.g., "mocks"
+	// (mockery), "mock_store" (gomock in the reflect mode) or "storemocks".
+	_mockPkgRegex = regexp.MustCompile(`^(mocks?|mock_\w+|\w+mocks)$`)
+	// _mockFileRegex matches the names of the files of mocks, e.g., "mock_store.go" (mockgen and
+	// mockery) or "store_mock.go".
+	_mockFileRegex = regexp.MustCompile(`^(mock_\w+|\w+_mocks?)(_test)?\.go$`)
+)
+
+// IsMockPath returns true iff the package path (or the file name if it is not empty) follows the
+// naming conventions of the mocks generated by gomock and mockery.
+func IsMockPath(pkgPath, fileName string) bool {
+	if fileName != "" {
+		return _mockFileRegex.MatchString(filepath.Base(fileName))
+	}
+	return _mockPkgRegex.MatchString(path.Base(pkgPath))
+}
+
 const _doc = `nilaway_config analyzer is responsible to take configurations (flags) for NilAway execution.
 It does not run any analysis and is only meant to be used as a dependency for the sub-analyzers of 
 NilAway to share the same configurations. 
@@ -113,11 +335,12 @@ NilAway to share the same configurations.
 // specified for this pseudo-analyzer ("nilaway_config"), and the error suppression lists will have
 // to be specified for the top-level analyzer ("nilaway") since that is the one that outputs errors.
 var Analyzer = &analysis.Analyzer{
-	Name:       "nilaway_config",
-	Doc:        _doc,
-	Run:        run,
-	Flags:      newFlagSet(),
-	ResultType: reflect.TypeOf((*Config)(nil)),
+	Name:             "nilaway_config",
+	Doc:              _doc,
+	Run:              run,
+	Flags:            newFlagSet(),
+	ResultType:       reflect.TypeOf((*Config)(nil)),
+	RunDespiteErrors: true,
 }
 
 const (
@@ -131,10 +354,236 @@ const (
 	ExcludePkgsFlag = "exclude-pkgs"
 	// ExcludeFileDocStringsFlag is the flag name for the docstrings that exclude files from analysis.
 	ExcludeFileDocStringsFlag = "exclude-file-docstrings"
+	// ExperimentalFlag is the flag name for the experimental features to enable (see Experiment).
+	ExperimentalFlag = "experimental"
+	// CheckStructInitFlag is the flag name for the mode of the struct initialization checking.
+	CheckStructInitFlag = "check-struct-init"
 	// ExperimentalStructInitEnableFlag is the flag name for the experimental struct init support.
+	//
+	// Deprecated: use CheckStructInitFlag with CheckStructInitReport instead.
 	ExperimentalStructInitEnableFlag = "experimental-struct-init"
 	// ExperimentalAnonymousFunctionFlag is the flag name for the experimental anonymous function support.
+	//
+	// Deprecated: use ExperimentalFlag with ExperimentAnonymousFunction instead.
 	ExperimentalAnonymousFunctionFlag = "experimental-anonymous-function"
+	// ExperimentalGroupResultsFlag is the flag name for the experimental heuristic for the results
+	// populated by the goroutines of an `errgroup.Group` (or a `sync.WaitGroup`).
+	//
+	// Deprecated: use ExperimentalFlag with ExperimentGroupResults instead.
+	ExperimentalGroupResultsFlag = "experimental-group-results"
+	// DisableParseCacheFlag is the flag name for disabling the parse cache in backpropagation.
+	DisableParseCacheFlag = "disable-parse-cache"
+	// MaxTreeWidthFlag is the flag name for the maximum width of the assertion trees.
+	MaxTreeWidthFlag = "max-tree-width"
+	// FixModeFlag is the flag name for the mode of suggested fixes.
+	FixModeFlag = "fix-mode"
+	// FixPolicyFlag is the flag name for the policy of the nil guards inserted by FixModeGuard.
+	FixPolicyFlag = "fix-policy"
+	// AnnotationFilesFlag is the flag name for the sidecar annotation files.
+	AnnotationFilesFlag = "annotation-files"
+	// StubDirsFlag is the flag name for the directories of the stub packages.
+	StubDirsFlag = "stub-dirs"
+	// PackageOverridesFlag is the flag name for the package override files.
+	PackageOverridesFlag = "package-overrides"
+	// IncludeGeneratedFlag is the flag name for reporting errors in generated files.
+	IncludeGeneratedFlag = "include-generated"
+	// ExcludeTestsFlag is the flag name for not reporting errors in test files.
+	ExcludeTestsFlag = "exclude-tests"
+	// TestsOnlyFlag is the flag name for only reporting errors in test files.
+	TestsOnlyFlag = "tests-only"
+	// ExcludeMocksFlag is the flag name for not reporting errors in mocks.
+	ExcludeMocksFlag = "exclude-mocks"
+	// ExportFactsDirFlag is the flag name for the directory to export the nilness facts to.
+	ExportFactsDirFlag = "export-facts-dir"
+	// ImportFactsDirFlag is the flag name for the directory to import the nilness facts from.
+	ImportFactsDirFlag = "import-facts-dir"
+	// DebugDepsFlag is the flag name for listing the upstream dependencies of the errors.
+	DebugDepsFlag = "debug-deps"
+	// MessageTemplateFlag is the flag name for the template of the error messages.
+	MessageTemplateFlag = "message-template"
+	// PathFormatFlag is the flag name for the format of the file paths in the error messages.
+	PathFormatFlag = "path-format"
+	// ExplainProvenanceFlag is the flag name for explaining the provenance of the inferred nilabilities.
+	ExplainProvenanceFlag = "explain-provenance"
+	// DebugDumpDirFlag is the flag name for the directory to dump the debugging graphs to.
+	DebugDumpDirFlag = "debug-dump-dir"
+	// DebugDumpFuncsFlag is the flag name for the regex selecting the functions to dump.
+	DebugDumpFuncsFlag = "debug-dump-funcs"
+	// ReflectEscapeFlag is the flag name for the conservative checking of values escaping via reflection and unsafe.
+	ReflectEscapeFlag = "reflect-escape"
+	// ErrorOnNilableMapReadFlag is the flag name for the strict checking of the reads from maps.
+	ErrorOnNilableMapReadFlag = "error-on-nilable-map-read"
+	// ReportAtFlag is the flag name for where the errors on the results of functions are reported.
+	ReportAtFlag = "report-at"
+	// ReportPositionPolicyFlag is the flag name for the policy of choosing the positions of the errors.
+	ReportPositionPolicyFlag = "report-position-policy"
+	// DefaultNilabilityFlag is the flag name for the nilability assigned to unconstrained sites.
+	DefaultNilabilityFlag = "default-nilability"
+	// StrictExportsFlag is the flag name for treating the parameters of the exported functions as nilable.
+	StrictExportsFlag = "strict-exports"
+	// ReportRedundantChecksFlag is the flag name for reporting the redundant nil checks.
+	ReportRedundantChecksFlag = "report-redundant-checks"
+	// RecoveredPanicsFlag is the flag name for how the errors in the regions recovering from panics are reported.
+	RecoveredPanicsFlag = "recovered-panics"
+	// RequireSourceInScopeFlag is the flag name for how the errors whose nil sources are outside the analysis scope are reported.
+	RequireSourceInScopeFlag = "require-source-in-scope"
+	// AssignmentFlowsFlag is the flag name for how the assignment flows in the error messages are printed.
+	AssignmentFlowsFlag = "assignment-flows"
+	// CrossPackageDedupFlag is the flag name for enabling the deduplication of errors across packages.
+	CrossPackageDedupFlag = "cross-package-dedup"
+	// DisableLineDirectivesFlag is the flag name for not adjusting the positions in the error messages by "//line" directives.
+	DisableLineDirectivesFlag = "disable-line-directives"
+	// IncludeErrorsInFilesFlag is the flag name for the file prefixes to only report errors in.
+	IncludeErrorsInFilesFlag = "include-errors-in-files"
+	// ExcludeErrorsInFilesFlag is the flag name for the file prefixes to not report errors in.
+	ExcludeErrorsInFilesFlag = "exclude-errors-in-files"
+	// InferenceModeFlag is the flag name for the mode of inference.
+	InferenceModeFlag = "inference-mode"
+	// ModelPacksFlag is the flag name for the optional built-in model packs to enable.
+	ModelPacksFlag = "model-packs"
+	// ModelPackFilesFlag is the flag name for the model pack files.
+	ModelPackFilesFlag = "model-pack-files"
+	// MemoryPressureThresholdFlag is the flag name for the fraction of the memory limit above which
+	// the packages are analyzed in a degraded mode.
+	MemoryPressureThresholdFlag = "memory-pressure-threshold"
+)
+
+const (
+	// ModelPackK8s models the idioms of the Kubernetes client libraries (k8s.io/client-go,
+	// k8s.io/apimachinery and sigs.k8s.io/controller-runtime), e.g., the informers and listers, the
+	// getters of the object metadata, and the generated DeepCopy methods. It is defined by the
+	// embedded "modelpacks/k8s.modelpack" file (see ModelPackRule).
+	ModelPackK8s = "k8s"
+)
+
+const (
+	// FixModeGuard is the fix mode that inserts nil guards before the flagged dereferences.
+	FixModeGuard = "guard"
+	// FixModeAnnotate is the fix mode that, instead of reporting the errors, documents the
+	// nilability of the exported APIs determined by inference as annotation comments.
+	FixModeAnnotate = "annotate"
+)
+
+const (
+	// FixPolicyAuto chooses the shape of the guard based on the enclosing function: an early return
+	// in error-returning functions, otherwise wrapping the statement in a nil check if possible, or
+	// panicking with a message as a last resort.
+	FixPolicyAuto = "auto"
+	// FixPolicyReturn always inserts an early return (with a non-nil error if the function returns
+	// one, and zero values otherwise).
+	FixPolicyReturn = "return"
+	// FixPolicyWrap always wraps the statement in a nil check, falling back to FixPolicyPanic if the
+	// statement declares variables.
+	FixPolicyWrap = "wrap"
+	// FixPolicyPanic always inserts a panic with a message describing the nil value.
+	FixPolicyPanic = "panic"
+)
+
+const (
+	// ReportAtSink reports the errors at the dereferences of the nil values.
+	ReportAtSink = "sink"
+	// ReportAtSource reports the errors on the results of the functions at the offending return
+	// statements in the functions instead, if any.
+	ReportAtSource = "source"
+	// ReportAtBoth reports the errors on the results of the functions at both the dereferences and
+	// the offending return statements.
+	ReportAtBoth = "both"
+)
+
+const (
+	// ReportPositionConsumption reports the errors at the consumption sites (e.g., the
+	// dereferences) of the nil flows.
+	ReportPositionConsumption = "consumption"
+	// ReportPositionProduction reports the errors at the production sites (i.e., the nil sources)
+	// of the nil flows.
+	ReportPositionProduction = "production"
+	// ReportPositionModule reports the errors at the consumption sites, unless they are outside the
+	// current module and the production sites are inside it (e.g., when a nil value is passed to
+	// an upstream module that dereferences it).
+	ReportPositionModule = "module"
+)
+
+const (
+	// CheckStructInitOff does not track the struct initializations, i.e., the fields omitted from
+	// the composite literals are only reported if they are enforced or always dereferenced.
+	CheckStructInitOff = "off"
+	// CheckStructInitReport tracks the fields left uninitialized (e.g., omitted from the composite
+	// literals or by the constructors) to their dereferences, and reports them as usual.
+	CheckStructInitReport = "report"
+	// CheckStructInitCount tracks the fields left uninitialized like CheckStructInitReport, but
+	// only reports the number of the resulting errors per package instead of the errors, such that
+	// the check can be turned on gradually.
+	CheckStructInitCount = "count"
+)
+
+const (
+	// RecoveredPanicsReport reports the errors in the regions recovering from panics as usual.
+	RecoveredPanicsReport = "report"
+	// RecoveredPanicsDowngrade reports the errors in the regions recovering from panics with a
+	// "recovered" category and a note in the messages, such that they can be triaged separately.
+	RecoveredPanicsDowngrade = "downgrade"
+	// RecoveredPanicsSuppress does not report the errors in the regions recovering from panics.
+	RecoveredPanicsSuppress = "suppress"
+)
+
+const (
+	// AssignmentFlowsSummary prints the assignment flows in the error messages with the chains of
+	// copies through the trivial single-use temporaries merged (e.g., "`foo()` to `z` via `x`,
+	// `y`"), making the messages shorter.
+	AssignmentFlowsSummary = "summary"
+	// AssignmentFlowsFull prints every assignment of the assignment flows in the error messages.
+	AssignmentFlowsFull = "full"
+)
+
+const (
+	// SourceScopeReport reports the errors whose nil sources are outside the analysis scope as
+	// usual.
+	SourceScopeReport = "report"
+	// SourceScopeAnnotate reports the errors whose nil sources are outside the analysis scope with
+	// a "source outside scope" note in the messages.
+	SourceScopeAnnotate = "annotate"
+	// SourceScopeSuppress does not report the errors whose nil sources are outside the analysis
+	// scope, i.e., only the flows produced within the scope are reported.
+	SourceScopeSuppress = "suppress"
+)
+
+const (
+	// DefaultNilabilityOptimistic leaves the unconstrained annotation sites unconstrained, such
+	// that they never cause errors by themselves (i.e., nil values are assumed never to flow
+	// through them).
+	DefaultNilabilityOptimistic = "optimistic"
+	// DefaultNilabilityPessimistic assumes the unconstrained annotation sites to be nilable, such
+	// that their dereferences (including the ones in downstream packages) are reported unless
+	// they are guarded, trading false negatives for false positives.
+	DefaultNilabilityPessimistic = "pessimistic"
+	// DefaultNilabilityPessimisticExports assumes only the unconstrained exported annotation
+	// sites (i.e., the public APIs) to be nilable, and leaves the others unconstrained.
+	DefaultNilabilityPessimisticExports = "pessimistic-exports"
+)
+
+const (
+	// InferenceModeFull infers the nilability of the annotation sites across packages: the
+	// constraints left undetermined by a package are exported as facts and resolved by the
+	// downstream packages, which is the most precise but requires the facts of all the
+	// dependencies.
+	InferenceModeFull = "full"
+	// InferenceModeSinglePackage checks every package on its own against the annotations in it:
+	// no facts are imported from the dependencies or exported to the dependents, and the sites
+	// not annotated are nonnil by default (as in the packages with the "<nilaway no inference>"
+	// docstring). This is fast enough for the editors, at the cost of missing the nil flows
+	// across packages.
+	InferenceModeSinglePackage = "single-package"
+)
+
+const (
+	// PathFormatShort only keeps the enclosing directory of the files in the error messages, e.g.,
+	// "foo/bar.go:10:2".
+	PathFormatShort = "short"
+	// PathFormatAbsolute prints the absolute paths of the files.
+	PathFormatAbsolute = "absolute"
+	// PathFormatModule prints the paths of the files relative to the roots of their modules (i.e.,
+	// the closest enclosing directories with go.mod files).
+	PathFormatModule = "module"
 )
 
 // newFlagSet returns a flag set to be used in the nilaway config analyzer.
@@ -148,8 +597,48 @@ func newFlagSet() flag.FlagSet {
 	_ = fs.String(IncludePkgsFlag, "", "Comma-separated list of packages to analyze")
 	_ = fs.String(ExcludePkgsFlag, "", "Comma-separated list of packages to exclude from analysis")
 	_ = fs.String(ExcludeFileDocStringsFlag, "", "Comma-separated list of docstrings to exclude from analysis")
-	_ = fs.Bool(ExperimentalStructInitEnableFlag, false, "Whether to enable experimental struct initialization support")
-	_ = fs.Bool(ExperimentalAnonymousFunctionFlag, false, "Whether to enable experimental anonymous function support")
+	_ = fs.String(ExperimentalFlag, "", "Comma-separated list of the experimental features to enable, which are heuristic (or not yet stable) and may change or be removed in any release, supported experiments: \"anonymous-function\" (checking of all anonymous functions) and \"group-results\" (assuming the variables and indices assigned by the goroutines of an errgroup.Group or a sync.WaitGroup to be nonnil once it is waited for)")
+	_ = fs.String(CheckStructInitFlag, CheckStructInitOff, "Mode of the struct initialization checking, which tracks the fields left uninitialized (e.g., omitted from the composite literals or by the constructors) to their dereferences: \"off\" (not at all), \"report\" (report the errors as usual), or \"count\" (only report the number of the errors per package, e.g., for migrating to \"report\")")
+	_ = fs.Bool(ExperimentalStructInitEnableFlag, false, "Deprecated: use -check-struct-init=report instead")
+	_ = fs.Bool(ExperimentalAnonymousFunctionFlag, false, "Deprecated: use -experimental=anonymous-function instead")
+	_ = fs.Bool(ExperimentalGroupResultsFlag, false, "Deprecated: use -experimental=group-results instead")
+	_ = fs.Bool(DisableParseCacheFlag, false, "Disable the memoization of expression parsing in backpropagation (for debugging only)")
+	_ = fs.Int(MaxTreeWidthFlag, 0, "Maximum number of tracked expressions per assertion tree before the least recently used ones are conservatively summarized (0 means no limit)")
+	_ = fs.String(FixModeFlag, "", "Suggest fixes for the diagnostics, supported modes: \"guard\" (insert nil guards before the flagged dereferences) and \"annotate\" (annotate exported APIs with the inferred nilability)")
+	_ = fs.String(FixPolicyFlag, FixPolicyAuto, "Policy for the nil guards inserted by -fix-mode=guard: \"auto\", \"return\", \"wrap\" or \"panic\"")
+	_ = fs.String(PackageOverridesFlag, "", "Comma-separated list of package override files, each line of which overrides the options \"check-struct-init\", \"error-on-nilable-map-read\", \"reflect-escape\", \"experimental\" and \"max-tree-width\" for the packages matching a pattern, e.g., \"./internal/core/... check-struct-init=report\" (see config.PackageOverride)")
+	_ = fs.String(AnnotationFilesFlag, "", "Comma-separated list of sidecar annotation files for code that cannot be annotated in place (e.g., vendored or generated code), which take precedence over the built-in models of the standard library")
+	_ = fs.String(StubDirsFlag, "", "Comma-separated list of directories of stub packages (laid out by import paths) whose annotated declarations replace the analysis of the real packages (e.g., assembly-backed or heavily generated code)")
+	_ = fs.Bool(IncludeGeneratedFlag, false, "Report errors in generated files (with the standard \"// Code generated ... DO NOT EDIT.\" header), which are otherwise analyzed but not reported")
+	_ = fs.Bool(ExcludeTestsFlag, false, "Do not report errors in test files (which are still analyzed)")
+	_ = fs.Bool(TestsOnlyFlag, false, "Only report errors in test files (other files are still analyzed)")
+	_ = fs.Bool(ExcludeMocksFlag, false, "Do not report errors in the mocks, i.e., the packages (e.g., \"mocks\" or \"mock_foo\") and files (e.g., \"mock_foo.go\") named after the conventions of gomock and mockery (which are still analyzed)")
+	_ = fs.Bool(DebugDepsFlag, false, "List the upstream packages (and the objects in them) that contributed facts to each error, to help understand why an error appears in an unchanged package")
+	_ = fs.String(MessageTemplateFlag, "", "Template for rendering the error messages: \"verbose\" (the default multi-line layout with the complete nil flows), \"short\" (a single line with the dereference and the nil source), or a custom Go text/template over the fields Position, Flow, Source, Dereference, SimilarPositions, Provenance, Instantiations and Deps")
+	_ = fs.String(PathFormatFlag, PathFormatShort, "Format of the file paths in the error messages: \"short\" (only the enclosing directory), \"absolute\", \"module\" (relative to the module root), or a Go text/template over the fields Path (module-relative), Absolute, Line and Column for links, e.g., \"https://github.com/org/repo/blob/<commit>/{{.Path}}#L{{.Line}}\"")
+	_ = fs.Bool(ExplainProvenanceFlag, false, "Explain why the conflicting site of each inferred error was inferred nilable and nonnil (e.g., a nil literal passed at some position), which is especially useful for errors spanning multiple packages")
+	_ = fs.String(DebugDumpDirFlag, "", "Directory to write DOT graphs of the preprocessed CFG, the assertion trees in each round of backpropagation, and the final full triggers of the functions selected by -debug-dump-funcs to (for debugging only)")
+	_ = fs.String(DebugDumpFuncsFlag, "", "Regular expression matching the full names (e.g., \"example.com/foo.Bar\" or \"(*example.com/foo.T).Baz\") of the functions to dump with -debug-dump-dir, empty means all functions")
+	_ = fs.Bool(ReflectEscapeFlag, false, "Conservatively report nilable values escaping via reflection (passed to \"reflect.ValueOf\"), \"unsafe.Pointer\" conversions, or cgo calls, where reflection-based setters and C code would panic on nil")
+	_ = fs.Bool(ErrorOnNilableMapReadFlag, false, "Report the reads from possibly-nil maps (e.g., \"m[k]\"), which give the zero values instead of panicking, for the code bases preferring to always initialize their maps")
+	_ = fs.String(ReportAtFlag, ReportAtSink, "Where to report the errors on the results of the functions inferred to return nonnil results (since the callers dereference them): \"sink\" (at the dereferences), \"source\" (at the offending return statements in the functions, giving actionable reports to the owners of the functions), or \"both\"")
+	_ = fs.String(ReportPositionPolicyFlag, ReportPositionConsumption, "Policy for choosing the positions of the errors (the nil flows in the messages are intact either way): \"consumption\" (at the dereferences), \"production\" (at the nil sources), or \"module\" (at the site within the current module when the flow crosses packages)")
+	_ = fs.String(DefaultNilabilityFlag, DefaultNilabilityOptimistic, "Nilability assumed for the annotation sites left unconstrained by inference: \"optimistic\" (no assumption, i.e., nil values are assumed never to flow through them), \"pessimistic\" (nilable, reporting their unguarded dereferences), or \"pessimistic-exports\" (nilable for the exported sites only), letting security-sensitive codebases trade false negatives for false positives")
+	_ = fs.Bool(StrictExportsFlag, false, "Treat the parameters of the exported functions as nilable regardless of inference, since the callers outside the analysis may pass nil, requiring the exported functions to guard their parameters or annotate them as \"//nonnil\"; the violations are reported at the first unguarded dereference of each parameter")
+	_ = fs.Bool(ReportRedundantChecksFlag, false, "Also report the nil checks on the values that are always nonnil (e.g., allocated values, or the sites inferred to be nonnil), which can be removed to reduce noise")
+	_ = fs.String(RecoveredPanicsFlag, RecoveredPanicsReport, "How the errors at the dereferences in the regions recovering from panics (i.e., after a `defer` of a function calling `recover()` without re-panicking) are reported: \"report\" (as usual), \"downgrade\" (with a \"recovered\" category and a note in the messages), or \"suppress\" (not at all)")
+	_ = fs.String(RequireSourceInScopeFlag, SourceScopeReport, "How the errors whose nil sources (i.e., the production sites of the nil flows) are in the packages outside the analysis scope (e.g., excluded by -exclude-pkgs) are reported: \"report\" (as usual), \"annotate\" (with a \"source outside scope\" note in the messages), or \"suppress\" (not at all)")
+	_ = fs.String(AssignmentFlowsFlag, "", "How the flows of the nilable values through assignments are printed in the error messages: \"summary\" (merging the chains of copies through the trivial single-use temporaries, e.g., \"`foo()` to `z` via `x`, `y`\") or \"full\" (every assignment); empty means \"full\" if the diagnostics are printed as JSON (i.e., with -json of the go/analysis drivers) and \"summary\" otherwise")
+	_ = fs.Bool(CrossPackageDedupFlag, false, "Deduplicate the errors across packages, where the errors fully determined by the facts of the upstream packages (e.g., an upstream field assigned nil in one package but dereferenced in another) are only reported by the first package reporting them instead of all the downstream packages. Only enable it if the errors of all analyzed packages are reported (e.g., \"./...\" of a module), since the errors of the dependencies analyzed only for their facts are not printed by the drivers")
+	_ = fs.Bool(DisableLineDirectivesFlag, false, "Disable adjusting the positions in the error messages by the \"//line\" directives in the generated code (e.g., by yacc or templ), i.e., report the positions in the generated files instead of the authored source files that the directives point back to")
+	_ = fs.String(IncludeErrorsInFilesFlag, "", "Comma-separated list of file prefixes to report errors in, empty means all files (the standalone nilaway driver defaults to the current working directory)")
+	_ = fs.String(ExcludeErrorsInFilesFlag, "", "Comma-separated list of file prefixes to not report errors in, which takes precedence over -include-errors-in-files")
+	_ = fs.String(ImportFactsDirFlag, "", "Directory to import externally produced nilability facts (in the format of -export-facts-dir) of the annotation sites of each analyzed package from, as \"<dir>/<package path>.json\", which seed the inference")
+	_ = fs.String(InferenceModeFlag, InferenceModeFull, "Mode of inference: \"full\" (infer the nilability across packages via the facts exported by the dependencies, the most precise) or \"single-package\" (check every package on its own against its annotations, assuming the sites not annotated to be nonnil, without importing or exporting any facts, which is fast enough for the editors but misses the nil flows across packages)")
+	_ = fs.String(ModelPacksFlag, "", "Comma-separated list of the optional model packs to enable, which model the idioms of popular libraries to avoid repeated false positives in their users, supported packs: \"k8s\" (client-go informers and listers, metav1.Object getters, DeepCopy methods and controller-runtime managers and builders)")
+	_ = fs.String(ModelPackFilesFlag, "", "Comma-separated list of model pack files, each line of which models the functions of a library as \"<func|method> <enclosing regex> <function name regex> <nonnil|nilable|ok|noreturn>\", such that the models of the libraries can be shared without changing NilAway")
+	_ = fs.Float64(MemoryPressureThresholdFlag, DefaultMemoryPressureThreshold, "Fraction of the soft memory limit of the Go runtime (GOMEMLIMIT) above which the packages are analyzed in a degraded mode (smaller assertion trees and no struct initialization checks) instead of running out of memory, reporting each degraded package; 0 disables the degradation, which never happens without a memory limit")
+	_ = fs.String(ExportFactsDirFlag, "", "Directory to export the final nilability (nilable or nonnil, shallow and deep) of the annotation sites of each analyzed package to, as \"<dir>/<package path>.json\"")
 
 	return *fs
 }
@@ -161,7 +650,19 @@ func run(pass *analysis.Pass) (any, error) {
 		GroupErrorMessages: true,
 		// If the user does not provide an include list, we give an empty package prefix to catch
 		// all packages.
-		includePkgs: []string{""},
+		includePkgs:          []string{""},
+		FixPolicy:            FixPolicyAuto,
+		PathFormat:           PathFormatShort,
+		ReportAt:             ReportAtSink,
+		ReportPositionPolicy: ReportPositionConsumption,
+		DefaultNilability:    DefaultNilabilityOptimistic,
+		RecoveredPanics:      RecoveredPanicsReport,
+		CheckStructInit:      CheckStructInitOff,
+		RequireSourceInScope: SourceScopeReport,
+		AssignmentFlows:      AssignmentFlowsSummary,
+		InferenceMode:        InferenceModeFull,
+
+		MemoryPressureThreshold: DefaultMemoryPressureThreshold,
 	}
 
 	// Override default values if the user provides flags.
@@ -171,11 +672,218 @@ func run(pass *analysis.Pass) (any, error) {
 	if groupErrorMessages, ok := pass.Analyzer.Flags.Lookup(GroupErrorMessagesFlag).Value.(flag.Getter).Get().(bool); ok {
 		conf.GroupErrorMessages = groupErrorMessages
 	}
-	if enableStructInit, ok := pass.Analyzer.Flags.Lookup(ExperimentalStructInitEnableFlag).Value.(flag.Getter).Get().(bool); ok {
-		conf.ExperimentalStructInitEnable = enableStructInit
+	conf.experiments = make(map[Experiment]bool)
+	if list, ok := pass.Analyzer.Flags.Lookup(ExperimentalFlag).Value.(flag.Getter).Get().(string); ok {
+		experiments, err := parseExperiments(list)
+		if err != nil {
+			return nil, err
+		}
+		conf.experiments = experiments
+	}
+	// The deprecated flags of the individual experiments are still honored.
+	for name, e := range map[string]Experiment{
+		ExperimentalStructInitEnableFlag:  ExperimentStructInit,
+		ExperimentalAnonymousFunctionFlag: ExperimentAnonymousFunction,
+		ExperimentalGroupResultsFlag:      ExperimentGroupResults,
+	} {
+		if enabled, ok := pass.Analyzer.Flags.Lookup(name).Value.(flag.Getter).Get().(bool); ok && enabled {
+			conf.experiments[e] = true
+		}
+	}
+	if checkStructInit, ok := pass.Analyzer.Flags.Lookup(CheckStructInitFlag).Value.(flag.Getter).Get().(string); ok {
+		if !slices.Contains([]string{CheckStructInitOff, CheckStructInitReport, CheckStructInitCount}, checkStructInit) {
+			return nil, fmt.Errorf("unsupported value %q for flag %q", checkStructInit, CheckStructInitFlag)
+		}
+		conf.CheckStructInit = checkStructInit
+	}
+	// The struct initialization checking graduated from the experiments, whose (deprecated) names
+	// still turn it on (in the report mode unless the count mode is given).
+	if conf.experiments[ExperimentStructInit] {
+		delete(conf.experiments, ExperimentStructInit)
+		if conf.CheckStructInit == CheckStructInitOff {
+			conf.CheckStructInit = CheckStructInitReport
+		}
+	}
+	if disableParseCache, ok := pass.Analyzer.Flags.Lookup(DisableParseCacheFlag).Value.(flag.Getter).Get().(bool); ok {
+		conf.DisableParseCache = disableParseCache
+	}
+	if maxTreeWidth, ok := pass.Analyzer.Flags.Lookup(MaxTreeWidthFlag).Value.(flag.Getter).Get().(int); ok {
+		conf.MaxTreeWidth = maxTreeWidth
+	}
+	if includeGenerated, ok := pass.Analyzer.Flags.Lookup(IncludeGeneratedFlag).Value.(flag.Getter).Get().(bool); ok {
+		conf.IncludeGenerated = includeGenerated
+	}
+	if excludeTests, ok := pass.Analyzer.Flags.Lookup(ExcludeTestsFlag).Value.(flag.Getter).Get().(bool); ok {
+		conf.ExcludeTests = excludeTests
+	}
+	if testsOnly, ok := pass.Analyzer.Flags.Lookup(TestsOnlyFlag).Value.(flag.Getter).Get().(bool); ok {
+		conf.TestsOnly = testsOnly
 	}
-	if enableAnonymousFunc, ok := pass.Analyzer.Flags.Lookup(ExperimentalAnonymousFunctionFlag).Value.(flag.Getter).Get().(bool); ok {
-		conf.ExperimentalAnonymousFuncEnable = enableAnonymousFunc
+	if conf.ExcludeTests && conf.TestsOnly {
+		return nil, fmt.Errorf("flags %q and %q are mutually exclusive", ExcludeTestsFlag, TestsOnlyFlag)
+	}
+	if conf.ExcludeTests || conf.TestsOnly {
+		conf.testFiles = make(map[*ast.File]bool)
+		for _, file := range pass.Files {
+			if strings.HasSuffix(pass.Fset.File(file.Pos()).Name(), "_test.go") {
+				conf.testFiles[file] = true
+			}
+		}
+	}
+	if excludeMocks, ok := pass.Analyzer.Flags.Lookup(ExcludeMocksFlag).Value.(flag.Getter).Get().(bool); ok {
+		conf.ExcludeMocks = excludeMocks
+	}
+	if conf.ExcludeMocks {
+		conf.mockPkg = IsMockPath(pass.Pkg.Path(), "")
+		for _, file := range pass.Files {
+			if IsMockPath("", pass.Fset.File(file.Pos()).Name()) {
+				if conf.mockFiles == nil {
+					conf.mockFiles = make(map[*ast.File]bool)
+				}
+				conf.mockFiles[file] = true
+			}
+		}
+	}
+	if exportFactsDir, ok := pass.Analyzer.Flags.Lookup(ExportFactsDirFlag).Value.(flag.Getter).Get().(string); ok {
+		conf.ExportFactsDir = exportFactsDir
+	}
+	if debugDeps, ok := pass.Analyzer.Flags.Lookup(DebugDepsFlag).Value.(flag.Getter).Get().(bool); ok {
+		conf.DebugDeps = debugDeps
+	}
+	if messageTemplate, ok := pass.Analyzer.Flags.Lookup(MessageTemplateFlag).Value.(flag.Getter).Get().(string); ok {
+		conf.MessageTemplate = messageTemplate
+	}
+	if pathFormat, ok := pass.Analyzer.Flags.Lookup(PathFormatFlag).Value.(flag.Getter).Get().(string); ok {
+		conf.PathFormat = pathFormat
+	}
+	if explainProvenance, ok := pass.Analyzer.Flags.Lookup(ExplainProvenanceFlag).Value.(flag.Getter).Get().(bool); ok {
+		conf.ExplainProvenance = explainProvenance
+	}
+	if debugDumpDir, ok := pass.Analyzer.Flags.Lookup(DebugDumpDirFlag).Value.(flag.Getter).Get().(string); ok {
+		conf.DebugDumpDir = debugDumpDir
+	}
+	if debugDumpFuncs, ok := pass.Analyzer.Flags.Lookup(DebugDumpFuncsFlag).Value.(flag.Getter).Get().(string); ok && debugDumpFuncs != "" {
+		re, err := regexp.Compile(debugDumpFuncs)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex for flag %q: %w", DebugDumpFuncsFlag, err)
+		}
+		conf.DebugDumpFuncs = re
+	}
+	if reflectEscape, ok := pass.Analyzer.Flags.Lookup(ReflectEscapeFlag).Value.(flag.Getter).Get().(bool); ok {
+		conf.ReflectEscape = reflectEscape
+	}
+	if errorOnNilableMapRead, ok := pass.Analyzer.Flags.Lookup(ErrorOnNilableMapReadFlag).Value.(flag.Getter).Get().(bool); ok {
+		conf.ErrorOnNilableMapRead = errorOnNilableMapRead
+	}
+	if strictExports, ok := pass.Analyzer.Flags.Lookup(StrictExportsFlag).Value.(flag.Getter).Get().(bool); ok {
+		conf.StrictExports = strictExports
+	}
+	if reportRedundantChecks, ok := pass.Analyzer.Flags.Lookup(ReportRedundantChecksFlag).Value.(flag.Getter).Get().(bool); ok {
+		conf.ReportRedundantChecks = reportRedundantChecks
+	}
+	if reportAt, ok := pass.Analyzer.Flags.Lookup(ReportAtFlag).Value.(flag.Getter).Get().(string); ok {
+		if !slices.Contains([]string{ReportAtSink, ReportAtSource, ReportAtBoth}, reportAt) {
+			return nil, fmt.Errorf("unsupported value %q for flag %q", reportAt, ReportAtFlag)
+		}
+		conf.ReportAt = reportAt
+	}
+	if policy, ok := pass.Analyzer.Flags.Lookup(ReportPositionPolicyFlag).Value.(flag.Getter).Get().(string); ok {
+		if !slices.Contains([]string{ReportPositionConsumption, ReportPositionProduction, ReportPositionModule}, policy) {
+			return nil, fmt.Errorf("unsupported value %q for flag %q", policy, ReportPositionPolicyFlag)
+		}
+		conf.ReportPositionPolicy = policy
+	}
+	if defaultNilability, ok := pass.Analyzer.Flags.Lookup(DefaultNilabilityFlag).Value.(flag.Getter).Get().(string); ok {
+		if !slices.Contains([]string{DefaultNilabilityOptimistic, DefaultNilabilityPessimistic, DefaultNilabilityPessimisticExports}, defaultNilability) {
+			return nil, fmt.Errorf("unsupported value %q for flag %q", defaultNilability, DefaultNilabilityFlag)
+		}
+		conf.DefaultNilability = defaultNilability
+	}
+	if recoveredPanics, ok := pass.Analyzer.Flags.Lookup(RecoveredPanicsFlag).Value.(flag.Getter).Get().(string); ok {
+		if !slices.Contains([]string{RecoveredPanicsReport, RecoveredPanicsDowngrade, RecoveredPanicsSuppress}, recoveredPanics) {
+			return nil, fmt.Errorf("unsupported value %q for flag %q", recoveredPanics, RecoveredPanicsFlag)
+		}
+		conf.RecoveredPanics = recoveredPanics
+	}
+	if assignmentFlows, ok := pass.Analyzer.Flags.Lookup(AssignmentFlowsFlag).Value.(flag.Getter).Get().(string); ok {
+		if assignmentFlows == "" {
+			assignmentFlows = defaultAssignmentFlows()
+		}
+		if !slices.Contains([]string{AssignmentFlowsSummary, AssignmentFlowsFull}, assignmentFlows) {
+			return nil, fmt.Errorf("unsupported value %q for flag %q", assignmentFlows, AssignmentFlowsFlag)
+		}
+		conf.AssignmentFlows = assignmentFlows
+	}
+	if requireSourceInScope, ok := pass.Analyzer.Flags.Lookup(RequireSourceInScopeFlag).Value.(flag.Getter).Get().(string); ok {
+		if !slices.Contains([]string{SourceScopeReport, SourceScopeAnnotate, SourceScopeSuppress}, requireSourceInScope) {
+			return nil, fmt.Errorf("unsupported value %q for flag %q", requireSourceInScope, RequireSourceInScopeFlag)
+		}
+		conf.RequireSourceInScope = requireSourceInScope
+	}
+	if dedup, ok := pass.Analyzer.Flags.Lookup(CrossPackageDedupFlag).Value.(flag.Getter).Get().(bool); ok {
+		conf.CrossPackageDedup = dedup
+	}
+	if disableLineDirectives, ok := pass.Analyzer.Flags.Lookup(DisableLineDirectivesFlag).Value.(flag.Getter).Get().(bool); ok {
+		conf.DisableLineDirectives = disableLineDirectives
+	}
+	// The files using cgo (i.e., `import "C"`) are rewritten by cgo before analysis, and the
+	// rewritten files are marked as generated. However, they contain "//line" directives pointing
+	// back to the original source files, so here we identify them to still report errors on them.
+	// Likewise, the errors in the other generated files are reported on their authored sources
+	// (e.g., the grammars of yacc or the templates of templ) via the "//line" directives or the
+	// registered source mappers, unless the positions are not adjusted.
+	for _, file := range pass.Files {
+		mapped := asthelper.IsCgoFile(pass.Fset, file)
+		if !mapped && !conf.DisableLineDirectives {
+			mapped = asthelper.HasLineDirectives(file) || sourcemap.Registered(pass.Fset.File(file.Pos()).Name())
+		}
+		if mapped {
+			if conf.mappedFiles == nil {
+				conf.mappedFiles = make(map[*ast.File]bool)
+			}
+			conf.mappedFiles[file] = true
+		}
+	}
+	if inferenceMode, ok := pass.Analyzer.Flags.Lookup(InferenceModeFlag).Value.(flag.Getter).Get().(string); ok {
+		if !slices.Contains([]string{InferenceModeFull, InferenceModeSinglePackage}, inferenceMode) {
+			return nil, fmt.Errorf("unsupported value %q for flag %q", inferenceMode, InferenceModeFlag)
+		}
+		conf.InferenceMode = inferenceMode
+	}
+	if threshold, ok := pass.Analyzer.Flags.Lookup(MemoryPressureThresholdFlag).Value.(flag.Getter).Get().(float64); ok {
+		if threshold < 0 || threshold > 1 {
+			return nil, fmt.Errorf("unsupported value %v for flag %q, expected a fraction in [0, 1]", threshold, MemoryPressureThresholdFlag)
+		}
+		conf.MemoryPressureThreshold = threshold
+	}
+	// The decision is made once per package such that all sub-analyzers agree on it.
+	conf.Degraded = underMemoryPressure(conf.MemoryPressureThreshold, runtimeMemoryUsage)
+	var includeErrorsInFiles, excludeErrorsInFiles string
+	if includes, ok := pass.Analyzer.Flags.Lookup(IncludeErrorsInFilesFlag).Value.(flag.Getter).Get().(string); ok {
+		includeErrorsInFiles = includes
+	}
+	if excludes, ok := pass.Analyzer.Flags.Lookup(ExcludeErrorsInFilesFlag).Value.(flag.Getter).Get().(string); ok {
+		excludeErrorsInFiles = excludes
+	}
+	errorFilter, err := suppression.NewFilter(includeErrorsInFiles, excludeErrorsInFiles)
+	if err != nil {
+		return nil, err
+	}
+	conf.ErrorFilter = errorFilter
+	if importFactsDir, ok := pass.Analyzer.Flags.Lookup(ImportFactsDirFlag).Value.(flag.Getter).Get().(string); ok {
+		conf.ImportFactsDir = importFactsDir
+	}
+	if fixMode, ok := pass.Analyzer.Flags.Lookup(FixModeFlag).Value.(flag.Getter).Get().(string); ok {
+		if fixMode != "" && fixMode != FixModeGuard && fixMode != FixModeAnnotate {
+			return nil, fmt.Errorf("unsupported fix mode %q", fixMode)
+		}
+		conf.FixMode = fixMode
+	}
+	if fixPolicy, ok := pass.Analyzer.Flags.Lookup(FixPolicyFlag).Value.(flag.Getter).Get().(string); ok {
+		if !slices.Contains([]string{FixPolicyAuto, FixPolicyReturn, FixPolicyWrap, FixPolicyPanic}, fixPolicy) {
+			return nil, fmt.Errorf("unsupported fix policy %q", fixPolicy)
+		}
+		conf.FixPolicy = fixPolicy
 	}
 	if include, ok := pass.Analyzer.Flags.Lookup(IncludePkgsFlag).Value.(flag.Getter).Get().(string); ok && include != "" {
 		conf.includePkgs = strings.Split(include, ",")
@@ -186,6 +894,80 @@ func run(pass *analysis.Pass) (any, error) {
 	if docstrings, ok := pass.Analyzer.Flags.Lookup(ExcludeFileDocStringsFlag).Value.(flag.Getter).Get().(string); ok && docstrings != "" {
 		conf.excludeFileDocStrings = strings.Split(docstrings, ",")
 	}
+	if packs, ok := pass.Analyzer.Flags.Lookup(ModelPacksFlag).Value.(flag.Getter).Get().(string); ok && packs != "" {
+		for _, pack := range strings.Split(packs, ",") {
+			rules, err := parseBuiltinModelPack(pack)
+			if err != nil {
+				return nil, err
+			}
+			conf.ModelPackRules = append(conf.ModelPackRules, rules...)
+		}
+	}
+	if files, ok := pass.Analyzer.Flags.Lookup(ModelPackFilesFlag).Value.(flag.Getter).Get().(string); ok && files != "" {
+		for _, file := range strings.Split(files, ",") {
+			rules, err := parseModelPackFile(file)
+			if err != nil {
+				return nil, err
+			}
+			conf.ModelPackRules = append(conf.ModelPackRules, rules...)
+		}
+	}
+	stdlib, err := parseStdlibAnnotations()
+	if err != nil {
+		return nil, err
+	}
+	conf.SidecarAnnotations = stdlib
+	if files, ok := pass.Analyzer.Flags.Lookup(AnnotationFilesFlag).Value.(flag.Getter).Get().(string); ok && files != "" {
+		for _, file := range strings.Split(files, ",") {
+			annotations, err := parseSidecarFile(file)
+			if err != nil {
+				return nil, err
+			}
+			conf.SidecarAnnotations = append(conf.SidecarAnnotations, annotations...)
+		}
+	}
+	if dirs, ok := pass.Analyzer.Flags.Lookup(StubDirsFlag).Value.(flag.Getter).Get().(string); ok && dirs != "" {
+		conf.stubPkgs = make(map[string]bool)
+		for _, dir := range strings.Split(dirs, ",") {
+			pkgs, annotations, err := parseStubDir(dir)
+			if err != nil {
+				return nil, err
+			}
+			maps.Copy(conf.stubPkgs, pkgs)
+			conf.SidecarAnnotations = append(conf.SidecarAnnotations, annotations...)
+		}
+	}
+	if files, ok := pass.Analyzer.Flags.Lookup(PackageOverridesFlag).Value.(flag.Getter).Get().(string); ok && files != "" {
+		modulePath := ""
+		if pass.Module != nil {
+			modulePath = pass.Module.Path
+		}
+		for _, file := range strings.Split(files, ",") {
+			overrides, err := parsePackageOverrideFile(file)
+			if err != nil {
+				return nil, err
+			}
+			for _, o := range overrides {
+				if !o.matches(pass.Pkg.Path(), modulePath) {
+					continue
+				}
+				if err := o.apply(conf); err != nil {
+					return nil, err
+				}
+				conf.PackageOverrides = append(conf.PackageOverrides, o)
+			}
+		}
+	}
 
 	return conf, nil
 }
+
+// defaultAssignmentFlows returns how the assignment flows are printed if not specified: in full if
+// the diagnostics are printed as JSON (i.e., the "-json" flag of the go/analysis drivers is set),
+// which is meant for tools rather than humans, and as summaries otherwise.
+func defaultAssignmentFlows() string {
+	if f := flag.Lookup("json"); f != nil && f.Value.String() == "true" {
+		return AssignmentFlowsFull
+	}
+	return AssignmentFlowsSummary
+}
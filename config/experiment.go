@@ -0,0 +1,80 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Experiment is the name of an experimental feature of NilAway, i.e., a heuristic (or a feature
+// not yet stable enough to be enabled by default) that is only enabled by ExperimentalFlag, e.g.,
+// `-experimental=anonymous-function,group-results`.
+type Experiment string
+
+const (
+	// ExperimentStructInit enables the tracking of the struct initializations, such that the
+	// fields omitted in the composite literals (and left nil) are reported when dereferenced.
+	//
+	// Deprecated: the struct initialization checking graduated to CheckStructInitFlag, and the
+	// experiment is only accepted as an alias of CheckStructInitReport.
+	ExperimentStructInit Experiment = "struct-init"
+	// ExperimentAnonymousFunction enables the checking of all anonymous functions, instead of
+	// only those that can be inlined at their call sites.
+	ExperimentAnonymousFunction Experiment = "anonymous-function"
+	// ExperimentGroupResults enables the heuristic assuming the variables (and the indices of the
+	// slices and maps) assigned by the goroutines of an `errgroup.Group` (or a `sync.WaitGroup`) to
+	// be nonnil once a nil error is returned by its `Wait` (or its `Wait` returns).
+	ExperimentGroupResults Experiment = "group-results"
+)
+
+// Experiments is the list of all supported experiments, in the order of display.
+var Experiments = []Experiment{
+	ExperimentAnonymousFunction,
+	ExperimentGroupResults,
+}
+
+// _graduatedExperiments is the list of the experiments that graduated to stable features, whose
+// names are still accepted by ExperimentalFlag for compatibility.
+var _graduatedExperiments = []Experiment{
+	ExperimentStructInit,
+}
+
+// parseExperiments parses the comma-separated list of experiments given by ExperimentalFlag.
+func parseExperiments(list string) (map[Experiment]bool, error) {
+	experiments := make(map[Experiment]bool)
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		e := Experiment(name)
+		if !slices.Contains(Experiments, e) && !slices.Contains(_graduatedExperiments, e) {
+			return nil, fmt.Errorf("unsupported experiment %q, supported experiments: %s", name, experimentNames())
+		}
+		experiments[e] = true
+	}
+	return experiments, nil
+}
+
+// experimentNames returns the comma-separated names of the supported experiments.
+func experimentNames() string {
+	names := make([]string, len(Experiments))
+	for i, e := range Experiments {
+		names[i] = string(e)
+	}
+	return strings.Join(names, ", ")
+}
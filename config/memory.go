@@ -0,0 +1,75 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"math"
+	"runtime/metrics"
+)
+
+// DefaultMemoryPressureThreshold is the default fraction of the soft memory limit of the Go runtime
+// above which the packages are analyzed in a degraded mode (see Config.Degraded).
+const DefaultMemoryPressureThreshold = 0.8
+
+// DegradedMaxTreeWidth is the maximum width of the assertion trees (see Config.MaxTreeWidth) for the
+// packages analyzed in a degraded mode, unless a smaller width is configured.
+const DegradedMaxTreeWidth = 32
+
+// EffectiveMaxTreeWidth returns the maximum width of the assertion trees for the current package,
+// i.e., MaxTreeWidth bounded by DegradedMaxTreeWidth if the package is analyzed in a degraded mode.
+func (c *Config) EffectiveMaxTreeWidth() int {
+	if c.Degraded && (c.MaxTreeWidth <= 0 || c.MaxTreeWidth > DegradedMaxTreeWidth) {
+		return DegradedMaxTreeWidth
+	}
+	return c.MaxTreeWidth
+}
+
+const (
+	// _memoryLimitMetric is the runtime metric of the soft memory limit (i.e., GOMEMLIMIT).
+	_memoryLimitMetric = "/gc/gomemlimit:bytes"
+	// _memoryTotalMetric is the runtime metric of all memory mapped by the Go runtime.
+	_memoryTotalMetric = "/memory/classes/total:bytes"
+	// _memoryReleasedMetric is the runtime metric of the memory released back to the OS, which
+	// does not count towards the memory limit.
+	_memoryReleasedMetric = "/memory/classes/heap/released:bytes"
+)
+
+// underMemoryPressure returns true if the memory in use exceeds the given fraction of the soft
+// memory limit of the Go runtime, both returned by the given function (i.e., runtimeMemoryUsage,
+// replaceable for testing). It always returns false if the threshold is 0 or there is no limit
+// set (e.g., via GOMEMLIMIT or debug.SetMemoryLimit).
+func underMemoryPressure(threshold float64, usage func() (inUse uint64, limit uint64)) bool {
+	if threshold <= 0 {
+		return false
+	}
+	inUse, limit := usage()
+	if limit == 0 || limit >= math.MaxInt64 {
+		return false
+	}
+	return float64(inUse) > threshold*float64(limit)
+}
+
+// runtimeMemoryUsage returns the memory counted towards the soft memory limit of the Go runtime
+// and the limit itself, or zeros if the runtime does not support the metrics.
+func runtimeMemoryUsage() (inUse uint64, limit uint64) {
+	samples := []metrics.Sample{{Name: _memoryLimitMetric}, {Name: _memoryTotalMetric}, {Name: _memoryReleasedMetric}}
+	metrics.Read(samples)
+	for _, s := range samples {
+		if s.Value.Kind() != metrics.KindUint64 {
+			return 0, 0
+		}
+	}
+	return samples[1].Value.Uint64() - samples[2].Value.Uint64(), samples[0].Value.Uint64()
+}
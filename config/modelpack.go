@@ -0,0 +1,137 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// ModelPackRule is a rule read from a model pack, which models the functions of a library that
+// NilAway does not (or cannot) analyze, such that the models can be shared without changing
+// NilAway itself. Each non-empty line of a model pack (except comment lines starting with "#")
+// has the following format:
+//
+//	<kind> <enclosing regex> <function name regex> <model>
+//
+// where the kind is either "func" or "method", and the regexes match the functions the same way
+// as the built-in models do: the enclosing regex matches "<package path>" for functions and
+// "<package path>.<type name>" for methods (left unanchored to also match the vendored copies),
+// and the function name regex matches the names of the functions (or methods). The model is one
+// of ModelNonnil, ModelNilable, ModelOk and ModelNoReturn. For example:
+//
+//	method example\.com/vendor/cache\.Cache$ ^(Get|Peek)$ ok
+//	func example\.com/vendor/client$ ^MustNew$ nonnil
+//	method example\.com/vendor/client\.Client$ ^LastError$ nilable
+//	func example\.com/vendor/log$ ^Fatalf?$ noreturn
+//
+// The results of the functions returning errors are already guarded by the errors, and the
+// contracts and the annotations of individual functions can be given by the sidecar annotation
+// files (see SidecarAnnotation) instead.
+type ModelPackRule struct {
+	// IsMethod indicates whether the rule matches methods instead of functions.
+	IsMethod bool
+	// Enclosing matches the package path (and the type name for methods) of the functions.
+	Enclosing *regexp.Regexp
+	// FuncName matches the names of the functions.
+	FuncName *regexp.Regexp
+	// Model is the model of the functions, e.g., ModelNonnil.
+	Model string
+}
+
+const (
+	// ModelNonnil models the functions whose results are never nil.
+	ModelNonnil = "nonnil"
+	// ModelNilable models the functions whose results may be nil.
+	ModelNilable = "nilable"
+	// ModelOk models the functions returning an ok boolean as their last result, where the other
+	// results are never nil only if the ok result is true (e.g., `v, ok := cache.Get(key)`).
+	ModelOk = "ok"
+	// ModelNoReturn models the functions that never return (e.g., loggers exiting the program).
+	ModelNoReturn = "noreturn"
+)
+
+// _builtinModelPacks holds the built-in model packs (see ModelPacksFlag), named after their files.
+//
+//go:embed modelpacks/*.modelpack
+var _builtinModelPacks embed.FS
+
+// parseBuiltinModelPack reads the rules of the built-in model pack with the given name.
+func parseBuiltinModelPack(name string) ([]ModelPackRule, error) {
+	f, err := _builtinModelPacks.Open("modelpacks/" + name + ".modelpack")
+	if err != nil {
+		return nil, fmt.Errorf("unsupported model pack %q", name)
+	}
+	defer f.Close()
+	return parseModelPack(name, f)
+}
+
+// parseModelPackFile reads the rules of the model pack from the given file.
+func parseModelPackFile(filename string) ([]ModelPackRule, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("open model pack file: %w", err)
+	}
+	defer f.Close()
+	return parseModelPack(filename, f)
+}
+
+// parseModelPack reads the rules of the model pack from the given reader, where name is used for
+// the error messages.
+func parseModelPack(name string, r io.Reader) ([]ModelPackRule, error) {
+	var rules []ModelPackRule
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("%s:%d: expect \"<kind> <enclosing regex> <function name regex> <model>\", got %q", name, line, text)
+		}
+		if fields[0] != "func" && fields[0] != "method" {
+			return nil, fmt.Errorf("%s:%d: unsupported kind %q, expect \"func\" or \"method\"", name, line, fields[0])
+		}
+		enclosing, err := regexp.Compile(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid enclosing regex: %w", name, line, err)
+		}
+		funcName, err := regexp.Compile(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid function name regex: %w", name, line, err)
+		}
+		switch fields[3] {
+		case ModelNonnil, ModelNilable, ModelOk, ModelNoReturn:
+		default:
+			return nil, fmt.Errorf("%s:%d: unsupported model %q", name, line, fields[3])
+		}
+		rules = append(rules, ModelPackRule{
+			IsMethod:  fields[0] == "method",
+			Enclosing: enclosing,
+			FuncName:  funcName,
+			Model:     fields[3],
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read model pack %q: %w", name, err)
+	}
+	return rules, nil
+}
@@ -0,0 +1,174 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// PackageOverride is a set of options overriding the ones given by the flags for the packages
+// matching a pattern, read from a package override file (see PackageOverridesFlag). This makes it
+// possible to roll out the stricter checks gradually, e.g., to enable the struct initialization
+// checking only in the core packages, or to relax the checks in the legacy ones. Each non-empty
+// line of a package override file (except comment lines starting with "#") has the following
+// format:
+//
+//	<package pattern> <option>=<value> [<option>=<value> ...]
+//
+// where the package pattern is an import path in which "..." matches any string (e.g.,
+// "example.com/app/internal/core/..." matches the package and all its subpackages), or such a
+// path starting with "./" relative to the module of the analyzed package (e.g.,
+// "./internal/core/..."). The options are named after the flags they override, and are one of
+// the _overridableFlags. For example:
+//
+//	./internal/core/...  check-struct-init=report
+//	./legacy/...         error-on-nilable-map-read=false experimental=
+//
+// The overrides of all the lines matching a package are applied in order, such that the later
+// lines take precedence over the earlier ones (and all of them over the flags).
+type PackageOverride struct {
+	// Pattern is the package pattern as written in the file.
+	Pattern string
+	// Options are the overriding options in the order they are written, each a flag name and its
+	// value.
+	Options [][2]string
+	// regex matches the import paths for Pattern, where a relative pattern is matched against the
+	// paths relative to the module (without the "./" prefix).
+	regex *regexp.Regexp
+}
+
+// _overridableFlags is the list of the flags that can be overridden per package, which are the
+// ones consulted by the analysis of the functions (see assertiontree.FunctionConfig) and the
+// reporting of the errors of the package.
+var _overridableFlags = []string{
+	CheckStructInitFlag,
+	ErrorOnNilableMapReadFlag,
+	ReflectEscapeFlag,
+	ExperimentalFlag,
+	MaxTreeWidthFlag,
+}
+
+// parsePackageOverrideFile reads the package overrides from the given file.
+func parsePackageOverrideFile(filename string) ([]PackageOverride, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("open package override file: %w", err)
+	}
+	defer f.Close()
+
+	var overrides []PackageOverride
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("%s:%d: expect \"<package pattern> <option>=<value> ...\", got %q", filename, line, text)
+		}
+		o := PackageOverride{Pattern: fields[0], regex: packagePatternRegex(strings.TrimPrefix(fields[0], "./"))}
+		for _, field := range fields[1:] {
+			name, value, ok := strings.Cut(field, "=")
+			if !ok || !slices.Contains(_overridableFlags, name) {
+				return nil, fmt.Errorf("%s:%d: unsupported option %q, expect \"<option>=<value>\" with one of the options %s", filename, line, field, strings.Join(_overridableFlags, ", "))
+			}
+			o.Options = append(o.Options, [2]string{name, value})
+		}
+		// Validate the values once here, such that the errors point to the lines.
+		if err := o.apply(&Config{experiments: make(map[Experiment]bool)}); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", filename, line, err)
+		}
+		overrides = append(overrides, o)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read package override file: %w", err)
+	}
+	return overrides, nil
+}
+
+// packagePatternRegex returns the regex matching the import paths for the package pattern, where
+// "..." matches any string. Like the patterns of the go command, a pattern ending with "/..."
+// also matches the package without the suffix (e.g., "a/..." matches "a").
+func packagePatternRegex(pattern string) *regexp.Regexp {
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `/\.\.\.`, `(/.*)?`)
+	expr = strings.ReplaceAll(expr, `\.\.\.`, `.*`)
+	return regexp.MustCompile("^" + expr + "$")
+}
+
+// matches returns true iff the override applies to the package with the given import path, where
+// modulePath is the path of the module containing it (empty if unknown, e.g., for the drivers not
+// providing the modules, where only the absolute patterns can match).
+func (o PackageOverride) matches(pkgPath, modulePath string) bool {
+	if !strings.HasPrefix(o.Pattern, "./") {
+		return o.regex.MatchString(pkgPath)
+	}
+	if modulePath == "" {
+		return false
+	}
+	if pkgPath == modulePath {
+		return o.regex.MatchString("")
+	}
+	rel, ok := strings.CutPrefix(pkgPath, modulePath+"/")
+	return ok && o.regex.MatchString(rel)
+}
+
+// apply applies the options of the override to the config.
+func (o PackageOverride) apply(conf *Config) error {
+	for _, option := range o.Options {
+		name, value := option[0], option[1]
+		switch name {
+		case CheckStructInitFlag:
+			if !slices.Contains([]string{CheckStructInitOff, CheckStructInitReport, CheckStructInitCount}, value) {
+				return fmt.Errorf("unsupported value %q for option %q", value, name)
+			}
+			conf.CheckStructInit = value
+		case ErrorOnNilableMapReadFlag, ReflectEscapeFlag:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("unsupported value %q for option %q: %w", value, name, err)
+			}
+			if name == ErrorOnNilableMapReadFlag {
+				conf.ErrorOnNilableMapRead = b
+			} else {
+				conf.ReflectEscape = b
+			}
+		case ExperimentalFlag:
+			experiments, err := parseExperiments(value)
+			if err != nil {
+				return err
+			}
+			if experiments[ExperimentStructInit] {
+				delete(experiments, ExperimentStructInit)
+				conf.CheckStructInit = CheckStructInitReport
+			}
+			conf.experiments = experiments
+		case MaxTreeWidthFlag:
+			width, err := strconv.Atoi(value)
+			if err != nil || width < 0 {
+				return fmt.Errorf("unsupported value %q for option %q, expect a non-negative integer", value, name)
+			}
+			conf.MaxTreeWidth = width
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// SidecarAnnotation is an annotation for an object read from a sidecar annotation file, which
+// makes it possible to annotate code that cannot be annotated in place (e.g., vendored or
+// generated code). Each non-empty line of a sidecar annotation file (except comment lines
+// starting with "#") has the following format:
+//
+//	<package path> <object path> <annotations>
+//
+// where the object path is the name of a package-level object (function, type or global
+// variable), or "<type name>.<method name>" for a method, and the annotations follow the same
+// syntax as the annotation comments on the declaration of the object. For example:
+//
+//	example.com/vendor/client NewClient nonnil(result 0) nilable(opts)
+//	example.com/vendor/client Client.Do nilable(result 0)
+//	example.com/vendor/client Config nilable(Timeout)
+//	example.com/vendor/client DefaultClient nonnil(DefaultClient)
+type SidecarAnnotation struct {
+	// PkgPath is the path of the package containing the object.
+	PkgPath string
+	// ObjectPath is the (dotted) path of the object in the package.
+	ObjectPath string
+	// Annotations is the annotation text for the object, e.g., "nilable(result 0)".
+	Annotations string
+	// Source is the location of the annotation in the sidecar annotation file (e.g.,
+	// "annotations.txt:3"), used in the error messages for the malformed annotations.
+	Source string
+}
+
+// _stdlibAnnotations holds the models of the standard library in the sidecar annotation format,
+// which are always added before the annotations read from the sidecar annotation files.
+//
+//go:embed stdlib.annotations
+var _stdlibAnnotations string
+
+// parseStdlibAnnotations reads the models of the standard library (see _stdlibAnnotations).
+func parseStdlibAnnotations() ([]SidecarAnnotation, error) {
+	return parseSidecar("stdlib.annotations", strings.NewReader(_stdlibAnnotations))
+}
+
+// parseSidecarFile reads the sidecar annotations from the given file.
+func parseSidecarFile(filename string) ([]SidecarAnnotation, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("open sidecar annotation file: %w", err)
+	}
+	defer f.Close()
+	return parseSidecar(filename, f)
+}
+
+// parseSidecar reads the sidecar annotations from the given reader, where name is used for the
+// error messages.
+func parseSidecar(name string, r io.Reader) ([]SidecarAnnotation, error) {
+	var annotations []SidecarAnnotation
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("%s:%d: expect \"<package path> <object path> <annotations>\", got %q", name, line, text)
+		}
+		annotations = append(annotations, SidecarAnnotation{
+			PkgPath:     fields[0],
+			ObjectPath:  fields[1],
+			Annotations: strings.Join(fields[2:], " "),
+			Source:      fmt.Sprintf("%s:%d", name, line),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read sidecar annotations %q: %w", name, err)
+	}
+	return annotations, nil
+}
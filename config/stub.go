@@ -0,0 +1,147 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// parseStubDir reads the stub packages from the given directory, i.e., the Go files in the
+// subdirectories named after the import paths of the packages they stub (e.g., the files in
+// "<dir>/example.com/vendor/asm" stub the package "example.com/vendor/asm"). The stub files
+// only need to declare the annotated objects of the real packages (with the same names and
+// parameter names, as the annotations are matched against the real declarations) along with
+// their annotation comments, for example:
+//
+//	package asm
+//
+//	// nilable(result 0)
+//	func Lookup(name string) *Entry
+//
+// A stubbed package is not analyzed: the annotations read from its stub files (see
+// SidecarAnnotation) are used as the nilability of its sites instead, which are exported to the
+// downstream packages. This is useful for the packages whose real source cannot be analyzed
+// meaningfully (e.g., packages backed by assembly) or is too expensive to analyze (e.g., heavily
+// generated code). It returns the set of stubbed package paths and the annotations read.
+func parseStubDir(dir string) (map[string]bool, []SidecarAnnotation, error) {
+	pkgs := make(map[string]bool)
+	var annotations []SidecarAnnotation
+	fset := token.NewFileSet()
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".go" || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, filepath.Dir(path))
+		if err != nil || rel == "." {
+			return fmt.Errorf("stub file %q is not in a package directory", path)
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			return fmt.Errorf("parse stub file: %w", err)
+		}
+		pkgPath := filepath.ToSlash(rel)
+		pkgs[pkgPath] = true
+		annotations = append(annotations, stubAnnotations(pkgPath, file)...)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("read stub directory %q: %w", dir, err)
+	}
+	return pkgs, annotations, nil
+}
+
+// stubAnnotations returns the annotations for the objects declared in the stub file, one for each
+// line of the doc comments of the declarations.
+func stubAnnotations(pkgPath string, file *ast.File) []SidecarAnnotation {
+	var annotations []SidecarAnnotation
+	add := func(objectPath string, doc *ast.CommentGroup) {
+		if doc == nil {
+			return
+		}
+		for _, c := range doc.List {
+			text, ok := strings.CutPrefix(c.Text, "//")
+			if text = strings.TrimSpace(text); !ok || text == "" {
+				continue
+			}
+			annotations = append(annotations, SidecarAnnotation{
+				PkgPath:     pkgPath,
+				ObjectPath:  objectPath,
+				Annotations: text,
+			})
+		}
+	}
+
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			objectPath := decl.Name.Name
+			if decl.Recv != nil && len(decl.Recv.List) == 1 {
+				recvName := recvTypeName(decl.Recv.List[0].Type)
+				if recvName == "" {
+					continue
+				}
+				objectPath = recvName + "." + objectPath
+			}
+			add(objectPath, decl.Doc)
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				doc := decl.Doc
+				if len(decl.Specs) > 1 {
+					doc = nil
+				}
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					if spec.Doc != nil {
+						doc = spec.Doc
+					}
+					add(spec.Name.Name, doc)
+				case *ast.ValueSpec:
+					if spec.Doc != nil {
+						doc = spec.Doc
+					}
+					for _, name := range spec.Names {
+						add(name.Name, doc)
+					}
+				}
+			}
+		}
+	}
+	return annotations
+}
+
+// recvTypeName returns the name of the receiver type expression (e.g., "T" for `*T` or `T[K]`),
+// or an empty string if it is not a (pointer to a) named type.
+func recvTypeName(expr ast.Expr) string {
+	switch expr := ast.Unparen(expr).(type) {
+	case *ast.StarExpr:
+		return recvTypeName(expr.X)
+	case *ast.IndexExpr:
+		return recvTypeName(expr.X)
+	case *ast.IndexListExpr:
+		return recvTypeName(expr.X)
+	case *ast.Ident:
+		return expr.Name
+	}
+	return ""
+}
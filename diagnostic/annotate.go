@@ -0,0 +1,230 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostic
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"regexp"
+	"strings"
+
+	"go.uber.org/nilaway/annotation"
+	"go.uber.org/nilaway/config"
+	"go.uber.org/nilaway/inference"
+	"go.uber.org/nilaway/util"
+	"golang.org/x/tools/go/analysis"
+)
+
+// annotatableNameRegex matches the names that can be referred to in the annotation comments.
+var annotatableNameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9]*$`)
+
+// inferredAnnotations collects the inferred nilability of the sites of a declaration, in the form
+// of the arguments to the `nilable(...)` and `nonnil(...)` annotation comments.
+type inferredAnnotations struct {
+	nilable []string
+	nonnil  []string
+}
+
+// add records the inferred nilabilities (shallow and, if applicable, deep) of the annotation site
+// of the key under the given name. Sites without a name (see siteName) are skipped.
+func (a *inferredAnnotations) add(inferred *inference.InferredMap, key annotation.Key, name string, t types.Type) {
+	if t == nil || name == "" {
+		return
+	}
+	if !util.TypeBarsNilness(t) {
+		if nilable, ok := inferred.InferredNilability(key, false /* isDeep */); ok {
+			a.record(nilable, name)
+		}
+	}
+	if util.TypeIsDeepAtSite(t) {
+		if nilable, ok := inferred.InferredNilability(key, true /* isDeep */); ok {
+			a.record(nilable, deepName(name, t))
+		}
+	}
+}
+
+func (a *inferredAnnotations) record(nilable bool, name string) {
+	if nilable {
+		a.nilable = append(a.nilable, name)
+	} else {
+		a.nonnil = append(a.nonnil, name)
+	}
+}
+
+// comments returns the annotation comments (without the leading `//`), or nil if nothing is
+// inferred.
+func (a *inferredAnnotations) comments() []string {
+	var comments []string
+	if len(a.nilable) > 0 {
+		comments = append(comments, "nilable("+strings.Join(a.nilable, ", ")+")")
+	}
+	if len(a.nonnil) > 0 {
+		comments = append(comments, "nonnil("+strings.Join(a.nonnil, ", ")+")")
+	}
+	return comments
+}
+
+// deepName returns the name referring to the deep nilability of the site with the given type.
+func deepName(name string, t types.Type) string {
+	switch t.Underlying().(type) {
+	case *types.Chan:
+		return "<-" + name
+	case *types.Pointer:
+		return "*" + name
+	default:
+		return name + "[]"
+	}
+}
+
+// AnnotationDiagnostics returns a diagnostic for each exported declaration of the current package
+// whose nilability has been (at least partially) determined by inference, with a suggested fix
+// that documents the inferred nilability as annotation comments. This is the output of
+// config.FixModeAnnotate, which helps libraries lock in their nilability contracts.
+func (e *Engine) AnnotationDiagnostics(inferred *inference.InferredMap) []analysis.Diagnostic {
+	conf := e.pass.ResultOf[config.Analyzer].(*config.Config)
+
+	var diagnostics []analysis.Diagnostic
+	report := func(name *ast.Ident, insertPos token.Pos, anns *inferredAnnotations) {
+		comments := anns.comments()
+		if len(comments) == 0 {
+			return
+		}
+		tokFile := e.pass.Fset.File(insertPos)
+		content := e.fileContent(tokFile)
+		if content == nil {
+			return
+		}
+		lineStart := tokFile.Offset(tokFile.LineStart(tokFile.Line(insertPos)))
+		indent := string(content[lineStart:tokFile.Offset(insertPos)])
+		if strings.TrimLeft(indent, " \t") != "" {
+			return
+		}
+		var text strings.Builder
+		for _, c := range comments {
+			text.WriteString("// " + c + "\n" + indent)
+		}
+		diagnostics = append(diagnostics, analysis.Diagnostic{
+			Pos:     name.Pos(),
+			Message: fmt.Sprintf("inferred annotations for `%s`: %s", name.Name, strings.Join(comments, ", ")),
+			SuggestedFixes: []analysis.SuggestedFix{{
+				Message:   fmt.Sprintf("Annotate `%s` with the inferred nilability", name.Name),
+				TextEdits: []analysis.TextEdit{{Pos: insertPos, End: insertPos, NewText: []byte(text.String())}},
+			}},
+		})
+	}
+
+	for _, file := range e.pass.Files {
+		if !conf.IsFileInScope(file) || strings.HasSuffix(e.pass.Fset.File(file.Pos()).Name(), "_test.go") {
+			continue
+		}
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				funcObj, ok := e.pass.TypesInfo.ObjectOf(decl.Name).(*types.Func)
+				if !ok || !isExportedFunc(funcObj) {
+					continue
+				}
+				sig := funcObj.Type().(*types.Signature)
+				anns := &inferredAnnotations{}
+				if recv := sig.Recv(); recv != nil {
+					anns.add(inferred, &annotation.RecvAnnotationKey{FuncDecl: funcObj}, siteName(recv, "", 0), recv.Type())
+				}
+				for i := 0; i < sig.Params().Len(); i++ {
+					param := sig.Params().At(i)
+					t := param.Type()
+					if sig.Variadic() && i == sig.Params().Len()-1 {
+						// Variadic parameters are annotated by their element types.
+						t = t.(*types.Slice).Elem()
+					}
+					anns.add(inferred, annotation.ParamKeyFromArgNum(funcObj, i), siteName(param, "param", i), t)
+				}
+				for i := 0; i < sig.Results().Len(); i++ {
+					result := sig.Results().At(i)
+					anns.add(inferred, annotation.RetKeyFromRetNum(funcObj, i), siteName(result, "result", i), result.Type())
+				}
+				report(decl.Name, decl.Pos(), anns)
+
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					// Annotations are read from the doc string of the declaration if it only
+					// contains a single spec, otherwise from the doc string of the spec.
+					insertPos := spec.Pos()
+					if len(decl.Specs) == 1 {
+						insertPos = decl.Pos()
+					}
+					switch spec := spec.(type) {
+					case *ast.ValueSpec:
+						if decl.Tok != token.VAR {
+							continue
+						}
+						anns := &inferredAnnotations{}
+						for _, name := range spec.Names {
+							if v, ok := e.pass.TypesInfo.ObjectOf(name).(*types.Var); ok && v.Exported() {
+								anns.add(inferred, &annotation.GlobalVarAnnotationKey{VarDecl: v}, siteName(v, "", 0), v.Type())
+							}
+						}
+						if len(spec.Names) > 0 {
+							report(spec.Names[0], insertPos, anns)
+						}
+					case *ast.TypeSpec:
+						st, ok := spec.Type.(*ast.StructType)
+						if !ok || !spec.Name.IsExported() {
+							continue
+						}
+						anns := &inferredAnnotations{}
+						for _, field := range st.Fields.List {
+							for _, name := range field.Names {
+								if v, ok := e.pass.TypesInfo.ObjectOf(name).(*types.Var); ok && v.Exported() {
+									anns.add(inferred, &annotation.FieldAnnotationKey{FieldDecl: v}, siteName(v, "", 0), v.Type())
+								}
+							}
+						}
+						report(spec.Name, insertPos, anns)
+					}
+				}
+			}
+		}
+	}
+	return diagnostics
+}
+
+// isExportedFunc returns true if the function (or method) is part of the exported API of the
+// package.
+func isExportedFunc(funcObj *types.Func) bool {
+	if !funcObj.Exported() {
+		return false
+	}
+	recv := funcObj.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return true
+	}
+	named, ok := types.Unalias(util.UnwrapPtr(recv.Type())).(*types.Named)
+	return ok && named.Obj().Exported()
+}
+
+// siteName returns the name under which a variable is referred to in the annotation comments: its
+// name if it is named, otherwise its kind ("param" or "result") and index for unnamed parameters
+// and results. It returns an empty string if the variable cannot be referred to (e.g., "_").
+func siteName(v *types.Var, kind string, i int) string {
+	if v.Name() == "" && kind != "" {
+		return fmt.Sprintf("%s %d", kind, i)
+	}
+	if !annotatableNameRegex.MatchString(v.Name()) {
+		return ""
+	}
+	return v.Name()
+}
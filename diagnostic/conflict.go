@@ -18,8 +18,8 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
-	"path/filepath"
 	"strings"
+	"text/template"
 
 	"go.uber.org/nilaway/config"
 	"golang.org/x/tools/go/analysis"
@@ -28,33 +28,93 @@ import (
 type conflict struct {
 	// position is the package-independent position where the conflict should be reported.
 	position token.Position
+	// sink is the package-independent position of the consumption site (i.e., the dereference) of
+	// the conflict, which is the same as position unless another position is chosen for reporting
+	// (see Engine.SetReportPositionPolicy and Engine.SetReportAt).
+	sink token.Position
 	// flow stores nil flow from source to dereference point
 	flow nilFlow
 	// similarConflicts stores other conflicts that are similar to this one.
 	similarConflicts []*conflict
+	// consumerExpr is the dereferenced expression, if known (i.e., for single assertion conflicts).
+	consumerExpr ast.Expr
+	// consumerRepr is the description of the last consumer in the nonnil path (i.e., the one at
+	// the reported position) for overconstraint conflicts.
+	consumerRepr string
+	// deps stores the upstream objects that contributed to this conflict, only collected if
+	// Engine.EnableDepsDebugging is called.
+	deps []upstreamDep
+	// provenance explains the root causes of the nilabilities of the conflicting site, only
+	// collected for overconstraint conflicts if Engine.EnableProvenance is called.
+	provenance *Provenance
+	// atSource indicates whether this conflict is reported at the offending return statement
+	// rather than at the dereference (see Engine.SetReportAt).
+	atSource bool
+	// recovered indicates whether the dereference of this conflict is in a region recovering from
+	// panics, only marked if the conflicts there are downgraded (see Engine.SetRecoveredPanics).
+	recovered bool
+	// sourceOutOfScope is the import path of the package outside the analysis scope that the nil
+	// source of this conflict is in, only set if such conflicts are not reported as usual (see
+	// Engine.SetRequireSourceInScope).
+	sourceOutOfScope string
+	// instance describes the instantiation of the generic code that the conflicting site belongs
+	// to, empty if it is not instantiated (see inference.InstanceOf).
+	instance string
+	// instantiations is the number of the instantiations of the generic code whose conflicts at
+	// the same position are collapsed into this one (see collapseInstantiations), 0 if none.
+	instantiations int
+	// dedupKey is the key of this overconstraint conflict for the cross-package deduplication,
+	// empty if it is disabled (see Engine.EnableCrossPackageDedup).
+	dedupKey string
 }
 
-func (c *conflict) String() string {
-	// build string for similar conflicts (i.e., conflicts with the same nil path)
-	similarConflictsString := ""
-	if len(c.similarConflicts) > 0 {
-		similarPos := make([]string, len(c.similarConflicts))
-		for i, s := range c.similarConflicts {
-			similarPos[i] = fmt.Sprintf("\"%s\"", s.flow.nonnilPath[len(s.flow.nonnilPath)-1].consumerPosition.String())
-		}
-
-		posString := strings.Join(similarPos[:len(similarPos)-1], ", ")
-		if len(similarPos) > 1 {
-			posString = posString + ", and "
+// messageData returns the data for rendering the message of the conflict via a message template,
+// where the positions are formatted by the given path formatter.
+func (c *conflict) messageData(f *pathFormatter) MessageData {
+	data := MessageData{
+		Position:         f.formatPosition(c.position, f.reported(c.position)),
+		Provenance:       c.provenance,
+		Recovered:        c.recovered,
+		SourceOutOfScope: c.sourceOutOfScope,
+		Instantiations:   c.instantiations,
+	}
+	for _, n := range append(append([]node(nil), c.flow.nilPath...), c.flow.nonnilPath...) {
+		data.Flow = append(data.Flow, n.step(f))
+	}
+	if len(data.Flow) > 0 {
+		data.Source, data.Dereference = data.Flow[0], data.Flow[len(data.Flow)-1]
+	}
+	// similar conflicts are the ones with the same nil path, hence we only report their
+	// dereference points
+	for _, s := range c.similarConflicts {
+		deref := s.flow.nonnilPath[len(s.flow.nonnilPath)-1]
+		position := deref.consumerPosition.String()
+		if f != nil {
+			position = f.formatPosition(deref.position, deref.consumerPosition)
 		}
-		posString = posString + similarPos[len(similarPos)-1]
-
-		similarConflictsString = fmt.Sprintf("\n\n(Same nil source could also cause potential nil panic(s) at %d "+
-			"other place(s): %s.)", len(c.similarConflicts), posString)
+		data.SimilarPositions = append(data.SimilarPositions, position)
+	}
+	for _, dep := range c.deps {
+		data.Deps = append(data.Deps, dep.pkgPath+": "+dep.object)
 	}
+	return data
+}
 
-	return fmt.Sprintf("Potential nil panic detected. Observed nil flow from "+
-		"source to dereference point: %s%s\n", c.flow.String(), similarConflictsString)
+// message renders the message of the conflict with the given template and path formatter. If the rendering fails
+// (e.g., a custom template indexes into an empty list), the default template is used instead and
+// the failure is noted in the message.
+func (c *conflict) message(tmpl *template.Template, f *pathFormatter) string {
+	data := c.messageData(f)
+	var b strings.Builder
+	err := tmpl.Execute(&b, data)
+	if err == nil {
+		return b.String()
+	}
+	b.Reset()
+	if defaultErr := _defaultMessageTemplate.Execute(&b, data); defaultErr != nil {
+		panic(fmt.Sprintf("failed to render the default message template: %v", defaultErr))
+	}
+	return fmt.Sprintf("%s(failed to render the message template: %v)\n", b.String(), err)
 }
 
 func (c *conflict) addSimilarConflict(conflict conflict) {
@@ -62,12 +122,22 @@ func (c *conflict) addSimilarConflict(conflict conflict) {
 }
 
 // groupConflicts groups conflicts with the same nil path together and update conflicts list.
-func groupConflicts(allConflicts []conflict, pass *analysis.Pass, cwd string) []conflict {
+// The fileName function returns the package-independent file names of the files (see
+// Engine.fileName).
+func groupConflicts(allConflicts []conflict, pass *analysis.Pass, fileName func(*ast.File) string) []conflict {
 	conflictsMap := make(map[string]int)  // key: nil path string, value: index in `allConflicts`
 	indicesToIgnore := make(map[int]bool) // indices of conflicts to be ignored from `allConflicts`, since they are grouped with other conflicts
 
 	for i, c := range allConflicts {
 		key := pathString(c.flow.nilPath)
+		if c.atSource {
+			// The conflicts reported at the sources are only grouped among themselves.
+			key = "source:" + key
+		}
+		if c.recovered {
+			// The downgraded conflicts are only grouped among themselves as well.
+			key = "recovered:" + key
+		}
 
 		// Handle the case of single assertion conflict separately
 		if len(c.flow.nilPath) == 0 && len(c.flow.nonnilPath) == 1 {
@@ -95,13 +165,8 @@ func groupConflicts(allConflicts []conflict, pass *analysis.Pass, cwd string) []
 				// from different functions. To handle such cases, we prepend the enclosing function name to the key.
 				conf := pass.ResultOf[config.Analyzer].(*config.Config)
 				for _, file := range pass.Files {
-					// `fileName` stores the complete file path relative to the current working directory
-					fileName := pass.Fset.Position(file.FileStart).Filename
-					if fn, err := filepath.Rel(cwd, fileName); err == nil {
-						fileName = fn
-					}
 					// Check if the file is in scope and the conflict position is in the same file
-					if !conf.IsFileInScope(file) || fileName != c.position.Filename {
+					if !conf.IsFileInScope(file) || fileName(file) != c.position.Filename {
 						continue
 					}
 					for _, decl := range file.Decls {
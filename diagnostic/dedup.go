@@ -0,0 +1,121 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostic
+
+import (
+	"go/token"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// ReportedConflicts is the package fact listing the overconstraint conflicts (identified by their
+// conflicting sites and nil flows) reported by a package, for the cross-package deduplication
+// (see EnableCrossPackageDedup).
+type ReportedConflicts struct {
+	// Keys are the keys of the reported conflicts (see Engine.dedupKeyOf), sorted.
+	Keys []string
+}
+
+// AFact is a marker method to make ReportedConflicts an analysis.Fact.
+func (*ReportedConflicts) AFact() {}
+
+// String returns the string representation of the fact.
+func (r *ReportedConflicts) String() string {
+	return "ReportedConflicts(" + strings.Join(r.Keys, ", ") + ")"
+}
+
+// EnableCrossPackageDedup enables the deduplication of the overconstraint conflicts across
+// packages. Such conflicts can be fully determined by the facts from the upstream packages (e.g.,
+// an upstream field assigned nil in one package but dereferenced in another), in which case all
+// the downstream packages importing them observe the same conflict again. With deduplication,
+// the first package reporting a conflict (in the dependency order) exports it as a
+// ReportedConflicts fact such that the downstream packages skip it. Note that the packages not
+// depending on each other cannot see each other's facts, so they may still report the same
+// conflict. Since the drivers only print the diagnostics of the packages they are asked to
+// analyze (not of their dependencies), this should only be enabled if all analyzed packages are
+// reported, otherwise the conflicts reported by a dependency are lost.
+func (e *Engine) EnableCrossPackageDedup() {
+	e.upstreamConflicts = make(map[string]bool)
+	e.dedupKeys = make(map[reportedDiagnostic][]string)
+	for _, fact := range e.pass.AllPackageFacts() {
+		if reported, ok := fact.Fact.(*ReportedConflicts); ok {
+			for _, key := range reported.Keys {
+				e.upstreamConflicts[key] = true
+			}
+		}
+	}
+}
+
+// reportedDiagnostic identifies a diagnostic generated by the engine, for matching the final
+// diagnostics of the package (see ExportReportedConflicts) back to their conflicts.
+type reportedDiagnostic struct {
+	pos     token.Pos
+	message string
+}
+
+// dedupKeyOf returns the key of the overconstraint conflict on the site with the nil flow for the
+// cross-package deduplication, or empty if deduplication is not enabled.
+func (e *Engine) dedupKeyOf(site string, flow nilFlow) string {
+	if e.upstreamConflicts == nil {
+		return ""
+	}
+	return site + ":" + flow.String()
+}
+
+// isReportedUpstream returns true if the overconstraint conflict with the key (see dedupKeyOf) is
+// reported by an upstream package. It always returns false if deduplication is not enabled.
+func (e *Engine) isReportedUpstream(key string) bool {
+	return key != "" && e.upstreamConflicts[key]
+}
+
+// recordDedupKeys records the keys of the conflict (including the ones grouped under it) for the
+// diagnostic generated from it, if deduplication is enabled.
+func (e *Engine) recordDedupKeys(d analysis.Diagnostic, c conflict) {
+	if e.dedupKeys == nil {
+		return
+	}
+	var keys []string
+	for _, k := range append([]*conflict{&c}, c.similarConflicts...) {
+		if k.dedupKey != "" {
+			keys = append(keys, k.dedupKey)
+		}
+	}
+	if len(keys) > 0 {
+		rd := reportedDiagnostic{pos: d.Pos, message: d.Message}
+		e.dedupKeys[rd] = append(e.dedupKeys[rd], keys...)
+	}
+}
+
+// ExportReportedConflicts exports the overconstraint conflicts of the given diagnostics -- the
+// ones finally reported by the current package, i.e., after all the filtering -- as a
+// ReportedConflicts fact, if deduplication is enabled (see EnableCrossPackageDedup). Only the
+// conflicts not reported by the upstream packages are exported, since the downstream packages
+// have access to the facts of all their transitive dependencies.
+func (e *Engine) ExportReportedConflicts(diagnostics []analysis.Diagnostic) {
+	if len(e.dedupKeys) == 0 {
+		return
+	}
+	var keys []string
+	for _, d := range diagnostics {
+		keys = append(keys, e.dedupKeys[reportedDiagnostic{pos: d.Pos, message: d.Message}]...)
+	}
+	if len(keys) == 0 {
+		return
+	}
+	slices.Sort(keys)
+	e.pass.ExportPackageFact(&ReportedConflicts{Keys: slices.Compact(keys)})
+}
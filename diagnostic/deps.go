@@ -0,0 +1,145 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostic
+
+import (
+	"cmp"
+	"fmt"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"slices"
+
+	"go.uber.org/nilaway/annotation"
+	"go.uber.org/nilaway/inference"
+)
+
+// upstreamDep is an object of an upstream package whose nilability (imported via facts)
+// contributed to a conflict. These are reported in the debug mode enabled by EnableDepsDebugging.
+type upstreamDep struct {
+	// pkgPath is the import path of the upstream package.
+	pkgPath string
+	// object describes the object (or the step in the nil flow) in the upstream package.
+	object string
+}
+
+// EnableDepsDebugging enables listing the upstream packages (and the objects in them) that
+// contributed facts to each diagnostic, which helps users understand why an error appears in a
+// package they did not change.
+func (e *Engine) EnableDepsDebugging() {
+	e.debugDeps = true
+}
+
+// singleAssertionDeps returns the upstream objects whose annotation sites are read by the
+// producer or the consumer of the trigger.
+func (e *Engine) singleAssertionDeps(trigger annotation.FullTrigger) []upstreamDep {
+	if !e.debugDeps {
+		return nil
+	}
+	var deps []upstreamDep
+	for _, site := range []annotation.Key{trigger.Producer.Annotation.UnderlyingSite(), trigger.Consumer.Annotation.UnderlyingSite()} {
+		if site == nil {
+			continue
+		}
+		obj := site.Object()
+		if obj == nil || obj.Pkg() == nil || obj.Pkg() == e.pass.Pkg {
+			continue
+		}
+		deps = append(deps, upstreamDep{pkgPath: obj.Pkg().Path(), object: site.String()})
+	}
+	return sortDeps(deps)
+}
+
+// overconstraintDeps returns the steps of the explanations of an overconstraint conflict that are
+// located in upstream packages (i.e., determined by the facts of the upstream packages).
+func (e *Engine) overconstraintDeps(reasons ...inference.ExplainedBool) []upstreamDep {
+	if !e.debugDeps {
+		return nil
+	}
+	var deps []upstreamDep
+	for _, reason := range reasons {
+		for r := reason; r != nil; r = r.DeeperReason() {
+			pkgPath, ok := e.upstreamPkgOf(r.Position())
+			if !ok {
+				continue
+			}
+			// The representations of the triggers already contain their positions.
+			object := fmt.Sprintf("%s at \"%s\"", r.String(), r.Position())
+			if producer, consumer := r.TriggerReprs(); producer != nil && consumer != nil {
+				object = producer.String() + " " + consumer.String()
+			}
+			deps = append(deps, upstreamDep{pkgPath: pkgPath, object: object})
+		}
+	}
+	return sortDeps(deps)
+}
+
+// upstreamPkgOf returns the import path of the upstream package containing the position, if any.
+// The packages of the files are found from the (package-level) objects and methods of all
+// transitively imported packages, hence files without any of them are unknown.
+func (e *Engine) upstreamPkgOf(position token.Position) (string, bool) {
+	if e.filePkgs == nil {
+		e.filePkgs = make(map[string]string)
+		addPos := func(pos token.Pos, pkg *types.Package) {
+			file := e.pass.Fset.File(pos)
+			if file == nil {
+				return
+			}
+			name := file.Name()
+			if rel, err := filepath.Rel(e.cwd, name); err == nil {
+				name = rel
+			}
+			if _, ok := e.filePkgs[name]; !ok {
+				e.filePkgs[name] = pkg.Path()
+			}
+		}
+		visited := make(map[*types.Package]bool)
+		var visit func(pkg *types.Package)
+		visit = func(pkg *types.Package) {
+			if visited[pkg] {
+				return
+			}
+			visited[pkg] = true
+			scope := pkg.Scope()
+			for _, name := range scope.Names() {
+				obj := scope.Lookup(name)
+				addPos(obj.Pos(), pkg)
+				if named, ok := obj.Type().(*types.Named); ok {
+					for i := 0; i < named.NumMethods(); i++ {
+						addPos(named.Method(i).Pos(), pkg)
+					}
+				}
+			}
+			for _, imported := range pkg.Imports() {
+				visit(imported)
+			}
+		}
+		visit(e.pass.Pkg)
+	}
+
+	pkgPath, ok := e.filePkgs[position.Filename]
+	if !ok || pkgPath == e.pass.Pkg.Path() {
+		return "", false
+	}
+	return pkgPath, true
+}
+
+// sortDeps sorts and deduplicates the dependencies.
+func sortDeps(deps []upstreamDep) []upstreamDep {
+	slices.SortFunc(deps, func(a, b upstreamDep) int {
+		return cmp.Or(cmp.Compare(a.pkgPath, b.pkgPath), cmp.Compare(a.object, b.object))
+	})
+	return slices.Compact(deps)
+}
@@ -24,10 +24,14 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"text/template"
 
 	"go.uber.org/nilaway/annotation"
+	"go.uber.org/nilaway/config"
 	"go.uber.org/nilaway/inference"
+	"go.uber.org/nilaway/suppression"
 	"go.uber.org/nilaway/util"
+	"go.uber.org/nilaway/util/asthelper"
 	"golang.org/x/tools/go/analysis"
 )
 
@@ -49,6 +53,66 @@ type Engine struct {
 	// cwd is the current working directory for trimming the file names to get truly package- and
 	// build-system- (bazel for example adds a random sandbox prefix) independent positions.
 	cwd string
+	// fixPolicy is the policy for the suggested nil guards, empty if fixes are disabled.
+	fixPolicy string
+	// consumers maps the positions to the full triggers of the current package whose consumers
+	// are at that position, for recovering the dereferenced expressions of the conflicts.
+	consumers map[token.Pos][]annotation.FullTrigger
+	// debugDeps indicates whether the upstream dependencies of the conflicts should be listed in
+	// the diagnostics (see EnableDepsDebugging).
+	debugDeps bool
+	// filePkgs maps the file names (modulo the possible build-system prefix) to the import paths
+	// of the packages containing them, lazily built for debugDeps (see upstreamPkgOf).
+	filePkgs map[string]string
+	// explainProvenance indicates whether the root causes of the nilabilities of the conflicting
+	// sites should be explained in the diagnostics (see EnableProvenance).
+	explainProvenance bool
+	// messageTemplate renders the messages of the diagnostics (see SetMessageTemplate).
+	messageTemplate *template.Template
+	// pathFormatter formats the positions in the messages (see SetPathFormat).
+	pathFormatter *pathFormatter
+	// reportAt is where the overconstraint conflicts on function results are reported, empty
+	// means the default (sinks) (see SetReportAt).
+	reportAt string
+	// positionPolicy is the policy for choosing the primary positions of the conflicts, empty
+	// means the default (consumption sites) (see SetReportPositionPolicy).
+	positionPolicy string
+	// moduleRoots caches the module roots of the directories for positionPolicy, lazily created.
+	moduleRoots moduleRootCache
+	// currentModuleRoot is the root of the module containing the current package, empty if it is
+	// not in a module. It is only valid if moduleRoots is created.
+	currentModuleRoot string
+	// strictExportConflicts maps the parameters of the exported functions assumed nilable in the
+	// strict mode to the indices of their first conflicts in conflicts (see addStrictExportConflict).
+	strictExportConflicts map[string]int
+	// recoveredPanics is how the conflicts in the regions recovering from panics are reported,
+	// empty means the default (as usual) (see SetRecoveredPanics).
+	recoveredPanics string
+	// checkStructInit is how the conflicts of the struct initialization checking are reported,
+	// empty means the default (as usual) (see SetCheckStructInit).
+	checkStructInit string
+	// requireSourceInScope is how the conflicts whose nil sources are outside the analysis scope
+	// are reported, empty means the default (as usual) (see SetRequireSourceInScope).
+	requireSourceInScope string
+	// isPkgPathInScope returns true iff the package with the given import path is in the analysis
+	// scope, only set along with requireSourceInScope.
+	isPkgPathInScope func(pkgPath string) bool
+	// suppressed is the number of the conflicts suppressed by each mechanism (see Suppressed).
+	suppressed suppression.Counts
+	// upstreamConflicts is the set of keys of the overconstraint conflicts reported by the
+	// upstream packages, nil if the cross-package deduplication is disabled (see
+	// EnableCrossPackageDedup).
+	upstreamConflicts map[string]bool
+	// dedupKeys maps the diagnostics generated from the overconstraint conflicts to the keys of
+	// those conflicts, nil if the cross-package deduplication is disabled (see
+	// ExportReportedConflicts).
+	dedupKeys map[reportedDiagnostic][]string
+	// cgoFiles is the set of files of the current package that are rewritten by cgo, whose
+	// positions are adjusted by their "//line" directives (see position).
+	cgoFiles map[*token.File]bool
+	// disableLineDirectives indicates whether the positions in the messages should not be
+	// adjusted by the "//line" directives (see DisableLineDirectives).
+	disableLineDirectives bool
 }
 
 // NewEngine creates a new diagnostic engine.
@@ -92,63 +156,118 @@ func NewEngine(pass *analysis.Pass) *Engine {
 		}
 		return true
 	})
+	// The positions in the files rewritten by cgo are adjusted by their "//line" directives (see
+	// [inference.primitivizer.toPosition]), so we map the original source files to them as well.
+	// Note that the offsets of the adjusted positions are still the ones in the rewritten files.
+	cgoFiles := make(map[*token.File]bool)
+	for _, file := range pass.Files {
+		if !asthelper.IsCgoFile(pass.Fset, file) {
+			continue
+		}
+		name := pass.Fset.Position(file.Package).Filename
+		if rel, err := filepath.Rel(cwd, name); err == nil {
+			name = rel
+		}
+		files[name] = fileInfo{file: pass.Fset.File(file.Package)}
+		cgoFiles[pass.Fset.File(file.Package)] = true
+	}
 
-	return &Engine{pass: pass, files: files, cwd: cwd}
+	e := &Engine{pass: pass, files: files, cwd: cwd, cgoFiles: cgoFiles, messageTemplate: _defaultMessageTemplate, suppressed: make(suppression.Counts)}
+	e.pathFormatter = &pathFormatter{format: config.PathFormatShort, adjust: e.adjust}
+	return e
 }
 
 // Diagnostics generates diagnostics from the internally-stored conflicts. The grouping parameter
 // controls whether the conflicts with the same nil flow -- the part in the complete nil flow going
 // from a nilable source point to the conflict point -- are grouped together (under the first
-// diagnostic) for concise reporting. The returned slice of diagnostics are sorted by file names
-// and then offsets in the file.
+// diagnostic) for concise reporting. The returned slice of diagnostics are sorted by file names,
+// offsets in the file, and then the messages, such that the order is deterministic across runs.
 func (e *Engine) Diagnostics(grouping bool) []analysis.Diagnostic {
+	e.applyRecoveredPanics()
+	e.applySourceScope()
+	structInitCount := e.applyStructInitCount()
+
 	// First sort the conflicts by position such that similar conflicts are grouped under the
-	// first diagnostic.
-	slices.SortFunc(e.conflicts, func(a, b conflict) int {
+	// first diagnostic. Conflicts at the same position are further ordered by their messages
+	// since the order in which they are added depends on the order of inference.
+	slices.SortStableFunc(e.conflicts, func(a, b conflict) int {
 		if n := cmp.Compare(a.position.Filename, b.position.Filename); n != 0 {
 			return n
 		}
-		return cmp.Compare(a.position.Offset, b.position.Offset)
+		if n := cmp.Compare(a.position.Offset, b.position.Offset); n != 0 {
+			return n
+		}
+		return cmp.Compare(a.flow.String(), b.flow.String())
 	})
 
-	conflicts := e.conflicts
+	// Collapse the conflicts repeated for the instantiations of the same generic code.
+	conflicts := collapseInstantiations(e.conflicts)
 	if grouping {
 		// Group conflicts with the same nil path together for concise reporting.
-		conflicts = groupConflicts(e.conflicts, e.pass, e.cwd)
+		conflicts = groupConflicts(conflicts, e.pass, e.fileName)
 	}
 
 	// Build diagnostics from conflicts.
 	diagnostics := make([]analysis.Diagnostic, 0, len(conflicts))
 	for _, c := range conflicts {
-		diagnostics = append(diagnostics, analysis.Diagnostic{
+		d := analysis.Diagnostic{
 			Pos:     e.toPos(c.position),
-			Message: c.String(),
-		})
+			Message: c.message(e.messageTemplate, e.pathFormatter),
+		}
+		if c.recovered {
+			d.Category = _recoveredCategory
+		}
+		if e.fixPolicy != "" && !c.atSource {
+			if fix := e.conflictFix(c); fix != nil {
+				d.SuggestedFixes = []analysis.SuggestedFix{*fix}
+			}
+		}
+		e.recordDedupKeys(d, c)
+		diagnostics = append(diagnostics, d)
+	}
+	if structInitCount != nil {
+		diagnostics = append(diagnostics, *structInitCount)
 	}
 	return diagnostics
 }
 
+// Suppressed returns the number of the conflicts suppressed so far by each mechanism (e.g., in the
+// regions recovering from panics, see SetRecoveredPanics).
+func (e *Engine) Suppressed() suppression.Counts {
+	return e.suppressed
+}
+
+// suppress records that n conflicts are suppressed by the mechanism.
+func (e *Engine) suppress(m suppression.Mechanism, n int) {
+	if n > 0 {
+		e.suppressed[m] += n
+	}
+}
+
 // AddSingleAssertionConflict adds a new single assertion conflict to the engine.
 func (e *Engine) AddSingleAssertionConflict(trigger annotation.FullTrigger) {
 	producer, consumer := trigger.Prestrings(e.pass)
 	flow := nilFlow{}
-	flow.addNonNilPathNode(producer, consumer)
+	position := e.position(trigger.Consumer.Pos())
+	flow.addNonNilPathNode(producer, consumer, position)
 
-	position := e.pass.Fset.Position(trigger.Consumer.Expr.Pos())
-	// Try to trim the build system prefix (i.e., the current working directory) from the position.
-	// If NilAway is running in a driver that does not add such prefix, we will hit an error here,
-	// but that is fine, and we just do not need to do anything.
-	if filename, err := filepath.Rel(e.cwd, position.Filename); err == nil {
-		position.Filename = filename
+	var production token.Position
+	if trigger.Producer.Expr != nil {
+		production = e.position(trigger.Producer.Expr.Pos())
 	}
 	e.conflicts = append(e.conflicts, conflict{
-		position: position,
-		flow:     flow,
+		position:         e.reportPosition(position, production),
+		sink:             position,
+		flow:             flow,
+		consumerExpr:     trigger.Consumer.Expr,
+		deps:             e.singleAssertionDeps(trigger),
+		sourceOutOfScope: e.singleAssertionSourceOutOfScope(trigger),
 	})
 }
 
-// AddOverconstraintConflict adds a new overconstraint conflict to the engine.
-func (e *Engine) AddOverconstraintConflict(nilReason, nonnilReason inference.ExplainedBool) {
+// AddOverconstraintConflict adds a new overconstraint conflict on the given site (described by
+// its string representation) to the engine.
+func (e *Engine) AddOverconstraintConflict(site string, nilReason, nonnilReason inference.ExplainedBool) {
 	flow := nilFlow{}
 
 	// Build nil path by traversing the inference graph from `nilReason` part of the overconstraint failure.
@@ -160,12 +279,12 @@ func (e *Engine) AddOverconstraintConflict(nilReason, nonnilReason inference.Exp
 		// 1. No annotation present (i.e., full inference): we have producer and consumer explanations available; use them directly
 		// 2: Annotation present (i.e., no inference): we construct the reason from the annotation string
 		if producer != nil && consumer != nil {
-			flow.addNilPathNode(producer, consumer)
+			flow.addNilPathNode(producer, consumer, r.Position())
 		} else {
 			flow.addNilPathNode(annotation.LocatedPrestring{
 				Contained: r,
-				Location:  util.TruncatePosition(r.Position()),
-			}, nil)
+				Location:  util.TruncatePosition(e.adjust(r.Position())),
+			}, nil, r.Position())
 		}
 	}
 
@@ -175,29 +294,70 @@ func (e *Engine) AddOverconstraintConflict(nilReason, nonnilReason inference.Exp
 	// Different from building the nil path above, here we also want to deduce the position where the error should be reported,
 	// i.e., the point of dereference where the nil panic would occur. In NilAway's context this is the last node
 	// in the non-nil path. Therefore, we keep updating `c.pos` until we reach the end of the non-nil path.
-	var reportPosition token.Position
+	var (
+		reportPosition token.Position
+		consumerRepr   string
+	)
 	for r := nonnilReason; r != nil; r = r.DeeperReason() {
 		producer, consumer := r.TriggerReprs()
 		position := r.Position()
+		consumerRepr = ""
 		// Similar to above, we have two cases here:
 		// 1. No annotation present (i.e., full inference): we have producer and consumer explanations available; use them directly
 		// 2: Annotation present (i.e., no inference): we construct the reason from the annotation string
 		if producer != nil && consumer != nil {
-			flow.addNonNilPathNode(producer, consumer)
+			flow.addNonNilPathNode(producer, consumer, position)
 			reportPosition = position
+			consumerRepr = consumer.String()
 		} else {
 			flow.addNonNilPathNode(annotation.LocatedPrestring{
 				Contained: r,
-				Location:  util.TruncatePosition(r.Position()),
-			}, nil)
+				Location:  util.TruncatePosition(e.adjust(r.Position())),
+			}, nil, position)
 			reportPosition = position
 		}
 	}
 
-	e.conflicts = append(e.conflicts, conflict{
-		position: reportPosition,
-		flow:     flow,
-	})
+	// The uninitialized fields annotated nonnil (e.g., by a field-level `//nonnil`) are reported at
+	// the constructions leaving them nil, rather than at the annotations.
+	if _, ok := nonnilReason.(inference.FalseBecauseAnnotation); ok && isUnassignedFld(nilReason) {
+		reportPosition = nilReason.Position()
+	}
+
+	var production token.Position
+	if len(flow.nilPath) > 0 {
+		production = flow.nilPath[0].position
+	}
+	c := conflict{
+		position:         e.reportPosition(reportPosition, production),
+		sink:             reportPosition,
+		flow:             flow,
+		consumerRepr:     consumerRepr,
+		deps:             e.overconstraintDeps(nilReason, nonnilReason),
+		provenance:       e.provenance(site, nilReason, nonnilReason),
+		instance:         instanceOf(nilReason, nonnilReason),
+		sourceOutOfScope: e.overconstraintSourceOutOfScope(production),
+		dedupKey:         e.dedupKeyOf(site, flow),
+	}
+	if e.addStrictExportConflict(nilReason, c) {
+		return
+	}
+	if e.isReportedUpstream(c.dedupKey) {
+		return
+	}
+	source, hasSource := e.returnSource(nilReason)
+	e.addReportedAt(c, source, hasSource)
+}
+
+// isUnassignedFld returns true iff the reason is a field left uninitialized by a construction of
+// the struct (i.e., produced by annotation.UnassignedFld).
+func isUnassignedFld(reason inference.ExplainedBool) bool {
+	producer, _ := reason.TriggerReprs()
+	if l, ok := producer.(annotation.LocatedPrestring); ok {
+		producer = l.Contained
+	}
+	_, ok := producer.(annotation.UnassignedFldPrestring)
+	return ok
 }
 
 // _fakeFileMaxLines is the maximum number of lines that the archive importer will add to a (fake)
@@ -0,0 +1,541 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostic
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"go.uber.org/nilaway/annotation"
+	"go.uber.org/nilaway/config"
+	"go.uber.org/nilaway/util"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// EnableGuardFixes makes the engine attach suggested fixes that insert nil guards before the
+// flagged dereferences to the diagnostics, using the given policy (see config.FixPolicyAuto and
+// friends) to choose the shape of the guards. The given triggers are the full triggers of the
+// current package, which are used to recover the dereferenced expressions for the conflicts found
+// by inference.
+func (e *Engine) EnableGuardFixes(policy string, triggers []annotation.FullTrigger) {
+	e.fixPolicy = policy
+	e.consumers = make(map[token.Pos][]annotation.FullTrigger)
+	for _, t := range triggers {
+		pos := t.Consumer.Expr.Pos()
+		e.consumers[pos] = append(e.consumers[pos], t)
+	}
+}
+
+// consumerExprOf returns the dereferenced expression of the conflict reported at pos, or nil if it
+// cannot be determined.
+//
+// nilable(result 0)
+func (e *Engine) consumerExprOf(c conflict, pos token.Pos) ast.Expr {
+	if c.consumerExpr != nil {
+		return c.consumerExpr
+	}
+	// For conflicts found by inference we only know the position and the description of the
+	// consumer, so we match them against the full triggers of the current package.
+	var found ast.Expr
+	for _, t := range e.consumers[pos] {
+		if _, consumer := t.Prestrings(e.pass); consumer.String() != c.consumerRepr {
+			continue
+		}
+		if found != nil && found != t.Consumer.Expr {
+			// Ambiguous, give up.
+			return nil
+		}
+		found = t.Consumer.Expr
+	}
+	return found
+}
+
+// conflictFix builds a suggested fix that guards all dereferences of the conflict, including the
+// ones of the similar conflicts grouped under it, or returns nil if no fix can be built.
+//
+// nilable(result 0)
+func (e *Engine) conflictFix(c conflict) *analysis.SuggestedFix {
+	var fixes []*analysis.SuggestedFix
+	for _, cc := range append([]*conflict{&c}, c.similarConflicts...) {
+		expr := e.consumerExprOf(*cc, e.toPos(cc.sink))
+		if expr == nil {
+			continue
+		}
+		if fix := e.guardFix(expr); fix != nil {
+			fixes = append(fixes, fix)
+		}
+	}
+	if len(fixes) <= 1 {
+		if len(fixes) == 0 {
+			return nil
+		}
+		return fixes[0]
+	}
+
+	// Merge the fixes into one, dropping duplicate edits (e.g., adding the same import).
+	merged := &analysis.SuggestedFix{Message: fmt.Sprintf("Insert nil guards for %d dereferences", len(fixes))}
+	type editKey struct {
+		pos, end token.Pos
+		text     string
+	}
+	seen := make(map[editKey]bool)
+	for _, fix := range fixes {
+		for _, edit := range fix.TextEdits {
+			k := editKey{pos: edit.Pos, end: edit.End, text: string(edit.NewText)}
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			merged.TextEdits = append(merged.TextEdits, edit)
+		}
+	}
+	return merged
+}
+
+// guardFix builds a suggested fix that inserts a nil guard for the given dereferenced expression,
+// or returns nil if no (safe) fix can be built.
+//
+// nilable(result 0)
+func (e *Engine) guardFix(expr ast.Expr) *analysis.SuggestedFix {
+	expr = astutil.Unparen(expr)
+	if !isGuardableExpr(expr) || !typeIsNilable(e.pass.TypesInfo.TypeOf(expr)) {
+		return nil
+	}
+
+	var file *ast.File
+	for _, f := range e.pass.Files {
+		if f.FileStart <= expr.Pos() && expr.End() <= f.FileEnd {
+			file = f
+			break
+		}
+	}
+	if file == nil {
+		return nil
+	}
+
+	// Find the statement (directly in a statement list) that contains the expression, and the
+	// signature of the function enclosing it.
+	path, _ := astutil.PathEnclosingInterval(file, expr.Pos(), expr.End())
+	var (
+		stmt ast.Stmt
+		sig  *types.Signature
+	)
+	for i, n := range path {
+		if stmt == nil {
+			s, ok := n.(ast.Stmt)
+			if !ok || i+1 >= len(path) {
+				continue
+			}
+			switch path[i+1].(type) {
+			case *ast.BlockStmt, *ast.CaseClause, *ast.CommClause:
+				stmt = s
+			}
+			continue
+		}
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			sig, _ = e.pass.TypesInfo.TypeOf(n).(*types.Signature)
+		case *ast.FuncDecl:
+			if obj := e.pass.TypesInfo.ObjectOf(n.Name); obj != nil {
+				sig, _ = obj.Type().(*types.Signature)
+			}
+		default:
+			continue
+		}
+		break
+	}
+	if stmt == nil || sig == nil {
+		return nil
+	}
+	switch stmt.(type) {
+	case *ast.CaseClause, *ast.CommClause:
+		// The expression is in a case expression, we cannot insert anything before it.
+		return nil
+	}
+	// All variables in the expression must be declared before the statement, otherwise the guard
+	// would refer to undeclared variables (e.g., `if v := f(); v.x > 0 {...}`).
+	declaredBefore := true
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			ast.Inspect(n.X, func(n ast.Node) bool {
+				if id, ok := n.(*ast.Ident); ok {
+					if obj := e.pass.TypesInfo.Uses[id]; obj != nil && obj.Pos() >= stmt.Pos() && obj.Pos() < stmt.End() {
+						declaredBefore = false
+					}
+				}
+				return declaredBefore
+			})
+			return false
+		case *ast.Ident:
+			if obj := e.pass.TypesInfo.Uses[n]; obj != nil && obj.Pos() >= stmt.Pos() && obj.Pos() < stmt.End() {
+				declaredBefore = false
+			}
+		}
+		return declaredBefore
+	})
+	if !declaredBefore {
+		return nil
+	}
+
+	tokFile := e.pass.Fset.File(stmt.Pos())
+	content := e.fileContent(tokFile)
+	if content == nil {
+		return nil
+	}
+	stmtStart, stmtEnd := tokFile.Offset(stmt.Pos()), tokFile.Offset(stmt.End())
+	lineStart := tokFile.Offset(tokFile.LineStart(tokFile.Line(stmt.Pos())))
+	indent := string(content[lineStart:stmtStart])
+	if strings.TrimLeft(indent, " \t") != "" {
+		// The statement does not start its own line, we do not attempt to fix such code.
+		return nil
+	}
+	exprText := string(content[tokFile.Offset(expr.Pos()):tokFile.Offset(expr.End())])
+	message := strconv.Quote(fmt.Sprintf("unexpected nil value: %s", exprText))
+
+	errorReturning := sig.Results().Len() > 0 &&
+		types.Identical(sig.Results().At(sig.Results().Len()-1).Type(), util.ErrorType)
+	policy := e.fixPolicy
+	if policy == config.FixPolicyAuto {
+		switch {
+		case errorReturning:
+			policy = config.FixPolicyReturn
+		case isWrappableStmt(stmt) && !isTerminatingStmt(e.pass.TypesInfo, stmt, ""):
+			policy = config.FixPolicyWrap
+		default:
+			policy = config.FixPolicyPanic
+		}
+	}
+	if policy == config.FixPolicyWrap && (!isWrappableStmt(stmt) || isTerminatingStmt(e.pass.TypesInfo, stmt, "")) {
+		policy = config.FixPolicyPanic
+	}
+
+	var edits []analysis.TextEdit
+	var body string
+	switch policy {
+	case config.FixPolicyWrap:
+		// Indent the continuation lines of the statement by one more level.
+		lines := strings.Split(string(content[stmtStart:stmtEnd]), "\n")
+		for i := 1; i < len(lines); i++ {
+			if lines[i] != "" {
+				lines[i] = "\t" + lines[i]
+			}
+		}
+		return &analysis.SuggestedFix{
+			Message: fmt.Sprintf("Wrap the statement in a nil check for `%s`", exprText),
+			TextEdits: []analysis.TextEdit{{
+				Pos: stmt.Pos(),
+				End: stmt.End(),
+				NewText: []byte("if " + exprText + " != nil {\n" +
+					indent + "\t" + strings.Join(lines, "\n") + "\n" +
+					indent + "}"),
+			}},
+		}
+	case config.FixPolicyReturn:
+		values := make([]string, 0, sig.Results().Len())
+		for i := 0; i < sig.Results().Len(); i++ {
+			if errorReturning && i == sig.Results().Len()-1 {
+				errorsName, edit := errorsImport(file)
+				if edit != nil {
+					edits = append(edits, *edit)
+				}
+				values = append(values, errorsName+".New("+message+")")
+				continue
+			}
+			v, ok := zeroValue(sig.Results().At(i).Type(), e.pass.Pkg, file)
+			if !ok {
+				values = nil
+				break
+			}
+			values = append(values, v)
+		}
+		if len(values) != sig.Results().Len() {
+			// Fall back to panic if we cannot spell the zero values.
+			edits, body = nil, "panic("+message+")"
+		} else {
+			body = strings.TrimSpace("return " + strings.Join(values, ", "))
+		}
+	default:
+		body = "panic(" + message + ")"
+	}
+
+	edits = append(edits, analysis.TextEdit{
+		Pos: stmt.Pos(),
+		End: stmt.Pos(),
+		NewText: []byte("if " + exprText + " == nil {\n" +
+			indent + "\t" + body + "\n" +
+			indent + "}\n" + indent),
+	})
+	return &analysis.SuggestedFix{
+		Message:   fmt.Sprintf("Insert a nil guard for `%s`", exprText),
+		TextEdits: edits,
+	}
+}
+
+// fileContent returns the content of the file, or nil if the file cannot be read (or has been
+// modified since parsing).
+//
+// nilable(result 0)
+func (e *Engine) fileContent(tokFile *token.File) []byte {
+	if e.pass.ReadFile == nil {
+		return nil
+	}
+	content, err := e.pass.ReadFile(tokFile.Name())
+	if err != nil || tokFile.Size() != len(content) {
+		return nil
+	}
+	return content
+}
+
+// isGuardableExpr returns true if the expression can be evaluated again in a guard without side
+// effects, i.e., it is a chain of variable reads, field reads, pointer loads and indexes by
+// constants or variables.
+func isGuardableExpr(expr ast.Expr) bool {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr.Name != "_"
+	case *ast.ParenExpr:
+		return isGuardableExpr(expr.X)
+	case *ast.StarExpr:
+		return isGuardableExpr(expr.X)
+	case *ast.SelectorExpr:
+		return isGuardableExpr(expr.X)
+	case *ast.IndexExpr:
+		switch expr.Index.(type) {
+		case *ast.BasicLit, *ast.Ident:
+			return isGuardableExpr(expr.X)
+		}
+	}
+	return false
+}
+
+// typeIsNilable returns true if values of the type can be compared against nil.
+func typeIsNilable(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	switch t.Underlying().(type) {
+	case *types.Pointer, *types.Slice, *types.Map, *types.Chan, *types.Signature, *types.Interface:
+		return true
+	}
+	return false
+}
+
+// isWrappableStmt returns true if wrapping the statement in an if statement keeps the program
+// valid, i.e., the statement does not declare anything or alter the control flow.
+func isWrappableStmt(stmt ast.Stmt) bool {
+	switch stmt := stmt.(type) {
+	case *ast.ExprStmt, *ast.SendStmt, *ast.IncDecStmt, *ast.GoStmt, *ast.DeferStmt,
+		*ast.IfStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt, *ast.ForStmt, *ast.RangeStmt:
+		return true
+	case *ast.AssignStmt:
+		return stmt.Tok != token.DEFINE
+	}
+	return false
+}
+
+// isTerminatingStmt returns true if the statement is a terminating statement as defined by the Go
+// spec (and implemented by go/types), with label being the label of the statement if any. Wrapping
+// such statements in an if statement is not safe, since the enclosing function may then be
+// missing a return at its end.
+func isTerminatingStmt(info *types.Info, stmt ast.Stmt, label string) bool {
+	switch stmt := stmt.(type) {
+	case *ast.ReturnStmt:
+		return true
+	case *ast.BranchStmt:
+		return stmt.Tok == token.GOTO || stmt.Tok == token.FALLTHROUGH
+	case *ast.ExprStmt:
+		if call, ok := ast.Unparen(stmt.X).(*ast.CallExpr); ok {
+			if id, ok := ast.Unparen(call.Fun).(*ast.Ident); ok {
+				b, ok := info.Uses[id].(*types.Builtin)
+				return ok && b.Name() == "panic"
+			}
+		}
+	case *ast.LabeledStmt:
+		return isTerminatingStmt(info, stmt.Stmt, stmt.Label.Name)
+	case *ast.BlockStmt:
+		return isTerminatingList(info, stmt.List)
+	case *ast.IfStmt:
+		return stmt.Else != nil && isTerminatingStmt(info, stmt.Body, "") && isTerminatingStmt(info, stmt.Else, "")
+	case *ast.ForStmt:
+		return stmt.Cond == nil && !hasBreak(stmt.Body, label, true)
+	case *ast.SwitchStmt:
+		return isTerminatingSwitch(info, stmt.Body, label)
+	case *ast.TypeSwitchStmt:
+		return isTerminatingSwitch(info, stmt.Body, label)
+	case *ast.SelectStmt:
+		for _, s := range stmt.Body.List {
+			cc := s.(*ast.CommClause)
+			if !isTerminatingList(info, cc.Body) || hasBreakList(cc.Body, label, true) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// isTerminatingList returns true if the last non-empty statement of the list is terminating.
+func isTerminatingList(info *types.Info, list []ast.Stmt) bool {
+	for i := len(list) - 1; i >= 0; i-- {
+		if _, ok := list[i].(*ast.EmptyStmt); !ok {
+			return isTerminatingStmt(info, list[i], "")
+		}
+	}
+	return false
+}
+
+// isTerminatingSwitch returns true if the body of the (type) switch statement has a default case
+// and all of its cases end in a terminating statement (or a fallthrough) without breaking out of
+// the switch statement.
+func isTerminatingSwitch(info *types.Info, body *ast.BlockStmt, label string) bool {
+	hasDefault := false
+	for _, s := range body.List {
+		cc := s.(*ast.CaseClause)
+		if cc.List == nil {
+			hasDefault = true
+		}
+		if !isTerminatingList(info, cc.Body) || hasBreakList(cc.Body, label, true) {
+			return false
+		}
+	}
+	return hasDefault
+}
+
+// hasBreakList returns true if any of the statements has a break referring to the enclosing
+// statement (see hasBreak).
+func hasBreakList(list []ast.Stmt, label string, implicit bool) bool {
+	for _, s := range list {
+		if hasBreak(s, label, implicit) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasBreak returns true if the statement contains a break referring to the enclosing statement,
+// i.e., a break with the given label, or an unlabeled break if implicit is true (which it no
+// longer is inside nested for, switch and select statements).
+func hasBreak(stmt ast.Stmt, label string, implicit bool) bool {
+	switch stmt := stmt.(type) {
+	case *ast.BranchStmt:
+		if stmt.Tok == token.BREAK {
+			if stmt.Label == nil {
+				return implicit
+			}
+			return stmt.Label.Name == label
+		}
+	case *ast.LabeledStmt:
+		return hasBreak(stmt.Stmt, label, implicit)
+	case *ast.BlockStmt:
+		return hasBreakList(stmt.List, label, implicit)
+	case *ast.IfStmt:
+		return hasBreak(stmt.Body, label, implicit) || (stmt.Else != nil && hasBreak(stmt.Else, label, implicit))
+	case *ast.CaseClause:
+		return hasBreakList(stmt.Body, label, implicit)
+	case *ast.CommClause:
+		return hasBreakList(stmt.Body, label, implicit)
+	case *ast.SwitchStmt:
+		return label != "" && hasBreak(stmt.Body, label, false)
+	case *ast.TypeSwitchStmt:
+		return label != "" && hasBreak(stmt.Body, label, false)
+	case *ast.SelectStmt:
+		return label != "" && hasBreak(stmt.Body, label, false)
+	case *ast.ForStmt:
+		return label != "" && hasBreak(stmt.Body, label, false)
+	case *ast.RangeStmt:
+		return label != "" && hasBreak(stmt.Body, label, false)
+	}
+	return false
+}
+
+// errorsImport returns the name under which the "errors" package is imported in the file, and an
+// edit to add the import if it is missing.
+//
+// nilable(result 1)
+func errorsImport(file *ast.File) (string, *analysis.TextEdit) {
+	for _, spec := range file.Imports {
+		if spec.Path.Value != `"errors"` {
+			continue
+		}
+		if spec.Name == nil {
+			return "errors", nil
+		}
+		if spec.Name.Name != "_" && spec.Name.Name != "." {
+			return spec.Name.Name, nil
+		}
+	}
+
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT && gen.Lparen.IsValid() {
+			return "errors", &analysis.TextEdit{Pos: gen.Lparen + 1, End: gen.Lparen + 1, NewText: []byte("\n\t\"errors\"")}
+		}
+	}
+	return "errors", &analysis.TextEdit{Pos: file.Name.End(), End: file.Name.End(), NewText: []byte("\n\nimport \"errors\"")}
+}
+
+// zeroValue returns the source representation of the zero value of the type in the given file,
+// and false if it cannot be spelled (e.g., the type refers to a package not imported in the file).
+func zeroValue(t types.Type, pkg *types.Package, file *ast.File) (string, bool) {
+	t = types.Unalias(t)
+	_, isTypeParam := t.(*types.TypeParam)
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsBoolean != 0:
+			return "false", true
+		case u.Info()&types.IsString != 0:
+			return `""`, true
+		case u.Info()&types.IsNumeric != 0:
+			return "0", true
+		default:
+			return "nil", true
+		}
+	case *types.Pointer, *types.Slice, *types.Map, *types.Chan, *types.Signature, *types.Interface:
+		if !isTypeParam {
+			return "nil", true
+		}
+	}
+
+	// Composite types and type parameters, spell them out with the imports of the file.
+	ok := true
+	typeString := types.TypeString(t, func(other *types.Package) string {
+		if other == pkg {
+			return ""
+		}
+		for _, spec := range file.Imports {
+			if path, err := strconv.Unquote(spec.Path.Value); err != nil || path != other.Path() {
+				continue
+			}
+			if spec.Name == nil {
+				return other.Name()
+			}
+			if spec.Name.Name != "_" && spec.Name.Name != "." {
+				return spec.Name.Name
+			}
+		}
+		ok = false
+		return other.Name()
+	})
+	if isTypeParam {
+		return "*new(" + typeString + ")", ok
+	}
+	return typeString + "{}", ok
+}
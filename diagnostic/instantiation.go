@@ -0,0 +1,82 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostic
+
+import (
+	"go.uber.org/nilaway/inference"
+)
+
+// instanceOf returns the description of the instantiation of the generic code that the conflict
+// with the given nil and nonnil reasons comes from (see inference.InstanceOf), or an empty string
+// if neither reason comes from an instantiation.
+func instanceOf(nilReason, nonnilReason inference.ExplainedBool) string {
+	if instance := inference.InstanceOf(nilReason); instance != "" {
+		return instance
+	}
+	return inference.InstanceOf(nonnilReason)
+}
+
+// collapseInstantiations collapses the conflicts reported at the same position with the same
+// nonnil path (i.e., the same dereference) for different instantiations of the same generic code
+// (e.g., a parameter of a generic function passed nil by the callers of both `Deref[int]` and
+// `Deref[string]`) into the first one, recording the number of the instantiations affected. The
+// sites of the instantiations share their positions, hence the same error would otherwise be
+// repeated for each instantiation. If the conflicts at a position all come from a single
+// instantiation (e.g., nil passed by multiple callers of `Deref[int]`), they are kept as usual.
+// The conflicts are expected to be sorted.
+func collapseInstantiations(conflicts []conflict) []conflict {
+	type collapseKey struct {
+		position, sink, nonnilPath string
+		atSource, recovered        bool
+	}
+	keyOf := func(c conflict) collapseKey {
+		return collapseKey{
+			position:   c.position.String(),
+			sink:       c.sink.String(),
+			nonnilPath: pathString(c.flow.nonnilPath),
+			atSource:   c.atSource,
+			recovered:  c.recovered,
+		}
+	}
+
+	instances := make(map[collapseKey]map[string]bool)
+	for _, c := range conflicts {
+		if c.instance == "" {
+			continue
+		}
+		key := keyOf(c)
+		if instances[key] == nil {
+			instances[key] = make(map[string]bool)
+		}
+		instances[key][c.instance] = true
+	}
+
+	collapsed := make([]conflict, 0, len(conflicts))
+	seen := make(map[collapseKey]bool)
+	for _, c := range conflicts {
+		if c.instance != "" {
+			key := keyOf(c)
+			if n := len(instances[key]); n > 1 {
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				c.instantiations = n
+			}
+		}
+		collapsed = append(collapsed, c)
+	}
+	return collapsed
+}
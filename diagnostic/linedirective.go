@@ -0,0 +1,74 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostic
+
+import (
+	"go/ast"
+	"go/token"
+	"path/filepath"
+
+	"go.uber.org/nilaway/util/sourcemap"
+)
+
+// DisableLineDirectives disables adjusting the positions in the messages of the diagnostics by the
+// "//line" directives in the generated code (e.g., by yacc or templ), i.e., the positions in the
+// generated files are reported instead of the ones in the authored source files.
+//
+// Note that the positions where the diagnostics are reported are always adjusted by the drivers.
+func (e *Engine) DisableLineDirectives() {
+	e.disableLineDirectives = true
+}
+
+// position returns the package-independent position of pos for identifying the conflicts, which
+// is consistent with the positions of the annotation sites (see inference.primitivizer.toPosition):
+// the position is not adjusted by the "//line" directives, except in the files rewritten by cgo,
+// and the possible build-system prefix is trimmed. Such positions can be converted back to
+// token.Pos by toPos, and adjusted for reporting by adjust.
+func (e *Engine) position(pos token.Pos) token.Position {
+	position := e.pass.Fset.PositionFor(pos, e.cgoFiles[e.pass.Fset.File(pos)] /* adjusted */)
+	// If NilAway is running in a driver that does not add the build-system prefix, we will hit an
+	// error here, but that is fine, and we just do not need to do anything.
+	if filename, err := filepath.Rel(e.cwd, position.Filename); err == nil {
+		position.Filename = filename
+	}
+	return position
+}
+
+// fileName returns the package-independent file name of the file, consistent with the file names
+// of the positions returned by position.
+func (e *Engine) fileName(file *ast.File) string {
+	return e.position(file.Package).Filename
+}
+
+// adjust returns the position for reporting in the messages of the diagnostics for the
+// package-independent position (see position), which is adjusted by the "//line" directives (or
+// the registered source mappers, see sourcemap.Register) unless disabled. The positions in the
+// files not available locally (e.g., imported from archives) are returned as is, since the
+// directives are only known for the local files.
+func (e *Engine) adjust(position token.Position) token.Position {
+	info, ok := e.files[position.Filename]
+	if e.disableLineDirectives || !position.IsValid() || !ok || info.isFake {
+		return position
+	}
+	pos := info.file.Pos(position.Offset)
+	adjusted, ok := sourcemap.Position(e.pass.Fset, e.pass.Files, pos)
+	if !ok {
+		adjusted = e.pass.Fset.PositionFor(pos, true /* adjusted */)
+	}
+	if filename, err := filepath.Rel(e.cwd, adjusted.Filename); err == nil {
+		adjusted.Filename = filename
+	}
+	return adjusted
+}
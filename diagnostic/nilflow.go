@@ -17,6 +17,7 @@ package diagnostic
 import (
 	"fmt"
 	"go/token"
+	"slices"
 	"strings"
 
 	"go.uber.org/nilaway/annotation"
@@ -27,9 +28,11 @@ type nilFlow struct {
 	nonnilPath []node // stores non-nil path of the flow from conflict point to dereference point
 }
 
-// addNilPathNode adds a new node to the nil path.
-func (n *nilFlow) addNilPathNode(p annotation.Prestring, c annotation.Prestring) {
+// addNilPathNode adds a new node to the nil path, where position is the untruncated position of
+// the node (if known) for formatting (see pathFormatter).
+func (n *nilFlow) addNilPathNode(p annotation.Prestring, c annotation.Prestring, position token.Position) {
 	nodeObj := newNode(p, c)
+	nodeObj.position = position
 
 	// Note that in the implication graph, we traverse backwards from the point of conflict to the source of nilability.
 	// Therefore, they are added in reverse order from what the program flow would look like. To account for this we
@@ -38,9 +41,11 @@ func (n *nilFlow) addNilPathNode(p annotation.Prestring, c annotation.Prestring)
 	n.nilPath = append([]node{nodeObj}, n.nilPath...)
 }
 
-// addNonNilPathNode adds a new node to the non-nil path
-func (n *nilFlow) addNonNilPathNode(p annotation.Prestring, c annotation.Prestring) {
+// addNonNilPathNode adds a new node to the non-nil path, where position is the untruncated position
+// of the node (if known) for formatting (see pathFormatter).
+func (n *nilFlow) addNonNilPathNode(p annotation.Prestring, c annotation.Prestring, position token.Position) {
 	nodeObj := newNode(p, c)
+	nodeObj.position = position
 	n.nonnilPath = append(n.nonnilPath, nodeObj)
 }
 
@@ -57,18 +62,30 @@ func (n *nilFlow) String() string {
 	return "\n" + strings.Join(flow, "\n")
 }
 
+// isStructInit returns true iff any node of the flow is one of the struct initialization checking
+// (see isStructInitPrestring).
+func (n *nilFlow) isStructInit() bool {
+	return slices.ContainsFunc(n.nilPath, func(n node) bool { return n.structInit }) ||
+		slices.ContainsFunc(n.nonnilPath, func(n node) bool { return n.structInit })
+}
+
 type node struct {
 	producerPosition token.Position
 	consumerPosition token.Position
 	producerRepr     string
 	consumerRepr     string
+	// position is the untruncated position of the node (i.e., of the consumer), if known.
+	position token.Position
+	// structInit indicates whether the producer or the consumer of the node is one of the struct
+	// initialization checking (see isStructInitPrestring).
+	structInit bool
 }
 
 // newNode creates a new node object from the given producer and consumer Prestrings.
 // LocatedPrestring contains accurate information about the position and the reason why NilAway deemed that position
 // to be nilable. We use it if available, else we use the raw string representation available from the Prestring.
 func newNode(p annotation.Prestring, c annotation.Prestring) node {
-	nodeObj := node{}
+	nodeObj := node{structInit: isStructInitPrestring(p) || isStructInitPrestring(c)}
 
 	// get producer representation string
 	if l, ok := p.(annotation.LocatedPrestring); ok {
@@ -90,23 +107,18 @@ func newNode(p annotation.Prestring, c annotation.Prestring) node {
 }
 
 func (n *node) String() string {
-	posStr := "<no pos info>"
-	reasonStr := ""
-	if n.consumerPosition.IsValid() {
-		posStr = n.consumerPosition.String()
-	}
+	return fmt.Sprintf("\t- %s", n.step(nil /* default path format */))
+}
 
-	if len(n.producerRepr) > 0 {
-		reasonStr += n.producerRepr
-	}
-	if len(n.consumerRepr) > 0 {
-		if len(n.producerRepr) > 0 {
-			reasonStr += " "
-		}
-		reasonStr += n.consumerRepr
+// step returns the FlowStep of the node for rendering the message templates, with the position
+// formatted by the given path formatter.
+func (n *node) step(f *pathFormatter) FlowStep {
+	short := n.consumerPosition
+	if !short.IsValid() {
+		// The steps without consumers (e.g., the annotations) are located by their producers.
+		short = n.producerPosition
 	}
-
-	return fmt.Sprintf("\t- %s: %s", posStr, reasonStr)
+	return FlowStep{Position: f.formatPosition(n.position, short), Producer: n.producerRepr, Consumer: n.consumerRepr}
 }
 
 func pathString(nodes []node) string {
@@ -0,0 +1,149 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostic
+
+import (
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"go.uber.org/nilaway/config"
+)
+
+// PathData is the data passed to the templated path formats (e.g., for rendering links to the
+// code hosting service).
+type PathData struct {
+	// Path is the slash-separated path of the file relative to the root of its module, or the
+	// absolute path if the file is not in a module.
+	Path string
+	// Absolute is the absolute path of the file.
+	Absolute string
+	// Line is the line number (starting at 1).
+	Line int
+	// Column is the column number (starting at 1).
+	Column int
+}
+
+// pathFormatter formats the positions in the diagnostics (see Engine.SetPathFormat). A nil
+// formatter uses the default (short) format without adjusting the positions.
+type pathFormatter struct {
+	// format is either config.PathFormatShort, config.PathFormatAbsolute or
+	// config.PathFormatModule, or empty if tmpl is used.
+	format string
+	// tmpl is the template for the templated path format.
+	tmpl *template.Template
+	// cwd is the current working directory for resolving the relative file names.
+	cwd string
+	// moduleRoots caches the module root of each directory.
+	moduleRoots moduleRootCache
+	// adjust adjusts the package-independent positions for reporting (see Engine.adjust).
+	adjust func(token.Position) token.Position
+}
+
+// SetPathFormat sets the format of the positions in the messages of the diagnostics, i.e., the
+// positions where the diagnostics are reported, the steps of the nil flows, and the positions of
+// the similar errors. The format is either config.PathFormatShort (the default),
+// config.PathFormatAbsolute, config.PathFormatModule, or a Go text/template over PathData, e.g.,
+// "https://github.com/org/repo/blob/<commit SHA>/{{.Path}}#L{{.Line}}" for permalinks.
+func (e *Engine) SetPathFormat(format string) error {
+	f := &pathFormatter{format: format, cwd: e.cwd, moduleRoots: make(moduleRootCache), adjust: e.adjust}
+	switch format {
+	case "", config.PathFormatShort:
+		f.format = config.PathFormatShort
+	case config.PathFormatAbsolute, config.PathFormatModule:
+		// These formats do not need any further setup.
+	default:
+		tmpl, err := template.New("path").Parse(format)
+		if err != nil {
+			return fmt.Errorf("parse path format: %w", err)
+		}
+		if err := tmpl.Execute(new(strings.Builder), PathData{}); err != nil {
+			return fmt.Errorf("parse path format: %w", err)
+		}
+		f.format, f.tmpl = "", tmpl
+	}
+	e.pathFormatter = f
+	return nil
+}
+
+// formatPosition formats the position of a step in the diagnostics. Here, full is the
+// untruncated package-independent position (if known), and short is the truncated one (already
+// adjusted for reporting), which is used as is for the default format.
+func (f *pathFormatter) formatPosition(full token.Position, short token.Position) string {
+	full = f.reported(full)
+	if f == nil || f.format == config.PathFormatShort || !full.IsValid() {
+		if !short.IsValid() {
+			return "<no pos info>"
+		}
+		return short.String()
+	}
+
+	absolute := full.Filename
+	if !filepath.IsAbs(absolute) {
+		absolute = filepath.Join(f.cwd, absolute)
+	}
+	path := absolute
+	if f.format != config.PathFormatAbsolute {
+		if root := f.moduleRoots.root(filepath.Dir(absolute)); root != "" {
+			if rel, err := filepath.Rel(root, absolute); err == nil {
+				path = rel
+			}
+		}
+	}
+
+	if f.tmpl == nil {
+		position := full
+		position.Filename = path
+		return position.String()
+	}
+	var b strings.Builder
+	data := PathData{Path: filepath.ToSlash(path), Absolute: absolute, Line: full.Line, Column: full.Column}
+	if err := f.tmpl.Execute(&b, data); err != nil {
+		// This should not happen since the template is checked when set, but we fall back to the
+		// plain position just in case.
+		return full.String()
+	}
+	return b.String()
+}
+
+// reported returns the position for reporting of the package-independent position.
+func (f *pathFormatter) reported(position token.Position) token.Position {
+	if f == nil || f.adjust == nil {
+		return position
+	}
+	return f.adjust(position)
+}
+
+// moduleRootCache caches the module root of each directory, empty if not in a module.
+type moduleRootCache map[string]string
+
+// root returns the root of the module containing the directory, i.e., the closest enclosing
+// directory with a go.mod file, or an empty string if there is none.
+func (m moduleRootCache) root(dir string) string {
+	if root, ok := m[dir]; ok {
+		return root
+	}
+	root := ""
+	if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+		root = dir
+	} else if parent := filepath.Dir(dir); parent != dir {
+		root = m.root(parent)
+	}
+	m[dir] = root
+	return root
+}
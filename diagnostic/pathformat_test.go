@@ -0,0 +1,56 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostic
+
+import (
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/nilaway/config"
+)
+
+func TestPathFormat(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/foo\n"), 0o644))
+	full := token.Position{Filename: filepath.Join(root, "bar", "baz.go"), Line: 10, Column: 2}
+	short := token.Position{Filename: "bar/baz.go", Line: 10, Column: 2}
+
+	format := func(pathFormat string, full token.Position) string {
+		e := &Engine{cwd: root}
+		require.NoError(t, e.SetPathFormat(pathFormat))
+		return e.pathFormatter.formatPosition(full, short)
+	}
+
+	require.Equal(t, "bar/baz.go:10:2", format(config.PathFormatShort, full))
+	require.Equal(t, "bar/baz.go:10:2", format("", full))
+	require.Equal(t, full.Filename+":10:2", format(config.PathFormatAbsolute, full))
+	require.Equal(t, filepath.Join("bar", "baz.go")+":10:2", format(config.PathFormatModule, full))
+	require.Equal(t, "https://example.com/blob/abc/bar/baz.go#L10", format("https://example.com/blob/abc/{{.Path}}#L{{.Line}}", full))
+	// Relative file names are resolved against the working directory.
+	relative := token.Position{Filename: filepath.Join("bar", "baz.go"), Line: 10, Column: 2}
+	require.Equal(t, full.Filename+":10:2", format(config.PathFormatAbsolute, relative))
+	// The short position is used if the full one is unknown.
+	require.Equal(t, "bar/baz.go:10:2", format(config.PathFormatModule, token.Position{}))
+
+	// Invalid templates are reported early.
+	e := &Engine{cwd: root}
+	require.Error(t, e.SetPathFormat("{{.Path"))
+	require.ErrorContains(t, e.SetPathFormat("{{.Unknown}}"), "Unknown")
+}
@@ -0,0 +1,72 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostic
+
+import (
+	"fmt"
+
+	"go.uber.org/nilaway/inference"
+	"go.uber.org/nilaway/util"
+)
+
+// EnableProvenance enables explaining, for each overconstraint conflict, the root causes that
+// forced the conflicting site to be nilable and nonnil (e.g., "Param 0 of function `F` was
+// inferred NILABLE because literal `nil` passed as arg `p` to `F()` at a/b.go:42"). This makes
+// errors spanning multiple packages more actionable, since the root causes may be far away from
+// the reported position.
+func (e *Engine) EnableProvenance() {
+	e.explainProvenance = true
+}
+
+// provenance returns the explanation of the root causes (i.e., the deepest reasons) of the
+// nilabilities of the site, or nil if provenance is not enabled.
+func (e *Engine) provenance(site string, reasons ...inference.ExplainedBool) *Provenance {
+	if !e.explainProvenance || len(reasons) == 0 {
+		return nil
+	}
+	p := &Provenance{Site: site}
+	for _, reason := range reasons {
+		root := reason
+		for r := reason; r != nil; r = r.DeeperReason() {
+			root = r
+		}
+		if root == nil {
+			continue
+		}
+		p.Causes = append(p.Causes, rootCauseString(root, e.pathFormatter))
+	}
+	return p
+}
+
+// rootCauseString describes the root cause of a nilability, which is either an assertion that
+// always fires (e.g., a nil literal passed as an argument), an annotation, or an imported fact.
+// The positions are formatted by the given path formatter.
+func rootCauseString(root inference.ExplainedBool, f *pathFormatter) string {
+	nilability := "NONNIL"
+	if root.Val() {
+		nilability = "NILABLE"
+	}
+	if producer, consumer := root.TriggerReprs(); producer != nil && consumer != nil {
+		n := newNode(producer, consumer)
+		short := util.TruncatePosition(f.reported(root.Position()))
+		if n.consumerPosition.IsValid() {
+			short = n.consumerPosition
+		}
+		return fmt.Sprintf("inferred %s because %s %s at \"%s\"", nilability, n.producerRepr, n.consumerRepr, f.formatPosition(root.Position(), short))
+	}
+	// The explanations without triggers (i.e., annotations and imported facts) already describe
+	// the nilabilities themselves.
+	return fmt.Sprintf("%s at \"%s\"", root.String(), f.formatPosition(root.Position(), util.TruncatePosition(f.reported(root.Position()))))
+}
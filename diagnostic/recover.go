@@ -0,0 +1,188 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostic
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+
+	"go.uber.org/nilaway/config"
+	"go.uber.org/nilaway/suppression"
+)
+
+// _recoveredCategory is the category of the diagnostics downgraded for being in the regions
+// recovering from panics (see SetRecoveredPanics).
+const _recoveredCategory = "recovered"
+
+// recoveredRegion is a region of a file in which the panics are recovered, i.e., the rest of a
+// function body after a `defer` of a recovering function.
+type recoveredRegion struct {
+	// start and end are the offsets of the region in the file.
+	start, end int
+	// excluded are the offset ranges of the `go` statements in the region, whose panics happen
+	// in other goroutines and are hence not recovered.
+	excluded [][2]int
+}
+
+// SetRecoveredPanics sets how the conflicts whose dereferences are in the regions recovering from
+// panics are reported: config.RecoveredPanicsReport (the default, i.e., as usual),
+// config.RecoveredPanicsDowngrade (with the "recovered" category and a note in the messages), or
+// config.RecoveredPanicsSuppress (not at all).
+func (e *Engine) SetRecoveredPanics(policy string) {
+	e.recoveredPanics = policy
+}
+
+// applyRecoveredPanics marks the conflicts whose dereferences are in the regions recovering from
+// panics, and drops them if they should be suppressed.
+func (e *Engine) applyRecoveredPanics() {
+	if e.recoveredPanics == "" || e.recoveredPanics == config.RecoveredPanicsReport {
+		return
+	}
+	regions := e.recoveredRegions()
+	if len(regions) == 0 {
+		return
+	}
+	for i := range e.conflicts {
+		e.conflicts[i].recovered = isInRecoveredRegion(regions, e.conflicts[i].sink)
+	}
+	if e.recoveredPanics == config.RecoveredPanicsSuppress {
+		n := len(e.conflicts)
+		e.conflicts = slices.DeleteFunc(e.conflicts, func(c conflict) bool { return c.recovered })
+		e.suppress(suppression.RecoveredPanics, n-len(e.conflicts))
+	}
+}
+
+// isInRecoveredRegion returns true iff the position is in one of the recovered regions of its
+// file.
+func isInRecoveredRegion(regions map[string][]recoveredRegion, pos token.Position) bool {
+	for _, r := range regions[pos.Filename] {
+		if pos.Offset < r.start || pos.Offset >= r.end {
+			continue
+		}
+		if !slices.ContainsFunc(r.excluded, func(x [2]int) bool { return pos.Offset >= x[0] && pos.Offset < x[1] }) {
+			return true
+		}
+	}
+	return false
+}
+
+// recoveredRegions returns the regions recovering from panics in the files of the current
+// package, keyed by the file names (modulo the possible build-system prefix) like the positions
+// of the conflicts. A region starts after a `defer` of a function literal (or a function declared
+// in the package) that calls `recover()` directly, and extends to the end of the enclosing
+// function. The deferred functions re-panicking (e.g., wrapping the recovered values in other
+// panics) do not recover from the panics, hence the flows through them are still tracked as usual.
+func (e *Engine) recoveredRegions() map[string][]recoveredRegion {
+	funcDecls := make(map[types.Object]*ast.FuncDecl)
+	for _, file := range e.pass.Files {
+		for _, decl := range file.Decls {
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Body != nil {
+				funcDecls[e.pass.TypesInfo.Defs[funcDecl.Name]] = funcDecl
+			}
+		}
+	}
+
+	regions := make(map[string][]recoveredRegion)
+	for _, file := range e.pass.Files {
+		name := e.fileName(file)
+		ast.Inspect(file, func(node ast.Node) bool {
+			var body *ast.BlockStmt
+			switch node := node.(type) {
+			case *ast.FuncDecl:
+				body = node.Body
+			case *ast.FuncLit:
+				body = node.Body
+			}
+			if body == nil {
+				return true
+			}
+			inspectFuncBody(body, func(n ast.Node) {
+				deferStmt, ok := n.(*ast.DeferStmt)
+				if !ok || !e.isRecovering(deferStmt.Call.Fun, funcDecls) {
+					return
+				}
+				region := recoveredRegion{
+					start: e.pass.Fset.Position(deferStmt.End()).Offset,
+					end:   e.pass.Fset.Position(body.End()).Offset,
+				}
+				inspectFuncBody(body, func(n ast.Node) {
+					if goStmt, ok := n.(*ast.GoStmt); ok && goStmt.Pos() > deferStmt.Pos() {
+						region.excluded = append(region.excluded, [2]int{
+							e.pass.Fset.Position(goStmt.Pos()).Offset,
+							e.pass.Fset.Position(goStmt.End()).Offset,
+						})
+					}
+				})
+				regions[name] = append(regions[name], region)
+			})
+			return true
+		})
+	}
+	return regions
+}
+
+// isRecovering returns true iff the deferred function is a function literal or a function declared
+// in the current package whose body calls `recover()` directly without calling `panic()`.
+func (e *Engine) isRecovering(fun ast.Expr, funcDecls map[types.Object]*ast.FuncDecl) bool {
+	var body *ast.BlockStmt
+	switch fun := ast.Unparen(fun).(type) {
+	case *ast.FuncLit:
+		body = fun.Body
+	case *ast.Ident:
+		if funcDecl, ok := funcDecls[e.pass.TypesInfo.Uses[fun]]; ok {
+			body = funcDecl.Body
+		}
+	}
+	if body == nil {
+		return false
+	}
+
+	recovers, panics := false, false
+	inspectFuncBody(body, func(n ast.Node) {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return
+		}
+		ident, ok := ast.Unparen(call.Fun).(*ast.Ident)
+		if !ok {
+			return
+		}
+		if builtin, ok := e.pass.TypesInfo.Uses[ident].(*types.Builtin); ok {
+			switch builtin.Name() {
+			case "recover":
+				recovers = true
+			case "panic":
+				panics = true
+			}
+		}
+	})
+	return recovers && !panics
+}
+
+// inspectFuncBody calls f on the nodes in the function body, excluding those in the nested
+// function literals (which are different functions).
+func inspectFuncBody(body *ast.BlockStmt, f func(ast.Node)) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		if n != nil {
+			f(n)
+		}
+		return true
+	})
+}
@@ -0,0 +1,77 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostic
+
+import (
+	"go/token"
+
+	"go.uber.org/nilaway/annotation"
+	"go.uber.org/nilaway/config"
+	"go.uber.org/nilaway/inference"
+)
+
+// SetReportAt sets where the overconstraint conflicts on the results of the functions are
+// reported (see config.ReportAtSink, config.ReportAtSource and config.ReportAtBoth). By default,
+// they are reported at the sinks, i.e., the dereferences at the callers. Reporting them at the
+// sources, i.e., the offending return statements in the functions inferred to return nonnil
+// results, gives the owners of the functions actionable reports instead.
+func (e *Engine) SetReportAt(reportAt string) {
+	e.reportAt = reportAt
+}
+
+// returnSource returns the position of the return statement in the current package through which
+// the nil value flows into the conflicting site, if the nil reason of the conflict is such a
+// return (i.e., the site is a function result inferred NILABLE because of the return).
+func (e *Engine) returnSource(nilReason inference.ExplainedBool) (token.Position, bool) {
+	_, consumer := nilReason.TriggerReprs()
+	if l, ok := consumer.(annotation.LocatedPrestring); ok {
+		consumer = l.Contained
+	}
+	if _, ok := consumer.(annotation.UseAsReturnPrestring); !ok {
+		return token.Position{}, false
+	}
+	position := nilReason.Position()
+	if !position.IsValid() || !e.isLocalFile(position.Filename) {
+		return token.Position{}, false
+	}
+	return position, true
+}
+
+// isLocalFile returns true if the file (modulo the possible build-system prefix) belongs to the
+// current package.
+func (e *Engine) isLocalFile(filename string) bool {
+	for _, file := range e.pass.Files {
+		if e.fileName(file) == filename {
+			return true
+		}
+	}
+	return false
+}
+
+// addReportedAt adds the overconstraint conflict to the engine at the sink and/or the source
+// according to the reportAt setting, where source is the position of the offending return
+// statement (if any, see returnSource).
+func (e *Engine) addReportedAt(c conflict, source token.Position, hasSource bool) {
+	if !hasSource || e.reportAt == "" || e.reportAt == config.ReportAtSink {
+		e.conflicts = append(e.conflicts, c)
+		return
+	}
+	if e.reportAt == config.ReportAtBoth {
+		e.conflicts = append(e.conflicts, c)
+	}
+	c.position = source
+	c.atSource = true
+	e.conflicts = append(e.conflicts, c)
+}
@@ -0,0 +1,77 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostic
+
+import (
+	"go/token"
+	"path/filepath"
+
+	"go.uber.org/nilaway/config"
+)
+
+// SetReportPositionPolicy sets the policy for choosing the primary positions of the diagnostics
+// among the production sites (i.e., the nil sources) and the consumption sites (i.e., the
+// dereferences) of the nil flows: config.ReportPositionConsumption (the default),
+// config.ReportPositionProduction, or config.ReportPositionModule, which chooses the site within
+// the current module when the flow crosses packages. The nil flows in the messages are intact
+// either way.
+func (e *Engine) SetReportPositionPolicy(policy string) {
+	e.positionPolicy = policy
+}
+
+// reportPosition returns the primary position of a conflict, given the positions of its
+// consumption site and production site (invalid if unknown), according to the policy.
+func (e *Engine) reportPosition(consumption, production token.Position) token.Position {
+	if !production.IsValid() {
+		return consumption
+	}
+	switch e.positionPolicy {
+	case config.ReportPositionProduction:
+		return production
+	case config.ReportPositionModule:
+		if !e.inCurrentModule(consumption) && e.inCurrentModule(production) {
+			return production
+		}
+	}
+	return consumption
+}
+
+// inCurrentModule returns true if the position (modulo the possible build-system prefix) is in
+// the module containing the current package. Outside modules (e.g., in GOPATH mode), only the
+// files of the current package are considered in the current module.
+func (e *Engine) inCurrentModule(position token.Position) bool {
+	if !position.IsValid() {
+		return false
+	}
+	if e.moduleRoots == nil {
+		e.moduleRoots = make(moduleRootCache)
+		if len(e.pass.Files) > 0 {
+			e.currentModuleRoot = e.moduleRoots.root(filepath.Dir(e.absolute(e.pass.Fset.Position(e.pass.Files[0].Package).Filename)))
+		}
+	}
+	if e.currentModuleRoot == "" {
+		return e.isLocalFile(position.Filename)
+	}
+	return e.moduleRoots.root(filepath.Dir(e.absolute(position.Filename))) == e.currentModuleRoot
+}
+
+// absolute returns the absolute path of the file name, which may be relative to the current
+// working directory (i.e., with the build-system prefix trimmed).
+func (e *Engine) absolute(filename string) string {
+	if filepath.IsAbs(filename) {
+		return filename
+	}
+	return filepath.Join(e.cwd, filename)
+}
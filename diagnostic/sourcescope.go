@@ -0,0 +1,119 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostic
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+
+	"go.uber.org/nilaway/annotation"
+	"go.uber.org/nilaway/config"
+	"go.uber.org/nilaway/suppression"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// SetRequireSourceInScope sets how the conflicts whose nil sources are in the packages outside the
+// analysis scope (i.e., the ones for which isPkgPathInScope returns false) are reported:
+// config.SourceScopeReport (the default, i.e., as usual), config.SourceScopeAnnotate (with a
+// "source outside scope" note in the messages), or config.SourceScopeSuppress (not at all).
+func (e *Engine) SetRequireSourceInScope(policy string, isPkgPathInScope func(pkgPath string) bool) {
+	e.requireSourceInScope = policy
+	e.isPkgPathInScope = isPkgPathInScope
+}
+
+// checksSourceScope returns true iff the conflicts whose nil sources are outside the analysis
+// scope are not reported as usual, i.e., the packages of the nil sources should be checked.
+func (e *Engine) checksSourceScope() bool {
+	return e.requireSourceInScope == config.SourceScopeAnnotate || e.requireSourceInScope == config.SourceScopeSuppress
+}
+
+// singleAssertionSourceOutOfScope returns the import path of the package of the site read by the
+// producer of the trigger (e.g., the result of an upstream function), or of the function called
+// by the producer if it reads no site (e.g., a trusted function or a function modeled by a model
+// pack), if it is outside the analysis scope and should be checked (see checksSourceScope),
+// otherwise an empty string.
+func (e *Engine) singleAssertionSourceOutOfScope(trigger annotation.FullTrigger) string {
+	if !e.checksSourceScope() {
+		return ""
+	}
+	var obj types.Object
+	if site := trigger.Producer.Annotation.UnderlyingSite(); site != nil {
+		obj = site.Object()
+	} else if call, ok := ast.Unparen(trigger.Producer.Expr).(*ast.CallExpr); ok {
+		obj = typeutil.Callee(e.pass.TypesInfo, call)
+		// The calls tracked in the assertion trees are rebuilt with fake identifiers of the callees,
+		// which are positioned at their declarations (see
+		// assertiontree.RootAssertionNode.GetDeclaringIdent), hence the packages of the callees
+		// are found from the positions instead.
+		if ident := calleeIdent(call); obj == nil && ident != nil {
+			if pkgPath, ok := e.upstreamPkgOf(e.position(ident.Pos())); ok {
+				return e.outOfScope(pkgPath)
+			}
+		}
+	}
+	if obj == nil || obj.Pkg() == nil {
+		return ""
+	}
+	return e.outOfScope(obj.Pkg().Path())
+}
+
+// calleeIdent returns the identifier of the function or method called, nil if the callee is not
+// named (e.g., a function literal).
+func calleeIdent(call *ast.CallExpr) *ast.Ident {
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		return fun
+	case *ast.SelectorExpr:
+		return fun.Sel
+	}
+	return nil
+}
+
+// overconstraintSourceOutOfScope returns the import path of the upstream package containing the
+// production site of the nil path of an overconstraint conflict, if it is outside the analysis
+// scope and should be checked (see checksSourceScope), otherwise an empty string. The sources in
+// the files whose packages are unknown (see upstreamPkgOf) are considered in scope.
+func (e *Engine) overconstraintSourceOutOfScope(production token.Position) string {
+	if !e.checksSourceScope() || !production.IsValid() {
+		return ""
+	}
+	pkgPath, ok := e.upstreamPkgOf(production)
+	if !ok {
+		return ""
+	}
+	return e.outOfScope(pkgPath)
+}
+
+// outOfScope returns the import path if the package is outside the analysis scope, otherwise an
+// empty string. The current package is always in scope since it is being analyzed.
+func (e *Engine) outOfScope(pkgPath string) string {
+	if pkgPath == e.pass.Pkg.Path() || e.isPkgPathInScope == nil || e.isPkgPathInScope(pkgPath) {
+		return ""
+	}
+	return pkgPath
+}
+
+// applySourceScope drops the conflicts whose nil sources are outside the analysis scope if they
+// should be suppressed (the ones to annotate are marked when they are added).
+func (e *Engine) applySourceScope() {
+	if e.requireSourceInScope != config.SourceScopeSuppress {
+		return
+	}
+	n := len(e.conflicts)
+	e.conflicts = slices.DeleteFunc(e.conflicts, func(c conflict) bool { return c.sourceOutOfScope != "" })
+	e.suppress(suppression.SourceOutOfScope, n-len(e.conflicts))
+}
@@ -0,0 +1,55 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostic
+
+import (
+	"cmp"
+
+	"go.uber.org/nilaway/inference"
+)
+
+// addStrictExportConflict adds the overconstraint conflict to the engine if the nil value comes
+// from a parameter of an exported function assumed nilable in the strict mode (see
+// inference.Engine.ObserveStrictExports), and returns whether it is such a conflict. Only the first
+// unguarded dereference (by position) of each parameter is reported, since guarding or annotating
+// the parameter fixes all of them at once.
+func (e *Engine) addStrictExportConflict(nilReason inference.ExplainedBool, c conflict) bool {
+	root := nilReason
+	for root.DeeperReason() != nil {
+		root = root.DeeperReason()
+	}
+	strict, ok := root.(inference.TrueBecauseStrictExport)
+	if !ok {
+		return false
+	}
+
+	param := strict.SitePos.String() + ": " + strict.Site
+	if e.strictExportConflicts == nil {
+		e.strictExportConflicts = make(map[string]int)
+	}
+	i, ok := e.strictExportConflicts[param]
+	if !ok {
+		e.strictExportConflicts[param] = len(e.conflicts)
+		e.conflicts = append(e.conflicts, c)
+		return true
+	}
+	if cmp.Or(
+		cmp.Compare(c.sink.Filename, e.conflicts[i].sink.Filename),
+		cmp.Compare(c.sink.Offset, e.conflicts[i].sink.Offset),
+	) < 0 {
+		e.conflicts[i] = c
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostic
+
+import (
+	"fmt"
+	"slices"
+
+	"go.uber.org/nilaway/annotation"
+	"go.uber.org/nilaway/config"
+	"golang.org/x/tools/go/analysis"
+)
+
+// _structInitCategory is the category of the diagnostic counting the conflicts of the struct
+// initialization checking in the count mode (see SetCheckStructInit).
+const _structInitCategory = "struct-init"
+
+// SetCheckStructInit sets how the conflicts of the struct initialization checking (i.e., the ones
+// whose nil flows go through the fields tracked by it) are reported: config.CheckStructInitReport
+// (the default, i.e., as usual), or config.CheckStructInitCount (only their number, in a single
+// diagnostic per package).
+func (e *Engine) SetCheckStructInit(mode string) {
+	e.checkStructInit = mode
+}
+
+// applyStructInitCount drops the conflicts of the struct initialization checking in the count
+// mode, and returns the diagnostic reporting their number instead (nil if there are none).
+func (e *Engine) applyStructInitCount() *analysis.Diagnostic {
+	if e.checkStructInit != config.CheckStructInitCount || len(e.pass.Files) == 0 {
+		return nil
+	}
+	n := len(e.conflicts)
+	e.conflicts = slices.DeleteFunc(e.conflicts, func(c conflict) bool { return c.flow.isStructInit() })
+	if n == len(e.conflicts) {
+		return nil
+	}
+	return &analysis.Diagnostic{
+		Pos:      e.pass.Files[0].Package,
+		Category: _structInitCategory,
+		Message: fmt.Sprintf("%d potential nil panic(s) from uninitialized struct fields in package %q (only counted by -%s=%s, use -%s=%s to report them)",
+			n-len(e.conflicts), e.pass.Pkg.Path(), config.CheckStructInitFlag, config.CheckStructInitCount, config.CheckStructInitFlag, config.CheckStructInitReport),
+	}
+}
+
+// isStructInitPrestring returns true iff the producer (or consumer) is one of the struct
+// initialization checking, i.e., it tracks the fields of the structs across the constructors,
+// the functions and the escapes, or a field left uninitialized in its mode.
+func isStructInitPrestring(p annotation.Prestring) bool {
+	if l, ok := p.(annotation.LocatedPrestring); ok {
+		p = l.Contained
+	}
+	switch p := p.(type) {
+	case annotation.UnassignedFldPrestring:
+		return p.StructInit
+	case annotation.FldReturnPrestring, annotation.ParamFldReadPrestring, annotation.ArgFldPassPrestring,
+		annotation.UseAsFldOfReturnPrestring, annotation.FldEscapePrestring:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,177 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostic
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+const (
+	// MessageTemplateVerbose is the name of the built-in (and default) message template, which
+	// renders the complete nil flow on multiple lines.
+	MessageTemplateVerbose = "verbose"
+	// MessageTemplateShort is the name of the built-in message template that renders a single
+	// line with the dereference and the nil source only.
+	MessageTemplateShort = "short"
+)
+
+// _builtinMessageTemplates maps the names of the built-in message templates to their texts.
+var _builtinMessageTemplates = map[string]string{
+	MessageTemplateVerbose: "Potential nil panic detected. Observed nil flow from source to dereference point: " +
+		"{{range .Flow}}\n\t- {{.}}{{end}}" +
+		"{{with .SimilarPositions}}\n\n(Same nil source could also cause potential nil panic(s) at {{len .}} other place(s): {{quotedList .}}.){{end}}" +
+		"{{if .Recovered}}\n\n(Downgraded since the dereference is in a region recovering from panics.){{end}}" +
+		"{{with .SourceOutOfScope}}\n\n(Source outside scope: the nil source is in package \"{{.}}\", which is not analyzed.){{end}}" +
+		"{{if .Instantiations}}\n\n(Reported once for the {{.Instantiations}} instantiations of the generic code affected.){{end}}" +
+		"{{with .Provenance}}\n\nProvenance of the inferred nilability of {{.Site}}:{{range .Causes}}\n\t- {{.}}{{end}}{{end}}" +
+		"{{with .Deps}}\n\nUpstream packages contributing facts to this error:{{range .}}\n\t- {{.}}{{end}}{{end}}\n",
+	MessageTemplateShort: "Potential nil panic: {{.Dereference.Reason}}" +
+		"{{if gt (len .Flow) 1}} (nil source: {{.Source.Reason}} at \"{{.Source.Position}}\"){{end}}" +
+		"{{if .Recovered}} [recovered]{{end}}" +
+		"{{if .SourceOutOfScope}} [source outside scope]{{end}}" +
+		"{{if .Instantiations}} [{{.Instantiations}} instantiations]{{end}}",
+}
+
+// _messageTemplateFuncs are the functions available in the message templates in addition to the
+// predefined ones of text/template.
+var _messageTemplateFuncs = template.FuncMap{
+	"join":       strings.Join,
+	"quotedList": quotedList,
+}
+
+// _defaultMessageTemplate is the message template used if none is set via
+// Engine.SetMessageTemplate.
+var _defaultMessageTemplate = template.Must(ParseMessageTemplate(MessageTemplateVerbose))
+
+// MessageData is the data passed to the message templates for rendering a diagnostic.
+type MessageData struct {
+	// Position is the position where the diagnostic is reported.
+	Position string
+	// Flow is the complete nil flow, from the nilable source to the dereference point.
+	Flow []FlowStep
+	// Source is the first step of the flow, i.e., where the nilable value is produced.
+	Source FlowStep
+	// Dereference is the last step of the flow, i.e., where the nilable value is dereferenced.
+	Dereference FlowStep
+	// SimilarPositions are the positions of the other dereferences of the same nil source, which
+	// are only populated if the errors are grouped.
+	SimilarPositions []string
+	// Provenance explains the root causes of the inferred nilability of the conflicting site, nil
+	// if unavailable or not requested.
+	Provenance *Provenance
+	// Recovered indicates whether the dereference is in a region recovering from panics, which
+	// is only set if such errors are downgraded (see Engine.SetRecoveredPanics).
+	Recovered bool
+	// SourceOutOfScope is the import path of the package outside the analysis scope that the nil
+	// source is in, which is only set if such errors are annotated (see
+	// Engine.SetRequireSourceInScope).
+	SourceOutOfScope string
+	// Instantiations is the number of the instantiations of the generic code whose errors at the
+	// same position are reported once by this diagnostic, 0 if the error is not collapsed.
+	Instantiations int
+	// Deps are the upstream objects (in the form of "<package path>: <object>") that contributed
+	// facts to the error, which are only populated in the debug-deps mode.
+	Deps []string
+}
+
+// FlowStep is a step in the nil flow of a diagnostic.
+type FlowStep struct {
+	// Position is the position of the step, or "<no pos info>" if unknown.
+	Position string
+	// Producer describes the value flowing at this step, e.g., "literal `nil`".
+	Producer string
+	// Consumer describes where the value flows to, e.g., "dereferenced".
+	Consumer string
+}
+
+// Reason returns the description of the step, i.e., the producer followed by the consumer.
+func (s FlowStep) Reason() string {
+	if s.Producer == "" || s.Consumer == "" {
+		return s.Producer + s.Consumer
+	}
+	return s.Producer + " " + s.Consumer
+}
+
+// String returns the string representation of the step in the form of "<position>: <reason>".
+func (s FlowStep) String() string {
+	return s.Position + ": " + s.Reason()
+}
+
+// Provenance explains the root causes that forced the conflicting site of a diagnostic to be
+// nilable and nonnil (see Engine.EnableProvenance).
+type Provenance struct {
+	// Site is the description of the conflicting site.
+	Site string
+	// Causes describe the root causes of the nilabilities.
+	Causes []string
+}
+
+// ParseMessageTemplate returns the message template for rendering the diagnostics (see
+// MessageData), given either the name of a built-in template (MessageTemplateVerbose or
+// MessageTemplateShort) or the text of a custom text/template. An empty string means the default
+// (verbose) template. The template is checked by rendering a sample diagnostic, such that invalid
+// references to the data are reported early.
+func ParseMessageTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		text = MessageTemplateVerbose
+	}
+	if builtin, ok := _builtinMessageTemplates[text]; ok {
+		text = builtin
+	}
+	tmpl, err := template.New("message").Funcs(_messageTemplateFuncs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	step := FlowStep{Position: "foo.go:1:2", Producer: "literal `nil`", Consumer: "dereferenced"}
+	sample := MessageData{
+		Position:         step.Position,
+		Flow:             []FlowStep{step},
+		Source:           step,
+		Dereference:      step,
+		SimilarPositions: []string{step.Position},
+		Provenance:       &Provenance{Site: "Field f", Causes: []string{"NILABLE because it is annotated as so"}},
+		SourceOutOfScope: "example.com/bar",
+		Instantiations:   2,
+		Deps:             []string{"example.com/foo: Field f"},
+	}
+	if err := tmpl.Execute(new(strings.Builder), sample); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// SetMessageTemplate sets the template for rendering the messages of the diagnostics (see
+// ParseMessageTemplate), replacing the default verbose layout.
+func (e *Engine) SetMessageTemplate(tmpl *template.Template) {
+	e.messageTemplate = tmpl
+}
+
+// quotedList formats the strings as a quoted list, e.g., `"a", "b", and "c"`.
+func quotedList(elems []string) string {
+	if len(elems) == 0 {
+		return ""
+	}
+	quoted := make([]string, len(elems))
+	for i, elem := range elems {
+		quoted[i] = fmt.Sprintf("\"%s\"", elem)
+	}
+	s := strings.Join(quoted[:len(quoted)-1], ", ")
+	if len(quoted) > 1 {
+		s += ", and "
+	}
+	return s + quoted[len(quoted)-1]
+}
@@ -0,0 +1,73 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostic
+
+import (
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMessageTemplate(t *testing.T) {
+	t.Parallel()
+
+	c := &conflict{
+		position: token.Position{Filename: "foo.go", Line: 3, Column: 4},
+		flow: nilFlow{
+			nilPath:    []node{{consumerPosition: token.Position{Filename: "foo.go", Line: 1, Column: 2}, producerRepr: "literal `nil`", consumerRepr: "returned from `f()`"}},
+			nonnilPath: []node{{consumerPosition: token.Position{Filename: "foo.go", Line: 3, Column: 4}, producerRepr: "result 0 of `f()`", consumerRepr: "dereferenced"}},
+		},
+	}
+
+	render := func(text string) string {
+		tmpl, err := ParseMessageTemplate(text)
+		require.NoError(t, err)
+		return c.message(tmpl, nil /* default path format */)
+	}
+
+	verbose := "Potential nil panic detected. Observed nil flow from source to dereference point: " +
+		"\n\t- foo.go:1:2: literal `nil` returned from `f()`\n\t- foo.go:3:4: result 0 of `f()` dereferenced\n"
+	require.Equal(t, verbose, render(""))
+	require.Equal(t, verbose, render(MessageTemplateVerbose))
+	require.Equal(t, "Potential nil panic: result 0 of `f()` dereferenced (nil source: literal `nil` returned from `f()` at \"foo.go:1:2\")", render(MessageTemplateShort))
+	require.Equal(t, "foo.go:3:4 [nilaway] 2 steps", render("{{.Position}} [nilaway] {{len .Flow}} steps"))
+
+	// The nil sources outside the analysis scope are noted by the built-in templates.
+	c.sourceOutOfScope = "example.com/bar"
+	require.Equal(t, strings.TrimSuffix(verbose, "\n")+"\n\n(Source outside scope: the nil source is in package \"example.com/bar\", which is not analyzed.)\n", render(""))
+	require.True(t, strings.HasSuffix(render(MessageTemplateShort), " [source outside scope]"))
+	c.sourceOutOfScope = ""
+
+	// Invalid templates and references to unknown fields are reported early.
+	_, err := ParseMessageTemplate("{{.Position")
+	require.Error(t, err)
+	_, err = ParseMessageTemplate("{{.Unknown}}")
+	require.ErrorContains(t, err, "Unknown")
+
+	// Failures at rendering fall back to the default template.
+	msg := render("{{index .Deps 0}}")
+	require.True(t, strings.HasPrefix(msg, verbose+"(failed to render the message template: "), msg)
+}
+
+func TestQuotedList(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "", quotedList(nil))
+	require.Equal(t, "\"a\"", quotedList([]string{"a"}))
+	require.Equal(t, "\"a\", and \"b\"", quotedList([]string{"a", "b"}))
+	require.Equal(t, "\"a\", \"b\", and \"c\"", quotedList([]string{"a", "b", "c"}))
+}
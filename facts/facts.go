@@ -0,0 +1,58 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package facts exposes the encoding of the facts exported by NilAway, such that custom drivers
+// that receive the gob-encoded facts (e.g., bazel workers or distributed analysis) can decode and
+// render them without depending on the internals of NilAway.
+//
+// The facts are gob-encoded with the implementations of the interface types registered under
+// short names to keep them small, so they can only be decoded by a binary with the same encoding.
+// The encoded inferred maps start with a header carrying the Fingerprint of their encoding: the
+// maps produced by an incompatible version are ignored by the analyzer (with a single notice per
+// package) and rejected by DecodeInferredMap. Drivers that cache or transfer the facts across
+// binaries should key them by Fingerprint.
+package facts
+
+import (
+	"go.uber.org/nilaway/inference"
+)
+
+// Site is the human-readable form of an annotation site (e.g., a parameter of a function) and its
+// inferred nilability in the facts.
+type Site = inference.DecodedSite
+
+// Implication is the human-readable form of an implication between the nilabilities of two sites
+// (i.e., the implicated site must be nilable if the implicating site is nilable) in the facts.
+type Implication = inference.DecodedImplication
+
+// Register registers the types in the facts to gob encoding. It must be called before decoding
+// the facts with the encoding/gob package directly (e.g., as part of the facts of all analyzers);
+// it is safe to be called multiple times.
+func Register() {
+	inference.GobRegister()
+}
+
+// Fingerprint returns the fingerprint of the encoding of the facts. Facts can only be decoded by
+// a binary with the same fingerprint as the one that encoded them.
+func Fingerprint() string {
+	return inference.GobFingerprint()
+}
+
+// DecodeInferredMap decodes the fact of the inferred nilabilities of a package (i.e., the
+// gob-encoded `*inference.InferredMap` exported by the NilAway analyzer) and returns its sites in
+// insertion order.
+func DecodeInferredMap(data []byte) ([]Site, error) {
+	Register()
+	return inference.DecodeInferredMap(data)
+}
@@ -0,0 +1,52 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hook
+
+import (
+	"go/ast"
+	"regexp"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// AcceptNilArgs returns true if the arguments of the given call expression are allowed to be nil,
+// regardless of the nilability of the parameters of the called function. This is useful for the
+// 3rd party functions storing their arguments as opaque values. For example, the values passed to
+// `Return` of an expected call of a mock are returned as is by the mocked method, where nil
+// values (e.g., `Return(nil, err)`) are as valid as in the real implementations.
+func AcceptNilArgs(pass *analysis.Pass, call *ast.CallExpr) bool {
+	for sig := range _acceptNilArgs {
+		if sig.match(pass, call) {
+			return true
+		}
+	}
+	return false
+}
+
+var _acceptNilArgs = map[trustedFuncSig]struct{}{
+	// `go.uber.org/mock/gomock` (and its predecessor `github.com/golang/mock/gomock`)
+	{
+		kind:           _method,
+		enclosingRegex: regexp.MustCompile(`(go\.uber\.org|github\.com/golang)/mock/gomock\.Call$`),
+		funcNameRegex:  regexp.MustCompile(`^(Return|SetArg)$`),
+	}: {},
+
+	// `github.com/stretchr/testify/mock`
+	{
+		kind:           _method,
+		enclosingRegex: regexp.MustCompile(`github\.com/stretchr/testify/mock\.(Mock|Call)$`),
+		funcNameRegex:  regexp.MustCompile(`^(On|Return)$`),
+	}: {},
+}
@@ -19,13 +19,18 @@ import (
 	"regexp"
 
 	"go.uber.org/nilaway/annotation"
+	"go.uber.org/nilaway/config"
 	"golang.org/x/tools/go/analysis"
 )
 
 // AssumeReturn returns the producer for the return value of the given call expression, which would
 // have the assumed nilability. This is useful for modeling the return value of stdlib and 3rd party
 // functions that are not analyzed by NilAway. For example, "errors.New" is assumed to return a
-// nonnil value. If the given call expression does not match any known function, nil is returned.
+// nonnil value. For the functions with multiple results, the producer applies to every result. If
+// the given call expression does not match any known function, nil is returned. The functions
+// modeled by the model packs (see config.ModelPackRule) are assumed to return values with the
+// modeled nilability as well. Lastly, the producers contributed by the registered trigger plugins
+// (see TriggerPlugin) are returned.
 func AssumeReturn(pass *analysis.Pass, call *ast.CallExpr) *annotation.ProduceTrigger {
 	for sig, act := range _assumeReturns {
 		if sig.match(pass, call) {
@@ -33,7 +38,13 @@ func AssumeReturn(pass *analysis.Pass, call *ast.CallExpr) *annotation.ProduceTr
 		}
 	}
 
-	return nil
+	switch modelPackModel(pass, call) {
+	case config.ModelNonnil:
+		return nonnilProducer(call)
+	case config.ModelNilable:
+		return nilableProducer(call)
+	}
+	return pluginProducer(pass, call)
 }
 
 type assumeReturnAction func(call *ast.CallExpr) *annotation.ProduceTrigger
@@ -64,6 +75,106 @@ var _assumeReturns = map[trustedFuncSig]assumeReturnAction{
 		enclosingRegex: regexp.MustCompile(`github\.com/pkg/errors$`),
 		funcNameRegex:  regexp.MustCompile(`^New$`),
 	}: nonnilProducer,
+
+	// `errors.Unwrap`: the result is nil if the error does not wrap another error.
+	{
+		kind:           _func,
+		enclosingRegex: regexp.MustCompile(`^errors$`),
+		funcNameRegex:  regexp.MustCompile(`^Unwrap$`),
+	}: nilableProducer,
+
+	// `github.com/samber/lo`: the generic "must" helpers (e.g., `lo.Must(os.Open(name))`) panic
+	// if the error (or the false ok) passed along with the values is not nil, hence the values are
+	// returned only if the error contract of the call passed to the helpers is satisfied.
+	{
+		kind:           _func,
+		enclosingRegex: regexp.MustCompile(`github\.com/samber/lo$`),
+		funcNameRegex:  regexp.MustCompile(`^Must[1-6]?$`),
+	}: nonnilProducer,
+
+	// Pointer helpers: the helpers of popular utility libraries converting values into pointers
+	// (e.g., `lo.ToPtr(v)` or `aws.String(s)`) always return the addresses of (copies of) the
+	// values. The helpers of other libraries can be modeled the same way by the model packs (see
+	// config.ModelPackRule).
+	{
+		kind:           _func,
+		enclosingRegex: regexp.MustCompile(`github\.com/samber/lo$`),
+		funcNameRegex:  regexp.MustCompile(`^(ToPtr|ToSlicePtr)$`),
+	}: nonnilProducer,
+	{
+		kind:           _func,
+		enclosingRegex: regexp.MustCompile(`github\.com/aws/(aws-sdk-go(-v2)?/aws|smithy-go/ptr)$`),
+		funcNameRegex: regexp.MustCompile(`^(String|Bool|Byte|Int|Int8|Int16|Int32|Int64|Uint|Uint8|Uint16|Uint32|Uint64|` +
+			`Float32|Float64|Time|Duration)(Slice|Map)?$`),
+	}: nonnilProducer,
+
+	// `sync/atomic.Pointer`: the value loaded (or swapped out) is nil until a nonnil value is
+	// stored. The stores are modeled separately (see AssumeStore), such that the loads after a
+	// nonnil store are not reported.
+	{
+		kind:           _method,
+		enclosingRegex: regexp.MustCompile(`^sync/atomic\.Pointer$`),
+		funcNameRegex:  regexp.MustCompile(`^(Load|Swap)$`),
+	}: nilableProducer,
+
+	// `go.uber.org/mock/gomock` (and its predecessor `github.com/golang/mock/gomock`): the
+	// controllers and the expected calls are always created, and the calls set up on them are
+	// chained (e.g., `m.EXPECT().Get(key).Return(nil, err).Times(1)`). The results of the calls to
+	// the mocks are the values set up by `Return` (the controller fails the test if no expected
+	// call matches), which are checked by the code generated for the mocked methods.
+	{
+		kind:           _func,
+		enclosingRegex: regexp.MustCompile(`(go\.uber\.org|github\.com/golang)/mock/gomock$`),
+		funcNameRegex:  regexp.MustCompile(`^(NewController|WithContext)$`),
+	}: nonnilProducer,
+	{
+		kind:           _method,
+		enclosingRegex: regexp.MustCompile(`(go\.uber\.org|github\.com/golang)/mock/gomock\.Controller$`),
+		funcNameRegex:  regexp.MustCompile(`^(Call|RecordCall|RecordCallWithMethodType)$`),
+	}: nonnilProducer,
+	{
+		kind:           _method,
+		enclosingRegex: regexp.MustCompile(`(go\.uber\.org|github\.com/golang)/mock/gomock\.Call$`),
+		funcNameRegex:  regexp.MustCompile(`^(Return|Do|DoAndReturn|Times|AnyTimes|MinTimes|MaxTimes|After|SetArg)$`),
+	}: nonnilProducer,
+
+	// `database/sql`: `QueryRow` always returns a row, whose errors (including `sql.ErrNoRows`)
+	// are deferred until `Scan`. The same holds for `QueryRowx` of `github.com/jmoiron/sqlx`.
+	{
+		kind:           _method,
+		enclosingRegex: regexp.MustCompile(`^database/sql\.(DB|Tx|Conn|Stmt)$`),
+		funcNameRegex:  regexp.MustCompile(`^QueryRow(Context)?$`),
+	}: nonnilProducer,
+	{
+		kind:           _method,
+		enclosingRegex: regexp.MustCompile(`github\.com/jmoiron/sqlx\.(DB|Tx|Conn|Stmt|NamedStmt)$`),
+		funcNameRegex:  regexp.MustCompile(`^QueryRowx?(Context)?$`),
+	}: nonnilProducer,
+
+	// `gorm.io/gorm`: the chainable and finisher methods always return a (new or the same) `*DB`,
+	// whose `Error` field holds the errors, e.g., `db.Where("id = ?", id).First(&u).Error`.
+	{
+		kind:           _method,
+		enclosingRegex: regexp.MustCompile(`gorm\.io/gorm\.DB$`),
+		funcNameRegex: regexp.MustCompile(`^(Model|Table|Select|Omit|Where|Not|Or|Joins|InnerJoins|Group|Having|Order|Limit|Offset|` +
+			`Scopes|Preload|Distinct|Unscoped|Raw|Clauses|Attrs|Assign|Session|WithContext|Debug|` +
+			`First|Take|Last|Find|FindInBatches|FirstOrInit|FirstOrCreate|Scan|Pluck|Count|` +
+			`Create|CreateInBatches|Save|Update|Updates|UpdateColumn|UpdateColumns|Delete|Exec|Begin|Commit|Rollback)$`),
+	}: nonnilProducer,
+
+	// `github.com/stretchr/testify/mock` (used by the mocks generated by mockery): the expected
+	// calls set up on the mocks are always created and chained (e.g.,
+	// `m.On("Get", key).Return(nil, err).Once()`).
+	{
+		kind:           _method,
+		enclosingRegex: regexp.MustCompile(`github\.com/stretchr/testify/mock\.Mock$`),
+		funcNameRegex:  regexp.MustCompile(`^On$`),
+	}: nonnilProducer,
+	{
+		kind:           _method,
+		enclosingRegex: regexp.MustCompile(`github\.com/stretchr/testify/mock\.Call$`),
+		funcNameRegex:  regexp.MustCompile(`^(On|Return|Run|Panic|Once|Twice|Times|Maybe|After|WaitUntil|NotBefore)$`),
+	}: nonnilProducer,
 }
 
 var nonnilProducer assumeReturnAction = func(call *ast.CallExpr) *annotation.ProduceTrigger {
@@ -72,3 +183,46 @@ var nonnilProducer assumeReturnAction = func(call *ast.CallExpr) *annotation.Pro
 		Expr:       call,
 	}
 }
+
+var nilableProducer assumeReturnAction = func(call *ast.CallExpr) *annotation.ProduceTrigger {
+	return &annotation.ProduceTrigger{
+		Annotation: &annotation.TrustedFuncNilable{ProduceTriggerTautology: &annotation.ProduceTriggerTautology{}},
+		Expr:       call,
+	}
+}
+
+// AssumeOkReturn returns the producer for a non-ok result (i.e., any result but the last boolean
+// one) of the given call expression to an ok-returning function, which would be nonnil only if the
+// ok result is checked. This is useful for modeling the lookups in stdlib and 3rd party containers
+// that are not analyzed by NilAway. For example, the value returned by "sync.Map.Load" is nil if
+// the key is not present, which is indicated by the ok result. The functions modeled with the ok
+// results by the model packs (see config.ModelPackRule) are handled as well. If the given call
+// expression does not match any known function, nil is returned.
+func AssumeOkReturn(pass *analysis.Pass, call *ast.CallExpr) *annotation.ProduceTrigger {
+	for sig, act := range _assumeOkReturns {
+		if sig.match(pass, call) {
+			return act(call)
+		}
+	}
+	if modelPackModel(pass, call) == config.ModelOk {
+		return guardedNonnilProducer(call)
+	}
+
+	return nil
+}
+
+var _assumeOkReturns = map[trustedFuncSig]assumeReturnAction{
+	// `sync.Map`
+	{
+		kind:           _method,
+		enclosingRegex: regexp.MustCompile(`^sync\.Map$`),
+		funcNameRegex:  regexp.MustCompile(`^(Load|LoadAndDelete|Swap)$`),
+	}: guardedNonnilProducer,
+}
+
+var guardedNonnilProducer assumeReturnAction = func(call *ast.CallExpr) *annotation.ProduceTrigger {
+	return &annotation.ProduceTrigger{
+		Annotation: &annotation.TrustedFuncOkResult{ProduceTriggerNever: &annotation.ProduceTriggerNever{NeedsGuard: true}},
+		Expr:       call,
+	}
+}
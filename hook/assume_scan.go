@@ -0,0 +1,130 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hook
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+	"sync"
+
+	"go.uber.org/nilaway/util"
+	"golang.org/x/tools/go/analysis"
+)
+
+// AssumeScan returns the destinations that the given call expression writes to via their addresses
+// passed as the arguments (e.g., `u` in `db.First(&u)`), and the values written to them, such that
+// the call can be handled as the assignment of the values to the destinations. This is useful for
+// modeling the database APIs scanning the query results into the destinations, which are otherwise
+// considered unassigned. Only the destinations of the types that can be nil are returned. If the
+// given call expression does not match any known function, nil values are returned.
+func AssumeScan(pass *analysis.Pass, call *ast.CallExpr) (dests []ast.Expr, values []ast.Expr) {
+	for sig, act := range _assumeScans {
+		if !sig.match(pass, call) {
+			continue
+		}
+		for _, arg := range call.Args {
+			addr, ok := ast.Unparen(arg).(*ast.UnaryExpr)
+			if !ok || addr.Op != token.AND {
+				continue
+			}
+			if !util.TypeBarsNilness(pass.TypesInfo.TypeOf(addr.X)) {
+				dests = append(dests, addr.X)
+				values = append(values, scannedValue(arg, act.value))
+			}
+			if act.firstOnly {
+				// Only the first address is the destination, the rest are query arguments.
+				break
+			}
+		}
+		return dests, values
+	}
+	return nil, nil
+}
+
+// _scannedValues caches the values created for the arguments, such that the same expressions are
+// returned when the same call is visited again (e.g., in the fixed-point iterations over loops).
+var _scannedValues sync.Map
+
+// scannedValue returns the (cached) value written to the destination whose address is the given
+// argument.
+func scannedValue(arg ast.Expr, value func(arg ast.Expr) ast.Expr) ast.Expr {
+	if v, ok := _scannedValues.Load(arg); ok {
+		return v.(ast.Expr)
+	}
+	v, _ := _scannedValues.LoadOrStore(arg, value(arg))
+	return v.(ast.Expr)
+}
+
+// scanAction describes how a scanning function writes to the destinations.
+type scanAction struct {
+	// value returns the value written to the destination whose address is the given argument.
+	value func(arg ast.Expr) ast.Expr
+	// firstOnly indicates that only the first address among the arguments is a destination.
+	firstOnly bool
+}
+
+// nonnilValue returns an expression that is never nil, standing for the values written to the
+// destinations (e.g., the records found by GORM).
+func nonnilValue(arg ast.Expr) ast.Expr {
+	return &ast.BasicLit{ValuePos: arg.Pos(), Kind: token.STRING, Value: `""`}
+}
+
+// nilValue returns the nil literal, standing for the values written to the destinations that can
+// be nil (e.g., the NULL columns scanned into pointers).
+func nilValue(arg ast.Expr) ast.Expr {
+	return &ast.Ident{NamePos: arg.Pos(), Name: "nil"}
+}
+
+var _assumeScans = map[trustedFuncSig]scanAction{
+	// `database/sql`: the NULL columns are scanned as nil into the pointers (e.g., `*string`),
+	// slices and interfaces (the `sqlx.Rows` embed `sql.Rows`, hence their `Scan` is matched here).
+	{
+		kind:           _method,
+		enclosingRegex: regexp.MustCompile(`^database/sql\.(Row|Rows)$`),
+		funcNameRegex:  regexp.MustCompile(`^Scan$`),
+	}: {value: nilValue},
+	{
+		kind:           _method,
+		enclosingRegex: regexp.MustCompile(`github\.com/jmoiron/sqlx\.Row$`),
+		funcNameRegex:  regexp.MustCompile(`^Scan$`),
+	}: {value: nilValue},
+
+	// `gorm.io/gorm`: the records found are stored into the destinations (typically pointers to
+	// structs or slices of them), e.g., `db.Where("name = ?", name).First(&u)`.
+	{
+		kind:           _method,
+		enclosingRegex: regexp.MustCompile(`gorm\.io/gorm\.DB$`),
+		funcNameRegex:  regexp.MustCompile(`^(First|Take|Last|Find|FirstOrInit|FirstOrCreate|Scan)$`),
+	}: {value: nonnilValue, firstOnly: true},
+
+	// `github.com/jmoiron/sqlx`: the rows are stored into the destinations given as the first
+	// addresses (e.g., `db.Get(&u, query, args...)`), or the structs scanned into.
+	{
+		kind:           _method,
+		enclosingRegex: regexp.MustCompile(`github\.com/jmoiron/sqlx\.(DB|Tx|Conn|Stmt|NamedStmt)$`),
+		funcNameRegex:  regexp.MustCompile(`^(Get|Select)(Context)?$`),
+	}: {value: nonnilValue, firstOnly: true},
+	{
+		kind:           _func,
+		enclosingRegex: regexp.MustCompile(`github\.com/jmoiron/sqlx$`),
+		funcNameRegex:  regexp.MustCompile(`^(Get|Select)(Context)?$`),
+	}: {value: nonnilValue, firstOnly: true},
+	{
+		kind:           _method,
+		enclosingRegex: regexp.MustCompile(`github\.com/jmoiron/sqlx\.(Row|Rows)$`),
+		funcNameRegex:  regexp.MustCompile(`^(StructScan|MapScan|SliceScan)$`),
+	}: {value: nonnilValue, firstOnly: true},
+}
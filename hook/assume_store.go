@@ -0,0 +1,80 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hook
+
+import (
+	"go/ast"
+	"go/types"
+	"regexp"
+
+	"go.uber.org/nilaway/util"
+	"golang.org/x/tools/go/analysis"
+)
+
+// AssumeStore returns the loading method and the stored value if the given call expression stores
+// a value that is returned by the later calls to the loading method on the same receiver. For
+// example, after "p.Store(v)" on a "sync/atomic.Pointer", "p.Load()" returns "v" (unless another
+// goroutine stores a different value in between, which we do not model). If the given call
+// expression does not match any known function, nil values are returned.
+func AssumeStore(pass *analysis.Pass, call *ast.CallExpr) (*types.Func, ast.Expr) {
+	for sig, loadName := range _assumeStores {
+		if !sig.match(pass, call) || len(call.Args) != 1 {
+			continue
+		}
+		// The match above guarantees that the call is a method call.
+		sel := pass.TypesInfo.Selections[call.Fun.(*ast.SelectorExpr)]
+		if sel == nil {
+			return nil, nil
+		}
+		obj, _, _ := types.LookupFieldOrMethod(sel.Recv(), true, nil, loadName)
+		if load, ok := obj.(*types.Func); ok {
+			return load, call.Args[0]
+		}
+	}
+	return nil, nil
+}
+
+// IsAssumedLoad returns true if the given function is the loading method of a storing method
+// known to AssumeStore (e.g., "Load" of a "sync/atomic.Pointer"). Repeated calls to such a method
+// on the same receiver return the last stored value, hence they can be tracked like the calls to
+// pure functions.
+func IsAssumedLoad(fn *types.Func) bool {
+	fn = fn.Origin()
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil || fn.Pkg() == nil {
+		return false
+	}
+	n, ok := util.UnwrapPtr(recv.Type()).(*types.Named)
+	if !ok {
+		return false
+	}
+	path := fn.Pkg().Path() + "." + n.Obj().Name()
+	for sig, loadName := range _assumeStores {
+		if loadName == fn.Name() && sig.enclosingRegex.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// _assumeStores maps the storing methods to the names of their loading methods.
+var _assumeStores = map[trustedFuncSig]string{
+	// `sync/atomic.Pointer`
+	{
+		kind:           _method,
+		enclosingRegex: regexp.MustCompile(`^sync/atomic\.Pointer$`),
+		funcNameRegex:  regexp.MustCompile(`^(Store|Swap)$`),
+	}: "Load",
+}
@@ -0,0 +1,147 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hook
+
+import (
+	"go/ast"
+	"go/types"
+	"regexp"
+
+	"go.uber.org/nilaway/util"
+	"golang.org/x/tools/go/analysis"
+)
+
+// CallbackAssumption is the assumed behavior of a trusted higher-order function on the callback
+// passed as one of its arguments.
+type CallbackAssumption struct {
+	// ArgNum is the index of the callback among the arguments of the call.
+	ArgNum int
+	// NonnilParams indicates that the function only passes nonnil values to the parameters of the
+	// callback, regardless of the nilability inferred from its implementation (if analyzed).
+	NonnilParams bool
+	// Invoked indicates that the function invokes the callback, such that the variables captured
+	// by a function literal passed as the callback are evaluated at the call.
+	Invoked bool
+	// Sync indicates that the callback is only invoked before the function returns (as opposed
+	// to, e.g., in a spawned goroutine), such that a function literal passed as the callback can be
+	// analyzed as if it were inlined at the call.
+	Sync bool
+	// Deferred indicates that the callback is invoked when the calling function returns (e.g.,
+	// the cleanup functions registered by `t.Cleanup`), such that the variables captured by a
+	// function literal passed as the callback are evaluated at the returns instead.
+	Deferred bool
+}
+
+// AssumeCallback returns the assumed behavior of the given call to a trusted higher-order function
+// on the callback passed as one of its arguments, and false if the call does not match any known
+// function. This is useful for modeling the stdlib and 3rd party functions invoking the user
+// callbacks, which are typically not analyzed by NilAway. For example, `filepath.WalkDir` is
+// assumed to pass a nonnil `fs.DirEntry` to its callback as long as the error passed is nil (see
+// ErrGuardedParam).
+func AssumeCallback(pass *analysis.Pass, call *ast.CallExpr) (CallbackAssumption, bool) {
+	for sig, assumption := range _assumeCallbacks {
+		if sig.match(pass, call) && assumption.ArgNum < len(call.Args) {
+			return assumption, true
+		}
+	}
+	return CallbackAssumption{}, false
+}
+
+// ErrGuardedParam returns the index of the parameter of a function with the given signature that
+// is only nonnil if its error parameter is nil, and the index of the error parameter, or false if
+// the signature has no such parameters. These are the parameters of the callbacks of the walk
+// functions (i.e., `fs.WalkDirFunc` and `filepath.WalkFunc`): the `fs.DirEntry` (`fs.FileInfo`)
+// passed to them is nil if the walk fails on the root, along with a nonnil error. The callbacks
+// must therefore check the error before using the entry, and the entry is only assumed nonnil
+// (see AssumeCallback) under such a check.
+func ErrGuardedParam(sig *types.Signature) (int, int, bool) {
+	params := sig.Params()
+	if params.Len() != 3 || sig.Results().Len() != 1 ||
+		!types.Identical(sig.Results().At(0).Type(), util.ErrorType) ||
+		!types.Identical(params.At(2).Type(), util.ErrorType) {
+		return 0, 0, false
+	}
+	if basic, ok := params.At(0).Type().(*types.Basic); !ok || basic.Kind() != types.String {
+		return 0, 0, false
+	}
+	named, ok := types.Unalias(params.At(1).Type()).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != "io/fs" {
+		return 0, 0, false
+	}
+	if name := named.Obj().Name(); name != "DirEntry" && name != "FileInfo" {
+		return 0, 0, false
+	}
+	return 1, 2, true
+}
+
+var _assumeCallbacks = map[trustedFuncSig]CallbackAssumption{
+	// `filepath.Walk` and `filepath.WalkDir`: the `fs.FileInfo` (`fs.DirEntry`) passed to the
+	// callback is only nil if the error passed is nonnil, which the callbacks must check first
+	// (see ErrGuardedParam).
+	{
+		kind:           _func,
+		enclosingRegex: regexp.MustCompile(`^path/filepath$`),
+		funcNameRegex:  regexp.MustCompile(`^Walk(Dir)?$`),
+	}: {ArgNum: 1, NonnilParams: true, Invoked: true, Sync: true},
+
+	// `fs.WalkDir`: similar to `filepath.WalkDir`.
+	{
+		kind:           _func,
+		enclosingRegex: regexp.MustCompile(`^io/fs$`),
+		funcNameRegex:  regexp.MustCompile(`^WalkDir$`),
+	}: {ArgNum: 2, NonnilParams: true, Invoked: true, Sync: true},
+
+	// `http.HandleFunc`: the handlers are passed a nonnil `http.ResponseWriter` and `*http.Request`.
+	{
+		kind:           _func,
+		enclosingRegex: regexp.MustCompile(`^net/http$`),
+		funcNameRegex:  regexp.MustCompile(`^HandleFunc$`),
+	}: {ArgNum: 1, NonnilParams: true},
+	{
+		kind:           _method,
+		enclosingRegex: regexp.MustCompile(`^net/http\.ServeMux$`),
+		funcNameRegex:  regexp.MustCompile(`^HandleFunc$`),
+	}: {ArgNum: 1, NonnilParams: true},
+
+	// `sort.Slice` and `sort.SliceStable`: the less function is invoked during the sort.
+	{
+		kind:           _func,
+		enclosingRegex: regexp.MustCompile(`^sort$`),
+		funcNameRegex:  regexp.MustCompile(`^Slice(Stable)?$`),
+	}: {ArgNum: 1, Invoked: true, Sync: true},
+
+	// `sync.Once.Do`: the function is invoked at most once, before `Do` returns.
+	{
+		kind:           _method,
+		enclosingRegex: regexp.MustCompile(`^sync\.Once$`),
+		funcNameRegex:  regexp.MustCompile(`^Do$`),
+	}: {ArgNum: 0, Invoked: true, Sync: true},
+
+	// `testing.TB.Cleanup` (declared on the unexported `testing.common` embedded in `testing.T`,
+	// `testing.B` and `testing.F`): the function is invoked once the test completes, i.e., after
+	// the test function returns.
+	{
+		kind:           _method,
+		enclosingRegex: regexp.MustCompile(`^testing\.(common|TB)$`),
+		funcNameRegex:  regexp.MustCompile(`^Cleanup$`),
+	}: {ArgNum: 0, Deferred: true},
+
+	// `errgroup.Group.Go` and `errgroup.Group.TryGo`: the function is invoked in a new goroutine.
+	{
+		kind:           _method,
+		enclosingRegex: regexp.MustCompile(`^golang\.org/x/sync/errgroup\.Group$`),
+		funcNameRegex:  regexp.MustCompile(`^(Try)?Go$`),
+	}: {ArgNum: 0, Invoked: true},
+}
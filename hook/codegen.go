@@ -0,0 +1,136 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hook
+
+import (
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"go.uber.org/nilaway/util/sourcemap"
+)
+
+// Codegen describes a code generator for templates (e.g., templ) whose generated code NilAway has
+// first-class support for. Other code generators can be supported via RegisterCodegen.
+type Codegen struct {
+	// Name is the name of the code generator, e.g., "templ".
+	Name string
+	// GeneratedFile matches the names of the generated files, e.g., `_templ\.go$`.
+	GeneratedFile *regexp.Regexp
+	// SourcePosition optionally maps the positions in the generated files back to the authored
+	// template sources for reporting, for the code generators that do not emit "//line"
+	// directives.
+	SourcePosition sourcemap.Mapper
+}
+
+var (
+	_codegensMu sync.RWMutex
+	_codegens   []Codegen
+)
+
+// RegisterCodegen registers the code generator. It must be called before the analysis starts
+// (e.g., in an init function of a custom driver).
+func RegisterCodegen(c Codegen) {
+	_codegensMu.Lock()
+	defer _codegensMu.Unlock()
+	_codegens = append(_codegens, c)
+	if c.SourcePosition != nil {
+		sourcemap.Register(c.GeneratedFile, c.SourcePosition)
+	}
+}
+
+func init() {
+	// templ (https://templ.guide) generates a function returning a `templ.Component` for each
+	// template in the "<name>_templ.go" file next to the "<name>.templ" file. The components are
+	// constructed as conversions of function literals (e.g., `templ.ComponentFunc(...)`), which are
+	// already trusted to be nonnil, hence only the positions need to be mapped.
+	RegisterCodegen(Codegen{
+		Name:           "templ",
+		GeneratedFile:  regexp.MustCompile(`_templ\.go$`),
+		SourcePosition: templSourcePosition,
+	})
+}
+
+// templSourcePosition maps the position in a file generated by templ back to the ".templ" file.
+// templ does not emit "//line" directives, but it wraps the errors of the Go expressions embedded
+// in the templates in `templ.Error` literals that record the positions of the expressions in the
+// templates (with zero-based lines and columns) right after evaluating them. Hence, we map the
+// position to the one recorded by the first such literal after it in the same function.
+func templSourcePosition(fset *token.FileSet, file *ast.File, pos token.Pos) (token.Position, bool) {
+	var funcDecl *ast.FuncDecl
+	for _, decl := range file.Decls {
+		if f, ok := decl.(*ast.FuncDecl); ok && f.Pos() <= pos && pos < f.End() {
+			funcDecl = f
+			break
+		}
+	}
+	if funcDecl == nil {
+		return token.Position{}, false
+	}
+
+	var (
+		position token.Position
+		found    bool
+	)
+	ast.Inspect(funcDecl, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		lit, ok := n.(*ast.CompositeLit)
+		if !ok || lit.Pos() < pos {
+			return true
+		}
+		sel, ok := lit.Type.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Error" {
+			return true
+		}
+		if x, ok := sel.X.(*ast.Ident); !ok || x.Name != "templ" {
+			return true
+		}
+		fields := make(map[string]string)
+		for _, elt := range lit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			key, ok := kv.Key.(*ast.Ident)
+			val, isLit := kv.Value.(*ast.BasicLit)
+			if ok && isLit {
+				fields[key.Name] = val.Value
+			}
+		}
+		name, err := strconv.Unquote(fields["FileName"])
+		if err != nil {
+			return true
+		}
+		line, err := strconv.Atoi(fields["Line"])
+		if err != nil {
+			return true
+		}
+		col, err := strconv.Atoi(fields["Col"])
+		if err != nil {
+			return true
+		}
+		// The templates are next to the generated files.
+		dir := filepath.Dir(fset.Position(file.Pos()).Filename)
+		position = token.Position{Filename: filepath.Join(dir, filepath.Base(name)), Line: line + 1, Column: col + 1}
+		found = true
+		return false
+	})
+	return position, found
+}
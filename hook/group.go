@@ -0,0 +1,84 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hook
+
+import (
+	"go/ast"
+	"regexp"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// GroupCall is the kind of a call to a method of a trusted group of goroutines, such as an
+// `errgroup.Group` or a `sync.WaitGroup`.
+type GroupCall uint8
+
+const (
+	// GroupSpawn is a call running its function argument in a new goroutine of the group, e.g.,
+	// `g.Go(func() error { ... })`.
+	GroupSpawn GroupCall = iota + 1
+	// GroupDone is a call marking the end of a goroutine of the group, e.g., `wg.Done()`.
+	GroupDone
+	// GroupWait is a call waiting for all goroutines of the group, e.g., `wg.Wait()`.
+	GroupWait
+	// GroupWaitErr is a call waiting for all goroutines of the group, which returns a nil error
+	// only if all of them succeeded, e.g., `g.Wait()` of an `errgroup.Group`.
+	GroupWaitErr
+)
+
+// AssumeGroupCall returns the kind of the given call to a method of a trusted group of
+// goroutines, or 0 if the call does not match any known method. This is useful for modeling the
+// results populated by the goroutines of a group, which are only available once the group is
+// waited for.
+func AssumeGroupCall(pass *analysis.Pass, call *ast.CallExpr) GroupCall {
+	for sig, kind := range _groupCalls {
+		if sig.match(pass, call) {
+			return kind
+		}
+	}
+	return 0
+}
+
+var _groupCalls = map[trustedFuncSig]GroupCall{
+	// `errgroup.Group.Go` and `errgroup.Group.TryGo`: the function is run in a new goroutine, and
+	// `Wait` returns the first nonnil error returned by the functions (if any).
+	{
+		kind:           _method,
+		enclosingRegex: regexp.MustCompile(`^golang\.org/x/sync/errgroup\.Group$`),
+		funcNameRegex:  regexp.MustCompile(`^(Try)?Go$`),
+	}: GroupSpawn,
+	{
+		kind:           _method,
+		enclosingRegex: regexp.MustCompile(`^golang\.org/x/sync/errgroup\.Group$`),
+		funcNameRegex:  regexp.MustCompile(`^Wait$`),
+	}: GroupWaitErr,
+
+	// `sync.WaitGroup.Go` (since Go 1.25), `sync.WaitGroup.Done` and `sync.WaitGroup.Wait`.
+	{
+		kind:           _method,
+		enclosingRegex: regexp.MustCompile(`^sync\.WaitGroup$`),
+		funcNameRegex:  regexp.MustCompile(`^Go$`),
+	}: GroupSpawn,
+	{
+		kind:           _method,
+		enclosingRegex: regexp.MustCompile(`^sync\.WaitGroup$`),
+		funcNameRegex:  regexp.MustCompile(`^Done$`),
+	}: GroupDone,
+	{
+		kind:           _method,
+		enclosingRegex: regexp.MustCompile(`^sync\.WaitGroup$`),
+		funcNameRegex:  regexp.MustCompile(`^Wait$`),
+	}: GroupWait,
+}
@@ -47,9 +47,11 @@ type trustedFuncSig struct {
 
 // match checks if a given call expression matches with a trusted function's signature. Namely,
 // it performs a strict matching for the function / method name and a user-defined regex match for
-// the enclosing package or struct path.
+// the enclosing package or struct path. Generic functions and methods of generic types are
+// matched by their origin objects, i.e., regardless of their (explicit or inferred) type arguments
+// (e.g., `lo.Must[int](v, err)` or `p.Load()` of an `atomic.Pointer[T]`).
 func (t *trustedFuncSig) match(pass *analysis.Pass, call *ast.CallExpr) bool {
-	sel, ok := call.Fun.(*ast.SelectorExpr)
+	sel, ok := unwrapInstantiation(call.Fun).(*ast.SelectorExpr)
 	if !ok || !t.funcNameRegex.MatchString(sel.Sel.Name) {
 		return false
 	}
@@ -58,6 +60,7 @@ func (t *trustedFuncSig) match(pass *analysis.Pass, call *ast.CallExpr) bool {
 	// if function, match enclosing "<pkg path>". E.g., for `assert.Error(err)`, path = github.com/stretchr/testify/assert
 	// if method, match with "<pkg path>.<struct name>". E.g., for `u.Require().Error(err)`, path = github.com/stretchr/testify/require.Assertions
 	if funcObj, ok := pass.TypesInfo.ObjectOf(sel.Sel).(*types.Func); ok && funcObj.Pkg() != nil {
+		funcObj = funcObj.Origin()
 		recv := funcObj.Type().(*types.Signature).Recv()
 		path := funcObj.Pkg().Path()
 
@@ -82,6 +85,19 @@ func (t *trustedFuncSig) match(pass *analysis.Pass, call *ast.CallExpr) bool {
 	return false
 }
 
+// unwrapInstantiation returns the generic function (or method) expression being explicitly
+// instantiated by the given expression (e.g., `lo.Must` in `lo.Must[int]`), or the expression
+// itself if it is not an instantiation.
+func unwrapInstantiation(expr ast.Expr) ast.Expr {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.IndexExpr:
+		return e.X
+	case *ast.IndexListExpr:
+		return e.X
+	}
+	return expr
+}
+
 // newNilBinaryExpr creates a new binary expression "expr op nil".
 func newNilBinaryExpr(expr ast.Expr, op token.Token) *ast.BinaryExpr {
 	return &ast.BinaryExpr{
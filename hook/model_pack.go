@@ -0,0 +1,42 @@
+//  Copyright (c) 2023 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hook
+
+import (
+	"go/ast"
+
+	"go.uber.org/nilaway/config"
+	"golang.org/x/tools/go/analysis"
+)
+
+// modelPackModel returns the model (e.g., config.ModelNonnil) of the first rule of the model packs
+// in the config (see config.ModelPackRule) matching the given call expression, or an empty string
+// if no rule matches.
+func modelPackModel(pass *analysis.Pass, call *ast.CallExpr) string {
+	conf, ok := pass.ResultOf[config.Analyzer].(*config.Config)
+	if !ok {
+		return ""
+	}
+	for _, rule := range conf.ModelPackRules {
+		sig := trustedFuncSig{kind: _func, enclosingRegex: rule.Enclosing, funcNameRegex: rule.FuncName}
+		if rule.IsMethod {
+			sig.kind = _method
+		}
+		if sig.match(pass, call) {
+			return rule.Model
+		}
+	}
+	return ""
+}
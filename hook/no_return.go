@@ -0,0 +1,51 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hook
+
+import (
+	"go/ast"
+	"regexp"
+
+	"go.uber.org/nilaway/config"
+	"golang.org/x/tools/go/analysis"
+)
+
+// NoReturn returns true if the given call expression never returns to the caller. The CFG built by
+// the ctrlflow analyzer already handles the static calls (e.g., `os.Exit` or `t.Fatal` on a
+// `*testing.T`), this hook additionally handles the calls that are not statically known, such as
+// `tb.Fatal` on the `testing.TB` interface, which ends the test (or benchmark) by calling
+// `runtime.Goexit` in all its implementations. The functions modeled as never returning by the
+// model packs (see config.ModelPackRule) are handled as well.
+//
+// Note that the closures registered via `t.Cleanup` (see AssumeCallback) and the subtests run via
+// `t.Run` are not executed at the call site, so calls to these functions inside them do not end
+// the enclosing flow.
+func NoReturn(pass *analysis.Pass, call *ast.CallExpr) bool {
+	for sig := range _noReturns {
+		if sig.match(pass, call) {
+			return true
+		}
+	}
+	return modelPackModel(pass, call) == config.ModelNoReturn
+}
+
+var _noReturns = map[trustedFuncSig]struct{}{
+	// `testing.TB`
+	{
+		kind:           _method,
+		enclosingRegex: regexp.MustCompile(`^testing\.TB$`),
+		funcNameRegex:  regexp.MustCompile(`^(Fatal(f)?|FailNow|Skip(f|Now)?)$`),
+	}: {},
+}
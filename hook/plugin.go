@@ -0,0 +1,104 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hook
+
+import (
+	"encoding/gob"
+	"fmt"
+	"go/ast"
+	"slices"
+	"sync"
+
+	"go.uber.org/nilaway/annotation"
+	"golang.org/x/tools/go/analysis"
+)
+
+// TriggerPlugin describes a plugin contributing triggers of new kinds (see annotation.PluginTrigger)
+// at the calls, such that the organizations (or the forks of NilAway) can add domain-specific
+// checks without editing NilAway itself, e.g., requiring the values passed to an audit sink to be
+// nonnil. Plugins are registered via RegisterTriggerPlugin.
+type TriggerPlugin struct {
+	// Name is the name of the plugin, e.g., "audit".
+	Name string
+	// Prestrings are the (zero) values of the Prestring types of the plugin triggers, which are
+	// registered for encoding them in the facts.
+	Prestrings []annotation.Prestring
+	// Consumers optionally returns the consumers of the values at the given call, e.g., the
+	// consumer of the argument of `audit.Record(event)` (with the argument as the expression)
+	// wrapping a plugin trigger in an annotation.PluginConsumer.
+	Consumers func(pass *analysis.Pass, call *ast.CallExpr) []*annotation.ConsumeTrigger
+	// Producer optionally returns the producer of the results of the given call (with the call as
+	// the expression) wrapping a plugin trigger in an annotation.PluginProducer, or nil if the
+	// call is not modeled by the plugin. Like the built-in models (see AssumeReturn), which take
+	// precedence over it, the producer replaces the analysis of the called function.
+	Producer func(pass *analysis.Pass, call *ast.CallExpr) *annotation.ProduceTrigger
+}
+
+var (
+	_triggerPluginsMu sync.RWMutex
+	_triggerPlugins   []TriggerPlugin
+)
+
+// RegisterTriggerPlugin registers the trigger plugin, and returns a function unregistering it
+// (e.g., for tests). It must be called before the analysis starts (e.g., in an init function of a
+// custom driver), by all the binaries encoding or decoding the facts of the analysis. It panics if
+// a plugin with the same name is already registered.
+func RegisterTriggerPlugin(p TriggerPlugin) (unregister func()) {
+	_triggerPluginsMu.Lock()
+	defer _triggerPluginsMu.Unlock()
+	if slices.ContainsFunc(_triggerPlugins, func(r TriggerPlugin) bool { return r.Name == p.Name }) {
+		panic(fmt.Sprintf("trigger plugin %q is already registered", p.Name))
+	}
+	for _, prestring := range p.Prestrings {
+		gob.Register(prestring)
+	}
+	_triggerPlugins = append(_triggerPlugins, p)
+	return func() {
+		_triggerPluginsMu.Lock()
+		defer _triggerPluginsMu.Unlock()
+		// The Prestring types stay registered for gob, which does not support unregistering them.
+		_triggerPlugins = slices.DeleteFunc(_triggerPlugins, func(r TriggerPlugin) bool { return r.Name == p.Name })
+	}
+}
+
+// PluginConsumers returns the consumers of the values at the given call contributed by the
+// registered trigger plugins (see TriggerPlugin).
+func PluginConsumers(pass *analysis.Pass, call *ast.CallExpr) []*annotation.ConsumeTrigger {
+	_triggerPluginsMu.RLock()
+	defer _triggerPluginsMu.RUnlock()
+	var consumers []*annotation.ConsumeTrigger
+	for _, p := range _triggerPlugins {
+		if p.Consumers != nil {
+			consumers = append(consumers, p.Consumers(pass, call)...)
+		}
+	}
+	return consumers
+}
+
+// pluginProducer returns the producer of the results of the given call contributed by the first
+// registered trigger plugin modeling it, or nil if there is none.
+func pluginProducer(pass *analysis.Pass, call *ast.CallExpr) *annotation.ProduceTrigger {
+	_triggerPluginsMu.RLock()
+	defer _triggerPluginsMu.RUnlock()
+	for _, p := range _triggerPlugins {
+		if p.Producer == nil {
+			continue
+		}
+		if producer := p.Producer(pass, call); producer != nil {
+			return producer
+		}
+	}
+	return nil
+}
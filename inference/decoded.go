@@ -0,0 +1,101 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inference
+
+import (
+	"fmt"
+)
+
+// DecodedSite is the human-readable form of an annotation site and its inferred value in an
+// InferredMap, such that drivers that only receive the encoded facts (e.g., bazel workers) can
+// render them without access to the analyzed packages.
+type DecodedSite struct {
+	// Site is the description of the site (e.g., "Result 0 of function `foo`").
+	Site string
+	// Package is the path of the package the site resides in.
+	Package string
+	// Position is the position of the site in the form of "file:line:column".
+	Position string
+	// Deep indicates whether this is the deep nilability (e.g., of the elements) of the site.
+	Deep bool
+	// Determined indicates whether the nilability of the site has been determined, in which case
+	// Nilable and Explanation are set; otherwise Implicates is set.
+	Determined bool
+	// Nilable is the determined nilability of the site.
+	Nilable bool
+	// Explanation is the chain of reasons of the determined nilability, from the direct reason to
+	// the root cause.
+	Explanation []string
+	// Implicates are the sites that must be nilable if this site is nilable.
+	Implicates []DecodedImplication
+}
+
+// DecodedImplication is the human-readable form of an implication edge between two sites in an
+// InferredMap.
+type DecodedImplication struct {
+	// Site is the description of the implicated site.
+	Site string
+	// Package is the path of the package the implicated site resides in.
+	Package string
+	// Position is the position of the implicated site in the form of "file:line:column".
+	Position string
+	// Deep indicates whether this is the deep nilability of the implicated site.
+	Deep bool
+	// Reason describes the assertion that creates the implication.
+	Reason string
+}
+
+// DecodeInferredMap decodes the gob-encoded InferredMap (i.e., the output of its GobEncode) and
+// returns its sites in the human-readable form. GobRegister must have been called before.
+func DecodeInferredMap(data []byte) ([]DecodedSite, error) {
+	var m InferredMap
+	if err := m.GobDecode(data); err != nil {
+		return nil, fmt.Errorf("decode inferred map (encoding %s): %w", GobFingerprint(), err)
+	}
+	if foreign := m.ForeignEncoding(); foreign != "" {
+		return nil, fmt.Errorf("decode inferred map: produced by encoding %s, want %s", foreign, GobFingerprint())
+	}
+
+	var sites []DecodedSite
+	m.OrderedRange(func(site primitiveSite, val InferredVal) bool {
+		decoded := DecodedSite{
+			Site:     site.Repr,
+			Package:  site.PkgPath,
+			Position: site.Position.String(),
+			Deep:     site.IsDeep,
+		}
+		switch v := val.(type) {
+		case *DeterminedVal:
+			decoded.Determined = true
+			decoded.Nilable = v.Bool.Val()
+			for r := v.Bool; r != nil; r = r.DeeperReason() {
+				decoded.Explanation = append(decoded.Explanation, fmt.Sprintf("%s at %s", r, r.Position()))
+			}
+		case *UndeterminedVal:
+			for _, p := range v.Implicates.Pairs {
+				decoded.Implicates = append(decoded.Implicates, DecodedImplication{
+					Site:     p.Key.Repr,
+					Package:  p.Key.PkgPath,
+					Position: p.Key.Position.String(),
+					Deep:     p.Key.IsDeep,
+					Reason:   fmt.Sprintf("%s %s at %s", p.Value.ProducerRepr, p.Value.ConsumerRepr, p.Value.Position),
+				})
+			}
+		}
+		sites = append(sites, decoded)
+		return true
+	})
+	return sites, nil
+}
@@ -18,12 +18,19 @@ package inference
 
 import (
 	"cmp"
+	"crypto/sha256"
 	"encoding/gob"
+	"encoding/hex"
 	"fmt"
+	"go/types"
 	"slices"
+	"strings"
+	"sync"
 
 	"go.uber.org/nilaway/annotation"
 	"go.uber.org/nilaway/assertion/function/assertiontree"
+	"go.uber.org/nilaway/config"
+	"go.uber.org/nilaway/util/orderedmap"
 	"golang.org/x/tools/go/analysis"
 )
 
@@ -31,7 +38,7 @@ import (
 // This makes the inference engine independent of the diagnostic generation logic.
 type conflictHandler interface {
 	AddSingleAssertionConflict(trigger annotation.FullTrigger)
-	AddOverconstraintConflict(nilExplanation, nonnilExplanation ExplainedBool)
+	AddOverconstraintConflict(site string, nilExplanation, nonnilExplanation ExplainedBool)
 }
 
 // Engine is the structure responsible for running the inference: it contains methods to run
@@ -50,9 +57,13 @@ type Engine struct {
 	// their primitive forms (see primitive.go).
 	primitive *primitivizer
 	// controlledTriggersBySite stores the set of controlled triggers for each site if the site
-	// controls any triggers. This field is for internal use in the struct only and should not be
-	// accessed elsewhere.
-	controlledTriggersBySite map[primitiveSite]map[annotation.FullTrigger]bool
+	// controls any triggers. The sets are ordered to keep the activation order (and hence the
+	// explanations in the diagnostics) deterministic. This field is for internal use in the struct
+	// only and should not be accessed elsewhere.
+	controlledTriggersBySite map[primitiveSite]*orderedmap.OrderedMap[annotation.FullTrigger, bool]
+	// incompatibleUpstream stores the paths of the upstream packages whose facts were produced by
+	// an incompatible version of NilAway, and hence ignored by ObserveUpstream.
+	incompatibleUpstream []string
 }
 
 // NewEngine constructs an inference engine that is ready to run inference.
@@ -97,6 +108,13 @@ func (e *Engine) ObserveUpstream() {
 	})
 
 	for _, f := range facts {
+		// The facts produced by an incompatible version of NilAway are decoded as empty maps, we
+		// record them such that a single notice can be reported instead of silently missing the
+		// upstream information.
+		if f.Fact.(*InferredMap).ForeignEncoding() != "" {
+			e.incompatibleUpstream = append(e.incompatibleUpstream, f.Package.Path())
+			continue
+		}
 		f.Fact.(*InferredMap).OrderedRange(func(site primitiveSite, val InferredVal) bool {
 			switch v := val.(type) {
 			case *DeterminedVal:
@@ -127,6 +145,12 @@ func (e *Engine) ObserveUpstream() {
 	})
 }
 
+// IncompatibleUpstream returns the sorted paths of the upstream packages whose facts were produced
+// by an incompatible version of NilAway and hence ignored by ObserveUpstream.
+func (e *Engine) IncompatibleUpstream() []string {
+	return e.incompatibleUpstream
+}
+
 // ObserveAnnotations does one of two things. If the inferenceType is FullInfer, then it reads
 // ONLY those annotations that are "set" (a separate flag for both nilability and deep nilability)
 // in an annotation.Val - corresponding to syntactically provided annotations but not default
@@ -145,6 +169,185 @@ func (e *Engine) ObserveAnnotations(pkgAnnotations *annotation.ObservedMap, mode
 	}, mode != NoInfer)
 }
 
+// ObserveImportedFacts seeds the inference with the externally produced nilness facts of the
+// current package (e.g., from dynamic analyses or manual audits) read from the facts file in the
+// given directory, which has the same format as the files written by InferredMap.WriteFactsFile.
+// The facts are matched to the annotation sites of the package by their positions and
+// descriptions, hence the facts of sites that have since moved are ignored. The sites already
+// determined (e.g., by the annotations) are not overridden. It is a no-op if the directory
+// contains no facts file for the current package.
+func (e *Engine) ObserveImportedFacts(pkgAnnotations *annotation.ObservedMap, dir string) error {
+	facts, err := readFactsFile(dir, e.pass.Pkg.Path())
+	if err != nil || facts == nil {
+		return err
+	}
+
+	type siteKey struct {
+		position string
+		repr     string
+		isDeep   bool
+	}
+	sites := make(map[siteKey]primitiveSite)
+	pkgAnnotations.Range(func(key annotation.Key, isDeep bool, _ bool) {
+		site := e.primitive.site(key, isDeep)
+		if site.PkgPath == facts.Package {
+			sites[siteKey{position: site.Position.String(), repr: site.Repr, isDeep: isDeep}] = site
+		}
+	}, false /* setSitesOnly */)
+
+	observe := func(fact FactsSite, nilability string, isDeep bool) {
+		site, ok := sites[siteKey{position: fact.Position, repr: fact.Site, isDeep: isDeep}]
+		if !ok {
+			return
+		}
+		if val, ok := e.inferredMap.Load(site); ok {
+			if _, ok := val.(*DeterminedVal); ok {
+				return
+			}
+		}
+		switch nilability {
+		case FactsNilable:
+			e.observeSiteExplanation(site, TrueBecauseImportedFact{SitePos: site.Position})
+		case FactsNonnil:
+			e.observeSiteExplanation(site, FalseBecauseImportedFact{SitePos: site.Position})
+		}
+	}
+	for _, fact := range facts.Sites {
+		observe(fact, fact.Shallow, false /* isDeep */)
+		observe(fact, fact.Deep, true /* isDeep */)
+	}
+	return nil
+}
+
+// ObserveStrictExports assumes the parameters of the exported functions of the current package
+// (i.e., the exported functions and the exported methods of the exported types) to be nilable
+// regardless of inference, since the callers outside the analysis may pass nil to them. Such
+// parameters must then be guarded before they are dereferenced, or annotated as nonnil, which
+// determines them before this call. It must be called before ObservePackage on the triggers of
+// the package, such that the conflicts are reported at the dereferences of the parameters.
+func (e *Engine) ObserveStrictExports(pkgAnnotations *annotation.ObservedMap) {
+	type paramSite struct {
+		site primitiveSite
+		name string
+	}
+	var params []paramSite
+	pkgAnnotations.Range(func(key annotation.Key, isDeep bool, _ bool) {
+		param, ok := key.(*annotation.ParamAnnotationKey)
+		if !ok || isDeep || param.FuncDecl.Pkg() != e.pass.Pkg || !isExportedAPI(param.FuncDecl) ||
+			strings.HasSuffix(e.pass.Fset.Position(param.FuncDecl.Pos()).Filename, "_test.go") {
+			return
+		}
+		sig := param.FuncDecl.Type().(*types.Signature)
+		if sig.Variadic() && param.ParamNum == sig.Params().Len()-1 {
+			// The variadic parameters are never nil unless explicitly spread (e.g., `f(nil...)`).
+			return
+		}
+		params = append(params, paramSite{site: e.primitive.site(key, isDeep), name: param.MinimalString()})
+	}, false /* setSitesOnly */)
+	// Sort the sites since the iteration order of the annotation map is not deterministic.
+	slices.SortFunc(params, func(a, b paramSite) int {
+		return cmp.Or(
+			cmp.Compare(a.site.Position.Filename, b.site.Position.Filename),
+			cmp.Compare(a.site.Position.Offset, b.site.Position.Offset),
+			cmp.Compare(a.site.Repr, b.site.Repr),
+		)
+	})
+
+	for _, p := range params {
+		if val, ok := e.inferredMap.Load(p.site); ok {
+			if _, ok := val.(*DeterminedVal); ok {
+				// The site is determined by annotations or imported facts.
+				continue
+			}
+		}
+		e.observeSiteExplanation(p.site, TrueBecauseStrictExport{SitePos: p.site.Position, Site: p.name})
+	}
+}
+
+// isExportedAPI returns true if the function can be called from other packages by its name, i.e.,
+// it is an exported function or an exported method of an exported type.
+func isExportedAPI(fn *types.Func) bool {
+	if !fn.Exported() {
+		return false
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return true
+	}
+	t := recv.Type()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Exported()
+}
+
+// ObserveDefaultNilability assigns the default nilability to the unconstrained annotation sites of
+// the current package according to the policy (see config.DefaultNilabilityOptimistic,
+// config.DefaultNilabilityPessimistic and config.DefaultNilabilityPessimisticExports), and must be
+// called before ObservePackage on the triggers of the package. The unconstrained sites are the
+// (shallow) parameters of the functions that are neither annotated nor referenced (e.g., called) in
+// the package, and that no value flows into within the package (e.g., via the interface methods
+// they implement). The arguments for such parameters come from external callers, hence they are
+// optimistically left undetermined by default. Under the pessimistic policies they are assumed
+// nilable instead, which is propagated to the sites they flow into, yielding errors at their
+// unguarded dereferences. Note that the fields and global variables are not considered, since their
+// initializations (e.g., in composite literals) are not all tracked as flows.
+func (e *Engine) ObserveDefaultNilability(pkgAnnotations *annotation.ObservedMap, triggers []annotation.FullTrigger, policy string) {
+	if policy != config.DefaultNilabilityPessimistic && policy != config.DefaultNilabilityPessimisticExports {
+		return
+	}
+
+	referenced := make(map[types.Object]bool)
+	for _, obj := range e.pass.TypesInfo.Uses {
+		if fn, ok := obj.(*types.Func); ok {
+			referenced[fn.Origin()] = true
+		}
+	}
+	flowedInto := make(map[primitiveSite]bool)
+	for _, t := range triggers {
+		if site := t.Consumer.Annotation.UnderlyingSite(); site != nil {
+			flowedInto[e.primitive.site(site, false /* isDeep */)] = true
+		}
+	}
+
+	// Collect the sites first, since the iteration order of the annotation map is not
+	// deterministic, while the order of observations matters for the explanations of conflicts.
+	var sites []primitiveSite
+	pkgAnnotations.Range(func(key annotation.Key, isDeep bool, _ bool) {
+		param, ok := key.(*annotation.ParamAnnotationKey)
+		if !ok || isDeep || referenced[param.FuncDecl] ||
+			// The functions in the test files (e.g., `TestFoo(t *testing.T)`) are called by the
+			// testing framework, which never passes nil.
+			strings.HasSuffix(e.pass.Fset.Position(param.FuncDecl.Pos()).Filename, "_test.go") {
+			return
+		}
+		site := e.primitive.site(key, isDeep)
+		if site.PkgPath != e.pass.Pkg.Path() || flowedInto[site] ||
+			(policy == config.DefaultNilabilityPessimisticExports && !site.Exported) {
+			return
+		}
+		sites = append(sites, site)
+	}, false /* setSitesOnly */)
+	slices.SortFunc(sites, func(a, b primitiveSite) int {
+		return cmp.Or(
+			cmp.Compare(a.Position.Filename, b.Position.Filename),
+			cmp.Compare(a.Position.Offset, b.Position.Offset),
+			cmp.Compare(a.Repr, b.Repr),
+		)
+	})
+
+	for _, site := range sites {
+		if val, ok := e.inferredMap.Load(site); ok {
+			if _, ok := val.(*DeterminedVal); ok {
+				// The site is determined by annotations or imported facts.
+				continue
+			}
+		}
+		e.observeSiteExplanation(site, TrueBecauseDefault{SitePos: site.Position})
+	}
+}
+
 // mapGuardMissingAndReturnToFuncSite returns two maps:
 // 1. A map with key being the function return site and value being the list of indices of guard-missing triggers matching the site.
 // 2. A map with key being the function return site and value being the list of indices of return triggers matching the site.
@@ -171,6 +374,53 @@ func (e *Engine) mapGuardMissingAndReturnToFuncSite(triggers []annotation.FullTr
 	return mapSiteGuardMissing, mapSiteReturn
 }
 
+// alwaysSafeSites returns the set of function return sites (of rich check effect functions) whose
+// every return is non-nil, i.e., the sites whose values are safe to use even without checking the
+// error (or ok) results. A return is non-nil if it is either trivially non-nil (e.g., `new(int)`),
+// or flows from the result of another always safe function in the package (e.g., `return m2()` or
+// `v, _ := m2(); return v, nil`). The latter makes the tracking span multiple hops, hence the set
+// is computed as a fixed point: a site is optimistically assumed safe, and it is removed once a
+// potentially nilable return is found.
+func (e *Engine) alwaysSafeSites(triggers []annotation.FullTrigger, mapSiteReturn map[primitiveSite][]int) map[primitiveSite]bool {
+	safeSites := make(map[primitiveSite]bool, len(mapSiteReturn))
+	for site := range mapSiteReturn {
+		safeSites[site] = true
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for site, returnIndices := range mapSiteReturn {
+			if !safeSites[site] {
+				continue
+			}
+			for _, index := range returnIndices {
+				if !e.isAlwaysSafeProducer(triggers[index].Producer.Annotation, safeSites) {
+					delete(safeSites, site)
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	return safeSites
+}
+
+// isAlwaysSafeProducer returns true if the producer is either trivially non-nil or the (possibly
+// unguarded) return of a function site in safeSites.
+func (e *Engine) isAlwaysSafeProducer(producer annotation.ProducingAnnotationTrigger, safeSites map[primitiveSite]bool) bool {
+	if producer.Kind() == annotation.Never {
+		return true
+	}
+	isDeep := producer.Kind() == annotation.DeepConditional
+	if p, ok := producer.(*annotation.GuardMissing); ok {
+		producer = p.OldAnnotation
+	}
+	if r, ok := producer.(*annotation.FuncReturn); ok && r.IsFromRichCheckEffectFunc {
+		return safeSites[e.primitive.site(r.UnderlyingSite(), isDeep)]
+	}
+	return false
+}
+
 // ObservePackage observes all the annotations and assertions computed locally about the current
 // package. The assertions are sorted based on whether they are already known to trigger without
 // reliance on annotation sites, such as `x` in `x = nil; x.f`, which will generate
@@ -185,27 +435,16 @@ func (e *Engine) ObservePackage(pkgFullTriggers []annotation.FullTrigger) {
 	// is focussed on the rich check effect functions, namely error returning functions and ok-returning functions.
 	// The process is to find all guard missing triggers reaching a function return site, and then check if all the return triggers
 	// to that function site are non-nil. If so, we can safely delete all the guard-missing triggers for this function site.
+	// The returns flowing from other such "always safe" functions in the package are non-nil as well (see alwaysSafeSites).
 	triggersToBeDeleted := make(map[int]bool)
 	mapSiteGuardMissing, mapSiteReturn := e.mapGuardMissingAndReturnToFuncSite(pkgFullTriggers)
+	safeSites := e.alwaysSafeSites(pkgFullTriggers, mapSiteReturn)
 	for site, guardMissingIndices := range mapSiteGuardMissing {
-		if returnIndices, ok := mapSiteReturn[site]; ok {
-			// Check if all the return triggers to this function site are non-nil.
-			nonnilCnt := 0
-			for _, index := range returnIndices {
-				returnTrigger := pkgFullTriggers[index]
-				if returnTrigger.Producer.Annotation.Kind() != annotation.Never {
-					// break early if we find a potentially nilable trigger
-					break
-				}
-				nonnilCnt++
-			}
-
-			if nonnilCnt == len(returnIndices) {
-				// If all return triggers are non-nil, then we can safely delete all the guard-missing triggers
-				// for this function site.
-				for _, index := range guardMissingIndices {
-					triggersToBeDeleted[index] = true
-				}
+		if safeSites[site] {
+			// If all return triggers are non-nil, then we can safely delete all the guard-missing triggers
+			// for this function site.
+			for _, index := range guardMissingIndices {
+				triggersToBeDeleted[index] = true
 			}
 		}
 	}
@@ -229,16 +468,25 @@ func (e *Engine) ObservePackage(pkgFullTriggers []annotation.FullTrigger) {
 	// their nilability status is known, then filter out the unnecessary UseAsNonErrorRetDependentOnErrorRetNilability
 	// triggers, and run the pkg inference process again only for the remainder triggers.
 	// Steps 2--4 below depict this approach in more detail.
+	//
+	// Similarly, triggers with FldOmitted consumers are separated out such that omitting a field from
+	// a composite literal never forces the field to be nilable (which would otherwise flood the
+	// dereferences of the field with errors). They are checked only after the inference is done (see
+	// Step 5 below).
 	var (
-		nonErrRetTriggers []annotation.FullTrigger
+		nonErrRetTriggers  []annotation.FullTrigger
+		fldOmittedTriggers []annotation.FullTrigger
 		// In most cases all triggers will be stored in otherTriggers, so we set a proper capacity.
 		otherTriggers = make([]annotation.FullTrigger, 0, len(pkgFullTriggers))
 	)
 
 	for _, t := range pkgFullTriggers {
-		if _, ok := t.Consumer.Annotation.(*annotation.UseAsNonErrorRetDependentOnErrorRetNilability); ok {
+		switch t.Consumer.Annotation.(type) {
+		case *annotation.UseAsNonErrorRetDependentOnErrorRetNilability:
 			nonErrRetTriggers = append(nonErrRetTriggers, t)
-		} else {
+		case *annotation.FldOmitted:
+			fldOmittedTriggers = append(fldOmittedTriggers, t)
+		default:
 			otherTriggers = append(otherTriggers, t)
 		}
 	}
@@ -301,11 +549,21 @@ func (e *Engine) ObservePackage(pkgFullTriggers []annotation.FullTrigger) {
 
 	// Step 4: run the inference building process for only the remaining UseAsNonErrorRetDependentOnErrorRetNilability triggers, and collect assertions
 	e.buildPkgInferenceMap(filteredTriggers)
+
+	// Step 5: report the FldOmitted triggers whose omitted fields are determined to be nonnil.
+	for _, t := range fldOmittedTriggers {
+		site := e.primitive.site(t.Consumer.Annotation.UnderlyingSite(), false)
+		if val, ok := e.inferredMap.Load(site); ok {
+			if vType, ok := val.(*DeterminedVal); ok && !vType.Bool.Val() {
+				e.diagnosticEngine.AddSingleAssertionConflict(t)
+			}
+		}
+	}
 }
 
 func (e *Engine) buildPkgInferenceMap(triggers []annotation.FullTrigger) {
 	// Map each site to all the triggers controlled by the site
-	controlledTgsBySite := map[primitiveSite]map[annotation.FullTrigger]bool{}
+	controlledTgsBySite := map[primitiveSite]*orderedmap.OrderedMap[annotation.FullTrigger, bool]{}
 	for _, trigger := range triggers {
 		if !trigger.Controlled() {
 			continue
@@ -316,10 +574,10 @@ func (e *Engine) buildPkgInferenceMap(triggers []annotation.FullTrigger) {
 		site := e.primitive.site(trigger.Controller, false)
 		ts, ok := controlledTgsBySite[site]
 		if !ok {
-			ts = map[annotation.FullTrigger]bool{}
+			ts = orderedmap.New[annotation.FullTrigger, bool]()
 			controlledTgsBySite[site] = ts
 		}
-		ts[trigger] = true
+		ts.Store(trigger, true)
 	}
 	e.controlledTriggersBySite = controlledTgsBySite
 
@@ -427,7 +685,7 @@ func (e *Engine) observeSiteExplanation(site primitiveSite, siteExplained Explai
 		if !v.Bool.Val() {
 			trueExplanation, falseExplanation = falseExplanation, trueExplanation
 		}
-		e.diagnosticEngine.AddOverconstraintConflict(trueExplanation, falseExplanation)
+		e.diagnosticEngine.AddOverconstraintConflict(site.String(), trueExplanation, falseExplanation)
 
 		// Even though we have a conflict, we still need to make sure to activate any controlled
 		// triggers that are waiting on this site, so that we would not miss processing any
@@ -472,8 +730,8 @@ func (e *Engine) storeDeterminedAndActivateControlledTriggers(site primitiveSite
 // to be a new value.
 func (e *Engine) activateControlledTriggers(site primitiveSite, siteExplained ExplainedBool) {
 	if controlledTgs, ok := e.controlledTriggersBySite[site]; ok && siteExplained.Val() {
-		for tg := range controlledTgs {
-			e.buildFromSingleFullTrigger(tg)
+		for _, p := range controlledTgs.Pairs {
+			e.buildFromSingleFullTrigger(p.Key)
 		}
 	}
 }
@@ -547,98 +805,137 @@ func (e *Engine) observeImplication(
 	e.inferredMap.StoreImplication(producerSite, consumerSite, assertion)
 }
 
+// gobTypes returns the implementations of the interface types (i.e., InferredVal, ExplainedBool
+// and annotation.Prestring) that can appear in the facts, in the order of their registration. The
+// types are registered under short sequential names to keep the facts small, so new types must
+// only be appended at the end, and the fingerprint of the encoding (see GobFingerprint) changes
+// whenever the list does.
+func gobTypes() []any {
+	return []any{
+		&DeterminedVal{},
+		&UndeterminedVal{},
+		FalseBecauseShallowConstraint{},
+		FalseBecauseDeepConstraint{},
+		FalseBecauseAnnotation{},
+		TrueBecauseShallowConstraint{},
+		TrueBecauseDeepConstraint{},
+		TrueBecauseAnnotation{},
+
+		annotation.PtrLoadPrestring{},
+		annotation.MapAccessPrestring{},
+		annotation.MapWrittenToPrestring{},
+		annotation.SliceAccessPrestring{},
+		annotation.FldAccessPrestring{},
+		annotation.UseAsErrorResultPrestring{},
+		annotation.FldAssignPrestring{},
+		annotation.GlobalVarAssignPrestring{},
+		annotation.ArgPassPrestring{},
+		annotation.InterfaceResultFromImplementationPrestring{},
+		annotation.MethodParamFromInterfacePrestring{},
+		annotation.UseAsReturnPrestring{},
+		annotation.SliceAssignPrestring{},
+		annotation.ArrayAssignPrestring{},
+		annotation.PtrAssignPrestring{},
+		annotation.MapAssignPrestring{},
+		annotation.DeepAssignPrimitivePrestring{},
+		annotation.ParamAssignDeepPrestring{},
+		annotation.FuncRetAssignDeepPrestring{},
+		annotation.VariadicParamAssignDeepPrestring{},
+		annotation.FieldAssignDeepPrestring{},
+		annotation.GlobalVarAssignDeepPrestring{},
+		annotation.LocalVarAssignDeepPrestring{},
+		annotation.ChanSendPrestring{},
+		annotation.ArgPassDeepPrestring{},
+		annotation.UseAsReturnDeepPrestring{},
+
+		annotation.TriggerIfNilablePrestring{},
+		annotation.TriggerIfDeepNilablePrestring{},
+		annotation.ProduceTriggerTautologyPrestring{},
+		annotation.ProduceTriggerNeverPrestring{},
+		annotation.PositiveNilCheckPrestring{},
+		annotation.NegativeNilCheckPrestring{},
+		annotation.ConstNilPrestring{},
+		annotation.NoVarAssignPrestring{},
+		annotation.FuncParamPrestring{},
+		annotation.VariadicFuncParamPrestring{},
+		annotation.TrustedFuncNilablePrestring{},
+		annotation.TrustedFuncNonnilPrestring{},
+		annotation.FldReadPrestring{},
+		annotation.FuncReturnPrestring{},
+		annotation.MethodReturnPrestring{},
+		annotation.MethodResultReachesInterfacePrestring{},
+		annotation.InterfaceParamReachesImplementationPrestring{},
+		annotation.GlobalVarReadPrestring{},
+		annotation.MapReadPrestring{},
+		annotation.SliceReadPrestring{},
+		annotation.ArrayReadPrestring{},
+		annotation.PtrReadPrestring{},
+		annotation.ChanRecvPrestring{},
+		annotation.FuncParamDeepPrestring{},
+		annotation.VariadicFuncParamDeepPrestring{},
+		annotation.FuncReturnDeepPrestring{},
+		annotation.FldReadDeepPrestring{},
+		annotation.LocalVarReadDeepPrestring{},
+		annotation.GlobalVarReadDeepPrestring{},
+		annotation.GuardMissingPrestring{},
+		annotation.UseAsFldOfReturnPrestring{},
+		annotation.ArgFldPassPrestring{},
+		annotation.ParamFldReadPrestring{},
+		annotation.UnassignedFldPrestring{},
+		annotation.FldEscapePrestring{},
+		annotation.LocatedPrestring{},
+		annotation.UseAsErrorRetWithNilabilityUnknownPrestring{},
+		annotation.UseAsNonErrorRetDependentOnErrorRetNilabilityPrestring{},
+		annotation.MethodRecvPrestring{},
+		annotation.RecvPassPrestring{},
+		annotation.MethodRecvDeepPrestring{},
+		annotation.FldReturnPrestring{},
+		annotation.TrackingSummarizedPrestring{},
+		annotation.ReflectEscapePrestring{},
+		annotation.TrustedFuncOkResultPrestring{},
+		annotation.FldOmittedPrestring{},
+		annotation.CallbackParamReachesFuncPrestring{},
+		annotation.FuncResultReachesCallbackPrestring{},
+		annotation.CallbackReturnPrestring{},
+		annotation.FuncParamFromCallbackPrestring{},
+		annotation.CallbackResultFromFuncPrestring{},
+		annotation.UnassignedArrayElemPrestring{},
+		annotation.PluginPrestring{},
+
+		FalseBecauseImportedFact{},
+		TrueBecauseImportedFact{},
+		TrueBecauseDefault{},
+		TrueBecauseStrictExport{},
+	}
+}
+
+var _gobRegisterOnce sync.Once
+
 // GobRegister must be called in an `init` function before attempting to run any procedure that can
 // deal with InferredAnnotationMaps as Facts. If not, gob encoding/decoding will be unable to handle
 // the data structures.
 // The called function RegisterName maintains an internal mapping to ensure that the
-// association between names and structs is bijective
+// association between names and structs is bijective. GobRegister is safe to be called multiple
+// times (e.g., by both the analyzer and a custom driver decoding the facts).
 func GobRegister() {
-	var curr rune
-	nextStr := func() string {
-		out := string(curr)
-		curr++
-		if curr > 255 {
+	_gobRegisterOnce.Do(func() {
+		registered := gobTypes()
+		if len(registered) > 256 {
 			panic("ERROR: too many strings requested")
 		}
-		return out
-	}
-
-	gob.RegisterName(nextStr(), &DeterminedVal{})
-	gob.RegisterName(nextStr(), &UndeterminedVal{})
-	gob.RegisterName(nextStr(), FalseBecauseShallowConstraint{})
-	gob.RegisterName(nextStr(), FalseBecauseDeepConstraint{})
-	gob.RegisterName(nextStr(), FalseBecauseAnnotation{})
-	gob.RegisterName(nextStr(), TrueBecauseShallowConstraint{})
-	gob.RegisterName(nextStr(), TrueBecauseDeepConstraint{})
-	gob.RegisterName(nextStr(), TrueBecauseAnnotation{})
-
-	gob.RegisterName(nextStr(), annotation.PtrLoadPrestring{})
-	gob.RegisterName(nextStr(), annotation.MapAccessPrestring{})
-	gob.RegisterName(nextStr(), annotation.MapWrittenToPrestring{})
-	gob.RegisterName(nextStr(), annotation.SliceAccessPrestring{})
-	gob.RegisterName(nextStr(), annotation.FldAccessPrestring{})
-	gob.RegisterName(nextStr(), annotation.UseAsErrorResultPrestring{})
-	gob.RegisterName(nextStr(), annotation.FldAssignPrestring{})
-	gob.RegisterName(nextStr(), annotation.GlobalVarAssignPrestring{})
-	gob.RegisterName(nextStr(), annotation.ArgPassPrestring{})
-	gob.RegisterName(nextStr(), annotation.InterfaceResultFromImplementationPrestring{})
-	gob.RegisterName(nextStr(), annotation.MethodParamFromInterfacePrestring{})
-	gob.RegisterName(nextStr(), annotation.UseAsReturnPrestring{})
-	gob.RegisterName(nextStr(), annotation.SliceAssignPrestring{})
-	gob.RegisterName(nextStr(), annotation.ArrayAssignPrestring{})
-	gob.RegisterName(nextStr(), annotation.PtrAssignPrestring{})
-	gob.RegisterName(nextStr(), annotation.MapAssignPrestring{})
-	gob.RegisterName(nextStr(), annotation.DeepAssignPrimitivePrestring{})
-	gob.RegisterName(nextStr(), annotation.ParamAssignDeepPrestring{})
-	gob.RegisterName(nextStr(), annotation.FuncRetAssignDeepPrestring{})
-	gob.RegisterName(nextStr(), annotation.VariadicParamAssignDeepPrestring{})
-	gob.RegisterName(nextStr(), annotation.FieldAssignDeepPrestring{})
-	gob.RegisterName(nextStr(), annotation.GlobalVarAssignDeepPrestring{})
-	gob.RegisterName(nextStr(), annotation.LocalVarAssignDeepPrestring{})
-	gob.RegisterName(nextStr(), annotation.ChanSendPrestring{})
-	gob.RegisterName(nextStr(), annotation.ArgPassDeepPrestring{})
-	gob.RegisterName(nextStr(), annotation.UseAsReturnDeepPrestring{})
-
-	gob.RegisterName(nextStr(), annotation.TriggerIfNilablePrestring{})
-	gob.RegisterName(nextStr(), annotation.TriggerIfDeepNilablePrestring{})
-	gob.RegisterName(nextStr(), annotation.ProduceTriggerTautologyPrestring{})
-	gob.RegisterName(nextStr(), annotation.ProduceTriggerNeverPrestring{})
-	gob.RegisterName(nextStr(), annotation.PositiveNilCheckPrestring{})
-	gob.RegisterName(nextStr(), annotation.NegativeNilCheckPrestring{})
-	gob.RegisterName(nextStr(), annotation.ConstNilPrestring{})
-	gob.RegisterName(nextStr(), annotation.NoVarAssignPrestring{})
-	gob.RegisterName(nextStr(), annotation.FuncParamPrestring{})
-	gob.RegisterName(nextStr(), annotation.VariadicFuncParamPrestring{})
-	gob.RegisterName(nextStr(), annotation.TrustedFuncNilablePrestring{})
-	gob.RegisterName(nextStr(), annotation.TrustedFuncNonnilPrestring{})
-	gob.RegisterName(nextStr(), annotation.FldReadPrestring{})
-	gob.RegisterName(nextStr(), annotation.FuncReturnPrestring{})
-	gob.RegisterName(nextStr(), annotation.MethodReturnPrestring{})
-	gob.RegisterName(nextStr(), annotation.MethodResultReachesInterfacePrestring{})
-	gob.RegisterName(nextStr(), annotation.InterfaceParamReachesImplementationPrestring{})
-	gob.RegisterName(nextStr(), annotation.GlobalVarReadPrestring{})
-	gob.RegisterName(nextStr(), annotation.MapReadPrestring{})
-	gob.RegisterName(nextStr(), annotation.SliceReadPrestring{})
-	gob.RegisterName(nextStr(), annotation.ArrayReadPrestring{})
-	gob.RegisterName(nextStr(), annotation.PtrReadPrestring{})
-	gob.RegisterName(nextStr(), annotation.ChanRecvPrestring{})
-	gob.RegisterName(nextStr(), annotation.FuncParamDeepPrestring{})
-	gob.RegisterName(nextStr(), annotation.VariadicFuncParamDeepPrestring{})
-	gob.RegisterName(nextStr(), annotation.FuncReturnDeepPrestring{})
-	gob.RegisterName(nextStr(), annotation.FldReadDeepPrestring{})
-	gob.RegisterName(nextStr(), annotation.LocalVarReadDeepPrestring{})
-	gob.RegisterName(nextStr(), annotation.GlobalVarReadDeepPrestring{})
-	gob.RegisterName(nextStr(), annotation.GuardMissingPrestring{})
-	gob.RegisterName(nextStr(), annotation.UseAsFldOfReturnPrestring{})
-	gob.RegisterName(nextStr(), annotation.ArgFldPassPrestring{})
-	gob.RegisterName(nextStr(), annotation.ParamFldReadPrestring{})
-	gob.RegisterName(nextStr(), annotation.UnassignedFldPrestring{})
-	gob.RegisterName(nextStr(), annotation.FldEscapePrestring{})
-	gob.RegisterName(nextStr(), annotation.LocatedPrestring{})
-	gob.RegisterName(nextStr(), annotation.UseAsErrorRetWithNilabilityUnknownPrestring{})
-	gob.RegisterName(nextStr(), annotation.UseAsNonErrorRetDependentOnErrorRetNilabilityPrestring{})
-	gob.RegisterName(nextStr(), annotation.MethodRecvPrestring{})
-	gob.RegisterName(nextStr(), annotation.RecvPassPrestring{})
-	gob.RegisterName(nextStr(), annotation.MethodRecvDeepPrestring{})
-	gob.RegisterName(nextStr(), annotation.FldReturnPrestring{})
+		for i, t := range registered {
+			gob.RegisterName(string(rune(i)), t)
+		}
+	})
+}
+
+// GobFingerprint returns the fingerprint of the gob encoding of the facts, which identifies the
+// names the interface implementations are registered under. Facts can only be decoded by a
+// binary with the same fingerprint as the one that encoded them.
+func GobFingerprint() string {
+	h := sha256.New()
+	for _, t := range gobTypes() {
+		fmt.Fprintf(h, "%T\n", t)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
 }
@@ -27,6 +27,7 @@ import (
 // - <Val>BecauseShallowConstraint: Applied to site X when X was half of an assertion where the other half was fixed as a definite site of nil production or nonnil consumption
 // - <Val>BecauseDeepConstraint: Applied to site X when X was half of an assertion where the other half was fixed, but through a deeper chain of assertions
 // - <Val>BecauseAnnotation: Applied to site X when a syntactic annotation was discovered on X
+// - <Val>BecauseImportedFact: Applied to site X when an externally produced nilness fact was imported for X
 type ExplainedBool interface {
 	fmt.Stringer
 
@@ -36,6 +37,23 @@ type ExplainedBool interface {
 	DeeperReason() ExplainedBool
 }
 
+// InstanceOf returns the description of the instantiation of the generic code that the given
+// shallow or deep constraint comes from (e.g., a nil argument passed to `Deref[int]`), or an empty
+// string if it does not come from an instantiation.
+func InstanceOf(reason ExplainedBool) string {
+	switch r := reason.(type) {
+	case TrueBecauseShallowConstraint:
+		return r.ExternalAssertion.Instance
+	case FalseBecauseShallowConstraint:
+		return r.ExternalAssertion.Instance
+	case TrueBecauseDeepConstraint:
+		return r.InternalAssertion.Instance
+	case FalseBecauseDeepConstraint:
+		return r.InternalAssertion.Instance
+	}
+	return ""
+}
+
 // ExplainedTrue is a common embedding in all instances of ExplainedBool that wrap the value `true`
 type ExplainedTrue struct{}
 
@@ -235,3 +253,118 @@ func (FalseBecauseAnnotation) TriggerReprs() (fmt.Stringer, fmt.Stringer) {
 func (f FalseBecauseAnnotation) DeeperReason() ExplainedBool {
 	return nil
 }
+
+// TrueBecauseImportedFact is used as the label for a site X for which an externally produced nilness
+// fact (see Engine.ObserveImportedFacts) asserts that it is nilable - forcing that site to be nilable.
+type TrueBecauseImportedFact struct {
+	ExplainedTrue
+	SitePos token.Position
+}
+
+func (TrueBecauseImportedFact) String() string {
+	return "NILABLE because it is asserted as so by the imported nilness facts"
+}
+
+// Position is the position of underlying site.
+func (t TrueBecauseImportedFact) Position() token.Position {
+	return t.SitePos
+}
+
+// TriggerReprs simply returns nil, nil since this constraint is the result of an imported fact.
+func (TrueBecauseImportedFact) TriggerReprs() (fmt.Stringer, fmt.Stringer) {
+	return nil, nil
+}
+
+// DeeperReason returns another ExplainedBool that marks the deeper reason of this constraint.
+// It is only nonnil for deep constraints.
+func (TrueBecauseImportedFact) DeeperReason() ExplainedBool {
+	return nil
+}
+
+// FalseBecauseImportedFact is used as the label for a site X for which an externally produced
+// nilness fact (see Engine.ObserveImportedFacts) asserts that it is nonnil - forcing that site to be
+// nonnil.
+type FalseBecauseImportedFact struct {
+	ExplainedFalse
+	SitePos token.Position
+}
+
+func (FalseBecauseImportedFact) String() string {
+	return "NONNIL because it is asserted as so by the imported nilness facts"
+}
+
+// Position is the position of underlying site.
+func (f FalseBecauseImportedFact) Position() token.Position {
+	return f.SitePos
+}
+
+// TriggerReprs simply returns nil, nil since this constraint is the result of an imported fact.
+func (FalseBecauseImportedFact) TriggerReprs() (fmt.Stringer, fmt.Stringer) {
+	return nil, nil
+}
+
+// DeeperReason returns another ExplainedBool that marks the deeper reason of this constraint.
+// It is only nonnil for deep constraints.
+func (FalseBecauseImportedFact) DeeperReason() ExplainedBool {
+	return nil
+}
+
+// TrueBecauseDefault is used as the label for a site X that is left unconstrained after analyzing
+// its package, when unconstrained sites are pessimistically assumed to be nilable (see
+// Engine.ObserveDefaultNilability).
+type TrueBecauseDefault struct {
+	ExplainedTrue
+	SitePos token.Position
+}
+
+func (TrueBecauseDefault) String() string {
+	return "NILABLE because it is unconstrained and assumed so by default"
+}
+
+// Position is the position of underlying site.
+func (t TrueBecauseDefault) Position() token.Position {
+	return t.SitePos
+}
+
+// TriggerReprs simply returns nil, nil since this constraint is the result of the default.
+func (TrueBecauseDefault) TriggerReprs() (fmt.Stringer, fmt.Stringer) {
+	return nil, nil
+}
+
+// DeeperReason returns another ExplainedBool that marks the deeper reason of this constraint.
+// It is only nonnil for deep constraints.
+func (TrueBecauseDefault) DeeperReason() ExplainedBool {
+	return nil
+}
+
+// TrueBecauseStrictExport is used as the label for a site X that is a parameter of an exported
+// function in the strict mode, where such parameters are assumed nilable regardless of inference
+// since the callers outside the analysis may pass nil (see Engine.ObserveStrictExports).
+type TrueBecauseStrictExport struct {
+	ExplainedTrue
+	SitePos token.Position
+	// Site is the description of the parameter (e.g., "arg `x`"), since the parameters of the same
+	// function share the same position.
+	Site string
+}
+
+func (t TrueBecauseStrictExport) String() string {
+	return fmt.Sprintf("NILABLE because %s of the exported function may be passed nil by external callers "+
+		"(guard it or annotate it as nonnil)", t.Site)
+}
+
+// Position is the position of underlying site.
+func (t TrueBecauseStrictExport) Position() token.Position {
+	return t.SitePos
+}
+
+// TriggerReprs simply returns nil, nil since this constraint is the result of the strict mode.
+func (TrueBecauseStrictExport) TriggerReprs() (fmt.Stringer, fmt.Stringer) {
+	return nil, nil
+}
+
+// DeeperReason returns another ExplainedBool that marks the deeper reason of this constraint.
+// It is only nonnil for deep constraints.
+func (TrueBecauseStrictExport) DeeperReason() ExplainedBool {
+	return nil
+}
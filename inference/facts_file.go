@@ -0,0 +1,183 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inference
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"golang.org/x/tools/go/types/objectpath"
+)
+
+const (
+	// FactsNilable is the nilability of a site in the facts file that may be nil.
+	FactsNilable = "nilable"
+	// FactsNonnil is the nilability of a site in the facts file that is never nil.
+	FactsNonnil = "nonnil"
+)
+
+// FactsFile is the machine-readable form of the final nilabilities of the annotation sites of a
+// package, written to the directory given by the `-export-facts-dir` flag such that other tools
+// (e.g., code generators for runtime guards or API docs) can consume NilAway's conclusions.
+type FactsFile struct {
+	// Package is the path of the package.
+	Package string `json:"package"`
+	// Sites are the annotation sites of the package whose nilabilities have been determined,
+	// sorted by their positions.
+	Sites []FactsSite `json:"sites"`
+}
+
+// FactsSite is the determined nilability of an annotation site (e.g., a parameter of a function)
+// in the FactsFile.
+type FactsSite struct {
+	// Site is the human-readable description of the site (e.g., "Result 0 of function `foo`").
+	Site string `json:"site"`
+	// Position is the position of the site in the form of "file:line:column".
+	Position string `json:"position"`
+	// ObjectPath identifies the object of the site relative to the package (see
+	// objectpath.Path), it is only available for exported objects.
+	ObjectPath objectpath.Path `json:"objectPath,omitempty"`
+	// Exported indicates whether the site is exported in the package.
+	Exported bool `json:"exported"`
+	// Shallow is the nilability of the site itself (FactsNilable or FactsNonnil), it is empty if
+	// undetermined.
+	Shallow string `json:"shallow,omitempty"`
+	// Deep is the nilability of the elements of the site (e.g., the elements of a slice), it is
+	// empty if undetermined or not applicable.
+	Deep string `json:"deep,omitempty"`
+}
+
+// Facts returns the determined nilabilities of the annotation sites of the given package in the
+// map, both from inference and from the annotations.
+func (i *InferredMap) Facts(pkgPath string) *FactsFile {
+	type siteKey struct {
+		position token.Position
+		repr     string
+	}
+	sites := make(map[siteKey]*FactsSite)
+	i.OrderedRange(func(site primitiveSite, val InferredVal) bool {
+		determined, ok := val.(*DeterminedVal)
+		if !ok || site.PkgPath != pkgPath {
+			return true
+		}
+		key := siteKey{position: site.Position, repr: site.Repr}
+		s, ok := sites[key]
+		if !ok {
+			s = &FactsSite{Site: site.Repr, Position: site.Position.String(), ObjectPath: site.ObjectPath, Exported: site.Exported}
+			sites[key] = s
+		}
+		nilability := FactsNonnil
+		if determined.Bool.Val() {
+			nilability = FactsNilable
+		}
+		if site.IsDeep {
+			s.Deep = nilability
+		} else {
+			s.Shallow = nilability
+		}
+		return true
+	})
+
+	keys := make([]siteKey, 0, len(sites))
+	for key := range sites {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b siteKey) int {
+		return cmp.Or(
+			cmp.Compare(a.position.Filename, b.position.Filename),
+			cmp.Compare(a.position.Offset, b.position.Offset),
+			cmp.Compare(a.repr, b.repr),
+		)
+	})
+	facts := &FactsFile{Package: pkgPath, Sites: make([]FactsSite, 0, len(keys))}
+	for _, key := range keys {
+		facts.Sites = append(facts.Sites, *sites[key])
+	}
+	return facts
+}
+
+// factsFilePath returns the path of the FactsFile of the given package in the directory.
+func factsFilePath(dir string, pkgPath string) string {
+	return filepath.Join(dir, filepath.FromSlash(pkgPath)+".json")
+}
+
+// readFactsFile reads the FactsFile of the given package from `<dir>/<package path>.json`. It
+// returns nil if the file does not exist.
+func readFactsFile(dir string, pkgPath string) (*FactsFile, error) {
+	content, err := os.ReadFile(factsFilePath(dir, pkgPath))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read facts file of package %q: %w", pkgPath, err)
+	}
+	var facts FactsFile
+	if err := json.Unmarshal(content, &facts); err != nil {
+		return nil, fmt.Errorf("decode facts file of package %q: %w", pkgPath, err)
+	}
+	if facts.Package != pkgPath {
+		return nil, fmt.Errorf("facts file of package %q is for package %q", pkgPath, facts.Package)
+	}
+	for _, site := range facts.Sites {
+		for _, nilability := range []string{site.Shallow, site.Deep} {
+			if nilability != "" && nilability != FactsNilable && nilability != FactsNonnil {
+				return nil, fmt.Errorf("facts file of package %q: unknown nilability %q for %s", pkgPath, nilability, site.Site)
+			}
+		}
+	}
+	return &facts, nil
+}
+
+// WriteFactsFile writes the FactsFile of the given package to `<dir>/<package path>.json`. The
+// file is written atomically (i.e., to a temporary file that is then renamed), since the same
+// package may be analyzed concurrently in different variants (e.g., with its tests).
+func (i *InferredMap) WriteFactsFile(dir string, pkgPath string) error {
+	content, err := json.MarshalIndent(i.Facts(pkgPath), "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode facts of package %q: %w", pkgPath, err)
+	}
+
+	path := factsFilePath(dir, pkgPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create directory for facts of package %q: %w", pkgPath, err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create facts file of package %q: %w", pkgPath, err)
+	}
+	defer os.Remove(tmp.Name()) // no-op if renamed successfully
+	if err := tmp.Chmod(0o644); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write facts file of package %q: %w", pkgPath, err)
+	}
+	if _, err := tmp.Write(append(content, '\n')); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write facts file of package %q: %w", pkgPath, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write facts file of package %q: %w", pkgPath, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("write facts file of package %q: %w", pkgPath, err)
+	}
+	return nil
+}
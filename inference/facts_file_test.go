@@ -0,0 +1,103 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inference
+
+import (
+	"encoding/json"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFacts(t *testing.T) {
+	t.Parallel()
+
+	m := newInferredMap(nil /* primitivizer */)
+	site := func(line int, repr string, isDeep bool, pkgPath string) primitiveSite {
+		return primitiveSite{
+			Position: token.Position{Filename: "foo.go", Offset: line * 10, Line: line, Column: 1},
+			PkgPath:  pkgPath,
+			Repr:     repr,
+			IsDeep:   isDeep,
+			Exported: true,
+		}
+	}
+	pos := token.Position{Filename: "foo.go", Line: 1, Column: 1}
+
+	m.StoreDetermined(site(10, "Result 0 of function `Foo`", false, "foo"), TrueBecauseShallowConstraint{})
+	m.StoreDetermined(site(10, "Result 0 of function `Foo`", true, "foo"), FalseBecauseDeepConstraint{})
+	m.StoreDetermined(site(2, "Param 0 of function `Bar`", false, "foo"), FalseBecauseAnnotation{AnnotationPos: pos})
+	// Undetermined sites and sites of other packages are not included.
+	m.StoreImplication(site(3, "Param 0 of function `Baz`", false, "foo"), site(4, "Result 0 of function `Baz`", false, "foo"), primitiveFullTrigger{})
+	m.StoreDetermined(site(5, "Result 0 of function `Upstream`", false, "bar"), TrueBecauseAnnotation{AnnotationPos: pos})
+
+	expected := &FactsFile{
+		Package: "foo",
+		Sites: []FactsSite{
+			{Site: "Param 0 of function `Bar`", Position: "foo.go:2:1", Exported: true, Shallow: FactsNonnil},
+			{Site: "Result 0 of function `Foo`", Position: "foo.go:10:1", Exported: true, Shallow: FactsNilable, Deep: FactsNonnil},
+		},
+	}
+	require.Equal(t, expected, m.Facts("foo"))
+
+	dir := t.TempDir()
+	require.NoError(t, m.WriteFactsFile(dir, "example.com/foo"))
+	require.NoError(t, m.WriteFactsFile(dir, "foo"))
+	content, err := os.ReadFile(filepath.Join(dir, "foo.json"))
+	require.NoError(t, err)
+	var actual FactsFile
+	require.NoError(t, json.Unmarshal(content, &actual))
+	require.Equal(t, expected, &actual)
+	require.FileExists(t, filepath.Join(dir, "example.com", "foo.json"))
+}
+
+func TestReadFactsFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	write := func(pkgPath string, content string) {
+		path := filepath.Join(dir, filepath.FromSlash(pkgPath)+".json")
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	// A missing facts file is not an error.
+	facts, err := readFactsFile(dir, "example.com/missing")
+	require.NoError(t, err)
+	require.Nil(t, facts)
+
+	write("example.com/valid", `{"package": "example.com/valid", "sites": [{"site": "Field f", "position": "foo.go:1:2", "shallow": "nilable"}]}`)
+	facts, err = readFactsFile(dir, "example.com/valid")
+	require.NoError(t, err)
+	require.Equal(t, &FactsFile{
+		Package: "example.com/valid",
+		Sites:   []FactsSite{{Site: "Field f", Position: "foo.go:1:2", Shallow: FactsNilable}},
+	}, facts)
+
+	write("example.com/mismatch", `{"package": "example.com/other", "sites": []}`)
+	_, err = readFactsFile(dir, "example.com/mismatch")
+	require.ErrorContains(t, err, "example.com/other")
+
+	write("example.com/unknown", `{"package": "example.com/unknown", "sites": [{"site": "Field f", "deep": "maybe"}]}`)
+	_, err = readFactsFile(dir, "example.com/unknown")
+	require.ErrorContains(t, err, "unknown nilability")
+
+	write("example.com/malformed", `{`)
+	_, err = readFactsFile(dir, "example.com/malformed")
+	require.Error(t, err)
+}
@@ -44,6 +44,9 @@ type InferredMap struct {
 	primitive       *primitivizer
 	upstreamMapping map[primitiveSite]InferredVal
 	mapping         *orderedmap.OrderedMap[primitiveSite, InferredVal]
+	// foreignEncoding is the fingerprint of the encoding of a decoded map that was produced by an
+	// incompatible version of NilAway (see GobDecode), empty otherwise.
+	foreignEncoding string
 }
 
 // newInferredMap returns a new, empty InferredMap.
@@ -162,9 +165,20 @@ func (i *InferredMap) Export(pass *analysis.Pass) {
 	}
 }
 
-// GobEncode encodes the inferred map via gob encoding.
+// _encodingMagic is the magic prefix of the header of the encoded inferred maps, which is followed
+// by the fingerprint of the encoding (see GobFingerprint).
+const _encodingMagic = "NA"
+
+// _unversionedEncoding is the fingerprint reported for the encoded maps without a header, i.e.,
+// the ones produced by the versions of NilAway before the header was introduced.
+const _unversionedEncoding = "unversioned"
+
+// GobEncode encodes the inferred map via gob encoding. The encoded map starts with a header
+// identifying the encoding, such that the maps produced by incompatible versions of NilAway can
+// be detected instead of failing (or panicking) in the middle of decoding.
 func (i *InferredMap) GobEncode() (b []byte, err error) {
 	var buf bytes.Buffer
+	buf.WriteString(_encodingMagic + GobFingerprint())
 	writer := s2.NewWriter(&buf)
 	defer func() {
 		if cerr := writer.Close(); cerr != nil {
@@ -183,15 +197,35 @@ func (i *InferredMap) GobEncode() (b []byte, err error) {
 	return buf.Bytes(), nil
 }
 
-// GobDecode decodes the InferredMap from buffer.
+// GobDecode decodes the InferredMap from buffer. A map produced by an incompatible version of
+// NilAway is decoded as an empty map instead of an error, since the drivers treat the errors as
+// fatal; the mismatch is available via ForeignEncoding such that it can be reported.
 func (i *InferredMap) GobDecode(input []byte) error {
 	i.mapping = orderedmap.New[primitiveSite, InferredVal]()
 	i.upstreamMapping = make(map[primitiveSite]InferredVal)
+	i.foreignEncoding = ""
+
+	fingerprint := GobFingerprint()
+	header := _encodingMagic + fingerprint
+	if !bytes.HasPrefix(input, []byte(header)) {
+		i.foreignEncoding = _unversionedEncoding
+		if rest, ok := bytes.CutPrefix(input, []byte(_encodingMagic)); ok && len(rest) >= len(fingerprint) {
+			i.foreignEncoding = string(rest[:len(fingerprint)])
+		}
+		return nil
+	}
 
-	buf := bytes.NewBuffer(input)
+	buf := bytes.NewBuffer(input[len(header):])
 	return gob.NewDecoder(s2.NewReader(buf)).Decode(&i.mapping)
 }
 
+// ForeignEncoding returns the fingerprint of the encoding of the decoded map if it was produced
+// by an incompatible version of NilAway (in which case the map is empty), or an empty string
+// otherwise.
+func (i *InferredMap) ForeignEncoding() string {
+	return i.foreignEncoding
+}
+
 // chooseSitesToExport returns the set of AnnotationSites mapped by this InferredMap that are both
 // reachable from and that reach an Exported (in the go sense; i.e. capitalized) site. We define
 // reachability  here to be reflexive, and we choose this definition so that the returned set is
@@ -324,3 +358,22 @@ func (i *InferredMap) checkAnnotationKey(key annotation.Key) (annotation.Val, bo
 		IsDeepNilableSet: true,
 	}, true
 }
+
+// InferredNilability returns the nilability of the (shallow or deep) annotation site of the key if
+// it has been determined by inference, and false if the site is undetermined or its nilability
+// merely comes from a syntactic annotation.
+func (i *InferredMap) InferredNilability(key annotation.Key, isDeep bool) (nilable bool, ok bool) {
+	val, ok := i.mapping.Load(i.primitive.site(key, isDeep))
+	if !ok {
+		return false, false
+	}
+	determined, ok := val.(*DeterminedVal)
+	if !ok {
+		return false, false
+	}
+	switch determined.Bool.(type) {
+	case TrueBecauseAnnotation, FalseBecauseAnnotation:
+		return false, false
+	}
+	return determined.Bool.Val(), true
+}
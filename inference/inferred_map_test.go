@@ -17,6 +17,7 @@ package inference
 import (
 	"bytes"
 	"encoding/gob"
+	"fmt"
 	"go/token"
 	"testing"
 
@@ -104,6 +105,114 @@ func TestDecoding(t *testing.T) {
 	require.Equal(t, value, v.(*DeterminedVal).Bool)
 }
 
+func TestDecoding_ForeignEncoding(t *testing.T) {
+	t.Parallel()
+
+	m := newInferredMap(nil /* primitive */)
+	site := primitiveSite{Position: token.Position{Filename: "foo.go", Line: 1, Column: 2}}
+	m.StoreDetermined(site, TrueBecauseAnnotation{AnnotationPos: site.Position})
+	data, err := m.GobEncode()
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(data, []byte(_encodingMagic+GobFingerprint())))
+
+	// The maps produced by the same encoding are decoded as is.
+	var decoded InferredMap
+	require.NoError(t, decoded.GobDecode(data))
+	require.Empty(t, decoded.ForeignEncoding())
+	require.Equal(t, 1, decoded.Len())
+
+	tests := map[string]struct {
+		data []byte
+		want string
+	}{
+		"different fingerprint": {
+			data: append([]byte(_encodingMagic+"0123456789abcdef"), data[len(_encodingMagic)+len(GobFingerprint()):]...),
+			want: "0123456789abcdef",
+		},
+		"unversioned": {
+			data: data[len(_encodingMagic)+len(GobFingerprint()):],
+			want: _unversionedEncoding,
+		},
+		"truncated header": {
+			data: []byte(_encodingMagic + "0123"),
+			want: _unversionedEncoding,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// The maps produced by other encodings are decoded as empty maps without errors.
+			var decoded InferredMap
+			require.NoError(t, decoded.GobDecode(tt.data))
+			require.Equal(t, tt.want, decoded.ForeignEncoding())
+			require.Zero(t, decoded.Len())
+		})
+	}
+}
+
+func TestDecodeInferredMap(t *testing.T) {
+	t.Parallel()
+
+	m := newInferredMap(nil /* primitive */)
+	pos := token.Position{Filename: "foo.go", Line: 1, Column: 2}
+	determined := primitiveSite{Position: pos, PkgPath: "foo", Repr: "Result 0 of function `foo`"}
+	m.StoreDetermined(determined, TrueBecauseAnnotation{AnnotationPos: pos})
+	from := primitiveSite{Position: pos, PkgPath: "foo", Repr: "Param 0 of function `bar`"}
+	to := primitiveSite{Position: pos, PkgPath: "foo", Repr: "Global variable `baz`", IsDeep: true}
+	m.StoreImplication(from, to, primitiveFullTrigger{
+		Position:     pos,
+		ProducerRepr: annotation.FuncParamPrestring{ParamName: "p", FuncName: "bar"},
+		ConsumerRepr: annotation.GlobalVarAssignDeepPrestring{VarName: "baz"},
+	})
+
+	data, err := m.GobEncode()
+	require.NoError(t, err)
+	sites, err := DecodeInferredMap(data)
+	require.NoError(t, err)
+	require.Equal(t, []DecodedSite{
+		{
+			Site:        "Result 0 of function `foo`",
+			Package:     "foo",
+			Position:    "foo.go:1:2",
+			Determined:  true,
+			Nilable:     true,
+			Explanation: []string{"NILABLE because it is annotated as so at foo.go:1:2"},
+		},
+		{
+			Site:     "Param 0 of function `bar`",
+			Package:  "foo",
+			Position: "foo.go:1:2",
+			Implicates: []DecodedImplication{{
+				Site:     "Global variable `baz`",
+				Package:  "foo",
+				Position: "foo.go:1:2",
+				Deep:     true,
+				Reason:   fmt.Sprintf("%s %s at foo.go:1:2", annotation.FuncParamPrestring{ParamName: "p", FuncName: "bar"}, annotation.GlobalVarAssignDeepPrestring{VarName: "baz"}),
+			}},
+		},
+		{
+			Site:     "Global variable `baz`",
+			Package:  "foo",
+			Position: "foo.go:1:2",
+			Deep:     true,
+		},
+	}, sites)
+
+	_, err = DecodeInferredMap([]byte("invalid"))
+	require.ErrorContains(t, err, GobFingerprint())
+}
+
+func TestGobRegister_Idempotent(t *testing.T) {
+	t.Parallel()
+
+	// GobRegister is already called in TestMain, calling it again (e.g., by a custom driver)
+	// must not panic, and the fingerprint must be stable.
+	require.NotPanics(t, GobRegister)
+	require.Len(t, GobFingerprint(), 16)
+	require.Equal(t, GobFingerprint(), GobFingerprint())
+}
+
 // newBigInferredMap creates an inferred map with 3000 sites, where the first 1000 are determined,
 // and the next 2000 with implications between them for stress testing.
 func newBigInferredMap() *InferredMap {
@@ -16,11 +16,15 @@ package inference
 
 import (
 	"fmt"
+	"go/ast"
 	"go/token"
+	"go/types"
 	"os"
 	"path/filepath"
+	"reflect"
 
 	"go.uber.org/nilaway/annotation"
+	"go.uber.org/nilaway/util/asthelper"
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/types/objectpath"
 )
@@ -40,6 +44,9 @@ type primitiveFullTrigger struct {
 	Position     token.Position
 	ProducerRepr annotation.Prestring
 	ConsumerRepr annotation.Prestring
+	// Instance describes the instantiation of the generic code that the sites of the trigger are
+	// instantiated from (see instanceOf), empty if they are not instantiated.
+	Instance string
 }
 
 // A primitiveSite represents an atomic choice that may be made about annotations. It is
@@ -128,6 +135,11 @@ type primitivizer struct {
 	// objPathEncoder is used to encode object paths, which amortizes the cost of encoding the
 	// paths of multiple objects.
 	objPathEncoder *objectpath.Encoder
+	// cgoFiles is the set of files of the current package that are rewritten by cgo, whose
+	// positions are adjusted by their "//line" directives (see toPosition).
+	cgoFiles map[*token.File]bool
+	// prestrings interns the prestrings of the primitive full triggers (see intern).
+	prestrings map[annotation.Prestring]annotation.Prestring
 }
 
 // newPrimitivizer returns a new and properly-initialized primitivizer.
@@ -171,11 +183,20 @@ func newPrimitivizer(pass *analysis.Pass) *primitivizer {
 		panic(fmt.Sprintf("cannot get current working directory: %v", err))
 	}
 
+	cgoFiles := make(map[*token.File]bool)
+	for _, file := range pass.Files {
+		if asthelper.IsCgoFile(pass.Fset, file) {
+			cgoFiles[pass.Fset.File(file.Pos())] = true
+		}
+	}
+
 	return &primitivizer{
 		pass:                 pass,
 		upstreamObjPositions: upstreamObjPositions,
 		curDir:               cwd,
 		objPathEncoder:       &objectpath.Encoder{},
+		cgoFiles:             cgoFiles,
+		prestrings:           make(map[annotation.Prestring]annotation.Prestring),
 	}
 }
 
@@ -189,10 +210,105 @@ func (p *primitivizer) fullTrigger(trigger annotation.FullTrigger) primitiveFull
 
 	producer, consumer := trigger.Prestrings(p.pass)
 	return primitiveFullTrigger{
-		Position:     p.toPosition(trigger.Consumer.Expr.Pos()),
-		ProducerRepr: producer,
-		ConsumerRepr: consumer,
+		Position:     p.toPosition(trigger.Consumer.Pos()),
+		ProducerRepr: p.intern(producer),
+		ConsumerRepr: p.intern(consumer),
+		Instance:     p.instanceOf(trigger),
+	}
+}
+
+// intern returns the canonical instance of the given prestring. The same prestrings recur across
+// many triggers (e.g., every dereference of the result of the same function), and the primitive
+// full triggers holding them are kept in the inferred maps, which are exported as facts and hence
+// kept in memory for the rest of the analysis. Sharing one instance per distinct prestring avoids
+// retaining a copy per trigger. Unlike the triggers themselves, the prestrings are immutable values,
+// so they can be shared safely. The prestrings that are not comparable (e.g., the ones of trigger
+// plugins holding slices) are returned as is.
+func (p *primitivizer) intern(prestring annotation.Prestring) annotation.Prestring {
+	if prestring == nil || !reflect.ValueOf(prestring).Comparable() {
+		return prestring
+	}
+	if canonical, ok := p.prestrings[prestring]; ok {
+		return canonical
+	}
+	p.prestrings[prestring] = prestring
+	return prestring
+}
+
+// instanceOf returns the description of the instantiation of the generic code that the consumer
+// (or otherwise the producer) site of the trigger belongs to, e.g., "*int" for a nil argument
+// passed to the parameter `p *T` of `Deref[int]`. The sites of different instantiations share the
+// positions and hence the errors, so this distinguishes them for reporting. It returns an empty
+// string if neither site is instantiated.
+func (p *primitivizer) instanceOf(trigger annotation.FullTrigger) string {
+	for _, side := range []struct {
+		key  annotation.Key
+		expr ast.Expr
+	}{
+		{key: trigger.Consumer.Annotation.UnderlyingSite(), expr: trigger.Consumer.Expr},
+		{key: trigger.Producer.Annotation.UnderlyingSite(), expr: trigger.Producer.Expr},
+	} {
+		if side.key == nil {
+			continue
+		}
+		// The uses of the generic functions refer to their origins, hence the instantiations of
+		// their parameters and results are only known from the types of the expressions.
+		var declared types.Type
+		switch key := side.key.(type) {
+		case *annotation.ParamAnnotationKey:
+			if params := key.FuncDecl.Type().(*types.Signature).Params(); key.ParamNum < params.Len() {
+				declared = params.At(key.ParamNum).Type()
+			}
+		case *annotation.RetAnnotationKey:
+			if results := key.FuncDecl.Type().(*types.Signature).Results(); key.RetNum < results.Len() {
+				declared = results.At(key.RetNum).Type()
+			}
+		}
+		if declared != nil && hasTypeParam(declared) && side.expr != nil {
+			if t := p.pass.TypesInfo.TypeOf(side.expr); t != nil && !hasTypeParam(t) {
+				return types.TypeString(t, nil /* qualifier */)
+			}
+		}
+
+		// On the other hand, the uses of the fields and methods of the instantiated generic types
+		// refer to the instantiated objects.
+		var instantiated bool
+		switch obj := side.key.Object().(type) {
+		case *types.Func:
+			instantiated = obj.Origin() != obj
+		case *types.Var:
+			instantiated = obj.Origin() != obj
+		}
+		if instantiated {
+			return types.TypeString(side.key.Object().Type(), nil /* qualifier */)
+		}
+	}
+	return ""
+}
+
+// hasTypeParam returns true if the given type is (or is composed of) a type parameter.
+func hasTypeParam(t types.Type) bool {
+	switch t := types.Unalias(t).(type) {
+	case *types.TypeParam:
+		return true
+	case *types.Pointer:
+		return hasTypeParam(t.Elem())
+	case *types.Slice:
+		return hasTypeParam(t.Elem())
+	case *types.Array:
+		return hasTypeParam(t.Elem())
+	case *types.Chan:
+		return hasTypeParam(t.Elem())
+	case *types.Map:
+		return hasTypeParam(t.Key()) || hasTypeParam(t.Elem())
+	case *types.Named:
+		for i := 0; i < t.TypeArgs().Len(); i++ {
+			if hasTypeParam(t.TypeArgs().At(i)) {
+				return true
+			}
+		}
 	}
+	return false
 }
 
 // site returns the primitive version of the annotation site.
@@ -246,7 +362,14 @@ func (p *primitivizer) toPosition(pos token.Pos) token.Position {
 	// identifying upstream objects in our cross-package inference, such adjustment will break it
 	// the inference (downstream analysis knows nothing about the "original source file").
 	// Therefore, here we explicitly disable the adjustment.
-	position := p.pass.Fset.PositionFor(pos, false /* adjusted */)
+	//
+	// The only exception is the files rewritten by cgo: they are generated in temporary (or, for
+	// build systems like bazel, sandboxed) directories that differ across the analyses, and the
+	// compiler records the adjusted positions (i.e., the ones in the original source files) for
+	// their objects in the export data that the downstream analysis reads. Therefore, adjusting
+	// the positions here keeps them consistent with the downstream analysis, and makes the
+	// diagnostics point at the original source files.
+	position := p.pass.Fset.PositionFor(pos, p.cgoFiles[p.pass.Fset.File(pos)] /* adjusted */)
 
 	// For build systems that employ sandboxing (e.g., bazel), the file names in the `Fset` may
 	// contain a random prefix. For example:
@@ -0,0 +1,59 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inference
+
+import (
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/nilaway/annotation"
+)
+
+// sliceprestring is a prestring that is not comparable, like the ones trigger plugins may define.
+type sliceprestring struct {
+	names []string
+}
+
+func (s sliceprestring) String() string {
+	return strings.Join(s.names, ", ")
+}
+
+func TestIntern(t *testing.T) {
+	t.Parallel()
+
+	p := &primitivizer{prestrings: make(map[annotation.Prestring]annotation.Prestring)}
+
+	first := p.intern(annotation.FuncReturnPrestring{RetNum: 0, FuncName: "foo"})
+	require.Equal(t, first, p.intern(annotation.FuncReturnPrestring{RetNum: 0, FuncName: "foo"}))
+	p.intern(annotation.FuncReturnPrestring{RetNum: 1, FuncName: "foo"})
+	located := annotation.LocatedPrestring{
+		Contained: annotation.FuncReturnPrestring{RetNum: 0, FuncName: "foo"},
+		Location:  token.Position{Filename: "foo.go", Line: 1, Column: 1},
+	}
+	require.Equal(t, located, p.intern(located))
+	// The equal prestrings share one entry.
+	require.Len(t, p.prestrings, 3)
+
+	// The prestrings that are not comparable (even only dynamically) are not interned, and do not
+	// cause panics when used as map keys.
+	require.NotPanics(t, func() {
+		p.intern(sliceprestring{names: []string{"foo"}})
+		p.intern(annotation.LocatedPrestring{Contained: sliceprestring{names: []string{"foo"}}})
+	})
+	require.Len(t, p.prestrings, 3)
+	require.Nil(t, p.intern(nil))
+}
@@ -13,12 +13,13 @@
 // limitations under the License.
 
 // Package nilaway implements the top-level analyzer that simply retrieves the diagnostics from
-// the accumulation analyzer and reports them.
+// the accumulation analyzer (and the complementary redundantcheck analyzer) and reports them.
 package nilaway
 
 import (
 	"go.uber.org/nilaway/accumulation"
 	"go.uber.org/nilaway/config"
+	"go.uber.org/nilaway/redundantcheck"
 	"go.uber.org/nilaway/util"
 	"golang.org/x/tools/go/analysis"
 )
@@ -29,16 +30,19 @@ const _doc = "Run NilAway on this package to report any possible flows of nil va
 // Analyzer is the top-level instance of Analyzer - it coordinates the entire dataflow to report
 // nil flow errors in this package. It is needed here for nogo to recognize the package.
 var Analyzer = &analysis.Analyzer{
-	Name:      "nilaway",
-	Doc:       _doc,
-	Run:       run,
-	FactTypes: []analysis.Fact{},
-	Requires:  []*analysis.Analyzer{config.Analyzer, accumulation.Analyzer},
+	Name:             "nilaway",
+	Doc:              _doc,
+	Run:              run,
+	FactTypes:        []analysis.Fact{},
+	Requires:         []*analysis.Analyzer{config.Analyzer, accumulation.Analyzer, redundantcheck.Analyzer},
+	RunDespiteErrors: true,
 }
 
 func run(pass *analysis.Pass) (interface{}, error) {
 	conf := pass.ResultOf[config.Analyzer].(*config.Config)
-	deferredErrors := pass.ResultOf[accumulation.Analyzer].([]analysis.Diagnostic)
+	deferredErrors := pass.ResultOf[accumulation.Analyzer].(*accumulation.Result).Diagnostics
+	// The redundant nil checks are only reported if requested (see config.Config.ReportRedundantChecks).
+	deferredErrors = append(deferredErrors, pass.ResultOf[redundantcheck.Analyzer].([]analysis.Diagnostic)...)
 	for _, e := range deferredErrors {
 		if conf.PrettyPrint {
 			e.Message = util.PrettyPrintErrorMessage(e.Message)
@@ -23,13 +23,23 @@ package nilaway
 
 import (
 	"fmt"
+	"go/ast"
+	"go/types"
 	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
+	"go.uber.org/nilaway/annotation"
 	"go.uber.org/nilaway/config"
+	"go.uber.org/nilaway/hook"
+	"go.uber.org/nilaway/util"
+	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/analysistest"
+	"golang.org/x/tools/go/types/typeutil"
 )
 
 func TestNilAway(t *testing.T) {
@@ -45,35 +55,51 @@ func TestNilAway(t *testing.T) {
 		patterns []string
 	}{
 		{name: "Inference", patterns: []string{"go.uber.org/inference"}},
-		{name: "Contracts", patterns: []string{"go.uber.org/contracts", "go.uber.org/contracts/namedtypes", "go.uber.org/contracts/inference"}},
+		{name: "Contracts", patterns: []string{"go.uber.org/contracts", "go.uber.org/contracts/namedtypes", "go.uber.org/contracts/inference", "go.uber.org/contracts/okreturn"}},
 		{name: "TrustedFunc", patterns: []string{"go.uber.org/trustedfunc"}},
-		{name: "ErrorReturn", patterns: []string{"go.uber.org/errorreturn", "go.uber.org/errorreturn/inference"}},
-		{name: "Maps", patterns: []string{"go.uber.org/maps"}},
+		{name: "ErrorReturn", patterns: []string{"go.uber.org/errorreturn", "go.uber.org/errorreturn/inference", "go.uber.org/errorreturn/contract"}},
+		{name: "Maps", patterns: []string{"go.uber.org/maps", "go.uber.org/mapread"}},
+		{name: "DeadBranch", patterns: []string{"go.uber.org/deadbranch"}},
+		{name: "CgoFiles", patterns: []string{"go.uber.org/cgofiles"}},
+		{name: "LineDirectives", patterns: []string{"go.uber.org/linedirectives"}},
+		{name: "TemplComponents", patterns: []string{"go.uber.org/templcomponents"}},
+		{name: "DependencyInjection", patterns: []string{"go.uber.org/dependencyinjection"}},
+		{name: "Devirtualization", patterns: []string{"go.uber.org/devirtualization"}},
+		{name: "Mocks", patterns: []string{"go.uber.org/mocks", "go.uber.org/mocks/store/mocks"}},
+		{name: "DBScan", patterns: []string{"go.uber.org/dbscan"}},
+		{name: "GenericInstances", patterns: []string{"go.uber.org/genericinstances"}},
 		{name: "Slices", patterns: []string{"go.uber.org/slices", "go.uber.org/slices/inference"}},
 		{name: "Arrays", patterns: []string{"go.uber.org/arrays"}},
 		{name: "Channels", patterns: []string{"go.uber.org/channels"}},
 		{name: "GoQuirks", patterns: []string{"go.uber.org/goquirks"}},
 		{name: "GlobalVars", patterns: []string{"go.uber.org/globalvars"}},
 		{name: "DeepNil", patterns: []string{"go.uber.org/deepnil", "go.uber.org/deepnil/inference"}},
+		{name: "NonNilField", patterns: []string{"go.uber.org/nonnilfield", "go.uber.org/nonnilfield/inference"}},
 		{name: "NilableTypes", patterns: []string{"go.uber.org/nilabletypes"}},
 		{name: "HelloWorld", patterns: []string{"go.uber.org/helloworld"}},
 		{name: "MultiFilePackage", patterns: []string{"go.uber.org/multifilepackage", "go.uber.org/multifilepackage/firstpackage", "go.uber.org/multifilepackage/secondpackage"}},
 		{name: "MultipleAssignment", patterns: []string{"go.uber.org/multipleassignment"}},
 		{name: "AnnotationParse", patterns: []string{"go.uber.org/annotationparse"}},
+		{name: "AnnotationSyntax", patterns: []string{"go.uber.org/annotationsyntax"}},
 		{name: "NilCheck", patterns: []string{"go.uber.org/nilcheck"}},
+		{name: "Closures", patterns: []string{"go.uber.org/closures"}},
 		{name: "SimpleFlow", patterns: []string{"go.uber.org/simpleflow"}},
 		{name: "LoopFlow", patterns: []string{"go.uber.org/loopflow"}},
 		{name: "MethodImplementation", patterns: []string{"go.uber.org/methodimplementation", "go.uber.org/methodimplementation/mergedDependencies", "go.uber.org/methodimplementation/chainedDependencies", "go.uber.org/methodimplementation/multipackage", "go.uber.org/methodimplementation/embedding"}},
 		{name: "NamedReturn", patterns: []string{"go.uber.org/namedreturn"}},
 		{name: "IgnoreGenerated", patterns: []string{"go.uber.org/ignoregenerated"}},
+		{name: "GeneratedCode", patterns: []string{"go.uber.org/generatedcode"}},
+		{name: "TestFiles", patterns: []string{"go.uber.org/testfiles"}},
 		{name: "IgnorePackage", patterns: []string{"ignoredpkg1", "ignoredpkg2"}},
-		{name: "Receivers", patterns: []string{"go.uber.org/receivers", "go.uber.org/receivers/inference"}},
+		{name: "Receivers", patterns: []string{"go.uber.org/receivers", "go.uber.org/receivers/inference", "go.uber.org/receivers/namedtypes"}},
 		{name: "Generics", patterns: []string{"go.uber.org/generics"}},
 		{name: "FunctionContracts", patterns: []string{"go.uber.org/functioncontracts", "go.uber.org/functioncontracts/inference"}},
 		{name: "Constants", patterns: []string{"go.uber.org/consts"}},
 		{name: "ErrorMessage", patterns: []string{"go.uber.org/errormessage", "go.uber.org/errormessage/inference"}},
 		{name: "LoopRange", patterns: []string{"go.uber.org/looprange"}},
 		{name: "AbnormalFlow", patterns: []string{"go.uber.org/abnormalflow"}},
+		{name: "Callbacks", patterns: []string{"go.uber.org/callbacks"}},
+		{name: "CallbackModels", patterns: []string{"go.uber.org/callbackmodels"}},
 	}
 
 	for _, tt := range tests {
@@ -88,12 +114,12 @@ func TestNilAway(t *testing.T) {
 }
 
 func TestStructInit(t *testing.T) { //nolint:paralleltest
-	// We specifically do not set this test to be parallel since we need to enable the
-	// experimental support for struct initialization to test this feature.
-	err := config.Analyzer.Flags.Set(config.ExperimentalStructInitEnableFlag, "true")
+	// We specifically do not set this test to be parallel since we need to enable the struct
+	// initialization checking to test this feature.
+	err := config.Analyzer.Flags.Set(config.CheckStructInitFlag, config.CheckStructInitReport)
 	require.NoError(t, err)
 	defer func() {
-		err := config.Analyzer.Flags.Set(config.ExperimentalStructInitEnableFlag, "false")
+		err := config.Analyzer.Flags.Set(config.CheckStructInitFlag, config.CheckStructInitOff)
 		require.NoError(t, err)
 	}()
 
@@ -101,13 +127,41 @@ func TestStructInit(t *testing.T) { //nolint:paralleltest
 	analysistest.Run(t, testdata, Analyzer, "go.uber.org/structinit/funcreturnfields", "go.uber.org/structinit/local", "go.uber.org/structinit/global", "go.uber.org/structinit/paramfield", "go.uber.org/structinit/paramsideeffect", "go.uber.org/structinit/defaultfield")
 }
 
+func TestStructInitCount(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to enable the count mode
+	// of the struct initialization checking to test this feature.
+	err := config.Analyzer.Flags.Set(config.CheckStructInitFlag, config.CheckStructInitCount)
+	require.NoError(t, err)
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.CheckStructInitFlag, config.CheckStructInitOff)
+		require.NoError(t, err)
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, Analyzer, "go.uber.org/structinit/count")
+}
+
+func TestStructInitExperimentAlias(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to enable the struct
+	// initialization checking via the name of the experiment it graduated from.
+	err := config.Analyzer.Flags.Set(config.ExperimentalFlag, "struct-init")
+	require.NoError(t, err)
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.ExperimentalFlag, "")
+		require.NoError(t, err)
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, Analyzer, "go.uber.org/structinit/local")
+}
+
 func TestAnonymousFunction(t *testing.T) { //nolint:paralleltest
 	// We specifically do not set this test to be parallel since we need to enable the
 	// experimental support for anonymous function to test this feature.
-	err := config.Analyzer.Flags.Set(config.ExperimentalAnonymousFunctionFlag, "true")
+	err := config.Analyzer.Flags.Set(config.ExperimentalFlag, "anonymous-function")
 	require.NoError(t, err)
 	defer func() {
-		err := config.Analyzer.Flags.Set(config.ExperimentalAnonymousFunctionFlag, "false")
+		err := config.Analyzer.Flags.Set(config.ExperimentalFlag, "")
 		require.NoError(t, err)
 	}()
 
@@ -115,6 +169,599 @@ func TestAnonymousFunction(t *testing.T) { //nolint:paralleltest
 	analysistest.Run(t, testdata, Analyzer, "go.uber.org/anonymousfunction")
 }
 
+func TestGroupResults(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to enable the
+	// experimental heuristic for the results populated by groups of goroutines to test this feature.
+	err := config.Analyzer.Flags.Set(config.ExperimentalFlag, "group-results")
+	require.NoError(t, err)
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.ExperimentalFlag, "")
+		require.NoError(t, err)
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, Analyzer, "go.uber.org/groupresults")
+}
+
+func TestErrorOnNilableMapRead(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to enable the strict
+	// checking of the reads from maps to test this feature.
+	err := config.Analyzer.Flags.Set(config.ErrorOnNilableMapReadFlag, "true")
+	require.NoError(t, err)
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.ErrorOnNilableMapReadFlag, "false")
+		require.NoError(t, err)
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, Analyzer, "go.uber.org/mapread/strict")
+}
+
+func TestMaxTreeWidth(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to limit the width of the
+	// assertion trees to test this feature.
+	err := config.Analyzer.Flags.Set(config.MaxTreeWidthFlag, "2")
+	require.NoError(t, err)
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.MaxTreeWidthFlag, "0")
+		require.NoError(t, err)
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, Analyzer, "go.uber.org/treewidth")
+}
+
+func TestMemoryPressure(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to force the memory
+	// pressure (via a memory limit and a tiny threshold) to test the degraded mode, where the
+	// struct initialization checks enabled here are skipped.
+	limit := debug.SetMemoryLimit(1 << 40)
+	err := config.Analyzer.Flags.Set(config.MemoryPressureThresholdFlag, "0.000001")
+	require.NoError(t, err)
+	err = config.Analyzer.Flags.Set(config.CheckStructInitFlag, config.CheckStructInitReport)
+	require.NoError(t, err)
+	defer func() {
+		debug.SetMemoryLimit(limit)
+		err := config.Analyzer.Flags.Set(config.MemoryPressureThresholdFlag, strconv.FormatFloat(config.DefaultMemoryPressureThreshold, 'g', -1, 64))
+		require.NoError(t, err)
+		err = config.Analyzer.Flags.Set(config.CheckStructInitFlag, config.CheckStructInitOff)
+		require.NoError(t, err)
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, Analyzer, "go.uber.org/degraded")
+}
+
+func TestFixModeGuard(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to enable the fix mode
+	// to test this feature.
+	err := config.Analyzer.Flags.Set(config.FixModeFlag, config.FixModeGuard)
+	require.NoError(t, err)
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.FixModeFlag, "")
+		require.NoError(t, err)
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.RunWithSuggestedFixes(t, testdata, Analyzer, "go.uber.org/fixguard")
+}
+
+func TestFixModeAnnotate(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to enable the fix mode
+	// to test this feature.
+	err := config.Analyzer.Flags.Set(config.FixModeFlag, config.FixModeAnnotate)
+	require.NoError(t, err)
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.FixModeFlag, "")
+		require.NoError(t, err)
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.RunWithSuggestedFixes(t, testdata, Analyzer, "go.uber.org/annotate")
+}
+
+func TestSidecarAnnotations(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to set the sidecar
+	// annotation files to test this feature.
+	// We also disable the grouping of error messages since the nil sources from the annotations
+	// are otherwise considered the same.
+	testdata := analysistest.TestData()
+	err := config.Analyzer.Flags.Set(config.AnnotationFilesFlag, filepath.Join(testdata, "src", "go.uber.org", "sidecar", "annotations.txt"))
+	require.NoError(t, err)
+	err = config.Analyzer.Flags.Set(config.GroupErrorMessagesFlag, "false")
+	require.NoError(t, err)
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.AnnotationFilesFlag, "")
+		require.NoError(t, err)
+		err = config.Analyzer.Flags.Set(config.GroupErrorMessagesFlag, "true")
+		require.NoError(t, err)
+	}()
+
+	analysistest.Run(t, testdata, Analyzer, "go.uber.org/sidecar")
+}
+
+func TestPackageOverrides(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to set the package
+	// override files (and the flags they override) to test this feature.
+	testdata := analysistest.TestData()
+	err := config.Analyzer.Flags.Set(config.PackageOverridesFlag, filepath.Join(testdata, "src", "go.uber.org", "pkgoverrides", "overrides.txt"))
+	require.NoError(t, err)
+	err = config.Analyzer.Flags.Set(config.ErrorOnNilableMapReadFlag, "true")
+	require.NoError(t, err)
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.PackageOverridesFlag, "")
+		require.NoError(t, err)
+		err = config.Analyzer.Flags.Set(config.ErrorOnNilableMapReadFlag, "false")
+		require.NoError(t, err)
+	}()
+
+	analysistest.Run(t, testdata, Analyzer, "go.uber.org/pkgoverrides/...")
+}
+
+func TestStubPackages(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to set the stub
+	// directories to test this feature.
+	testdata := analysistest.TestData()
+	err := config.Analyzer.Flags.Set(config.StubDirsFlag, filepath.Join(testdata, "src", "go.uber.org", "stubpkgs", "stubs"))
+	require.NoError(t, err)
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.StubDirsFlag, "")
+		require.NoError(t, err)
+	}()
+
+	analysistest.Run(t, testdata, Analyzer, "go.uber.org/stubpkgs")
+}
+
+func TestImportFacts(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to set the directory of
+	// the imported facts to test this feature.
+	testdata := analysistest.TestData()
+	err := config.Analyzer.Flags.Set(config.ImportFactsDirFlag, filepath.Join(testdata, "src", "go.uber.org", "importfacts", "facts"))
+	require.NoError(t, err)
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.ImportFactsDirFlag, "")
+		require.NoError(t, err)
+	}()
+
+	analysistest.Run(t, testdata, Analyzer, "go.uber.org/importfacts")
+}
+
+func TestDebugDeps(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to enable the debug-deps
+	// mode to test this feature.
+	err := config.Analyzer.Flags.Set(config.DebugDepsFlag, "true")
+	require.NoError(t, err)
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.DebugDepsFlag, "false")
+		require.NoError(t, err)
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, Analyzer, "go.uber.org/debugdeps", "go.uber.org/debugdeps/noinfer")
+}
+
+func TestExplainProvenance(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to enable the provenance
+	// explanations to test this feature.
+	err := config.Analyzer.Flags.Set(config.ExplainProvenanceFlag, "true")
+	require.NoError(t, err)
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.ExplainProvenanceFlag, "false")
+		require.NoError(t, err)
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, Analyzer, "go.uber.org/provenance")
+}
+
+func TestReflectEscape(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to enable the
+	// conservative reflect-escape mode to test this feature.
+	err := config.Analyzer.Flags.Set(config.ReflectEscapeFlag, "true")
+	require.NoError(t, err)
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.ReflectEscapeFlag, "false")
+		require.NoError(t, err)
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, Analyzer, "go.uber.org/reflectescape")
+}
+
+func TestExcludeMocks(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to exclude the mocks from
+	// reporting to test this feature.
+	err := config.Analyzer.Flags.Set(config.ExcludeMocksFlag, "true")
+	require.NoError(t, err)
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.ExcludeMocksFlag, "false")
+		require.NoError(t, err)
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, Analyzer, "go.uber.org/mockreporting", "go.uber.org/mockreporting/mocks")
+}
+
+func TestModelPacks(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to enable the model packs
+	// to test this feature.
+	err := config.Analyzer.Flags.Set(config.ModelPacksFlag, config.ModelPackK8s)
+	require.NoError(t, err)
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.ModelPacksFlag, "")
+		require.NoError(t, err)
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, Analyzer, "go.uber.org/modelpacks")
+}
+
+func TestModelPackFiles(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to set the model pack
+	// files to test this feature.
+	testdata := analysistest.TestData()
+	err := config.Analyzer.Flags.Set(config.ModelPackFilesFlag, filepath.Join(testdata, "src", "go.uber.org", "modelpackfiles", "models.modelpack"))
+	require.NoError(t, err)
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.ModelPackFilesFlag, "")
+		require.NoError(t, err)
+	}()
+
+	analysistest.Run(t, testdata, Analyzer, "go.uber.org/modelpackfiles")
+}
+
+func TestDisableLineDirectives(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to disable adjusting the
+	// positions by the "//line" directives to test this feature.
+	err := config.Analyzer.Flags.Set(config.DisableLineDirectivesFlag, "true")
+	require.NoError(t, err)
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.DisableLineDirectivesFlag, "false")
+		require.NoError(t, err)
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, Analyzer, "go.uber.org/linedirectives/disabled")
+}
+
+func TestReportAt(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to report the errors at
+	// both the sources and the sinks to test this feature.
+	err := config.Analyzer.Flags.Set(config.ReportAtFlag, config.ReportAtBoth)
+	require.NoError(t, err)
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.ReportAtFlag, config.ReportAtSink)
+		require.NoError(t, err)
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, Analyzer, "go.uber.org/reportat")
+}
+
+func TestReportPositionPolicy(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to set the position policy
+	// to test this feature.
+	tests := []struct {
+		policy  string
+		pattern string
+	}{
+		{policy: config.ReportPositionModule, pattern: "go.uber.org/positionpolicy"},
+		{policy: config.ReportPositionProduction, pattern: "go.uber.org/positionpolicy/production"},
+	}
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.ReportPositionPolicyFlag, config.ReportPositionConsumption)
+		require.NoError(t, err)
+	}()
+
+	testdata := analysistest.TestData()
+	for _, tt := range tests {
+		err := config.Analyzer.Flags.Set(config.ReportPositionPolicyFlag, tt.policy)
+		require.NoError(t, err)
+		analysistest.Run(t, testdata, Analyzer, tt.pattern)
+	}
+}
+
+func TestDefaultNilability(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to set the default
+	// nilability to test this feature.
+	tests := []struct {
+		defaultNilability string
+		pattern           string
+	}{
+		{defaultNilability: config.DefaultNilabilityPessimistic, pattern: "go.uber.org/defaultnilability"},
+		{defaultNilability: config.DefaultNilabilityPessimisticExports, pattern: "go.uber.org/defaultnilability/exports"},
+	}
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.DefaultNilabilityFlag, config.DefaultNilabilityOptimistic)
+		require.NoError(t, err)
+	}()
+
+	testdata := analysistest.TestData()
+	for _, tt := range tests {
+		err := config.Analyzer.Flags.Set(config.DefaultNilabilityFlag, tt.defaultNilability)
+		require.NoError(t, err)
+		analysistest.Run(t, testdata, Analyzer, tt.pattern)
+	}
+}
+
+func TestStrictExports(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to set the strict mode to
+	// test this feature.
+	err := config.Analyzer.Flags.Set(config.StrictExportsFlag, "true")
+	require.NoError(t, err)
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.StrictExportsFlag, "false")
+		require.NoError(t, err)
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, Analyzer, "go.uber.org/strictexports")
+}
+
+func TestInferenceMode(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to set the inference mode
+	// to test this feature.
+	err := config.Analyzer.Flags.Set(config.InferenceModeFlag, config.InferenceModeSinglePackage)
+	require.NoError(t, err)
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.InferenceModeFlag, config.InferenceModeFull)
+		require.NoError(t, err)
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, Analyzer, "go.uber.org/inferencemode", "go.uber.org/inferencemode/upstream")
+}
+
+func TestReportRedundantChecks(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to set the flag to test
+	// this feature.
+	err := config.Analyzer.Flags.Set(config.ReportRedundantChecksFlag, "true")
+	require.NoError(t, err)
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.ReportRedundantChecksFlag, "false")
+		require.NoError(t, err)
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, Analyzer, "go.uber.org/redundantcheck")
+}
+
+func TestRecoveredPanics(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to set the policy for the
+	// recovered panics to test this feature.
+	tests := []struct {
+		recoveredPanics string
+		pattern         string
+	}{
+		{recoveredPanics: config.RecoveredPanicsDowngrade, pattern: "go.uber.org/recoveredpanics"},
+		{recoveredPanics: config.RecoveredPanicsSuppress, pattern: "go.uber.org/recoveredpanics/suppress"},
+	}
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.RecoveredPanicsFlag, config.RecoveredPanicsReport)
+		require.NoError(t, err)
+	}()
+
+	testdata := analysistest.TestData()
+	for _, tt := range tests {
+		err := config.Analyzer.Flags.Set(config.RecoveredPanicsFlag, tt.recoveredPanics)
+		require.NoError(t, err)
+		analysistest.Run(t, testdata, Analyzer, tt.pattern)
+	}
+}
+
+func TestRequireSourceInScope(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to exclude the package of
+	// the nil sources from the analysis scope and set the policy for them to test this feature.
+	testdata := analysistest.TestData()
+	flags := map[string]string{
+		config.ExcludePkgsFlag:    "ignoredpkg1,ignoredpkg2,go.uber.org/sourcescope/excluded",
+		config.ModelPackFilesFlag: filepath.Join(testdata, "src", "go.uber.org", "sourcescope", "models.modelpack"),
+	}
+	defaults := map[string]string{
+		config.ExcludePkgsFlag:          "ignoredpkg1,ignoredpkg2",
+		config.ModelPackFilesFlag:       "",
+		config.RequireSourceInScopeFlag: config.SourceScopeReport,
+	}
+	defer func() {
+		for flag, value := range defaults {
+			err := config.Analyzer.Flags.Set(flag, value)
+			require.NoError(t, err)
+		}
+	}()
+	for flag, value := range flags {
+		err := config.Analyzer.Flags.Set(flag, value)
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		requireSourceInScope string
+		pattern              string
+	}{
+		{requireSourceInScope: config.SourceScopeAnnotate, pattern: "go.uber.org/sourcescope"},
+		{requireSourceInScope: config.SourceScopeSuppress, pattern: "go.uber.org/sourcescope/suppress"},
+	}
+	for _, tt := range tests {
+		err := config.Analyzer.Flags.Set(config.RequireSourceInScopeFlag, tt.requireSourceInScope)
+		require.NoError(t, err)
+		analysistest.Run(t, testdata, Analyzer, tt.pattern)
+	}
+}
+
+func TestErrorsInFiles(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to set the file prefixes
+	// for error suppression to test this feature.
+	testdata := analysistest.TestData()
+	dir := filepath.Join(testdata, "src", "go.uber.org", "errorsinfiles")
+	flags := map[string]string{
+		config.IncludeErrorsInFilesFlag: dir,
+		config.ExcludeErrorsInFilesFlag: filepath.Join(dir, "suppressed.go"),
+	}
+	defer func() {
+		for flag := range flags {
+			err := config.Analyzer.Flags.Set(flag, "")
+			require.NoError(t, err)
+		}
+	}()
+	for flag, value := range flags {
+		err := config.Analyzer.Flags.Set(flag, value)
+		require.NoError(t, err)
+	}
+
+	analysistest.Run(t, testdata, Analyzer, "go.uber.org/errorsinfiles")
+}
+
+// diagnosticRecorder is an analysistest.Testing that records the mismatches against the "want"
+// comments instead of failing the test, for checking the diagnostics reported in the files of
+// other packages (which cannot be matched by analysistest) manually.
+type diagnosticRecorder struct {
+	errors []string
+}
+
+func (r *diagnosticRecorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestCrossPackageDedup(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to toggle the
+	// deduplication of errors across packages to test this feature.
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.CrossPackageDedupFlag, "false")
+		require.NoError(t, err)
+	}()
+
+	// The conflict on the upstream field is fully determined by the facts of nilassign and deref,
+	// hence it is observed by both first and second (which imports first). It is reported at the
+	// dereference in deref, which analysistest cannot match since it is in another package.
+	testdata := analysistest.TestData()
+	for enabled, want := range map[string]map[string]int{
+		"true":  {"go.uber.org/crosspackagededup/first": 1},
+		"false": {"go.uber.org/crosspackagededup/first": 1, "go.uber.org/crosspackagededup/second": 1},
+	} {
+		err := config.Analyzer.Flags.Set(config.CrossPackageDedupFlag, enabled)
+		require.NoError(t, err)
+
+		recorder := &diagnosticRecorder{}
+		results := analysistest.Run(recorder, testdata, Analyzer, "go.uber.org/crosspackagededup/...")
+		got := make(map[string]int)
+		for _, r := range results {
+			require.NoError(t, r.Err)
+			if len(r.Diagnostics) > 0 {
+				got[r.Pass.Pkg.Path()] = len(r.Diagnostics)
+			}
+		}
+		require.Equal(t, want, got, "enabled: %s", enabled)
+		require.Len(t, recorder.errors, len(want), "enabled: %s", enabled)
+	}
+}
+
+func TestCrossPackageDedupDownstreamOnly(t *testing.T) {
+	t.Parallel()
+
+	// Only second is analyzed (and reported), while first is analyzed as its dependency for the
+	// facts only. The conflict must still be reported by second since the deduplication is not
+	// enabled by default.
+	testdata := analysistest.TestData()
+	recorder := &diagnosticRecorder{}
+	results := analysistest.Run(recorder, testdata, Analyzer, "go.uber.org/crosspackagededup/second")
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Err)
+	require.Len(t, results[0].Diagnostics, 1)
+	require.Contains(t, results[0].Pass.Fset.Position(results[0].Diagnostics[0].Pos).String(), "deref.go:21:10")
+	require.Len(t, recorder.errors, 1)
+}
+
+func TestDisableParseCache(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to disable the parse cache
+	// to test that it does not change the diagnostics.
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.DisableParseCacheFlag, "false")
+		require.NoError(t, err)
+	}()
+
+	testdata := analysistest.TestData()
+	patterns := []string{"go.uber.org/inference", "go.uber.org/loopflow", "go.uber.org/looprange", "go.uber.org/deepnil", "go.uber.org/slices", "go.uber.org/nilcheck"}
+	diagnostics := make(map[string][]string)
+	for _, disabled := range []string{"false", "true"} {
+		err := config.Analyzer.Flags.Set(config.DisableParseCacheFlag, disabled)
+		require.NoError(t, err)
+
+		for _, r := range analysistest.Run(t, testdata, Analyzer, patterns...) {
+			require.NoError(t, r.Err)
+			for _, d := range r.Diagnostics {
+				diagnostics[disabled] = append(diagnostics[disabled], fmt.Sprintf("%s: %s", r.Pass.Fset.Position(d.Pos), d.Message))
+			}
+		}
+	}
+	require.NotEmpty(t, diagnostics["false"])
+	require.Equal(t, diagnostics["false"], diagnostics["true"])
+}
+
+func TestMessageTemplate(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to set the message
+	// template to test this feature.
+	err := config.Analyzer.Flags.Set(config.MessageTemplateFlag, "short")
+	require.NoError(t, err)
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.MessageTemplateFlag, "")
+		require.NoError(t, err)
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, Analyzer, "go.uber.org/messagetemplate")
+}
+
+func TestPathFormat(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to set the path format to
+	// test this feature.
+	err := config.Analyzer.Flags.Set(config.PathFormatFlag, "https://example.com/blob/abc123/{{.Path}}#L{{.Line}}")
+	require.NoError(t, err)
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.PathFormatFlag, config.PathFormatShort)
+		require.NoError(t, err)
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, Analyzer, "go.uber.org/pathformat")
+}
+
+func TestDebugDump(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to set the debug dump
+	// flags to test this feature.
+	dir := t.TempDir()
+	err := config.Analyzer.Flags.Set(config.DebugDumpDirFlag, dir)
+	require.NoError(t, err)
+	err = config.Analyzer.Flags.Set(config.DebugDumpFuncsFlag, `debugdump\.Dumped$`)
+	require.NoError(t, err)
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.DebugDumpDirFlag, "")
+		require.NoError(t, err)
+		err = config.Analyzer.Flags.Set(config.DebugDumpFuncsFlag, "")
+		require.NoError(t, err)
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, Analyzer, "go.uber.org/debugdump")
+
+	// Only the selected function is dumped.
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	for _, entry := range entries {
+		require.Contains(t, entry.Name(), "go.uber.org_debugdump.Dumped.")
+	}
+
+	cfg, err := os.ReadFile(filepath.Join(dir, "go.uber.org_debugdump.Dumped.cfg.dot"))
+	require.NoError(t, err)
+	require.Contains(t, string(cfg), "digraph cfg")
+	require.Contains(t, string(cfg), `label="true"`)
+	round, err := os.ReadFile(filepath.Join(dir, "go.uber.org_debugdump.Dumped.round1.dot"))
+	require.NoError(t, err)
+	require.Contains(t, string(round), "subgraph cluster_b")
+	triggers, err := os.ReadFile(filepath.Join(dir, "go.uber.org_debugdump.Dumped.triggers.dot"))
+	require.NoError(t, err)
+	require.Contains(t, string(triggers), "->")
+}
+
 func TestPrettyPrint(t *testing.T) { //nolint:paralleltest
 	// We specifically do not set this test to be parallel such that this test is run separately
 	// from the parallel tests. This makes it possible to set the pretty-print flag to true for
@@ -130,6 +777,42 @@ func TestPrettyPrint(t *testing.T) { //nolint:paralleltest
 	analysistest.Run(t, testdata, Analyzer, "prettyprint")
 }
 
+func TestIncludeGenerated(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to enable reporting the
+	// errors in generated files to test this feature.
+	err := config.Analyzer.Flags.Set(config.IncludeGeneratedFlag, "true")
+	require.NoError(t, err)
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.IncludeGeneratedFlag, "false")
+		require.NoError(t, err)
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, Analyzer, "go.uber.org/generatedcode/included")
+}
+
+func TestTestFiles(t *testing.T) { //nolint:paralleltest
+	// We specifically do not set this test to be parallel since we need to toggle the flags for
+	// reporting the errors in test files to test this feature.
+	testdata := analysistest.TestData()
+
+	for flag, pkg := range map[string]string{
+		config.ExcludeTestsFlag: "go.uber.org/testfiles/excludetests",
+		config.TestsOnlyFlag:    "go.uber.org/testfiles/testsonly",
+	} {
+		func() {
+			err := config.Analyzer.Flags.Set(flag, "true")
+			require.NoError(t, err)
+			defer func() {
+				err := config.Analyzer.Flags.Set(flag, "false")
+				require.NoError(t, err)
+			}()
+
+			analysistest.Run(t, testdata, Analyzer, pkg)
+		}()
+	}
+}
+
 func TestGroupErrorMessages(t *testing.T) { //nolint:paralleltest
 	// We specifically do not set this test to be parallel such that this test is run separately
 	// from the parallel tests. This makes it possible to test the group error messages flag independently
@@ -153,6 +836,94 @@ func TestGroupErrorMessages(t *testing.T) { //nolint:paralleltest
 	}()
 }
 
+//nolint:paralleltest
+func TestAssignmentFlowsFull(t *testing.T) {
+	err := config.Analyzer.Flags.Set(config.AssignmentFlowsFlag, config.AssignmentFlowsFull)
+	require.NoError(t, err)
+	defer func() {
+		err := config.Analyzer.Flags.Set(config.AssignmentFlowsFlag, config.AssignmentFlowsSummary)
+		require.NoError(t, err)
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, Analyzer, "go.uber.org/assignmentflows")
+}
+
+// auditTrigger is the plugin trigger of the audit plugin registered in TestTriggerPlugin.
+type auditTrigger struct {
+	lookup bool
+}
+
+func (a *auditTrigger) Equals(other annotation.PluginTrigger) bool {
+	o, ok := other.(*auditTrigger)
+	return ok && *a == *o
+}
+
+func (a *auditTrigger) Prestring() annotation.Prestring {
+	return auditPrestring{Lookup: a.lookup}
+}
+
+// auditPrestring is the Prestring of auditTrigger.
+type auditPrestring struct {
+	Lookup bool
+}
+
+func (a auditPrestring) String() string {
+	if a.Lookup {
+		return "looked up from the audit sink"
+	}
+	return "passed to the audit sink"
+}
+
+// auditCallee returns the name of the function of the audit stub package called by the call, or
+// "" if it does not call one.
+func auditCallee(pass *analysis.Pass, call *ast.CallExpr) string {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "go.uber.org/triggerplugin/audit" {
+		return ""
+	}
+	return fn.Name()
+}
+
+//nolint:paralleltest
+func TestTriggerPlugin(t *testing.T) {
+	unregister := hook.RegisterTriggerPlugin(hook.TriggerPlugin{
+		Name:       "audit",
+		Prestrings: []annotation.Prestring{auditPrestring{}},
+		Consumers: func(pass *analysis.Pass, call *ast.CallExpr) []*annotation.ConsumeTrigger {
+			if auditCallee(pass, call) != "Record" || len(call.Args) != 1 {
+				return nil
+			}
+			return []*annotation.ConsumeTrigger{{
+				Annotation: &annotation.PluginConsumer{
+					ConsumingAnnotationTrigger: &annotation.ConsumeTriggerTautology{},
+					Plugin:                     &auditTrigger{},
+				},
+				Expr:   call.Args[0],
+				Guards: util.NoGuards(),
+			}}
+		},
+		Producer: func(pass *analysis.Pass, call *ast.CallExpr) *annotation.ProduceTrigger {
+			if auditCallee(pass, call) != "Lookup" {
+				return nil
+			}
+			return &annotation.ProduceTrigger{
+				Annotation: &annotation.PluginProducer{
+					ProducingAnnotationTrigger: &annotation.ProduceTriggerTautology{},
+					Plugin:                     &auditTrigger{lookup: true},
+				},
+				Expr: call,
+			}
+		},
+	})
+	t.Cleanup(unregister)
+	// The plugins are identified by their names, hence the duplicates are rejected.
+	require.Panics(t, func() { hook.RegisterTriggerPlugin(hook.TriggerPlugin{Name: "audit"}) })
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, Analyzer, "go.uber.org/triggerplugin")
+}
+
 func TestMain(m *testing.M) {
 	flags := map[string]string{
 		// Pretty print should be turned off for easier error message matching in test files.
@@ -0,0 +1,253 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package redundantcheck implements a complementary analyzer that reports the nil checks on the
+// values that are always nonnil (e.g., checking `x != nil` right after `x := &T{}`), such that
+// the noise can be cleaned up. It shares the inference results of the accumulation analyzer to
+// also recognize the sites that NilAway has inferred to be nonnil, and it is off by default (see
+// config.Config.ReportRedundantChecks).
+package redundantcheck
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+
+	"go.uber.org/nilaway/accumulation"
+	"go.uber.org/nilaway/annotation"
+	"go.uber.org/nilaway/config"
+	"go.uber.org/nilaway/inference"
+	"go.uber.org/nilaway/util"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/ssa"
+)
+
+const _doc = "Report the nil checks on the values that are always nonnil, either intrinsically (e.g.," +
+	" newly allocated values) or by the inference results of NilAway, as redundant"
+
+// Analyzer reports the redundant nil checks in this package. It returns the diagnostics for the
+// top-level analyzer to report.
+var Analyzer = &analysis.Analyzer{
+	Name:       "nilaway_redundant_check_analyzer",
+	Doc:        _doc,
+	Run:        run,
+	Requires:   []*analysis.Analyzer{config.Analyzer, accumulation.Analyzer, buildssa.Analyzer},
+	ResultType: reflect.TypeOf(([]analysis.Diagnostic)(nil)),
+	// Packages with type errors are not checked (since the buildssa analyzer does not run on them),
+	// but the analyzer still runs such that the top-level analyzer can report the other diagnostics.
+	RunDespiteErrors: true,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	conf := pass.ResultOf[config.Analyzer].(*config.Config)
+	if !conf.ReportRedundantChecks || !conf.IsPkgInScope(pass.Pkg) {
+		// Must return a typed nil since the driver is using reflection to retrieve the result.
+		return ([]analysis.Diagnostic)(nil), nil
+	}
+	// The buildssa analyzer does not run on packages with type errors, which are not checked.
+	ssaResult, ok := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
+	if !ok {
+		return ([]analysis.Diagnostic)(nil), nil
+	}
+
+	c := &checker{
+		inferredMap: pass.ResultOf[accumulation.Analyzer].(*accumulation.Result).InferredMap,
+		checks:      make(map[token.Pos]*ast.BinaryExpr),
+	}
+	reported := make(map[*token.File]bool)
+	for _, file := range pass.Files {
+		tokFile := pass.Fset.File(file.Pos())
+		if !conf.IsFileInScope(file) || !conf.IsFileReported(file) || !conf.ErrorFilter.IsReported(tokFile.Name()) {
+			continue
+		}
+		reported[tokFile] = true
+		ast.Inspect(file, func(n ast.Node) bool {
+			if expr, ok := n.(*ast.BinaryExpr); ok && (expr.Op == token.EQL || expr.Op == token.NEQ) {
+				c.checks[expr.OpPos] = expr
+			}
+			return true
+		})
+	}
+
+	var diagnostics []analysis.Diagnostic
+	for _, fn := range ssaResult.SrcFuncs {
+		if fn == nil || !reported[pass.Fset.File(fn.Pos())] {
+			continue
+		}
+		for _, block := range fn.Blocks {
+			if d, ok := c.check(block); ok {
+				diagnostics = append(diagnostics, d)
+			}
+		}
+	}
+	return diagnostics, nil
+}
+
+// checker checks the nil checks in the SSA form of the functions against the values known to be
+// always nonnil.
+type checker struct {
+	// inferredMap is the inferred map of this package, which is nil if the package is not analyzed
+	// by the accumulation analyzer.
+	inferredMap *inference.InferredMap
+	// checks maps the operator positions of the (in)equality expressions in the source to the
+	// expressions, such that the redundant nil checks can be reported in terms of the source code.
+	checks map[token.Pos]*ast.BinaryExpr
+}
+
+// check returns the diagnostic for the redundant nil check that terminates the block, if any.
+func (c *checker) check(block *ssa.BasicBlock) (analysis.Diagnostic, bool) {
+	if len(block.Instrs) == 0 {
+		return analysis.Diagnostic{}, false
+	}
+	ifInstr, ok := block.Instrs[len(block.Instrs)-1].(*ssa.If)
+	if !ok {
+		return analysis.Diagnostic{}, false
+	}
+	cond, ok := ifInstr.Cond.(*ssa.BinOp)
+	if !ok || (cond.Op != token.EQL && cond.Op != token.NEQ) {
+		return analysis.Diagnostic{}, false
+	}
+	expr, ok := c.checks[cond.Pos()]
+	if !ok {
+		return analysis.Diagnostic{}, false
+	}
+
+	// Find the operand that is checked against nil, the order of the operands is preserved in the
+	// SSA form.
+	var value ssa.Value
+	var checked ast.Expr
+	switch {
+	case isNilConst(cond.Y):
+		value, checked = cond.X, expr.X
+	case isNilConst(cond.X):
+		value, checked = cond.Y, expr.Y
+	default:
+		return analysis.Diagnostic{}, false
+	}
+
+	reason := c.nonnilReason(value, make(map[*ssa.Phi]bool))
+	if reason == "" {
+		return analysis.Diagnostic{}, false
+	}
+	return analysis.Diagnostic{
+		Pos:     expr.Pos(),
+		End:     expr.End(),
+		Message: fmt.Sprintf("redundant nil check: `%s` is always nonnil since %s", types.ExprString(checked), reason),
+	}, true
+}
+
+// nonnilReason returns the reason why the value is always nonnil, or an empty string if the value
+// may be nil. The visited phis are tracked to terminate on loops.
+func (c *checker) nonnilReason(value ssa.Value, visited map[*ssa.Phi]bool) string {
+	switch v := value.(type) {
+	case *ssa.Alloc, *ssa.MakeMap, *ssa.MakeChan, *ssa.MakeSlice:
+		return "it is newly allocated"
+	case *ssa.MakeClosure, *ssa.Function:
+		return "it is a function value"
+	case *ssa.FieldAddr, *ssa.IndexAddr, *ssa.Global:
+		return "it is the address of a variable"
+	case *ssa.Phi:
+		// A value merged from different paths is nonnil only if it is nonnil on all the paths.
+		if visited[v] {
+			return ""
+		}
+		visited[v] = true
+		reason := ""
+		for _, edge := range v.Edges {
+			r := c.nonnilReason(edge, visited)
+			if r == "" {
+				return ""
+			}
+			if reason == "" {
+				reason = r
+			} else if reason != r {
+				reason = "it is nonnil on all paths"
+			}
+		}
+		return reason
+	case *ssa.Parameter:
+		return c.paramReason(v)
+	case *ssa.Call:
+		return c.resultReason(v.Call, 0)
+	case *ssa.Extract:
+		if call, ok := v.Tuple.(*ssa.Call); ok {
+			return c.resultReason(call.Call, v.Index)
+		}
+	}
+	return ""
+}
+
+// paramReason returns the reason why the parameter is always nonnil if NilAway has inferred its
+// site to be nonnil, or an empty string otherwise.
+func (c *checker) paramReason(param *ssa.Parameter) string {
+	funcObj, ok := param.Parent().Object().(*types.Func)
+	if !ok {
+		return ""
+	}
+	params := funcObj.Type().(*types.Signature).Params()
+	for i := 0; i < params.Len(); i++ {
+		if params.At(i) != param.Object() {
+			continue
+		}
+		// The elements of a variadic parameter share the same site, which does not tell the
+		// nilability of the slice itself.
+		if funcObj.Type().(*types.Signature).Variadic() && i == params.Len()-1 {
+			return ""
+		}
+		if c.isInferredNonnil(annotation.ParamKeyFromArgNum(funcObj, i)) {
+			return fmt.Sprintf("parameter `%s` is inferred to be nonnil", param.Name())
+		}
+		return ""
+	}
+	return ""
+}
+
+// resultReason returns the reason why the result at the index of the static call is always
+// nonnil if NilAway has inferred its site to be nonnil, or an empty string otherwise. The results
+// of the error-returning and ok-returning functions are skipped since they are only nonnil when
+// the error is nil or the `ok` is true.
+func (c *checker) resultReason(call ssa.CallCommon, index int) string {
+	callee := call.StaticCallee()
+	if callee == nil {
+		return ""
+	}
+	funcObj, ok := callee.Object().(*types.Func)
+	if !ok || util.FuncIsErrReturning(funcObj) || util.FuncIsOkReturning(funcObj) {
+		return ""
+	}
+	if c.isInferredNonnil(annotation.RetKeyFromRetNum(funcObj, index)) {
+		return fmt.Sprintf("result %d of `%s` is inferred to be nonnil", index, funcObj.Name())
+	}
+	return ""
+}
+
+// isInferredNonnil returns true iff the (shallow) site of the key has been inferred to be nonnil.
+// The sites determined by the syntactic annotations only are not considered, since the
+// annotations are not verified for the values coming from outside the analysis.
+func (c *checker) isInferredNonnil(key annotation.Key) bool {
+	if c.inferredMap == nil {
+		return false
+	}
+	nilable, ok := c.inferredMap.InferredNilability(key, false /* isDeep */)
+	return ok && !nilable
+}
+
+// isNilConst returns true iff the value is the constant nil.
+func isNilConst(value ssa.Value) bool {
+	con, ok := value.(*ssa.Const)
+	return ok && con.IsNil()
+}
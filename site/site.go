@@ -0,0 +1,142 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package site provides stable, symbolic identifiers of the annotation sites of NilAway (e.g.,
+// parameter i of function F, result j of method T.M, or field f of type S), such that the tooling
+// built around NilAway (e.g., suppression dashboards or annotation generators) can refer to the
+// sites without depending on the internal types of the analysis. The identifiers only depend on
+// the names of the declarations, and are comparable with ==.
+//
+// The text form of a Site follows the sidecar annotation files (see config.SidecarAnnotation):
+//
+//	<package path> <object path> <kind> [<index or name>]
+//
+// For example:
+//
+//	example.com/client NewClient param 0
+//	example.com/client Client.Do result 1
+//	example.com/client Client.Do receiver
+//	example.com/client Config field Timeout
+//	example.com/client DefaultClient global
+package site
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Kind is the kind of an annotation site.
+type Kind string
+
+const (
+	// KindParam is the kind of the parameters of the functions and methods.
+	KindParam Kind = "param"
+	// KindResult is the kind of the results of the functions and methods.
+	KindResult Kind = "result"
+	// KindReceiver is the kind of the receivers of the methods.
+	KindReceiver Kind = "receiver"
+	// KindField is the kind of the fields of the named struct types.
+	KindField Kind = "field"
+	// KindGlobal is the kind of the package-level variables.
+	KindGlobal Kind = "global"
+)
+
+// Site identifies an annotation site.
+type Site struct {
+	// Kind is the kind of the site.
+	Kind Kind
+	// PkgPath is the path of the package declaring the site.
+	PkgPath string
+	// ObjectPath is the path of the declaration in the package containing the site: the name of
+	// the function, "<type name>.<method name>" for the methods, the name of the struct type for
+	// the fields, or the name of the variable for the globals.
+	ObjectPath string
+	// Index is the index of the parameter (or the result), 0 for the other kinds.
+	Index int
+	// Field is the name of the field, empty for the other kinds.
+	Field string
+}
+
+// Param returns the site of the i-th parameter of the function (or the method "<type>.<method>").
+func Param(pkgPath, funcPath string, i int) Site {
+	return Site{Kind: KindParam, PkgPath: pkgPath, ObjectPath: funcPath, Index: i}
+}
+
+// Result returns the site of the i-th result of the function (or the method "<type>.<method>").
+func Result(pkgPath, funcPath string, i int) Site {
+	return Site{Kind: KindResult, PkgPath: pkgPath, ObjectPath: funcPath, Index: i}
+}
+
+// Receiver returns the site of the receiver of the method "<type>.<method>".
+func Receiver(pkgPath, methodPath string) Site {
+	return Site{Kind: KindReceiver, PkgPath: pkgPath, ObjectPath: methodPath}
+}
+
+// Field returns the site of the field of the named struct type.
+func Field(pkgPath, typeName, field string) Site {
+	return Site{Kind: KindField, PkgPath: pkgPath, ObjectPath: typeName, Field: field}
+}
+
+// Global returns the site of the package-level variable.
+func Global(pkgPath, name string) Site {
+	return Site{Kind: KindGlobal, PkgPath: pkgPath, ObjectPath: name}
+}
+
+// String returns the text form of the site, which is parsed back by Parse.
+func (s Site) String() string {
+	prefix := s.PkgPath + " " + s.ObjectPath + " " + string(s.Kind)
+	switch s.Kind {
+	case KindParam, KindResult:
+		return prefix + " " + strconv.Itoa(s.Index)
+	case KindField:
+		return prefix + " " + s.Field
+	default:
+		return prefix
+	}
+}
+
+// Parse parses the text form of a site (see String).
+func Parse(text string) (Site, error) {
+	fields := strings.Fields(text)
+	if len(fields) < 3 {
+		return Site{}, fmt.Errorf("expect \"<package path> <object path> <kind> [<index or name>]\", got %q", text)
+	}
+	pkgPath, objectPath, kind := fields[0], fields[1], Kind(fields[2])
+	args := fields[3:]
+
+	switch kind {
+	case KindParam, KindResult:
+		if len(args) != 1 {
+			return Site{}, fmt.Errorf("expect an index for the %s site, got %q", kind, text)
+		}
+		i, err := strconv.Atoi(args[0])
+		if err != nil || i < 0 {
+			return Site{}, fmt.Errorf("invalid index %q for the %s site", args[0], kind)
+		}
+		return Site{Kind: kind, PkgPath: pkgPath, ObjectPath: objectPath, Index: i}, nil
+	case KindField:
+		if len(args) != 1 {
+			return Site{}, fmt.Errorf("expect a field name for the field site, got %q", text)
+		}
+		return Field(pkgPath, objectPath, args[0]), nil
+	case KindReceiver, KindGlobal:
+		if len(args) != 0 {
+			return Site{}, fmt.Errorf("unexpected %q for the %s site", strings.Join(args, " "), kind)
+		}
+		return Site{Kind: kind, PkgPath: pkgPath, ObjectPath: objectPath}, nil
+	default:
+		return Site{}, fmt.Errorf("unknown site kind %q", kind)
+	}
+}
@@ -0,0 +1,60 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		site Site
+		want string
+	}{
+		{Param("example.com/client", "NewClient", 0), "example.com/client NewClient param 0"},
+		{Result("example.com/client", "Client.Do", 1), "example.com/client Client.Do result 1"},
+		{Receiver("example.com/client", "Client.Do"), "example.com/client Client.Do receiver"},
+		{Field("example.com/client", "Config", "Timeout"), "example.com/client Config field Timeout"},
+		{Global("example.com/client", "DefaultClient"), "example.com/client DefaultClient global"},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, tt.site.String())
+		parsed, err := Parse(tt.want)
+		require.NoError(t, err)
+		require.Equal(t, tt.site, parsed)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, text := range []string{
+		"",
+		"example.com/client NewClient",
+		"example.com/client NewClient param",
+		"example.com/client NewClient param x",
+		"example.com/client NewClient result -1",
+		"example.com/client Config field",
+		"example.com/client Client.Do receiver 0",
+		"example.com/client NewClient local x",
+	} {
+		_, err := Parse(text)
+		require.Error(t, err, text)
+	}
+}
@@ -0,0 +1,162 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package suppression implements the driver-agnostic suppression of NilAway errors by the names
+// of the files they are reported in, such that the standalone driver, nogo, golangci-lint and
+// unitchecker drivers share the same filtering semantics.
+package suppression
+
+import (
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Mechanism is a mechanism suppressing the errors, named after the flag (or the convention)
+// enabling it.
+type Mechanism string
+
+const (
+	// Generated suppresses the errors in the generated files (unless -include-generated is set).
+	Generated Mechanism = "generated"
+	// ExcludeTests suppresses the errors in the test files (-exclude-tests).
+	ExcludeTests Mechanism = "exclude-tests"
+	// TestsOnly suppresses the errors in the non-test files (-tests-only).
+	TestsOnly Mechanism = "tests-only"
+	// ExcludeMocks suppresses the errors in the mocks (-exclude-mocks).
+	ExcludeMocks Mechanism = "exclude-mocks"
+	// IncludeErrorsInFiles suppresses the errors in the files not matching the include list of a
+	// Filter (-include-errors-in-files).
+	IncludeErrorsInFiles Mechanism = "include-errors-in-files"
+	// ExcludeErrorsInFiles suppresses the errors in the files matching the exclude list of a
+	// Filter (-exclude-errors-in-files).
+	ExcludeErrorsInFiles Mechanism = "exclude-errors-in-files"
+	// RecoveredPanics suppresses the errors in the regions recovering from panics
+	// (-recovered-panics=suppress).
+	RecoveredPanics Mechanism = "recovered-panics"
+	// SourceOutOfScope suppresses the errors whose nil sources are outside the analysis scope
+	// (-require-source-in-scope=suppress).
+	SourceOutOfScope Mechanism = "require-source-in-scope"
+	// Baseline suppresses the errors accepted by the baseline file of the standalone driver
+	// (-baseline), i.e., they do not fail the run.
+	Baseline Mechanism = "baseline"
+)
+
+// Counts is the number of the errors suppressed by each mechanism.
+type Counts map[Mechanism]int
+
+// Add adds the numbers in other to the counts.
+func (c Counts) Add(other Counts) {
+	for m, n := range other {
+		c[m] += n
+	}
+}
+
+// Filter decides whether the errors in a file should be reported by matching the file name
+// against the lists of file prefixes to include and exclude.
+type Filter struct {
+	// includes is the list of file prefixes to report errors in, empty means all files.
+	includes []string
+	// excludes is the list of file prefixes to not report errors in, which takes precedence over
+	// the include list.
+	excludes []string
+}
+
+// NewFilter returns a filter for the comma-separated lists of file prefixes to include and
+// exclude, where the prefixes are converted to absolute file paths.
+func NewFilter(includes, excludes string) (*Filter, error) {
+	in, err := parseFilePrefixes(includes)
+	if err != nil {
+		return nil, fmt.Errorf("parse file prefixes for error inclusion: %w", err)
+	}
+	ex, err := parseFilePrefixes(excludes)
+	if err != nil {
+		return nil, fmt.Errorf("parse file prefixes for error exclusion: %w", err)
+	}
+	return &Filter{includes: in, excludes: ex}, nil
+}
+
+// IsReported returns true iff the errors in the file with the given name should be reported, i.e.,
+// the file matches the include list (if any) but not the exclude list. Relative file names (e.g.,
+// the ones from the "//line" directives, or under build systems with sandboxing) are resolved
+// against the current working directory.
+func (f *Filter) IsReported(filename string) bool {
+	return f.SuppressedBy(filename) == ""
+}
+
+// SuppressedBy returns the mechanism suppressing the errors in the file with the given name, i.e.,
+// ExcludeErrorsInFiles or IncludeErrorsInFiles, or an empty string if they are reported (see
+// IsReported).
+func (f *Filter) SuppressedBy(filename string) Mechanism {
+	if abs, err := filepath.Abs(filename); err == nil {
+		filename = abs
+	}
+	for _, e := range f.excludes {
+		if strings.HasPrefix(filename, e) {
+			return ExcludeErrorsInFiles
+		}
+	}
+	if len(f.includes) == 0 {
+		return ""
+	}
+	for _, i := range f.includes {
+		if strings.HasPrefix(filename, i) {
+			return ""
+		}
+	}
+	return IncludeErrorsInFiles
+}
+
+// Apply returns the diagnostics in the files that should be reported (see IsReported). The
+// diagnostics are filtered in place, and the numbers of the ones suppressed are added to suppressed
+// (if not nil) by their mechanisms.
+func (f *Filter) Apply(fset *token.FileSet, diagnostics []analysis.Diagnostic, suppressed Counts) []analysis.Diagnostic {
+	if len(f.includes) == 0 && len(f.excludes) == 0 {
+		return diagnostics
+	}
+	return slices.DeleteFunc(diagnostics, func(d analysis.Diagnostic) bool {
+		file := fset.File(d.Pos)
+		if file == nil {
+			return false
+		}
+		m := f.SuppressedBy(file.Name())
+		if m != "" && suppressed != nil {
+			suppressed[m]++
+		}
+		return m != ""
+	})
+}
+
+// parseFilePrefixes parses the comma-separated list of file prefixes, converts them to absolute
+// file paths, and returns them as a slice.
+func parseFilePrefixes(s string) ([]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	// Convert the file paths to absolute paths.
+	list := strings.Split(s, ",")
+	for i := range list {
+		p, err := filepath.Abs(list[i])
+		if err != nil {
+			return nil, fmt.Errorf("convert %q to absolute path: %w", list[i], err)
+		}
+		list[i] = p
+	}
+	return list, nil
+}
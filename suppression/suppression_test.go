@@ -0,0 +1,74 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package suppression
+
+import (
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/analysis"
+)
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	// No prefixes means all files are reported.
+	f, err := NewFilter("", "")
+	require.NoError(t, err)
+	require.True(t, f.IsReported("/any/file.go"))
+
+	f, err = NewFilter("/src/foo,bar", "/src/foo/gen,bar/baz.go")
+	require.NoError(t, err)
+	require.True(t, f.IsReported("/src/foo/foo.go"))
+	require.False(t, f.IsReported("/src/other/other.go"))
+	// The exclude list takes precedence over the include list.
+	require.False(t, f.IsReported("/src/foo/gen/gen.go"))
+	// The relative prefixes and file names are resolved against the working directory.
+	require.True(t, f.IsReported(filepath.Join(wd, "bar", "bar.go")))
+	require.True(t, f.IsReported(filepath.Join("bar", "bar.go")))
+	require.False(t, f.IsReported(filepath.Join("bar", "baz.go")))
+
+	require.Equal(t, Mechanism(""), f.SuppressedBy("/src/foo/foo.go"))
+	require.Equal(t, IncludeErrorsInFiles, f.SuppressedBy("/src/other/other.go"))
+	require.Equal(t, ExcludeErrorsInFiles, f.SuppressedBy("/src/foo/gen/gen.go"))
+}
+
+func TestFilterApply(t *testing.T) {
+	t.Parallel()
+
+	fset := token.NewFileSet()
+	var diagnostics []analysis.Diagnostic
+	for _, name := range []string{"/src/foo/foo.go", "/src/foo/gen/gen.go", "/src/other/other.go", "/src/other/other2.go"} {
+		file := fset.AddFile(name, -1, 10)
+		diagnostics = append(diagnostics, analysis.Diagnostic{Pos: file.Pos(1), Message: name})
+	}
+
+	f, err := NewFilter("/src/foo", "/src/foo/gen")
+	require.NoError(t, err)
+	suppressed := make(Counts)
+	diagnostics = f.Apply(fset, diagnostics, suppressed)
+	require.Len(t, diagnostics, 1)
+	require.Equal(t, "/src/foo/foo.go", diagnostics[0].Message)
+	require.Equal(t, Counts{IncludeErrorsInFiles: 2, ExcludeErrorsInFiles: 1}, suppressed)
+
+	suppressed.Add(Counts{ExcludeErrorsInFiles: 1, RecoveredPanics: 3})
+	require.Equal(t, Counts{IncludeErrorsInFiles: 2, ExcludeErrorsInFiles: 2, RecoveredPanics: 3}, suppressed)
+}
@@ -0,0 +1,48 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package templ is a minimal stub of github.com/a-h/templ for testing.
+package templ
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// Component is the interface of the components generated from the templates.
+type Component interface {
+	Render(ctx context.Context, w io.Writer) error
+}
+
+// ComponentFunc converts a function to a Component.
+type ComponentFunc func(ctx context.Context, w io.Writer) error
+
+// Render renders the component.
+func (f ComponentFunc) Render(ctx context.Context, w io.Writer) error {
+	return f(ctx, w)
+}
+
+// Error is the error of a Go expression embedded in a template, with its position in the template.
+type Error struct {
+	Err      error
+	FileName string
+	Line     int
+	Col      int
+}
+
+// Error returns the error message.
+func (e Error) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %v", e.FileName, e.Line, e.Col, e.Err)
+}
@@ -0,0 +1,31 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package wire is a minimal stub of github.com/google/wire for testing.
+package wire
+
+// ProviderSet is a set of providers.
+type ProviderSet struct{}
+
+// NewSet creates a new provider set from the providers.
+func NewSet(...interface{}) ProviderSet { return ProviderSet{} }
+
+// Build declares an injector built from the providers.
+func Build(...interface{}) string { return "implementation not generated, run wire" }
+
+// StructProvider provides a struct by populating its fields.
+type StructProvider struct{}
+
+// Struct specifies the fields of the struct pointed to by structType to populate.
+func Struct(structType interface{}, fieldNames ...string) StructProvider { return StructProvider{} }
@@ -0,0 +1,68 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This package aims to test the annotations suggested by `-fix-mode=annotate`. The expected results
+// of the fixes are in the golden file.
+package annotate
+
+// Config is a config.
+type Config struct { //want "inferred annotations for `Config`: nonnil\\(Name\\)"
+	Name  *string
+	Extra *int
+	inner *int
+}
+
+// Default is the default config.
+var Default = &Config{} //want "inferred annotations for `Default`: nonnil\\(Default\\)"
+
+var (
+	// Fallback is the fallback config.
+	Fallback = Default //want "inferred annotations for `Fallback`: nonnil\\(Fallback\\)"
+	Enabled  = true
+)
+
+// New returns a new config.
+func New(name *string) *Config { //want "inferred annotations for `New`: nonnil\\(result 0\\)"
+	return &Config{Name: name}
+}
+
+// Find returns nil.
+func Find(key string) (*Config, error) { //want "inferred annotations for `Find`: nilable\\(result 0\\)"
+	if key == "" {
+		return nil, nil
+	}
+	return Default, nil
+}
+
+// Len dereferences its receiver and argument.
+func (c *Config) Len(p *int) int { //want "inferred annotations for `Len`: nonnil\\(c, p\\)"
+	return len(*c.Name) + *p
+}
+
+// Annotated is already annotated.
+// nilable(result 0)
+func Annotated() *int {
+	return nil
+}
+
+func unexported() *int {
+	return nil
+}
+
+func use() {
+	s := "name"
+	print(New(&s).Name)
+	print(Fallback.Extra)
+	print(unexported())
+}
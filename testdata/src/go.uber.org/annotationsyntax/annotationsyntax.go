@@ -0,0 +1,115 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+This test aims to make sure that the full grammar of the annotation comments (targets by name,
+by index, receivers, fields and deep variants) is supported, and that malformed annotations are
+reported.
+
+<nilaway no inference>
+*/
+package annotationsyntax
+
+import "strings"
+
+type Client struct {
+	Name string
+}
+
+type Config struct {
+	Client *Client
+	Other  *Client
+}
+
+// Unnamed parameters and results can be referred to by their indices.
+
+// nilable(param 0, result 0)
+func byIndex(*int, *int) *int {
+	return nil
+}
+
+func testByIndex() {
+	_ = *byIndex(nil, new(int)) //want "dereferenced"
+}
+
+// Named parameters and results can be referred to by their indices as well.
+
+// nilable(param 1) nonnil(result 0)
+func byIndexNamed(a, b *int) (r *int) {
+	_ = *a
+	_ = *b   //want "dereferenced"
+	return b //want "returned"
+}
+
+type T struct {
+	f *int
+}
+
+// nilable(receiver)
+func (t *T) nilableRecv() int {
+	return *t.f //want "accessed field"
+}
+
+// Fields of struct types declared in this package can be annotated on the sites holding them.
+
+// nilable(cfg.Other)
+func useConfig(cfg *Config) string {
+	return cfg.Client.Name + cfg.Other.Name //want "accessed field"
+}
+
+// Deep variants.
+
+// nilable(*p, <-c, s[]) nonnil(s)
+func deep(p **int, c chan *int, s []*int) {
+	_ = **p   //want "dereferenced"
+	_ = *<-c  //want "dereferenced"
+	_ = *s[0] //want "dereferenced"
+}
+
+// Call site annotations by name and by index.
+
+func callSite(a *int) *int {
+	return a
+}
+
+func testCallSite() {
+	_ = *callSite(nil) //want "passed"
+	_ = *callSite(nil) // nilable(a) nonnil(result 0)
+	_ = *callSite(nil) // nilable(param 0) nonnil(result 0)
+}
+
+// Malformed annotations are reported. Block comments are used such that the expectations are
+// not part of the annotation comments.
+
+/* nilable(b) */           //want "Invalid NilAway annotation: unknown annotation target \"b\""
+func unknownTarget(a *int) {}
+
+/* nilable(param 1) */    //want "Invalid NilAway annotation: unknown annotation target \"param 1\""
+func unknownIndex(a *int) {}
+
+/* nilable(a */           //want "Invalid NilAway annotation: missing \"\\)\""
+func missingParen(a *int) {}
+
+/* nilable(a b) */         //want "Invalid NilAway annotation: invalid annotation .* is not a parameter, result, receiver or field"
+func invalidTarget(a *int) {}
+
+/* nilable(*a[]) */                //want "Invalid NilAway annotation: invalid annotation .* multiple deep markers"
+func multipleDeepMarkers(a []*int) {}
+
+/* nilable(cfg.Missing) */     //want "Invalid NilAway annotation: unknown annotation target \"cfg.Missing\""
+func unknownField(cfg *Config) {}
+
+func testNonLocalCallSite() {
+	_ = strings.ToUpper("") // nilable(result 0) // want "Invalid NilAway annotation: call site annotations are only supported"
+}
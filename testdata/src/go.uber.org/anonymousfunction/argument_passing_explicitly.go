@@ -32,7 +32,7 @@ func testPassingArgsExplicityToAnonFuncs() {
 	}(t1)
 
 	// Pass a nonnil struct as parameter
-	t2 := A{}
+	t2 := A{} //want "uninitialized field `c` omitted from the composite literal"
 	func(t *A) *int {
 		return t.a
 	}(&t2)
@@ -104,5 +104,5 @@ func nilReceiverTestDriver() {
 func (a *A) testNonNilReceiver() {
 	func(na *A) {
 		print(*na) // this is also ok
-	}(&A{})
+	}(&A{}) //want "uninitialized field `c` omitted from the composite literal"
 }
@@ -0,0 +1,47 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anonymousfunction
+
+// forEach passes a nilable value to its callback.
+func forEach(cb func(p *int)) {
+	cb(nil)
+}
+
+// forEachNonnil passes a nonnil value to its callback.
+func forEachNonnil(cb func(p *int)) {
+	cb(new(int))
+}
+
+func testCallbackFuncLits() {
+	forEach(func(p *int) {
+		print(*p) //want "passed as arg `p` to `cb\\(\\)`"
+	})
+
+	forEach(func(p *int) {
+		if p != nil {
+			print(*p)
+		}
+	})
+
+	forEachNonnil(func(p *int) {
+		print(*p)
+	})
+
+	// The function literal can also be assigned to a variable first.
+	f := func(p *int) {
+		print(*p) //want "passed as arg `p` to `cb\\(\\)`"
+	}
+	forEach(f)
+}
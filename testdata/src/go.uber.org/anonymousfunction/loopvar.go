@@ -0,0 +1,68 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anonymousfunction
+
+// This file tests the loop variables captured by the function literals spawned in goroutines,
+// which are created afresh for each iteration since Go 1.22, such that the goroutines only
+// observe the later assignments in the same iteration.
+
+// nilable(next)
+type node struct {
+	v    int
+	next *node
+}
+
+// nilable(ps[])
+func spawnGuardedRangeValue(ps []*node) {
+	for _, p := range ps {
+		if p == nil {
+			continue
+		}
+		go func() {
+			print(p.v)
+		}()
+	}
+}
+
+// nilable(ps[])
+func spawnReassignedRangeValue(ps []*node) {
+	for _, p := range ps {
+		if p == nil {
+			continue
+		}
+		go func() {
+			print(p.v) //want "literal `nil` passed as arg `p`"
+		}()
+		p = nil
+	}
+}
+
+func spawnAssignedBeforeRangeValue(ps []*node) {
+	for _, p := range ps {
+		p = nil
+		p = &node{}
+		go func() {
+			print(p.v)
+		}()
+	}
+}
+
+func spawnForLoopVar(head *node) {
+	for n := head; n != nil; n = n.next {
+		go func() {
+			print(n.v)
+		}()
+	}
+}
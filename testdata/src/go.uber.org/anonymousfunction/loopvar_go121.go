@@ -0,0 +1,59 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.21
+
+package anonymousfunction
+
+// This file tests the loop variables captured by the function literals spawned in goroutines
+// with Go versions older than 1.22, where the loop variables are shared across the iterations,
+// such that the goroutines may observe the values of the later iterations that are not guarded
+// at the `go` statements.
+
+// nilable(ps[])
+func spawnGuardedRangeValueGo121(ps []*node) {
+	for _, p := range ps {
+		if p == nil {
+			continue
+		}
+		go func() {
+			print(p.v) //want "deep read from parameter `ps` passed as arg `p`"
+		}()
+	}
+}
+
+func spawnNonnilRangeValueGo121(ps []*node) {
+	for _, p := range ps {
+		go func() {
+			print(p.v)
+		}()
+	}
+}
+
+func spawnForLoopVarGo121(head *node) {
+	for n := head; n != nil; n = n.next {
+		go func() {
+			print(n.v) //want "field `next` passed as arg `n`"
+		}()
+	}
+}
+
+func spawnPerIterationCopyGo121(head *node) {
+	for n := head; n != nil; n = n.next {
+		n := n
+		go func() {
+			print(n.v)
+		}()
+	}
+}
@@ -39,7 +39,7 @@ func retNilable() *int {
 func simple() {
 	// Here we test nilability analysis _inside_ the anonymous functions, where no interactions
 	// happen between the anonymous functions and the outside world.
-	aNonnilPtr := &A{}
+	aNonnilPtr := &A{} //want "uninitialized field `c` omitted from the composite literal"
 	// ERROR_GROUP: the two errors reporting dereference of `aNonnilPtr.a` are grouped together and reported on the below line.
 	print(*(aNonnilPtr.a)) //want "it is annotated"
 
@@ -53,7 +53,7 @@ func simple() {
 		print(*t3) //want "result 0 of `retNilable.*` dereferenced"
 		var aPtr *A
 		print(*aPtr) //want "unassigned variable `aPtr`"
-		aNonnilPtr := &A{}
+		aNonnilPtr := &A{} //want "uninitialized field `c` omitted from the composite literal"
 		print(*(aNonnilPtr.a)) // (error here is grouped with the error at line marked with `ERROR_GROUP`)
 		// A.c is marked as nonnil, so it is ok to dereference.
 		print(aNonnilPtr.c.a)
@@ -0,0 +1,88 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Tests for arrays of pointers that are declared but never assigned, whose elements are all nil.
+
+package arrays
+
+type elem struct {
+	f int
+}
+
+func testUnassignedArrayIndex(i int) int {
+	var arr [3]*elem
+	return arr[i].f //want "element of unassigned array `arr` accessed field `f`"
+}
+
+func testUnassignedArrayConstIndex() int {
+	var arr [3]*elem
+	return arr[0].f //want "element of unassigned array `arr` accessed field `f`"
+}
+
+func testUnassignedArrayViaLocal(i int) int {
+	var arr [3]*elem
+	x := arr[i]
+	return x.f //want "element of unassigned array `arr` accessed field `f`"
+}
+
+func countNonnil(a [3]*elem) int {
+	n := 0
+	for _, e := range a {
+		if e != nil {
+			n++
+		}
+	}
+	return n
+}
+
+func testUnassignedArrayPassedByValue(i int) int {
+	var arr [3]*elem
+	_ = countNonnil(arr)
+	return arr[i].f //want "element of unassigned array `arr` accessed field `f`"
+}
+
+func testUnassignedArrayChecked(i int) int {
+	var arr [3]*elem
+	if arr[i] != nil {
+		return arr[i].f
+	}
+	return 0
+}
+
+func testArrayAssignedInLoop(i int) int {
+	var arr [3]*elem
+	for j := range arr {
+		arr[j] = &elem{}
+	}
+	return arr[i].f
+}
+
+func fillArray(a *[3]*elem) {
+	for i := range a {
+		a[i] = &elem{}
+	}
+}
+
+func testArrayFilledThroughPointer(i int) int {
+	var arr [3]*elem
+	fillArray(&arr)
+	return arr[i].f
+}
+
+// nonnil(other[])
+func testArrayAssignedWhole(i int, other [3]*elem) int {
+	var arr [3]*elem
+	arr = other
+	return arr[i].f
+}
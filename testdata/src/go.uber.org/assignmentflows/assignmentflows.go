@@ -0,0 +1,25 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package assignmentflows tests that every assignment of the assignment flows is printed in the
+// error messages with -assignment-flows=full, including the trivial single-use temporaries that
+// are elided by default.
+package assignmentflows
+
+func chain() int {
+	var x *int
+	y := x
+	z := y
+	return *z //want "`x` to `y` at .*,\n\t\t- `y` to `z` at "
+}
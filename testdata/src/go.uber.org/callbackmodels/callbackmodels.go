@@ -0,0 +1,123 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package callbackmodels tests the modeling of the callbacks passed to the common higher-order
+// functions (e.g., `filepath.WalkDir` or `sort.Slice`).
+package callbackmodels
+
+import (
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+func visit(path string, d fs.DirEntry, err error) error {
+	// The implementation of `filepath.WalkDir` passes a nil `d` along with a nonnil `err`, so `d`
+	// must not be used before checking `err`.
+	_ = d.Name() //want "lacking guarding"
+	return nil
+}
+
+func visitChecked(path string, d fs.DirEntry, err error) error {
+	if err != nil {
+		return err
+	}
+	_ = d.Name()
+	return nil
+}
+
+func visitInfo(path string, info fs.FileInfo, err error) error {
+	if err == nil {
+		_ = info.Name()
+	}
+	return nil
+}
+
+func handle(w http.ResponseWriter, r *http.Request) {
+	_ = r.URL.Path
+	w.WriteHeader(http.StatusOK)
+}
+
+func testWalk() {
+	_ = filepath.WalkDir(".", visit)
+	_ = filepath.WalkDir(".", visitChecked)
+	_ = filepath.Walk(".", visitInfo)
+	_ = fs.WalkDir(os.DirFS("."), ".", visitChecked)
+	_ = filepath.WalkDir(".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		_ = d.IsDir()
+		return nil
+	})
+	_ = filepath.WalkDir(".", func(path string, d fs.DirEntry, err error) error {
+		_ = d.IsDir() //want "lacking guarding"
+		return err
+	})
+}
+
+func testHandle() {
+	http.HandleFunc("/", handle)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", handle)
+	mux.HandleFunc("/x", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.Method
+	})
+}
+
+type T struct {
+	v int
+}
+
+func testSortCaptures(ts []int, p *T) {
+	var nilT *T
+	sort.Slice(ts, func(i, j int) bool {
+		return nilT.v < ts[i] //want "unassigned variable `nilT` passed as arg `nilT`"
+	})
+
+	if p != nil {
+		sort.SliceStable(ts, func(i, j int) bool {
+			return p.v < ts[i]
+		})
+	}
+}
+
+func testOnceCaptures(p *T) {
+	var once sync.Once
+	var nilT *T
+	once.Do(func() {
+		print(nilT.v) //want "unassigned variable `nilT` passed as arg `nilT`"
+	})
+
+	if p == nil {
+		return
+	}
+	once.Do(func() {
+		print(p.v)
+	})
+}
+
+func testErrgroup() error {
+	var g errgroup.Group
+	g.Go(func() error {
+		_, err := os.Stat(".")
+		return err
+	})
+	return g.Wait()
+}
@@ -0,0 +1,93 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package callbacks tests the nilability flows into and out of the callbacks, i.e., the functions
+// passed as the function-typed parameters of other functions.
+package callbacks
+
+var dummy bool
+
+type T struct {
+	f int
+}
+
+// walk passes a nilable value to its callback.
+func walk(cb func(t *T, depth int) error) error {
+	if dummy {
+		return cb(nil, 0)
+	}
+	return cb(&T{}, 1)
+}
+
+// visit passes a nonnil value to its callback.
+func visit(cb func(t *T)) {
+	cb(&T{})
+}
+
+func handleDeref(t *T, depth int) error {
+	_ = t.f //want "arg `t` of callback `cb` of `walk\\(\\)` passed as parameter `t` to `handleDeref\\(\\)`"
+	return nil
+}
+
+func handleChecked(t *T, depth int) error {
+	if t != nil {
+		_ = t.f
+	}
+	return nil
+}
+
+func handleVisit(t *T) {
+	_ = t.f
+}
+
+type S struct{}
+
+func (S) handle(t *T, depth int) error {
+	_ = t.f //want "passed as arg `t` to `cb\\(\\)`"
+	return nil
+}
+
+func testParams(s S) {
+	_ = walk(handleDeref)
+	_ = walk(handleChecked)
+	_ = walk(s.handle)
+	visit(handleVisit)
+}
+
+// produce returns the result of its callback.
+func produce(cb func() *T) int {
+	return cb().f //want "result 0 of `retNil\\(\\)` returned as result 0 from callback `cb` of `produce\\(\\)`"
+}
+
+func retNil() *T {
+	return nil
+}
+
+func retNonnil() *T {
+	return &T{}
+}
+
+// produceChecked guards the result of its callback.
+func produceChecked(cb func() *T) int {
+	if t := cb(); t != nil {
+		return t.f
+	}
+	return 0
+}
+
+func testResults() {
+	_ = produce(retNonnil)
+	_ = produce(retNil)
+	_ = produceChecked(retNil)
+}
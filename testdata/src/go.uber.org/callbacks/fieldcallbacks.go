@@ -0,0 +1,92 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package callbacks
+
+// The below tests check the nilability flows into and out of the callbacks stored in the
+// function-typed struct fields.
+
+type Event struct {
+	name string
+}
+
+type Bus struct {
+	onEvent  func(e *Event)
+	onClose  func(e *Event)
+	onLookup func(key string) *Event
+	onFind   func(key string) *Event
+}
+
+func handleEvent(e *Event) {
+	_ = e.name //want "arg `e` of callback field `onEvent` passed as parameter `e` to `handleEvent\\(\\)`"
+}
+
+func (b *Bus) emit() {
+	b.onEvent(nil)
+	b.onClose(&Event{})
+}
+
+func newBus() *Bus {
+	b := &Bus{
+		// The closure is only passed nonnil values.
+		onClose: func(e *Event) {
+			_ = e.name
+		},
+		onLookup: lookupNil,
+		onFind:   lookupNonnil,
+	}
+	b.onEvent = handleEvent
+	return b
+}
+
+func lookupNil(key string) *Event {
+	return nil
+}
+
+func lookupNonnil(key string) *Event {
+	return &Event{name: key}
+}
+
+func (b *Bus) lookup(key string) string {
+	return b.onLookup(key).name //want "result 0 of `lookupNil\\(\\)` returned as result 0 from callback field `onLookup`"
+}
+
+func (b *Bus) lookupChecked(key string) string {
+	if e := b.onLookup(key); e != nil {
+		return e.name
+	}
+	return ""
+}
+
+func (b *Bus) find(key string) string {
+	return b.onFind(key).name
+}
+
+type Server struct {
+	onRequest func(r *Event) *Event
+}
+
+func (s *Server) serve() string {
+	return s.onRequest(nil).name
+}
+
+func newServer() *Server {
+	s := &Server{}
+	// The closure dereferences the nil value passed by the call of the field.
+	s.onRequest = func(r *Event) *Event {
+		_ = r.name //want "arg `r` of callback field `onRequest`"
+		return &Event{}
+	}
+	return s
+}
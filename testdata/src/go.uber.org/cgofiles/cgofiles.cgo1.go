@@ -0,0 +1,14 @@
+// This file simulates a file rewritten by cgo from the original source file "cgo.go" (except
+// for the "Code generated" header, since the generated files are excluded in the tests).
+
+//line cgo.go:1:1
+package cgofiles
+
+func nilPtr() *int {
+	return nil
+}
+
+func derefLocal() {
+	var p *int
+	print(*p) // want "cgofiles/cgo.go:9:9: unassigned variable `p` dereferenced"
+}
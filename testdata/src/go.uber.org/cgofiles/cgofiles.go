@@ -0,0 +1,22 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This package tests that the positions in the files rewritten by cgo are adjusted by their
+// "//line" directives, such that the diagnostics point at the original source files.
+
+package cgofiles
+
+func deref() {
+	print(*nilPtr()) // want "cgofiles/cgo.go:4:9: literal `nil` returned"
+}
@@ -0,0 +1,131 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This package tests that the function literals that are immediately invoked, or bound to local
+// variables and called exactly once, are analyzed even without the experimental anonymous
+// function support, as if they were inlined at their call sites. In particular, the nil checks
+// of the closure variables at the call sites are propagated into the function literals. Other
+// function literals are not analyzed.
+
+package closures
+
+func nilablePtr() *int {
+	return nil
+}
+
+func guardedImmediateCall() {
+	x := nilablePtr()
+	if x != nil {
+		func() {
+			print(*x)
+		}()
+	}
+}
+
+func unguardedImmediateCall() {
+	x := nilablePtr()
+	func() {
+		print(*x) //want "passed as arg `x`"
+	}()
+}
+
+func guardInsideImmediateCall() {
+	x := nilablePtr()
+	func() {
+		if x != nil {
+			print(*x)
+		}
+	}()
+}
+
+func guardedCallOnce() {
+	x := nilablePtr()
+	f := func() {
+		print(*x)
+	}
+	if x != nil {
+		f()
+	}
+}
+
+func earlyReturnCallOnce() {
+	x := nilablePtr()
+	if x == nil {
+		return
+	}
+	f := func() {
+		print(*x)
+	}
+	f()
+}
+
+func unguardedCallOnce() {
+	x := nilablePtr()
+	f := func() {
+		print(*x) //want "passed as arg `x`"
+	}
+	f()
+}
+
+func nestedImmediateCalls() {
+	x := nilablePtr()
+	if x == nil {
+		return
+	}
+	func() {
+		func() {
+			print(*x)
+		}()
+	}()
+
+	y := nilablePtr()
+	func() {
+		func() {
+			print(*y) //want "passed as arg `y`"
+		}()
+	}()
+}
+
+// The function literals below are not inlinable, hence not analyzed.
+
+func calledTwice() {
+	x := nilablePtr()
+	f := func() {
+		print(*x)
+	}
+	f()
+	f()
+}
+
+func deferred() {
+	x := nilablePtr()
+	defer func() {
+		print(*x)
+	}()
+}
+
+func spawned() {
+	x := nilablePtr()
+	go func() {
+		print(*x)
+	}()
+}
+
+func escaping() func() {
+	x := nilablePtr()
+	f := func() {
+		print(*x)
+	}
+	return f
+}
@@ -0,0 +1,93 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// These tests check that the results of the immediately invoked function literals (and the ones
+// called exactly once) flow to the assignment targets of their calls, which is a common pattern
+// for conditional initializations.
+
+package closures
+
+func conditionalInit(b bool) {
+	x := func() *int {
+		if b {
+			return nil
+		}
+		return new(int)
+	}()
+	print(*x) //want "dereferenced via the assignment\\(s\\):\n.*`func\\(\\) \\*int {...}\\(\\)` to `x`"
+}
+
+func nonnilInit(b bool) {
+	x := func() *int {
+		if b {
+			return new(int)
+		}
+		return new(int)
+	}()
+	print(*x)
+}
+
+func guardedInit() {
+	x := func() *int {
+		v := nilablePtr()
+		if v == nil {
+			v = new(int)
+		}
+		return v
+	}()
+	print(*x)
+}
+
+func closureVarInit() {
+	v := nilablePtr()
+	x := func() *int {
+		return v
+	}()
+	print(*x) //want "dereferenced"
+
+	if v != nil {
+		y := func() *int {
+			return v
+		}()
+		print(*y)
+	}
+}
+
+func multipleResultsInit() {
+	x, y := func() (*int, *int) {
+		return nil, new(int)
+	}()
+	print(*x) //want "dereferenced"
+	print(*y)
+}
+
+func callOnceInit() {
+	f := func() *int {
+		return nil
+	}
+	x := f()
+	print(*x) //want "dereferenced"
+}
+
+func paramInit() {
+	x := func(p *int) *int {
+		return p
+	}(nilablePtr())
+	print(*x) //want "dereferenced"
+
+	y := func(p *int) *int {
+		return p
+	}(new(int))
+	print(*y)
+}
@@ -110,9 +110,7 @@ func testAlwaysSafe(i int) {
 	}
 }
 
-// Test always safe through multiple hops. Currently, we support only immediate function call for "always safe" tracking.
-// Hence, the below cases are expected to report errors.
-// TODO: add support for multiple hops to address the false positives
+// Test always safe through multiple hops, i.e., the non-nil returns flowing from other always safe functions.
 
 func m1() (*int, bool) {
 	return m2()
@@ -155,13 +153,13 @@ func f1(i int) (*int, bool) {
 }
 
 func testAlwaysSafeMultipleHops() {
-	// TODO: call to m1() should be reported as always safe. This is a false positive since currently we are limiting the
-	//  "always safe" tracking to only immediate function call, not chained error returning function calls.
+	// always safe, since m1() returns the results of m2(), which is always safe
 	v1, _ := m1()
-	print(*v1) //want "dereferenced"
+	print(*v1)
 
 	// TODO: call to f1() should be reported as always safe. This is a false positive since currently we are limiting the
-	// analysis of "return statements" to only the directly determinable cases (e.g., new(int), &S{}, NegativeNilCheck), not through multiple hops.
+	// analysis of "return statements" to the directly determinable cases (e.g., new(int), &S{}, NegativeNilCheck) and the
+	// returns of other always safe functions, not the field reads.
 	v2, _ := f1(0)
 	print(*v2) //want "dereferenced"
 }
@@ -0,0 +1,55 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package okreturn tests that the return contracts of the upstream ok-returning functions are used
+// to check their callers: the results used under an `ok` check are safe if they are nonnil
+// whenever `ok` is true, while the results used without checking `ok` are reported.
+package okreturn
+
+import "go.uber.org/contracts/okreturn/upstream"
+
+func guarded() int {
+	if v, ok := upstream.Lookup("a"); ok {
+		return v.F
+	}
+	if v, ok := upstream.LookupChecked("b"); ok {
+		return v.F
+	}
+	if v, ok := upstream.LookupNested("c"); ok {
+		return v.F
+	}
+	if v, ok := upstream.Recv(); ok {
+		return v.F
+	}
+	return 0
+}
+
+func unguarded() int {
+	v, _ := upstream.Lookup("a")
+	return v.F //want "result 0 of `Lookup.*` lacking guarding"
+}
+
+func guardedNoContract(i int) int {
+	switch i {
+	case 0:
+		if v, ok := upstream.Negated("a"); ok {
+			return v.F //want "result 0 of `Negated.*`"
+		}
+	case 1:
+		if v, ok := upstream.Unrelated("b"); ok {
+			return v.F //want "result 0 of `Unrelated.*`"
+		}
+	}
+	return 0
+}
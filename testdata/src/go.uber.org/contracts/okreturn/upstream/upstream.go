@@ -0,0 +1,66 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package upstream declares ok-returning functions whose return contracts (i.e., which results are
+// nonnil whenever `ok` is true) are exported to the downstream packages.
+package upstream
+
+type T struct {
+	F int
+}
+
+var cache = map[string]*T{}
+
+var ch = make(chan *T)
+
+// Lookup passes on the `ok` of the map read, so it has the contract "ok => nonnil(result 0)".
+func Lookup(k string) (*T, bool) {
+	v, ok := cache[k]
+	return v, ok
+}
+
+// LookupChecked checks the `ok` of the map read itself, so it has the contract
+// "ok => nonnil(result 0)".
+func LookupChecked(k string) (*T, bool) {
+	v, ok := cache[k]
+	if !ok {
+		return nil, false
+	}
+	return v, true
+}
+
+// LookupNested passes on the `ok` of another ok-returning function.
+func LookupNested(k string) (*T, bool) {
+	v, ok := Lookup(k)
+	return v, ok
+}
+
+// Recv passes on the `ok` of the channel receive.
+func Recv() (*T, bool) {
+	v, ok := <-ch
+	return v, ok
+}
+
+// Negated does not pass on the `ok` of the map read, so it has no contract.
+func Negated(k string) (*T, bool) {
+	v, ok := cache[k]
+	return v, !ok
+}
+
+// Unrelated returns an `ok` unrelated to the returned value, so it has no contract.
+func Unrelated(k string) (*T, bool) {
+	v, _ := cache[k]
+	_, ok := cache[k+k]
+	return v, ok
+}
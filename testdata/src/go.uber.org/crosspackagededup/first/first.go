@@ -0,0 +1,28 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package first is the first package importing both nilassign and deref, which observes the
+// conflict on the upstream field (i.e., nilable in nilassign but nonnil in deref) and reports it.
+package first
+
+import (
+	"go.uber.org/crosspackagededup/deref"
+	"go.uber.org/crosspackagededup/nilassign"
+	"go.uber.org/crosspackagededup/upstream"
+)
+
+func Use(t *upstream.T) int {
+	nilassign.Assign(t)
+	return deref.Deref(t)
+}
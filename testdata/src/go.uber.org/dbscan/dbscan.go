@@ -0,0 +1,104 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dbscan tests the modeling of the database APIs scanning the query results into the
+// destinations whose addresses are passed.
+package dbscan
+
+import (
+	"context"
+	"database/sql"
+
+	"go.uber.org/dbscan/github.com/jmoiron/sqlx"
+	"go.uber.org/dbscan/gorm.io/gorm"
+)
+
+type User struct {
+	Name     string
+	Nickname *string
+}
+
+// The NULL columns are scanned as nil into the pointers.
+
+func scanPointer(row *sql.Row) string {
+	var nickname *string
+	if err := row.Scan(&nickname); err != nil {
+		return ""
+	}
+	return *nickname //want "dereferenced via the assignment\\(s\\):(.|\n)*`nil` to `nickname`"
+}
+
+func scanPointerGuarded(row *sql.Row) string {
+	var nickname *string
+	if err := row.Scan(&nickname); err != nil || nickname == nil {
+		return ""
+	}
+	return *nickname
+}
+
+func scanField(rows *sql.Rows) string {
+	var u User
+	for rows.Next() {
+		if err := rows.Scan(&u.Name, &u.Nickname); err != nil {
+			return ""
+		}
+	}
+	return u.Name + *u.Nickname //want "literal `nil` assigned into field `Nickname`" "`nil` to `u.Nickname`"
+}
+
+func scanQueryRow(db *sql.DB) string {
+	var name string
+	if err := db.QueryRow("SELECT name FROM users").Scan(&name); err != nil {
+		return ""
+	}
+	return name
+}
+
+// The records found are stored into the destinations.
+
+func gormFirst(db *gorm.DB, id int) string {
+	var u *User
+	if err := db.Where("id = ?", id).First(&u).Error; err != nil {
+		return ""
+	}
+	return u.Name
+}
+
+func sqlxGet(db *sqlx.DB, id int) string {
+	var u *User
+	if err := db.Get(&u, "SELECT * FROM users WHERE id = ?", id); err != nil {
+		return ""
+	}
+	return u.Name
+}
+
+func sqlxGetContext(ctx context.Context, db *sqlx.DB, id int) string {
+	var u *User
+	if err := sqlx.Get(db, &u, "SELECT * FROM users WHERE id = ?", id); err != nil {
+		return ""
+	}
+	var v *User
+	_ = db.GetContext(ctx, &v, "SELECT * FROM users WHERE id = ?", id)
+	return u.Name + v.Name
+}
+
+// The other functions taking the addresses are not affected.
+
+func load(dest any) {}
+
+func unknown() string {
+	var u *User
+	load(&u)
+	return u.Name //want "unassigned variable `u` accessed field `Name`"
+}
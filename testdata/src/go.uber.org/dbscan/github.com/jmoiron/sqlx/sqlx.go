@@ -0,0 +1,38 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// <nilaway no inference>
+package sqlx
+
+import "context"
+
+// these stubs simulate the real `github.com/jmoiron/sqlx` package because we can't import it in tests
+
+type Queryer interface {
+	QueryRowx(query string, args ...any) *Row
+}
+
+type DB struct{}
+
+func (db *DB) Get(dest any, query string, args ...any) error { return nil }
+
+func (db *DB) GetContext(ctx context.Context, dest any, query string, args ...any) error { return nil }
+
+func (db *DB) QueryRowx(query string, args ...any) *Row { return &Row{} }
+
+func Get(q Queryer, dest any, query string, args ...any) error { return nil }
+
+type Row struct{}
+
+func (r *Row) StructScan(dest any) error { return nil }
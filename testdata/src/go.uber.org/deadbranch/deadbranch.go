@@ -0,0 +1,135 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package deadbranch tests that the branches guarded by compile-time constants that are provably
+// dead do not create nil flows.
+package deadbranch
+
+import "runtime"
+
+const debug = false
+
+const verbose = !debug
+
+var dummy bool
+
+func deref(p *int) int {
+	return *p
+}
+
+func constIf() int {
+	var p *int
+	if debug {
+		return *p
+	}
+	return 0
+}
+
+func constElse() int {
+	var p *int
+	if verbose {
+		return 0
+	} else {
+		return *p
+	}
+}
+
+func constNegated() int {
+	var p *int
+	if !verbose {
+		return *p
+	}
+	return 0
+}
+
+func constAnd() int {
+	var p *int
+	if debug && dummy {
+		return *p
+	}
+	return 0
+}
+
+func constOr() int {
+	var p *int
+	if verbose || dummy {
+		return 0
+	}
+	return *p
+}
+
+func constLiveBranch() int {
+	var p *int
+	if verbose {
+		return *p //want "unassigned variable `p` dereferenced"
+	}
+	return 0
+}
+
+func constNonConstOperand() int {
+	var p *int
+	if verbose && dummy {
+		return *p //want "unassigned variable `p` dereferenced"
+	}
+	return 0
+}
+
+func constGOOS() int {
+	var p *int
+	if runtime.GOOS == "plan9" && runtime.GOOS == "windows" {
+		return *p
+	}
+	return 0
+}
+
+func constSwitch() int {
+	var p *int
+	switch {
+	case debug:
+		return *p
+	case dummy:
+		return 1
+	}
+	return 0
+}
+
+func constDeadAssignment() int {
+	p := new(int)
+	if debug {
+		p = nil
+	}
+	return *p
+}
+
+func constLiveAssignment() int {
+	p := new(int)
+	if verbose {
+		p = nil
+	}
+	return *p //want "literal `nil` dereferenced"
+}
+
+func constDeadCall() {
+	if debug {
+		deref(nil)
+	}
+}
+
+func nonConst() int {
+	var p *int
+	if dummy {
+		return *p //want "unassigned variable `p` dereferenced"
+	}
+	return 0
+}
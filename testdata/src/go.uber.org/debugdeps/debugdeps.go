@@ -0,0 +1,28 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package debugdeps tests the debug-deps mode, which lists the upstream packages (and the objects
+// in them) that contributed facts to each error.
+package debugdeps
+
+import "go.uber.org/debugdeps/upstream"
+
+func derefUpstreamNilable() int {
+	return *upstream.Nilable() // want "Upstream packages contributing facts to this error:\n\t- go.uber.org/debugdeps/upstream: literal `nil` at \"upstream/upstream.go:20:9\" returned from `Nilable\\(\\)`"
+}
+
+func localOnly() int {
+	var p *int
+	return *p // want "dereferenced\n$"
+}
@@ -0,0 +1,27 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package noinfer tests the debug-deps mode for the errors found without inference, where the
+upstream objects whose nilabilities are checked are listed.
+
+<nilaway no inference>
+*/
+package noinfer
+
+import "go.uber.org/debugdeps/noinfer/upstream"
+
+func derefUpstreamNilable() int {
+	return *upstream.Nilable() // want "Upstream packages contributing facts to this error:\n\t- go.uber.org/debugdeps/noinfer/upstream: Result 0 of Function Nilable"
+}
@@ -0,0 +1,36 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This package tests that the debug dump mode writes the graphs of the selected functions without
+// affecting the reported errors.
+//
+// <nilaway no inference>
+package debugdump
+
+type T struct {
+	f *int
+}
+
+// nilable(t)
+func Dumped(t *T) int {
+	if t != nil && t.f != nil {
+		return *t.f
+	}
+	return *t.f //want "accessed field `f`"
+}
+
+// nilable(t)
+func (t *T) NotDumped() int {
+	return *t.f //want "accessed field `f`"
+}
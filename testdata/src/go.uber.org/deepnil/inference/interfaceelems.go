@@ -0,0 +1,76 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This file tests the deep nilability of local containers of interfaces (e.g., `[]error`), where
+// the (possibly nil) concrete pointers stored into them flow to the uses of their elements.
+
+package inference
+
+import "io"
+
+type nilErr struct{}
+
+func (e *nilErr) Error() string {
+	return "nil error"
+}
+
+func (e *nilErr) Read([]byte) (int, error) {
+	return 0, nil
+}
+
+func retNilErr() *nilErr {
+	return nil
+}
+
+func firstError(errs []error) string {
+	return errs[0].Error() //want "called `Error\\(\\)`"
+}
+
+func testErrorSliceArg() {
+	errs := make([]error, 1)
+	errs[0] = retNilErr()
+	firstError(errs)
+}
+
+func retErrorSlice() []error {
+	errs := make([]error, 1)
+	errs[0] = retNilErr()
+	return errs
+}
+
+func testErrorSliceResult() {
+	_ = retErrorSlice()[0].Error() //want "called `Error\\(\\)`"
+}
+
+func readFirst(readers map[string]io.Reader) {
+	if r, ok := readers["first"]; ok {
+		_, _ = r.Read(make([]byte, 8)) //want "called `Read\\(\\)`"
+	}
+}
+
+func testReaderMapArg() {
+	readers := make(map[string]io.Reader)
+	readers["first"] = retNilErr()
+	readFirst(readers)
+}
+
+func lastError(errs []error) string {
+	return errs[len(errs)-1].Error()
+}
+
+func testErrorSliceNonnil() {
+	errs := make([]error, 1)
+	errs[0] = &nilErr{}
+	lastError(errs)
+}
@@ -0,0 +1,57 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// These tests check that the deep nilability inferred for one field (or param, or result) of a
+// named slice type does not spill over to the other fields of the same type.
+
+package inference
+
+type ptrs []*int
+
+type holdsPtrs struct {
+	nilables ptrs
+	nonnils  ptrs
+}
+
+func fillPtrs(h *holdsPtrs) {
+	h.nilables[0] = nil
+	h.nonnils[0] = new(int)
+}
+
+func derefPtrsFields(h *holdsPtrs) {
+	_ = *h.nilables[0] //want "dereferenced"
+	_ = *h.nonnils[0]
+}
+
+func derefPtrsParams(nilables, nonnils ptrs) {
+	_ = *nilables[0] //want "dereferenced"
+	_ = *nonnils[0]
+}
+
+func passPtrsFields(h *holdsPtrs) {
+	derefPtrsParams(h.nilables, h.nonnils)
+}
+
+func retNilablePtrs(h *holdsPtrs) ptrs {
+	return h.nilables
+}
+
+func retNonnilPtrs(h *holdsPtrs) ptrs {
+	return h.nonnils
+}
+
+func derefPtrsResults(h *holdsPtrs) {
+	_ = *retNilablePtrs(h)[0] //want "dereferenced"
+	_ = *retNonnilPtrs(h)[0]
+}
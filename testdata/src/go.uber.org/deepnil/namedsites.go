@@ -0,0 +1,94 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// These tests check that the fields, params, and results of named slice types have their own deep
+// nilability, which defaults to the deep nilability annotated at the named types.
+
+package deepnil
+
+// nilable(N[])
+type N []*int
+
+type M []*int
+
+// nonnil(n1, n2, m1, m2, n1[], m1[])
+// nilable(m2[])
+type holdsNamed struct {
+	n1 N
+	n2 N
+	m1 M
+	m2 M
+}
+
+func readNamedFields(h *holdsNamed) *int {
+	switch 0 {
+	case 1:
+		return h.n1[0]
+	case 2:
+		return h.n2[0] //want "returned"
+	case 3:
+		return h.m1[0]
+	default:
+		return h.m2[0] //want "returned"
+	}
+}
+
+func writeNamedFields(h *holdsNamed) {
+	h.n1[0] = nil //want "assigned"
+	h.n2[0] = nil
+	h.m1[0] = nil //want "assigned"
+	h.m2[0] = nil
+}
+
+// nonnil(n1, n2, m1, m2, n1[], m1[])
+// nilable(m2[])
+func readNamedParams(n1, n2 N, m1, m2 M) *int {
+	switch 0 {
+	case 1:
+		return n1[0]
+	case 2:
+		return n2[0] //want "returned"
+	case 3:
+		return m1[0]
+	default:
+		return m2[0] //want "returned"
+	}
+}
+
+// nonnil(result 0, result 0[])
+func retNonnilN() N {
+	return N{new(int)}
+}
+
+// nonnil(result 0)
+func retN() N {
+	return N{new(int)}
+}
+
+// nonnil(result 0)
+// nilable(result 0[])
+func retNilableM() M {
+	return M{nil}
+}
+
+func readNamedResults() *int {
+	switch 0 {
+	case 1:
+		return retNonnilN()[0]
+	case 2:
+		return retN()[0] //want "returned"
+	default:
+		return retNilableM()[0] //want "returned"
+	}
+}
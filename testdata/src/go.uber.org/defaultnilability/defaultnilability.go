@@ -0,0 +1,71 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This package tests that the parameters of the functions not called in the package are assumed
+// nilable with `-default-nilability=pessimistic`, such that their unguarded dereferences are
+// reported.
+package defaultnilability
+
+type T struct {
+	f int
+}
+
+func Exported(t *T) int {
+	return t.f //want "NILABLE because it is unconstrained and assumed so by default(.|\n)*function parameter `t` accessed field `f`"
+}
+
+func unexported(t *T) int {
+	return t.f //want "NILABLE because it is unconstrained and assumed so by default(.|\n)*function parameter `t` accessed field `f`"
+}
+
+func Guarded(t *T) int {
+	if t == nil {
+		return 0
+	}
+	return t.f
+}
+
+// The parameters of the functions called in the package are constrained by the arguments instead.
+func called(t *T) int {
+	return t.f
+}
+
+func Caller() int {
+	return called(&T{})
+}
+
+// The parameters of the functions referenced in the package (e.g., as callbacks) are constrained
+// as well, since the arguments are unknown.
+func callback(t *T) int {
+	return t.f
+}
+
+func Register() func(*T) int {
+	return callback
+}
+
+// The annotated parameters are not affected.
+// nonnil(t)
+func Annotated(t *T) int {
+	return t.f
+}
+
+// Flows from the parameters are reported where they are dereferenced.
+func passThrough(t *T) *T {
+	return t
+}
+
+func ExportedPassThrough(t *T) int {
+	return passThrough(t).f //want "NILABLE because it is unconstrained and assumed so by default(.|\n)*accessed field `f`"
+}
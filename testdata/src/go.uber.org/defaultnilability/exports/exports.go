@@ -0,0 +1,33 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This package tests that only the parameters of the exported functions not called in the package
+// are assumed nilable with `-default-nilability=pessimistic-exports`.
+package exports
+
+type T struct {
+	f int
+}
+
+func Exported(t *T) int {
+	return t.f //want "NILABLE because it is unconstrained and assumed so by default(.|\n)*function parameter `t` accessed field `f`"
+}
+
+func (t *T) Method(other *T) int {
+	return other.f //want "NILABLE because it is unconstrained and assumed so by default(.|\n)*function parameter `other` accessed field `f`"
+}
+
+func unexported(t *T) int {
+	return t.f
+}
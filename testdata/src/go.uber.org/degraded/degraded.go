@@ -0,0 +1,35 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This package aims to test the degraded mode under memory pressure, which is forced for this
+// package by a tiny memory pressure threshold. The struct initialization checks are enabled but
+// skipped in the degraded mode.
+package degraded //want "package analyzed in degraded mode due to memory pressure"
+
+type A struct {
+	ptr  *int
+	aptr *A
+}
+
+func testStructInitSkipped() {
+	// The struct initialization checks would report the access of the uninitialized field `ptr`
+	// instead.
+	b := &A{} //want "uninitialized field `aptr` omitted from the composite literal"
+	print(b.aptr.ptr)
+}
+
+func testStillReported() {
+	var p *int
+	print(*p) //want "dereferenced"
+}
@@ -0,0 +1,126 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dependencyinjection tests that the parameters of the constructors registered with the
+// dependency injection frameworks (fx, dig and wire) and the fields of their parameter objects are
+// assumed nonnil, since they are populated by the frameworks. Hence, the nil values passed to them
+// by the other callers (e.g., in tests) are reported at the callers instead of the constructors.
+package dependencyinjection
+
+import (
+	"github.com/google/wire"
+	"go.uber.org/dig"
+	"go.uber.org/fx"
+)
+
+type Logger struct {
+	name string
+}
+
+func NewServer(l *Logger) string { //want "literal `nil` passed as arg `l` to `NewServer\\(\\)`"
+	return l.name
+}
+
+func NewAnnotatedServer(l *Logger) string { //want "literal `nil` passed as arg `l` to `NewAnnotatedServer\\(\\)`"
+	return l.name
+}
+
+func register(l *Logger) { //want "literal `nil` passed as arg `l` to `register\\(\\)`"
+	print(l.name)
+}
+
+var Module = fx.Provide(
+	NewServer,
+	fx.Annotate(NewAnnotatedServer, fx.ParamTags(`name:"annotated"`)),
+)
+
+var Invocations = fx.Invoke(register)
+
+func callWithNil() {
+	NewServer(nil)
+	NewAnnotatedServer(nil)
+	register(nil)
+}
+
+// The functions not registered with the frameworks are not affected.
+func unregistered(l *Logger) string {
+	return l.name //want "accessed field `name`"
+}
+
+func callUnregisteredWithNil() {
+	unregistered(nil)
+}
+
+// The fields of the parameter objects are populated as well, except for the optional ones that
+// are left nil if not provided.
+
+type Params struct {
+	fx.In
+
+	Logger   *Logger //want "literal `nil` assigned into field `Logger`"
+	Fallback *Logger `optional:"true"`
+}
+
+func NewHandler(p Params) string {
+	return p.Logger.name + p.Fallback.name //want "accessed field `name`"
+}
+
+func callHandlerWithNil() {
+	p := Params{Logger: &Logger{}}
+	p.Logger = nil
+	NewHandler(p)
+}
+
+type DigParams struct {
+	dig.In
+
+	Logger *Logger //want "literal `nil` assigned into field `Logger`"
+}
+
+func newStore(p DigParams, l *Logger) string { //want "literal `nil` passed as arg `l` to `newStore\\(\\)`"
+	return p.Logger.name + l.name
+}
+
+func Container() {
+	c := dig.New()
+	_ = c.Provide(newStore)
+	p := DigParams{Logger: &Logger{}}
+	p.Logger = nil
+	newStore(p, nil)
+}
+
+// wire populates the parameters of the providers in the sets and the listed fields of the structs.
+
+func NewCache(l *Logger) string { //want "literal `nil` passed as arg `l` to `NewCache\\(\\)`"
+	return l.name
+}
+
+type Deps struct {
+	Logger   *Logger //want "literal `nil` assigned into field `Logger`"
+	Fallback *Logger
+}
+
+func (d *Deps) Names() string {
+	return d.Logger.name + d.Fallback.name //want "accessed field `name`"
+}
+
+var Set = wire.NewSet(NewCache, wire.Struct(new(Deps), "Logger"))
+
+func callWireWithNil() {
+	NewCache(nil)
+	d := &Deps{Logger: &Logger{}, Fallback: &Logger{}}
+	d.Logger = nil
+	d.Fallback = nil
+	print(d.Names())
+}
@@ -0,0 +1,112 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package devirtualization tests that the calls to the methods of the interfaces with a single
+// implementation injected in the package are analyzed with the contracts of the implementation.
+package devirtualization
+
+import "math/rand"
+
+type getter interface {
+	get(x *int) *int
+}
+
+type store struct{}
+
+// contract(nonnil -> nonnil)
+func (*store) get(x *int) *int {
+	if x != nil {
+		return x
+	}
+	if rand.Float64() > 0.5 {
+		return new(int)
+	}
+	return nil
+}
+
+type server struct {
+	g getter
+}
+
+func newServer(g getter) *server {
+	return &server{g: g}
+}
+
+func testSingleImplementation() {
+	s := newServer(&store{})
+	n := 1
+	// No error since `store` is the only implementation of `getter`, whose contract applies here.
+	print(*s.g.get(&n))
+}
+
+func testSingleImplementationNil(s *server) {
+	var p *int
+	print(*s.g.get(p)) // want "result 0 of `get\\(\\)` at .* dereferenced"
+}
+
+type validator interface {
+	valid(x *int) bool
+}
+
+type checker struct{}
+
+// contract(nil -> false)
+func (*checker) valid(x *int) bool {
+	return x != nil && *x > 0
+}
+
+func testNilCheckContract() int {
+	var v validator = &checker{}
+	var x *int
+	if rand.Float64() > 0.5 {
+		x = new(int)
+	}
+	if v.valid(x) {
+		// No error since the handwritten contract of `checker.valid` applies here.
+		return *x
+	}
+	return 0
+}
+
+type finder interface {
+	find(x *int) *int
+}
+
+type cache struct{}
+
+func (*cache) find(x *int) *int {
+	if x != nil {
+		return x
+	}
+	return nil
+}
+
+type database struct{}
+
+func (*database) find(x *int) *int {
+	if x != nil {
+		return x
+	}
+	return nil
+}
+
+func testMultipleImplementations(useCache bool) {
+	var f finder = &database{}
+	if useCache {
+		f = &cache{}
+	}
+	n := 1
+	// The contracts of the implementations are not applied since there are two of them.
+	print(*f.find(&n)) // want "implemented by `cache.find\\(\\)`" "implemented by `database.find\\(\\)`"
+}
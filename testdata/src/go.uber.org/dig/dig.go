@@ -0,0 +1,31 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dig is a minimal stub of go.uber.org/dig for testing.
+package dig
+
+// In is embedded in the parameter objects whose fields are populated by the container.
+type In struct{}
+
+// Container is a dependency injection container.
+type Container struct{}
+
+// New returns a new container.
+func New() *Container { return &Container{} }
+
+// Provide registers the constructor with the container.
+func (c *Container) Provide(constructor interface{}) error { return nil }
+
+// Invoke calls the function with its parameters populated by the container.
+func (c *Container) Invoke(function interface{}) error { return nil }
@@ -12,7 +12,8 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// This package tests error messages for assignment flow tracking.
+// This package tests error messages for assignment flow tracking, where the chains of copies
+// through the trivial single-use temporaries are merged by default (see -assignment-flows).
 
 // <nilaway no inference>
 package errormessage
@@ -30,7 +31,7 @@ func test2(x *int) {
 	x = nil
 	y := x
 	z := y
-	print(*z) //want "`y` to `z`"
+	print(*z) //want "`nil` to `z` via `x`, `y`"
 }
 
 func test3(x *int) {
@@ -41,7 +42,7 @@ func test3(x *int) {
 	}
 	y := x
 	z := y
-	print(*z) //want "`nil` to `x`"
+	print(*z) //want "`nil` to `z` via `x`, `y`"
 }
 
 // nilable(f)
@@ -61,7 +62,7 @@ func test4(x *int) {
 func test5() {
 	x := new(int)
 	for i := 0; i < 10; i++ {
-		print(*x) //want "`nil` to `y`"
+		print(*x) //want "`nil` to `x` via `y`, `z`"
 		var y *int = nil
 		z := y
 		x = z
@@ -72,7 +73,7 @@ func test6() *int {
 	var x *int = nil
 	y := x
 	z := y
-	return z //want "`nil` to `x`"
+	return z //want "`nil` to `z` via `x`, `y`"
 }
 
 func test7() {
@@ -96,7 +97,7 @@ func test8() {
 func test9(m map[int]*int) {
 	x, _ := m[0]
 	y := x
-	print(*y) //want "`m\\[0\\]` to `x`"
+	print(*y) //want "`m\\[0\\]` to `y` via `x`"
 }
 
 // nilable(nilableChan) nonnil(nonnilDeeplyNonnilChan, <-nonnilDeeplyNonnilChan)
@@ -160,7 +161,7 @@ func test14(x *int, i int) {
 		"abc",
 		true).bar(i)
 	y := x
-	print(*y) //want "`s.foo\\(...\\).bar\\(i\\)` to `x`"
+	print(*y) //want "`s.foo\\(...\\).bar\\(i\\)` to `y` via `x`"
 }
 
 func test15(x *int) {
@@ -168,14 +169,14 @@ func test15(x *int) {
 	s := &S{}
 	x = s.foo(longVarName, &anotherLongVarName, "abc", true).bar(yetAnotherLongName)
 	y := x
-	print(*y) //want "`s.foo\\(...\\).bar\\(...\\)` to `x`"
+	print(*y) //want "`s.foo\\(...\\).bar\\(...\\)` to `y` via `x`"
 }
 
 func test16(mp map[int]*int) {
 	var aVeryVeryVeryLongIndexVar int
 	x := mp[aVeryVeryVeryLongIndexVar]
 	y := x
-	print(*y) //want "`mp\\[...\\]` to `x`"
+	print(*y) //want "`mp\\[...\\]` to `y` via `x`"
 }
 
 func test17(x *int, mp map[int]*int) {
@@ -184,49 +185,49 @@ func test17(x *int, mp map[int]*int) {
 
 	x = s.foo(1, mp[aVeryVeryVeryLongIndexVar], "abc", true).bar(2) //want "deep read"
 	y := x
-	print(*y) //want "`s.foo\\(...\\).bar\\(2\\)` to `x`"
+	print(*y) //want "`s.foo\\(...\\).bar\\(2\\)` to `y` via `x`"
 }
 
 func test18(x *int, mp map[int]*int) {
 	s := &S{}
 	x = mp[*(s.foo(1, new(int), "abc", true).bar(2))] //want "dereferenced"
 	y := x
-	print(*y) //want "`mp\\[...\\]` to `x`"
+	print(*y) //want "`mp\\[...\\]` to `y` via `x`"
 }
 
 func test19() {
 	mp := make(map[string]*string)
 	x := mp["("]
 	y := x
-	print(*y) //want "`mp\\[\"\\(\"\\]` to `x`"
+	print(*y) //want "`mp\\[\"\\(\"\\]` to `y` via `x`"
 
 	x = mp[")"]
 	y = x
-	print(*y) //want "`mp\\[\"\\)\"\\]` to `x`"
+	print(*y) //want "`mp\\[\"\\)\"\\]` to `y` via `x`"
 
 	x = mp["))"]
 	y = x
-	print(*y) //want "`mp\\[...\\]` to `x`"
+	print(*y) //want "`mp\\[...\\]` to `y` via `x`"
 
 	x = mp["(("]
 	y = x
-	print(*y) //want "`mp\\[...\\]` to `x`"
+	print(*y) //want "`mp\\[...\\]` to `y` via `x`"
 
 	x = mp[")))((("]
 	y = x
-	print(*y) //want "`mp\\[...\\]` to `x`"
+	print(*y) //want "`mp\\[...\\]` to `y` via `x`"
 
 	x = mp[")))((("]
 	y = x
-	print(*y) //want "`mp\\[...\\]` to `x`"
+	print(*y) //want "`mp\\[...\\]` to `y` via `x`"
 
 	x = mp["(((()"]
 	y = x
-	print(*y) //want "`mp\\[...\\]` to `x`"
+	print(*y) //want "`mp\\[...\\]` to `y` via `x`"
 
 	x = mp["())))"]
 	y = x
-	print(*y) //want "`mp\\[...\\]` to `x`"
+	print(*y) //want "`mp\\[...\\]` to `y` via `x`"
 
 	s := &S{}
 	i := 0
@@ -235,18 +236,18 @@ func test19() {
 		"({[",
 		true).bar(i)
 	b := a
-	print(*b) //want "`s.foo\\(...\\).bar\\(i\\)` to `a`"
+	print(*b) //want "`s.foo\\(...\\).bar\\(i\\)` to `b` via `a`"
 }
 
 func test20() {
 	mp := make(map[rune]*rune)
 	x := mp['(']
 	y := x
-	print(*y) //want "`mp\\['\\('\\]` to `x`"
+	print(*y) //want "`mp\\['\\('\\]` to `y` via `x`"
 
 	x = mp[')']
 	y = x
-	print(*y) //want "`mp\\['\\)'\\]` to `x`"
+	print(*y) //want "`mp\\['\\)'\\]` to `y` via `x`"
 }
 
 // below test checks that NilAway can handle non-English (non-ASCII) identifiers
@@ -274,7 +275,7 @@ func test22(i int) {
 	case 1:
 		if x, err := retPtrErr(); err == nil {
 			y := x
-			print(*y) //want "`retPtrErr\\(\\)` to `x`"
+			print(*y) //want "`retPtrErr\\(\\)` to `y` via `x`"
 		}
 
 	case 2:
@@ -334,7 +335,7 @@ func test24() {
 		b = a
 	}
 	print(*a) //want "`retMultiple\\(\\)` to `a`"
-	print(*b) //want "`a` to `b`"
+	print(*b) //want "`retMultiple\\(\\)` to `b` via `a`"
 	print(*c) //want "`retMultiple\\(\\)` to `c`"
 }
 
@@ -0,0 +1,62 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package contract tests the callers of the upstream functions with error return contracts: the
+// results used under an error check are safe if they are nonnil whenever the error is nil, while
+// the results used without checking the error are reported.
+package contract
+
+import "go.uber.org/errorreturn/contract/upstream"
+
+func guarded() int {
+	t, err := upstream.New(true)
+	if err != nil {
+		return 0
+	}
+	return t.F
+}
+
+func unguarded() int {
+	t, _ := upstream.New(true)
+	return t.F //want "result 0 of `New.*` lacking guarding"
+}
+
+func guardedNoContract() int {
+	t, err := upstream.Maybe(true)
+	if err != nil {
+		return 0
+	}
+	return t.F //want "result 0 of `Maybe.*`"
+}
+
+func guardedPartialContract() int {
+	a, b, err := upstream.Pair(true)
+	if err != nil {
+		return 0
+	}
+	return b.F + a.F //want "result 0 of `Pair.*`"
+}
+
+func guardedMethod(t *upstream.T) int {
+	opened, err := t.Open()
+	if err != nil {
+		return 0
+	}
+	return opened.F
+}
+
+func unguardedMethod(t *upstream.T) int {
+	opened, _ := t.Open()
+	return opened.F //want "result 0 of `Open.*` lacking guarding"
+}
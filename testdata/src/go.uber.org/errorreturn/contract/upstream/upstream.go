@@ -0,0 +1,55 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package upstream declares error-returning functions whose error return contracts (i.e., which
+// results are nonnil whenever the error is nil) are exported to the downstream packages.
+package upstream
+
+import "errors"
+
+type T struct {
+	F int
+}
+
+// New has the contract "err == nil => nonnil(result 0)".
+func New(fail bool) (*T, error) {
+	if fail {
+		return nil, errors.New("failed")
+	}
+	return &T{}, nil
+}
+
+// Maybe returns a nil result with a nil error, so it has no contract.
+func Maybe(fail bool) (*T, error) {
+	if fail {
+		return nil, nil
+	}
+	return &T{}, nil
+}
+
+// Pair has the contract "err == nil => nonnil(result 1)" only.
+func Pair(fail bool) (*T, *T, error) {
+	if fail {
+		return nil, nil, errors.New("failed")
+	}
+	return nil, &T{}, nil
+}
+
+// Open has the contract "err == nil => nonnil(result 0)".
+func (t *T) Open() (*T, error) {
+	if t.F == 0 {
+		return nil, errors.New("closed")
+	}
+	return t, nil
+}
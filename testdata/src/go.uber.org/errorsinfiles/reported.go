@@ -0,0 +1,22 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package errorsinfiles tests that the errors are only reported in the files matching the
+// include-errors-in-files flag but not the exclude-errors-in-files flag, regardless of the driver.
+package errorsinfiles
+
+func reported() int {
+	var x *int
+	return *x //want "unassigned variable `x` dereferenced"
+}
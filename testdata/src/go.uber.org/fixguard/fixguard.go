@@ -0,0 +1,59 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This package aims to test the nil guards suggested by `-fix-mode=guard` under the default
+// ("auto") policy. The expected results of the fixes are in the golden file. Note that the
+// dereferences of `retNil()` are grouped under the first diagnostic, which then guards all of them.
+package fixguard
+
+type S struct {
+	f *int
+}
+
+func retNil() *S {
+	return nil
+}
+
+func errReturning() (int, *S, error) {
+	s := retNil()
+	print(s.f) //want "accessed field `f`"
+	return 0, s, nil
+}
+
+func wrapped() {
+	s := retNil()
+	print(s.f)
+}
+
+func declares() int {
+	s := retNil()
+	v := s.f
+	return *v
+}
+
+func literal() {
+	var p *int
+	print(*p) //want "unassigned variable `p` dereferenced"
+}
+
+// The if statement is the terminating statement of the function, wrapping it in a nil check would
+// leave the function without a final return, so the guard panics instead.
+func terminating() int {
+	var p *int
+	if *p > 0 { //want "unassigned variable `p` dereferenced"
+		return 1
+	} else {
+		return 2
+	}
+}
@@ -0,0 +1,68 @@
+//  Copyright (c) 2023 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This file tests the inferred contract(recv -> recv) of the methods returning their receivers,
+// such that the chained calls of builders after a nonnil constructor are nonnil.
+
+package inference
+
+type config struct {
+	v int
+}
+
+type builder struct {
+	x   *int
+	cfg *config
+}
+
+func newBuilder() *builder {
+	return &builder{cfg: &config{}}
+}
+
+func (b *builder) withX(x *int) *builder {
+	b.x = x
+	return b
+}
+
+func (b *builder) withNothing() *builder {
+	return b
+}
+
+func (b *builder) build() int {
+	return b.cfg.v // want "read by method receiver `b` accessed field `cfg`"
+}
+
+func testChain() int {
+	n := 1
+	// No error since the results of the chained calls are the nonnil builder itself.
+	return newBuilder().withX(&n).withNothing().build()
+}
+
+func testChainLocal() int {
+	b := newBuilder()
+	b2 := b.withNothing()
+	return b2.build()
+}
+
+func testNilChain() int {
+	var b *builder
+	// The receiver of `build` is the nil builder itself.
+	return b.withNothing().build()
+}
+
+func testNilReceiver() *builder {
+	var b *builder
+	// Calling the receiver-returning method on a nil receiver does not affect other chains.
+	return b.withNothing()
+}
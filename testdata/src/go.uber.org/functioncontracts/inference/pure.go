@@ -0,0 +1,104 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inference
+
+// Tests for pure functions (i.e., `contract(pure)`), the repeated calls to which with the same
+// arguments are assumed to return the same value. Trivial getters are inferred to be pure.
+
+type entry struct {
+	v int
+}
+
+type cache struct {
+	m     map[string]*entry
+	items []*entry
+	i     int
+}
+
+// get is a trivial getter, which is inferred to be pure.
+func (c *cache) get(k string) *entry {
+	return c.m[k]
+}
+
+// next is not pure since it modifies the cache.
+func (c *cache) next() *entry {
+	c.i++
+	if c.i >= len(c.items) {
+		return nil
+	}
+	return c.items[c.i]
+}
+
+// contract(pure)
+func (c *cache) lookup(k string) *entry {
+	if e, ok := c.m[k]; ok {
+		return e
+	}
+	return nil
+}
+
+// lookupImpure is not a trivial getter and has no written contract(pure).
+func (c *cache) lookupImpure(k string) *entry {
+	if e, ok := c.m[k]; ok {
+		return e
+	}
+	return nil
+}
+
+func key() string {
+	return "key"
+}
+
+func testPureGetter(c *cache) int {
+	if c.get("a") != nil {
+		return c.get("a").v
+	}
+	return 0
+}
+
+func testPureGetterDifferentArgs(c *cache) int {
+	if c.get("a") != nil {
+		return c.get("b").v //want "accessed field `v`"
+	}
+	return 0
+}
+
+func testImpureFunc(c *cache) int {
+	if c.next() != nil {
+		return c.next().v //want "accessed field `v`"
+	}
+	return 0
+}
+
+func testWrittenPureContract(c *cache) int {
+	if c.lookup("a") != nil {
+		return c.lookup("a").v
+	}
+	return 0
+}
+
+func testNotTrivialGetter(c *cache) int {
+	if c.lookupImpure("a") != nil {
+		return c.lookupImpure("a").v //want "accessed field `v`"
+	}
+	return 0
+}
+
+func testPureFuncAsIndex(m map[string]*entry) int {
+	if m[key()] != nil {
+		return m[key()].v
+	}
+	return 0
+}
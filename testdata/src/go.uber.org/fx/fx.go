@@ -0,0 +1,39 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fx is a minimal stub of go.uber.org/fx for testing.
+package fx
+
+import "go.uber.org/dig"
+
+// In is embedded in the parameter objects whose fields are populated by the application.
+type In = dig.In
+
+// Option configures an application.
+type Option interface{}
+
+// Provide registers the constructors with the application.
+func Provide(constructors ...interface{}) Option { return nil }
+
+// Invoke registers the functions to call with their parameters populated by the application.
+func Invoke(funcs ...interface{}) Option { return nil }
+
+// Annotation annotates a constructor.
+type Annotation interface{}
+
+// Annotate annotates the constructor.
+func Annotate(t interface{}, anns ...Annotation) interface{} { return t }
+
+// ParamTags is an annotation for the tags of the parameters of the constructor.
+func ParamTags(tags ...string) Annotation { return nil }
@@ -0,0 +1,72 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package genericinstances tests that the errors repeated for the instantiations of the same
+// generic code are reported once.
+package genericinstances
+
+// The parameters of the generic functions.
+
+func Deref[T any](p *T) T {
+	return *p //want "Reported once for the 3 instantiations of the generic code affected"
+}
+
+func derefInt() int {
+	var p *int
+	return Deref(p)
+}
+
+func derefString() string {
+	var p *string
+	return Deref(p)
+}
+
+func derefFloat() float64 {
+	var p *float64
+	return Deref(p)
+}
+
+// The fields of the generic types.
+
+type Box[T any] struct {
+	v *T
+}
+
+func (b *Box[T]) Load() T {
+	return *b.v //want "Reported once for the 2 instantiations of the generic code affected"
+}
+
+func resetInt(b *Box[int]) {
+	b.v = nil
+}
+
+func resetString(b *Box[string]) {
+	b.v = nil
+}
+
+// The errors of a single instantiation are reported as usual.
+
+func Value[T any](p *T) T {
+	return *p //want "passed as arg `p` to `Value\\(\\)`" "passed as arg `p` to `Value\\(\\)`"
+}
+
+func valueInt() int {
+	var p *int
+	return Value(p)
+}
+
+func valueIntAgain() int {
+	var q *int
+	return Value(q)
+}
@@ -0,0 +1,146 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This package aims to test the experimental heuristic for the results populated by the
+// goroutines of an `errgroup.Group` (or a `sync.WaitGroup`), which are assumed to be nonnil once
+// the group is waited for.
+package groupresults
+
+import (
+	"errors"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+type T struct{ n int }
+
+func fetch(i int) (*T, error) {
+	if i < 0 {
+		return nil, errors.New("negative")
+	}
+	return &T{n: i}, nil
+}
+
+func testVar() (int, error) {
+	var res *T
+	var g errgroup.Group
+	g.Go(func() error {
+		var err error
+		res, err = fetch(1)
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return 0, err
+	}
+	return res.n, nil
+}
+
+func testVars() (int, error) {
+	var a, b *T
+	var g errgroup.Group
+	g.Go(func() (err error) {
+		a, err = fetch(1)
+		return err
+	})
+	g.TryGo(func() (err error) {
+		b, err = fetch(2)
+		return err
+	})
+	if g.Wait() != nil {
+		return 0, errors.New("failed")
+	}
+	return a.n + b.n, nil
+}
+
+func testIndices(n int) (int, error) {
+	results := make(map[int]*T, n)
+	var mu sync.Mutex
+	var g errgroup.Group
+	for i := 0; i < n; i++ {
+		g.Go(func() error {
+			r, err := fetch(i)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			results[i] = r
+			return nil
+		})
+	}
+	err := g.Wait()
+	if err != nil {
+		return 0, err
+	}
+	return results[0].n, nil
+}
+
+func testWaitGroup() int {
+	var res *T
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		res = &T{}
+	}()
+	wg.Wait()
+	return res.n
+}
+
+func testErrorDiscarded() int {
+	var res *T
+	var g errgroup.Group
+	g.Go(func() error {
+		var err error
+		res, err = fetch(1)
+		return err
+	})
+	_ = g.Wait()
+	return res.n //want "unassigned variable `res`"
+}
+
+func testReadBeforeWait() (int, error) {
+	var res *T
+	var g errgroup.Group
+	g.Go(func() error {
+		var err error
+		res, err = fetch(1)
+		return err
+	})
+	n := res.n //want "unassigned variable `res`"
+	if err := g.Wait(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func testOtherGroup() (int, error) {
+	var res, other *T
+	var g, h errgroup.Group
+	g.Go(func() error {
+		var err error
+		res, err = fetch(1)
+		return err
+	})
+	h.Go(func() error {
+		var err error
+		other, err = fetch(2)
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return 0, err
+	}
+	return res.n + other.n, nil //want "unassigned variable `other`"
+}
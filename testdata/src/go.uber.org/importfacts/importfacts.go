@@ -0,0 +1,56 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package importfacts tests the externally produced nilness facts imported from the facts file
+// facts/go.uber.org/importfacts.json, which seed the inference.
+package importfacts
+
+// The result is asserted to be nilable by the imported facts.
+func nilableByFact() *int {
+	return new(int)
+}
+
+func derefNilableByFact() int {
+	return *nilableByFact() // want "dereferenced"
+}
+
+// The parameter is asserted to be nonnil by the imported facts, the conflict is reported here as
+// for the annotated sites.
+func nonnilByFact(p *int) { // want "NONNIL because it is asserted as so by the imported nilness facts"
+	print(p)
+}
+
+func passNilToNonnilByFact() {
+	nonnilByFact(nil)
+}
+
+// The annotation takes precedence over the imported fact (nilable) for the result.
+// nonnil(result 0)
+func annotatedNonnil() *int {
+	return new(int)
+}
+
+func derefAnnotatedNonnil() int {
+	return *annotatedNonnil()
+}
+
+// The imported fact for the result does not match its position (e.g., the fact is stale), hence
+// it is ignored.
+func staleFact() *int {
+	return new(int)
+}
+
+func derefStaleFact() int {
+	return *staleFact()
+}
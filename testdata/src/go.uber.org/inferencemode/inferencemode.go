@@ -0,0 +1,49 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This package tests that with `-inference-mode=single-package` every package is checked on its
+// own against the annotations in it, where the sites not annotated are nonnil by default and no
+// facts of the upstream packages are observed.
+package inferencemode
+
+import "go.uber.org/inferencemode/upstream"
+
+// The nil flows from the upstream packages are missed, since the upstream sites are assumed to be
+// nonnil without the facts.
+func derefUpstream() int {
+	return *upstream.SometimesNil(true)
+}
+
+// The local sites not annotated are nonnil by default, hence the nil returned is reported at the
+// return instead of the dereference.
+func sometimesNil(b bool) *int {
+	if b {
+		return nil //want "literal `nil` returned from `sometimesNil\\(\\)` in position 0"
+	}
+	return new(int)
+}
+
+func derefLocal() int {
+	return *sometimesNil(true)
+}
+
+// nilable(result 0)
+func annotatedNilable() *int {
+	return nil
+}
+
+// The local annotations are still respected.
+func derefAnnotated() int {
+	return *annotatedNilable() //want "result 0 of `annotatedNilable\\(\\)`"
+}
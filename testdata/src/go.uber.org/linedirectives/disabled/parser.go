@@ -0,0 +1,29 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This file simulates the code generated by yacc from the grammar in "parser.y" (except for the
+// "Code generated" header, since the generated files are excluded in the tests).
+
+package disabled
+
+func parse() *int {
+//line parser.y:4
+	return nil
+}
+
+func action() {
+	var p *int
+//line parser.y:12
+	print(*p) // want "disabled/parser.go:28:9: unassigned variable `p` dereferenced"
+}
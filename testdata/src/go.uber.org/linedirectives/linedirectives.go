@@ -0,0 +1,22 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This package tests that the positions in the error messages are adjusted by the "//line"
+// directives in the generated code (e.g., by yacc), such that they point at the authored sources.
+
+package linedirectives
+
+func deref() {
+	print(*parse()) // want "linedirectives/parser.y:4: literal `nil` returned"
+}
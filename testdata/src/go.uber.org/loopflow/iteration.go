@@ -0,0 +1,159 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Tests for values carried from the previous iteration of a loop, which are only known to be
+// assigned when the loop index shows that the current iteration is not the first one.
+
+package loopflow
+
+type node struct {
+	v    int
+	next *node
+}
+
+// nonnil(xs[])
+func linkAfterFirst(xs []*node) {
+	var prev *node
+	for i, cur := range xs {
+		if i > 0 {
+			prev.next = cur
+		}
+		prev = cur
+	}
+}
+
+// nonnil(xs[])
+func sumPrevMirrored(xs []*node) int {
+	s := 0
+	var prev *node
+	for i, cur := range xs {
+		if 0 < i {
+			s += prev.v
+		}
+		if i != 0 {
+			s += prev.v
+		}
+		if i >= 1 {
+			s += prev.v
+		}
+		prev = cur
+	}
+	return s
+}
+
+func countedLoop(n int) int {
+	s := 0
+	var last *node
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			s += last.v
+		}
+		last = &node{v: i}
+	}
+	return s
+}
+
+// nonnil(xs[])
+func accumulateWithNilCheck(xs []*node) int {
+	s := 0
+	var last *node
+	for _, cur := range xs {
+		if last != nil {
+			s += last.v
+		}
+		last = cur
+	}
+	return s
+}
+
+// nonnil(xs[])
+func noIterationCheck(xs []*node) int {
+	s := 0
+	var last *node
+	for _, cur := range xs {
+		s += last.v //want "unassigned variable `last` accessed field `v`"
+		last = cur
+	}
+	return s
+}
+
+// nonnil(xs[])
+func conditionallyAssigned(xs []*node, b bool) int {
+	s := 0
+	var prev *node
+	for i, cur := range xs {
+		if i > 0 {
+			s += prev.v //want "unassigned variable `prev` accessed field `v`"
+		}
+		if b {
+			prev = cur
+		}
+	}
+	return s
+}
+
+// nonnil(xs[])
+func assignmentSkippedByContinue(xs []*node) int {
+	s := 0
+	var prev *node
+	for i, cur := range xs {
+		if i > 0 {
+			s += prev.v //want "unassigned variable `prev` accessed field `v`"
+		}
+		if cur.v < 0 {
+			continue
+		}
+		prev = cur
+	}
+	return s
+}
+
+func indexModifiedInBody(n int) int {
+	s := 0
+	var last *node
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			s += last.v //want "unassigned variable `last` accessed field `v`"
+		}
+		last = &node{}
+		i += 2
+	}
+	return s
+}
+
+// nonnil(m[])
+func rangeOverMap(m map[int]*node) int {
+	s := 0
+	var prev *node
+	for k, cur := range m {
+		if k > 0 {
+			s += prev.v //want "unassigned variable `prev` accessed field `v`"
+		}
+		prev = cur
+	}
+	return s
+}
+
+// nonnil(xs[])
+func nilAssignedInBody(xs []*node) int {
+	s := 0
+	var prev *node
+	for i := range xs {
+		if i > 0 {
+			s += prev.v //want "literal `nil` accessed field `v`"
+		}
+		prev = nil
+	}
+	return s
+}
@@ -0,0 +1,75 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This package aims to test the strict checking of the reads from possibly-nil maps, i.e.,
+// NilAway run with config.ErrorOnNilableMapReadFlag.
+package strict
+
+var global map[string]int
+
+func readUnassigned() int {
+	var m map[string]int
+	return m["a"] //want "unassigned variable `m` keyed into"
+}
+
+func readCommaOk() bool {
+	var m map[string]int
+	_, ok := m["a"] //want "unassigned variable `m` keyed into"
+	return ok
+}
+
+func writeUnassigned() {
+	var m map[string]int
+	m["a"] = 1 //want "written to at an index"
+}
+
+func readMade() int {
+	m := make(map[string]int)
+	return m["a"]
+}
+
+func readLiteral() int {
+	m := map[string]int{"a": 1}
+	return m["a"]
+}
+
+func readGlobal() int {
+	return global["a"] //want "global variable `global` keyed into"
+}
+
+func readParam(m map[string]int) int {
+	return m["a"] //want "function parameter `m` keyed into"
+}
+
+func callReadParam() int {
+	return readParam(nil)
+}
+
+func readChecked(m map[string]int) int {
+	if m == nil {
+		return 0
+	}
+	return m["a"]
+}
+
+// Ranging over, taking the length of, and deleting from a nil map are safe in both modes.
+func otherAccesses() int {
+	var m map[string]int
+	n := len(m)
+	for _, v := range m {
+		n += v
+	}
+	delete(m, "a")
+	return n
+}
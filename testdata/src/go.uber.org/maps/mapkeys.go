@@ -0,0 +1,66 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maps
+
+// The below tests check that the keys of the maps are consumed by their own dereferences and
+// calls, while the nilable pointers themselves can be used as keys.
+
+type Request struct {
+	ID   int
+	Conn *Conn
+}
+
+type Conn struct {
+	Addr string
+}
+
+type mutex struct{}
+
+func (m *mutex) Unlock() {}
+
+// nilable(result 0)
+func nilableRequest() *Request {
+	return nil
+}
+
+func (r *Request) key() int {
+	return r.ID
+}
+
+// nonnil(inflight, byReq, byAddr)
+func indexByKeys(inflight map[int]bool, byReq map[*Request]bool, byAddr map[string]int) {
+	req := nilableRequest()
+	// Nilable pointers are legal keys.
+	_ = byReq[req]
+	byReq[req] = true
+	delete(byReq, req)
+
+	_ = inflight[req.ID]                    //want "accessed field `ID`"
+	inflight[req.key()] = true              //want "used as receiver to call `key\\(\\)`"
+	byAddr[req.Conn.Addr]++                 //want "accessed field `Conn`"
+	if v, ok := byAddr[req.Conn.Addr]; ok { //want "accessed field `Conn`"
+		_ = v
+	}
+}
+
+// nonnil(inflight, mus)
+func deferredKeys(inflight map[int]bool, mus map[int]*mutex) {
+	req := nilableRequest()
+	// The arguments of the deferred calls and the receivers of the deferred methods are evaluated
+	// at the defer statements.
+	defer delete(inflight, req.ID) //want "accessed field `ID`"
+	other := nilableRequest()
+	defer mus[other.ID].Unlock() //want "accessed field `ID`"
+}
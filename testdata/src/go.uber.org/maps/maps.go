@@ -826,6 +826,13 @@ func retInt() int {
 	return 0
 }
 
+var counter int
+
+func retIntImpure() int {
+	counter++
+	return counter
+}
+
 type A struct {
 	f int
 	g int
@@ -890,12 +897,13 @@ func testNonLiteralMapAccess(mp map[int]*int, i, j int) {
 		}
 
 	case 10:
-		// NilAway does not consider user-defined functions as stable, and hence reports an error here. It could be
-		// considered a false positive from a user perspective, but NilAway cannot guarantee the stability of the function
-		// without a more complex analysis. We are currently not choosing to do this since we believe this to be a rare
-		// case and also an anti-pattern since users should ideally create a local variable and use that instead.
+		// NilAway considers only the pure user-defined functions (i.e., with `contract(pure)` or trivial getters
+		// such as `retInt`) as stable, and hence reports an error for the impure `retIntImpure` here.
 		if mp[retInt()] != nil {
-			print(*mp[retInt()]) //want "lacking guarding"
+			print(*mp[retInt()])
+		}
+		if mp[retIntImpure()] != nil {
+			print(*mp[retIntImpure()]) //want "lacking guarding"
 		}
 
 		localVar := retInt()
@@ -0,0 +1,129 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// These tests check that the comma-ok reads through getters wrapping map accesses, i.e.,
+// ok-returning methods on map types, act like direct comma-ok map reads `v, ok := m[k]`.
+
+package maps
+
+type wrappedMap map[int]*int
+
+// Get passes on the `ok` of reading its receiver, so `ok` being true implies that the receiver
+// map is nonnil.
+//
+// nilable(w)
+func (w wrappedMap) Get(k int) (*int, bool) {
+	v, ok := w[k]
+	return v, ok
+}
+
+// Has also reads its receiver, and returns false explicitly for the negative keys.
+//
+// nilable(w)
+func (w wrappedMap) Has(k int) (*int, bool) {
+	if k < 0 {
+		return nil, false
+	}
+	_, ok := w[k]
+	return w[k], ok
+}
+
+// Default may return true without reading its receiver, so nothing is known about the receiver
+// from its `ok`.
+//
+// nilable(w)
+func (w wrappedMap) Default(k int) (*int, bool) {
+	if v, ok := w[k]; ok {
+		return v, true
+	}
+	return new(int), true
+}
+
+// Reassigned reads a map other than its original receiver.
+//
+// nilable(w)
+func (w wrappedMap) Reassigned(k int) (*int, bool) {
+	w = globalWrappedMap
+	v, ok := w[k]
+	return v, ok
+}
+
+var globalWrappedMap wrappedMap
+
+// nilable(w)
+func wrapperEstablishesNonnil(w wrappedMap) {
+	// Calling a method on the nilable map w is safe, since the receiver of Get is nilable.
+	v, ok := w.Get(0)
+
+	// here, w and v should be nilable
+	takesNonnil(v) //want "passed"
+	takesNonnil(w) //want "passed"
+
+	switch 0 {
+	case 1:
+		if !ok {
+			return
+		}
+
+		// here, we should know that BOTH v and w are nonnil
+		takesNonnil(v)
+		takesNonnil(w)
+	case 2:
+		ok = true
+
+		if !ok {
+			return
+		}
+
+		// here, neither v nor w should be nonnil
+		takesNonnil(v) //want "passed"
+		takesNonnil(w) //want "passed"
+	case 3:
+		w = nil
+
+		if !ok {
+			return
+		}
+
+		// here, JUST v should be nonnil
+		takesNonnil(v)
+		takesNonnil(w) //want "passed"
+	}
+}
+
+// nilable(w)
+func wrapperReflCheck(w wrappedMap, i int) {
+	switch i {
+	case 0:
+		if _, ok := w.Get(0); ok {
+			takesNonnil(w)
+		}
+	case 1:
+		if _, ok := w.Has(0); ok {
+			takesNonnil(w)
+		}
+	case 2:
+		if _, ok := w.Default(0); ok {
+			takesNonnil(w) //want "passed"
+		}
+	case 3:
+		if _, ok := w.Reassigned(0); ok {
+			takesNonnil(w) //want "passed"
+		}
+	case 4:
+		if _, ok := w.Get(0); !ok {
+			takesNonnil(w) //want "passed"
+		}
+	}
+}
@@ -0,0 +1,29 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This package tests rendering the error messages with the built-in "short" message template.
+package messagetemplate
+
+func nilResult() *int {
+	return nil
+}
+
+func derefResult() int {
+	return *nilResult() //want "^Potential nil panic: result 0 of `nilResult\\(\\)` dereferenced \\(nil source: literal `nil` returned from `nilResult\\(\\)` in position 0 at \"messagetemplate/messagetemplate.go:19:9\"\\)$"
+}
+
+func derefLocal() int {
+	var p *int
+	return *p //want "^Potential nil panic: unassigned variable `p` dereferenced$"
+}
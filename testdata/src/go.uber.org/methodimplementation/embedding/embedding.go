@@ -165,7 +165,7 @@ func testRecursion() *int {
 
 // below test checks embedding of multiple interfaces within a struct, and embedding of interfaces within an interface
 type J interface {
-	bar() *int //want "returned" "returned"
+	bar() *int
 }
 
 type A9 struct {
@@ -183,13 +183,13 @@ type C9 struct{}
 
 // nilable(result 0)
 func (*C9) bar() *int {
-	return nil
+	return nil //want "returned as result"
 }
 
 func testMultipleEmbeddedInterfaces() {
 	a9 := &A9{I: &B9{}, J: &C9{}}
 	_ = a9.foo(nil) // (error reported at B9.foo() definition)
-	_ = a9.bar()    // (error reported at J.bar() declaration)
+	_ = a9.bar()    // (error reported at C9.bar() definition)
 }
 
 type IandJ interface {
@@ -205,13 +205,13 @@ func (*A7) foo(x *int) *int { //want "passed as parameter"
 
 // nilable(result 0)
 func (*A7) bar() *int {
-	return nil
+	return nil //want "returned as result"
 }
 
 func testEmbeddingInterfaceInInterface() {
 	var i IandJ = &A7{}
 	_ = i.foo(nil) // (error reported at A7.foo() definition)
-	_ = i.bar()    // (error reported at J.bar() declaration)
+	_ = i.bar()    // (error reported at A7.bar() definition)
 }
 
 // below test checks embedding of interface within a struct
@@ -292,7 +292,7 @@ func testNestedStructs() {
 
 // below test checks a non-trivial case simulated from https://github.com/golang/go/pull/60823
 type Conn interface {
-	RemoteAddr() Addr //want "returned"
+	RemoteAddr() Addr
 }
 
 type Addr interface {
@@ -312,7 +312,7 @@ type netConn struct{}
 // nilable(result 0)
 func (c *netConn) RemoteAddr() Addr {
 	if true {
-		return nil
+		return nil //want "returned as result"
 	}
 	return &addrImpl{}
 }
@@ -16,7 +16,7 @@
 package packageA
 
 type I1 interface {
-	Foo1() *int //want "returned as result"
+	Foo1() *int
 
 	// nilable(n)
 	Foo2(n *int) bool
@@ -25,7 +25,7 @@ type I1 interface {
 type S1 struct{}
 
 // nilable(result 0)
-func (*S1) Foo1() *int {
+func (*S1) Foo1() *int { //want "returned as result"
 	var v *int
 	return v
 }
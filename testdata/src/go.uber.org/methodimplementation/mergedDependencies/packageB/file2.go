@@ -16,13 +16,13 @@
 package packageB
 
 type I2 interface {
-	Bar() *string //want "returned as result"
+	Bar() *string
 }
 
 type S2 struct{}
 
 // nilable(result 0)
-func (S2) Bar() *string {
+func (S2) Bar() *string { //want "returned as result"
 	s := "hello"
 	return &s
 }
@@ -24,7 +24,7 @@ package methodimplementation
 
 type I interface {
 	// nilable(x)
-	foo(x *A) (*A, string) //want "returned as result"
+	foo(x *A) (*A, string)
 }
 
 type J interface {
@@ -43,7 +43,7 @@ type B struct {
 // nilable(result 0)
 func (A) foo(x *A) (*A, string) { //want "passed as param"
 	var b *A
-	return b, x.s
+	return b, x.s //want "returned as result"
 }
 
 // nilable(x)
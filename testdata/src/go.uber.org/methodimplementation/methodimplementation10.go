@@ -31,11 +31,11 @@ type i3 interface {
 }
 
 type i4 interface {
-	foo() (x *int) //want "returned as result"
+	foo() (x *int)
 }
 
 type i5 interface {
-	foo() (x *int) //want "returned as result"
+	foo() (x *int)
 }
 
 type i6 interface {
@@ -43,11 +43,11 @@ type i6 interface {
 }
 
 type i7 interface {
-	foo() (x *int) //want "returned as result"
+	foo() (x *int)
 }
 
 type i8 interface {
-	foo() (x *int) //want "returned as result"
+	foo() (x *int)
 }
 
 type s1 struct{}
@@ -60,7 +60,7 @@ func (*s1) foo() (x *int) {
 type s2 struct{}
 
 // nilable(x)
-func (*s2) foo() (x *int) { return nil }
+func (*s2) foo() (x *int) { return nil } //want "returned as result" "returned as result" "returned as result" "returned as result"
 
 func rets11() (*s1, *s1) {
 	return &s1{}, &s1{}
@@ -32,7 +32,7 @@ package methodimplementation
 
 type I121 interface {
 	// nilable(x)
-	foo(x *A121) (*A121, string) //want "returned as result"
+	foo(x *A121) (*A121, string)
 }
 
 type J121 interface {
@@ -51,7 +51,7 @@ type B121 struct {
 // nilable(result 0)
 func (A121) foo(x *A121) (*A121, string) { //want "passed as param"
 	var b *A121
-	return b, x.s
+	return b, x.s //want "returned as result"
 }
 
 // nilable(x)
@@ -88,7 +88,7 @@ func m121(x *A121, y *B121) (I121, J121) {
 
 type I122 interface {
 	// nilable(x)
-	foo(x *A122) (*A122, string) //want "returned as result"
+	foo(x *A122) (*A122, string)
 }
 
 type J122 interface {
@@ -107,7 +107,7 @@ type B122 struct {
 // nilable(result 0)
 func (A122) foo(x *A122) (*A122, string) { //want "passed as param"
 	var b *A122
-	return b, x.s
+	return b, x.s //want "returned as result"
 }
 
 // nilable(x)
@@ -148,7 +148,7 @@ func m122_2() {
 
 type I122_3 interface {
 	// nilable(x)
-	foo(x *A122_3) (*A122_3, string) //want "returned as result"
+	foo(x *A122_3) (*A122_3, string)
 }
 
 type A122_3 struct {
@@ -158,7 +158,7 @@ type A122_3 struct {
 // nilable(result 0)
 func (A122_3) foo(x *A122_3) (*A122_3, string) { //want "passed as param"
 	var b *A122_3
-	return b, x.s
+	return b, x.s //want "returned as result"
 }
 
 func m122_3() {
@@ -172,7 +172,7 @@ func m122_3() {
 
 type I123 interface {
 	// nilable(x)
-	foo(x *A123) (*A123, string) //want "returned as result"
+	foo(x *A123) (*A123, string)
 }
 
 type A123 struct {
@@ -182,7 +182,7 @@ type A123 struct {
 // nilable(result 0)
 func (A123) foo(x *A123) (*A123, string) { //want "passed as param"
 	var b *A123
-	return b, x.s
+	return b, x.s //want "returned as result"
 }
 
 func m123() {
@@ -194,7 +194,7 @@ func m123() {
 
 type I123_2 interface {
 	// nilable(x)
-	foo(x *A123_2) (*A123_2, string) //want "returned as result"
+	foo(x *A123_2) (*A123_2, string)
 }
 
 type A123_2 struct {
@@ -204,7 +204,7 @@ type A123_2 struct {
 // nilable(result 0)
 func (A123_2) foo(x *A123_2) (*A123_2, string) { //want "passed as param"
 	var b *A123_2
-	return b, x.s
+	return b, x.s //want "returned as result"
 }
 
 func m123_2() {
@@ -0,0 +1,57 @@
+//  Copyright (c) 2023 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+This is a test for checking that the package-level assertions of implementations (e.g., `var _ I = (*S)(nil)`) are
+sites of affiliations, even if the implementations are never cast to the interfaces in any function. The covariance
+violations of results are reported at the offending return statements of the implementing methods.
+
+<nilaway no inference>
+*/
+package methodimplementation
+
+type I14 interface {
+	// nilable(x)
+	foo(x *int) *int
+}
+
+type A14 struct{}
+
+var _ I14 = (*A14)(nil)
+
+// nilable(result 0)
+func (*A14) foo(x *int) *int { //want "passed as param"
+	return x //want "returned as result"
+}
+
+type B14 struct{}
+
+var _ I14 = B14{}
+
+// nilable(x)
+func (B14) foo(x *int) *int {
+	return new(int)
+}
+
+type C14 struct{}
+
+var (
+	_ I14 = &C14{}
+	_     = &C14{}
+)
+
+// nilable(x, result 0)
+func (*C14) foo(x *int) *int {
+	return x //want "returned as result"
+}
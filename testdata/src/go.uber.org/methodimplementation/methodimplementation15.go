@@ -0,0 +1,80 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+This is a test for checking that the affiliations involving generic interfaces and generic implementations are
+matched against the generic declarations of the methods, such that the nilability of the params and results are
+checked for variance regardless of the instantiations.
+
+<nilaway no inference>
+*/
+package methodimplementation
+
+type I15[T any] interface {
+	// nilable(x)
+	foo(x *T) *T
+}
+
+type A15 struct{}
+
+// nilable(result 0)
+func (*A15) foo(x *int) *int { //want "passed as param"
+	return x //want "returned as result"
+}
+
+type B15[T any] struct{}
+
+// nilable(result 0)
+func (*B15[T]) foo(x *T) *T { //want "passed as param"
+	return x //want "returned as result"
+}
+
+type C15[T any] struct{}
+
+// nilable(x)
+func (*C15[T]) foo(x *T) *T {
+	return new(T)
+}
+
+func m15() {
+	// generic interface with a non-generic implementation
+	var i1 I15[int] = &A15{}
+	i1.foo(nil)
+
+	// generic interface with a generic implementation, instantiated multiple times
+	var i2 I15[int] = &B15[int]{}
+	var i3 I15[string] = &B15[string]{}
+	i2.foo(nil)
+	i3.foo(nil)
+
+	var i4 I15[int] = &C15[int]{}
+	i4.foo(nil)
+}
+
+// Generic interfaces can also be used as constraints, where the implementations are affiliated
+// at the instantiations of the type parameters.
+type D15 struct{}
+
+// nilable(result 0)
+func (*D15) foo(x *string) *string { //want "passed as param"
+	return x //want "returned as result"
+}
+
+func useConstraint[T I15[string]](t T) *string {
+	return t.foo(nil)
+}
+
+func m15Constraint() {
+	useConstraint(&D15{})
+}
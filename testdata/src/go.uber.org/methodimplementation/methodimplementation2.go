@@ -23,7 +23,7 @@ package methodimplementation
 
 type I2 interface {
 	// nilable(x)
-	foo(x *A2) (*A2, string) //want "returned as result"
+	foo(x *A2) (*A2, string)
 }
 
 type A2 struct {
@@ -33,7 +33,7 @@ type A2 struct {
 // nilable(result 0)
 func (A2) foo(x *A2) (*A2, string) { //want "passed as param"
 	var b *A2
-	return b, x.s
+	return b, x.s //want "returned as result"
 }
 
 func m2() {
@@ -23,7 +23,7 @@ package methodimplementation
 
 type I4 interface {
 	// nilable(x)
-	foo(x *A4) (*A4, string) //want "returned as result"
+	foo(x *A4) (*A4, string)
 }
 
 type A4 struct {
@@ -33,7 +33,7 @@ type A4 struct {
 // nilable(result 0)
 func (A4) foo(x *A4) (*A4, string) { //want "passed as param"
 	var b *A4
-	return b, x.s
+	return b, x.s //want "returned as result"
 }
 
 func param3(i I4) {
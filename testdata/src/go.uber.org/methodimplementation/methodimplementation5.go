@@ -23,7 +23,7 @@ package methodimplementation
 
 type I5 interface {
 	// nilable(x)
-	foo(x *A5) (*A5, string) //want "returned as result"
+	foo(x *A5) (*A5, string)
 }
 
 type A5 struct {
@@ -33,7 +33,7 @@ type A5 struct {
 // nilable(result 0)
 func (A5) foo(x *A5) (*A5, string) { //want "passed as param"
 	var b *A5
-	return b, x.s
+	return b, x.s //want "returned as result"
 }
 
 func ret5() *A5 {
@@ -23,7 +23,7 @@ package methodimplementation
 
 type I6 interface {
 	// nilable(x)
-	foo(x *A6) (*A6, string) //want "returned as result"
+	foo(x *A6) (*A6, string)
 }
 
 type A6 struct {
@@ -33,7 +33,7 @@ type A6 struct {
 // nilable(result 0)
 func (A6) foo(x *A6) (*A6, string) { //want "passed as param"
 	var b *A6
-	return b, x.s
+	return b, x.s //want "returned as result"
 }
 
 func m6() {
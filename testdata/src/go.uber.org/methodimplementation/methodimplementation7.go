@@ -23,7 +23,7 @@ package methodimplementation
 
 type I7 interface {
 	// nilable(x)
-	foo(x *A7) (*A7, string) //want "returned as result"
+	foo(x *A7) (*A7, string)
 }
 
 type A7 struct {
@@ -35,7 +35,7 @@ type FuncType func(x *A7) (*A7, string)
 // nilable(result 0)
 func (f FuncType) foo(x *A7) (*A7, string) { //want "passed as param"
 	var b *A7
-	return b, x.s
+	return b, x.s //want "returned as result"
 }
 
 func m7() {
@@ -23,7 +23,7 @@ package methodimplementation
 
 type I8 interface {
 	// nilable(x)
-	foo(x *A8) (*A8, string) //want "returned as result"
+	foo(x *A8) (*A8, string)
 }
 
 type A8 struct {
@@ -35,7 +35,7 @@ type NamedType int
 // nilable(result 0)
 func (NamedType) foo(x *A8) (*A8, string) { //want "passed as param"
 	var b *A8
-	return b, x.s
+	return b, x.s //want "returned as result"
 }
 
 func m8() {
@@ -20,7 +20,7 @@ type A9 struct {
 }
 
 // nilable(result 0)
-func (a *A9) Foo(x *A9) (*A9, string) { //want "passed as param"
+func (a *A9) Foo(x *A9) (*A9, string) { //want "passed as param" "returned as result"
 	var b *A9
 	return b, x.S
 }
@@ -0,0 +1,23 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mockreporting tests that the errors in the mocks (i.e., the packages and files named
+// after the conventions of gomock and mockery) are not reported with `-exclude-mocks`.
+package mockreporting
+
+import "go.uber.org/mockreporting/mocks"
+
+func name() string {
+	return mocks.Missing().Name //want "accessed field `Name`"
+}
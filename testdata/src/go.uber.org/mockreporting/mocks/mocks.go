@@ -0,0 +1,41 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mocks is a package of (hand-written) mocks, whose errors are not reported with
+// `-exclude-mocks`, while the nilability flowing through it is still known downstream.
+package mocks
+
+type Item struct {
+	Name string
+}
+
+type FakeStore struct {
+	items map[string]*Item
+}
+
+func (s *FakeStore) Get(key string) *Item {
+	return s.items[key]
+}
+
+func (s *FakeStore) Name(key string) string {
+	var item *Item
+	if key == "" {
+		return item.Name
+	}
+	return s.items[key].Name
+}
+
+func Missing() *Item {
+	return nil
+}
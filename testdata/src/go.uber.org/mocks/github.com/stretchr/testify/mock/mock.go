@@ -0,0 +1,52 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// <nilaway no inference>
+package mock
+
+// these stubs simulate the real `github.com/stretchr/testify/mock` package because we can't import it in tests
+
+type TestingT interface {
+	Errorf(format string, args ...any)
+	FailNow()
+	Cleanup(func())
+}
+
+type Mock struct{}
+
+func (m *Mock) On(methodName string, arguments ...any) *Call { return &Call{Parent: m} }
+
+func (m *Mock) Called(arguments ...any) Arguments { return nil }
+
+func (m *Mock) Test(t TestingT) {}
+
+func (m *Mock) AssertExpectations(t TestingT) bool { return true }
+
+type Call struct {
+	Parent *Mock
+}
+
+func (c *Call) Return(returnArguments ...any) *Call { return c }
+
+func (c *Call) Once() *Call { return c }
+
+func (c *Call) Run(fn func(args Arguments)) *Call { return c }
+
+type Arguments []any
+
+func (args Arguments) Get(index int) any { return args[index] }
+
+func (args Arguments) Error(index int) error { return nil }
+
+const Anything = "mock.Anything"
@@ -0,0 +1,57 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mocks tests the modeling of the mocks generated by gomock and mockery.
+package mocks
+
+import (
+	"go.uber.org/mocks/go.uber.org/mock/gomock"
+	"go.uber.org/mocks/store"
+	"go.uber.org/mocks/store/mocks"
+)
+
+func lookup(s store.Store) string {
+	item, err := s.Get("key")
+	if err != nil {
+		return ""
+	}
+	return item.Name
+}
+
+// T simulates `*testing.T`.
+type T interface {
+	Errorf(format string, args ...any)
+	Fatalf(format string, args ...any)
+	FailNow()
+	Cleanup(func())
+}
+
+// The nil values set up as the results of the expected calls (e.g., `Return(nil, nil)` for a
+// missing item) are not reported, and neither are the results of the controllers and the chained
+// calls, nor the results of the calls to the controllers in the generated mocked methods.
+
+func testGomock(t T) {
+	ctrl := gomock.NewController(t)
+	m := mocks.NewMockStore(ctrl)
+	m.EXPECT().Get(gomock.Any()).Return(nil, nil).AnyTimes()
+	m.EXPECT().Get("key").Return(&store.Item{}, nil)
+	print(lookup(m))
+}
+
+func testMockery(t T) {
+	m := mocks.NewStore(t)
+	m.On("Get", "key").Return(nil, nil).Once()
+	m.EXPECT().Get("key").Return(nil, nil)
+	print(lookup(m))
+}
@@ -0,0 +1,96 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This file simulates the mocks generated by mockery (the "Code generated" header is omitted since
+// such files are excluded from the analysis in the tests).
+
+package mocks
+
+import (
+	mock "go.uber.org/mocks/github.com/stretchr/testify/mock"
+	store "go.uber.org/mocks/store"
+)
+
+// Store is an autogenerated mock type for the Store type
+type Store struct {
+	mock.Mock
+}
+
+type Store_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Store) EXPECT() *Store_Expecter {
+	return &Store_Expecter{mock: &_m.Mock}
+}
+
+// Get provides a mock function with given fields: key
+func (_m *Store) Get(key string) (*store.Item, error) {
+	ret := _m.Called(key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *store.Item
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*store.Item, error)); ok {
+		return rf(key)
+	}
+	if rf, ok := ret.Get(0).(func(string) *store.Item); ok {
+		r0 = rf(key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*store.Item)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type Store_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - key string
+func (_e *Store_Expecter) Get(key interface{}) *Store_Get_Call {
+	return &Store_Get_Call{Call: _e.mock.On("Get", key)}
+}
+
+func (_c *Store_Get_Call) Return(_a0 *store.Item, _a1 error) *Store_Get_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+// NewStore creates a new instance of Store. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Store {
+	mock := &Store{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
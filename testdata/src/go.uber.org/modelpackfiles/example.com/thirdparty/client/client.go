@@ -0,0 +1,29 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// <nilaway no inference>
+package client
+
+// these stubs simulate a third party library that is modeled by the model pack file in the test
+
+type Error struct {
+	Msg string
+}
+
+type Client struct{}
+
+// nilable(result 0)
+func MustNew() *Client { return &Client{} }
+
+func (c *Client) LastError() *Error { return nil }
@@ -0,0 +1,54 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package modelpackfiles tests the model pack files given by `-model-pack-files`, which model the
+// functions of the third party libraries here (see "models.modelpack").
+package modelpackfiles
+
+import (
+	"go.uber.org/modelpackfiles/example.com/thirdparty/cache"
+	"go.uber.org/modelpackfiles/example.com/thirdparty/client"
+	"go.uber.org/modelpackfiles/example.com/thirdparty/log"
+)
+
+// The values got from the cache are nonnil only if they are present.
+
+func lookup(c *cache.Cache, key string) string {
+	item, _ := c.Get(key)
+	return item.Name //want "accessed field `Name`"
+}
+
+func lookupChecked(c *cache.Cache, key string) string {
+	if item, ok := c.Get(key); ok {
+		return item.Name
+	}
+	return ""
+}
+
+// The client is always created, while its last error may be nil.
+
+func lastError() string {
+	c := client.MustNew()
+	return c.LastError().Msg //want "determined to be nilable by a trusted function"
+}
+
+// The fatal logs never return.
+
+func mustLookup(c *cache.Cache, key string) string {
+	item, ok := c.Get(key)
+	if !ok {
+		log.Fatalf("missing %s", key)
+	}
+	return item.Name
+}
@@ -0,0 +1,37 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// <nilaway no inference>
+package v1
+
+// these stubs simulate the real `k8s.io/apimachinery/pkg/apis/meta/v1` package because we can't
+// import it in tests
+
+type Time struct{}
+
+func (t Time) String() string { return "" }
+
+type Object interface {
+	GetName() string
+	GetDeletionTimestamp() *Time
+}
+
+type ObjectMeta struct {
+	Name              string
+	DeletionTimestamp *Time
+}
+
+func (m *ObjectMeta) GetName() string { return m.Name }
+
+func (m *ObjectMeta) GetDeletionTimestamp() *Time { return m.DeletionTimestamp }
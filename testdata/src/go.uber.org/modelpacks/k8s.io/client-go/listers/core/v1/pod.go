@@ -0,0 +1,31 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// <nilaway no inference>
+package v1
+
+import corev1 "go.uber.org/modelpacks/k8s.io/api/core/v1"
+
+// these stubs simulate the real `k8s.io/client-go/listers/core/v1` package because we can't
+// import it in tests. The results are annotated as nilable to check that the model pack takes
+// precedence over the facts of the real packages.
+
+type PodLister interface {
+	// nilable(result 0)
+	Pods(namespace string) PodNamespaceLister
+}
+
+type PodNamespaceLister interface {
+	Get(name string) (*corev1.Pod, error)
+}
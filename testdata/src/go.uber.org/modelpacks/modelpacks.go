@@ -0,0 +1,86 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package modelpacks tests the optional model packs enabled by `-model-packs`, i.e., the "k8s"
+// model pack for the Kubernetes client libraries.
+package modelpacks
+
+import (
+	corev1 "go.uber.org/modelpacks/k8s.io/api/core/v1"
+	informers "go.uber.org/modelpacks/k8s.io/client-go/informers/core/v1"
+	"go.uber.org/modelpacks/sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// The informers and listers always exist, and the objects got from the listers are guarded by the
+// errors.
+
+func nodeName(informer informers.Interface, namespace, name string) string {
+	pod, err := informer.Pods().Lister().Pods(namespace).Get(name)
+	if err != nil {
+		return ""
+	}
+	return pod.Spec.NodeName
+}
+
+// The deletion timestamps are only set for the objects being deleted.
+
+func deletedAt(pod *corev1.Pod) string {
+	return pod.GetDeletionTimestamp().String() //want "determined to be nilable by a trusted function"
+}
+
+func deletedAtGuarded(pod *corev1.Pod) string {
+	if pod.GetDeletionTimestamp() == nil {
+		return ""
+	}
+	return pod.GetDeletionTimestamp().String()
+}
+
+// The DeepCopy methods generated for the custom resources return nil only for nil receivers.
+
+type Widget struct {
+	Spec *WidgetSpec
+}
+
+type WidgetSpec struct {
+	Size int
+}
+
+func (in *Widget) DeepCopyInto(out *Widget) {
+	*out = *in
+	if in.Spec != nil {
+		out.Spec = new(WidgetSpec)
+		*out.Spec = *in.Spec
+	}
+}
+
+func (in *Widget) DeepCopy() *Widget {
+	if in == nil {
+		return nil
+	}
+	out := new(Widget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func resize(w *Widget, size int) *Widget {
+	copied := w.DeepCopy()
+	copied.Spec = &WidgetSpec{Size: size}
+	return copied
+}
+
+// The managers always hold the clients.
+
+func get(mgr manager.Manager, key string) error {
+	return mgr.GetClient().Get(key)
+}
@@ -0,0 +1,29 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// <nilaway no inference>
+package manager
+
+// these stubs simulate the real `sigs.k8s.io/controller-runtime/pkg/manager` package because we
+// can't import it in tests. The results are annotated as nilable to check that the model pack
+// takes precedence over the facts of the real packages.
+
+type Client interface {
+	Get(key string) error
+}
+
+type Manager interface {
+	// nilable(result 0)
+	GetClient() Client
+}
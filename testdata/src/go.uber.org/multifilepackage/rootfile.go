@@ -38,9 +38,9 @@ func safeBoxManipulations() {
 	c.Ptr = nil
 }
 
-func unsafeBoxManipulations() *secondpackage.C {
+func unsafeBoxManipulations(unbox bool) *secondpackage.C {
 	c := secondpackage.CBox{}
-	if true {
+	if unbox {
 		return c.Unbox() //want "returned"
 	} else {
 		return c.Ptr //want "returned"
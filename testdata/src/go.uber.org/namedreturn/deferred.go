@@ -0,0 +1,81 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// These tests check that assignments to named result variables in deferred function literals are
+// tracked as returned values at the returns following the defer statements.
+
+package namedreturn
+
+func deferAssignsNil() (p *int) {
+	p = new(int)
+	defer func() { p = nil }()
+	return //want "named return `p`"
+}
+
+func deferAssignsNilExplicitReturn() (p *int) {
+	defer func() { p = nil }()
+	return new(int) //want "named return `p`"
+}
+
+func deferAssignsNilConditionally(b bool) (p *int) {
+	p = new(int)
+	defer func() {
+		if b {
+			p = nil
+		}
+	}()
+	return //want "named return `p`"
+}
+
+func deferAssignsNonnil() (p *int) {
+	p = new(int)
+	defer func() { p = new(int) }()
+	return
+}
+
+func deferAfterReturn(b bool) (p *int) {
+	p = new(int)
+	if b {
+		return
+	}
+	defer func() { p = nil }()
+	return //want "named return `p`"
+}
+
+func deferAssignsOuterVar() (p *int) {
+	var q *int
+	p = new(int)
+	defer func() { p = q }()
+	return //want "named return `p`"
+}
+
+func deferAssignsLocalVar() (p *int) {
+	p = new(int)
+	defer func() {
+		// the values of the variables local to the function literal are not tracked
+		q := new(int)
+		p = q
+	}()
+	return
+}
+
+func deferGuardedByError() (p *int, err error) {
+	p = new(int)
+	defer func() {
+		if err != nil {
+			p = nil
+		}
+	}()
+	return
+}
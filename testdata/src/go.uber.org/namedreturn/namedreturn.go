@@ -21,8 +21,8 @@ values
 */
 package namedreturn
 
-func foo1() (i *int) { //want "returned from `foo1.*` via named return `i`"
-	return
+func foo1() (i *int) {
+	return //want "returned from `foo1.*` via named return `i`"
 }
 
 // nilable(i)
@@ -30,60 +30,60 @@ func foo2() (i *int) {
 	return
 }
 
-func foo3() (i, j *int) { //want "returned from `foo3.*` via named return `j`"
+func foo3() (i, j *int) {
 	x := 1
 	i = &x
-	return
+	return //want "returned from `foo3.*` via named return `j`"
 }
 
-func foo4(x int, y string) (k bool, i *int, s *string, a []int) { //want "named return `s`" "named return `i`" "named return `i`" "named return `s`" "named return `i`" "named return `s`"
+func foo4(x int, y string) (k bool, i *int, s *string, a []int) {
 	switch x {
 	case 0:
 		return k, i, s, a //want "returned" "returned"
 	case 1:
 		i = &x
-		return // (error is reported for `s` at function declaration)
+		return //want "named return `s`"
 	case 2:
 		s = &y
-		return // (error is reported for `i` at function declaration)
+		return //want "named return `i`"
 	case 3:
 		i = &x
 		s = &y
 		return
 	case 4:
 		a = make([]int, 5)
-		return // (error is reported for `i` and `s` at function declaration)
+		return //want "named return `i`" "named return `s`"
 	}
-	return // (error is reported for `i` and `s` at function declaration)
+	return //want "named return `i`" "named return `s`"
 }
 
-func foo5(n int) (i *int) { //want "named return `i`"
+func foo5(n int) (i *int) {
 	if n > 0 {
 		x := 1
 		i := &x
 		return i
 	}
-	return
+	return //want "named return `i`"
 }
 
-func foo6() (i, j *int) { //want "named return `j`"
+func foo6() (i, j *int) {
 	x := 1
 	i, k := &x, 0
 	func(...any) {}(i, k)
-	return
+	return //want "named return `j`"
 }
 
-func foo7() (i, j *int) { //want "named return `i`" "named return `j`"
+func foo7() (i, j *int) {
 	x := 1
 	if true {
 		i := &x
 		func(any) {}(i)
 	}
-	return
+	return //want "named return `i`" "named return `j`"
 }
 
-func foo8(x string) (_ *int) { //want "named return `_`"
-	return
+func foo8(x string) (_ *int) {
+	return //want "named return `_`"
 }
 
 type myErr struct{}
@@ -101,8 +101,8 @@ func foo10() (x *int, _ error) {
 	return
 }
 
-func foo11() (x *int, _ error) { //want "named return `x`"
-	return
+func foo11() (x *int, _ error) {
+	return //want "named return `x`"
 }
 
 var dummy bool
@@ -150,20 +150,20 @@ func retsNonnilNilableWithErr2(x *int, y *int) (r0 *int, r1 *int, e error) {
 }
 
 // nilable(x, r1)
-func retsNonnilNilableWithErr3(x *int, y *int) (r0 *int, r1 *int, e error) { //want "named return `r0`"
+func retsNonnilNilableWithErr3(x *int, y *int) (r0 *int, r1 *int, e error) {
 	// this error case indicates that if we return nil as our error and as a
 	// non-nilable result, that result will be interpreted as an error
-	return
+	return //want "named return `r0`"
 }
 
 // nilable(x, r1)
-func retsNonnilNilableWithErr4(x *int, y *int) (r0 *int, r1 *int, e error) { //want "named return `r0`"
+func retsNonnilNilableWithErr4(x *int, y *int) (r0 *int, r1 *int, e error) {
 	i := 0
 	switch 0 {
 	case 7:
 		// this is the same error case as above, but involving flow from a param
 		r0 = x
-		return
+		return //want "named return `r0`"
 	case 8:
 		// this is safe
 		r0 = &i
@@ -177,19 +177,19 @@ func retsNonnilNilableWithErr4(x *int, y *int) (r0 *int, r1 *int, e error) { //w
 }
 
 // nilable(x, r1)
-func retsNonnilNilableWithErr5(x *int, y *int) (r0 *int, r1 *int, e error) { //want "named return `r0`"
+func retsNonnilNilableWithErr5(x *int, y *int) (r0 *int, r1 *int, e error) {
 	// this illustrates that an unassigned local error variable is interpreted as nil based on its zero value
 	var e2 error
 	e = e2
-	return
+	return //want "named return `r0`"
 }
 
 // nilable(x, r1)
-func retsNonnilNilableWithErr6(x *int, y *int) (r0 *int, r1 *int, e error) { //want "named return `r0`"
+func retsNonnilNilableWithErr6(x *int, y *int) (r0 *int, r1 *int, e error) {
 	// this is similar to the above case - but makes sure that computations in non-error results
 	// are not ignored
 	r0 = takesNonnilRetsNilable(nil) //want "passed"
-	return
+	return                           //want "named return `r0`"
 }
 
 // nilable(x, r1)
@@ -215,7 +215,7 @@ func retsNonnilNilableWithErr8(x *int, y *int) (r0 *int, r1 *int, e error) {
 }
 
 // nilable(x, r1)
-func retsNonnilNilableWithErr9(x *int, y *int, cond bool) (r0 *int, r1 *int, e error) { //want "named return" "named return" "named return" "named return"
+func retsNonnilNilableWithErr9(x *int, y *int, cond bool) (r0 *int, r1 *int, e error) {
 	if cond {
 		// this case further tests the flow-sensitivity of the error result
 		if e != nil {
@@ -232,11 +232,11 @@ func retsNonnilNilableWithErr9(x *int, y *int, cond bool) (r0 *int, r1 *int, e e
 					}
 					e = nil
 					if dummy {
-						return // (error is reported for `r0` at function declaration)
+						return //want "named return `r0`"
 					}
 				}
 				if dummy { // here - two different flows result in a nilable (L187) or non-nil (L175) value for e
-					return // (error is reported for `r0` at function declaration)
+					return //want "named return `r0`"
 				}
 			} else {
 				if dummy {
@@ -251,10 +251,10 @@ func retsNonnilNilableWithErr9(x *int, y *int, cond bool) (r0 *int, r1 *int, e e
 			}
 			if dummy {
 				// here - two different flows result in a nilable (L187) or non-nil (L175, L200) value for e
-				return // (error is reported for `r0` at function declaration)
+				return //want "named return `r0`"
 			}
 		}
 	}
 	// here - two different flows result in a nilable (L102, L187) or non-nil (L200) value for e
-	return // (error is reported for `r0` at function declaration)
+	return //want "named return `r0`"
 }
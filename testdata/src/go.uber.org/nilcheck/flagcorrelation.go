@@ -0,0 +1,172 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// These tests check that boolean flags set alongside values (e.g., `found = true` right after
+// `p = x`) guard the uses of the values in the branches where the flags are true.
+
+package nilcheck
+
+type flagT struct {
+	f int
+}
+
+// nilable(result 0)
+func nilableFlagT() *flagT {
+	return nil
+}
+
+func flagInLoop(xs []*flagT) int {
+	var p *flagT
+	found := false
+	for _, x := range xs {
+		if x != nil && x.f > 0 {
+			p = x
+			found = true
+			break
+		}
+	}
+	if found {
+		return p.f
+	}
+	return 0
+}
+
+func flagInIfs(a, b *flagT) int {
+	var p *flagT
+	var ok bool
+	if a != nil {
+		p = a
+		ok = true
+	} else if b != nil {
+		p = b
+		ok = true
+	}
+	if ok {
+		return p.f
+	}
+	return 0
+}
+
+func flagDefinedFirst(a *flagT) int {
+	found := false
+	var p *flagT
+	if a != nil {
+		p = a
+		found = true
+	}
+	if !found {
+		return 0
+	}
+	return p.f
+}
+
+func flagReset(xs []*flagT) int {
+	var p *flagT
+	found := false
+	for _, x := range xs {
+		if x != nil {
+			p = x
+			found = true
+		}
+		if dummy {
+			found = false
+		}
+	}
+	if found {
+		return p.f
+	}
+	return 0
+}
+
+func flagUnrelated(a *flagT) int {
+	var p *flagT
+	found := false
+	if a != nil {
+		found = true
+	}
+	if found {
+		return p.f //want "accessed field `f`"
+	}
+	return 0
+}
+
+func flagSetWithoutValue(a *flagT) int {
+	var p *flagT
+	found := false
+	if a != nil {
+		p = a
+		found = true
+	}
+	if dummy {
+		found = true
+	}
+	if found {
+		return p.f //want "accessed field `f`"
+	}
+	return 0
+}
+
+func flagNonConstant(a *flagT) int {
+	var p *flagT
+	found := false
+	if a != nil {
+		p = a
+		found = true
+	}
+	found = dummy
+	if found {
+		return p.f //want "accessed field `f`"
+	}
+	return 0
+}
+
+func flagSetInClosure(a *flagT) int {
+	var p *flagT
+	found := false
+	if a != nil {
+		p = a
+		found = true
+	}
+	func() { found = true }()
+	if found {
+		return p.f //want "accessed field `f`"
+	}
+	return 0
+}
+
+func flagNilableValue(a *flagT) int {
+	var p *flagT
+	found := false
+	if a != nil {
+		p = nilableFlagT()
+		found = true
+	}
+	if found {
+		return p.f //want "accessed field `f`"
+	}
+	return 0
+}
+
+func flagInitiallyTrue(a *flagT) int {
+	var p *flagT
+	found := true
+	if a != nil {
+		p = a
+		found = true
+	}
+	if found {
+		return p.f //want "accessed field `f`"
+	}
+	return 0
+}
@@ -0,0 +1,108 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// These tests check that the receivers of method values (e.g., `f := x.foo`) are consumed at the
+// binding site, and the receivers passed to method expressions (e.g., `(*T).foo(x)`) are consumed
+// as receivers at the call site.
+
+package nilcheck
+
+type methodValueT struct {
+	f int
+}
+
+func (t *methodValueT) ptrMethod() int {
+	return t.f
+}
+
+// nilable(t)
+func (t *methodValueT) nilableRecvMethod() int {
+	if t == nil {
+		return 0
+	}
+	return t.f
+}
+
+func (t methodValueT) valMethod() int {
+	return t.f
+}
+
+type methodValueOuter struct {
+	*methodValueT
+}
+
+// nilable(result 0)
+func nilableMethodValueT() *methodValueT {
+	return nil
+}
+
+func methodValueValueRecv() int {
+	x := nilableMethodValueT()
+	f := x.valMethod // want "called `valMethod\\(\\)`"
+	return f()
+}
+
+func methodValuePtrRecv() int {
+	x := nilableMethodValueT()
+	f := x.ptrMethod // want "used as receiver to call `ptrMethod\\(\\)`"
+	return f()
+}
+
+func methodValueNilableRecv() int {
+	x := nilableMethodValueT()
+	f := x.nilableRecvMethod
+	return f()
+}
+
+func methodValueGuarded() int {
+	x := nilableMethodValueT()
+	if x == nil {
+		return 0
+	}
+	f := x.ptrMethod
+	return f()
+}
+
+func methodExprPtrRecv() int {
+	x := nilableMethodValueT()
+	return (*methodValueT).ptrMethod(x) // want "used as receiver to call `ptrMethod\\(\\)`"
+}
+
+func methodExprNilableRecv() int {
+	x := nilableMethodValueT()
+	return (*methodValueT).nilableRecvMethod(x)
+}
+
+func methodExprValueRecvThroughPtr() int {
+	x := nilableMethodValueT()
+	return (*methodValueT).valMethod(x) // want "called `valMethod\\(\\)`"
+}
+
+func methodExprValueRecv() int {
+	x := nilableMethodValueT()
+	return methodValueT.valMethod(*x) // want "dereferenced"
+}
+
+func methodExprGuarded() int {
+	x := nilableMethodValueT()
+	if x != nil {
+		return (*methodValueT).ptrMethod(x)
+	}
+	return 0
+}
+
+func methodExprPromoted(o *methodValueOuter) int {
+	o = nil
+	return (*methodValueOuter).nilableRecvMethod(o) // want "called `nilableRecvMethod\\(\\)`"
+}
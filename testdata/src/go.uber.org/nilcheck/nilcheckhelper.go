@@ -0,0 +1,139 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// These tests check that user-defined nil-check helper functions (e.g., `isNil(x)`) refine the
+// nilness of their arguments at the call sites, just like direct nil comparisons.
+
+package nilcheck
+
+type helperT struct {
+	f int
+	p *helperT
+}
+
+// nilable(result 0)
+func nilableHelperT() *helperT {
+	return nil
+}
+
+// nilable(x)
+func isNil(x *helperT) bool {
+	return x == nil
+}
+
+// nilable(x)
+func isNonNil(x *helperT) bool {
+	return x != nil
+}
+
+// nilable(x)
+func isEmpty(x *helperT) bool {
+	if x == nil {
+		return true
+	}
+	return false
+}
+
+// Only contract(nil -> false) can be inferred for this helper, i.e., a true result implies x is
+// nonnil but a false result tells nothing.
+//
+// nilable(x)
+func isNonNilAndPositive(x *helperT) bool {
+	return x != nil && x.f > 0
+}
+
+// nilable(x)
+func alwaysFalse(x *helperT) bool {
+	_ = x
+	return false
+}
+
+func useNotIsNil() int {
+	x := nilableHelperT()
+	if !isNil(x) {
+		return x.f
+	}
+	return 0
+}
+
+func useIsNilEarlyReturn() int {
+	x := nilableHelperT()
+	if isNil(x) {
+		return 0
+	}
+	return x.f
+}
+
+func useIsNilWrongBranch() int {
+	x := nilableHelperT()
+	if isNil(x) {
+		return x.f // want "accessed field `f`"
+	}
+	return 0
+}
+
+func useIsNonNil() int {
+	x := nilableHelperT()
+	if isNonNil(x) {
+		return x.f
+	}
+	return x.f // want "accessed field `f`"
+}
+
+func useIsEmpty() int {
+	x := nilableHelperT()
+	if isEmpty(x) {
+		return 0
+	}
+	return x.f
+}
+
+func useIsNonNilAndPositive() int {
+	x := nilableHelperT()
+	if isNonNilAndPositive(x) {
+		return x.f
+	}
+	return x.f // want "accessed field `f`"
+}
+
+func useNotIsNonNilAndPositive() int {
+	x := nilableHelperT()
+	if !isNonNilAndPositive(x) {
+		return 0
+	}
+	return x.f
+}
+
+func useAlwaysFalse() int {
+	x := nilableHelperT()
+	if !alwaysFalse(x) {
+		return x.f // want "accessed field `f`"
+	}
+	return 0
+}
+
+func useIsNilOnField(x *helperT) int {
+	if x == nil || isNil(x.p) {
+		return 0
+	}
+	return x.p.f
+}
+
+func useIsNilInCompoundCondition(a, b *helperT) int {
+	a, b = nilableHelperT(), nilableHelperT()
+	if !isNil(a) && !isNil(b) {
+		return a.f + b.f
+	}
+	return 0
+}
@@ -0,0 +1,95 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// These tests check that accesses to promoted fields and methods (e.g., `x.f` for `x.inner.f`)
+// require the embedded pointers they are accessed through to be nonnil, and that nil checks of
+// the embedded pointers apply to the promoted accesses.
+
+package nilcheck
+
+type promotedInner struct {
+	f int
+}
+
+func (i promotedInner) valMethod() int {
+	return i.f
+}
+
+type promotedOuter struct {
+	*promotedInner
+	g int
+}
+
+type promotedOuterOuter struct {
+	promotedOuter
+}
+
+// nilable(result 0)
+func nilablePromotedInner() *promotedInner {
+	return nil
+}
+
+func promotedFieldNilGuard(o *promotedOuter) int {
+	o.promotedInner = nilablePromotedInner()
+	if o.promotedInner == nil {
+		return o.f // want "accessed field `f`"
+	}
+	return o.f
+}
+
+func promotedFieldNonnilGuard(o *promotedOuter) int {
+	o.promotedInner = nilablePromotedInner()
+	if o.promotedInner != nil {
+		return o.f + o.promotedInner.f
+	}
+	return o.f // want "accessed field `f`"
+}
+
+func promotedFieldAssignedNil(o *promotedOuter) int {
+	o.promotedInner = nil
+	return o.f // want "accessed field `f`"
+}
+
+func promotedFieldAssignedNonnil(o *promotedOuter) int {
+	o.promotedInner = nil
+	o.promotedInner = &promotedInner{}
+	return o.f
+}
+
+func promotedFieldWrite(o *promotedOuter) {
+	o.promotedInner = nil
+	o.f = 1 // want "accessed field `f`"
+}
+
+func promotedFieldNested(o *promotedOuterOuter) int {
+	o.promotedOuter.promotedInner = nilablePromotedInner()
+	if o.promotedInner == nil {
+		return o.f // want "accessed field `f`"
+	}
+	return o.f
+}
+
+func promotedMethodNilGuard(o *promotedOuter) int {
+	o.promotedInner = nilablePromotedInner()
+	if o.promotedInner == nil {
+		return o.valMethod() // want "called `valMethod\\(\\)`"
+	}
+	return o.valMethod()
+}
+
+func promotedNonPointerField(o *promotedOuterOuter) int {
+	// the embedded field promotedOuter is a struct value, so only o itself is required to be
+	// nonnil here
+	return o.g
+}
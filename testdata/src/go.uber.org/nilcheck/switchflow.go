@@ -0,0 +1,178 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// These tests check that nil comparisons in the cases of switch statements refine the nilness of
+// the compared values in the other cases, just like the branches of if statements.
+
+package nilcheck
+
+type switchT struct {
+	f int
+	p *switchT
+}
+
+// nilable(result 0)
+func nilableSwitchT() *switchT {
+	return nil
+}
+
+func tagless() int {
+	x := nilableSwitchT()
+	switch {
+	case x == nil:
+		return 0
+	default:
+		return x.f
+	}
+}
+
+func taglessNeq() int {
+	x := nilableSwitchT()
+	switch {
+	case x != nil:
+		return x.f
+	default:
+		return x.f // want "accessed field `f`"
+	}
+}
+
+func taglessNilFirst() int {
+	x := nilableSwitchT()
+	switch {
+	case nil == x:
+		return x.f // want "accessed field `f`"
+	case x.f > 0:
+		return x.f
+	}
+	return x.f
+}
+
+func taglessMultipleCases(y int) int {
+	x := nilableSwitchT()
+	switch {
+	case y > 0:
+		return x.f // want "accessed field `f`"
+	case x == nil, y < 0:
+		return 0
+	default:
+		return x.f
+	}
+}
+
+func taglessCompound(y int) int {
+	x := nilableSwitchT()
+	switch {
+	case x != nil && x.p != nil:
+		return x.p.f
+	case x == nil || y > 0:
+		return 0
+	}
+	return x.f
+}
+
+func taglessInit() int {
+	switch x := nilableSwitchT(); {
+	case x == nil:
+		return 0
+	default:
+		return x.f
+	}
+}
+
+func tagged() int {
+	x := nilableSwitchT()
+	switch x {
+	case nil:
+		return 0
+	default:
+		return x.f
+	}
+}
+
+func taggedNilCase() int {
+	x := nilableSwitchT()
+	switch x {
+	case nil:
+		return x.f // want "accessed field `f`"
+	}
+	return x.f
+}
+
+func taggedNoDefault() int {
+	x := nilableSwitchT()
+	switch x {
+	case nil:
+		return 0
+	}
+	return x.f
+}
+
+func taggedMultipleValues(y *switchT) int {
+	x := nilableSwitchT()
+	switch x {
+	case y, nil:
+		return 0
+	default:
+		return x.f
+	}
+}
+
+func taggedInit() int {
+	switch x := nilableSwitchT(); x {
+	case nil:
+		return 0
+	default:
+		return x.f
+	}
+}
+
+func taggedField(s *switchT) int {
+	switch s.p {
+	case nil:
+		return 0
+	default:
+		return s.p.f
+	}
+}
+
+func taggedTrue() int {
+	x := nilableSwitchT()
+	switch true {
+	case x == nil:
+		return 0
+	default:
+		return x.f
+	}
+}
+
+func taggedFalse() int {
+	x := nilableSwitchT()
+	switch false {
+	case x != nil:
+		return 0
+	default:
+		return x.f
+	}
+}
+
+func fallthroughCase() int {
+	x := nilableSwitchT()
+	switch {
+	case x != nil:
+		fallthrough
+	case x == nil:
+		return x.f // want "accessed field `f`"
+	}
+	return 0
+}
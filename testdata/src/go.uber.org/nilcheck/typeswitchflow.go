@@ -0,0 +1,122 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// These tests check that the symbolic variables of type switches (e.g., `v` in
+// `switch v := x.(type)`) follow the nilness of the switched values, and that the `case nil`
+// arms refine the nilness of the switched values in the other arms.
+
+package nilcheck
+
+type typeSwitchI interface {
+	m() int
+}
+
+type typeSwitchA struct {
+	f int
+}
+
+func (a *typeSwitchA) m() int { return 0 }
+
+type typeSwitchB struct{}
+
+func (typeSwitchB) m() int { return 0 }
+
+// nilable(result 0)
+func nilableTypeSwitchI() typeSwitchI {
+	return nil
+}
+
+func typeSwitchNonnil(x typeSwitchI) int {
+	switch v := x.(type) {
+	case *typeSwitchA:
+		return v.m()
+	default:
+		return v.m()
+	}
+}
+
+func typeSwitchNilable() int {
+	x := nilableTypeSwitchI()
+	switch v := x.(type) {
+	case typeSwitchB:
+		return v.m()
+	default:
+		return v.m() // want "called `m\\(\\)`"
+	}
+}
+
+func typeSwitchCaseNil() int {
+	x := nilableTypeSwitchI()
+	switch v := x.(type) {
+	case nil:
+		return v.m() // want "called `m\\(\\)`"
+	case typeSwitchB:
+		return v.m()
+	default:
+		return v.m()
+	}
+}
+
+func typeSwitchCaseNilLast() int {
+	x := nilableTypeSwitchI()
+	switch v := x.(type) {
+	case *typeSwitchA:
+		return 1
+	case nil:
+		return 0
+	default:
+		return v.m()
+	}
+}
+
+func typeSwitchCaseNilMultiple() int {
+	x := nilableTypeSwitchI()
+	switch v := x.(type) {
+	case typeSwitchB, nil:
+		return v.m() // want "called `m\\(\\)`"
+	default:
+		return v.m()
+	}
+}
+
+func typeSwitchCaseNilWithoutBinding() int {
+	x := nilableTypeSwitchI()
+	switch x.(type) {
+	case nil:
+		return 0
+	}
+	return x.m()
+}
+
+func typeSwitchWithoutCaseNil() int {
+	x := nilableTypeSwitchI()
+	switch x.(type) {
+	case typeSwitchB:
+		return 0
+	}
+	return x.m() // want "called `m\\(\\)`"
+}
+
+// The symbolic variables can be captured by closures, where they are declared by identifiers
+// without any associated objects.
+func typeSwitchClosure() int {
+	x := nilableTypeSwitchI()
+	switch v := x.(type) {
+	case nil:
+		return 0
+	case typeSwitchB:
+		return func() int { return v.m() }()
+	}
+	return 0
+}
@@ -0,0 +1,112 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package inference tests that the fields inferred to be nonnil (e.g., since they are
+// dereferenced) are reported at the composite literals omitting them, rather than at their
+// far-away dereferences, while omitting a field never forces it to be nilable.
+package inference
+
+import "sync"
+
+type Client struct {
+	Name string
+}
+
+type Server struct {
+	client  *Client
+	backup  *Client
+	cache   *Client
+	routes  map[string]*Client
+	clients []*Client
+	done    chan struct{}
+	mu      *sync.Mutex
+}
+
+func (s *Server) use() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routes["default"] = s.client
+	return s.client.Name + s.backup.Name
+}
+
+func (s *Server) useCache() string {
+	// The omission of `cache` does not make it nilable, hence this is not reported.
+	return s.cache.Name
+}
+
+func (s *Server) useClients() int {
+	// Nil slices and channels are idiomatic zero values, whose omissions are not reported.
+	close(s.done)
+	return len(s.clients) + len(s.clients[0].Name)
+}
+
+func newServer(c *Client) *Server {
+	return &Server{
+		client: c,
+		backup: c,
+		cache:  c,
+		routes: map[string]*Client{},
+		mu:     &sync.Mutex{},
+	}
+}
+
+func newServerPartial(c *Client) *Server {
+	return &Server{client: c, cache: c, routes: map[string]*Client{}, mu: &sync.Mutex{}} //want "uninitialized field `backup` omitted from the composite literal"
+}
+
+func newServerEmpty() *Server {
+	// The omissions of `client`, `backup`, `cache`, `routes`, and `mu` are grouped together.
+	return &Server{} //want "omitted from the composite literal(.|\n)*at 4 other place"
+}
+
+func newServerAssigned(c *Client) *Server {
+	// The fields assigned after the literal are not considered omitted.
+	s := &Server{cache: c}
+	s.client, s.backup = c, c
+	s.routes = make(map[string]*Client)
+	s.mu = &sync.Mutex{}
+	return s
+}
+
+type Options struct {
+	Logger *Client
+}
+
+func (o Options) name() string {
+	if o.Logger == nil {
+		return ""
+	}
+	return o.Logger.Name
+}
+
+func newOptions() Options {
+	// The fields checked for nil before their dereferences are nilable, whose omissions are fine.
+	return Options{}
+}
+
+// The fields annotated with field-level `//nonnil` are reported at the composite literals
+// omitting them, rather than at their annotations.
+type Conn struct {
+	client *Client //nonnil
+}
+
+func newConn() *Conn {
+	return &Conn{} //want "uninitialized assigned into field `client`"
+}
+
+func newConnAssigned(c *Client) *Conn {
+	conn := &Conn{}
+	conn.client = c
+	return conn
+}
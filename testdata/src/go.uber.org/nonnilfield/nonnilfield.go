@@ -0,0 +1,156 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+This test aims to make sure that the field-level `//nonnil` annotations shift the checking of the
+fields from their dereferences to their initialization sites.
+
+<nilaway no inference>
+*/
+package nonnilfield
+
+type Client struct {
+	Name string
+}
+
+type Server struct {
+	client *Client //nonnil
+
+	//nonnil
+	backup *Client
+
+	cache *Client
+}
+
+func (s *Server) use() string {
+	// Dereferences of the enforced fields are never reported.
+	return s.client.Name + s.backup.Name
+}
+
+func (s *Server) useCache() string {
+	return s.cache.Name
+}
+
+func newServer(c *Client) *Server {
+	return &Server{client: c, backup: c}
+}
+
+func newServerPositional(c *Client) *Server {
+	return &Server{c, c, nil}
+}
+
+func newServerOmitted(c *Client) *Server {
+	return &Server{client: c} //want "uninitialized assigned into field `backup`"
+}
+
+func newServerEmpty() *Server {
+	s := Server{} //want "uninitialized assigned into field `client`" "uninitialized assigned into field `backup`"
+	return &s
+}
+
+// The zero values omit all the fields like the empty composite literals.
+func newServerVar() string {
+	var s Server //want "uninitialized assigned into field `client`" "uninitialized assigned into field `backup`"
+	return s.client.Name
+}
+
+func newServerNew() string {
+	return new(Server).client.Name //want "uninitialized assigned into field `client`" "uninitialized assigned into field `backup`"
+}
+
+func resetServer(s *Server) {
+	*s = Server{} //want "uninitialized assigned into field `client`" "uninitialized assigned into field `backup`"
+}
+
+func newServerVarAssigned(c *Client) *Server {
+	var s *Server // pointers are not constructions
+	s = &Server{client: c, backup: c}
+	return s
+}
+
+func newServerAssigned(c *Client) *Server {
+	// The fields assigned right after the construction are not considered omitted.
+	s := &Server{}
+	s.client = c
+	s.backup = c
+	return s
+}
+
+func newServerVarFields(c *Client) *Server {
+	var s Server
+	s.client, s.backup = c, c
+	return &s
+}
+
+func newServerEscaped(c *Client) *Server {
+	// The fields assigned after the value may have escaped are still considered omitted.
+	s := &Server{backup: c} //want "uninitialized assigned into field `client`"
+	register(s)
+	s.client = c
+	return s
+}
+
+func register(*Server) {}
+
+func newServerNil(c *Client) *Server {
+	return &Server{client: nil, backup: c} //want "literal `nil` assigned into field `client`"
+}
+
+func testCallers() {
+	newServer(&Client{})
+	newServer(nil)           //want "literal `nil` passed as arg `c` to `newServer\\(\\)`"
+	newServerPositional(nil) //want "literal `nil` passed as arg `c` to `newServerPositional\\(\\)`"
+}
+
+// nilable(c)
+func newServerNilable(c *Client) *Server {
+	return &Server{client: c, backup: &Client{}} //want "function parameter `c` assigned into field `client`"
+}
+
+func testAssignment(s *Server) {
+	s.client = nil //want "literal `nil` assigned into field `client`"
+}
+
+// The omissions of the fields annotated nonnil in the struct docstring are reported at the
+// composite literals, while the unannotated fields (nonnil by default) may be omitted.
+//
+// nonnil(primary)
+type Pool struct {
+	primary   *Client
+	secondary *Client
+	clients   []*Client
+}
+
+func (p *Pool) use() string {
+	return p.primary.Name + p.secondary.Name
+}
+
+func newPool(c *Client) *Pool {
+	return &Pool{primary: c}
+}
+
+func newPoolOmitted(c *Client) *Pool {
+	return &Pool{secondary: c} //want "uninitialized field `primary` omitted from the composite literal"
+}
+
+func newPoolPositional(c *Client) *Pool {
+	return &Pool{c, c, nil}
+}
+
+func newPoolAssigned(c *Client) *Pool {
+	// The fields assigned after the literal are not considered omitted.
+	p := &Pool{}
+	p.primary = c
+	return p
+}
@@ -0,0 +1,30 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This package tests formatting the file paths in the error messages as links via a templated
+// path format.
+package pathformat
+
+func nilResult() *int {
+	return nil
+}
+
+func derefResult() int {
+	return *nilResult() //want "\n\t- https://example.com/blob/abc123/testdata/src/go.uber.org/pathformat/pathformat.go#L20: literal `nil` returned from `nilResult\\(\\)` in position 0\n\t- https://example.com/blob/abc123/testdata/src/go.uber.org/pathformat/pathformat.go#L24: result 0 of `nilResult\\(\\)` dereferenced\n"
+}
+
+func derefLocal() int {
+	var p *int
+	return *p //want "\n\t- https://example.com/blob/abc123/testdata/src/go.uber.org/pathformat/pathformat.go#L29: unassigned variable `p` dereferenced\n"
+}
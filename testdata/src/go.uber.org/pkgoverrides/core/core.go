@@ -0,0 +1,39 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package core checks that the overrides of the package override file apply to the packages
+// matching the patterns: the struct initialization checking is enabled here, along with the strict
+// checking of the map reads enabled by the flag.
+package core
+
+type A struct {
+	ptr *int
+}
+
+type B struct {
+	aptr *A
+}
+
+func structInit(init bool) *int {
+	b := &B{}
+	if init {
+		b.aptr = &A{}
+	}
+	return b.aptr.ptr //want "uninitialized field `aptr` of `B` accessed field `ptr`"
+}
+
+func mapRead() *int {
+	var nilMap map[string]*int
+	return nilMap["k"] //want "unassigned variable `nilMap` keyed into"
+}
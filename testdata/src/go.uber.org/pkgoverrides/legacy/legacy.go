@@ -0,0 +1,38 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package legacy checks that the packages not matching the patterns keep the options given by
+// the flags, while the options overridden for them take precedence over the flags.
+package legacy
+
+type A struct {
+	ptr *int
+}
+
+type B struct {
+	aptr *A
+}
+
+func structInit(init bool) *int {
+	b := &B{}
+	if init {
+		b.aptr = &A{}
+	}
+	return b.aptr.ptr
+}
+
+func mapRead() *int {
+	var nilMap map[string]*int
+	return nilMap["k"]
+}
@@ -0,0 +1,33 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This package tests that the errors are reported at the sites within the current module when
+// the nil flows cross into other modules with `-report-position-policy=module`.
+package positionpolicy
+
+import "go.uber.org/positionpolicy/upstream"
+
+func passNil() int {
+	// The nil value is dereferenced in the upstream module, hence the error is reported here.
+	return upstream.Deref(nil) //want "literal `nil` passed as arg `p` to `Deref\\(\\)`(.|\n)*function parameter `p` dereferenced"
+}
+
+func find() *int {
+	return nil
+}
+
+func localFlow() int {
+	// The flows within the current module are reported at the dereferences as usual.
+	return *find() //want "literal `nil` returned from `find\\(\\)`"
+}
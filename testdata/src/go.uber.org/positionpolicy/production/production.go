@@ -0,0 +1,30 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This package tests that the errors are reported at the nil sources with
+// `-report-position-policy=production`, while the nil flows in the messages are intact.
+package production
+
+func find() *int {
+	return nil //want "literal `nil` returned from `find\\(\\)`(.|\n)*result 0 of `find\\(\\)` dereferenced(.|\n)*at 1 other place"
+}
+
+func derefFind() int {
+	return *find() + *find()
+}
+
+func local() int {
+	var p *int //want "unassigned variable `p` dereferenced"
+	return *p
+}
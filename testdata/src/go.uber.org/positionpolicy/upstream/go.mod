@@ -0,0 +1,3 @@
+module go.uber.org/positionpolicy/upstream
+
+go 1.21
@@ -0,0 +1,41 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This package tests that the error messages explain the provenance of the inferred nilabilities
+// of the conflicting sites, even if they were inferred in upstream packages.
+package provenance
+
+import "go.uber.org/provenance/upstream"
+
+func upstreamField(s *upstream.S) int {
+	// The nilability of the field is inferred in the upstream package.
+	return *s.F //want "Provenance of the inferred nilability of Field F:\n\t- inferred NILABLE because literal `nil` assigned into field `F` at \"upstream/upstream.go:24:8\"\n\t- inferred NONNIL because field `F` dereferenced at \"provenance/provenance.go:23:10\""
+}
+
+func local() *int {
+	return nil
+}
+
+func localResult() int {
+	return *local() //want "inferred NILABLE because literal `nil` returned from `local\\(\\)` in position 0 at \"provenance/provenance.go:27:9\""
+}
+
+func wrap() *int {
+	return local()
+}
+
+func wrappedResult() int {
+	// Only the root cause of the nilability (i.e., the nil literal in local) is explained.
+	return *wrap() //want "Provenance of the inferred nilability of Result 0 of Function wrap:\n\t- inferred NILABLE because literal `nil` returned from `local\\(\\)` in position 0 at \"provenance/provenance.go:27:9\"\n\t- inferred NONNIL because result 0 of `wrap\\(\\)` dereferenced"
+}
@@ -112,6 +112,43 @@ func testAffiliation() {
 	newI2().foo() //want "result 0 of `newI2.*`"
 }
 
+// -----------------------------------
+// the below tests check the invocations of interface methods on interface values assigned locally from pointers of
+// known types. The invocations pass the pointers as receivers to the implementing methods, hence they are safe if the
+// implementing methods are nil-safe.
+
+type T struct {
+	f int
+}
+
+func (t *T) foo() {
+	print(t.f) //want "used as receiver to call `foo\\(\\)` through interface method `I.foo\\(\\)` via the assignment"
+}
+
+func newS() (*S, error) {
+	return nil, nil
+}
+
+func testInterfaceAssignment() {
+	// no error since `S.foo()` is nil-safe
+	var s *S
+	var i I = s
+	i.foo()
+
+	var j I
+	j, _ = newS()
+	j.foo()
+
+	// error since `T.foo()` is not nil-safe, reported inside `T.foo()`
+	var t *T
+	i = t
+	i.foo()
+
+	// TP since it's the case of untyped nil
+	var k I
+	k.foo() //want "unassigned variable `k` called `foo\\(\\)`"
+}
+
 // -----------------------------------
 // below tests check for non-pointer receivers. When you call a method on a non-pointer receiver (blank or named),
 // Go automatically dereferences the value and passes a copy of the value to the method. This means that such receivers
@@ -0,0 +1,83 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package namedtypes checks the nilable receivers of the methods with value receivers of named
+// map, slice and channel types, which (unlike the value receivers of the struct types) are passed
+// as is and hence can be nil.
+package namedtypes
+
+type M map[string]*int
+
+func (m M) get(k string) *int {
+	return m[k]
+}
+
+func (m M) set(k string, v *int) {
+	m[k] = v //want "written to at an index"
+}
+
+type L []int
+
+func (l L) length() int {
+	return len(l)
+}
+
+func (l L) first() int {
+	return l[0] //want "sliced into"
+}
+
+type C chan int
+
+func (c C) size() int {
+	return len(c)
+}
+
+type F func()
+
+func (f F) noop() {}
+
+func testNamedTypes() {
+	var m M
+	_ = m.get("a") // safe
+	m.set("a", nil)
+
+	var l L
+	_ = l.length() // safe
+	_ = l.first()
+
+	var c C
+	_ = c.size() // safe
+
+	var f F
+	f.noop() // safe
+
+	nonnil := M{}
+	nonnil.set("a", nil) // safe
+}
+
+func testPointerToNamedType() {
+	// The value receivers are dereferenced when the methods are invoked on pointers.
+	var p *M
+	_ = p.get("a") //want "called `get\\(\\)`"
+}
+
+type getter interface {
+	get(k string) *int
+}
+
+func testInterface() {
+	var m M
+	var g getter = m
+	_ = g.get("a") // safe, since `g` is nonnil (holding a nil map) and `M.get` handles a nil receiver
+}
@@ -0,0 +1,63 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This package tests that the errors at the dereferences in the regions recovering from panics
+// are downgraded with `-recovered-panics=downgrade`, while the flows through the deferred
+// functions re-panicking are reported as usual.
+package recoveredpanics
+
+import "fmt"
+
+type T struct {
+	f int
+}
+
+func recoverInFuncLit() int {
+	defer func() {
+		recover()
+	}()
+	var t *T
+	return t.f //want "accessed field `f`(.|\n)*Downgraded since the dereference is in a region recovering from panics"
+}
+
+func handlePanic() {
+	if r := recover(); r != nil {
+		fmt.Println(r)
+	}
+}
+
+func recoverInFuncDecl() int {
+	defer handlePanic()
+	var t *T
+	return t.f //want "accessed field `f`(.|\n)*Downgraded"
+}
+
+// The following dereferences are not recovered and hence reported as usual.
+
+func beforeDefer() int {
+	var t *T
+	x := t.f //want "accessed field `f`\n$"
+	defer handlePanic()
+	return x
+}
+
+func wrappedPanic() int {
+	defer func() {
+		if r := recover(); r != nil {
+			panic(fmt.Errorf("wrapped: %v", r))
+		}
+	}()
+	var t *T
+	return t.f //want "accessed field `f`\n$"
+}
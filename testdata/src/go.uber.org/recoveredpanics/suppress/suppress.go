@@ -0,0 +1,34 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This package tests that the errors at the dereferences in the regions recovering from panics
+// are not reported with `-recovered-panics=suppress`.
+package suppress
+
+type T struct {
+	f int
+}
+
+func recovered() int {
+	defer func() {
+		recover()
+	}()
+	var t *T
+	return t.f
+}
+
+func notRecovered() int {
+	var t *T
+	return t.f //want "accessed field `f`"
+}
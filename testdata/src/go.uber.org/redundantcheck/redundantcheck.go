@@ -0,0 +1,117 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This package tests that the nil checks on the values that are always nonnil are reported as
+// redundant with `-report-redundant-checks`.
+package redundantcheck
+
+type T struct {
+	f int
+}
+
+func allocated() int {
+	x := &T{}
+	if x != nil { //want "redundant nil check: `x` is always nonnil since it is newly allocated"
+		return x.f
+	}
+	return 0
+}
+
+func madeOnAllPaths(b bool) int {
+	var m map[int]int
+	if b {
+		m = make(map[int]int)
+	} else {
+		m = map[int]int{}
+	}
+	if m == nil { //want "redundant nil check: `m` is always nonnil since it is newly allocated"
+		return 1
+	}
+	return len(m)
+}
+
+func funcValue() {
+	f := func() {}
+	if f != nil { //want "redundant nil check: `f` is always nonnil since it is a function value"
+		f()
+	}
+}
+
+func newT() *T {
+	return &T{}
+}
+
+func derefResult() int {
+	return newT().f
+}
+
+func checkResult() int {
+	t := newT()
+	if t != nil { //want "redundant nil check: `t` is always nonnil since result 0 of `newT` is inferred to be nonnil"
+		return t.f
+	}
+	return 0
+}
+
+func checkAfterDeref(p *T) int {
+	v := p.f
+	if nil != p { //want "redundant nil check: `p` is always nonnil since parameter `p` is inferred to be nonnil"
+		v++
+	}
+	return v
+}
+
+// The nil checks below are necessary and hence not reported.
+
+func guardedParam(p *T) int {
+	if p != nil {
+		return p.f
+	}
+	return 0
+}
+
+func allocatedOnSomePaths(b bool) *T {
+	var t *T
+	if b {
+		t = &T{}
+	}
+	if t != nil {
+		return t
+	}
+	return &T{}
+}
+
+func errorReturning() (*T, error) {
+	return &T{}, nil
+}
+
+func checkErrorReturning() int {
+	t, err := errorReturning()
+	if err != nil {
+		return 0
+	}
+	if t != nil {
+		return t.f
+	}
+	return 0
+}
+
+func captured() int {
+	x := &T{}
+	func() { x = nil }()
+	if x != nil {
+		return x.f
+	}
+	return 0
+}
@@ -0,0 +1,70 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This package tests the conservative reflect-escape mode, where the nilable values escaping via
+// reflection, `unsafe.Pointer` conversions, or cgo calls are reported.
+package reflectescape
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+type T struct {
+	f int
+}
+
+func setViaReflect(v int) {
+	var t *T
+	// Setting a field of a nil pointer via reflection panics.
+	reflect.ValueOf(t).Elem().Field(0).SetInt(int64(v)) //want "escaped via `reflect.ValueOf`"
+}
+
+func setViaReflectNonnil(v int) {
+	t := &T{}
+	reflect.ValueOf(t).Elem().Field(0).SetInt(int64(v))
+}
+
+func setViaReflectGuarded(t *T, v int) {
+	if t != nil {
+		reflect.ValueOf(t).Elem().Field(0).SetInt(int64(v))
+	}
+}
+
+func setViaReflectParam(t *T, v int) {
+	reflect.ValueOf(t).Elem().Field(0).SetInt(int64(v)) //want "escaped via `reflect.ValueOf`"
+}
+
+func callSetViaReflectParam() {
+	setViaReflectParam(nil, 1)
+}
+
+func nonNilable(t T) reflect.Value {
+	// Values of non-nilable types cannot be nil.
+	return reflect.ValueOf(t)
+}
+
+func toUnsafe() unsafe.Pointer {
+	var p *int
+	return unsafe.Pointer(p) //want "escaped via conversion to `unsafe.Pointer`"
+}
+
+func toUnsafeNonnil() unsafe.Pointer {
+	i := 1
+	return unsafe.Pointer(&i)
+}
+
+func toUnsafeParenthesized(m map[int]*int) unsafe.Pointer {
+	return (unsafe.Pointer)(m[0]) //want "escaped via conversion to `unsafe.Pointer`"
+}
@@ -0,0 +1,45 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This package tests that the errors on the results of the functions inferred to return nonnil
+// results are reported at both the offending return statements and the dereferences with
+// `-report-at=both`.
+package reportat
+
+func find(k int) *int {
+	if k > 0 {
+		return nil //want "literal `nil` returned from `find\\(\\)` in position 0(.|\n)*at 1 other place"
+	}
+	v := k
+	return &v
+}
+
+func wrap(k int) *int {
+	return find(k) //want "result 0 of `find\\(\\)` returned from `wrap\\(\\)` in position 0(.|\n)*result 0 of `wrap\\(\\)` dereferenced"
+}
+
+func derefFind() int {
+	// The errors at the dereferences are grouped separately from the ones at the returns.
+	return *find(1) + *find(2) //want "result 0 of `find\\(\\)` dereferenced(.|\n)*at 1 other place"
+}
+
+func derefWrap() int {
+	return *wrap(3) //want "result 0 of `wrap\\(\\)` dereferenced"
+}
+
+func local() int {
+	// The errors not on the results of functions are only reported at the dereferences.
+	var p *int
+	return *p //want "unassigned variable `p` dereferenced"
+}
@@ -0,0 +1,68 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This package aims to test the annotations read from the sidecar annotation files (see
+// annotations.txt) for the upstream package that cannot be annotated in place.
+package sidecar //want "Invalid NilAway annotation: unknown object \"missing\""
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/sidecar/vendored"
+)
+
+func newClient() {
+	c := vendored.NewClient("foo")
+	print(c.Name) //want "result 0 of `NewClient\\(\\)`"
+}
+
+func timeout(c *vendored.Client) int {
+	if c == nil {
+		return 0
+	}
+	return *c.Timeout //want "field `Timeout`"
+}
+
+func do(c *vendored.Client) {
+	if c == nil {
+		return
+	}
+	s, err := c.Do(nil)
+	if err != nil {
+		return
+	}
+	print(*s)
+}
+
+func defaultClient() {
+	print(vendored.DefaultClient.Name) //want "global variable `DefaultClient`"
+}
+
+// The models of the standard library are overridden by the sidecar annotation files.
+
+func stdlibModels() {
+	print(context.Background().Err())
+	_ = *time.UTC
+}
+
+func overriddenStdlibModel(t time.Time) {
+	_ = *t.Location() //want "result 0 of `Location\\(\\)`"
+}
+
+// The malformed annotations for this package in the sidecar annotation files are reported.
+
+func malformed(x *int) *int { //want "Invalid NilAway annotation: unknown annotation target \"y\" \\(in sidecar annotation .*annotations.txt:9\\)"
+	return x
+}
@@ -0,0 +1,44 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vendored simulates a third-party package that cannot be annotated in place. Its
+// annotations are provided by the sidecar annotation file in the parent directory instead.
+package vendored
+
+// Client is a client.
+type Client struct {
+	Timeout *int
+	Name    *string
+}
+
+// DefaultClient is the default client.
+var DefaultClient *Client
+
+// NewClient returns a new client, or nil if the name is empty.
+func NewClient(name string) *Client {
+	if name == "" {
+		return nil
+	}
+	return &Client{Name: &name}
+}
+
+// Do does nothing.
+func (c *Client) Do(opts *int) (*string, error) {
+	return nil, nil
+}
+
+// Lookup returns the client for the name.
+func Lookup(name string) *Client {
+	return DefaultClient
+}
@@ -756,7 +756,7 @@ func testAppendNil(a []*int) {
 }
 
 // nonnil(a, a[], b)
-// nilable(c, result 0)
+// nilable(c)
 func testAppend(a []*int, b, c *int) {
 	b = c
 	a = append(a, b) //want "assigned deeply into parameter arg `a`"
@@ -768,7 +768,7 @@ func nilableFun() *int {
 	return nil
 }
 
-// nonnil(a, a[], b)
+// nonnil(a, a[])
 func testAppendNilableFunc(a []*int) {
 	a[0] = nilableFun()         //want "assigned deeply into parameter arg `a`"
 	a = append(a, nilableFun()) //want "assigned deeply into parameter arg `a`"
@@ -778,8 +778,8 @@ func testAppendNilableFunc(a []*int) {
 // nilable(b, b[])
 func testTheFirstArgumentOfAppend(a, b []*int) {
 	t := 1
-	a = append(b, &t) // TODO: this will be handled once we fix
-	print(*a[0])
+	a = append(b, &t) //want "deep read from parameter `b` assigned deeply into parameter arg `a`"
+	print(*a[0])      //want "deep read from parameter `b` dereferenced"
 }
 
 // nonnil(a, a[])
@@ -792,20 +792,20 @@ func testVariadicArgs(a, b []*int) {
 // nonnil(a, a[], nonnilvar)
 // nilable(nilablevar)
 func testMultipleAppendArgs(a []*int, nilablevar, nonnilvar *int) {
-	a = append(a, nonnilvar, nilablevar, nil) // TODO: this will be handled once we fix
+	a = append(a, nonnilvar, nilablevar, nil) //want "function parameter `nilablevar` assigned deeply into parameter arg `a`" "literal `nil` assigned deeply into parameter arg `a`"
 }
 
 func testAppendNilableForLocalVar() {
 	var a = make([]*int, 0)
 	a = append(a, nil)
-	print(*a[0]) //want "literal `nil` sliced into"
+	print(*a[0]) //want "literal `nil` dereferenced"
 }
 
 var a = make([]*int, 0)
 
 func testAppendNilableForGlobalVar() {
-	a = append(a, nil) //want "literal `nil` assigned into global variable `a`"
-	print(*a[0])       //want "literal `nil` sliced into"
+	a = append(a, nil) //want "literal `nil` assigned deeply into global variable `a`"
+	print(*a[0])       //want "literal `nil` dereferenced"
 }
 
 func testShadowAppend() {
@@ -813,3 +813,55 @@ func testShadowAppend() {
 	var append = func(s []*int, x ...*int) []*int { return s }
 	a = append(a, nil) // Safe here because the shadowed append does not touch the elements.
 }
+
+// nilable(b[])
+func testAppendSpreadToLocal(b []*int) {
+	var s []*int
+	s = append(s, b...)
+	print(*s[0]) //want "deep read from parameter `b` dereferenced"
+}
+
+// nilable(s[])
+func testAppendFromNilableElems(s []*int) {
+	t := 1
+	u := append(s, &t)
+	print(*u[0]) //want "deep read from parameter `s` dereferenced"
+}
+
+func testAppendNonnilToLocal() {
+	s := make([]*int, 0)
+	t := 1
+	s = append(s, &t)
+	print(*s[0])
+}
+
+// nilable(x)
+func testAppendGuardedValue(x *int) {
+	var s []*int
+	if x != nil {
+		s = append(s, x)
+		print(*s[0])
+	}
+	s = append(s, x)
+	print(*s[0]) //want "function parameter `x` dereferenced"
+}
+
+// nonnil(dst[])
+// nilable(src[])
+func testCopyIntoParam(dst, src []*int) {
+	copy(dst, src) //want "deep read from parameter `src` assigned deeply into parameter arg `dst`"
+}
+
+// nilable(src[])
+func testCopyIntoLocal(src []*int) {
+	dst := make([]*int, len(src))
+	copy(dst, src)
+	print(*dst[0]) //want "deep read from parameter `src` dereferenced"
+}
+
+// nonnil(src[])
+func testCopyNonnilIntoLocal(src []*int) {
+	dst := make([]*int, len(src))
+	copy(dst, src)
+	print(*dst[0])
+}
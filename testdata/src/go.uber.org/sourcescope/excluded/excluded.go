@@ -0,0 +1,30 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package excluded is excluded from the analysis scope (by -exclude-pkgs) in the tests of
+// `-require-source-in-scope`, where its client is modeled by a model pack instead.
+package excluded
+
+// Client is a client whose last error is modeled to be nilable.
+type Client struct{}
+
+// LastError returns the last error of the client.
+func (*Client) LastError() *Error {
+	return nil
+}
+
+// Error is an error of the client.
+type Error struct {
+	Msg string
+}
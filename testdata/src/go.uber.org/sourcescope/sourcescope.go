@@ -0,0 +1,28 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This package tests that the errors whose nil sources are outside the analysis scope are
+// annotated with `-require-source-in-scope=annotate`.
+package sourcescope
+
+import "go.uber.org/sourcescope/excluded"
+
+func outOfScope(c *excluded.Client) string {
+	return c.LastError().Msg //want "Source outside scope: the nil source is in package \"go.uber.org/sourcescope/excluded\""
+}
+
+func inScope() int {
+	var p *int
+	return *p //want "dereferenced\n$"
+}
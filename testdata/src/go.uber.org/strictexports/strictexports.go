@@ -0,0 +1,72 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This package tests that the parameters of the exported functions are treated as nilable with
+// `-strict-exports`, such that they must be guarded or annotated, and that only their first
+// unguarded dereferences are reported.
+package strictexports
+
+type T struct {
+	f int
+}
+
+func Exported(t *T, u *T) int {
+	a := t.f //want "NILABLE because arg `t` of the exported function may be passed nil(.|\n)*function parameter `t` accessed field `f`"
+	b := t.f
+	return a + b + u.f //want "NILABLE because arg `u` of the exported function may be passed nil(.|\n)*function parameter `u` accessed field `f`"
+}
+
+// The parameters are treated as nilable even if all local callers pass nonnil values.
+func Caller() int {
+	return Exported(&T{}, &T{})
+}
+
+func Guarded(t *T) int {
+	if t == nil {
+		return 0
+	}
+	return t.f
+}
+
+// nonnil(t)
+func Annotated(t *T) int {
+	return t.f
+}
+
+func Indirect(t *T) int {
+	return helper(t)
+}
+
+func helper(t *T) int {
+	return t.f //want "NILABLE because arg `t` of the exported function may be passed nil(.|\n)*passed as arg `t` to `helper\\(\\)`"
+}
+
+func (t *T) Method(other *T) int {
+	return other.f //want "NILABLE because arg `other` of the exported function may be passed nil"
+}
+
+// The methods of the unexported types and the unexported functions are not exported APIs.
+type unexportedT struct{}
+
+func (unexportedT) Method(t *T) int {
+	return t.f
+}
+
+func unexported(t *T) int {
+	return t.f
+}
+
+func Variadic(ts ...*T) int {
+	return len(ts)
+}
@@ -0,0 +1,52 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package count checks that the errors of the struct initialization checking are only counted in
+// the count mode, while the other errors are still reported as usual.
+package count //want "3 potential nil panic\\(s\\) from uninitialized struct fields"
+
+type A struct {
+	ptr *int
+}
+
+type B struct {
+	aptr *A
+}
+
+func newB() *B {
+	return &B{}
+}
+
+func local() int {
+	b := &B{}
+	return *b.aptr.ptr
+}
+
+func constructor() int {
+	b := newB()
+	return *b.aptr.ptr
+}
+
+func param(b *B) int {
+	return *b.aptr.ptr
+}
+
+func caller() int {
+	return param(&B{})
+}
+
+func other() int {
+	var p *int
+	return *p //want "unassigned variable `p` dereferenced"
+}
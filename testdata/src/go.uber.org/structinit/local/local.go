@@ -24,12 +24,12 @@ type A struct {
 
 func m() {
 	var b = &A{}
-	print(b.aptr.ptr) //want "uninitialized accessed field `ptr`"
+	print(b.aptr.ptr) //want "uninitialized field `aptr` of `A` accessed field `ptr`"
 }
 
 func m2() {
 	var b = A{}
-	print(b.aptr.ptr) //want "uninitialized accessed field `ptr`"
+	print(b.aptr.ptr) //want "uninitialized field `aptr` of `A` accessed field `ptr`"
 }
 
 func m3() {
@@ -55,7 +55,7 @@ func m6() {
 
 func m7() {
 	b := &A{}
-	print(b.aptr.ptr) //want "uninitialized accessed field `ptr`"
+	print(b.aptr.ptr) //want "uninitialized field `aptr` of `A` accessed field `ptr`"
 }
 
 func m8() {
@@ -66,7 +66,7 @@ func m8() {
 func m9() {
 	var b *A
 	b = &A{}
-	print(b.aptr.ptr) //want "uninitialized accessed field `ptr`"
+	print(b.aptr.ptr) //want "uninitialized field `aptr` of `A` accessed field `ptr`"
 }
 
 func m10() {
@@ -77,12 +77,12 @@ func m10() {
 
 func m14() {
 	b := new(A)
-	print(b.aptr.ptr) //want "uninitialized accessed field `ptr`"
+	print(b.aptr.ptr) //want "uninitialized field `aptr` of `A` accessed field `ptr`"
 }
 
 func m15() {
 	var b A
-	print(b.aptr.ptr) //want "uninitialized accessed field `ptr`"
+	print(b.aptr.ptr) //want "uninitialized field `aptr` of `A` accessed field `ptr`"
 }
 
 // this test checks that we only get error for `b` being nil, and not for its uninitialized fields
@@ -111,7 +111,7 @@ type A11 struct {
 
 func m11() {
 	var b = &A11{}
-	print(b.A11.ptr) //want "uninitialized accessed field `ptr`"
+	print(b.A11.ptr) //want "uninitialized field `A11` of `A11` accessed field `ptr`"
 }
 
 // Tests use of promoted fields
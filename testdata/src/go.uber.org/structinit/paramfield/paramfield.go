@@ -37,7 +37,7 @@ func callF12() {
 }
 
 func f12(c *A) {
-	print(c.aptr.ptr) //want "field `aptr` of argument 0 to `f12.*`"
+	print(c.aptr.ptr) //want "passed as field `aptr` of argument 0 to `f12\\(\\)`"
 }
 
 // Negative test
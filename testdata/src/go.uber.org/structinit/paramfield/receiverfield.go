@@ -35,7 +35,7 @@ func callM22() {
 }
 
 func (c *A) m22() {
-	print(c.aptr.ptr) //want "field `aptr` of method receiver `c`"
+	print(c.aptr.ptr) //want "field `aptr` of receiver `c` of `m22\\(\\)` accessed field `ptr`"
 }
 
 // Checking if Nilaway does not crash on unnamed receivers
@@ -43,7 +43,7 @@ func m12() *int {
 	b := &A{}
 	b.aptr = &A{}
 	populate12(b)
-	print(b.newPtr.ptr) //want "field `newPtr` of argument 0 to `populate12.*`"
+	print(b.newPtr.ptr) //want "field `newPtr` of param `x` as left by `populate12\\(\\)`"
 	return b.aptr.ptr
 }
 
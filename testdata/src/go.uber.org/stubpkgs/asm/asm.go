@@ -0,0 +1,41 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package asm simulates a package whose real source cannot be analyzed meaningfully (e.g., it is
+// backed by assembly). Its analysis is replaced by the stub package in the stubs directory.
+package asm
+
+// Entry is an entry.
+type Entry struct {
+	Name *string
+	Next *Entry
+}
+
+var _table = map[string]*Entry{}
+
+// Lookup returns the entry for the name, which is always found in the real implementation (but
+// NilAway cannot tell from this source).
+func Lookup(name string) *Entry {
+	return _table[name]
+}
+
+// Find returns the entry for the name if found.
+func Find(name string) *Entry {
+	return &Entry{}
+}
+
+// Get returns the next entry.
+func (e *Entry) Get() *Entry {
+	return e.Next
+}
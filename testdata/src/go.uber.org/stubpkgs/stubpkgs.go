@@ -0,0 +1,36 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This package aims to test the stub packages (see the stubs directory), whose annotations
+// replace the analysis of the real source of the stubbed packages.
+package stubpkgs
+
+import "go.uber.org/stubpkgs/asm"
+
+func lookup() *string {
+	// The real source returns a map read, but the stub declares the result nonnil.
+	return asm.Lookup("foo").Name
+}
+
+func find() *string {
+	// The real source always returns a nonnil entry, but the stub declares the result nilable.
+	return asm.Find("foo").Name //want "result 0 of `Find\\(\\)`"
+}
+
+func get(e *asm.Entry) *string {
+	if e == nil {
+		return nil
+	}
+	return e.Get().Name //want "result 0 of `Get\\(\\)`"
+}
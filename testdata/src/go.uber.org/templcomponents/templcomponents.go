@@ -0,0 +1,34 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This package tests the support for the code generated by templ: the components constructed as
+// `templ.ComponentFunc` conversions are nonnil, and the positions in the generated files are
+// mapped back to the ".templ" files.
+
+package templcomponents
+
+import (
+	"context"
+	"io"
+)
+
+func render(ctx context.Context, w io.Writer) error {
+	// The components constructed by the generated code as `templ.ComponentFunc` conversions are
+	// always nonnil.
+	if err := Hello("world").Render(ctx, w); err != nil {
+		return err
+	}
+	// The other generated functions are inferred as usual.
+	return Maybe(false).Render(ctx, w) //want "unassigned variable `c` returned from `Maybe\\(\\)`"
+}
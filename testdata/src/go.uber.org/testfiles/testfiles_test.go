@@ -0,0 +1,101 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testfiles
+
+import "testing"
+
+func TestFatal(t *testing.T) {
+	var x *int
+	if x == nil {
+		t.Fatal("x is nil")
+	}
+	print(*x)
+}
+
+func helperFatal(tb testing.TB) {
+	var x *int
+	if x == nil {
+		tb.Fatal("x is nil")
+	}
+	print(*x)
+}
+
+func helperFatalf(tb testing.TB) {
+	var x *int
+	if x == nil {
+		tb.Fatalf("%s is nil", "x")
+	}
+	print(*x)
+}
+
+func helperFailNow(tb testing.TB) {
+	var x *int
+	if x == nil {
+		tb.FailNow()
+	}
+	print(*x)
+}
+
+func helperSkip(tb testing.TB) {
+	var x *int
+	if x == nil {
+		tb.Skip("x is nil")
+	}
+	print(*x)
+}
+
+func helperError(tb testing.TB) {
+	var x *int
+	if x == nil {
+		// Error does not end the test.
+		tb.Error("x is nil")
+	}
+	print(*x) //want "dereferenced"
+}
+
+func TestCleanup(t *testing.T) {
+	var x *int
+	if x == nil {
+		// The cleanup closure runs after the test, so it does not end the flow here.
+		t.Cleanup(func() { t.Fatal("x is nil") })
+	}
+	print(*x) //want "dereferenced"
+}
+
+func TestSubtest(t *testing.T) {
+	var x *int
+	if x == nil {
+		// The subtest ends itself, but not the enclosing test.
+		t.Run("sub", func(t *testing.T) { t.Fatal("x is nil") })
+	}
+	print(*x) //want "dereferenced"
+}
+
+func TestCleanupCaptures(t *testing.T) {
+	var x *int
+	// The cleanup closure runs after the test, where `x` is still nil.
+	t.Cleanup(func() {
+		print(*x) //want "unassigned variable `x` passed as arg `x`"
+	})
+}
+
+func TestCleanupAssignedLater(t *testing.T) {
+	var x *int
+	// The cleanup closure runs after the test, where `x` is assigned.
+	t.Cleanup(func() {
+		print(*x)
+	})
+	x = new(int)
+}
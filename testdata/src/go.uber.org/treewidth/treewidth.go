@@ -0,0 +1,42 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This package aims to test the summarization of assertion trees exceeding the configured width
+// limit, which is set to 2 for this package.
+package treewidth
+
+func testWithinLimit() {
+	x, y := new(int), new(int)
+	print(*x)
+	print(*y)
+}
+
+func testExceedsLimit() {
+	x, y, z := new(int), new(int), new(int)
+	// Consumers are added in reverse order, hence the dereference of `z` is the least recently
+	// touched one and gets summarized.
+	print(*x)
+	print(*y)
+	print(*z) //want "value no longer tracked"
+}
+
+func testTouchedAgain() {
+	x, y, z := new(int), new(int), new(int)
+	print(*y)
+	// `x` is touched again here, so the dereference of `z` below is the least recently touched
+	// one when `y` is added.
+	print(*x)
+	print(*z) //want "value no longer tracked"
+	print(*x)
+}
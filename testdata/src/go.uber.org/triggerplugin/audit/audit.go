@@ -0,0 +1,33 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package audit is a stub of an audit sink modeled by the trigger plugin registered in
+// TestTriggerPlugin.
+package audit
+
+// Event is an audited event.
+type Event struct {
+	Name string
+}
+
+// Record records the event. The plugin requires the event to be nonnil.
+func Record(e *Event) {
+	_ = e
+}
+
+// Lookup looks up a recorded event. Its result is always nonnil here, but the plugin models it as
+// nilable.
+func Lookup(name string) *Event {
+	return &Event{Name: name}
+}
@@ -0,0 +1,47 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package triggerplugin tests the triggers contributed by the plugin registered in
+// TestTriggerPlugin, which requires the values passed to `audit.Record` to be nonnil and models
+// the result of `audit.Lookup` as nilable.
+package triggerplugin
+
+import "go.uber.org/triggerplugin/audit"
+
+func recordNil() {
+	var e *audit.Event
+	audit.Record(e) //want "passed to the audit sink"
+}
+
+func recordNonnil() {
+	audit.Record(&audit.Event{Name: "login"})
+}
+
+func recordChecked(e *audit.Event) {
+	if e != nil {
+		audit.Record(e)
+	}
+}
+
+func lookup() string {
+	e := audit.Lookup("login")
+	return e.Name //want "looked up from the audit sink"
+}
+
+func lookupChecked() string {
+	if e := audit.Lookup("login"); e != nil {
+		return e.Name
+	}
+	return ""
+}
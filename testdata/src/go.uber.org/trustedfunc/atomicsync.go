@@ -0,0 +1,96 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This file tests the trusted models of the atomic and sync primitives, i.e., `atomic.Pointer`
+// (whose loads are nilable unless a nonnil value is stored first) and `sync.Map` (whose loads
+// are nilable unless guarded by the ok result).
+
+package trustedfunc
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+type node struct {
+	f int
+}
+
+var _ptr atomic.Pointer[node]
+
+func testLoad() int {
+	return _ptr.Load().f //want "accessed field `f`"
+}
+
+func testLoadChecked() int {
+	if n := _ptr.Load(); n != nil {
+		return n.f
+	}
+	if _ptr.Load() != nil {
+		return _ptr.Load().f
+	}
+	return 0
+}
+
+func testStoreNonnil() int {
+	var p atomic.Pointer[node]
+	p.Store(&node{})
+	return p.Load().f
+}
+
+func testStoreNil(p *atomic.Pointer[node]) int {
+	p.Store(nil)
+	return p.Load().f //want "accessed field `f`"
+}
+
+func testSwap(p *atomic.Pointer[node]) int {
+	old := p.Swap(&node{})
+	x := p.Load().f
+	return x + old.f //want "accessed field `f`"
+}
+
+type holder struct {
+	p atomic.Pointer[node]
+}
+
+func testStoreField(h *holder) int {
+	h.p.Store(&node{})
+	return h.p.Load().f
+}
+
+var _map sync.Map
+
+// nonnil(result 0)
+func testMapLoad(k string) any {
+	v, _ := _map.Load(k)
+	return v //want "returned"
+}
+
+// nonnil(result 0)
+func testMapLoadChecked(k string) any {
+	if v, ok := _map.Load(k); ok {
+		return v
+	}
+	v, loaded := _map.LoadAndDelete(k)
+	if !loaded {
+		return 0
+	}
+	return v
+}
+
+// nonnil(result 0)
+func testMapSwap(k string) any {
+	prev, _ := _map.Swap(k, 1)
+	return prev //want "returned"
+}
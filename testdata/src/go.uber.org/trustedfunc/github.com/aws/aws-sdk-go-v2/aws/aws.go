@@ -0,0 +1,42 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// <nilaway no inference>
+package aws
+
+// these stubs simulate the real `github.com/aws/aws-sdk-go-v2/aws` package because we can't import it in tests
+
+// nilable(result 0)
+func String(v string) *string {
+	return &v
+}
+
+// nilable(result 0)
+func Int64(v int64) *int64 {
+	return &v
+}
+
+// nilable(result 0)
+func StringSlice(vs []string) []*string {
+	ps := make([]*string, len(vs))
+	for i := range vs {
+		ps[i] = &vs[i]
+	}
+	return ps
+}
+
+// nilable(result 0)
+func StringValue(v *string) *string {
+	return v
+}
@@ -0,0 +1,60 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// <nilaway no inference>
+package lo
+
+// these stubs simulate the real `github.com/samber/lo` package because we can't import it in tests
+
+// nilable(err)
+func must(err any, messageArgs ...interface{}) {
+	if err == nil {
+		return
+	}
+	if ok, isBool := err.(bool); isBool && ok {
+		return
+	}
+	panic(err)
+}
+
+// nilable(val, err)
+func Must[T any](val T, err any, messageArgs ...interface{}) T {
+	must(err, messageArgs...)
+	return val
+}
+
+// nilable(val, err)
+func Must1[T any](val T, err any, messageArgs ...interface{}) T {
+	return Must(val, err, messageArgs...)
+}
+
+// nilable(val1, val2, err)
+func Must2[T1, T2 any](val1 T1, val2 T2, err any, messageArgs ...interface{}) (T1, T2) {
+	must(err, messageArgs...)
+	return val1, val2
+}
+
+// nilable(result 0)
+func ToPtr[T any](x T) *T {
+	return &x
+}
+
+// nilable(result 0)
+func ToSlicePtr[T any](collection []T) []*T {
+	result := make([]*T, len(collection))
+	for i := range collection {
+		result[i] = &collection[i]
+	}
+	return result
+}
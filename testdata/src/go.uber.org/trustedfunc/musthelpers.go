@@ -0,0 +1,60 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This file tests the trusted models of the generic "must" helpers (e.g., `lo.Must`), whose
+// results are nonnil since they panic if the error passed along with the values is not nil.
+
+package trustedfunc
+
+import (
+	"go.uber.org/trustedfunc/github.com/samber/lo"
+)
+
+type res struct {
+	f int
+}
+
+// nilable(result 0)
+func open() (*res, error) {
+	return nil, nil
+}
+
+// nilable(result 0, result 1)
+func openPair() (*res, *res, error) {
+	return nil, nil, nil
+}
+
+func testMust() int {
+	r := lo.Must(open())
+	return r.f
+}
+
+func testMustExplicitInstantiation() int {
+	r := lo.Must[*res](open())
+	return r.f
+}
+
+func testMust1(r *res, err error) int {
+	return lo.Must1(r, err).f
+}
+
+func testMust2() int {
+	r1, r2 := lo.Must2(openPair())
+	return r1.f + r2.f
+}
+
+func testWithoutMust() int {
+	r, _ := open()
+	return r.f //want "accessed field `f`"
+}
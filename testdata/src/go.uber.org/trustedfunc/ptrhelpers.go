@@ -0,0 +1,69 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This file tests the trusted models of the helpers converting values into pointers (e.g.,
+// `lo.ToPtr`), as well as of the conversions and the type assertions, whose results are nonnil.
+
+package trustedfunc
+
+import (
+	"go.uber.org/trustedfunc/github.com/aws/aws-sdk-go-v2/aws"
+	"go.uber.org/trustedfunc/github.com/samber/lo"
+)
+
+type cfg struct {
+	name    *string
+	retries *int64
+}
+
+func testToPtr(n int) int {
+	p := lo.ToPtr(n)
+	return *p
+}
+
+func testToPtrExplicitInstantiation(n int) int {
+	return *lo.ToPtr[int](n)
+}
+
+func testToSlicePtr(ns []int) int {
+	ps := lo.ToSlicePtr(ns)
+	return len(ps)
+}
+
+func testAWSHelpers(name string) *cfg {
+	c := &cfg{name: aws.String(name), retries: aws.Int64(3)}
+	print(*c.name, *c.retries)
+	return c
+}
+
+func testAWSSlice(names []string) int {
+	return len(aws.StringSlice(names))
+}
+
+func testUntrustedHelper(name *string) string {
+	// `StringValue` is not a pointer helper, so its annotated nilable result is not trusted.
+	return *aws.StringValue(name) //want "dereferenced"
+}
+
+type alias cfg
+
+// nilable(c)
+func testConversion(c *cfg) *string {
+	return (*alias)(c).name
+}
+
+// nilable(c)
+func testTypeAssertion(c *cfg) *string {
+	return any(c).(*cfg).name
+}
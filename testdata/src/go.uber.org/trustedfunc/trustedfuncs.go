@@ -1001,5 +1001,44 @@ func errorsAs(err error, num string, dummy bool) {
 		if errors.As(*nilError, &exitErr) { //want "unassigned variable `nilError` dereferenced"
 			print(*exitErr) // But this is fine!
 		}
+	case "result stored in a variable":
+		var exitErr *exec.ExitError
+		ok := errors.As(err, &exitErr)
+		if ok {
+			print(*exitErr)
+		}
+		if ok := errors.As(err, &exitErr); !ok {
+			return
+		}
+		print(*exitErr)
+	case "result stored in a variable checked later":
+		var exitErr *exec.ExitError
+		ok := errors.As(err, &exitErr)
+		print("other statements")
+		if ok {
+			// We only understand the variable checked right after the call.
+			print(*exitErr) //want "unassigned variable `exitErr` dereferenced"
+		}
+	case "errors.As in a non-conditional expression":
+		var exitErr *exec.ExitError
+		print(errors.As(err, &exitErr) || exitErr.ProcessState != nil) //want "unassigned variable `exitErr` accessed field `ProcessState`"
+		print(errors.As(err, &exitErr) && exitErr.ProcessState != nil)
+	}
+}
+
+// nilable(err)
+func errorsUnwrap(err error, num string) string {
+	switch num {
+	case "unchecked":
+		return errors.Unwrap(err).Error() //want "determined to be nilable by a trusted function called `Error\\(\\)`"
+	case "checked":
+		if inner := errors.Unwrap(err); inner != nil {
+			return inner.Error()
+		}
+	case "chain":
+		for e := errors.Unwrap(err); e != nil; e = errors.Unwrap(e) {
+			print(e.Error())
+		}
 	}
+	return ""
 }
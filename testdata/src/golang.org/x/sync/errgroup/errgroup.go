@@ -0,0 +1,49 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package errgroup is a minimal stub of golang.org/x/sync/errgroup for testing.
+package errgroup
+
+import "sync"
+
+// Group is a collection of goroutines working on subtasks of a common task.
+type Group struct {
+	wg  sync.WaitGroup
+	err error
+}
+
+// Go calls the given function in a new goroutine.
+func (g *Group) Go(f func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := f(); err != nil {
+			g.err = err
+		}
+	}()
+}
+
+// Wait blocks until all function calls from the Go method have returned, then returns the first
+// non-nil error (if any) from them.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	return g.err
+}
+
+// TryGo calls the given function in a new goroutine only if the number of active goroutines in
+// the group is currently below the configured limit, and reports whether the goroutine was started.
+func (g *Group) TryGo(f func() error) bool {
+	g.Go(f)
+	return true
+}
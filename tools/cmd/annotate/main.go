@@ -0,0 +1,114 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package main implements a tool that runs NilAway inference on the given packages and writes
+// `// nilable(...)` / `// nonnil(...)` annotation comments for the exported APIs whose nilability
+// has been determined by inference. This helps libraries document and lock in their nilability
+// contracts for downstream consumers.
+//
+// Usage:
+//
+//	annotate [-nilaway <path>] [-dry-run] [-- NilAway flags...] <packages>
+//
+// Flags after `--` are passed to NilAway as is (e.g., `-include-pkgs`).
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+)
+
+// Diagnostic is the diagnostic reported by NilAway in the annotate mode, i.e., a declaration with
+// inferred annotations.
+type Diagnostic struct {
+	// Posn is the position string of the diagnostic.
+	Posn string `json:"posn"`
+	// Message is the message describing the inferred annotations.
+	Message string `json:"message"`
+}
+
+// ParseDiagnostics parses the JSON output of NilAway and returns the diagnostics sorted by their
+// positions.
+func ParseDiagnostics(out []byte) ([]Diagnostic, error) {
+	// pkg name -> analyzer name -> list of diagnostics (or an error object).
+	var result map[string]map[string]json.RawMessage
+	if err := json.Unmarshal(out, &result); err != nil {
+		return nil, fmt.Errorf("decode nilaway output: %w", err)
+	}
+
+	var diagnostics []Diagnostic
+	for pkg, m := range result {
+		raw, ok := m["nilaway"]
+		if !ok {
+			continue
+		}
+		var ds []Diagnostic
+		if err := json.Unmarshal(raw, &ds); err != nil {
+			return nil, fmt.Errorf("analysis of package %q failed: %s", pkg, string(raw))
+		}
+		diagnostics = append(diagnostics, ds...)
+	}
+	slices.SortFunc(diagnostics, func(a, b Diagnostic) int {
+		return cmp.Or(cmp.Compare(a.Posn, b.Posn), cmp.Compare(a.Message, b.Message))
+	})
+	return diagnostics, nil
+}
+
+// Run runs the NilAway binary in the annotate mode with the given arguments, and writes the
+// summary of the annotated declarations to the writer. The source files are rewritten in place
+// unless dryRun is set.
+func Run(writer io.Writer, nilaway string, dryRun bool, args []string) error {
+	nilawayArgs := []string{"-json", "-pretty-print=false", "-fix-mode=annotate", fmt.Sprintf("-fix=%t", !dryRun)}
+	cmd := exec.Command(nilaway, append(nilawayArgs, args...)...)
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("run nilaway: %w\n%s", err, string(out))
+	}
+
+	diagnostics, err := ParseDiagnostics(out)
+	if err != nil {
+		return err
+	}
+	for _, d := range diagnostics {
+		fmt.Fprintf(writer, "%s: %s\n", d.Posn, d.Message)
+	}
+	verb := "Annotated"
+	if dryRun {
+		verb = "Would annotate"
+	}
+	fmt.Fprintf(writer, "%s %d declaration(s)\n", verb, len(diagnostics))
+	return nil
+}
+
+func main() {
+	nilaway := flag.String("nilaway", "nilaway", "Path to the NilAway binary")
+	dryRun := flag.Bool("dry-run", false, "Only print the inferred annotations without rewriting the source files")
+	flag.Parse()
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: annotate [-nilaway <path>] [-dry-run] [-- NilAway flags...] <packages>")
+		os.Exit(2)
+	}
+
+	if err := Run(os.Stdout, *nilaway, *dryRun, flag.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "FAILED: %s\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,52 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestParseDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	out := []byte(`{
+		"example.com/b": {"nilaway": [
+			{"posn": "b.go:3:6", "message": "inferred annotations for ` + "`B`" + `: nonnil(result 0)"}
+		]},
+		"example.com/a": {"nilaway": [
+			{"posn": "a.go:5:6", "message": "inferred annotations for ` + "`A`" + `: nilable(p)"}
+		]},
+		"example.com/c": {}
+	}`)
+	diagnostics, err := ParseDiagnostics(out)
+	require.NoError(t, err)
+	require.Equal(t, []Diagnostic{
+		{Posn: "a.go:5:6", Message: "inferred annotations for `A`: nilable(p)"},
+		{Posn: "b.go:3:6", Message: "inferred annotations for `B`: nonnil(result 0)"},
+	}, diagnostics)
+
+	_, err = ParseDiagnostics([]byte(`{"example.com/a": {"nilaway": {"error": "failed"}}}`))
+	require.ErrorContains(t, err, "example.com/a")
+
+	_, err = ParseDiagnostics([]byte(`not json`))
+	require.ErrorContains(t, err, "decode nilaway output")
+}
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
@@ -0,0 +1,70 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Repo is a repository in the benchmark corpus, pinned at a specific version such that the
+// measurements are comparable across runs.
+type Repo struct {
+	// Name is the short name of the repository used in the report and as its checkout directory.
+	Name string `json:"name"`
+	// URL is the git URL of the repository.
+	URL string `json:"url"`
+	// Ref is the pinned tag (or branch) of the repository to check out.
+	Ref string `json:"ref"`
+	// IncludePkgs is the value of the `-include-pkgs` flag of NilAway, i.e., the package prefix
+	// of the repository.
+	IncludePkgs string `json:"include_pkgs"`
+	// Patterns are the package patterns to analyze, relative to the root of the repository
+	// (default "./...").
+	Patterns []string `json:"patterns,omitempty"`
+}
+
+// DefaultCorpus is the default benchmark corpus, a set of large OSS repositories covering
+// different coding styles.
+var DefaultCorpus = []Repo{
+	{Name: "zap", URL: "https://github.com/uber-go/zap", Ref: "v1.27.0", IncludePkgs: "go.uber.org/zap"},
+	{Name: "cobra", URL: "https://github.com/spf13/cobra", Ref: "v1.8.1", IncludePkgs: "github.com/spf13/cobra"},
+	{Name: "grpc-go", URL: "https://github.com/grpc/grpc-go", Ref: "v1.65.0", IncludePkgs: "google.golang.org/grpc"},
+	{Name: "prometheus", URL: "https://github.com/prometheus/prometheus", Ref: "v2.53.1", IncludePkgs: "github.com/prometheus/prometheus"},
+	{Name: "hugo", URL: "https://github.com/gohugoio/hugo", Ref: "v0.128.2", IncludePkgs: "github.com/gohugoio/hugo"},
+}
+
+// LoadCorpus reads the benchmark corpus from the JSON file (a list of [Repo]s), or returns the
+// [DefaultCorpus] if the file name is empty.
+func LoadCorpus(file string) ([]Repo, error) {
+	if file == "" {
+		return DefaultCorpus, nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read corpus: %w", err)
+	}
+	var repos []Repo
+	if err := json.Unmarshal(data, &repos); err != nil {
+		return nil, fmt.Errorf("decode corpus %q: %w", file, err)
+	}
+	for _, r := range repos {
+		if r.Name == "" || r.URL == "" || r.Ref == "" || r.IncludePkgs == "" {
+			return nil, fmt.Errorf("corpus %q: name, url, ref and include_pkgs are required: %+v", file, r)
+		}
+	}
+	return repos, nil
+}
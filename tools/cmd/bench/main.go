@@ -0,0 +1,258 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package main implements a benchmark for NilAway that checks out pinned versions of large OSS
+// repositories (see [DefaultCorpus]), runs two NilAway versions on them with timing and memory
+// instrumentation, and writes a comparison report. This complements the golden test, which
+// compares the errors reported on the stdlib for correctness.
+//
+// Usage (at the root of the NilAway git repository):
+//
+//	bench [-base-branch main] [-test-branch <ref>] [-corpus <file>] [-work-dir <dir>] [-runs 1] [-result-file <file>]
+//
+// The repositories and the built NilAway binaries are cached in the work directory across runs.
+// Env vars such as GOMEMLIMIT and GOGC are inherited by the NilAway runs.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Run benchmarks the base and test versions of NilAway on the corpus and writes the report.
+func Run(writer io.Writer, baseBranch, testBranch string, corpus []Repo, workDir string, runs int) error {
+	out, err := exec.Command("git", "rev-parse", "--show-toplevel").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("get root of git repository: %w, output: %q", err, out)
+	}
+	root := strings.TrimSpace(string(out))
+
+	// If test branch is not specified, use the current branch.
+	if testBranch == "" {
+		out, err = exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD").CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("get current branch name: %w, output: %q", err, out)
+		}
+		testBranch = strings.TrimSpace(string(out))
+		log.Printf("test branch is not specified, using current branch %q", testBranch)
+	}
+
+	// Build the NilAway binaries of both versions.
+	versions := [2]Version{{Name: baseBranch}, {Name: testBranch}}
+	var binaries [2]string
+	for i := range versions {
+		out, err = exec.Command("git", "rev-parse", "--short", versions[i].Name).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("get short commit hash of %q: %w, output: %q", versions[i].Name, err, out)
+		}
+		versions[i].ShortSHA = strings.TrimSpace(string(out))
+		if versions[i].Name == "HEAD" {
+			versions[i].Name = versions[i].ShortSHA
+		}
+		binaries[i], err = buildNilAway(root, workDir, versions[i].ShortSHA)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Check out the corpus and measure both versions on each repository.
+	results := make([]Result, 0, len(corpus))
+	for _, repo := range corpus {
+		dir, err := checkout(workDir, repo)
+		if err != nil {
+			return err
+		}
+		result := Result{Repo: repo}
+		for run := 0; run < runs; run++ {
+			// Interleave the runs of the versions to reduce the effects of the drifts in the
+			// machine load.
+			for i, m := range [...]*Measurement{&result.Base, &result.Test} {
+				log.Printf("running %s (%s) on %s@%s (run %d/%d)", versions[i].Name, versions[i].ShortSHA, repo.Name, repo.Ref, run+1, runs)
+				measured, err := measure(binaries[i], dir, repo)
+				if err != nil {
+					return fmt.Errorf("benchmark %s on %s: %w", versions[i].ShortSHA, repo.Name, err)
+				}
+				if run == 0 || measured.Duration < m.Duration {
+					m.Duration = measured.Duration
+				}
+				m.MaxRSS = max(m.MaxRSS, measured.MaxRSS)
+				m.Errors = measured.Errors
+			}
+		}
+		results = append(results, result)
+	}
+
+	WriteReport(writer, versions[0], versions[1], results)
+	return nil
+}
+
+// buildNilAway builds the NilAway binary at the commit in a temporary git worktree, and returns
+// the path to the binary. The binary is reused if it has been built before.
+func buildNilAway(root, workDir, sha string) (string, error) {
+	binary, err := filepath.Abs(filepath.Join(workDir, "bin", "nilaway-"+sha))
+	if err != nil {
+		return "", fmt.Errorf("get path of binary: %w", err)
+	}
+	if _, err := os.Stat(binary); err == nil {
+		return binary, nil
+	}
+
+	worktree, err := os.MkdirTemp("", "nilaway-bench-")
+	if err != nil {
+		return "", fmt.Errorf("create worktree directory: %w", err)
+	}
+	defer os.RemoveAll(worktree)
+	if out, err := runIn(root, "git", "worktree", "add", "--detach", worktree, sha); err != nil {
+		return "", fmt.Errorf("add worktree for %s: %w, output: %q", sha, err, out)
+	}
+	defer func() {
+		if out, err := runIn(root, "git", "worktree", "remove", "--force", worktree); err != nil {
+			log.Printf("failed to remove worktree %q: %v, output: %q", worktree, err, out)
+		}
+	}()
+
+	log.Printf("building NilAway at %s", sha)
+	if out, err := runIn(worktree, "go", "build", "-o", binary, "./cmd/nilaway"); err != nil {
+		return "", fmt.Errorf("build NilAway at %s: %w, output: %q", sha, err, out)
+	}
+	return binary, nil
+}
+
+// checkout checks out the repository at its pinned version in the work directory (if it has not
+// been checked out before), downloads its dependencies, and returns the directory of the checkout.
+func checkout(workDir string, repo Repo) (string, error) {
+	dir, err := filepath.Abs(filepath.Join(workDir, "corpus", repo.Name+"@"+repo.Ref))
+	if err != nil {
+		return "", fmt.Errorf("get path of checkout: %w", err)
+	}
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		log.Printf("checking out %s@%s", repo.URL, repo.Ref)
+		if out, err := runIn("", "git", "clone", "--quiet", "--depth", "1", "--branch", repo.Ref, repo.URL, dir); err != nil {
+			return "", fmt.Errorf("clone %s@%s: %w, output: %q", repo.URL, repo.Ref, err, out)
+		}
+	}
+	// Download the dependencies up front such that they are not included in the measurements.
+	if out, err := runIn(dir, "go", "mod", "download"); err != nil {
+		return "", fmt.Errorf("download dependencies of %s: %w, output: %q", repo.Name, err, out)
+	}
+	return dir, nil
+}
+
+// measure runs the NilAway binary on the repository checked out in the directory and returns the
+// measurement.
+func measure(binary, dir string, repo Repo) (Measurement, error) {
+	patterns := repo.Patterns
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	args := append([]string{"-json", "-pretty-print=false", "-include-pkgs=" + repo.IncludePkgs}, patterns...)
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(binary, args...)
+	cmd.Dir = dir
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	// Inherit env vars such that users can control the resource usages via GOMEMLIMIT, GOGC etc.
+	// env vars.
+	cmd.Env = os.Environ()
+	start := time.Now()
+	err := cmd.Run()
+	duration := time.Since(start)
+	// NilAway may exit with a non-zero code when errors are reported, which is fine as long as it
+	// produces the JSON output.
+	if err != nil && stdout.Len() == 0 {
+		return Measurement{}, fmt.Errorf("run NilAway: %w, stderr: %q", err, stderr.String())
+	}
+	errs, err := CountDiagnostics(stdout.Bytes())
+	if err != nil {
+		return Measurement{}, err
+	}
+	return Measurement{Duration: duration, MaxRSS: maxRSS(cmd.ProcessState), Errors: errs}, nil
+}
+
+// CountDiagnostics returns the number of diagnostics in the JSON output of NilAway.
+func CountDiagnostics(out []byte) (int, error) {
+	// Package name -> "nilaway" -> slice of diagnostics.
+	var output map[string]map[string]json.RawMessage
+	if err := json.Unmarshal(out, &output); err != nil {
+		return 0, fmt.Errorf("decode nilaway output: %w", err)
+	}
+	count := 0
+	for pkg, m := range output {
+		raw, ok := m["nilaway"]
+		if !ok {
+			continue
+		}
+		var diagnostics []json.RawMessage
+		if err := json.Unmarshal(raw, &diagnostics); err != nil {
+			return 0, fmt.Errorf("analysis of package %q failed: %s", pkg, string(raw))
+		}
+		count += len(diagnostics)
+	}
+	return count, nil
+}
+
+// runIn runs the command in the directory (or the current directory if empty) and returns its
+// combined output.
+func runIn(dir string, name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	return cmd.CombinedOutput()
+}
+
+func main() {
+	fset := flag.NewFlagSet("bench", flag.ExitOnError)
+	baseBranch := fset.String("base-branch", "main", "the base branch to compare against")
+	testBranch := fset.String("test-branch", "", "the test branch to benchmark (default current branch)")
+	corpusFile := fset.String("corpus", "", "JSON file listing the repositories to benchmark on (default the built-in corpus)")
+	workDir := fset.String("work-dir", filepath.Join(os.TempDir(), "nilaway-bench"), "the directory to cache the checked out repositories and built binaries in")
+	runs := fset.Int("runs", 1, "the number of runs of each version on each repository (the minimum time is reported)")
+	resultFile := fset.String("result-file", "", "the file to write the report to, default stdout")
+	if err := fset.Parse(os.Args[1:]); err != nil {
+		log.Printf("failed to parse flags: %v\n", err)
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+	if *runs < 1 {
+		log.Fatalf("invalid number of runs %d", *runs)
+	}
+
+	corpus, err := LoadCorpus(*corpusFile)
+	if err != nil {
+		log.Fatalf("failed to load corpus: %v", err)
+	}
+
+	writer := os.Stdout
+	if *resultFile != "" {
+		w, err := os.OpenFile(*resultFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+		if err != nil {
+			log.Fatalf("failed to open file %q: %v", *resultFile, err)
+		}
+		writer = w
+	}
+
+	if err := Run(writer, *baseBranch, *testBranch, corpus, *workDir, *runs); err != nil {
+		log.Printf("failed to run benchmark: %v", err)
+		os.Exit(1)
+	}
+}
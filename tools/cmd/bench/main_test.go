@@ -0,0 +1,107 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestLoadCorpus(t *testing.T) {
+	t.Parallel()
+
+	repos, err := LoadCorpus("")
+	require.NoError(t, err)
+	require.Equal(t, DefaultCorpus, repos)
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "corpus.json")
+	require.NoError(t, os.WriteFile(file, []byte(`[
+		{"name": "zap", "url": "https://github.com/uber-go/zap", "ref": "v1.27.0", "include_pkgs": "go.uber.org/zap", "patterns": ["./zapcore/..."]}
+	]`), 0o644))
+	repos, err = LoadCorpus(file)
+	require.NoError(t, err)
+	require.Equal(t, []Repo{{
+		Name:        "zap",
+		URL:         "https://github.com/uber-go/zap",
+		Ref:         "v1.27.0",
+		IncludePkgs: "go.uber.org/zap",
+		Patterns:    []string{"./zapcore/..."},
+	}}, repos)
+
+	require.NoError(t, os.WriteFile(file, []byte(`[{"name": "zap", "url": "https://github.com/uber-go/zap"}]`), 0o644))
+	_, err = LoadCorpus(file)
+	require.ErrorContains(t, err, "required")
+
+	_, err = LoadCorpus(filepath.Join(dir, "missing.json"))
+	require.ErrorContains(t, err, "read corpus")
+}
+
+func TestCountDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	count, err := CountDiagnostics([]byte(`{
+		"example.com/a": {"nilaway": [{"posn": "a.go:1:1", "message": "a"}, {"posn": "a.go:2:1", "message": "b"}]},
+		"example.com/b": {"nilaway": [{"posn": "b.go:1:1", "message": "c"}]},
+		"example.com/c": {}
+	}`))
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+
+	_, err = CountDiagnostics([]byte(`{"example.com/a": {"nilaway": {"error": "failed"}}}`))
+	require.ErrorContains(t, err, "example.com/a")
+
+	_, err = CountDiagnostics([]byte(`not json`))
+	require.ErrorContains(t, err, "decode nilaway output")
+}
+
+func TestWriteReport(t *testing.T) {
+	t.Parallel()
+
+	results := []Result{
+		{
+			Repo: Repo{Name: "zap", Ref: "v1.27.0"},
+			Base: Measurement{Duration: 10 * time.Second, MaxRSS: 100 << 20, Errors: 3},
+			Test: Measurement{Duration: 12 * time.Second, MaxRSS: 50 << 20, Errors: 2},
+		},
+		{
+			Repo: Repo{Name: "cobra", Ref: "v1.8.1"},
+			Base: Measurement{Duration: 2 * time.Second, Errors: 1},
+			Test: Measurement{Duration: 2 * time.Second, Errors: 1},
+		},
+	}
+	var buf bytes.Buffer
+	WriteReport(&buf, Version{Name: "main", ShortSHA: "abc1234"}, Version{Name: "def5678", ShortSHA: "def5678"}, results)
+	require.Equal(t, `## Benchmark
+
+Base: main (`+"`abc1234`"+`), test: `+"`def5678`"+`
+
+| Repository | Time (base) | Time (test) | Δ | Memory (base) | Memory (test) | Δ | Errors (base) | Errors (test) |
+|---|---:|---:|---:|---:|---:|---:|---:|---:|
+| zap@v1.27.0 | 10s | 12s | +20.0% | 100.0 MiB | 50.0 MiB | -50.0% | 3 | 2 |
+| cobra@v1.8.1 | 2s | 2s | +0.0% | - | - | - | 1 | 1 |
+| **Total** | 12s | 14s | +16.7% | 100.0 MiB | 50.0 MiB | -50.0% | 4 | 3 |
+`, buf.String())
+}
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
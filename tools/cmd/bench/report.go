@@ -0,0 +1,104 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// Measurement is the measurement of a NilAway version on a repository in the corpus.
+type Measurement struct {
+	// Duration is the (minimum) wall time of the runs.
+	Duration time.Duration
+	// MaxRSS is the (maximum) peak resident set size of the runs in bytes, 0 if unknown.
+	MaxRSS int64
+	// Errors is the number of errors reported.
+	Errors int
+}
+
+// Version is a NilAway version being benchmarked.
+type Version struct {
+	// Name is the git ref of the version (e.g., a branch name).
+	Name string
+	// ShortSHA is the short SHA of the version.
+	ShortSHA string
+}
+
+// Result is the comparison between the base and test versions on a repository in the corpus.
+type Result struct {
+	// Repo is the repository.
+	Repo Repo
+	// Base and Test are the measurements of the base and test versions, respectively.
+	Base, Test Measurement
+}
+
+// WriteReport writes the comparison report between the base and test versions in markdown.
+func WriteReport(writer io.Writer, base, test Version, results []Result) {
+	fmt.Fprintf(writer, "## Benchmark\n\n")
+	fmt.Fprintf(writer, "Base: %s, test: %s\n\n", versionString(base), versionString(test))
+	fmt.Fprintf(writer, "| Repository | Time (base) | Time (test) | Δ | Memory (base) | Memory (test) | Δ | Errors (base) | Errors (test) |\n")
+	fmt.Fprintf(writer, "|---|---:|---:|---:|---:|---:|---:|---:|---:|\n")
+
+	var total [2]Measurement
+	for _, r := range results {
+		writeRow(writer, fmt.Sprintf("%s@%s", r.Repo.Name, r.Repo.Ref), r.Base, r.Test)
+		for i, m := range [...]Measurement{r.Base, r.Test} {
+			total[i].Duration += m.Duration
+			total[i].MaxRSS = max(total[i].MaxRSS, m.MaxRSS)
+			total[i].Errors += m.Errors
+		}
+	}
+	// The total time is the sum of the runs, while the total memory is the peak of the runs.
+	writeRow(writer, "**Total**", total[0], total[1])
+}
+
+// writeRow writes a row of the report table.
+func writeRow(writer io.Writer, name string, base, test Measurement) {
+	fmt.Fprintf(writer, "| %s | %s | %s | %s | %s | %s | %s | %d | %d |\n",
+		name,
+		base.Duration.Round(10*time.Millisecond), test.Duration.Round(10*time.Millisecond),
+		change(float64(base.Duration), float64(test.Duration)),
+		formatBytes(base.MaxRSS), formatBytes(test.MaxRSS),
+		change(float64(base.MaxRSS), float64(test.MaxRSS)),
+		base.Errors, test.Errors,
+	)
+}
+
+// versionString returns the display string of the version.
+func versionString(v Version) string {
+	if v.Name == v.ShortSHA {
+		return fmt.Sprintf("`%s`", v.ShortSHA)
+	}
+	return fmt.Sprintf("%s (`%s`)", v.Name, v.ShortSHA)
+}
+
+// change returns the relative change from the base value to the test value, or "-" if the base
+// value is unknown.
+func change(base, test float64) string {
+	if base == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%+.1f%%", 100*(test-base)/base)
+}
+
+// formatBytes returns the human-readable size in MiB, or "-" if the size is unknown.
+func formatBytes(n int64) string {
+	if n == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+}
@@ -0,0 +1,271 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package main implements a tool that evaluates the recall of NilAway against the nil panics
+// observed at runtime. It parses the nil panics from the given stack traces (e.g., application
+// logs or Sentry exports), runs NilAway on the given packages, and reports which panics are
+// flagged by NilAway at the dereference sites. The missed panics (and the functions containing
+// them) help prioritize the features NilAway is missing.
+//
+// Usage:
+//
+//	panic-recall -traces <files> [-format auto|go|sentry] [-nilaway <path>] [-- NilAway flags...] <packages>
+//
+// Flags after `--` are passed to NilAway as is (e.g., `-include-pkgs`). The file paths recorded
+// in the traces (i.e., on the build machines) are matched against the positions reported by
+// NilAway by their longest common suffixes, so they do not need to be rewritten.
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Diagnostic is the diagnostic reported by NilAway.
+type Diagnostic struct {
+	// Posn is the position string of the diagnostic.
+	Posn string `json:"posn"`
+	// Message is the message reported by NilAway.
+	Message string `json:"message"`
+}
+
+// ParseDiagnostics parses the JSON output of NilAway and returns the diagnostics.
+func ParseDiagnostics(out []byte) ([]Diagnostic, error) {
+	// pkg name -> analyzer name -> list of diagnostics (or an error object).
+	var result map[string]map[string]json.RawMessage
+	if err := json.Unmarshal(out, &result); err != nil {
+		return nil, fmt.Errorf("decode nilaway output: %w", err)
+	}
+
+	var diagnostics []Diagnostic
+	for pkg, m := range result {
+		raw, ok := m["nilaway"]
+		if !ok {
+			continue
+		}
+		var ds []Diagnostic
+		if err := json.Unmarshal(raw, &ds); err != nil {
+			return nil, fmt.Errorf("analysis of package %q failed: %s", pkg, string(raw))
+		}
+		diagnostics = append(diagnostics, ds...)
+	}
+	return diagnostics, nil
+}
+
+// ParsePanics parses the nil panics from the trace data in the given format ("go", "sentry", or
+// "auto" to detect the format from the data).
+func ParsePanics(data []byte, format string) ([]Panic, error) {
+	if format == "auto" {
+		format = "go"
+		if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+			format = "sentry"
+		}
+	}
+	switch format {
+	case "go":
+		return ParseGoTraces(data), nil
+	case "sentry":
+		return ParseSentryEvents(data)
+	default:
+		return nil, fmt.Errorf("unsupported trace format %q", format)
+	}
+}
+
+// SiteResult is the evaluation result of a dereference site where nil panics were observed.
+type SiteResult struct {
+	// Panic is the (first) panic observed at the site.
+	Panic Panic
+	// Count is the number of panics observed at the site.
+	Count int
+	// Flagged indicates whether NilAway reported an error at the site.
+	Flagged bool
+}
+
+// Report is the recall report of NilAway against the observed nil panics.
+type Report struct {
+	// Sites are the results of the dereference sites, ordered by the number of panics observed
+	// (most frequent first).
+	Sites []SiteResult
+}
+
+// Evaluate matches the panics against the diagnostics reported by NilAway and returns the report.
+// A panic is flagged if a diagnostic is reported at the same line of the same file, where the file
+// paths are compared by their longest common suffix (see sameFile).
+func Evaluate(panics []Panic, diagnostics []Diagnostic) Report {
+	type position struct {
+		file string
+		line int
+	}
+	var reported []position
+	for _, d := range diagnostics {
+		file, line, ok := parsePosn(d.Posn)
+		if ok {
+			reported = append(reported, position{file: file, line: line})
+		}
+	}
+
+	index := make(map[string]int)
+	var sites []SiteResult
+	for _, p := range panics {
+		key := p.String()
+		if i, ok := index[key]; ok {
+			sites[i].Count++
+			continue
+		}
+		flagged := slices.ContainsFunc(reported, func(pos position) bool {
+			return pos.line == p.Line && sameFile(pos.file, p.File)
+		})
+		index[key] = len(sites)
+		sites = append(sites, SiteResult{Panic: p, Count: 1, Flagged: flagged})
+	}
+	slices.SortStableFunc(sites, func(a, b SiteResult) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Panic.String(), b.Panic.String()))
+	})
+	return Report{Sites: sites}
+}
+
+// Recall returns the numbers of the flagged panics and of all panics.
+func (r Report) Recall() (flagged, total int) {
+	for _, s := range r.Sites {
+		total += s.Count
+		if s.Flagged {
+			flagged += s.Count
+		}
+	}
+	return flagged, total
+}
+
+// Write writes the summary of the report, followed by the missed sites (most frequent first),
+// and the flagged sites.
+func (r Report) Write(writer io.Writer) {
+	flagged, total := r.Recall()
+	recall := 0.0
+	if total > 0 {
+		recall = 100 * float64(flagged) / float64(total)
+	}
+	fmt.Fprintf(writer, "Recall: %d/%d nil panic(s) flagged (%.1f%%) at %d site(s)\n", flagged, total, recall, len(r.Sites))
+	for _, flaggedSites := range []bool{false, true} {
+		header := "Missed"
+		if flaggedSites {
+			header = "Flagged"
+		}
+		fmt.Fprintf(writer, "\n%s:\n", header)
+		for _, s := range r.Sites {
+			if s.Flagged == flaggedSites {
+				fmt.Fprintf(writer, "  %s (%s): %d panic(s)\n", s.Panic, s.Panic.Function, s.Count)
+			}
+		}
+	}
+}
+
+// parsePosn parses the file and line from the "<file>:<line>:<column>" position string.
+func parsePosn(posn string) (string, int, bool) {
+	rest, _, ok := cutLast(posn, ":")
+	if !ok {
+		return "", 0, false
+	}
+	file, lineStr, ok := cutLast(rest, ":")
+	if !ok {
+		return "", 0, false
+	}
+	line, err := strconv.Atoi(lineStr)
+	if err != nil {
+		return "", 0, false
+	}
+	return file, line, true
+}
+
+// cutLast slices s around the last instance of sep.
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+// sameFile returns true if the two paths likely refer to the same file, i.e., they share the
+// longest common suffix of path components that includes at least the enclosing directory of the
+// file (or the whole shorter path). This allows matching the paths recorded on the build machines
+// (e.g., "/build/src/example.com/pkg/file.go") against the local ones (e.g., "/home/u/pkg/file.go").
+func sameFile(a, b string) bool {
+	as := strings.Split(strings.Trim(filepath.ToSlash(a), "/"), "/")
+	bs := strings.Split(strings.Trim(filepath.ToSlash(b), "/"), "/")
+	common := 0
+	for common < len(as) && common < len(bs) && as[len(as)-1-common] == bs[len(bs)-1-common] {
+		common++
+	}
+	return common >= 2 || (common > 0 && common == min(len(as), len(bs)))
+}
+
+// Run parses the panics from the trace files, runs the NilAway binary with the given arguments,
+// and writes the recall report to the writer.
+func Run(writer io.Writer, nilaway string, traceFiles []string, format string, args []string) error {
+	var panics []Panic
+	for _, file := range traceFiles {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("read traces: %w", err)
+		}
+		ps, err := ParsePanics(data, format)
+		if err != nil {
+			return fmt.Errorf("parse traces in %q: %w", file, err)
+		}
+		panics = append(panics, ps...)
+	}
+	if len(panics) == 0 {
+		return fmt.Errorf("no nil panics found in the traces")
+	}
+
+	// Each error is reported at its own dereference site (instead of being grouped with the
+	// errors sharing the same nil source) such that all sites can be matched.
+	nilawayArgs := []string{"-json", "-pretty-print=false", "-group-error-messages=false"}
+	cmd := exec.Command(nilaway, append(nilawayArgs, args...)...)
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("run nilaway: %w\n%s", err, string(out))
+	}
+	diagnostics, err := ParseDiagnostics(out)
+	if err != nil {
+		return err
+	}
+
+	Evaluate(panics, diagnostics).Write(writer)
+	return nil
+}
+
+func main() {
+	nilaway := flag.String("nilaway", "nilaway", "Path to the NilAway binary")
+	traces := flag.String("traces", "", "Comma-separated list of files containing the stack traces of the nil panics")
+	format := flag.String("format", "auto", "Format of the trace files: \"go\" (Go stack traces, e.g., in logs), \"sentry\" (JSON export of Sentry events), or \"auto\"")
+	flag.Parse()
+	if *traces == "" || flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: panic-recall -traces <files> [-format auto|go|sentry] [-nilaway <path>] [-- NilAway flags...] <packages>")
+		os.Exit(2)
+	}
+
+	if err := Run(os.Stdout, *nilaway, strings.Split(*traces, ","), *format, flag.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "FAILED: %s\n", err)
+		os.Exit(1)
+	}
+}
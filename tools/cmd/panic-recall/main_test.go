@@ -0,0 +1,126 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+const _goTraces = `2024/05/01 10:00:00 starting server
+panic: runtime error: invalid memory address or nil pointer dereference
+[signal SIGSEGV: segmentation violation code=0x1 addr=0x0 pc=0x47b0b1]
+
+goroutine 1 [running]:
+example.com/app/handler.(*Server).handle(0x0, {0x4c2f60, 0xc000010030})
+	/build/src/example.com/app/handler/server.go:42 +0x11
+main.main()
+	/build/src/example.com/app/main.go:10 +0x25
+exit status 2
+panic: something else went wrong
+
+goroutine 1 [running]:
+main.main()
+	/build/src/example.com/app/main.go:12 +0x25
+panic: assignment to entry in nil map
+
+goroutine 7 [running]:
+example.com/app/store.Put(...)
+	/build/src/example.com/app/store/store.go:7
+main.main()
+	/build/src/example.com/app/main.go:15 +0x25
+`
+
+const _sentryEvents = `{"exception": {"values": [{"type": "runtime.Error", "value": "invalid memory address or nil pointer dereference", "stacktrace": {"frames": [
+	{"function": "main", "module": "main", "abs_path": "/build/src/example.com/app/main.go", "lineno": 10},
+	{"function": "(*Server).handle", "module": "example.com/app/handler", "abs_path": "/build/src/example.com/app/handler/server.go", "lineno": 42},
+	{"function": "panicmem", "module": "runtime", "abs_path": "/usr/local/go/src/runtime/panic.go", "lineno": 261}
+]}}]}}
+{"exception": {"values": [{"type": "*errors.errorString", "value": "timeout", "stacktrace": {"frames": []}}]}}
+`
+
+func TestParsePanics(t *testing.T) {
+	t.Parallel()
+
+	handle := Panic{Function: "example.com/app/handler.(*Server).handle", File: "/build/src/example.com/app/handler/server.go", Line: 42}
+	put := Panic{Function: "example.com/app/store.Put", File: "/build/src/example.com/app/store/store.go", Line: 7}
+
+	panics, err := ParsePanics([]byte(_goTraces), "auto")
+	require.NoError(t, err)
+	require.Equal(t, []Panic{handle, put}, panics)
+
+	panics, err = ParsePanics([]byte(_sentryEvents), "auto")
+	require.NoError(t, err)
+	require.Equal(t, []Panic{handle}, panics)
+
+	panics, err = ParsePanics([]byte("["+_sentryEvents[:len(_sentryEvents)-1]+"]"), "sentry")
+	require.ErrorContains(t, err, "decode sentry events")
+	require.Nil(t, panics)
+
+	_, err = ParsePanics([]byte(_goTraces), "unknown")
+	require.ErrorContains(t, err, "unsupported trace format")
+}
+
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+
+	handle := Panic{Function: "handler.(*Server).handle", File: "/build/src/example.com/app/handler/server.go", Line: 42}
+	put := Panic{Function: "store.Put", File: "/build/src/example.com/app/store/store.go", Line: 7}
+	diagnostics := []Diagnostic{
+		{Posn: "/home/user/app/handler/server.go:42:9", Message: "Potential nil panic detected."},
+		{Posn: "/home/user/app/handler/server.go:50:3", Message: "Potential nil panic detected."},
+		// Not the same file although sharing the file name.
+		{Posn: "/home/user/app/other/store.go:7:2", Message: "Potential nil panic detected."},
+	}
+
+	report := Evaluate([]Panic{put, handle, put}, diagnostics)
+	require.Equal(t, []SiteResult{
+		{Panic: put, Count: 2, Flagged: false},
+		{Panic: handle, Count: 1, Flagged: true},
+	}, report.Sites)
+	flagged, total := report.Recall()
+	require.Equal(t, 1, flagged)
+	require.Equal(t, 3, total)
+
+	var buf bytes.Buffer
+	report.Write(&buf)
+	require.Equal(t, `Recall: 1/3 nil panic(s) flagged (33.3%) at 2 site(s)
+
+Missed:
+  /build/src/example.com/app/store/store.go:7 (store.Put): 2 panic(s)
+
+Flagged:
+  /build/src/example.com/app/handler/server.go:42 (handler.(*Server).handle): 1 panic(s)
+`, buf.String())
+}
+
+func TestSameFile(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, sameFile("/build/src/example.com/app/main.go", "app/main.go"))
+	require.True(t, sameFile("app/main.go", "/build/src/example.com/app/main.go"))
+	require.True(t, sameFile("/a/main.go", "/a/main.go"))
+	require.True(t, sameFile("main.go", "/a/main.go"))
+	require.True(t, sameFile("/home/user/app/main.go", "/build/src/example.com/app/main.go"))
+	require.False(t, sameFile("/build/src/example.com/app/main.go", "pp/main.go"))
+	require.False(t, sameFile("/build/app/main.go", "/home/other/main.go"))
+}
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
@@ -0,0 +1,178 @@
+//  Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Panic is a nil panic observed at runtime, located at the frame that dereferenced the nil value.
+type Panic struct {
+	// Function is the fully qualified name of the function containing the dereference.
+	Function string
+	// File is the path of the file containing the dereference, as recorded by the trace (i.e., on
+	// the machine that built the binary).
+	File string
+	// Line is the line of the dereference.
+	Line int
+}
+
+// String returns the position of the panic in the "<file>:<line>" format.
+func (p Panic) String() string {
+	return fmt.Sprintf("%s:%d", p.File, p.Line)
+}
+
+// _nilPanicMessages are the substrings of the panic messages that indicate nil panics.
+var _nilPanicMessages = []string{
+	"nil pointer dereference",
+	"assignment to entry in nil map",
+}
+
+// isNilPanicMessage returns true if the panic message indicates a nil panic.
+func isNilPanicMessage(msg string) bool {
+	for _, s := range _nilPanicMessages {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// _goFuncLine matches the function lines of the Go stack traces, e.g.,
+	// "example.com/pkg.(*T).Method(0x0, ...)" or "main.main()".
+	_goFuncLine = regexp.MustCompile(`^(\S+)\(.*\)$`)
+	// _goFileLine matches the file lines of the Go stack traces, e.g.,
+	// "	/src/example.com/pkg/file.go:42 +0x1d".
+	_goFileLine = regexp.MustCompile(`^\s+(\S+\.go):(\d+)(?: \+0x[0-9a-f]+)?$`)
+)
+
+// ParseGoTraces parses the nil panics from the Go stack traces in the text (e.g., application
+// logs), which may contain other lines between the traces. Each panic is located at the first
+// frame outside the runtime, i.e., the frame that dereferenced the nil value.
+func ParseGoTraces(data []byte) []Panic {
+	var panics []Panic
+	// inTrace indicates that a nil panic is seen and its dereferencing frame is being searched.
+	inTrace := false
+	function := ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "panic: "):
+			inTrace = isNilPanicMessage(trimmed)
+			function = ""
+		case !inTrace:
+			continue
+		case _goFuncLine.MatchString(trimmed) && !strings.HasPrefix(line, "\t"):
+			function = _goFuncLine.FindStringSubmatch(trimmed)[1]
+		case _goFileLine.MatchString(line):
+			m := _goFileLine.FindStringSubmatch(line)
+			if function == "" || isRuntimeFunction(function) {
+				continue
+			}
+			lineNum, err := strconv.Atoi(m[2])
+			if err != nil {
+				continue
+			}
+			panics = append(panics, Panic{Function: function, File: m[1], Line: lineNum})
+			inTrace = false
+		}
+	}
+	return panics
+}
+
+// isRuntimeFunction returns true if the function (of a stack frame) is in the runtime, i.e., it
+// is not the one that dereferenced the nil value.
+func isRuntimeFunction(function string) bool {
+	return strings.HasPrefix(function, "runtime.") || function == "panic"
+}
+
+// sentryEvent is the subset of a Sentry event (as exported by the Sentry API) that is relevant
+// for locating the nil panics.
+type sentryEvent struct {
+	Exception struct {
+		Values []struct {
+			Type       string `json:"type"`
+			Value      string `json:"value"`
+			Stacktrace struct {
+				Frames []struct {
+					Function string `json:"function"`
+					Module   string `json:"module"`
+					Filename string `json:"filename"`
+					AbsPath  string `json:"abs_path"`
+					Lineno   int    `json:"lineno"`
+				} `json:"frames"`
+			} `json:"stacktrace"`
+		} `json:"values"`
+	} `json:"exception"`
+}
+
+// ParseSentryEvents parses the nil panics from the Sentry events in the data, which is either a
+// JSON array of events, or a stream of JSON events (e.g., one per line). The frames of the Sentry
+// stack traces are ordered from the oldest to the newest, hence each panic is located at the last
+// frame outside the runtime.
+func ParseSentryEvents(data []byte) ([]Panic, error) {
+	var events []sentryEvent
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &events); err != nil {
+			return nil, fmt.Errorf("decode sentry events: %w", err)
+		}
+	} else {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		for decoder.More() {
+			var event sentryEvent
+			if err := decoder.Decode(&event); err != nil {
+				return nil, fmt.Errorf("decode sentry event: %w", err)
+			}
+			events = append(events, event)
+		}
+	}
+
+	var panics []Panic
+	for _, event := range events {
+		for _, exception := range event.Exception.Values {
+			if !isNilPanicMessage(exception.Type + ": " + exception.Value) {
+				continue
+			}
+			frames := exception.Stacktrace.Frames
+			for i := len(frames) - 1; i >= 0; i-- {
+				f := frames[i]
+				function := f.Function
+				if f.Module != "" {
+					function = f.Module + "." + f.Function
+				}
+				if isRuntimeFunction(function) || f.Lineno == 0 {
+					continue
+				}
+				file := f.AbsPath
+				if file == "" {
+					file = f.Filename
+				}
+				panics = append(panics, Panic{Function: function, File: file, Line: f.Lineno})
+				break
+			}
+		}
+	}
+	return panics, nil
+}
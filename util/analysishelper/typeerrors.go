@@ -0,0 +1,56 @@
+//	Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package analysishelper
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// ReferencesBrokenObjects returns whether the node references (or defines) objects that are
+// missing or broken due to type errors in the package, i.e., identifiers without objects or
+// objects of invalid types. Analyzing such nodes would operate on incomplete type information,
+// hence NilAway skips them in packages with type errors (see analysis.Analyzer.RunDespiteErrors).
+func ReferencesBrokenObjects(info *types.Info, node ast.Node) bool {
+	broken := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if broken {
+			return false
+		}
+		ident, ok := n.(*ast.Ident)
+		if !ok || ident.Name == "_" {
+			return true
+		}
+		obj, ok := info.Defs[ident]
+		if !ok {
+			obj, ok = info.Uses[ident]
+		}
+		if !ok {
+			broken = true
+			return false
+		}
+		switch obj.(type) {
+		case nil, *types.Label, *types.PkgName:
+			// The symbolic variables in type switch headers do not have objects, and labels and
+			// package names do not have types.
+			return true
+		}
+		if obj.Type() == nil || obj.Type() == types.Typ[types.Invalid] {
+			broken = true
+			return false
+		}
+		return true
+	})
+	return broken
+}
@@ -0,0 +1,78 @@
+//	Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package analysishelper
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReferencesBrokenObjects(t *testing.T) {
+	t.Parallel()
+
+	src := `package p
+
+import "fmt"
+
+type T struct{ f *int }
+
+func good(x any) {
+	switch v := x.(type) {
+	case *T:
+		_ = v.f
+	}
+L:
+	for {
+		break L
+	}
+	fmt.Println(T{f: nil})
+}
+
+func undefinedName() { _ = undefined }
+
+func undefinedField(t *T) { _ = t.g }
+
+func invalidType(x Undefined) {}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, 0)
+	require.NoError(t, err)
+	info := &types.Info{
+		Defs: make(map[*ast.Ident]types.Object),
+		Uses: make(map[*ast.Ident]types.Object),
+	}
+	var typeErrors []error
+	conf := types.Config{Importer: importer.Default(), Error: func(err error) { typeErrors = append(typeErrors, err) }}
+	_, _ = conf.Check("p", fset, []*ast.File{file}, info)
+	require.Len(t, typeErrors, 3)
+
+	broken := make(map[string]bool)
+	for _, decl := range file.Decls {
+		if f, ok := decl.(*ast.FuncDecl); ok {
+			broken[f.Name.Name] = ReferencesBrokenObjects(info, f)
+		}
+	}
+	require.Equal(t, map[string]bool{
+		"good":           false,
+		"undefinedName":  true,
+		"undefinedField": true,
+		"invalidType":    true,
+	}, broken)
+}
@@ -112,6 +112,13 @@ func printExpr(writer io.Writer, fset *token.FileSet, e ast.Expr) (err error) {
 		}
 		_, err = io.WriteString(writer, "["+indexExpr+"]")
 
+	case *ast.FuncLit:
+		// the body of a function literal is elided (e.g., `func() *int {...}`)
+		if err = printer.Fprint(writer, fset, node.Type); err != nil {
+			return
+		}
+		_, err = io.WriteString(writer, " {...}")
+
 	default:
 		err = printer.Fprint(writer, fset, e)
 	}
@@ -131,3 +138,23 @@ func ExtractLHSRHS(node ast.Node) (lhs, rhs []ast.Expr) {
 	}
 	return
 }
+
+// IsCgoFile returns true if the file is rewritten by cgo from an original source file (i.e., one
+// with `import "C"`). Such files contain "//line" directives pointing back to the original source
+// files, hence we identify them by their package clauses that are mapped to different files.
+func IsCgoFile(fset *token.FileSet, file *ast.File) bool {
+	return fset.PositionFor(file.Package, true).Filename != fset.PositionFor(file.Package, false).Filename
+}
+
+// HasLineDirectives returns true if the file contains "//line" (or "/*line") directives, e.g., the
+// ones emitted by the code generators (such as yacc) pointing back to the authored sources.
+func HasLineDirectives(file *ast.File) bool {
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			if strings.HasPrefix(comment.Text, "//line ") || strings.HasPrefix(comment.Text, "/*line ") {
+				return true
+			}
+		}
+	}
+	return false
+}
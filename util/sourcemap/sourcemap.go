@@ -0,0 +1,82 @@
+//  Copyright (c) 2025 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sourcemap maps the positions in the files generated by code generators that do not
+// emit "//line" directives (e.g., templ) back to their authored sources for reporting.
+package sourcemap
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+	"sync"
+)
+
+// Mapper maps the position in the generated file back to the authored source, and returns false
+// if the position cannot be mapped.
+type Mapper func(fset *token.FileSet, file *ast.File, pos token.Pos) (token.Position, bool)
+
+// mapping is a registered mapper for the generated files whose names match the regex.
+type mapping struct {
+	file   *regexp.Regexp
+	mapper Mapper
+}
+
+var (
+	_mu       sync.RWMutex
+	_mappings []mapping
+)
+
+// Register registers the mapper for the generated files whose names match the regex. It must be
+// called before the analysis starts (e.g., in an init function).
+func Register(file *regexp.Regexp, mapper Mapper) {
+	_mu.Lock()
+	defer _mu.Unlock()
+	_mappings = append(_mappings, mapping{file: file, mapper: mapper})
+}
+
+// Registered returns true if a mapper is registered for the generated file with the given name.
+func Registered(fileName string) bool {
+	_mu.RLock()
+	defer _mu.RUnlock()
+	for _, m := range _mappings {
+		if m.file.MatchString(fileName) {
+			return true
+		}
+	}
+	return false
+}
+
+// Position maps the position back to the authored source if it is in one of the given files
+// generated by a code generator with a registered mapper, and returns false otherwise.
+func Position(fset *token.FileSet, files []*ast.File, pos token.Pos) (token.Position, bool) {
+	tokFile := fset.File(pos)
+	if tokFile == nil {
+		return token.Position{}, false
+	}
+
+	_mu.RLock()
+	defer _mu.RUnlock()
+	for _, m := range _mappings {
+		if !m.file.MatchString(tokFile.Name()) {
+			continue
+		}
+		for _, file := range files {
+			if fset.File(file.Pos()) == tokFile {
+				return m.mapper(fset, file, pos)
+			}
+		}
+	}
+	return token.Position{}, false
+}
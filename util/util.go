@@ -24,6 +24,7 @@ import (
 	"strings"
 
 	"go.uber.org/nilaway/config"
+	"go.uber.org/nilaway/util/sourcemap"
 	"golang.org/x/tools/go/analysis"
 )
 
@@ -42,6 +43,9 @@ var BuiltinAppend = types.Universe.Lookup("append")
 // BuiltinNew is the builtin "new" function object.
 var BuiltinNew = types.Universe.Lookup("new")
 
+// BuiltinCopy is the builtin "copy" function object.
+var BuiltinCopy = types.Universe.Lookup("copy")
+
 // TypeIsDeep checks if a type is an expression that admits deep nilability, such as maps, slices, arrays, etc.
 // Only consider pointers to deep types (e.g., `var x *[]int`) as deep type,
 // not pointers to basic types (e.g., `var x *int`) or struct types (e.g., `var x *S`)
@@ -61,6 +65,42 @@ func TypeIsDeep(t types.Type) bool {
 	return false
 }
 
+// TypeIsDeepAtSite checks if a type admits deep nilability when it is the type of an annotation
+// site such as a struct field, a function parameter, or a function result. In addition to the deep
+// types (see TypeIsDeep), this includes named types of maps, slices, arrays, pointers, and
+// channels (e.g., `type S []*int`), such that two fields of the same named type can have
+// different deep nilability.
+//
+// Named types whose elements are interfaces (e.g., `type M map[string]any`) are excluded: such
+// elements are mostly consumed through type assertions and switches, where the sites of the same
+// named type are conflated anyway, so separate deep annotation sites would only add triggers
+// without distinguishing anything. They keep sharing the deep nilability of the named type.
+func TypeIsDeepAtSite(t types.Type) bool {
+	if TypeIsDeep(t) {
+		return true
+	}
+	t, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	var elem types.Type
+	switch u := t.Underlying().(type) {
+	case *types.Slice:
+		elem = u.Elem()
+	case *types.Array:
+		elem = u.Elem()
+	case *types.Map:
+		elem = u.Elem()
+	case *types.Pointer:
+		elem = u.Elem()
+	case *types.Chan:
+		elem = u.Elem()
+	default:
+		return false
+	}
+	return !types.IsInterface(elem)
+}
+
 // TypeIsSlice returns true if `t` is of slice type
 func TypeIsSlice(t types.Type) bool {
 	switch t.(type) {
@@ -131,6 +171,14 @@ func TypeIsDeeplyChan(t types.Type) bool {
 	return false
 }
 
+// TypeIsDeeplyNilableRecv returns true if `t`, the receiver type of a method, may be nil when the
+// method is invoked without panicking at the call site, i.e., if it is a pointer, or a map, slice
+// or channel type (including transitively through Named types). The values of the other types
+// are dereferenced (if passed via pointers) at the call sites.
+func TypeIsDeeplyNilableRecv(t types.Type) bool {
+	return TypeIsDeeplyPtr(t) || TypeIsDeeplyMap(t) || TypeIsDeeplySlice(t) || TypeIsDeeplyChan(t)
+}
+
 // TypeAsDeeplyStruct returns underlying struct type if the type is struct type or a pointer to a struct type
 // returns nil otherwise
 func TypeAsDeeplyStruct(typ types.Type) *types.Struct {
@@ -166,6 +214,28 @@ func TypeIsDeeplyInterface(t types.Type) bool {
 	return false
 }
 
+// TypeHasInterfaceElem returns true if `t` is a slice, array, map, pointer, or channel type
+// (including transitively through Named types) whose elements are of interface type, e.g.,
+// `[]error` or `map[string]io.Reader`.
+func TypeHasInterfaceElem(t types.Type) bool {
+	var elem types.Type
+	switch t := t.Underlying().(type) {
+	case *types.Slice:
+		elem = t.Elem()
+	case *types.Array:
+		elem = t.Elem()
+	case *types.Map:
+		elem = t.Elem()
+	case *types.Pointer:
+		elem = t.Elem()
+	case *types.Chan:
+		elem = t.Elem()
+	default:
+		return false
+	}
+	return TypeIsDeeplyInterface(elem)
+}
+
 // UnwrapPtr unwraps a pointer type and returns the element type. For all other types it returns
 // the type unmodified.
 func UnwrapPtr(t types.Type) types.Type {
@@ -461,7 +531,19 @@ func truncatePosition(position token.Position) token.Position {
 	return position
 }
 
-// PosToLocation converts a token.Pos as a real code location, of token.Position.
+// PosToLocation converts a token.Pos as a real code location, of token.Position. The position is
+// adjusted by the "//line" directives or the registered source mappers (see sourcemap.Register),
+// i.e., it points back to the authored source file of the generated code, unless disabled by
+// config.Config.DisableLineDirectives.
 func PosToLocation(pos token.Pos, pass *analysis.Pass) token.Position {
-	return truncatePosition(pass.Fset.Position(pos))
+	adjusted := true
+	if conf, ok := pass.ResultOf[config.Analyzer].(*config.Config); ok {
+		adjusted = !conf.DisableLineDirectives
+	}
+	if adjusted {
+		if position, ok := sourcemap.Position(pass.Fset, pass.Files, pos); ok {
+			return truncatePosition(position)
+		}
+	}
+	return truncatePosition(pass.Fset.PositionFor(pos, adjusted))
 }